@@ -0,0 +1,349 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/Brownie44l1/debank/pkg/generator"
+)
+
+// errReconciliationFailed makes reconcile exit non-zero for cron and alerting
+var errReconciliationFailed = errors.New("ledger does not reconcile")
+
+var commands = []command{
+	{name: "create-system-account", summary: "open an account with no user (reserve, fees, settlement)", permission: auth.PermLedgerManage, run: createSystemAccount},
+	{name: "users", summary: "search users by name, email, username, phone or account number", permission: auth.PermUsersRead, run: searchUsers},
+	{name: "user", summary: "show a user", permission: auth.PermUsersRead, run: getUser},
+	{name: "account", summary: "show an account and its freeze state", permission: auth.PermTransactionsRead, run: getAccount},
+	{name: "transaction", summary: "show a transaction and its postings", permission: auth.PermTransactionsRead, run: getTransaction},
+	{name: "adjust", summary: "credit or debit an account against a system account", permission: auth.PermLedgerManage, run: adjust},
+	{name: "reverse", summary: "reverse a posted transaction", permission: auth.PermLedgerManage, run: reverse},
+	{name: "freeze", summary: "stop debits from an account", permission: auth.PermLedgerManage, run: freeze},
+	{name: "unfreeze", summary: "lift an account freeze", permission: auth.PermLedgerManage, run: unfreeze},
+	{name: "reconcile", summary: "check balances against postings; exits 1 if they disagree", permission: auth.PermTransactionsRead, run: reconcile},
+	{name: "statement", summary: "export an account's postings with running balances", permission: auth.PermTransactionsRead, run: statement},
+	{name: "rotate-key", summary: "stage a new JWT signing key in JWT_KEYS_DIR", run: rotateKey},
+}
+
+// ==============================================
+// ACCOUNTS
+// ==============================================
+
+func createSystemAccount(ctx context.Context, a *app, args []string) error {
+	var req dto.CreateSystemAccountRequest
+	fs := newFlags("create-system-account", "-external-id ID -name NAME -type TYPE -reason REASON [-currency NGN]")
+	fs.StringVar(&req.ExternalID, "external-id", "", "identifier the code looks the account up by, e.g. sys_settlement")
+	fs.StringVar(&req.Name, "name", "", "display name")
+	fs.StringVar(&req.Type, "type", "system", "system, reserve or fee")
+	fs.StringVar(&req.Currency, "currency", "NGN", "ISO 4217 code")
+	fs.StringVar(&req.Reason, "reason", "", "why the account is needed (audited)")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if err := validate(&req); err != nil {
+		return err
+	}
+
+	account, err := a.ops.CreateSystemAccount(ctx, a.operatorID, req, a.dryRun)
+	if err != nil {
+		return err
+	}
+	a.changed("Created system account %s", req.ExternalID)
+	return a.out.account(account)
+}
+
+func freeze(ctx context.Context, a *app, args []string) error {
+	var req dto.FreezeAccountRequest
+	fs := newFlags("freeze", "-account NUMBER -reason REASON")
+	fs.StringVar(&req.AccountNumber, "account", "", "account number")
+	fs.StringVar(&req.Reason, "reason", "", "why the account is frozen (audited, shown to staff)")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if err := validate(&req); err != nil {
+		return err
+	}
+
+	account, err := a.ops.FreezeAccount(ctx, a.operatorID, req, a.dryRun)
+	if err != nil {
+		return err
+	}
+	a.changed("Froze account %s", req.AccountNumber)
+	return a.out.account(account)
+}
+
+func unfreeze(ctx context.Context, a *app, args []string) error {
+	var req dto.UnfreezeAccountRequest
+	fs := newFlags("unfreeze", "-account NUMBER -reason REASON")
+	fs.StringVar(&req.AccountNumber, "account", "", "account number")
+	fs.StringVar(&req.Reason, "reason", "", "why the freeze is lifted (audited)")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if err := validate(&req); err != nil {
+		return err
+	}
+
+	account, err := a.ops.UnfreezeAccount(ctx, a.operatorID, req, a.dryRun)
+	if err != nil {
+		return err
+	}
+	a.changed("Unfroze account %s", req.AccountNumber)
+	return a.out.account(account)
+}
+
+// ==============================================
+// LOOKUPS
+// ==============================================
+
+func searchUsers(ctx context.Context, a *app, args []string) error {
+	var req dto.SearchUsersRequest
+	fs := newFlags("users", "[-q QUERY] [-page N] [-per-page N]")
+	fs.StringVar(&req.Query, "q", "", "name, email, username, phone or account number; empty lists everyone")
+	fs.IntVar(&req.Page, "page", 1, "page number")
+	fs.IntVar(&req.PerPage, "per-page", 20, "results per page, at most 100")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if err := validate(&req); err != nil {
+		return err
+	}
+
+	users, err := a.admin.SearchUsers(ctx, a.operatorID, req)
+	if err != nil {
+		return err
+	}
+	return a.out.users(users)
+}
+
+func getUser(ctx context.Context, a *app, args []string) error {
+	fs := newFlags("user", "-id ID")
+	id := fs.Int("id", 0, "user ID")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *id <= 0 {
+		return errors.New("-id is required")
+	}
+
+	user, err := a.admin.GetUser(ctx, a.operatorID, *id)
+	if err != nil {
+		return err
+	}
+	return a.out.user(user)
+}
+
+func getAccount(ctx context.Context, a *app, args []string) error {
+	fs := newFlags("account", "-account NUMBER")
+	number := fs.String("account", "", "account number")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *number == "" {
+		return errors.New("-account is required")
+	}
+
+	account, err := a.admin.GetAccount(ctx, a.operatorID, *number)
+	if err != nil {
+		return err
+	}
+	return a.out.account(account)
+}
+
+func getTransaction(ctx context.Context, a *app, args []string) error {
+	fs := newFlags("transaction", "-reference REF")
+	reference := fs.String("reference", "", "transaction reference")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *reference == "" {
+		return errors.New("-reference is required")
+	}
+
+	txn, err := a.admin.GetTransaction(ctx, a.operatorID, *reference)
+	if err != nil {
+		return err
+	}
+	return a.out.transaction(txn)
+}
+
+// ==============================================
+// POSTINGS
+// ==============================================
+
+func adjust(ctx context.Context, a *app, args []string) error {
+	var req dto.AdjustmentRequest
+	fs := newFlags("adjust", "-account NUMBER -direction credit|debit -amount KOBO -reason REASON [-counter sys_reserve] [-idempotency-key KEY]")
+	fs.StringVar(&req.AccountNumber, "account", "", "account number to adjust")
+	fs.StringVar(&req.Direction, "direction", "", "credit or debit, as seen by the account")
+	fs.Int64Var(&req.Amount, "amount", 0, "amount in kobo")
+	fs.StringVar(&req.CounterAccount, "counter", "sys_reserve", "external ID of the system account on the other side")
+	fs.StringVar(&req.Reason, "reason", "", "why the adjustment is needed (audited, kept on the transaction)")
+	fs.StringVar(&req.IdempotencyKey, "idempotency-key", "", "reuse to retry safely (default: a new key)")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	a.defaultIdempotencyKey(&req.IdempotencyKey)
+	if err := validate(&req); err != nil {
+		return err
+	}
+
+	txn, err := a.ops.PostAdjustment(ctx, a.operatorID, req, a.dryRun)
+	if err != nil {
+		return err
+	}
+	a.changed("Posted adjustment %s", txn.Reference)
+	return a.out.transaction(txn)
+}
+
+func reverse(ctx context.Context, a *app, args []string) error {
+	var req dto.ReverseTransactionRequest
+	fs := newFlags("reverse", "-reference REF -reason REASON [-idempotency-key KEY]")
+	fs.StringVar(&req.Reference, "reference", "", "reference of the transaction to reverse")
+	fs.StringVar(&req.Reason, "reason", "", "why it is reversed (audited, kept on the reversal)")
+	fs.StringVar(&req.IdempotencyKey, "idempotency-key", "", "reuse to retry safely (default: a new key)")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	a.defaultIdempotencyKey(&req.IdempotencyKey)
+	if err := validate(&req); err != nil {
+		return err
+	}
+
+	txn, err := a.ops.ReverseTransaction(ctx, a.operatorID, req, a.dryRun)
+	if err != nil {
+		return err
+	}
+	a.changed("Reversed %s with %s", req.Reference, txn.Reference)
+	return a.out.transaction(txn)
+}
+
+// ==============================================
+// CHECKS AND EXPORTS
+// ==============================================
+
+func reconcile(ctx context.Context, a *app, args []string) error {
+	fs := newFlags("reconcile", "")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	report, err := a.ops.Reconcile(ctx)
+	if err != nil {
+		return err
+	}
+	if err := a.out.reconciliation(report); err != nil {
+		return err
+	}
+	if !report.OK {
+		return errReconciliationFailed
+	}
+	return nil
+}
+
+func statement(ctx context.Context, a *app, args []string) error {
+	var req dto.StatementRequest
+	fs := newFlags("statement", "-account NUMBER -from DATE [-to DATE]")
+	fs.StringVar(&req.AccountNumber, "account", "", "account number")
+	from := fs.String("from", "", "start, inclusive: YYYY-MM-DD or RFC 3339")
+	to := fs.String("to", "", "end, exclusive: YYYY-MM-DD or RFC 3339 (default now)")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	var err error
+	if req.From, err = parseTime("-from", *from); err != nil {
+		return err
+	}
+	req.To = time.Now()
+	if *to != "" {
+		if req.To, err = parseTime("-to", *to); err != nil {
+			return err
+		}
+	}
+	if err := validate(&req); err != nil {
+		return err
+	}
+
+	stmt, err := a.ops.Statement(ctx, a.operatorID, req)
+	if err != nil {
+		return err
+	}
+	return a.out.statement(stmt)
+}
+
+// ==============================================
+// KEYS
+// ==============================================
+
+// rotateKey stages a key in the manifest; servers pick it up on SIGHUP and
+// sign with it from -not-before, while older keys keep verifying for JWT_KEY_OVERLAP
+func rotateKey(ctx context.Context, a *app, args []string) error {
+	fs := newFlags("rotate-key", "[-id ID] [-alg RS256|EdDSA] [-not-before TIME]")
+	id := fs.String("id", time.Now().UTC().Format("2006-01-02"), "key ID, published as the JWT kid")
+	alg := fs.String("alg", "RS256", "signing algorithm: RS256 or EdDSA")
+	notBefore := fs.String("not-before", "", "when signing switches to the key: YYYY-MM-DD or RFC 3339 (default now)")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	if *notBefore != "" {
+		var err error
+		if start, err = parseTime("-not-before", *notBefore); err != nil {
+			return err
+		}
+	}
+	if a.dryRun {
+		note("Dry run: would stage %s key %q in %s, signing from %s", *alg, *id, a.cfg.JWTKeysDir, start.Format(time.RFC3339))
+		return nil
+	}
+
+	key, err := auth.StageKey(a.cfg.JWTKeysDir, *id, *alg, start)
+	if err != nil {
+		return err
+	}
+	note("Staged key %q in %s; send SIGHUP to the API servers and worker to load it", key.ID, a.cfg.JWTKeysDir)
+	return a.out.signingKey(key)
+}
+
+// ==============================================
+// HELPERS
+// ==============================================
+
+// changed reports a change, making clear when a dry run left nothing behind
+func (a *app) changed(format string, args ...any) {
+	if a.dryRun {
+		note("Dry run: "+format+" (rolled back)", args...)
+		return
+	}
+	note(format, args...)
+}
+
+// defaultIdempotencyKey fills in a fresh key and shows it so a failed run can be retried with it
+func (a *app) defaultIdempotencyKey(key *string) {
+	if *key != "" {
+		return
+	}
+	*key = generator.GenerateReference("CTL")
+	note("Idempotency key: %s", *key)
+}
+
+// parseTime accepts a date (midnight UTC) or an RFC 3339 timestamp
+func parseTime(flagName, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("%s is required", flagName)
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: want YYYY-MM-DD or RFC 3339, got %q", flagName, value)
+	}
+	return t, nil
+}
@@ -0,0 +1,208 @@
+// Command debankctl runs ledger operations that have no public API:
+// system accounts, manual adjustments, reversals, freezes, reconciliation,
+// statements and signing key rotation.
+//
+//	debankctl [-o table|json] [-dry-run] [-as STAFF_USER_ID] COMMAND [flags]
+//
+// Every command runs as a staff member (-as, or DEBANKCTL_OPERATOR) whose
+// role must grant it the same permission the admin API would ask for, and
+// every change is audited under their ID. With -dry-run a change is made
+// and checked inside its database transaction, then rolled back.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"syscall"
+
+	"github.com/Brownie44l1/debank/internal/api/problem"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/Brownie44l1/debank/internal/clientinfo"
+	"github.com/Brownie44l1/debank/internal/config"
+	"github.com/Brownie44l1/debank/internal/db"
+	"github.com/Brownie44l1/debank/internal/repository"
+	"github.com/Brownie44l1/debank/internal/service"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// errUsage marks a mistake in the command line; the usage text has already been printed
+var errUsage = errors.New("usage")
+
+// app is what a command runs with
+type app struct {
+	cfg        config.Config
+	ops        *service.LedgerOpsService
+	admin      *service.AdminService
+	operatorID int // Staff user the command runs as; changes are audited under it
+	out        *printer
+	dryRun     bool
+}
+
+type command struct {
+	name       string
+	summary    string
+	permission auth.Permission // Empty for commands that don't touch the database
+	run        func(ctx context.Context, a *app, args []string) error
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("debankctl: ")
+
+	global := flag.NewFlagSet("debankctl", flag.ContinueOnError)
+	format := global.String("o", "table", "output format: table or json")
+	dryRun := global.Bool("dry-run", false, "make and check changes, then roll them back")
+	operator := global.String("as", os.Getenv("DEBANKCTL_OPERATOR"), "ID of the staff user running the command (default $DEBANKCTL_OPERATOR)")
+	global.Usage = func() { usage(global) }
+	if err := global.Parse(os.Args[1:]); err != nil {
+		os.Exit(2)
+	}
+
+	out, err := newPrinter(os.Stdout, *format)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if global.NArg() == 0 {
+		usage(global)
+		os.Exit(2)
+	}
+	cmd, ok := findCommand(global.Arg(0))
+	if !ok {
+		log.Printf("unknown command %q", global.Arg(0))
+		usage(global)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	// Audit entries record the CLI as the client
+	ctx = clientinfo.NewContext(ctx, clientinfo.Info{UserAgent: "debankctl"})
+
+	a := &app{cfg: config.LoadConfig(), out: out, dryRun: *dryRun}
+	problem.UseRequestFieldNames()
+
+	if cmd.permission != "" {
+		pool, err := db.NewPool(ctx, a.cfg.DBUrl)
+		if err != nil {
+			log.Fatal("Failed to connect to database: ", err)
+		}
+		defer pool.Close()
+
+		userRepo := repository.NewUserRepository(pool)
+		walletRepo := repository.NewWalletRepository(pool)
+		auditLogger := service.NewAuditLogger(repository.NewAuditRepository(pool))
+		a.ops = service.NewLedgerOpsService(walletRepo, repository.NewReconciliationRepository(pool), userRepo, auditLogger)
+		a.admin = service.NewAdminService(userRepo, walletRepo, auditLogger)
+
+		if err := a.authorize(ctx, *operator, cmd.permission); err != nil {
+			pool.Close()
+			log.Fatal(err)
+		}
+	}
+
+	if err := cmd.run(ctx, a, global.Args()[1:]); err != nil {
+		stop()
+		if errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		log.Print(err)
+		os.Exit(1)
+	}
+}
+
+// authorize loads the operator and checks their role allows the command
+func (a *app) authorize(ctx context.Context, operatorID string, perm auth.Permission) error {
+	if operatorID == "" {
+		return errors.New("no operator: pass -as or set DEBANKCTL_OPERATOR to your staff user ID")
+	}
+	id, err := strconv.Atoi(operatorID)
+	if err != nil {
+		return fmt.Errorf("invalid operator ID %q", operatorID)
+	}
+
+	user, err := a.ops.Operator(ctx, id)
+	if err != nil {
+		return fmt.Errorf("operator %d: %w", id, err)
+	}
+	if !auth.HasPermission(user.Role, perm) {
+		return fmt.Errorf("operator %d (%s) lacks the %s permission", id, user.Role, perm)
+	}
+	a.operatorID = id
+	return nil
+}
+
+func findCommand(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+func usage(global *flag.FlagSet) {
+	w := global.Output()
+	fmt.Fprintln(w, "Usage: debankctl [flags] COMMAND [command flags]")
+	fmt.Fprintln(w, "\nFlags:")
+	global.PrintDefaults()
+	fmt.Fprintln(w, "\nCommands:")
+
+	sorted := append([]command(nil), commands...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+	for _, c := range sorted {
+		fmt.Fprintf(w, "  %-24s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w, "\nRun 'debankctl COMMAND -h' for a command's flags.")
+}
+
+// validate checks a request against its binding tags, as the API would
+func validate(req any) error {
+	err := binding.Validator.ValidateStruct(req)
+	if err == nil {
+		return nil
+	}
+	p := problem.FromBindError(err)
+	if len(p.Errors) == 0 {
+		return err
+	}
+	msg := "invalid request:"
+	for _, fe := range p.Errors {
+		msg += fmt.Sprintf(" %s %s;", fe.Field, fe.Message)
+	}
+	return errors.New(msg[:len(msg)-1])
+}
+
+// newFlags starts a command's flag set; it reports errors and -h itself
+func newFlags(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: debankctl %s %s\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses args and rejects positional leftovers
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(fs.Output(), "unexpected argument %q\n", fs.Arg(0))
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// note writes progress for the operator; stdout carries only the result
+func note(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+}
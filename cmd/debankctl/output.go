@@ -0,0 +1,252 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/auth"
+)
+
+// printer writes results as aligned tables for people or JSON for scripts
+// JSON uses the same DTOs, and so the same field names, as the admin API.
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	switch format {
+	case "table":
+		return &printer{w: w}, nil
+	case "json":
+		return &printer{w: w, json: true}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q: want table or json", format)
+	}
+}
+
+func (p *printer) account(a *dto.AdminAccountDTO) error {
+	if p.json {
+		return p.encode(a)
+	}
+	fields := [][2]string{
+		{"ID", strconv.FormatInt(a.ID, 10)},
+		{"Account number", a.AccountNumber},
+		{"Name", a.Name},
+		{"Type", a.Type},
+		{"User ID", optionalInt(a.UserID)},
+		{"Balance", kobo(a.Balance) + " " + a.Currency},
+		{"Active", yesNo(a.IsActive)},
+		{"Frozen", yesNo(a.Frozen)},
+	}
+	if a.Frozen {
+		fields = append(fields, [2]string{"Frozen at", a.FrozenAt}, [2]string{"Frozen reason", a.FrozenReason})
+	}
+	return p.record(append(fields, [2]string{"Created", a.CreatedAt}))
+}
+
+func (p *printer) users(list *dto.AdminUserListResponse) error {
+	if p.json {
+		return p.encode(list)
+	}
+	rows := make([][]string, len(list.Users))
+	for i, u := range list.Users {
+		rows[i] = []string{strconv.Itoa(u.ID), u.Name, u.Email, u.Phone, u.Role, yesNo(u.IsActive), yesNo(u.Locked)}
+	}
+	if err := p.table([]string{"ID", "NAME", "EMAIL", "PHONE", "ROLE", "ACTIVE", "LOCKED"}, rows); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(p.w, "\nPage %d, %d of %d users\n", list.Page, len(list.Users), list.Total)
+	return err
+}
+
+func (p *printer) user(u *dto.AdminUserDTO) error {
+	if p.json {
+		return p.encode(u)
+	}
+	username := ""
+	if u.Username != nil {
+		username = *u.Username
+	}
+	return p.record([][2]string{
+		{"ID", strconv.Itoa(u.ID)},
+		{"Name", u.Name},
+		{"Username", username},
+		{"Email", u.Email},
+		{"Phone", u.Phone},
+		{"Role", u.Role},
+		{"Active", yesNo(u.IsActive)},
+		{"Email verified", yesNo(u.IsEmailVerified)},
+		{"Locked", yesNo(u.Locked)},
+		{"Locked until", u.LockedUntil},
+		{"Failed logins", strconv.Itoa(u.FailedLoginAttempts)},
+		{"Last login", u.LastLoginAt},
+	})
+}
+
+func (p *printer) transaction(t *dto.AdminTransactionDTO) error {
+	if p.json {
+		return p.encode(t)
+	}
+	err := p.record([][2]string{
+		{"ID", strconv.FormatInt(t.ID, 10)},
+		{"Reference", t.Reference},
+		{"Kind", t.Kind},
+		{"Status", t.Status},
+		{"Amount", kobo(t.Amount) + " " + t.Currency},
+		{"From", t.FromIdentifier},
+		{"To", t.ToIdentifier},
+		{"Description", t.Description},
+		{"Failure reason", t.FailureReason},
+		{"Created", t.CreatedAt},
+		{"Posted", t.PostedAt},
+	})
+	if err != nil || len(t.Postings) == 0 {
+		return err
+	}
+
+	rows := make([][]string, len(t.Postings))
+	for i, leg := range t.Postings {
+		rows[i] = []string{strconv.FormatInt(leg.AccountID, 10), kobo(leg.Amount), leg.Currency}
+	}
+	fmt.Fprintln(p.w)
+	return p.table([]string{"ACCOUNT ID", "AMOUNT", "CURRENCY"}, rows)
+}
+
+func (p *printer) reconciliation(r *dto.ReconciliationReport) error {
+	if p.json {
+		return p.encode(r)
+	}
+	result := "OK"
+	if !r.OK {
+		result = "FAILED"
+	}
+	err := p.record([][2]string{
+		{"Result", result},
+		{"Accounts", strconv.FormatInt(r.Accounts, 10)},
+		{"Transactions", strconv.FormatInt(r.Transactions, 10)},
+		{"Balance total", kobo(r.BalanceTotal)},
+		{"Posting total", kobo(r.PostingTotal)},
+		{"Checked", r.CheckedAt},
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(r.BalanceMismatches) > 0 {
+		rows := make([][]string, len(r.BalanceMismatches))
+		for i, m := range r.BalanceMismatches {
+			rows[i] = []string{strconv.FormatInt(m.AccountID, 10), m.AccountNumber, m.ExternalID, kobo(m.Balance), kobo(m.PostedBalance), kobo(m.Difference)}
+		}
+		fmt.Fprintln(p.w, "\nBalance mismatches:")
+		if err := p.table([]string{"ACCOUNT ID", "ACCOUNT NUMBER", "EXTERNAL ID", "BALANCE", "POSTED", "DIFFERENCE"}, rows); err != nil {
+			return err
+		}
+	}
+	if len(r.UnbalancedTransactions) > 0 {
+		rows := make([][]string, len(r.UnbalancedTransactions))
+		for i, u := range r.UnbalancedTransactions {
+			rows[i] = []string{strconv.FormatInt(u.TransactionID, 10), u.Reference, u.Status, strconv.Itoa(u.Postings), kobo(u.PostingTotal)}
+		}
+		fmt.Fprintln(p.w, "\nUnbalanced transactions:")
+		if err := p.table([]string{"TRANSACTION ID", "REFERENCE", "STATUS", "POSTINGS", "NET"}, rows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *printer) statement(s *dto.StatementResponse) error {
+	if p.json {
+		return p.encode(s)
+	}
+	fmt.Fprintf(p.w, "Statement for %s (%s), %s to %s\n\n", s.Account.AccountNumber, s.Account.Name, s.From, s.To)
+
+	rows := make([][]string, 0, len(s.Entries)+2)
+	rows = append(rows, []string{"", "", "Opening balance", "", "", "", kobo(s.OpeningBalance)})
+	for _, e := range s.Entries {
+		rows = append(rows, []string{e.Date, e.Reference, e.Description, e.Type, e.Direction, kobo(e.Amount), kobo(e.Balance)})
+	}
+	rows = append(rows, []string{"", "", "Closing balance", "", "", "", kobo(s.ClosingBalance)})
+	if err := p.table([]string{"DATE", "REFERENCE", "DESCRIPTION", "TYPE", "DIRECTION", "AMOUNT", "BALANCE"}, rows); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(p.w, "\nCredits %s, debits %s (%s)\n", kobo(s.TotalCredits), kobo(s.TotalDebits), s.Account.Currency)
+	return err
+}
+
+func (p *printer) signingKey(k *auth.SigningKey) error {
+	view := struct {
+		ID        string `json:"kid"`
+		Algorithm string `json:"alg"`
+		NotBefore string `json:"not_before"`
+	}{k.ID, k.Algorithm, k.NotBefore.UTC().Format(time.RFC3339)}
+	if p.json {
+		return p.encode(view)
+	}
+	return p.record([][2]string{
+		{"Key ID", view.ID},
+		{"Algorithm", view.Algorithm},
+		{"Signs from", view.NotBefore},
+	})
+}
+
+// ==============================================
+// HELPERS
+// ==============================================
+
+func (p *printer) encode(v any) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// record prints one item as label/value lines, leaving out empty values
+func (p *printer) record(fields [][2]string) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	for _, f := range fields {
+		if f[1] != "" {
+			fmt.Fprintf(tw, "%s:\t%s\n", f[0], f[1])
+		}
+	}
+	return tw.Flush()
+}
+
+func (p *printer) table(headers []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// kobo formats an amount in kobo as naira with two decimals
+func kobo(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func optionalInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
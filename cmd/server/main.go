@@ -2,26 +2,61 @@ package main
 
 import (
 	"context"
+	"errors"
+	"io/fs"
 	"log"
+	"net"
     "net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	"github.com/gin-gonic/gin"
+	"github.com/Brownie44l1/debank/internal/api"
+	"github.com/Brownie44l1/debank/internal/api/handlers"
+	"github.com/Brownie44l1/debank/internal/api/middleware"
+	"github.com/Brownie44l1/debank/internal/api/openapi"
+	"github.com/Brownie44l1/debank/internal/auth"
 	"github.com/Brownie44l1/debank/internal/config"
 	"github.com/Brownie44l1/debank/internal/db"
-	"github.com/Brownie44l1/debank/internal/handlers"
+	"github.com/Brownie44l1/debank/internal/events"
+	"github.com/Brownie44l1/debank/internal/health"
+	"github.com/Brownie44l1/debank/internal/metrics"
 	"github.com/Brownie44l1/debank/internal/repository"
+	"github.com/Brownie44l1/debank/internal/rpc"
+	"github.com/Brownie44l1/debank/internal/sanctions"
 	"github.com/Brownie44l1/debank/internal/service"
+	"github.com/Brownie44l1/debank/internal/storage"
+	"github.com/Brownie44l1/debank/internal/tracing"
+	"google.golang.org/grpc"
 )
 
+// Health check thresholds
+const (
+	maxGoroutines        = 10000       // Far above normal load; more means something leaks
+	poolSaturationLimit  = 0.9         // Share of DB connections in use before readiness degrades
+	workerHeartbeatGrace = time.Minute // Slack on top of two job intervals before a job counts as stalled
+)
+
+// idempotencyLease is how long a request holds its Idempotency-Key; an identical retry may take it over after a crash
+const idempotencyLease = time.Minute
+
 func main() {
 	// 1. Load configuration
 	cfg := config.LoadConfig()
 	log.Println("✓ Configuration loaded")
 
+	// Tracing is a no-op with TRACING_EXPORTER=none
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		ServiceName: "debank-api",
+		Exporter:    cfg.TracingExporter,
+		File:        cfg.TracingFile,
+		SampleRatio: cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		log.Fatal("Failed to set up tracing:", err)
+	}
+
 	// 2. Initialize database connection
 	ctx := context.Background()
 	pool, err := db.NewPool(ctx, cfg.DBUrl)
@@ -31,30 +66,186 @@ func main() {
 	defer pool.Close()
 
 	// 3. Initialize layers
+	userRepo := repository.NewUserRepository(pool)
+	verificationRepo := repository.NewVerificationRepository(pool)
 	walletRepo := repository.NewWalletRepository(pool)
-	walletService := service.NewWalletService(walletRepo)
-	walletHandler := handlers.NewWalletHandler(walletService)
+	paymentRequestRepo := repository.NewPaymentRequestRepository(pool)
+	beneficiaryRepo := repository.NewBeneficiaryRepository(pool)
+	kycRepo := repository.NewKYCRepository(pool)
+	riskRepo := repository.NewRiskRepository(pool)
+	amlRepo := repository.NewAMLRepository(pool)
+	screeningRepo := repository.NewScreeningRepository(pool)
+	mfaRepo := repository.NewMFARepository(pool)
+	stepUpRepo := repository.NewStepUpRepository(pool)
+	deviceRepo := repository.NewDeviceRepository(pool)
+	auditRepo := repository.NewAuditRepository(pool)
+	integrityRepo := repository.NewIntegrityRepository(pool)
+	journalRepo := repository.NewJournalRepository(pool)
 
-	// 4. Setup Gin router
-	router := gin.Default()
+	// Watchlists are optional at startup so development works without them; until they load,
+	// every signup, KYC submission and beneficiary is held for manual review
+	screeningService := service.NewScreeningService(screeningRepo, sanctions.NewScreener(cfg.SanctionsListDir, cfg.SanctionsMatchThreshold))
+	if _, err := screeningService.Reload(ctx); err != nil {
+		log.Printf("⚠ No sanctions watchlists loaded - new subjects are held for manual review: %v", err)
+	}
 
-	// Register wallet routes
-	walletHandler.RegisterRoutes(router)
+	// Without a key manifest (development) tokens are signed with a throwaway key
+	keyringOpts := auth.KeyringOptions{Issuer: cfg.JWTIssuer, Audience: cfg.JWTAudience, Overlap: cfg.JWTKeyOverlap}
+	keyring, err := auth.LoadKeyring(cfg.JWTKeysDir, keyringOpts)
+	if errors.Is(err, fs.ErrNotExist) {
+		log.Printf("⚠ No JWT key manifest in %s, signing with a temporary key; tokens won't survive a restart", cfg.JWTKeysDir)
+		keyring, err = auth.NewEphemeralKeyring(keyringOpts)
+	}
+	if err != nil {
+		log.Fatal("Failed to load JWT signing keys:", err)
+	}
+
+	emailService := service.NewEmailService()
+	auditLogger := service.NewAuditLogger(auditRepo)
+	mfaKey := auth.SecretKey(cfg.MFAEncryptionKey)
+	stepUpService := service.NewStepUpService(stepUpRepo, mfaRepo, userRepo, verificationRepo, emailService, mfaKey, cfg.StepUpPaymentThreshold)
+	deviceService := service.NewDeviceService(deviceRepo, emailService)
+	authService := service.NewAuthService(userRepo, verificationRepo, walletRepo, emailService, mfaRepo, screeningService, stepUpService, deviceService, keyring, auditLogger, mfaKey, cfg.NewDeviceStepUp)
+	documentStore, err := storage.NewLocalStorage(cfg.StorageDir)
+	if err != nil {
+		log.Fatal("Failed to initialise document storage:", err)
+	}
+	kycService := service.NewKYCService(kycRepo, walletRepo, userRepo, documentStore, service.NewFakeIdentityVerifier(), screeningService)
+	riskService := service.NewRiskService(riskRepo)
+	walletService := service.NewWalletService(walletRepo, authService, kycService, riskService, screeningService, stepUpService, auditLogger)
+	paymentRequestService := service.NewPaymentRequestService(paymentRequestRepo, userRepo, walletService, cfg.AppBaseURL)
+	qrService := service.NewQRService(walletRepo)
+	beneficiaryService := service.NewBeneficiaryService(beneficiaryRepo, walletRepo, screeningService, stepUpService)
+	amlService := service.NewAMLService(amlRepo, userRepo, service.DefaultAMLRules())
+	adminService := service.NewAdminService(userRepo, walletRepo, auditLogger)
+	auditService := service.NewAuditService(auditRepo, auditLogger)
+	integrityService := service.NewIntegrityService(integrityRepo, keyring, nil, auditLogger) // The worker takes checkpoints
+	journalService := service.NewJournalService(journalRepo, walletRepo, documentStore, auditLogger)
+
+	// Pool stats and system account balances are read on every /metrics scrape
+	metrics.RegisterPool(pool)
+	metrics.RegisterSystemBalances(func(ctx context.Context, externalID string) (int64, error) {
+		account, err := walletRepo.GetSystemAccount(ctx, externalID)
+		if err != nil {
+			return 0, err
+		}
+		return account.Balance, nil
+	}, "sys_reserve", "sys_fee")
+
+	// /livez only looks at the process; /readyz at everything serving traffic depends on
+	liveness := health.NewRegistry(cfg.HealthCacheTTL)
+	liveness.Register(health.Check{Name: "goroutines", Critical: true, Run: health.Goroutines(maxGoroutines)})
+
+	healthRepo := repository.NewHealthRepository(pool)
+	readiness := health.NewRegistry(cfg.HealthCacheTTL)
+	readiness.Register(health.Check{Name: "database", Critical: true, Run: health.Ping(pool)})
+	readiness.Register(health.Check{Name: "migrations", Critical: true, Run: health.SchemaVersion(healthRepo.SchemaVersion, db.SchemaVersion())})
+	readiness.Register(health.Check{Name: "system_accounts", Critical: true, Run: health.SystemAccounts(walletRepo.GetSystemAccount, "sys_reserve", "sys_fee")})
+	readiness.Register(health.Check{Name: "db_pool", Run: health.PoolSaturation(pool, poolSaturationLimit)})
+	readiness.Register(health.Check{Name: "worker", Run: health.WorkerHeartbeats(healthRepo.ListWorkerHeartbeats, workerHeartbeatGrace)})
+	readiness.Register(health.Check{Name: "email", Run: health.Ping(emailService)})
+
+	// One LISTEN connection feeds every event stream open on this instance
+	eventBroker := events.NewBroker(repository.NewAccountEventRepository(pool))
+	eventsCtx, stopEvents := context.WithCancel(ctx)
+	defer stopEvents()
+	go eventBroker.Run(eventsCtx)
+
+	spec, err := openapi.JSON()
+	if err != nil {
+		log.Fatal("Failed to build the OpenAPI document:", err)
+	}
+
+	// 4. Setup Gin router
+	router := api.NewRouter(api.Handlers{
+		Health:         handlers.NewHealthHandler(liveness, readiness, cfg.MetricsToken),
+		JWKS:           handlers.NewJWKSHandler(keyring),
+		Docs:           handlers.NewDocsHandler(spec),
+		Metrics:        handlers.NewMetricsHandler(cfg.MetricsToken),
+		Auth:           handlers.NewAuthHandler(authService),
+		Wallet:         handlers.NewWalletHandler(walletService),
+		PaymentRequest: handlers.NewPaymentRequestHandler(paymentRequestService),
+		QR:             handlers.NewQRHandler(qrService),
+		Beneficiary:    handlers.NewBeneficiaryHandler(beneficiaryService),
+		KYC:            handlers.NewKYCHandler(kycService),
+		Risk:           handlers.NewRiskHandler(riskService),
+		AML:            handlers.NewAMLHandler(amlService),
+		Screening:      handlers.NewScreeningHandler(screeningService),
+		StepUp:         handlers.NewStepUpHandler(stepUpService),
+		Device:         handlers.NewDeviceHandler(deviceService),
+		Admin:          handlers.NewAdminHandler(adminService),
+		Audit:          handlers.NewAuditHandler(auditService),
+		Integrity:      handlers.NewIntegrityHandler(integrityService),
+		Journal:        handlers.NewJournalHandler(journalService),
+		Events:         handlers.NewEventHandler(eventBroker),
+	}, keyring, deviceService, repository.NewIdempotencyRepository(pool), middleware.IdempotencyOptions{
+		TTL:     cfg.IdempotencyKeyTTL,
+		Lease:   idempotencyLease,
+		Wait:    cfg.IdempotencyWait,
+		MaxBody: service.MaxKYCDocumentSize + 1<<20, // A document upload plus multipart overhead
+	})
 
 	// 5. Start server with graceful shutdown
 	srv := &http.Server{
-		Addr:    ":8080",
+		Addr:    ":" + cfg.Port,
 		Handler: router,
 	}
+	// Shutdown does not wait out open event streams; ending them lets it finish
+	srv.RegisterOnShutdown(eventBroker.Close)
 
 	// Start server in a goroutine
 	go func() {
-		log.Println("🚀 Server starting on :8080")
+		log.Printf("🚀 Server starting on :%s", cfg.Port)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatal("Failed to start server:", err)
 		}
 	}()
 
+	// The internal gRPC ledger API runs next to HTTP when GRPC_PORT is set
+	var grpcServer *grpc.Server
+	if cfg.GRPCPort != "" {
+		if cfg.GRPCInsecure {
+			log.Println("⚠ gRPC is running without TLS or client certificates; development only")
+		}
+		grpcServer, err = rpc.NewServer(rpc.Config{
+			CertFile:       cfg.GRPCTLSCert,
+			KeyFile:        cfg.GRPCTLSKey,
+			ClientCAFile:   cfg.GRPCClientCA,
+			AllowedClients: cfg.GRPCAllowedClients,
+			Insecure:       cfg.GRPCInsecure,
+		}, rpc.NewLedgerServer(walletService), keyring, deviceService)
+		if err != nil {
+			log.Fatal("Failed to set up gRPC server:", err)
+		}
+
+		lis, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		if err != nil {
+			log.Fatal("Failed to listen for gRPC:", err)
+		}
+		go func() {
+			log.Printf("🚀 gRPC server starting on :%s", cfg.GRPCPort)
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Fatal("Failed to start gRPC server:", err)
+			}
+		}()
+	}
+
+	// SIGHUP reloads the sanctions watchlists and JWT signing keys without a restart
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			if _, err := screeningService.Reload(context.Background()); err != nil {
+				log.Printf("⚠ Watchlist reload failed, keeping previous lists: %v", err)
+			}
+			if n, err := keyring.Reload(); err != nil {
+				log.Printf("⚠ JWT key reload failed, keeping previous keys: %v", err)
+			} else {
+				log.Printf("✓ Reloaded %d JWT signing keys", n)
+			}
+		}
+	}()
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -68,6 +259,27 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatal("Server forced to shutdown:", err)
 	}
+	if grpcServer != nil {
+		stopGRPC(ctx, grpcServer)
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("⚠ Failed to flush traces: %v", err)
+	}
 
 	log.Println("✓ Server exited")
-}
\ No newline at end of file
+}
+
+// stopGRPC lets in-flight calls finish, cutting off open streams when ctx expires
+func stopGRPC(ctx context.Context, srv *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		srv.Stop()
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/Brownie44l1/debank/internal/config"
+	"github.com/Brownie44l1/debank/internal/db"
+	"github.com/Brownie44l1/debank/internal/repository"
+	"github.com/Brownie44l1/debank/internal/service"
+	"github.com/Brownie44l1/debank/internal/storage"
+	"github.com/Brownie44l1/debank/internal/tracing"
+	"github.com/Brownie44l1/debank/internal/worker"
+)
+
+func main() {
+	once := flag.Bool("once", false, "run each job a single time and exit (for cron)")
+	flag.Parse()
+
+	// 1. Load configuration
+	cfg := config.LoadConfig()
+	log.Println("✓ Configuration loaded")
+
+	// Tracing is a no-op with TRACING_EXPORTER=none
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		ServiceName: "debank-worker",
+		Exporter:    cfg.TracingExporter,
+		File:        cfg.TracingFile,
+		SampleRatio: cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		log.Fatal("Failed to set up tracing:", err)
+	}
+	defer flushTracing(shutdownTracing)
+
+	// 2. Initialize database connection
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	pool, err := db.NewPool(ctx, cfg.DBUrl)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer pool.Close()
+
+	// 3. Initialize layers
+	userRepo := repository.NewUserRepository(pool)
+	amlRepo := repository.NewAMLRepository(pool)
+	amlService := service.NewAMLService(amlRepo, userRepo, service.DefaultAMLRules())
+
+	jobs := []worker.Job{{
+		Name:     "aml_scan",
+		Interval: cfg.AMLScanInterval,
+		Run: func(ctx context.Context) error {
+			_, err := amlService.RunScan(ctx)
+			return err
+		},
+	}}
+
+	// Checkpoints must be signed with the published keys; a throwaway key would make them worthless
+	keyringOpts := auth.KeyringOptions{Issuer: cfg.JWTIssuer, Audience: cfg.JWTAudience, Overlap: cfg.JWTKeyOverlap}
+	keyring, err := auth.LoadKeyring(cfg.JWTKeysDir, keyringOpts)
+	if err != nil {
+		log.Printf("⚠ Hash chain checkpoints disabled, no JWT signing keys: %v", err)
+	} else {
+		checkpointStore, err := storage.NewLocalStorage(cfg.ChainCheckpointDir)
+		if err != nil {
+			log.Fatal("Failed to initialise checkpoint storage:", err)
+		}
+		auditLogger := service.NewAuditLogger(repository.NewAuditRepository(pool))
+		integrityService := service.NewIntegrityService(repository.NewIntegrityRepository(pool), keyring, checkpointStore, auditLogger)
+
+		jobs = append(jobs, worker.Job{
+			Name:     "hash_chain_checkpoint",
+			Interval: cfg.ChainCheckpointInterval,
+			Run:      integrityService.Checkpoint,
+		})
+	}
+
+	idempotencyRepo := repository.NewIdempotencyRepository(pool)
+	jobs = append(jobs, worker.Job{
+		Name:     "idempotency_key_cleanup",
+		Interval: cfg.IdempotencyCleanup,
+		Run: func(ctx context.Context) error {
+			deleted, err := idempotencyRepo.DeleteExpired(ctx)
+			if err == nil && deleted > 0 {
+				log.Printf("[WORKER] Purged %d expired idempotency keys", deleted)
+			}
+			return err
+		},
+	})
+
+	accountEventRepo := repository.NewAccountEventRepository(pool)
+	jobs = append(jobs, worker.Job{
+		Name:     "account_event_cleanup",
+		Interval: cfg.AccountEventCleanup,
+		Run: func(ctx context.Context) error {
+			deleted, err := accountEventRepo.DeleteBefore(ctx, time.Now().Add(-cfg.AccountEventRetention))
+			if err == nil && deleted > 0 {
+				log.Printf("[WORKER] Purged %d old account events", deleted)
+			}
+			return err
+		},
+	})
+
+	// Each run leaves a heartbeat that the API's /readyz reports on
+	healthRepo := repository.NewHealthRepository(pool)
+	for i := range jobs {
+		jobs[i] = worker.WithHeartbeat(jobs[i], healthRepo)
+	}
+
+	// 4. Run jobs
+	if *once {
+		failed := false
+		for _, job := range jobs {
+			if err := worker.RunOnce(ctx, job); err != nil {
+				failed = true
+			}
+		}
+		if failed {
+			flushTracing(shutdownTracing)
+			pool.Close()
+			os.Exit(1)
+		}
+		return
+	}
+
+	log.Println("🚀 Worker started")
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker.Run(ctx, job)
+		}()
+	}
+	wg.Wait()
+	log.Println("✓ Worker exited")
+}
+
+// flushTracing exports any spans still buffered
+func flushTracing(shutdown func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdown(ctx); err != nil {
+		log.Printf("⚠ Failed to flush traces: %v", err)
+	}
+}
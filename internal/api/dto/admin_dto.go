@@ -0,0 +1,104 @@
+package dto
+
+import "time"
+
+// ==============================================
+// ADMIN REQUEST DTOs
+// ==============================================
+
+// SearchUsersRequest - Match name, email, username, phone or account number; empty lists everyone
+type SearchUsersRequest struct {
+	Query   string `form:"q" binding:"max=100"`
+	Page    int    `form:"page" binding:"omitempty,min=1"`
+	PerPage int    `form:"per_page" binding:"omitempty,min=1,max=100"`
+}
+
+// LockUserRequest - Lock a user out; without Until the lock lasts until someone unlocks it
+type LockUserRequest struct {
+	Reason string    `json:"reason" binding:"required,min=1,max=500"`
+	Until  time.Time `json:"until,omitempty"`
+}
+
+// UnlockUserRequest - Lift a lock, including one from failed logins
+type UnlockUserRequest struct {
+	Reason string `json:"reason" binding:"required,min=1,max=500"`
+}
+
+// SetRoleRequest - Grant or revoke staff access
+type SetRoleRequest struct {
+	Role   string `json:"role" binding:"required,oneof=user support compliance admin superadmin"`
+	Reason string `json:"reason" binding:"required,min=1,max=500"`
+}
+
+// ==============================================
+// ADMIN RESPONSE DTOs
+// ==============================================
+
+// AdminUserDTO - A user as staff see them
+type AdminUserDTO struct {
+	ID                  int              `json:"id"`
+	Name                string           `json:"name"`
+	Phone               string           `json:"phone"`
+	Email               string           `json:"email"`
+	Username            *string          `json:"username,omitempty"`
+	Role                string           `json:"role"`
+	IsActive            bool             `json:"is_active"`
+	IsEmailVerified     bool             `json:"is_email_verified"`
+	FailedLoginAttempts int              `json:"failed_login_attempts"`
+	Locked              bool             `json:"locked"`
+	LockedUntil         string           `json:"locked_until,omitempty"`  // ISO 8601
+	LastLoginAt         string           `json:"last_login_at,omitempty"` // ISO 8601
+	CreatedAt           string           `json:"created_at"`              // ISO 8601
+	Account             *AdminAccountDTO `json:"account,omitempty"`       // Only on single-user lookups
+}
+
+// AdminUserListResponse - One page of search results
+type AdminUserListResponse struct {
+	Users   []AdminUserDTO `json:"users"`
+	Total   int            `json:"total"`
+	Page    int            `json:"page"`
+	PerPage int            `json:"per_page"`
+}
+
+// AdminAccountDTO - A ledger account with its freeze state
+type AdminAccountDTO struct {
+	ID            int64  `json:"id"`
+	AccountNumber string `json:"account_number,omitempty"`
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	UserID        *int   `json:"user_id,omitempty"`
+	Balance       int64  `json:"balance"` // In kobo
+	Currency      string `json:"currency"`
+	IsActive      bool   `json:"is_active"`
+	Frozen        bool   `json:"frozen"`
+	FrozenReason  string `json:"frozen_reason,omitempty"`
+	FrozenAt      string `json:"frozen_at,omitempty"` // ISO 8601
+	CreatedAt     string `json:"created_at"`          // ISO 8601
+}
+
+// AdminTransactionDTO - A transaction with its ledger postings
+type AdminTransactionDTO struct {
+	ID             int64             `json:"id"`
+	Reference      string            `json:"reference"`
+	Kind           string            `json:"kind"`
+	Status         string            `json:"status"`
+	Amount         int64             `json:"amount"` // In kobo
+	Currency       string            `json:"currency"`
+	FromAccountID  *int64            `json:"from_account_id,omitempty"`
+	ToAccountID    *int64            `json:"to_account_id,omitempty"`
+	FromIdentifier string            `json:"from_identifier,omitempty"`
+	ToIdentifier   string            `json:"to_identifier,omitempty"`
+	Description    string            `json:"description,omitempty"`
+	FailureReason  string            `json:"failure_reason,omitempty"`
+	CreatedAt      string            `json:"created_at"`          // ISO 8601
+	PostedAt       string            `json:"posted_at,omitempty"` // ISO 8601
+	FailedAt       string            `json:"failed_at,omitempty"` // ISO 8601
+	Postings       []AdminPostingDTO `json:"postings"`
+}
+
+// AdminPostingDTO - One leg of a transaction
+type AdminPostingDTO struct {
+	AccountID int64  `json:"account_id"`
+	Amount    int64  `json:"amount"` // In kobo; positive credits, negative debits
+	Currency  string `json:"currency"`
+}
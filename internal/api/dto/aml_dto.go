@@ -0,0 +1,98 @@
+package dto
+
+// ==============================================
+// AML CASE REQUEST DTOs
+// ==============================================
+
+// ListAMLCasesRequest - Case queue query parameters
+type ListAMLCasesRequest struct {
+	Status     string `form:"status" binding:"omitempty,oneof=open escalated closed"`
+	Typology   string `form:"typology" binding:"omitempty,oneof=structuring rapid_in_out fan_in fan_out dormant_reactivation"`
+	AssignedTo int    `form:"assigned_to" binding:"omitempty,min=1"`
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	PerPage    int    `form:"per_page" binding:"omitempty,min=1,max=100"`
+}
+
+// AssignAMLCaseRequest - Hand a case to an investigator (defaults to the caller)
+type AssignAMLCaseRequest struct {
+	AssigneeID int `json:"assignee_id,omitempty" binding:"omitempty,min=1"`
+}
+
+// AddAMLCaseNoteRequest - Investigator comment
+type AddAMLCaseNoteRequest struct {
+	Body string `json:"body" binding:"required,min=1,max=2000"`
+}
+
+// EscalateAMLCaseRequest - Escalate, optionally freezing the account
+type EscalateAMLCaseRequest struct {
+	Note          string `json:"note,omitempty" binding:"max=2000"`
+	FreezeAccount bool   `json:"freeze_account,omitempty"`
+}
+
+// CloseAMLCaseRequest - Resolve a case, optionally lifting a freeze it applied
+type CloseAMLCaseRequest struct {
+	Resolution      string `json:"resolution" binding:"required,oneof=false_positive no_action reported"`
+	Note            string `json:"note,omitempty" binding:"max=2000"`
+	UnfreezeAccount bool   `json:"unfreeze_account,omitempty"`
+}
+
+// ==============================================
+// AML CASE RESPONSE DTOs
+// ==============================================
+
+// AMLCaseDTO - A monitoring case; transactions and notes are only filled on the detail view
+type AMLCaseDTO struct {
+	ID             int64                   `json:"id"`
+	AccountID      int64                   `json:"account_id"`
+	UserID         *int                    `json:"user_id,omitempty"`
+	Typology       string                  `json:"typology"`
+	Status         string                  `json:"status"`
+	Summary        string                  `json:"summary"`
+	Details        map[string]any          `json:"details"`
+	AssignedTo     *int                    `json:"assigned_to,omitempty"`
+	AccountFrozen  bool                    `json:"account_frozen"`
+	Resolution     string                  `json:"resolution,omitempty"`
+	LastDetectedAt string                  `json:"last_detected_at"`
+	EscalatedAt    string                  `json:"escalated_at,omitempty"`
+	ClosedAt       string                  `json:"closed_at,omitempty"`
+	CreatedAt      string                  `json:"created_at"`
+	Transactions   []AMLCaseTransactionDTO `json:"transactions,omitempty"`
+	Notes          []AMLCaseNoteDTO        `json:"notes,omitempty"`
+}
+
+// AMLCaseTransactionDTO - A transaction linked to a case
+type AMLCaseTransactionDTO struct {
+	ID            int64   `json:"id"`
+	Reference     string  `json:"reference"`
+	Kind          string  `json:"kind"`
+	Status        string  `json:"status"`
+	Amount        int64   `json:"amount"`
+	AmountNGN     float64 `json:"amount_ngn"`
+	FromAccountID *int64  `json:"from_account_id,omitempty"`
+	ToAccountID   *int64  `json:"to_account_id,omitempty"`
+	CreatedAt     string  `json:"created_at"`
+}
+
+// AMLCaseNoteDTO - An investigator comment
+type AMLCaseNoteDTO struct {
+	ID        int64  `json:"id"`
+	AuthorID  *int   `json:"author_id,omitempty"`
+	Body      string `json:"body"`
+	CreatedAt string `json:"created_at"`
+}
+
+// AMLCaseListResponse - A page of cases
+type AMLCaseListResponse struct {
+	Cases   []AMLCaseDTO `json:"cases"`
+	Page    int          `json:"page"`
+	PerPage int          `json:"per_page"`
+}
+
+// AMLScanResponse - Result of one monitoring pass
+type AMLScanResponse struct {
+	RunID        int64  `json:"run_id"`
+	CasesOpened  int    `json:"cases_opened"`
+	CasesUpdated int    `json:"cases_updated"`
+	StartedAt    string `json:"started_at"`
+	FinishedAt   string `json:"finished_at"`
+}
@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ==============================================
+// AUDIT REQUEST DTOs
+// ==============================================
+
+// ListSecurityActivityRequest - Paging for the caller's security history
+type ListSecurityActivityRequest struct {
+	Page    int `form:"page" binding:"omitempty,min=1"`
+	PerPage int `form:"per_page" binding:"omitempty,min=1,max=100"`
+}
+
+// SearchAuditLogsRequest - Staff search; every filter is optional
+type SearchAuditLogsRequest struct {
+	ActorID    int       `form:"actor_id" binding:"omitempty,min=1"`
+	UserID     int       `form:"user_id" binding:"omitempty,min=1"` // Acted as, or acted on
+	Action     string    `form:"action" binding:"max=64"`
+	EntityType string    `form:"entity_type" binding:"max=64"`
+	EntityID   int64     `form:"entity_id" binding:"omitempty,min=1"`
+	From       time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Page       int       `form:"page" binding:"omitempty,min=1"`
+	PerPage    int       `form:"per_page" binding:"omitempty,min=1,max=100"`
+}
+
+// ==============================================
+// AUDIT RESPONSE DTOs
+// ==============================================
+
+// SecurityEventDTO - One entry in a user's security history
+type SecurityEventDTO struct {
+	ID        int64                  `json:"id"`
+	Action    string                 `json:"action"`
+	ByStaff   bool                   `json:"by_staff"` // Done to the account by support or compliance
+	IPAddress string                 `json:"ip_address,omitempty"`
+	UserAgent string                 `json:"user_agent,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"` // Only on the user's own actions
+	CreatedAt string                 `json:"created_at"`        // ISO 8601
+}
+
+// SecurityActivityResponse - The caller's security history, newest first
+type SecurityActivityResponse struct {
+	Events  []SecurityEventDTO `json:"events"`
+	Page    int                `json:"page"`
+	PerPage int                `json:"per_page"`
+}
+
+// AuditLogDTO - A raw audit entry as staff see it
+type AuditLogDTO struct {
+	ID         int64           `json:"id"`
+	ActorID    *int            `json:"actor_id,omitempty"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type,omitempty"`
+	EntityID   *int64          `json:"entity_id,omitempty"`
+	Metadata   json.RawMessage `json:"metadata,omitempty"`
+	IPAddress  string          `json:"ip_address,omitempty"`
+	UserAgent  string          `json:"user_agent,omitempty"`
+	CreatedAt  string          `json:"created_at"` // ISO 8601
+}
+
+// AuditLogListResponse - One page of audit search results
+type AuditLogListResponse struct {
+	Logs    []AuditLogDTO `json:"logs"`
+	Page    int           `json:"page"`
+	PerPage int           `json:"per_page"`
+}
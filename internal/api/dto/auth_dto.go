@@ -56,8 +56,9 @@ type ChangePasswordRequest struct {
 
 // SetPinRequest - Set or update transaction PIN
 type SetPinRequest struct {
-	Pin        string `json:"pin" binding:"required,len=4,numeric"`
-	ConfirmPin string `json:"confirm_pin" binding:"required,len=4,numeric,eqfield=Pin"`
+	Pin        string      `json:"pin" binding:"required,len=4,numeric"`
+	ConfirmPin string      `json:"confirm_pin" binding:"required,len=4,numeric,eqfield=Pin"`
+	StepUp     StepUpProof `json:"step_up"` // Needed to change an existing PIN
 }
 
 // ValidatePinRequest - For transaction authorization
@@ -88,13 +89,19 @@ type VerifyEmailResponse struct {
 	NextStep string `json:"next_step,omitempty"` // "complete_onboarding"
 }
 
-// LoginResponse
+// LoginResponse - Either a token, or (with 2FA on) an MFA challenge to complete at /auth/login/mfa
 type LoginResponse struct {
-	User         *UserDTO `json:"user"`
-	AccessToken  string   `json:"access_token"`
+	User         *UserDTO `json:"user,omitempty"`
+	AccessToken  string   `json:"access_token,omitempty"`
 	RefreshToken string   `json:"refresh_token,omitempty"`
-	ExpiresIn    int      `json:"expires_in"` // seconds
-	TokenType    string   `json:"token_type"` // "Bearer"
+	ExpiresIn    int      `json:"expires_in,omitempty"` // seconds
+	TokenType    string   `json:"token_type,omitempty"` // "Bearer"
+
+	MFARequired  bool   `json:"mfa_required,omitempty"`
+	MFAToken     string `json:"mfa_token,omitempty"`      // Short-lived, single use
+	MFAMethod    string `json:"mfa_method,omitempty"`     // "totp" or "email"
+	MFAReason    string `json:"mfa_reason,omitempty"`     // "mfa" (2FA is on) or "new_device"
+	MFAExpiresIn int    `json:"mfa_expires_in,omitempty"` // seconds
 }
 
 // CompleteOnboardingResponse
@@ -154,6 +161,7 @@ type UserDTO struct {
 	Username            *string `json:"username,omitempty"`
 	IsEmailVerified     bool    `json:"is_email_verified"`
 	OnboardingCompleted bool    `json:"onboarding_completed"`
+	Role                string  `json:"role"`
 	CreatedAt           string  `json:"created_at"` // ISO 8601
 }
 
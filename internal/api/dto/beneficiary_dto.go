@@ -0,0 +1,69 @@
+package dto
+
+// ==============================================
+// BENEFICIARY REQUEST DTOs
+// ==============================================
+
+// NameEnquiryRequest - Look up who an identifier pays before sending money
+type NameEnquiryRequest struct {
+	Identifier string `form:"identifier" json:"identifier" binding:"required"` // @username, phone, or account_number
+}
+
+// CreateBeneficiaryRequest - Save a recipient to the address book
+type CreateBeneficiaryRequest struct {
+	Identifier string      `json:"identifier" binding:"required"` // @username, phone, or account_number
+	Nickname   string      `json:"nickname,omitempty" binding:"max=50"`
+	StepUp     StepUpProof `json:"step_up"`
+}
+
+// ListRecipientsRequest - Recent or frequent recipients from history
+type ListRecipientsRequest struct {
+	Order string `form:"order" binding:"omitempty,oneof=recent frequent"` // Default "recent"
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// ==============================================
+// BENEFICIARY RESPONSE DTOs
+// ==============================================
+
+// NameEnquiryResponse - Masked details of the account an identifier resolves to
+type NameEnquiryResponse struct {
+	Identifier    string `json:"identifier"`
+	AccountName   string `json:"account_name"`   // Masked: "Ada O*** N***"
+	AccountNumber string `json:"account_number"` // Masked: "******5678"
+}
+
+// BeneficiaryDTO - Saved recipient
+// Pay a beneficiary by sending Identifier as to_identifier
+type BeneficiaryDTO struct {
+	ID            int64   `json:"id"`
+	Nickname      *string `json:"nickname,omitempty"`
+	Identifier    string  `json:"identifier"`
+	AccountName   string  `json:"account_name"`   // Masked
+	AccountNumber string  `json:"account_number"` // Masked
+	Status        string  `json:"status"`         // active, held (pending screening review), blocked
+	CreatedAt     string  `json:"created_at"`     // ISO 8601
+}
+
+// BeneficiaryListResponse - Address book
+type BeneficiaryListResponse struct {
+	Beneficiaries []BeneficiaryDTO `json:"beneficiaries"`
+}
+
+// RecipientDTO - Someone the user has sent money to before
+type RecipientDTO struct {
+	Identifier     string  `json:"identifier"`     // Last to_identifier used
+	AccountName    string  `json:"account_name"`   // Masked
+	AccountNumber  string  `json:"account_number"` // Masked
+	TransferCount  int     `json:"transfer_count"`
+	TotalAmount    int64   `json:"total_amount"` // In kobo
+	TotalAmountNGN float64 `json:"total_amount_ngn"`
+	LastTransferAt string  `json:"last_transfer_at"` // ISO 8601
+	IsBeneficiary  bool    `json:"is_beneficiary"`
+}
+
+// RecipientListResponse - Recent or frequent recipients
+type RecipientListResponse struct {
+	Order      string         `json:"order"`
+	Recipients []RecipientDTO `json:"recipients"`
+}
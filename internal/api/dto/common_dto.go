@@ -4,13 +4,6 @@ package dto
 // COMMON RESPONSE DTOs
 // ==============================================
 
-// ErrorResponse - Standard error format
-type ErrorResponse struct {
-	Error   string            `json:"error"`
-	Message string            `json:"message"`
-	Details map[string]string `json:"details,omitempty"`
-}
-
 // SuccessResponse - Generic success response
 type SuccessResponse struct {
 	Success bool        `json:"success"`
@@ -30,11 +23,4 @@ type PaginationMeta struct {
 	PerPage    int `json:"per_page"`
 	Total      int `json:"total"`
 	TotalPages int `json:"total_pages"`
-}
-
-// HealthResponse - API health check
-type HealthResponse struct {
-	Status    string `json:"status"`
-	Timestamp string `json:"timestamp"`
-	Version   string `json:"version,omitempty"`
 }
\ No newline at end of file
@@ -0,0 +1,37 @@
+package dto
+
+// ==============================================
+// DEVICE REQUEST DTOs
+// ==============================================
+
+// RenameDeviceRequest - Give a trusted device a friendlier name
+type RenameDeviceRequest struct {
+	Name string `json:"name" binding:"required,max=64"`
+}
+
+// ==============================================
+// DEVICE RESPONSE DTOs
+// ==============================================
+
+// DeviceDTO - A device the user has signed in from
+type DeviceDTO struct {
+	ID             int64  `json:"id"`
+	Name           string `json:"name"`
+	UserAgent      string `json:"user_agent,omitempty"`
+	LastIP         string `json:"last_ip,omitempty"`
+	Current        bool   `json:"current"`         // The device making this request
+	ActiveSessions int    `json:"active_sessions"` // Signed-in sessions that revoking would end
+	FirstSeenAt    string `json:"first_seen_at"`   // ISO 8601
+	LastSeenAt     string `json:"last_seen_at"`    // ISO 8601, last sign-in
+}
+
+// DeviceListResponse - The user's trusted devices
+type DeviceListResponse struct {
+	Devices []DeviceDTO `json:"devices"`
+}
+
+// RevokeDeviceResponse
+type RevokeDeviceResponse struct {
+	Message       string `json:"message"`
+	SessionsEnded int64  `json:"sessions_ended"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+// ==============================================
+// EVENT STREAM REQUEST DTOs
+// ==============================================
+
+// EventStreamRequest - Where a reconnecting stream resumes
+// SSE clients may send the Last-Event-ID header instead; it wins when both are set.
+type EventStreamRequest struct {
+	LastEventID int64 `form:"last_event_id" binding:"omitempty,min=0"` // Replay events after this ID; 0 only sends new ones
+}
+
+// ==============================================
+// EVENT STREAM RESPONSE DTOs
+// ==============================================
+
+// AccountEvent - One message on the event streams: an SSE data line, or a WebSocket text frame
+type AccountEvent struct {
+	ID          int64                  `json:"id"`                    // Resume after it with Last-Event-ID or last_event_id
+	Type        string                 `json:"type"`                  // "transaction_posted", "balance_changed", or "heartbeat" (WebSocket only)
+	Transaction *TransactionPostedData `json:"transaction,omitempty"` // Set for transaction_posted
+	Balance     *BalanceChangedData    `json:"balance,omitempty"`     // Set for balance_changed
+	CreatedAt   string                 `json:"created_at,omitempty"`  // ISO 8601
+}
+
+// TransactionPostedData - A transaction that moved money in or out of the caller's account
+type TransactionPostedData struct {
+	TransactionID int64   `json:"transaction_id"`
+	Reference     string  `json:"reference"`
+	Type          string  `json:"type"` // 'p2p', 'deposit', 'withdrawal'
+	Status        string  `json:"status"`
+	Amount        int64   `json:"amount"`    // In kobo
+	Direction     string  `json:"direction"` // 'credit' or 'debit'
+	Description   *string `json:"description,omitempty"`
+	Currency      string  `json:"currency"`
+}
+
+// BalanceChangedData - The caller's new balance
+type BalanceChangedData struct {
+	AccountNumber   string `json:"account_number"`
+	Balance         int64  `json:"balance"`          // In kobo
+	PreviousBalance int64  `json:"previous_balance"` // In kobo
+	Currency        string `json:"currency"`
+}
@@ -0,0 +1,61 @@
+package dto
+
+// ==============================================
+// INTEGRITY REQUEST DTOs
+// ==============================================
+
+// VerifyChainRequest - Which hash chain to walk
+type VerifyChainRequest struct {
+	Chain string `form:"chain" binding:"required,oneof=transactions audit_logs"`
+}
+
+// ListCheckpointsRequest - A chain's signed checkpoints, newest first
+type ListCheckpointsRequest struct {
+	Chain   string `form:"chain" binding:"required,oneof=transactions audit_logs"`
+	Page    int    `form:"page" binding:"omitempty,min=1"`
+	PerPage int    `form:"per_page" binding:"omitempty,min=1,max=100"`
+}
+
+// ==============================================
+// INTEGRITY RESPONSE DTOs
+// ==============================================
+
+// ChainBreakDTO - A row where the chain stops matching
+type ChainBreakDTO struct {
+	RowID  int64  `json:"row_id"`
+	Reason string `json:"reason"`
+}
+
+// ChainVerificationResponse - The result of walking a chain from its first row to its head
+type ChainVerificationResponse struct {
+	Chain                   string          `json:"chain"`
+	Intact                  bool            `json:"intact"`
+	RowsChecked             int64           `json:"rows_checked"`
+	FirstID                 int64           `json:"first_id,omitempty"`
+	LastID                  int64           `json:"last_id,omitempty"`
+	HeadHash                string          `json:"head_hash"` // Hex
+	CheckpointsChecked      int             `json:"checkpoints_checked"`
+	CheckpointsUnverifiable int             `json:"checkpoints_unverifiable"` // Signed by a key no longer in the keyring
+	Breaks                  []ChainBreakDTO `json:"breaks"`
+	BreaksTruncated         bool            `json:"breaks_truncated"`
+	VerifiedAt              string          `json:"verified_at"` // ISO 8601
+}
+
+// ChainCheckpointDTO - A signed snapshot of a chain head
+type ChainCheckpointDTO struct {
+	ID        int64  `json:"id"`
+	Chain     string `json:"chain"`
+	LastID    int64  `json:"last_id"`
+	LastHash  string `json:"last_hash"` // Hex
+	Rows      int64  `json:"rows"`
+	KeyID     string `json:"key_id"`
+	Signature string `json:"signature"` // Compact JWS; verify against /.well-known/jwks.json
+	CreatedAt string `json:"created_at"`
+}
+
+// ChainCheckpointListResponse - One page of checkpoints
+type ChainCheckpointListResponse struct {
+	Checkpoints []ChainCheckpointDTO `json:"checkpoints"`
+	Page        int                  `json:"page"`
+	PerPage     int                  `json:"per_page"`
+}
@@ -0,0 +1,80 @@
+package dto
+
+// ==============================================
+// JOURNAL ENTRY REQUEST DTOs
+// ==============================================
+// Manual postings between arbitrary accounts under maker-checker control:
+// one staff member submits, another approves before anything posts.
+
+// JournalLegRequest - One account's side of an entry
+type JournalLegRequest struct {
+	AccountNumber string `json:"account_number" binding:"required"`
+	Amount        int64  `json:"amount" binding:"required,ne=0"` // In kobo; positive credits, negative debits
+	Memo          string `json:"memo,omitempty" binding:"max=200"`
+}
+
+// SubmitJournalEntryRequest - Legs must sum to zero, touch each account once and share a currency
+type SubmitJournalEntryRequest struct {
+	Reason string              `json:"reason" binding:"required,min=1,max=500"`
+	Legs   []JournalLegRequest `json:"legs" binding:"required,min=2,max=50,dive"`
+}
+
+// ListJournalEntriesRequest - Approval queue query parameters
+type ListJournalEntriesRequest struct {
+	Status  string `form:"status" binding:"omitempty,oneof=pending_approval posted rejected"` // Default "pending_approval"
+	Page    int    `form:"page" binding:"omitempty,min=1"`
+	PerPage int    `form:"per_page" binding:"omitempty,min=1,max=100"`
+}
+
+// ReviewJournalEntryRequest - Checker decision (a note is required to reject)
+type ReviewJournalEntryRequest struct {
+	Note string `json:"note,omitempty" binding:"max=500"`
+}
+
+// ==============================================
+// JOURNAL ENTRY RESPONSE DTOs
+// ==============================================
+
+// JournalEntryDTO - An entry with its legs and attachments
+type JournalEntryDTO struct {
+	ID            int64                  `json:"id"`
+	Reference     string                 `json:"reference"` // Shared with the transaction once posted
+	Status        string                 `json:"status"`    // 'pending_approval', 'posted', 'rejected'
+	Currency      string                 `json:"currency"`
+	Total         int64                  `json:"total"` // Sum of the credit legs, in kobo
+	Reason        string                 `json:"reason"`
+	MakerUserID   int                    `json:"maker_user_id"`
+	CheckerUserID *int                   `json:"checker_user_id,omitempty"`
+	ReviewNote    *string                `json:"review_note,omitempty"`
+	ReviewedAt    *string                `json:"reviewed_at,omitempty"`    // ISO 8601
+	TransactionID *int64                 `json:"transaction_id,omitempty"` // Once posted
+	CreatedAt     string                 `json:"created_at"`               // ISO 8601
+	Legs          []JournalLegDTO        `json:"legs"`
+	Attachments   []JournalAttachmentDTO `json:"attachments"`
+}
+
+// JournalLegDTO - One leg of an entry
+type JournalLegDTO struct {
+	AccountID     int64  `json:"account_id"`
+	AccountNumber string `json:"account_number"`
+	Amount        int64  `json:"amount"` // In kobo; positive credits, negative debits
+	Memo          string `json:"memo,omitempty"`
+}
+
+// JournalAttachmentDTO - Uploaded attachment metadata
+type JournalAttachmentDTO struct {
+	ID          int64  `json:"id"`
+	UploadedBy  int    `json:"uploaded_by"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	SizeBytes   int64  `json:"size_bytes"`
+	SHA256      string `json:"sha256"`
+	CreatedAt   string `json:"created_at"` // ISO 8601
+}
+
+// JournalEntryListResponse - A page of the approval queue
+type JournalEntryListResponse struct {
+	Entries []JournalEntryDTO `json:"entries"`
+	Page    int               `json:"page"`
+	PerPage int               `json:"per_page"`
+}
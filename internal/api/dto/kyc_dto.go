@@ -0,0 +1,101 @@
+package dto
+
+// ==============================================
+// KYC RESPONSE DTOs
+// ==============================================
+
+// KYCTierDTO - Limits for one tier (all amounts in kobo)
+type KYCTierDTO struct {
+	Tier                 int    `json:"tier"`
+	Name                 string `json:"name"`
+	Requirements         string `json:"requirements"`
+	MaxSingleTransaction int64  `json:"max_single_transaction"`
+	DailyDebitLimit      int64  `json:"daily_debit_limit"`
+	MonthlyDebitLimit    int64  `json:"monthly_debit_limit"`
+	MaxBalance           *int64 `json:"max_balance"` // null = unlimited
+}
+
+// KYCTiersResponse - Every tier, lowest first
+type KYCTiersResponse struct {
+	Tiers []KYCTierDTO `json:"tiers"`
+}
+
+// KYCLimitsResponse - The caller's tier, limits and rolling usage
+type KYCLimitsResponse struct {
+	CurrentTier           KYCTierDTO  `json:"current_tier"`
+	Balance               int64       `json:"balance"`
+	DailyDebitUsed        int64       `json:"daily_debit_used"`        // Last 24 hours
+	DailyDebitRemaining   int64       `json:"daily_debit_remaining"`   // Before the daily cap
+	MonthlyDebitUsed      int64       `json:"monthly_debit_used"`      // Last 30 days
+	MonthlyDebitRemaining int64       `json:"monthly_debit_remaining"` // Before the monthly cap
+	NextTier              *KYCTierDTO `json:"next_tier,omitempty"`     // What upgrading unlocks
+}
+
+// ==============================================
+// KYC SUBMISSION REQUEST DTOs
+// ==============================================
+
+// SubmitKYCRequest - Ask to be moved up to a higher tier
+// Tier 3 also needs an address and uploaded documents before it can be approved
+type SubmitKYCRequest struct {
+	TargetTier int    `json:"target_tier" binding:"required,oneof=2 3"`
+	IDType     string `json:"id_type" binding:"required,oneof=bvn nin"`
+	IDNumber   string `json:"id_number" binding:"required,len=11,numeric"`
+	Address    string `json:"address,omitempty" binding:"max=300"`
+}
+
+// UploadKYCDocumentRequest - multipart form fields sent alongside the "file" part
+type UploadKYCDocumentRequest struct {
+	DocumentType string `form:"document_type" binding:"required,oneof=id_card passport drivers_license utility_bill selfie"`
+}
+
+// ListKYCSubmissionsRequest - Review queue query parameters
+type ListKYCSubmissionsRequest struct {
+	Status  string `form:"status" binding:"omitempty,oneof=pending approved rejected"` // Default "pending"
+	Page    int    `form:"page" binding:"omitempty,min=1"`
+	PerPage int    `form:"per_page" binding:"omitempty,min=1,max=100"`
+}
+
+// ReviewKYCSubmissionRequest - Reviewer decision (reason is required to reject)
+type ReviewKYCSubmissionRequest struct {
+	Reason string `json:"reason,omitempty" binding:"max=500"`
+}
+
+// ==============================================
+// KYC SUBMISSION RESPONSE DTOs
+// ==============================================
+
+// KYCDocumentDTO - Uploaded document metadata
+type KYCDocumentDTO struct {
+	ID           int64  `json:"id"`
+	DocumentType string `json:"document_type"`
+	FileName     string `json:"file_name"`
+	ContentType  string `json:"content_type"`
+	SizeBytes    int64  `json:"size_bytes"`
+	SHA256       string `json:"sha256"`
+	CreatedAt    string `json:"created_at"` // ISO 8601
+}
+
+// KYCSubmissionDTO - Submission as seen by the user or a reviewer
+type KYCSubmissionDTO struct {
+	ID                 int64            `json:"id"`
+	UserID             int              `json:"user_id"`
+	TargetTier         int              `json:"target_tier"`
+	IDType             string           `json:"id_type"`
+	IDNumber           string           `json:"id_number"` // Masked for the user, full for reviewers
+	Address            *string          `json:"address,omitempty"`
+	Status             string           `json:"status"`              // 'pending', 'approved', 'rejected'
+	VerificationStatus string           `json:"verification_status"` // 'matched', 'mismatch', 'not_found', 'error'
+	VerifiedName       *string          `json:"verified_name,omitempty"`
+	ReviewReason       *string          `json:"review_reason,omitempty"`
+	ReviewedAt         *string          `json:"reviewed_at,omitempty"`
+	CreatedAt          string           `json:"created_at"`
+	Documents          []KYCDocumentDTO `json:"documents"`
+}
+
+// KYCSubmissionListResponse - A user's submissions or the review queue
+type KYCSubmissionListResponse struct {
+	Submissions []KYCSubmissionDTO `json:"submissions"`
+	Page        int                `json:"page,omitempty"`
+	PerPage     int                `json:"per_page,omitempty"`
+}
@@ -0,0 +1,113 @@
+package dto
+
+import "time"
+
+// ==============================================
+// LEDGER OPERATION REQUEST DTOs
+// ==============================================
+// Ops corrections driven by cmd/debankctl. Every change needs a reason,
+// which is kept in the transaction's metadata and the audit log.
+
+// CreateSystemAccountRequest - Open an account with no user behind it (reserve, fees, settlement)
+type CreateSystemAccountRequest struct {
+	ExternalID string `json:"external_id" binding:"required,min=3,max=50"` // e.g. sys_settlement; lowercase letters, digits and underscores
+	Name       string `json:"name" binding:"required,min=1,max=100"`
+	Type       string `json:"type" binding:"required,oneof=system reserve fee"`
+	Currency   string `json:"currency" binding:"required,len=3,uppercase"`
+	Reason     string `json:"reason" binding:"required,min=1,max=500"`
+}
+
+// AdjustmentRequest - Credit or debit an account by hand, against a system account
+type AdjustmentRequest struct {
+	AccountNumber  string `json:"account_number" binding:"required"`
+	Direction      string `json:"direction" binding:"required,oneof=credit debit"`
+	Amount         int64  `json:"amount" binding:"required,gt=0"`                  // In kobo
+	CounterAccount string `json:"counter_account" binding:"required,min=3,max=50"` // System account external_id, e.g. sys_reserve
+	Reason         string `json:"reason" binding:"required,min=1,max=500"`
+	IdempotencyKey string `json:"idempotency_key" binding:"required,min=1,max=100"`
+}
+
+// ReverseTransactionRequest - Post the mirror image of a transaction and mark it reversed
+type ReverseTransactionRequest struct {
+	Reference      string `json:"reference" binding:"required"`
+	Reason         string `json:"reason" binding:"required,min=1,max=500"`
+	IdempotencyKey string `json:"idempotency_key" binding:"required,min=1,max=100"`
+}
+
+// FreezeAccountRequest - Stop debits from an account
+type FreezeAccountRequest struct {
+	AccountNumber string `json:"account_number" binding:"required"`
+	Reason        string `json:"reason" binding:"required,min=1,max=500"`
+}
+
+// UnfreezeAccountRequest - Lift a freeze, including one placed by AML or sanctions screening
+type UnfreezeAccountRequest struct {
+	AccountNumber string `json:"account_number" binding:"required"`
+	Reason        string `json:"reason" binding:"required,min=1,max=500"`
+}
+
+// StatementRequest - An account's postings in [From, To)
+type StatementRequest struct {
+	AccountNumber string    `json:"account_number" binding:"required"`
+	From          time.Time `json:"from" binding:"required"`
+	To            time.Time `json:"to" binding:"required,gtfield=From"`
+}
+
+// ==============================================
+// LEDGER OPERATION RESPONSE DTOs
+// ==============================================
+
+// ReconciliationReport - Whether every balance and transaction agrees with the postings
+type ReconciliationReport struct {
+	OK                     bool                       `json:"ok"`
+	Accounts               int64                      `json:"accounts"`
+	Transactions           int64                      `json:"transactions"`
+	BalanceTotal           int64                      `json:"balance_total"` // In kobo
+	PostingTotal           int64                      `json:"posting_total"` // In kobo; zero when balanced
+	BalanceMismatches      []BalanceMismatchDTO       `json:"balance_mismatches"`
+	UnbalancedTransactions []UnbalancedTransactionDTO `json:"unbalanced_transactions"`
+	CheckedAt              string                     `json:"checked_at"` // ISO 8601
+}
+
+// BalanceMismatchDTO - An account whose balance isn't the sum of its postings
+type BalanceMismatchDTO struct {
+	AccountID     int64  `json:"account_id"`
+	AccountNumber string `json:"account_number"`
+	ExternalID    string `json:"external_id,omitempty"`
+	Balance       int64  `json:"balance"`        // Stored, in kobo
+	PostedBalance int64  `json:"posted_balance"` // Sum of postings, in kobo
+	Difference    int64  `json:"difference"`     // Balance - PostedBalance
+}
+
+// UnbalancedTransactionDTO - A transaction whose postings don't net to zero
+type UnbalancedTransactionDTO struct {
+	TransactionID int64  `json:"transaction_id"`
+	Reference     string `json:"reference"`
+	Status        string `json:"status"`
+	PostingTotal  int64  `json:"posting_total"` // In kobo
+	Postings      int    `json:"postings"`
+}
+
+// StatementResponse - An account's movements over a period with running balances
+type StatementResponse struct {
+	Account        AdminAccountDTO     `json:"account"`
+	From           string              `json:"from"` // ISO 8601
+	To             string              `json:"to"`   // ISO 8601
+	OpeningBalance int64               `json:"opening_balance"`
+	ClosingBalance int64               `json:"closing_balance"`
+	TotalCredits   int64               `json:"total_credits"`
+	TotalDebits    int64               `json:"total_debits"`
+	Entries        []StatementEntryDTO `json:"entries"`
+}
+
+// StatementEntryDTO - One line of a statement
+type StatementEntryDTO struct {
+	Date        string `json:"date"` // ISO 8601
+	Reference   string `json:"reference"`
+	Type        string `json:"type"`
+	Status      string `json:"status"`
+	Description string `json:"description,omitempty"`
+	Direction   string `json:"direction"` // 'credit' or 'debit'
+	Amount      int64  `json:"amount"`    // In kobo, always positive
+	Balance     int64  `json:"balance"`   // Running balance after this entry
+}
@@ -0,0 +1,67 @@
+package dto
+
+// ==============================================
+// MFA REQUEST DTOs
+// ==============================================
+
+// MFALoginRequest - Second login step: a TOTP/email code or a recovery code
+type MFALoginRequest struct {
+	MFAToken     string `json:"mfa_token" binding:"required"`
+	Code         string `json:"code,omitempty" binding:"required_without=RecoveryCode,omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recovery_code,omitempty" binding:"omitempty,max=20"`
+}
+
+// MFAEmailFallbackRequest - Email a code for a pending login challenge instead of using the authenticator
+type MFAEmailFallbackRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+}
+
+// ConfirmTOTPRequest - First code from the authenticator app, proving enrollment worked
+type ConfirmTOTPRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// EnableEmailMFARequest - Turn on emailed codes (re-authenticate with password)
+type EnableEmailMFARequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// MFAReauthRequest - Password plus a current second factor, for disabling 2FA or new recovery codes
+type MFAReauthRequest struct {
+	Password     string `json:"password" binding:"required"`
+	Code         string `json:"code,omitempty" binding:"required_without=RecoveryCode,omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recovery_code,omitempty" binding:"omitempty,max=20"`
+}
+
+// ==============================================
+// MFA RESPONSE DTOs
+// ==============================================
+
+// MFAStatusResponse - The caller's 2FA settings
+type MFAStatusResponse struct {
+	Enabled                bool   `json:"enabled"`
+	Method                 string `json:"method,omitempty"`
+	PendingEnrollment      bool   `json:"pending_enrollment,omitempty"` // TOTP secret issued but not confirmed
+	RecoveryCodesRemaining int    `json:"recovery_codes_remaining"`
+}
+
+// TOTPEnrollmentResponse - Secret for the authenticator app; show the URI as a QR code
+type TOTPEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+	Message    string `json:"message"`
+}
+
+// RecoveryCodesResponse - Shown once; only hashes are stored
+type RecoveryCodesResponse struct {
+	Method        string   `json:"method"`
+	RecoveryCodes []string `json:"recovery_codes"`
+	Message       string   `json:"message"`
+}
+
+// MFACodeSentResponse - An emailed code is on its way
+type MFACodeSentResponse struct {
+	Message   string `json:"message"`
+	Email     string `json:"email"`      // Masked
+	ExpiresIn int    `json:"expires_in"` // seconds
+}
@@ -0,0 +1,72 @@
+package dto
+
+// ==============================================
+// PAYMENT REQUEST REQUEST DTOs
+// ==============================================
+
+// CreatePaymentRequestRequest - Ask someone for money
+// Leave PayerIdentifier empty to create an open, shareable link
+type CreatePaymentRequestRequest struct {
+	PayerIdentifier string `json:"payer_identifier,omitempty"` // @username or phone
+	Amount          int64  `json:"amount" binding:"required,gt=0"`
+	Description     string `json:"description,omitempty" binding:"max=140"`
+	ExpiresInHours  int    `json:"expires_in_hours,omitempty" binding:"omitempty,min=1,max=720"` // Default 168 (7 days)
+}
+
+// PayPaymentRequestRequest - Payer approves a request with their PIN
+// No idempotency key: the request itself is the idempotency scope
+type PayPaymentRequestRequest struct {
+	Pin    string      `json:"pin" binding:"required,len=4,numeric"`
+	StepUp StepUpProof `json:"step_up"` // Needed for large amounts, as for transfers
+}
+
+// DeclinePaymentRequestRequest - Payer rejects a request
+type DeclinePaymentRequestRequest struct {
+	Reason string `json:"reason,omitempty" binding:"max=140"`
+}
+
+// ListPaymentRequestsRequest - History query parameters
+type ListPaymentRequestsRequest struct {
+	Direction string `form:"direction" binding:"omitempty,oneof=sent received"` // Default "received"
+	Status    string `form:"status" binding:"omitempty,oneof=open paid declined expired"`
+	Page      int    `form:"page" binding:"omitempty,min=1"`
+	PerPage   int    `form:"per_page" binding:"omitempty,min=1,max=100"`
+}
+
+// ==============================================
+// PAYMENT REQUEST RESPONSE DTOs
+// ==============================================
+
+// PaymentRequestDTO - Payment request as seen by either party
+type PaymentRequestDTO struct {
+	ID              int64   `json:"id"`
+	Token           string  `json:"token"`
+	Link            string  `json:"link"`
+	Requester       string  `json:"requester"`                  // Requester's display name
+	PayerIdentifier *string `json:"payer_identifier,omitempty"` // NULL for open links
+	Amount          int64   `json:"amount"`                     // In kobo
+	AmountNGN       float64 `json:"amount_ngn"`
+	Currency        string  `json:"currency"`
+	Description     *string `json:"description,omitempty"`
+	Status          string  `json:"status"` // 'open', 'paid', 'declined', 'expired'
+	TransactionID   *int64  `json:"transaction_id,omitempty"`
+	DeclineReason   *string `json:"decline_reason,omitempty"`
+	ExpiresAt       string  `json:"expires_at"` // ISO 8601
+	PaidAt          *string `json:"paid_at,omitempty"`
+	DeclinedAt      *string `json:"declined_at,omitempty"`
+	CreatedAt       string  `json:"created_at"`
+}
+
+// PayPaymentRequestResponse - Result of settling a request
+type PayPaymentRequestResponse struct {
+	PaymentRequest *PaymentRequestDTO `json:"payment_request"`
+	Transfer       *TransferResponse  `json:"transfer"`
+}
+
+// PaymentRequestListResponse - Paginated history
+type PaymentRequestListResponse struct {
+	Direction       string              `json:"direction"`
+	PaymentRequests []PaymentRequestDTO `json:"payment_requests"`
+	Page            int                 `json:"page"`
+	PerPage         int                 `json:"per_page"`
+}
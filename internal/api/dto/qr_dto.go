@@ -0,0 +1,47 @@
+package dto
+
+// ==============================================
+// QR CODE REQUEST DTOs
+// ==============================================
+
+// GenerateQRRequest - Build a QR code for receiving money into the caller's account
+// Without an amount the code is static (reusable, payer enters the amount);
+// with an amount it is dynamic (single payment for a fixed amount)
+type GenerateQRRequest struct {
+	Amount    int64  `json:"amount,omitempty" form:"amount" binding:"omitempty,gt=0"` // In kobo
+	Reference string `json:"reference,omitempty" form:"reference" binding:"omitempty,max=25"`
+}
+
+// DecodeQRRequest - Validate a scanned QR payload before paying it
+type DecodeQRRequest struct {
+	Payload string `json:"payload" binding:"required"`
+}
+
+// ==============================================
+// QR CODE RESPONSE DTOs
+// ==============================================
+
+// QRCodeResponse - Generated QR code as a raw EMVCo payload and a PNG
+type QRCodeResponse struct {
+	Payload       string  `json:"payload"`
+	PNGBase64     string  `json:"png_base64"` // image/png, base64-encoded
+	Type          string  `json:"type"`       // 'static', 'dynamic'
+	AccountNumber string  `json:"account_number"`
+	AccountName   string  `json:"account_name"`
+	Amount        int64   `json:"amount,omitempty"` // In kobo
+	AmountNGN     float64 `json:"amount_ngn,omitempty"`
+	Reference     string  `json:"reference,omitempty"`
+}
+
+// DecodeQRResponse - Validated QR payload, with a transfer ready to submit
+// The client adds the PIN, an idempotency key and (for static codes) the amount,
+// then posts Transfer to /api/v1/transfer
+type DecodeQRResponse struct {
+	Type          string          `json:"type"`             // 'static', 'dynamic'
+	AccountNumber string          `json:"account_number"`   // Masked: "******5678"
+	AccountName   string          `json:"account_name"`     // Masked: "Ada O*** N***"
+	Amount        int64           `json:"amount,omitempty"` // In kobo, fixed for dynamic codes
+	AmountNGN     float64         `json:"amount_ngn,omitempty"`
+	Reference     string          `json:"reference,omitempty"`
+	Transfer      TransferRequest `json:"transfer"`
+}
@@ -0,0 +1,71 @@
+package dto
+
+// ==============================================
+// RISK RULE DTOs
+// ==============================================
+
+// RiskRuleDTO - A fraud rule and its current thresholds (amounts in kobo)
+type RiskRuleDTO struct {
+	Code        string           `json:"code"`
+	Description string           `json:"description"`
+	Enabled     bool             `json:"enabled"`
+	Action      string           `json:"action"`
+	Params      map[string]int64 `json:"params"`
+	UpdatedAt   string           `json:"updated_at"`
+}
+
+// RiskRulesResponse - Every configured rule
+type RiskRulesResponse struct {
+	Rules []RiskRuleDTO `json:"rules"`
+}
+
+// UpdateRiskRuleRequest - Change a rule; omitted fields are left as they are
+type UpdateRiskRuleRequest struct {
+	Enabled *bool            `json:"enabled,omitempty"`
+	Action  string           `json:"action,omitempty" binding:"omitempty,oneof=challenge block"`
+	Params  map[string]int64 `json:"params,omitempty"`
+}
+
+// ==============================================
+// RISK DECISION DTOs
+// ==============================================
+
+// ListRiskDecisionsRequest - Analyst query parameters
+type ListRiskDecisionsRequest struct {
+	UserID  int    `form:"user_id" binding:"omitempty,min=1"`
+	Outcome string `form:"outcome" binding:"omitempty,oneof=allow challenge block"`
+	Page    int    `form:"page" binding:"omitempty,min=1"`
+	PerPage int    `form:"per_page" binding:"omitempty,min=1,max=100"`
+}
+
+// RiskRuleHitDTO - A rule that fired during screening
+type RiskRuleHitDTO struct {
+	Code   string `json:"code"`
+	Action string `json:"action"`
+	Detail string `json:"detail"`
+}
+
+// RiskDecisionDTO - The outcome of screening one payment
+type RiskDecisionDTO struct {
+	ID                    int64            `json:"id"`
+	UserID                int              `json:"user_id"`
+	AccountID             int64            `json:"account_id"`
+	Operation             string           `json:"operation"`
+	Amount                int64            `json:"amount"`
+	AmountNGN             float64          `json:"amount_ngn"`
+	CounterpartyAccountID *int64           `json:"counterparty_account_id,omitempty"`
+	DeviceID              string           `json:"device_id,omitempty"`
+	IPAddress             string           `json:"ip_address,omitempty"`
+	UserAgent             string           `json:"user_agent,omitempty"`
+	Outcome               string           `json:"outcome"`
+	FiredRules            []RiskRuleHitDTO `json:"fired_rules"`
+	TransactionID         *int64           `json:"transaction_id,omitempty"`
+	CreatedAt             string           `json:"created_at"`
+}
+
+// RiskDecisionListResponse - A page of decisions
+type RiskDecisionListResponse struct {
+	Decisions []RiskDecisionDTO `json:"decisions"`
+	Page      int               `json:"page"`
+	PerPage   int               `json:"per_page"`
+}
@@ -0,0 +1,59 @@
+package dto
+
+// ==============================================
+// SCREENING REQUEST DTOs
+// ==============================================
+
+// ListScreeningHitsRequest - Review queue query parameters
+type ListScreeningHitsRequest struct {
+	Status      string `form:"status" binding:"omitempty,oneof=pending cleared confirmed"`
+	SubjectType string `form:"subject_type" binding:"omitempty,oneof=signup kyc_submission beneficiary"`
+	Page        int    `form:"page" binding:"omitempty,min=1"`
+	PerPage     int    `form:"per_page" binding:"omitempty,min=1,max=100"`
+}
+
+// ReviewScreeningHitRequest - Clear (false positive) or confirm a potential match
+type ReviewScreeningHitRequest struct {
+	Note string `json:"note,omitempty" binding:"max=2000"`
+}
+
+// ==============================================
+// SCREENING RESPONSE DTOs
+// ==============================================
+
+// ScreeningHitDTO - A potential watchlist match
+type ScreeningHitDTO struct {
+	ID           int64   `json:"id"`
+	SubjectType  string  `json:"subject_type"`
+	SubjectID    int64   `json:"subject_id"`
+	UserID       *int    `json:"user_id,omitempty"`
+	ScreenedName string  `json:"screened_name"`
+	ListName     string  `json:"list_name"`
+	EntryUID     string  `json:"entry_uid"`
+	EntryName    string  `json:"entry_name"`
+	MatchedName  string  `json:"matched_name"`
+	EntryType    string  `json:"entry_type,omitempty"`
+	Program      string  `json:"program,omitempty"`
+	Score        float64 `json:"score"`
+	Status       string  `json:"status"`
+	ReviewedBy   *int    `json:"reviewed_by,omitempty"`
+	ReviewedAt   string  `json:"reviewed_at,omitempty"`
+	ReviewNote   string  `json:"review_note,omitempty"`
+	CreatedAt    string  `json:"created_at"`
+}
+
+// ScreeningHitListResponse - A page of hits
+type ScreeningHitListResponse struct {
+	Hits    []ScreeningHitDTO `json:"hits"`
+	Page    int               `json:"page"`
+	PerPage int               `json:"per_page"`
+}
+
+// WatchlistStatusResponse - What the screener currently has loaded
+type WatchlistStatusResponse struct {
+	Loaded    bool     `json:"loaded"`
+	Files     []string `json:"files"`
+	Entries   int      `json:"entries"`
+	Threshold float64  `json:"threshold"`
+	LoadedAt  string   `json:"loaded_at,omitempty"`
+}
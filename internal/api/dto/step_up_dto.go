@@ -0,0 +1,55 @@
+package dto
+
+// ==============================================
+// STEP-UP REQUEST DTOs
+// ==============================================
+
+// StepUpProof - Answer to a step-up challenge, sent with the retried request
+// Leave empty on the first attempt; a sensitive operation then responds with a challenge
+type StepUpProof struct {
+	Token string `json:"token,omitempty"`
+	Code  string `json:"code,omitempty" binding:"omitempty,len=6,numeric"`
+}
+
+// IsEmpty checks if no proof was sent
+func (p StepUpProof) IsEmpty() bool {
+	return p.Token == "" && p.Code == ""
+}
+
+// StepUpEmailRequest - Email a code for a pending challenge instead of using the authenticator
+type StepUpEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ChangeEmailRequest - New email address; it has to be verified again afterwards
+type ChangeEmailRequest struct {
+	NewEmail string      `json:"new_email" binding:"required,email"`
+	StepUp   StepUpProof `json:"step_up"`
+}
+
+// ChangePhoneRequest - New phone number, also the login identifier
+type ChangePhoneRequest struct {
+	NewPhone string      `json:"new_phone" binding:"required"`
+	StepUp   StepUpProof `json:"step_up"`
+}
+
+// ==============================================
+// STEP-UP RESPONSE DTOs
+// ==============================================
+
+// StepUpChallengeResponse - Returned with 403 when an operation needs a fresh second factor
+// Repeat the same request with step_up.token and step_up.code within expires_in
+type StepUpChallengeResponse struct {
+	Token     string `json:"token"`
+	Action    string `json:"action"`
+	Method    string `json:"method"`          // "totp" or "email"
+	Email     string `json:"email,omitempty"` // Masked; set when a code was emailed
+	ExpiresIn int    `json:"expires_in"`      // seconds
+}
+
+// ChangeContactResponse - Result of an email or phone change
+type ChangeContactResponse struct {
+	Success  bool   `json:"success"`
+	Message  string `json:"message"`
+	NextStep string `json:"next_step,omitempty"` // "verify_email" after an email change
+}
@@ -13,19 +13,21 @@ type DepositRequest struct {
 
 // WithdrawRequest for withdrawing money
 type WithdrawRequest struct {
-	Amount         int64  `json:"amount" binding:"required,gt=0"`
-	Pin            string `json:"pin" binding:"required,len=4,numeric"`
-	IdempotencyKey string `json:"idempotency_key" binding:"required"`
-	Reference      string `json:"reference,omitempty"`
+	Amount         int64       `json:"amount" binding:"required,gt=0"`
+	Pin            string      `json:"pin" binding:"required,len=4,numeric"`
+	IdempotencyKey string      `json:"idempotency_key" binding:"required"`
+	Reference      string      `json:"reference,omitempty"`
+	StepUp         StepUpProof `json:"step_up"` // Needed for large withdrawals, see StepUpChallengeResponse
 }
 
 // TransferRequest for P2P transfers
 type TransferRequest struct {
-	ToIdentifier   string `json:"to_identifier" binding:"required"` // @username, phone, or account_number
-	Amount         int64  `json:"amount" binding:"required,gt=0"`
-	Pin            string `json:"pin" binding:"required,len=4,numeric"`
-	IdempotencyKey string `json:"idempotency_key" binding:"required"`
-	Description    string `json:"description,omitempty"`
+	ToIdentifier   string      `json:"to_identifier" binding:"required"` // @username, phone, or account_number
+	Amount         int64       `json:"amount" binding:"required,gt=0"`
+	Pin            string      `json:"pin" binding:"required,len=4,numeric"`
+	IdempotencyKey string      `json:"idempotency_key" binding:"required"`
+	Description    string      `json:"description,omitempty"`
+	StepUp         StepUpProof `json:"step_up"` // Needed for large transfers, see StepUpChallengeResponse
 }
 
 // ==============================================
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/api/middleware"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// ==============================================
+// SERVICE INTERFACE (for testing)
+// ==============================================
+
+type AdminService interface {
+	SearchUsers(ctx context.Context, actorID int, req dto.SearchUsersRequest) (*dto.AdminUserListResponse, error)
+	GetUser(ctx context.Context, actorID, userID int) (*dto.AdminUserDTO, error)
+	GetAccount(ctx context.Context, actorID int, accountNumber string) (*dto.AdminAccountDTO, error)
+	GetTransaction(ctx context.Context, actorID int, reference string) (*dto.AdminTransactionDTO, error)
+	LockUser(ctx context.Context, actorID int, actorRole string, userID int, req dto.LockUserRequest) (*dto.AdminUserDTO, error)
+	UnlockUser(ctx context.Context, actorID int, actorRole string, userID int, req dto.UnlockUserRequest) (*dto.AdminUserDTO, error)
+	SetRole(ctx context.Context, actorID int, actorRole string, userID int, req dto.SetRoleRequest) (*dto.AdminUserDTO, error)
+}
+
+// ==============================================
+// HANDLER
+// ==============================================
+
+type AdminHandler struct {
+	service AdminService
+}
+
+func NewAdminHandler(service AdminService) *AdminHandler {
+	return &AdminHandler{service: service}
+}
+
+// ==============================================
+// LOOKUP ENDPOINTS
+// ==============================================
+
+// SearchUsers handles GET /api/v1/admin/users?q=&page=&per_page=
+func (h *AdminHandler) SearchUsers(c *gin.Context) {
+	actorID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.SearchUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.SearchUsers(c.Request.Context(), actorID, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// GetUser handles GET /api/v1/admin/users/:id
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	actorID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.GetUser(c.Request.Context(), actorID, int(id))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// GetAccount handles GET /api/v1/admin/accounts/:account_number
+func (h *AdminHandler) GetAccount(c *gin.Context) {
+	actorID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	resp, err := h.service.GetAccount(c.Request.Context(), actorID, c.Param("account_number"))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// GetTransaction handles GET /api/v1/admin/transactions/:reference
+func (h *AdminHandler) GetTransaction(c *gin.Context) {
+	actorID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	resp, err := h.service.GetTransaction(c.Request.Context(), actorID, c.Param("reference"))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ==============================================
+// ACCOUNT CONTROL ENDPOINTS
+// ==============================================
+
+// LockUser handles POST /api/v1/admin/users/:id/lock
+func (h *AdminHandler) LockUser(c *gin.Context) {
+	actorID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	var req dto.LockUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.LockUser(c.Request.Context(), actorID, middleware.GetRole(c), int(id), req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// UnlockUser handles POST /api/v1/admin/users/:id/unlock
+func (h *AdminHandler) UnlockUser(c *gin.Context) {
+	actorID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	var req dto.UnlockUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.UnlockUser(c.Request.Context(), actorID, middleware.GetRole(c), int(id), req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// SetRole handles PUT /api/v1/admin/users/:id/role
+func (h *AdminHandler) SetRole(c *gin.Context) {
+	actorID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	var req dto.SetRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.SetRole(c.Request.Context(), actorID, middleware.GetRole(c), int(id), req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ==============================================
+// ROUTE REGISTRATION
+// ==============================================
+
+// RegisterAdminRoutes registers user, account and transaction tooling on the /api/v1/admin group
+func (h *AdminHandler) RegisterAdminRoutes(admin *gin.RouterGroup) {
+	admin.GET("/users", middleware.RequirePermission(auth.PermUsersRead), h.SearchUsers)
+	admin.GET("/users/:id", middleware.RequirePermission(auth.PermUsersRead), h.GetUser)
+	admin.GET("/accounts/:account_number", middleware.RequirePermission(auth.PermUsersRead), h.GetAccount)
+	admin.GET("/transactions/:reference", middleware.RequirePermission(auth.PermTransactionsRead), h.GetTransaction)
+	admin.POST("/users/:id/lock", middleware.RequirePermission(auth.PermUsersLock), h.LockUser)
+	admin.POST("/users/:id/unlock", middleware.RequirePermission(auth.PermUsersLock), h.UnlockUser)
+	admin.PUT("/users/:id/role", middleware.RequirePermission(auth.PermRolesManage), h.SetRole)
+}
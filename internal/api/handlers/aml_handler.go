@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/api/middleware"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// ==============================================
+// SERVICE INTERFACE (for testing)
+// ==============================================
+
+type AMLService interface {
+	RunScan(ctx context.Context) (*dto.AMLScanResponse, error)
+	ListCases(ctx context.Context, req dto.ListAMLCasesRequest) (*dto.AMLCaseListResponse, error)
+	GetCase(ctx context.Context, id int64) (*dto.AMLCaseDTO, error)
+	Assign(ctx context.Context, actorID int, id int64, req dto.AssignAMLCaseRequest) (*dto.AMLCaseDTO, error)
+	AddNote(ctx context.Context, actorID int, id int64, req dto.AddAMLCaseNoteRequest) (*dto.AMLCaseNoteDTO, error)
+	Escalate(ctx context.Context, actorID int, id int64, req dto.EscalateAMLCaseRequest) (*dto.AMLCaseDTO, error)
+	Close(ctx context.Context, actorID int, id int64, req dto.CloseAMLCaseRequest) (*dto.AMLCaseDTO, error)
+}
+
+// ==============================================
+// HANDLER
+// ==============================================
+
+type AMLHandler struct {
+	service AMLService
+}
+
+func NewAMLHandler(service AMLService) *AMLHandler {
+	return &AMLHandler{service: service}
+}
+
+// ==============================================
+// ADMIN ENDPOINTS
+// ==============================================
+
+// RunScan handles POST /api/v1/admin/aml/scans
+func (h *AMLHandler) RunScan(c *gin.Context) {
+	resp, err := h.service.RunScan(c.Request.Context())
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ListCases handles GET /api/v1/admin/aml/cases?status=&typology=&assigned_to=&page=&per_page=
+func (h *AMLHandler) ListCases(c *gin.Context) {
+	var req dto.ListAMLCasesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.ListCases(c.Request.Context(), req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// GetCase handles GET /api/v1/admin/aml/cases/:id
+func (h *AMLHandler) GetCase(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.GetCase(c.Request.Context(), id)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// Assign handles POST /api/v1/admin/aml/cases/:id/assign
+func (h *AMLHandler) Assign(c *gin.Context) {
+	var req dto.AssignAMLCaseRequest
+	handleAMLAction(c, &req, func(ctx context.Context, actorID int, id int64) (interface{}, error) {
+		return h.service.Assign(ctx, actorID, id, req)
+	})
+}
+
+// AddNote handles POST /api/v1/admin/aml/cases/:id/notes
+func (h *AMLHandler) AddNote(c *gin.Context) {
+	var req dto.AddAMLCaseNoteRequest
+	handleAMLAction(c, &req, func(ctx context.Context, actorID int, id int64) (interface{}, error) {
+		return h.service.AddNote(ctx, actorID, id, req)
+	})
+}
+
+// Escalate handles POST /api/v1/admin/aml/cases/:id/escalate
+func (h *AMLHandler) Escalate(c *gin.Context) {
+	var req dto.EscalateAMLCaseRequest
+	handleAMLAction(c, &req, func(ctx context.Context, actorID int, id int64) (interface{}, error) {
+		return h.service.Escalate(ctx, actorID, id, req)
+	})
+}
+
+// Close handles POST /api/v1/admin/aml/cases/:id/close
+func (h *AMLHandler) Close(c *gin.Context) {
+	var req dto.CloseAMLCaseRequest
+	handleAMLAction(c, &req, func(ctx context.Context, actorID int, id int64) (interface{}, error) {
+		return h.service.Close(ctx, actorID, id, req)
+	})
+}
+
+// handleAMLAction binds the body into req, then runs a case action as the caller
+func handleAMLAction(c *gin.Context, req interface{}, act func(ctx context.Context, actorID int, id int64) (interface{}, error)) {
+	actorID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	if err := c.ShouldBindJSON(req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := act(c.Request.Context(), actorID, id)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ==============================================
+// ROUTE REGISTRATION
+// ==============================================
+
+// RegisterAdminRoutes registers AML case management routes on the /api/v1/admin group
+func (h *AMLHandler) RegisterAdminRoutes(admin *gin.RouterGroup) {
+	aml := admin.Group("", middleware.RequirePermission(auth.PermAMLManage))
+	aml.POST("/aml/scans", h.RunScan)
+	aml.GET("/aml/cases", h.ListCases)
+	aml.GET("/aml/cases/:id", h.GetCase)
+	aml.POST("/aml/cases/:id/assign", h.Assign)
+	aml.POST("/aml/cases/:id/notes", h.AddNote)
+	aml.POST("/aml/cases/:id/escalate", h.Escalate)
+	aml.POST("/aml/cases/:id/close", h.Close)
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/api/middleware"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// ==============================================
+// SERVICE INTERFACE (for testing)
+// ==============================================
+
+type AuditService interface {
+	ListSecurityActivity(ctx context.Context, userID int, req dto.ListSecurityActivityRequest) (*dto.SecurityActivityResponse, error)
+	SearchLogs(ctx context.Context, actorID int, req dto.SearchAuditLogsRequest) (*dto.AuditLogListResponse, error)
+}
+
+// ==============================================
+// HANDLER
+// ==============================================
+
+type AuditHandler struct {
+	service AuditService
+}
+
+func NewAuditHandler(service AuditService) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+// ==============================================
+// ENDPOINTS
+// ==============================================
+
+// SecurityActivity handles GET /api/v1/security/activity?page=&per_page=
+func (h *AuditHandler) SecurityActivity(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.ListSecurityActivityRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.ListSecurityActivity(c.Request.Context(), userID, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// SearchLogs handles GET /api/v1/admin/audit-logs?actor_id=&user_id=&action=&entity_type=&entity_id=&from=&to=
+func (h *AuditHandler) SearchLogs(c *gin.Context) {
+	actorID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.SearchAuditLogsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.SearchLogs(c.Request.Context(), actorID, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ==============================================
+// ROUTE REGISTRATION
+// ==============================================
+
+// RegisterRoutes registers the caller's security history on the authenticated /api/v1 group
+func (h *AuditHandler) RegisterRoutes(public, protected *gin.RouterGroup) {
+	protected.GET("/security/activity", h.SecurityActivity)
+}
+
+// RegisterAdminRoutes registers audit search on the /api/v1/admin group
+func (h *AuditHandler) RegisterAdminRoutes(admin *gin.RouterGroup) {
+	admin.GET("/audit-logs", middleware.RequirePermission(auth.PermAuditRead), h.SearchLogs)
+}
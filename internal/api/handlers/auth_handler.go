@@ -1 +1,307 @@
-package handlers
\ No newline at end of file
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// ==============================================
+// SERVICE INTERFACE (for testing)
+// ==============================================
+
+type AuthService interface {
+	Signup(ctx context.Context, req dto.SignupRequest) (*dto.SignupResponse, error)
+	VerifyEmail(ctx context.Context, req dto.VerifyEmailRequest) (*dto.VerifyEmailResponse, error)
+	ResendOTP(ctx context.Context, req dto.ResendOTPRequest) (*dto.ResendOTPResponse, error)
+	CompleteOnboarding(ctx context.Context, userID int, req dto.CompleteOnboardingRequest) (*dto.CompleteOnboardingResponse, error)
+	Login(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, error)
+	ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest) (*dto.ForgotPasswordResponse, error)
+	ResetPassword(ctx context.Context, req dto.ResetPasswordRequest) (*dto.ResetPasswordResponse, error)
+	ChangePassword(ctx context.Context, userID int, req dto.ChangePasswordRequest) (*dto.ChangePasswordResponse, error)
+	SetPin(ctx context.Context, userID int, req dto.SetPinRequest) (*dto.SetPinResponse, error)
+	ChangeEmail(ctx context.Context, userID int, req dto.ChangeEmailRequest) (*dto.ChangeContactResponse, error)
+	ChangePhone(ctx context.Context, userID int, req dto.ChangePhoneRequest) (*dto.ChangeContactResponse, error)
+
+	CompleteMFALogin(ctx context.Context, req dto.MFALoginRequest) (*dto.LoginResponse, error)
+	SendLoginCodeByEmail(ctx context.Context, req dto.MFAEmailFallbackRequest) (*dto.MFACodeSentResponse, error)
+	GetMFAStatus(ctx context.Context, userID int) (*dto.MFAStatusResponse, error)
+	StartTOTPEnrollment(ctx context.Context, userID int) (*dto.TOTPEnrollmentResponse, error)
+	ConfirmTOTP(ctx context.Context, userID int, req dto.ConfirmTOTPRequest) (*dto.RecoveryCodesResponse, error)
+	EnableEmailMFA(ctx context.Context, userID int, req dto.EnableEmailMFARequest) (*dto.RecoveryCodesResponse, error)
+	SendMFACode(ctx context.Context, userID int) (*dto.MFACodeSentResponse, error)
+	DisableMFA(ctx context.Context, userID int, req dto.MFAReauthRequest) error
+	RegenerateRecoveryCodes(ctx context.Context, userID int, req dto.MFAReauthRequest) (*dto.RecoveryCodesResponse, error)
+}
+
+// ==============================================
+// HANDLER
+// ==============================================
+
+type AuthHandler struct {
+	service AuthService
+}
+
+func NewAuthHandler(service AuthService) *AuthHandler {
+	return &AuthHandler{service: service}
+}
+
+// ==============================================
+// PUBLIC ENDPOINTS
+// ==============================================
+
+// Signup handles POST /api/v1/auth/signup
+func (h *AuthHandler) Signup(c *gin.Context) {
+	var req dto.SignupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.Signup(c.Request.Context(), req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusCreated, resp)
+}
+
+// VerifyEmail handles POST /api/v1/auth/verify-email
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req dto.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.VerifyEmail(c.Request.Context(), req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ResendOTP handles POST /api/v1/auth/resend-otp
+func (h *AuthHandler) ResendOTP(c *gin.Context) {
+	var req dto.ResendOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.ResendOTP(c.Request.Context(), req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// Login handles POST /api/v1/auth/login
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req dto.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.Login(c.Request.Context(), req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ForgotPassword handles POST /api/v1/auth/forgot-password
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.ForgotPassword(c.Request.Context(), req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ResetPassword handles POST /api/v1/auth/reset-password
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.ResetPassword(c.Request.Context(), req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ==============================================
+// AUTHENTICATED ENDPOINTS
+// ==============================================
+
+// CompleteOnboarding handles POST /api/v1/auth/onboarding
+func (h *AuthHandler) CompleteOnboarding(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.CompleteOnboardingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.CompleteOnboarding(c.Request.Context(), userID, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ChangePassword handles POST /api/v1/auth/change-password
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.ChangePassword(c.Request.Context(), userID, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// SetPin handles POST /api/v1/auth/pin
+func (h *AuthHandler) SetPin(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.SetPinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.SetPin(c.Request.Context(), userID, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ChangeEmail handles POST /api/v1/auth/change-email
+func (h *AuthHandler) ChangeEmail(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.ChangeEmail(c.Request.Context(), userID, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ChangePhone handles POST /api/v1/auth/change-phone
+func (h *AuthHandler) ChangePhone(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.ChangePhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.ChangePhone(c.Request.Context(), userID, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ==============================================
+// ROUTE REGISTRATION
+// ==============================================
+
+// RegisterRoutes registers auth routes on the public and authenticated /api/v1 groups
+func (h *AuthHandler) RegisterRoutes(public, protected *gin.RouterGroup) {
+	authPublic := public.Group("/auth")
+	authPublic.POST("/signup", h.Signup)
+	authPublic.POST("/verify-email", h.VerifyEmail)
+	authPublic.POST("/resend-otp", h.ResendOTP)
+	authPublic.POST("/login", h.Login)
+	authPublic.POST("/login/mfa", h.CompleteMFALogin)
+	authPublic.POST("/login/mfa/email", h.SendLoginCodeByEmail)
+	authPublic.POST("/forgot-password", h.ForgotPassword)
+	authPublic.POST("/reset-password", h.ResetPassword)
+
+	authProtected := protected.Group("/auth")
+	authProtected.POST("/onboarding", h.CompleteOnboarding)
+	authProtected.POST("/change-password", h.ChangePassword)
+	authProtected.POST("/pin", h.SetPin)
+	authProtected.POST("/change-email", h.ChangeEmail)
+	authProtected.POST("/change-phone", h.ChangePhone)
+
+	mfa := authProtected.Group("/mfa")
+	mfa.GET("", h.GetMFAStatus)
+	mfa.POST("/totp", h.StartTOTPEnrollment)
+	mfa.POST("/totp/confirm", h.ConfirmTOTP)
+	mfa.POST("/email", h.EnableEmailMFA)
+	mfa.POST("/code", h.SendMFACode)
+	mfa.POST("/disable", h.DisableMFA)
+	mfa.POST("/recovery-codes", h.RegenerateRecoveryCodes)
+}
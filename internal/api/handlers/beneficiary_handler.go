@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// ==============================================
+// SERVICE INTERFACE (for testing)
+// ==============================================
+
+type BeneficiaryService interface {
+	NameEnquiry(ctx context.Context, userID int, req dto.NameEnquiryRequest) (*dto.NameEnquiryResponse, error)
+	Create(ctx context.Context, userID int, req dto.CreateBeneficiaryRequest) (*dto.BeneficiaryDTO, error)
+	List(ctx context.Context, userID int) (*dto.BeneficiaryListResponse, error)
+	Delete(ctx context.Context, userID int, id int64) error
+	Recipients(ctx context.Context, userID int, req dto.ListRecipientsRequest) (*dto.RecipientListResponse, error)
+}
+
+// ==============================================
+// HANDLER
+// ==============================================
+
+type BeneficiaryHandler struct {
+	service BeneficiaryService
+}
+
+func NewBeneficiaryHandler(service BeneficiaryService) *BeneficiaryHandler {
+	return &BeneficiaryHandler{service: service}
+}
+
+// ==============================================
+// ENDPOINTS
+// ==============================================
+
+// NameEnquiry handles GET /api/v1/name-enquiry?identifier=
+func (h *BeneficiaryHandler) NameEnquiry(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.NameEnquiryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.NameEnquiry(c.Request.Context(), userID, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// Create handles POST /api/v1/beneficiaries
+func (h *BeneficiaryHandler) Create(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.CreateBeneficiaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.Create(c.Request.Context(), userID, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusCreated, resp)
+}
+
+// List handles GET /api/v1/beneficiaries
+func (h *BeneficiaryHandler) List(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	resp, err := h.service.List(c.Request.Context(), userID)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// Delete handles DELETE /api/v1/beneficiaries/:id
+func (h *BeneficiaryHandler) Delete(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), userID, id); err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Recipients handles GET /api/v1/recipients?order=recent|frequent&limit=
+func (h *BeneficiaryHandler) Recipients(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.ListRecipientsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.Recipients(c.Request.Context(), userID, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ==============================================
+// ROUTE REGISTRATION
+// ==============================================
+
+// RegisterRoutes registers beneficiary routes on the authenticated /api/v1 group
+func (h *BeneficiaryHandler) RegisterRoutes(public, protected *gin.RouterGroup) {
+	protected.GET("/name-enquiry", h.NameEnquiry)
+	protected.GET("/beneficiaries", h.List)
+	protected.POST("/beneficiaries", h.Create)
+	protected.DELETE("/beneficiaries/:id", h.Delete)
+	protected.GET("/recipients", h.Recipients)
+}
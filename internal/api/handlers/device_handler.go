@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// ==============================================
+// SERVICE INTERFACE (for testing)
+// ==============================================
+
+type DeviceService interface {
+	ListDevices(ctx context.Context, userID int) (*dto.DeviceListResponse, error)
+	RenameDevice(ctx context.Context, userID int, deviceID int64, req dto.RenameDeviceRequest) (*dto.DeviceDTO, error)
+	RevokeDevice(ctx context.Context, userID int, deviceID int64) (*dto.RevokeDeviceResponse, error)
+}
+
+// ==============================================
+// HANDLER
+// ==============================================
+
+type DeviceHandler struct {
+	service DeviceService
+}
+
+func NewDeviceHandler(service DeviceService) *DeviceHandler {
+	return &DeviceHandler{service: service}
+}
+
+// ==============================================
+// ENDPOINTS
+// ==============================================
+
+// List handles GET /api/v1/devices
+func (h *DeviceHandler) List(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	resp, err := h.service.ListDevices(c.Request.Context(), userID)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// Rename handles PATCH /api/v1/devices/:id
+func (h *DeviceHandler) Rename(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	var req dto.RenameDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.RenameDevice(c.Request.Context(), userID, id, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// Revoke handles DELETE /api/v1/devices/:id
+func (h *DeviceHandler) Revoke(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.RevokeDevice(c.Request.Context(), userID, id)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ==============================================
+// ROUTE REGISTRATION
+// ==============================================
+
+// RegisterRoutes registers device management routes on the authenticated /api/v1 group
+func (h *DeviceHandler) RegisterRoutes(public, protected *gin.RouterGroup) {
+	protected.GET("/devices", h.List)
+	protected.PATCH("/devices/:id", h.Rename)
+	protected.DELETE("/devices/:id", h.Revoke)
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ==============================================
+// HANDLER
+// ==============================================
+
+// DocsHandler serves the OpenAPI document and a browsable UI for it
+type DocsHandler struct {
+	spec []byte // Rendered once at startup by openapi.JSON
+}
+
+func NewDocsHandler(spec []byte) *DocsHandler {
+	return &DocsHandler{spec: spec}
+}
+
+// OpenAPI handles GET /openapi.json
+func (h *DocsHandler) OpenAPI(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", h.spec)
+}
+
+// Docs handles GET /docs - Swagger UI reading /openapi.json
+func (h *DocsHandler) Docs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docsPage))
+}
+
+// RegisterRoutes registers the docs endpoints at the server root
+func (h *DocsHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/openapi.json", h.OpenAPI)
+	router.GET("/docs", h.Docs)
+}
+
+// docsPage loads a pinned Swagger UI release from a CDN; nothing is bundled into the binary
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>Debank API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5.17.14/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5.17.14/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui", persistAuthorization: true });
+    };
+  </script>
+</body>
+</html>
+`
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/Brownie44l1/debank/internal/api/problem"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/Brownie44l1/debank/internal/events"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/requestid"
+	"github.com/Brownie44l1/debank/internal/service"
+	"github.com/Brownie44l1/debank/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// ==============================================
+// ERROR RESPONSES
+// ==============================================
+// Every handler error is an RFC 7807 problem (see internal/api/problem).
+// Domain errors are mapped in serviceErrors below, the one place that
+// decides their status, code and user-facing title. An error missing from
+// it answers 500 and is logged with the request ID.
+
+// serviceErrors maps domain errors to problems; first match wins
+var serviceErrors = newServiceErrors()
+
+func newServiceErrors() *problem.Registry {
+	r := problem.NewRegistry()
+
+	// Validation errors (400 Bad Request)
+	r.Add(http.StatusBadRequest, models.ErrCodeInvalidAmount, "Invalid amount", service.ErrInvalidAmount, models.ErrInvalidAmount)
+	r.AddWithDetail(http.StatusBadRequest, models.ErrCodeAmountTooSmall, "Amount too small", service.ErrAmountTooSmall)
+	r.AddWithDetail(http.StatusBadRequest, models.ErrCodeAmountTooLarge, "Amount too large", service.ErrAmountTooLarge)
+	r.Add(http.StatusBadRequest, models.ErrCodeIdempotencyKeyMissing, "Idempotency key required", service.ErrInvalidIdempotencyKey)
+	r.Add(http.StatusBadRequest, models.ErrCodeInvalidIdempotencyKey, "Idempotency key uses a reserved prefix", service.ErrReservedIdempotencyKey)
+	r.Add(http.StatusBadRequest, models.ErrCodeSameAccount, "Cannot transfer to same account", service.ErrSameAccount, models.ErrSameAccount)
+	r.Add(http.StatusBadRequest, models.ErrCodeSelfPaymentRequest, "Cannot request money from yourself", service.ErrSelfPaymentRequest)
+	r.Add(http.StatusBadRequest, models.ErrCodeSystemAccountTransfer, "Cannot transfer to system account", models.ErrSystemAccountTransfer)
+	r.AddWithDetail(http.StatusBadRequest, models.ErrCodeInvalidQRCode, "Invalid QR code", service.ErrInvalidQRPayload)
+	r.Add(http.StatusBadRequest, models.ErrCodeUnsupportedQRCode, "Unsupported QR code", service.ErrUnsupportedQRScheme)
+	r.Add(http.StatusBadRequest, models.ErrCodeUnsupportedCurrency, "Unsupported currency", service.ErrUnsupportedCurrency)
+	r.Add(http.StatusBadRequest, models.ErrCodeKYCTierNotHigher, "Target tier must be higher than your current tier", service.ErrKYCTierNotHigher)
+	r.Add(http.StatusBadRequest, models.ErrCodeKYCAddressRequired, "Address is required for tier 3", service.ErrKYCAddressRequired)
+	r.Add(http.StatusBadRequest, models.ErrCodeKYCDocumentType, "Unsupported document type", service.ErrKYCDocumentType)
+	r.Add(http.StatusBadRequest, models.ErrCodeReasonRequired, "Reason is required", service.ErrKYCReviewReasonRequired)
+	r.Add(http.StatusBadRequest, models.ErrCodeReasonRequired, "Reason is required", service.ErrJournalReasonRequired)
+	r.AddWithDetail(http.StatusBadRequest, models.ErrCodeJournalUnbalanced, "Invalid journal legs",
+		service.ErrJournalTooFewLegs, service.ErrJournalZeroLeg, service.ErrJournalUnbalanced, service.ErrJournalDuplicateAccount)
+	r.Add(http.StatusBadRequest, models.ErrCodeCurrencyMismatch, "Accounts hold different currencies", service.ErrCurrencyMismatch)
+	r.Add(http.StatusBadRequest, models.ErrCodeAttachmentType, "Unsupported attachment type", service.ErrJournalAttachmentType)
+	r.AddWithDetail(http.StatusBadRequest, models.ErrCodeInvalidRuleParams, "Invalid rule parameters", service.ErrRiskRuleParams)
+	r.Add(http.StatusBadRequest, models.ErrCodeInvalidPhone, "Invalid phone number", models.ErrInvalidPhone)
+	r.Add(http.StatusBadRequest, models.ErrCodeInvalidEmail, "Invalid email address", models.ErrInvalidEmail)
+	r.Add(http.StatusBadRequest, models.ErrCodeWeakPassword, "Password too weak", models.ErrWeakPassword)
+	r.Add(http.StatusBadRequest, models.ErrCodeInvalidPin, "Invalid PIN", models.ErrInvalidPin)
+	r.Add(http.StatusBadRequest, models.ErrCodeContactUnchanged, "Nothing to change", service.ErrContactUnchanged)
+	r.Add(http.StatusBadRequest, models.ErrCodeStepUpMismatch, "Verification was for a different operation", service.ErrStepUpMismatch)
+	r.Add(http.StatusBadRequest, models.ErrCodeInvalidDeviceName, "Invalid device name", service.ErrInvalidDeviceName)
+	r.Add(http.StatusBadRequest, models.ErrCodeInvalidRole, "Unknown role", service.ErrInvalidRole)
+	r.Add(http.StatusBadRequest, models.ErrCodeLockInPast, "Lock must end in the future", service.ErrLockInPast)
+	r.Add(http.StatusRequestEntityTooLarge, models.ErrCodeDocumentTooLarge, "Document too large", service.ErrKYCDocumentTooLarge)
+	r.Add(http.StatusRequestEntityTooLarge, models.ErrCodeDocumentTooLarge, "Attachment too large", service.ErrJournalAttachmentTooLarge)
+
+	// Authentication errors (401/403)
+	r.Add(http.StatusUnauthorized, models.ErrCodeInvalidCredentials, "Invalid credentials", models.ErrInvalidCredentials)
+	r.Add(http.StatusForbidden, models.ErrCodeAccountLocked, "Account is locked", models.ErrAccountLocked)
+	r.Add(http.StatusForbidden, models.ErrCodeAccountInactive, "Account is inactive", models.ErrAccountInactive)
+	r.Add(http.StatusForbidden, models.ErrCodeEmailNotVerified, "Email not verified", models.ErrEmailNotVerified)
+	r.Add(http.StatusBadRequest, models.ErrCodeOTPExpired, "Invalid or expired code", models.ErrOTPExpired)
+	r.Add(http.StatusBadRequest, models.ErrCodeOTPInvalid, "Invalid or expired code", models.ErrOTPInvalid, models.ErrOTPAlreadyUsed, models.ErrOTPNotFound)
+	r.Add(http.StatusTooManyRequests, models.ErrCodeOTPMaxAttempts, "Too many attempts, request a new code", models.ErrOTPMaxAttempts)
+	r.Add(http.StatusUnauthorized, models.ErrCodePasswordIncorrect, "Password is incorrect", service.ErrPasswordIncorrect)
+	r.Add(http.StatusUnauthorized, models.ErrCodeMFACodeInvalid, "Invalid two-factor code", service.ErrMFACodeInvalid)
+	r.Add(http.StatusUnauthorized, models.ErrCodeMFAChallengeExpired, "Sign-in attempt expired, please log in again", service.ErrMFAChallengeInvalid)
+	r.Add(http.StatusUnauthorized, models.ErrCodeTokenExpired, "Token expired", models.ErrTokenExpired)
+	r.Add(http.StatusUnauthorized, models.ErrCodeInvalidToken, "Invalid token", models.ErrInvalidToken, auth.ErrUnknownKey)
+	r.Add(http.StatusUnauthorized, models.ErrCodeSessionExpired, "Session has ended, please sign in again",
+		models.ErrSessionExpired, models.ErrSessionRevoked, models.ErrSessionNotFound)
+	r.Add(http.StatusNotFound, models.ErrCodeUserNotFound, "User not found", models.ErrUserNotFound)
+	r.Add(http.StatusUnauthorized, models.ErrCodeIncorrectPin, "Incorrect PIN", models.ErrIncorrectPin)
+	r.Add(http.StatusForbidden, models.ErrCodePinNotSet, "Transaction PIN not set", models.ErrPinNotSet)
+	r.Add(http.StatusForbidden, models.ErrCodeNotPayer, "Not the payer of this request", service.ErrNotPaymentRequestPayer)
+	r.Add(http.StatusForbidden, models.ErrCodeSelfReview, "Cannot review your own submission", service.ErrKYCSelfReview)
+	r.Add(http.StatusForbidden, models.ErrCodeSelfReview, "Cannot review your own journal entry", service.ErrJournalSelfApproval)
+	r.Add(http.StatusForbidden, models.ErrCodeNotMaker, "Only the submitter can attach files", service.ErrJournalNotMaker)
+	r.Add(http.StatusForbidden, models.ErrCodeSelfAction, "Cannot perform this action on your own account", service.ErrAdminSelfAction)
+	r.Add(http.StatusForbidden, models.ErrCodeInsufficientRole, "Your role does not allow acting on this user", service.ErrInsufficientRole)
+	r.AddWithDetail(http.StatusForbidden, models.ErrCodeStepUpRequired, "Additional verification required", service.ErrStepUpRequired)
+	r.Add(http.StatusForbidden, models.ErrCodeStepUpExpired, "Verification expired, please try again", service.ErrStepUpInvalid)
+	r.Add(http.StatusUnauthorized, models.ErrCodeStepUpCodeInvalid, "Invalid verification code", service.ErrStepUpCodeInvalid)
+	r.AddWithDetail(http.StatusForbidden, models.ErrCodeRiskChallenge, "Additional verification required", service.ErrRiskChallengeRequired)
+	r.AddWithDetail(http.StatusForbidden, models.ErrCodeTransactionDeclined, "Transaction declined", service.ErrRiskBlocked)
+
+	// Not found errors (404 Not Found)
+	r.Add(http.StatusNotFound, models.ErrCodeAccountNotFound, "Account not found", service.ErrAccountNotFound, models.ErrAccountNotFound)
+	r.Add(http.StatusNotFound, models.ErrCodeRecipientNotFound, "Recipient not found", service.ErrRecipientNotFound)
+	r.Add(http.StatusNotFound, models.ErrCodePayerNotFound, "Payer not found", service.ErrPayerNotFound)
+	r.Add(http.StatusNotFound, models.ErrCodeNotFound, "Payment request not found", service.ErrPaymentRequestNotFound)
+	r.Add(http.StatusNotFound, models.ErrCodeNotFound, "Beneficiary not found", service.ErrBeneficiaryNotFound)
+	r.Add(http.StatusNotFound, models.ErrCodeNotFound, "KYC submission not found", service.ErrKYCSubmissionNotFound)
+	r.Add(http.StatusNotFound, models.ErrCodeNotFound, "Document not found", service.ErrKYCDocumentNotFound, storage.ErrObjectNotFound)
+	r.Add(http.StatusNotFound, models.ErrCodeNotFound, "AML case not found", service.ErrAMLCaseNotFound)
+	r.Add(http.StatusNotFound, models.ErrCodeAssigneeNotFound, "Assignee not found", service.ErrAMLAssigneeNotFound)
+	r.Add(http.StatusNotFound, models.ErrCodeNotFound, "Risk rule not found", service.ErrRiskRuleNotFound)
+	r.Add(http.StatusNotFound, models.ErrCodeNotFound, "Risk decision not found", service.ErrRiskDecisionNotFound)
+	r.Add(http.StatusNotFound, models.ErrCodeNotFound, "Screening hit not found", service.ErrScreeningHitNotFound)
+	r.Add(http.StatusNotFound, models.ErrCodeNotFound, "Device not found", service.ErrDeviceNotFound)
+	r.Add(http.StatusNotFound, models.ErrCodeNotFound, "Transaction not found", service.ErrTransactionNotFound, models.ErrTransactionNotFound)
+	r.Add(http.StatusNotFound, models.ErrCodeNotFound, "Journal entry not found", service.ErrJournalEntryNotFound)
+	r.Add(http.StatusNotFound, models.ErrCodeNotFound, "Attachment not found", service.ErrJournalAttachmentNotFound)
+
+	// Conflict errors (409 Conflict)
+	r.Add(http.StatusConflict, models.ErrCodePhoneExists, "Phone number already registered", models.ErrPhoneAlreadyExists)
+	r.Add(http.StatusConflict, models.ErrCodeEmailExists, "Email already registered", models.ErrEmailAlreadyExists)
+	r.Add(http.StatusConflict, models.ErrCodeUsernameTaken, "Username already taken", models.ErrUsernameAlreadyExists)
+	r.Add(http.StatusConflict, models.ErrCodeUserExists, "User already exists", models.ErrUserAlreadyExists)
+	r.Add(http.StatusConflict, models.ErrCodeIdempotencyConflict, "Idempotency key already used", service.ErrIdempotencyConflict)
+	r.Add(http.StatusConflict, models.ErrCodeDuplicateTransaction, "Transaction already exists", models.ErrTransactionAlreadyExists)
+	r.Add(http.StatusConflict, models.ErrCodePaymentRequestClosed, "Payment request is no longer open", service.ErrPaymentRequestNotOpen)
+	r.Add(http.StatusConflict, models.ErrCodeBeneficiaryExists, "Beneficiary already saved", service.ErrBeneficiaryExists)
+	r.Add(http.StatusConflict, models.ErrCodeKYCPending, "A KYC submission is already pending", service.ErrKYCSubmissionPending)
+	r.Add(http.StatusConflict, models.ErrCodeAlreadyReviewed, "KYC submission has already been reviewed", service.ErrKYCSubmissionNotPending)
+	r.Add(http.StatusConflict, models.ErrCodeAMLCaseClosed, "AML case is closed", service.ErrAMLCaseClosed)
+	r.Add(http.StatusConflict, models.ErrCodeAlreadyReviewed, "Screening hit has already been reviewed", service.ErrScreeningHitReviewed)
+	r.Add(http.StatusConflict, models.ErrCodeAlreadyReviewed, "Journal entry has already been reviewed", service.ErrJournalEntryNotPending)
+	r.Add(http.StatusConflict, models.ErrCodeScreeningHold, "On hold pending screening review", service.ErrScreeningHold)
+	r.Add(http.StatusConflict, models.ErrCodeMFAAlreadyEnabled, "Two-factor authentication is already enabled", service.ErrMFAAlreadyEnabled)
+	r.Add(http.StatusConflict, models.ErrCodeMFANotEnabled, "Two-factor authentication is not enabled", service.ErrMFANotEnabled)
+	r.Add(http.StatusConflict, models.ErrCodeMFAEnrollmentMissing, "Start authenticator setup first", service.ErrMFAEnrollmentNotStarted)
+
+	// Rate limiting (429 Too Many Requests)
+	r.Add(http.StatusTooManyRequests, models.ErrCodeOTPResendCooldown, "Please wait before requesting another code", models.ErrOTPResendCooldown)
+
+	// Business logic errors (422 Unprocessable Entity)
+	r.Add(http.StatusUnprocessableEntity, models.ErrCodeInsufficientBalance, "Insufficient balance", service.ErrInsufficientBalance, models.ErrInsufficientBalance)
+	r.Add(http.StatusUnprocessableEntity, models.ErrCodeAccountFrozen, "Account is frozen", service.ErrAccountFrozen, models.ErrAccountFrozen)
+	r.Add(http.StatusUnprocessableEntity, models.ErrCodeTierSingleLimit, "Single transaction limit exceeded for your KYC tier", service.ErrTierSingleLimitExceeded)
+	r.Add(http.StatusUnprocessableEntity, models.ErrCodeTierDailyLimit, "Daily limit exceeded for your KYC tier", service.ErrTierDailyLimitExceeded)
+	r.Add(http.StatusUnprocessableEntity, models.ErrCodeTierMonthlyLimit, "Monthly limit exceeded for your KYC tier", service.ErrTierMonthlyLimitExceeded)
+	r.Add(http.StatusUnprocessableEntity, models.ErrCodeTierMaxBalance, "Maximum balance exceeded for your KYC tier", service.ErrTierMaxBalanceExceeded)
+	r.Add(http.StatusUnprocessableEntity, models.ErrCodeRecipientLimit, "Recipient cannot receive this amount", service.ErrRecipientLimitExceeded)
+	r.Add(http.StatusUnprocessableEntity, models.ErrCodeKYCDocumentsMissing, "Tier 3 requires uploaded documents", service.ErrKYCDocumentsRequired)
+	r.Add(http.StatusUnprocessableEntity, models.ErrCodeAttachmentsMissing, "Journal entry needs an attachment", service.ErrJournalAttachmentsRequired)
+	r.Add(http.StatusUnprocessableEntity, models.ErrCodePaymentRequestExpired, "Payment request has expired", service.ErrPaymentRequestExpired)
+	r.Add(http.StatusUnprocessableEntity, models.ErrCodeRecipientOnHold, "Recipient is on hold pending review", service.ErrBeneficiaryHeld)
+
+	// System errors (500 Internal Server Error); anything unregistered also lands here
+	r.Add(http.StatusInternalServerError, models.ErrCodeWatchlistReload, "Failed to reload watchlists", service.ErrWatchlistReload)
+	r.Add(http.StatusServiceUnavailable, models.ErrCodeStreamUnavailable, "Event stream unavailable, please retry", events.ErrUnavailable)
+
+	return r
+}
+
+// ServiceProblem returns the problem registered for err, and false when err is not registered
+// The gRPC API (internal/rpc) maps errors through it too, so both report the same codes.
+func ServiceProblem(err error) (problem.Problem, bool) {
+	p, known := serviceErrors.Lookup(err)
+
+	// Hand back the challenge so the client can retry with a proof
+	var stepUp *service.StepUpRequiredError
+	if errors.As(err, &stepUp) {
+		p = p.With("step_up", stepUp.Challenge)
+	}
+	return p, known
+}
+
+// respondServiceError answers with the problem registered for err
+func respondServiceError(c *gin.Context, err error) {
+	p, known := ServiceProblem(err)
+	if !known || p.Status >= http.StatusInternalServerError {
+		log.Printf("[API] %s %s failed - RequestID: %s: %v",
+			c.Request.Method, c.FullPath(), requestid.FromContext(c.Request.Context()), err)
+	}
+
+	problem.Abort(c, p)
+}
+
+// respondBindError answers 400 with the fields that failed binding or validation
+func respondBindError(c *gin.Context, err error) {
+	problem.Abort(c, problem.FromBindError(err))
+}
+
+// respondFieldError answers 400 for a single invalid field
+func respondFieldError(c *gin.Context, field, code, message string) {
+	respondBindError(c, &problem.FieldError{Field: field, Code: code, Message: message})
+}
+
+// respondUnauthorized answers 401 for a request that reached a handler without an authenticated user
+func respondUnauthorized(c *gin.Context) {
+	problem.Abort(c, problem.New(http.StatusUnauthorized, models.ErrCodeUnauthorized, "Unauthorized").
+		WithDetail("authentication required"))
+}
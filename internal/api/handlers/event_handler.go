@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/events"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// ==============================================
+// SERVICE INTERFACE (for testing)
+// ==============================================
+
+// EventBroker opens streams of a user's account events (implemented by events.Broker)
+type EventBroker interface {
+	Subscribe(ctx context.Context, userID int, lastEventID int64) (*events.Subscription, error)
+}
+
+// ==============================================
+// HANDLER (HTTP Layer ONLY)
+// ==============================================
+
+const (
+	heartbeatInterval = 25 * time.Second // Keeps idle streams open through proxies that cut silent connections
+	sseRetry          = 3 * time.Second  // How long an EventSource waits before reconnecting
+	heartbeatEvent    = "heartbeat"      // WebSocket keep-alive message type
+	maxClientMessage  = 4 << 10          // Clients have nothing to send; anything bigger closes the socket
+)
+
+type EventHandler struct {
+	broker EventBroker
+}
+
+func NewEventHandler(broker EventBroker) *EventHandler {
+	return &EventHandler{broker: broker}
+}
+
+// ==============================================
+// ENDPOINTS
+// ==============================================
+
+// Stream handles GET /api/v1/events - Server-Sent Events
+// Each event's id is its event ID, so a reconnecting EventSource resumes via Last-Event-ID.
+func (h *EventHandler) Stream(c *gin.Context) {
+	sub, ok := h.subscribe(c, c.Request.Context())
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	w := c.Writer
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Stop nginx buffering the stream
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetry.Milliseconds())
+	w.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			io.WriteString(w, ": heartbeat\n\n")
+		case e, open := <-sub.Events():
+			if !open {
+				return // The client reconnects and resumes from the last ID it got
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+		}
+		w.Flush()
+	}
+}
+
+// WebSocket handles GET /api/v1/events/ws - the same events as JSON text frames
+// Resume with ?last_event_id=; a heartbeat message is sent when the stream is idle.
+func (h *EventHandler) WebSocket(c *gin.Context) {
+	// After the upgrade the request context no longer ends with the connection, so the read loop cancels this one
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	// Subscribing before the upgrade lets a refusal still be a problem response
+	sub, ok := h.subscribe(c, ctx)
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	server := websocket.Server{
+		// Clients authenticate with a Bearer token, not cookies, so any origin may connect
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			ws.MaxPayloadBytes = maxClientMessage
+			go func() {
+				defer cancel()
+				var msg string
+				for websocket.Message.Receive(ws, &msg) == nil {
+				}
+			}()
+			pumpWebSocket(ctx, ws, sub)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+func pumpWebSocket(ctx context.Context, ws *websocket.Conn, sub *events.Subscription) {
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		var msg dto.AccountEvent
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			msg = dto.AccountEvent{Type: heartbeatEvent}
+		case e, open := <-sub.Events():
+			if !open {
+				return
+			}
+			msg = e
+		}
+		if err := websocket.JSON.Send(ws, msg); err != nil {
+			return
+		}
+	}
+}
+
+// subscribe opens the caller's stream, answering with a problem when it cannot
+func (h *EventHandler) subscribe(c *gin.Context, ctx context.Context) (*events.Subscription, bool) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return nil, false
+	}
+
+	var req dto.EventStreamRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return nil, false
+	}
+	if header := c.GetHeader("Last-Event-ID"); header != "" {
+		id, err := strconv.ParseInt(header, 10, 64)
+		if err != nil || id < 0 {
+			respondFieldError(c, "Last-Event-ID", "numeric", "must be a whole number")
+			return nil, false
+		}
+		req.LastEventID = id
+	}
+
+	sub, err := h.broker.Subscribe(ctx, userID, req.LastEventID)
+	if err != nil {
+		respondServiceError(c, err)
+		return nil, false
+	}
+	return sub, true
+}
+
+// RegisterRoutes registers the event streams
+func (h *EventHandler) RegisterRoutes(public, protected *gin.RouterGroup) {
+	protected.GET("/events", h.Stream)
+	protected.GET("/events/ws", h.WebSocket)
+}
@@ -3,43 +3,55 @@ package handlers
 import (
 	"net/http"
 
+	"github.com/Brownie44l1/debank/internal/health"
 	"github.com/gin-gonic/gin"
 )
 
-type HealthHandler struct{}
+type HealthHandler struct {
+	live  *health.Registry
+	ready *health.Registry
+	token string // With a token set, only callers presenting it see check details and errors
+}
 
-func NewHealthHandler() *HealthHandler {
-	return &HealthHandler{}
+func NewHealthHandler(live, ready *health.Registry, token string) *HealthHandler {
+	return &HealthHandler{live: live, ready: ready, token: token}
 }
 
-// Health handles GET /health - returns API health status
-func (h *HealthHandler) Health(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "healthy",
-		"service": "debank-wallet-api",
-		"version": "v1.0.0",
-	})
+// Livez handles GET /livez - whether the process itself works; failing means restart it
+func (h *HealthHandler) Livez(c *gin.Context) {
+	h.respond(c, h.live.Run(c.Request.Context()))
 }
 
-// Readiness handles GET /ready - can be extended to check dependencies
-func (h *HealthHandler) Readiness(c *gin.Context) {
-	// In future, you can add checks for:
-	// - Database connectivity
-	// - External service availability
-	// - Queue health, etc.
-	
-	c.JSON(http.StatusOK, gin.H{
-		"status": "ready",
-		"checks": gin.H{
-			"api": "ok",
-			// "database": "ok",
-			// "cache": "ok",
-		},
-	})
+// Readyz handles GET /readyz - whether this instance can take traffic
+// Non-critical failures (worker, email, pool pressure) report "degraded" but still answer 200
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	h.respond(c, h.ready.Run(c.Request.Context()))
+}
+
+func (h *HealthHandler) respond(c *gin.Context, report health.Report) {
+	status := http.StatusOK
+	if !report.Healthy() {
+		status = http.StatusServiceUnavailable
+	}
+
+	// Errors can name hosts and tables; anonymous callers get the statuses only
+	if h.token != "" && !bearerTokenMatches(c, h.token) {
+		for i := range report.Checks {
+			report.Checks[i].Detail = ""
+			report.Checks[i].Error = ""
+		}
+	}
+
+	c.JSON(status, report)
 }
 
 // RegisterRoutes registers health check routes
 func (h *HealthHandler) RegisterRoutes(router *gin.Engine) {
-	router.GET("/health", h.Health)
-	router.GET("/ready", h.Readiness)
-}
\ No newline at end of file
+	router.GET("/livez", h.Livez)
+	router.GET("/readyz", h.Readyz)
+
+	// Older probe paths
+	router.GET("/health", h.Livez)
+	router.GET("/ready", h.Readyz)
+	router.GET("/api/v1/health", h.Livez)
+}
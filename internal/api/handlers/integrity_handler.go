@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/api/middleware"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// ==============================================
+// SERVICE INTERFACE (for testing)
+// ==============================================
+
+type IntegrityService interface {
+	Verify(ctx context.Context, actorID int, chain string) (*dto.ChainVerificationResponse, error)
+	ListCheckpoints(ctx context.Context, req dto.ListCheckpointsRequest) (*dto.ChainCheckpointListResponse, error)
+}
+
+// ==============================================
+// HANDLER
+// ==============================================
+
+type IntegrityHandler struct {
+	service IntegrityService
+}
+
+func NewIntegrityHandler(service IntegrityService) *IntegrityHandler {
+	return &IntegrityHandler{service: service}
+}
+
+// ==============================================
+// ENDPOINTS
+// ==============================================
+
+// Verify handles GET /api/v1/admin/integrity/verify?chain=transactions|audit_logs
+// Always 200; the body says whether the chain is intact and where it breaks
+func (h *IntegrityHandler) Verify(c *gin.Context) {
+	actorID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.VerifyChainRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.Verify(c.Request.Context(), actorID, req.Chain)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ListCheckpoints handles GET /api/v1/admin/integrity/checkpoints?chain=&page=&per_page=
+func (h *IntegrityHandler) ListCheckpoints(c *gin.Context) {
+	var req dto.ListCheckpointsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.ListCheckpoints(c.Request.Context(), req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ==============================================
+// ROUTE REGISTRATION
+// ==============================================
+
+// RegisterAdminRoutes registers hash chain verification on the /api/v1/admin group
+func (h *IntegrityHandler) RegisterAdminRoutes(admin *gin.RouterGroup) {
+	integrity := admin.Group("/integrity", middleware.RequirePermission(auth.PermAuditRead))
+	integrity.GET("/verify", h.Verify)
+	integrity.GET("/checkpoints", h.ListCheckpoints)
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/api/middleware"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// ==============================================
+// SERVICE INTERFACE (for testing)
+// ==============================================
+
+type JournalService interface {
+	Submit(ctx context.Context, makerID int, req dto.SubmitJournalEntryRequest) (*dto.JournalEntryDTO, error)
+	UploadAttachment(ctx context.Context, userID int, entryID int64, fileName string, r io.Reader) (*dto.JournalAttachmentDTO, error)
+	List(ctx context.Context, req dto.ListJournalEntriesRequest) (*dto.JournalEntryListResponse, error)
+	Get(ctx context.Context, id int64) (*dto.JournalEntryDTO, error)
+	Approve(ctx context.Context, checkerID int, id int64, req dto.ReviewJournalEntryRequest) (*dto.JournalEntryDTO, error)
+	Reject(ctx context.Context, checkerID int, id int64, req dto.ReviewJournalEntryRequest) (*dto.JournalEntryDTO, error)
+	OpenAttachment(ctx context.Context, id int64) (io.ReadCloser, *dto.JournalAttachmentDTO, error)
+}
+
+// ==============================================
+// HANDLER
+// ==============================================
+
+type JournalHandler struct {
+	service JournalService
+}
+
+func NewJournalHandler(service JournalService) *JournalHandler {
+	return &JournalHandler{service: service}
+}
+
+// ==============================================
+// ADMIN ENDPOINTS
+// ==============================================
+
+// Submit handles POST /api/v1/admin/journal-entries
+func (h *JournalHandler) Submit(c *gin.Context) {
+	makerID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.SubmitJournalEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.Submit(c.Request.Context(), makerID, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusCreated, resp)
+}
+
+// UploadAttachment handles POST /api/v1/admin/journal-entries/:id/attachments (multipart: file)
+func (h *JournalHandler) UploadAttachment(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		respondFieldError(c, "file", "required", "is required")
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		respondFieldError(c, "file", "unreadable", "could not be read")
+		return
+	}
+	defer file.Close()
+
+	resp, err := h.service.UploadAttachment(c.Request.Context(), userID, id, fileHeader.Filename, file)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusCreated, resp)
+}
+
+// List handles GET /api/v1/admin/journal-entries?status=&page=&per_page=
+func (h *JournalHandler) List(c *gin.Context) {
+	var req dto.ListJournalEntriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.List(c.Request.Context(), req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// Get handles GET /api/v1/admin/journal-entries/:id
+func (h *JournalHandler) Get(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// Approve handles POST /api/v1/admin/journal-entries/:id/approve
+func (h *JournalHandler) Approve(c *gin.Context) {
+	h.review(c, h.service.Approve)
+}
+
+// Reject handles POST /api/v1/admin/journal-entries/:id/reject
+func (h *JournalHandler) Reject(c *gin.Context) {
+	h.review(c, h.service.Reject)
+}
+
+func (h *JournalHandler) review(c *gin.Context, decide func(context.Context, int, int64, dto.ReviewJournalEntryRequest) (*dto.JournalEntryDTO, error)) {
+	checkerID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	var req dto.ReviewJournalEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := decide(c.Request.Context(), checkerID, id, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// DownloadAttachment handles GET /api/v1/admin/journal-attachments/:id
+func (h *JournalHandler) DownloadAttachment(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	rc, attachment, err := h.service.OpenAttachment(c.Request.Context(), id)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	defer rc.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.SizeBytes, attachment.ContentType, rc, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+		"Cache-Control":       "no-store",
+	})
+}
+
+// ==============================================
+// ROUTE REGISTRATION
+// ==============================================
+
+// RegisterAdminRoutes registers journal entries on the /api/v1/admin group
+func (h *JournalHandler) RegisterAdminRoutes(admin *gin.RouterGroup) {
+	journal := admin.Group("", middleware.RequirePermission(auth.PermLedgerManage))
+	journal.POST("/journal-entries", h.Submit)
+	journal.GET("/journal-entries", h.List)
+	journal.GET("/journal-entries/:id", h.Get)
+	journal.POST("/journal-entries/:id/attachments", h.UploadAttachment)
+	journal.POST("/journal-entries/:id/approve", h.Approve)
+	journal.POST("/journal-entries/:id/reject", h.Reject)
+	journal.GET("/journal-attachments/:id", h.DownloadAttachment)
+}
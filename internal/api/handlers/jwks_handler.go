@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// ==============================================
+// SERVICE INTERFACE (for testing)
+// ==============================================
+
+type KeySet interface {
+	JWKS() auth.JWKS
+}
+
+// ==============================================
+// HANDLER
+// ==============================================
+
+// JWKSHandler publishes the public keys access tokens are signed with,
+// so other services can verify them without sharing a secret
+type JWKSHandler struct {
+	keys KeySet
+}
+
+func NewJWKSHandler(keys KeySet) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+// JWKS handles GET /.well-known/jwks.json
+func (h *JWKSHandler) JWKS(c *gin.Context) {
+	// Short enough that verifiers see a staged key well before it starts signing
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
+
+// RegisterRoutes registers the JWKS endpoint at the server root
+func (h *JWKSHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/.well-known/jwks.json", h.JWKS)
+}
@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/api/middleware"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// ==============================================
+// SERVICE INTERFACE (for testing)
+// ==============================================
+
+type KYCService interface {
+	ListTiers(ctx context.Context) (*dto.KYCTiersResponse, error)
+	GetLimits(ctx context.Context, userID int) (*dto.KYCLimitsResponse, error)
+	Submit(ctx context.Context, userID int, req dto.SubmitKYCRequest) (*dto.KYCSubmissionDTO, error)
+	UploadDocument(ctx context.Context, userID int, submissionID int64, documentType, fileName string, r io.Reader) (*dto.KYCDocumentDTO, error)
+	ListMySubmissions(ctx context.Context, userID int) (*dto.KYCSubmissionListResponse, error)
+	ListQueue(ctx context.Context, req dto.ListKYCSubmissionsRequest) (*dto.KYCSubmissionListResponse, error)
+	GetForReview(ctx context.Context, id int64) (*dto.KYCSubmissionDTO, error)
+	Approve(ctx context.Context, reviewerID int, id int64, req dto.ReviewKYCSubmissionRequest) (*dto.KYCSubmissionDTO, error)
+	Reject(ctx context.Context, reviewerID int, id int64, req dto.ReviewKYCSubmissionRequest) (*dto.KYCSubmissionDTO, error)
+	OpenDocument(ctx context.Context, id int64) (io.ReadCloser, *dto.KYCDocumentDTO, error)
+}
+
+// ==============================================
+// HANDLER
+// ==============================================
+
+type KYCHandler struct {
+	service KYCService
+}
+
+func NewKYCHandler(service KYCService) *KYCHandler {
+	return &KYCHandler{service: service}
+}
+
+// ==============================================
+// ENDPOINTS
+// ==============================================
+
+// ListTiers handles GET /api/v1/kyc/tiers
+func (h *KYCHandler) ListTiers(c *gin.Context) {
+	resp, err := h.service.ListTiers(c.Request.Context())
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// GetLimits handles GET /api/v1/kyc/limits
+func (h *KYCHandler) GetLimits(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	resp, err := h.service.GetLimits(c.Request.Context(), userID)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// Submit handles POST /api/v1/kyc/submissions
+func (h *KYCHandler) Submit(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.SubmitKYCRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.Submit(c.Request.Context(), userID, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusCreated, resp)
+}
+
+// ListMySubmissions handles GET /api/v1/kyc/submissions
+func (h *KYCHandler) ListMySubmissions(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	resp, err := h.service.ListMySubmissions(c.Request.Context(), userID)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// UploadDocument handles POST /api/v1/kyc/submissions/:id/documents (multipart: document_type, file)
+func (h *KYCHandler) UploadDocument(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	var req dto.UploadKYCDocumentRequest
+	if err := c.ShouldBind(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		respondFieldError(c, "file", "required", "is required")
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		respondFieldError(c, "file", "unreadable", "could not be read")
+		return
+	}
+	defer file.Close()
+
+	resp, err := h.service.UploadDocument(c.Request.Context(), userID, id, req.DocumentType, fileHeader.Filename, file)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusCreated, resp)
+}
+
+// ==============================================
+// ADMIN ENDPOINTS
+// ==============================================
+
+// ListQueue handles GET /api/v1/admin/kyc/submissions?status=&page=&per_page=
+func (h *KYCHandler) ListQueue(c *gin.Context) {
+	var req dto.ListKYCSubmissionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.ListQueue(c.Request.Context(), req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// GetForReview handles GET /api/v1/admin/kyc/submissions/:id
+func (h *KYCHandler) GetForReview(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.GetForReview(c.Request.Context(), id)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// Approve handles POST /api/v1/admin/kyc/submissions/:id/approve
+func (h *KYCHandler) Approve(c *gin.Context) {
+	h.review(c, h.service.Approve)
+}
+
+// Reject handles POST /api/v1/admin/kyc/submissions/:id/reject
+func (h *KYCHandler) Reject(c *gin.Context) {
+	h.review(c, h.service.Reject)
+}
+
+func (h *KYCHandler) review(c *gin.Context, decide func(context.Context, int, int64, dto.ReviewKYCSubmissionRequest) (*dto.KYCSubmissionDTO, error)) {
+	reviewerID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	var req dto.ReviewKYCSubmissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := decide(c.Request.Context(), reviewerID, id, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// DownloadDocument handles GET /api/v1/admin/kyc/documents/:id
+func (h *KYCHandler) DownloadDocument(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	rc, document, err := h.service.OpenDocument(c.Request.Context(), id)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	defer rc.Close()
+
+	c.DataFromReader(http.StatusOK, document.SizeBytes, document.ContentType, rc, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": document.FileName}),
+		"Cache-Control":       "no-store",
+	})
+}
+
+// ==============================================
+// ROUTE REGISTRATION
+// ==============================================
+
+// RegisterRoutes registers KYC routes on the public and authenticated /api/v1 groups
+func (h *KYCHandler) RegisterRoutes(public, protected *gin.RouterGroup) {
+	public.GET("/kyc/tiers", h.ListTiers)
+
+	protected.GET("/kyc/limits", h.GetLimits)
+	protected.POST("/kyc/submissions", h.Submit)
+	protected.GET("/kyc/submissions", h.ListMySubmissions)
+	protected.POST("/kyc/submissions/:id/documents", h.UploadDocument)
+}
+
+// RegisterAdminRoutes registers the KYC review queue on the /api/v1/admin group
+func (h *KYCHandler) RegisterAdminRoutes(admin *gin.RouterGroup) {
+	kyc := admin.Group("", middleware.RequirePermission(auth.PermKYCReview))
+	kyc.GET("/kyc/submissions", h.ListQueue)
+	kyc.GET("/kyc/submissions/:id", h.GetForReview)
+	kyc.POST("/kyc/submissions/:id/approve", h.Approve)
+	kyc.POST("/kyc/submissions/:id/reject", h.Reject)
+	kyc.GET("/kyc/documents/:id", h.DownloadDocument)
+}
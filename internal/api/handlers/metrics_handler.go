@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/Brownie44l1/debank/internal/metrics"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+type MetricsHandler struct {
+	token   string // Bearer token scrapers must send; empty leaves /metrics open
+	handler http.Handler
+}
+
+func NewMetricsHandler(token string) *MetricsHandler {
+	return &MetricsHandler{
+		token:   token,
+		handler: promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{}),
+	}
+}
+
+// Metrics handles GET /metrics - Prometheus exposition format
+func (h *MetricsHandler) Metrics(c *gin.Context) {
+	if h.token != "" && !bearerTokenMatches(c, h.token) {
+		respondUnauthorized(c)
+		return
+	}
+	h.handler.ServeHTTP(c.Writer, c.Request)
+}
+
+// bearerTokenMatches compares the request's Bearer token with token in constant time
+func bearerTokenMatches(c *gin.Context, token string) bool {
+	want := "Bearer " + token
+	return subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), []byte(want)) == 1
+}
+
+// RegisterRoutes registers the metrics endpoint
+func (h *MetricsHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/metrics", h.Metrics)
+}
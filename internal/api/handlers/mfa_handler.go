@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// ==============================================
+// TWO-FACTOR LOGIN (public)
+// ==============================================
+
+// CompleteMFALogin handles POST /api/v1/auth/login/mfa
+func (h *AuthHandler) CompleteMFALogin(c *gin.Context) {
+	var req dto.MFALoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.CompleteMFALogin(c.Request.Context(), req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// SendLoginCodeByEmail handles POST /api/v1/auth/login/mfa/email
+func (h *AuthHandler) SendLoginCodeByEmail(c *gin.Context) {
+	var req dto.MFAEmailFallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.SendLoginCodeByEmail(c.Request.Context(), req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ==============================================
+// TWO-FACTOR SETTINGS (authenticated)
+// ==============================================
+
+// GetMFAStatus handles GET /api/v1/auth/mfa
+func (h *AuthHandler) GetMFAStatus(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	resp, err := h.service.GetMFAStatus(c.Request.Context(), userID)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// StartTOTPEnrollment handles POST /api/v1/auth/mfa/totp
+func (h *AuthHandler) StartTOTPEnrollment(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	resp, err := h.service.StartTOTPEnrollment(c.Request.Context(), userID)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSecret(c, http.StatusOK, resp)
+}
+
+// ConfirmTOTP handles POST /api/v1/auth/mfa/totp/confirm
+func (h *AuthHandler) ConfirmTOTP(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.ConfirmTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.ConfirmTOTP(c.Request.Context(), userID, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSecret(c, http.StatusOK, resp)
+}
+
+// EnableEmailMFA handles POST /api/v1/auth/mfa/email
+func (h *AuthHandler) EnableEmailMFA(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.EnableEmailMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.EnableEmailMFA(c.Request.Context(), userID, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSecret(c, http.StatusOK, resp)
+}
+
+// SendMFACode handles POST /api/v1/auth/mfa/code
+func (h *AuthHandler) SendMFACode(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	resp, err := h.service.SendMFACode(c.Request.Context(), userID)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// DisableMFA handles POST /api/v1/auth/mfa/disable
+func (h *AuthHandler) DisableMFA(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.MFAReauthRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	if err := h.service.DisableMFA(c.Request.Context(), userID, req); err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RegenerateRecoveryCodes handles POST /api/v1/auth/mfa/recovery-codes
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.MFAReauthRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.RegenerateRecoveryCodes(c.Request.Context(), userID, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSecret(c, http.StatusOK, resp)
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// ==============================================
+// SERVICE INTERFACE (for testing)
+// ==============================================
+
+type PaymentRequestService interface {
+	Create(ctx context.Context, userID int, req dto.CreatePaymentRequestRequest) (*dto.PaymentRequestDTO, error)
+	Get(ctx context.Context, userID int, id int64) (*dto.PaymentRequestDTO, error)
+	GetByToken(ctx context.Context, token string) (*dto.PaymentRequestDTO, error)
+	Pay(ctx context.Context, userID int, id int64, req dto.PayPaymentRequestRequest) (*dto.PayPaymentRequestResponse, error)
+	PayByToken(ctx context.Context, userID int, token string, req dto.PayPaymentRequestRequest) (*dto.PayPaymentRequestResponse, error)
+	Decline(ctx context.Context, userID int, id int64, req dto.DeclinePaymentRequestRequest) (*dto.PaymentRequestDTO, error)
+	List(ctx context.Context, userID int, req dto.ListPaymentRequestsRequest) (*dto.PaymentRequestListResponse, error)
+}
+
+// ==============================================
+// HANDLER
+// ==============================================
+
+type PaymentRequestHandler struct {
+	service PaymentRequestService
+}
+
+func NewPaymentRequestHandler(service PaymentRequestService) *PaymentRequestHandler {
+	return &PaymentRequestHandler{service: service}
+}
+
+// ==============================================
+// ENDPOINTS
+// ==============================================
+
+// Create handles POST /api/v1/payment-requests
+func (h *PaymentRequestHandler) Create(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.CreatePaymentRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.Create(c.Request.Context(), userID, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusCreated, resp)
+}
+
+// List handles GET /api/v1/payment-requests?direction=sent|received&status=&page=&per_page=
+func (h *PaymentRequestHandler) List(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.ListPaymentRequestsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.List(c.Request.Context(), userID, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// Get handles GET /api/v1/payment-requests/:id
+func (h *PaymentRequestHandler) Get(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.Get(c.Request.Context(), userID, id)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// Pay handles POST /api/v1/payment-requests/:id/pay
+func (h *PaymentRequestHandler) Pay(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	var req dto.PayPaymentRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.Pay(c.Request.Context(), userID, id, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// Decline handles POST /api/v1/payment-requests/:id/decline
+func (h *PaymentRequestHandler) Decline(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	var req dto.DeclinePaymentRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.Decline(c.Request.Context(), userID, id, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// GetByToken handles GET /api/v1/pay/:token
+// Public so that a shared link can be previewed before logging in
+func (h *PaymentRequestHandler) GetByToken(c *gin.Context) {
+	resp, err := h.service.GetByToken(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// PayByToken handles POST /api/v1/pay/:token
+func (h *PaymentRequestHandler) PayByToken(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.PayPaymentRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.PayByToken(c.Request.Context(), userID, c.Param("token"), req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ==============================================
+// ROUTE REGISTRATION
+// ==============================================
+
+// RegisterRoutes registers payment request routes on the public and authenticated /api/v1 groups
+func (h *PaymentRequestHandler) RegisterRoutes(public, protected *gin.RouterGroup) {
+	public.GET("/pay/:token", h.GetByToken)
+
+	protected.POST("/pay/:token", h.PayByToken)
+	protected.POST("/payment-requests", h.Create)
+	protected.GET("/payment-requests", h.List)
+	protected.GET("/payment-requests/:id", h.Get)
+	protected.POST("/payment-requests/:id/pay", h.Pay)
+	protected.POST("/payment-requests/:id/decline", h.Decline)
+}
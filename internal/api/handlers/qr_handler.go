@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// ==============================================
+// SERVICE INTERFACE (for testing)
+// ==============================================
+
+type QRService interface {
+	Generate(ctx context.Context, userID int, req dto.GenerateQRRequest) (*dto.QRCodeResponse, []byte, error)
+	Decode(ctx context.Context, req dto.DecodeQRRequest) (*dto.DecodeQRResponse, error)
+}
+
+// ==============================================
+// HANDLER
+// ==============================================
+
+type QRHandler struct {
+	service QRService
+}
+
+func NewQRHandler(service QRService) *QRHandler {
+	return &QRHandler{service: service}
+}
+
+// ==============================================
+// ENDPOINTS
+// ==============================================
+
+// Generate handles POST /api/v1/qr
+// Returns the EMVCo payload string and the PNG (base64) in one response
+func (h *QRHandler) Generate(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.GenerateQRRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, _, err := h.service.Generate(c.Request.Context(), userID, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// GeneratePNG handles GET /api/v1/qr.png?amount=&reference=
+// Returns the raw image so it can be used directly as an <img> source
+func (h *QRHandler) GeneratePNG(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.GenerateQRRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, png, err := h.service.Generate(c.Request.Context(), userID, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	c.Header("X-QR-Payload", resp.Payload)
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// Decode handles POST /api/v1/qr/decode
+func (h *QRHandler) Decode(c *gin.Context) {
+	var req dto.DecodeQRRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.Decode(c.Request.Context(), req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ==============================================
+// ROUTE REGISTRATION
+// ==============================================
+
+// RegisterRoutes registers QR routes on the authenticated /api/v1 group
+func (h *QRHandler) RegisterRoutes(public, protected *gin.RouterGroup) {
+	protected.POST("/qr", h.Generate)
+	protected.GET("/qr.png", h.GeneratePNG)
+	protected.POST("/qr/decode", h.Decode)
+}
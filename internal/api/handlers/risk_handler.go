@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/api/middleware"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// ==============================================
+// SERVICE INTERFACE (for testing)
+// ==============================================
+
+type RiskService interface {
+	ListRules(ctx context.Context) (*dto.RiskRulesResponse, error)
+	UpdateRule(ctx context.Context, adminID int, code string, req dto.UpdateRiskRuleRequest) (*dto.RiskRuleDTO, error)
+	ListDecisions(ctx context.Context, req dto.ListRiskDecisionsRequest) (*dto.RiskDecisionListResponse, error)
+	GetDecision(ctx context.Context, id int64) (*dto.RiskDecisionDTO, error)
+}
+
+// ==============================================
+// HANDLER
+// ==============================================
+
+type RiskHandler struct {
+	service RiskService
+}
+
+func NewRiskHandler(service RiskService) *RiskHandler {
+	return &RiskHandler{service: service}
+}
+
+// ==============================================
+// ADMIN ENDPOINTS
+// ==============================================
+
+// ListRules handles GET /api/v1/admin/risk/rules
+func (h *RiskHandler) ListRules(c *gin.Context) {
+	resp, err := h.service.ListRules(c.Request.Context())
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// UpdateRule handles PATCH /api/v1/admin/risk/rules/:code
+func (h *RiskHandler) UpdateRule(c *gin.Context) {
+	adminID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.UpdateRiskRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.UpdateRule(c.Request.Context(), adminID, c.Param("code"), req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ListDecisions handles GET /api/v1/admin/risk/decisions?user_id=&outcome=&page=&per_page=
+func (h *RiskHandler) ListDecisions(c *gin.Context) {
+	var req dto.ListRiskDecisionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.ListDecisions(c.Request.Context(), req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// GetDecision handles GET /api/v1/admin/risk/decisions/:id
+func (h *RiskHandler) GetDecision(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.GetDecision(c.Request.Context(), id)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ==============================================
+// ROUTE REGISTRATION
+// ==============================================
+
+// RegisterAdminRoutes registers risk analyst routes on the /api/v1/admin group
+func (h *RiskHandler) RegisterAdminRoutes(admin *gin.RouterGroup) {
+	risk := admin.Group("", middleware.RequirePermission(auth.PermRiskRead))
+	risk.GET("/risk/rules", h.ListRules)
+	risk.PATCH("/risk/rules/:code", middleware.RequirePermission(auth.PermRiskManage), h.UpdateRule)
+	risk.GET("/risk/decisions", h.ListDecisions)
+	risk.GET("/risk/decisions/:id", h.GetDecision)
+}
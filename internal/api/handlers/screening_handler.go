@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/api/middleware"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// ==============================================
+// SERVICE INTERFACE (for testing)
+// ==============================================
+
+type ScreeningService interface {
+	ListHits(ctx context.Context, req dto.ListScreeningHitsRequest) (*dto.ScreeningHitListResponse, error)
+	GetHit(ctx context.Context, id int64) (*dto.ScreeningHitDTO, error)
+	Clear(ctx context.Context, reviewerID int, id int64, req dto.ReviewScreeningHitRequest) (*dto.ScreeningHitDTO, error)
+	Confirm(ctx context.Context, reviewerID int, id int64, req dto.ReviewScreeningHitRequest) (*dto.ScreeningHitDTO, error)
+	Reload(ctx context.Context) (*dto.WatchlistStatusResponse, error)
+	Status() *dto.WatchlistStatusResponse
+}
+
+// ==============================================
+// HANDLER
+// ==============================================
+
+type ScreeningHandler struct {
+	service ScreeningService
+}
+
+func NewScreeningHandler(service ScreeningService) *ScreeningHandler {
+	return &ScreeningHandler{service: service}
+}
+
+// ==============================================
+// ADMIN ENDPOINTS
+// ==============================================
+
+// ListHits handles GET /api/v1/admin/screening/hits?status=&subject_type=&page=&per_page=
+func (h *ScreeningHandler) ListHits(c *gin.Context) {
+	var req dto.ListScreeningHitsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.ListHits(c.Request.Context(), req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// GetHit handles GET /api/v1/admin/screening/hits/:id
+func (h *ScreeningHandler) GetHit(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.GetHit(c.Request.Context(), id)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// Clear handles POST /api/v1/admin/screening/hits/:id/clear
+func (h *ScreeningHandler) Clear(c *gin.Context) {
+	h.review(c, h.service.Clear)
+}
+
+// Confirm handles POST /api/v1/admin/screening/hits/:id/confirm
+func (h *ScreeningHandler) Confirm(c *gin.Context) {
+	h.review(c, h.service.Confirm)
+}
+
+func (h *ScreeningHandler) review(c *gin.Context, act func(ctx context.Context, reviewerID int, id int64, req dto.ReviewScreeningHitRequest) (*dto.ScreeningHitDTO, error)) {
+	reviewerID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	var req dto.ReviewScreeningHitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := act(c.Request.Context(), reviewerID, id, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// Status handles GET /api/v1/admin/screening/lists
+func (h *ScreeningHandler) Status(c *gin.Context) {
+	respondSuccess(c, http.StatusOK, h.service.Status())
+}
+
+// Reload handles POST /api/v1/admin/screening/lists/reload
+func (h *ScreeningHandler) Reload(c *gin.Context) {
+	resp, err := h.service.Reload(c.Request.Context())
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ==============================================
+// ROUTE REGISTRATION
+// ==============================================
+
+// RegisterAdminRoutes registers screening review and watchlist routes on the /api/v1/admin group
+func (h *ScreeningHandler) RegisterAdminRoutes(admin *gin.RouterGroup) {
+	screening := admin.Group("", middleware.RequirePermission(auth.PermScreeningManage))
+	screening.GET("/screening/hits", h.ListHits)
+	screening.GET("/screening/hits/:id", h.GetHit)
+	screening.POST("/screening/hits/:id/clear", h.Clear)
+	screening.POST("/screening/hits/:id/confirm", h.Confirm)
+	screening.GET("/screening/lists", h.Status)
+	screening.POST("/screening/lists/reload", h.Reload)
+}
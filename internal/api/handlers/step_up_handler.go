@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// ==============================================
+// SERVICE INTERFACE (for testing)
+// ==============================================
+
+type StepUpService interface {
+	SendEmailCode(ctx context.Context, userID int, req dto.StepUpEmailRequest) (*dto.MFACodeSentResponse, error)
+}
+
+// ==============================================
+// HANDLER
+// ==============================================
+
+// StepUpHandler serves the helpers around step-up challenges; the challenges
+// themselves come back from the sensitive endpoints as 403 responses
+type StepUpHandler struct {
+	service StepUpService
+}
+
+func NewStepUpHandler(service StepUpService) *StepUpHandler {
+	return &StepUpHandler{service: service}
+}
+
+// SendEmailCode handles POST /api/v1/auth/step-up/email
+func (h *StepUpHandler) SendEmailCode(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.StepUpEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	resp, err := h.service.SendEmailCode(c.Request.Context(), userID, req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondSuccess(c, http.StatusOK, resp)
+}
+
+// ==============================================
+// ROUTE REGISTRATION
+// ==============================================
+
+// RegisterRoutes registers step-up routes on the authenticated /api/v1 group
+func (h *StepUpHandler) RegisterRoutes(public, protected *gin.RouterGroup) {
+	stepUp := protected.Group("/auth/step-up")
+	stepUp.POST("/email", h.SendEmailCode)
+}
@@ -5,10 +5,10 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
-	"time"
 
-	"github.com/Brownie44l1/debank/internal/models"
-	"github.com/Brownie44l1/debank/internal/service"
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/api/middleware"
+	"github.com/Brownie44l1/debank/internal/api/problem"
 	"github.com/gin-gonic/gin"
 )
 
@@ -17,11 +17,11 @@ import (
 // ==============================================
 
 type WalletService interface {
-	Deposit(ctx context.Context, req models.DepositRequest) (*models.TransactionResponse, error)
-	Withdraw(ctx context.Context, req models.WithdrawRequest) (*models.TransactionResponse, error)
-	Transfer(ctx context.Context, req models.TransferRequest) (*models.TransferResponse, error)
-	GetBalance(ctx context.Context, userID int) (*models.BalanceResponse, error)
-	GetTransactionHistory(ctx context.Context, userID, page, perPage int) (*models.TransactionHistoryResponse, error)
+	Deposit(ctx context.Context, userID int, req dto.DepositRequest) (*dto.TransactionResponse, error)
+	Withdraw(ctx context.Context, userID int, req dto.WithdrawRequest) (*dto.TransactionResponse, error)
+	Transfer(ctx context.Context, userID int, req dto.TransferRequest) (*dto.TransferResponse, error)
+	GetBalance(ctx context.Context, userID int) (*dto.BalanceResponse, error)
+	GetTransactionHistory(ctx context.Context, userID, page, perPage int) (*dto.TransactionHistoryResponse, error)
 }
 
 // ==============================================
@@ -40,25 +40,22 @@ func NewWalletHandler(service WalletService) *WalletHandler {
 // ENDPOINTS
 // ==============================================
 
-// HealthCheck handles GET /api/v1/health
-func (h *WalletHandler) HealthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "ok",
-		"message": "wallet service is healthy",
-		"time":    time.Now().UTC(),
-	})
-}
-
 // Deposit handles POST /api/v1/deposit
 func (h *WalletHandler) Deposit(c *gin.Context) {
-	var req models.DepositRequest
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.DepositRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		respondError(c, http.StatusBadRequest, "Invalid request", err)
+		respondBindError(c, err)
 		return
 	}
 
-	resp, err := h.service.Deposit(c.Request.Context(), req)
+	resp, err := h.service.Deposit(c.Request.Context(), userID, req)
 	if err != nil {
 		respondServiceError(c, err)
 		return
@@ -69,14 +66,20 @@ func (h *WalletHandler) Deposit(c *gin.Context) {
 
 // Withdraw handles POST /api/v1/withdraw
 func (h *WalletHandler) Withdraw(c *gin.Context) {
-	var req models.WithdrawRequest
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.WithdrawRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		respondError(c, http.StatusBadRequest, "Invalid request", err)
+		respondBindError(c, err)
 		return
 	}
 
-	resp, err := h.service.Withdraw(c.Request.Context(), req)
+	resp, err := h.service.Withdraw(c.Request.Context(), userID, req)
 	if err != nil {
 		respondServiceError(c, err)
 		return
@@ -87,14 +90,20 @@ func (h *WalletHandler) Withdraw(c *gin.Context) {
 
 // Transfer handles POST /api/v1/transfer
 func (h *WalletHandler) Transfer(c *gin.Context) {
-	var req models.TransferRequest
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondUnauthorized(c)
+		return
+	}
+
+	var req dto.TransferRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		respondError(c, http.StatusBadRequest, "Invalid request", err)
+		respondBindError(c, err)
 		return
 	}
 
-	resp, err := h.service.Transfer(c.Request.Context(), req)
+	resp, err := h.service.Transfer(c.Request.Context(), userID, req)
 	if err != nil {
 		respondServiceError(c, err)
 		return
@@ -103,11 +112,11 @@ func (h *WalletHandler) Transfer(c *gin.Context) {
 	respondSuccess(c, http.StatusOK, resp)
 }
 
-// GetBalance handles GET /api/v1/balance/:user_id
+// GetBalance handles GET /api/v1/balance
 func (h *WalletHandler) GetBalance(c *gin.Context) {
-	userID, err := parseUserID(c)
+	userID, err := currentUserID(c)
 	if err != nil {
-		respondError(c, http.StatusBadRequest, "Invalid user_id", err)
+		respondUnauthorized(c)
 		return
 	}
 
@@ -120,19 +129,22 @@ func (h *WalletHandler) GetBalance(c *gin.Context) {
 	respondSuccess(c, http.StatusOK, resp)
 }
 
-// GetTransactionHistory handles GET /api/v1/transactions/:user_id
+// GetTransactionHistory handles GET /api/v1/transactions
 func (h *WalletHandler) GetTransactionHistory(c *gin.Context) {
-	userID, err := parseUserID(c)
+	userID, err := currentUserID(c)
 	if err != nil {
-		respondError(c, http.StatusBadRequest, "Invalid user_id", err)
+		respondUnauthorized(c)
 		return
 	}
 
-	// Parse pagination parameters
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	var req dto.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
 
-	resp, err := h.service.GetTransactionHistory(c.Request.Context(), userID, page, perPage)
+	// Zero values fall back to the service's defaults
+	resp, err := h.service.GetTransactionHistory(c.Request.Context(), userID, req.Page, req.PerPage)
 	if err != nil {
 		respondServiceError(c, err)
 		return
@@ -145,33 +157,38 @@ func (h *WalletHandler) GetTransactionHistory(c *gin.Context) {
 // ROUTE REGISTRATION
 // ==============================================
 
-func (h *WalletHandler) RegisterRoutes(router *gin.Engine) {
-	v1 := router.Group("/api/v1")
-	{
-		v1.GET("/health", h.HealthCheck)
-		v1.POST("/deposit", h.Deposit)
-		v1.POST("/withdraw", h.Withdraw)
-		v1.POST("/transfer", h.Transfer)
-		v1.GET("/balance/:user_id", h.GetBalance)
-		v1.GET("/transactions/:user_id", h.GetTransactionHistory)
-	}
+// RegisterRoutes registers wallet routes on the public and authenticated /api/v1 groups
+func (h *WalletHandler) RegisterRoutes(public, protected *gin.RouterGroup) {
+	protected.POST("/deposit", h.Deposit)
+	protected.POST("/withdraw", h.Withdraw)
+	protected.POST("/transfer", h.Transfer)
+	protected.GET("/balance", h.GetBalance)
+	protected.GET("/transactions", h.GetTransactionHistory)
 }
 
 // ==============================================
 // HELPER FUNCTIONS
 // ==============================================
 
-// parseUserID extracts and validates user_id from URL parameter
-func parseUserID(c *gin.Context) (int, error) {
-	userIDStr := c.Param("user_id")
-	userID, err := strconv.Atoi(userIDStr)
+// currentUserID returns the authenticated caller's user ID
+func currentUserID(c *gin.Context) (int, error) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return 0, errors.New("authentication required")
+	}
+	return userID, nil
+}
+
+// parseIDParam extracts and validates a positive numeric ID from a URL parameter
+func parseIDParam(c *gin.Context, name string) (int64, error) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
 	if err != nil {
-		return 0, errors.New("user_id must be a number")
+		return 0, &problem.FieldError{Field: name, Code: "numeric", Message: "must be a number"}
 	}
-	if userID <= 0 {
-		return 0, errors.New("user_id must be positive")
+	if id <= 0 {
+		return 0, &problem.FieldError{Field: name, Code: "gt", Message: "must be positive"}
 	}
-	return userID, nil
+	return id, nil
 }
 
 // respondSuccess sends a successful JSON response
@@ -179,52 +196,9 @@ func respondSuccess(c *gin.Context, statusCode int, data interface{}) {
 	c.JSON(statusCode, data)
 }
 
-// respondError sends an error JSON response
-func respondError(c *gin.Context, statusCode int, message string, err error) {
-	c.JSON(statusCode, gin.H{
-		"error":   message,
-		"message": err.Error(),
-	})
-}
-
-// respondServiceError maps service errors to appropriate HTTP status codes and responses
-func respondServiceError(c *gin.Context, err error) {
-	statusCode, message := mapServiceError(err)
-	c.JSON(statusCode, gin.H{
-		"error":   message,
-		"message": err.Error(),
-	})
+// respondSecret sends a JSON response carrying secrets (TOTP seeds, recovery codes)
+// no-store keeps it out of caches and out of the Idempotency-Key response store
+func respondSecret(c *gin.Context, statusCode int, data interface{}) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(statusCode, data)
 }
-
-// mapServiceError maps service errors to HTTP status codes and user-friendly messages
-func mapServiceError(err error) (int, string) {
-	switch {
-	// Validation errors (400 Bad Request)
-	case errors.Is(err, service.ErrInvalidAmount):
-		return http.StatusBadRequest, "Invalid amount"
-	case errors.Is(err, service.ErrAmountTooSmall):
-		return http.StatusBadRequest, "Amount too small"
-	case errors.Is(err, service.ErrAmountTooLarge):
-		return http.StatusBadRequest, "Amount too large"
-	case errors.Is(err, service.ErrInvalidIdempotencyKey):
-		return http.StatusBadRequest, "Idempotency key required"
-	case errors.Is(err, service.ErrSameAccount):
-		return http.StatusBadRequest, "Cannot transfer to same account"
-
-	// Not found errors (404 Not Found)
-	case errors.Is(err, service.ErrAccountNotFound):
-		return http.StatusNotFound, "Account not found"
-
-	// Business logic errors (422 Unprocessable Entity)
-	case errors.Is(err, service.ErrInsufficientBalance):
-		return http.StatusUnprocessableEntity, "Insufficient balance"
-
-	// System errors (500 Internal Server Error)
-	case errors.Is(err, service.ErrNegativeBalance):
-		return http.StatusInternalServerError, "System error: negative balance detected"
-
-	// Default (500 Internal Server Error)
-	default:
-		return http.StatusInternalServerError, "Internal server error"
-	}
-}
\ No newline at end of file
@@ -1 +1,97 @@
-package middleware
\ No newline at end of file
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/Brownie44l1/debank/internal/api/problem"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Gin context keys set by AuthMiddleware
+const (
+	ContextUserIDKey = "user_id" // The authenticated user's ID
+	ContextRoleKey   = "role"    // The role the access token was issued with
+)
+
+// TokenVerifier checks an access token's signature and claims (implemented by auth.Keyring)
+type TokenVerifier interface {
+	ParseJWT(token string) (*auth.Claims, error)
+}
+
+// SessionValidator reports whether the login session behind a token is still open (implemented by DeviceService)
+type SessionValidator interface {
+	IsSessionActive(ctx context.Context, userID int, sessionID string) (bool, error)
+}
+
+// AuthMiddleware verifies the Bearer JWT and its session, and stores the caller's user ID and role in the context
+func AuthMiddleware(tokens TokenVerifier, sessions SessionValidator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		token, found := strings.CutPrefix(header, "Bearer ")
+		if !found || token == "" {
+			problem.Abort(c, problem.New(http.StatusUnauthorized, models.ErrCodeUnauthorized, "Unauthorized").
+				WithDetail("missing or malformed Authorization header"))
+			return
+		}
+
+		claims, err := tokens.ParseJWT(token)
+		if err != nil {
+			problem.Abort(c, problem.New(http.StatusUnauthorized, models.ErrCodeUnauthorized, "Unauthorized").
+				WithDetail("invalid or expired token"))
+			return
+		}
+
+		// Signing out or revoking the device ends the session before the token expires
+		active, err := sessions.IsSessionActive(c.Request.Context(), claims.UserID, claims.SessionID())
+		if err != nil {
+			log.Printf("[AUTH] Session check failed - UserID: %d: %v", claims.UserID, err)
+			problem.Abort(c, problem.New(http.StatusInternalServerError, models.ErrCodeInternalError, "Internal server error").
+				WithDetail("could not verify session"))
+			return
+		}
+		if !active {
+			problem.Abort(c, problem.New(http.StatusUnauthorized, models.ErrCodeUnauthorized, "Unauthorized").
+				WithDetail("session has ended, please sign in again"))
+			return
+		}
+
+		c.Set(ContextUserIDKey, claims.UserID)
+		c.Set(ContextRoleKey, claims.Role)
+		c.Next()
+	}
+}
+
+// GetUserID returns the authenticated user's ID set by AuthMiddleware
+func GetUserID(c *gin.Context) (int, bool) {
+	v, ok := c.Get(ContextUserIDKey)
+	if !ok {
+		return 0, false
+	}
+	userID, ok := v.(int)
+	return userID, ok && userID > 0
+}
+
+// GetRole returns the authenticated user's role set by AuthMiddleware
+func GetRole(c *gin.Context) string {
+	role, _ := c.Get(ContextRoleKey)
+	r, _ := role.(string)
+	return r
+}
+
+// RequirePermission only lets through callers whose role grants perm
+// Must run after AuthMiddleware
+func RequirePermission(perm auth.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !auth.HasPermission(GetRole(c), perm) {
+			problem.Abort(c, problem.New(http.StatusForbidden, models.ErrCodeForbidden, "Forbidden").
+				WithDetail("your role does not allow this action"))
+			return
+		}
+		c.Next()
+	}
+}
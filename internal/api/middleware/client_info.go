@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/Brownie44l1/debank/internal/clientinfo"
+	"github.com/gin-gonic/gin"
+)
+
+// maxDeviceIDLength caps the client-supplied device ID before it reaches the database
+const maxDeviceIDLength = 128
+
+// ClientInfo stores the caller's IP, user agent and device details in the request context
+func ClientInfo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		deviceID := strings.TrimSpace(c.GetHeader(clientinfo.DeviceIDHeader))
+		if len(deviceID) > maxDeviceIDLength {
+			deviceID = deviceID[:maxDeviceIDLength]
+		}
+
+		ctx := clientinfo.NewContext(c.Request.Context(), clientinfo.Info{
+			IPAddress:  c.ClientIP(),
+			UserAgent:  c.Request.UserAgent(),
+			DeviceID:   deviceID,
+			DeviceName: strings.TrimSpace(c.GetHeader(clientinfo.DeviceNameHeader)),
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
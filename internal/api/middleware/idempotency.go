@@ -0,0 +1,254 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/problem"
+	"github.com/Brownie44l1/debank/internal/metrics"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ==============================================
+// IDEMPOTENCY
+// ==============================================
+// A client may send an Idempotency-Key header on any authenticated
+// POST/PUT/PATCH/DELETE. The key is scoped to the caller and the route, and
+// bound to a fingerprint of the method, URL and body:
+//   - a repeat of a request that succeeded gets the stored response back,
+//     marked with Idempotent-Replayed: true
+//   - the same key with a different request is refused with 409
+//   - a repeat while the first attempt is still running waits for it, then
+//     gives up with 409 and Retry-After
+// Only 2xx/3xx responses are stored. A rejected attempt frees its key, so
+// the client can correct the request (add a PIN, a step-up proof) and retry
+// under the same key. A response marked Cache-Control: no-store (TOTP
+// secrets, recovery codes) is never stored either: its key is freed and a
+// retry runs the request again.
+
+// IdempotencyKeyHeader is the request header carrying the key
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader marks a response served from an earlier attempt
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+const (
+	maxIdempotencyKeyLength = 255
+	idempotencyPollInterval = 100 * time.Millisecond
+)
+
+// IdempotencyStore claims keys and keeps the responses to replay
+type IdempotencyStore interface {
+	Claim(ctx context.Context, userID int, endpoint, key string, fingerprint []byte, lease, ttl time.Duration) (*models.IdempotencyKey, bool, error)
+	Complete(ctx context.Context, k *models.IdempotencyKey, status int, contentType string, body []byte) error
+	Release(ctx context.Context, k *models.IdempotencyKey) error
+}
+
+// IdempotencyOptions tunes the idempotency middleware
+type IdempotencyOptions struct {
+	TTL     time.Duration // How long a successful response can be replayed
+	Lease   time.Duration // How long an attempt holds its key before an identical retry may take over
+	Wait    time.Duration // How long a retry waits for a running attempt before answering 409
+	MaxBody int64         // Largest request body that is fingerprinted
+}
+
+var errIdempotencyTimeout = errors.New("idempotency: original request still in progress")
+
+// Idempotency makes mutating requests that carry an Idempotency-Key safe to retry
+// Must run after AuthMiddleware
+func Idempotency(store IdempotencyStore, opts IdempotencyOptions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		userID, authenticated := GetUserID(c)
+		if key == "" || !authenticated || !isMutating(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if !validIdempotencyKey(key) {
+			problem.Abort(c, problem.New(http.StatusBadRequest, models.ErrCodeInvalidIdempotencyKey, "Invalid Idempotency-Key").
+				WithDetail("key must be 1-255 visible ASCII characters"))
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, opts.MaxBody+1))
+		if err != nil {
+			problem.Abort(c, problem.New(http.StatusBadRequest, models.ErrCodeValidationFailed, "Bad request").
+				WithDetail("could not read request body"))
+			return
+		}
+		if int64(len(body)) > opts.MaxBody {
+			problem.Abort(c, problem.New(http.StatusRequestEntityTooLarge, models.ErrCodeRequestTooLarge, "Request too large").
+				WithDetail("request body is too large to use with an Idempotency-Key"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		endpoint := c.Request.Method + " " + c.FullPath()
+		fingerprint := requestFingerprint(c.Request.Method, c.Request.URL.RequestURI(), body)
+
+		claim, err := awaitIdempotencyClaim(c.Request.Context(), store, userID, endpoint, key, fingerprint, opts)
+		switch {
+		case errors.Is(err, errIdempotencyTimeout):
+			c.Header("Retry-After", "1")
+			problem.Abort(c, problem.New(http.StatusConflict, models.ErrCodeRequestInProgress, "Request in progress").
+				WithDetail("a request with this Idempotency-Key is still being processed"))
+			return
+		case err != nil:
+			if c.Request.Context().Err() == nil {
+				log.Printf("[IDEMPOTENCY] Claim failed - UserID: %d, Endpoint: %s: %v", userID, endpoint, err)
+			}
+			problem.Abort(c, problem.New(http.StatusInternalServerError, models.ErrCodeInternalError, "Internal server error").
+				WithDetail("could not check Idempotency-Key"))
+			return
+		case !bytes.Equal(claim.record.Fingerprint, fingerprint):
+			problem.Abort(c, problem.New(http.StatusConflict, models.ErrCodeIdempotencyConflict, "Idempotency key already used").
+				WithDetail("this Idempotency-Key was used for a different request"))
+			return
+		case !claim.claimed:
+			replayResponse(c, claim.record)
+			return
+		}
+
+		runIdempotent(c, store, claim.record)
+	}
+}
+
+type idempotencyClaim struct {
+	record  *models.IdempotencyKey
+	claimed bool
+}
+
+// awaitIdempotencyClaim claims the key, waiting up to opts.Wait while another attempt holds it
+func awaitIdempotencyClaim(ctx context.Context, store IdempotencyStore, userID int, endpoint, key string, fingerprint []byte, opts IdempotencyOptions) (idempotencyClaim, error) {
+	deadline := time.Now().Add(opts.Wait)
+	for {
+		record, claimed, err := store.Claim(ctx, userID, endpoint, key, fingerprint, opts.Lease, opts.TTL)
+		if err != nil {
+			return idempotencyClaim{}, err
+		}
+		// Decided: ours to run, a response to replay, or a mismatch to refuse
+		if claimed || record.IsCompleted() || !bytes.Equal(record.Fingerprint, fingerprint) {
+			return idempotencyClaim{record: record, claimed: claimed}, nil
+		}
+
+		if time.Now().After(deadline) {
+			return idempotencyClaim{}, errIdempotencyTimeout
+		}
+		select {
+		case <-ctx.Done():
+			return idempotencyClaim{}, ctx.Err()
+		case <-time.After(idempotencyPollInterval):
+		}
+	}
+}
+
+// runIdempotent runs the handlers, then stores a successful response or frees the key
+func runIdempotent(c *gin.Context, store IdempotencyStore, record *models.IdempotencyKey) {
+	// Bookkeeping must finish even if the client hangs up
+	ctx := context.WithoutCancel(c.Request.Context())
+
+	recorder := &responseRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+
+	completed := false
+	defer func() {
+		// Also runs while a handler panic unwinds to Recovery
+		if completed {
+			return
+		}
+		if err := store.Release(ctx, record); err != nil {
+			log.Printf("[IDEMPOTENCY] Release failed - KeyID: %d: %v", record.ID, err)
+		}
+	}()
+
+	c.Next()
+
+	status := recorder.Status()
+	if status < http.StatusOK || status >= http.StatusBadRequest {
+		return
+	}
+	// Secrets must not outlive the response in idempotency_keys
+	if noStore(recorder.Header().Get("Cache-Control")) {
+		return
+	}
+	if err := store.Complete(ctx, record, status, recorder.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+		log.Printf("[IDEMPOTENCY] Storing response failed - KeyID: %d: %v", record.ID, err)
+		return
+	}
+	completed = true
+}
+
+// replayResponse answers with the response stored by an earlier attempt
+func replayResponse(c *gin.Context, record *models.IdempotencyKey) {
+	metrics.IdempotentReplays.WithLabelValues("http").Inc()
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(int(record.ResponseStatus.Int32), record.ResponseContentType.String, record.ResponseBody)
+	c.Abort()
+}
+
+// requestFingerprint hashes what makes two requests "the same": method, path with query, and body
+func requestFingerprint(method, uri string, body []byte) []byte {
+	h := sha256.New()
+	for _, part := range [][]byte{[]byte(method), []byte(uri), body} {
+		var n [8]byte
+		binary.BigEndian.PutUint64(n[:], uint64(len(part)))
+		h.Write(n[:])
+		h.Write(part)
+	}
+	return h.Sum(nil)
+}
+
+// noStore reports whether a Cache-Control header value forbids storing the response
+func noStore(cacheControl string) bool {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+			return true
+		}
+	}
+	return false
+}
+
+func validIdempotencyKey(key string) bool {
+	if len(key) == 0 || len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// responseRecorder copies the response body while it is written to the client
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
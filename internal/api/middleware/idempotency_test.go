@@ -0,0 +1,227 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is an in-memory IdempotencyStore; leases never expire
+type memoryStore struct {
+	mu     sync.Mutex
+	nextID int64
+	keys   map[string]*models.IdempotencyKey
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{keys: map[string]*models.IdempotencyKey{}}
+}
+
+func (s *memoryStore) Claim(ctx context.Context, userID int, endpoint, key string, fingerprint []byte, lease, ttl time.Duration) (*models.IdempotencyKey, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := fmt.Sprintf("%d|%s|%s", userID, endpoint, key)
+	if k, ok := s.keys[id]; ok {
+		copied := *k
+		return &copied, false, nil
+	}
+	s.nextID++
+	k := &models.IdempotencyKey{
+		ID:          s.nextID,
+		UserID:      userID,
+		Endpoint:    endpoint,
+		Key:         key,
+		Fingerprint: fingerprint,
+		Status:      models.IdempotencyStatusInProgress,
+	}
+	s.keys[id] = k
+	copied := *k
+	return &copied, true, nil
+}
+
+func (s *memoryStore) Complete(ctx context.Context, k *models.IdempotencyKey, status int, contentType string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, stored := range s.keys {
+		if stored.ID == k.ID {
+			stored.Status = models.IdempotencyStatusCompleted
+			stored.ResponseStatus = pgtype.Int4{Int32: int32(status), Valid: true}
+			stored.ResponseContentType = pgtype.Text{String: contentType, Valid: true}
+			stored.ResponseBody = body
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) Release(ctx context.Context, k *models.IdempotencyKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, stored := range s.keys {
+		if stored.ID == k.ID && stored.Status == models.IdempotencyStatusInProgress {
+			delete(s.keys, id)
+		}
+	}
+	return nil
+}
+
+var testIdempotencyOptions = IdempotencyOptions{
+	TTL:     time.Hour,
+	Lease:   time.Minute,
+	Wait:    50 * time.Millisecond,
+	MaxBody: 1024,
+}
+
+// newIdempotentRouter serves POST /pay as user 7, answering with handler
+func newIdempotentRouter(store IdempotencyStore, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(ContextUserIDKey, 7)
+		c.Next()
+	})
+	r.Use(Idempotency(store, testIdempotencyOptions))
+	r.POST("/pay", handler)
+	r.GET("/pay", handler)
+	return r
+}
+
+func send(r http.Handler, method, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/pay", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency_ReplaysSuccess(t *testing.T) {
+	var calls atomic.Int32
+	r := newIdempotentRouter(newMemoryStore(), func(c *gin.Context) {
+		n := calls.Add(1)
+		c.JSON(http.StatusCreated, gin.H{"call": n})
+	})
+
+	first := send(r, http.MethodPost, "k1", `{"amount":100}`)
+	second := send(r, http.MethodPost, "k1", `{"amount":100}`)
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+	assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+}
+
+func TestIdempotency_DifferentPayloadConflicts(t *testing.T) {
+	r := newIdempotentRouter(newMemoryStore(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	require.Equal(t, http.StatusOK, send(r, http.MethodPost, "k1", `{"amount":100}`).Code)
+	assert.Equal(t, http.StatusConflict, send(r, http.MethodPost, "k1", `{"amount":200}`).Code)
+}
+
+func TestIdempotency_FailureFreesKey(t *testing.T) {
+	var calls atomic.Int32
+	r := newIdempotentRouter(newMemoryStore(), func(c *gin.Context) {
+		if calls.Add(1) == 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "PIN required"})
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	assert.Equal(t, http.StatusUnauthorized, send(r, http.MethodPost, "k1", `{}`).Code)
+	// Corrected request under the same key runs again
+	assert.Equal(t, http.StatusOK, send(r, http.MethodPost, "k1", `{"pin":"1234"}`).Code)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestIdempotency_NoStoreResponseIsNotKept(t *testing.T) {
+	store := newMemoryStore()
+	var calls atomic.Int32
+	r := newIdempotentRouter(store, func(c *gin.Context) {
+		n := calls.Add(1)
+		c.Header("Cache-Control", "private, no-store")
+		c.JSON(http.StatusOK, gin.H{"recovery_codes": []string{fmt.Sprintf("code-%d", n)}})
+	})
+
+	first := send(r, http.MethodPost, "k1", `{}`)
+	second := send(r, http.MethodPost, "k1", `{}`)
+
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Empty(t, second.Header().Get(IdempotentReplayedHeader))
+	assert.Empty(t, store.keys, "the secret-bearing response must not be stored")
+}
+
+func TestIdempotency_InProgressConflicts(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	r := newIdempotentRouter(newMemoryStore(), func(c *gin.Context) {
+		close(started)
+		<-release
+		c.Status(http.StatusOK)
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- send(r, http.MethodPost, "k1", `{}`) }()
+	<-started
+
+	retry := send(r, http.MethodPost, "k1", `{}`)
+	assert.Equal(t, http.StatusConflict, retry.Code)
+	assert.Equal(t, "1", retry.Header().Get("Retry-After"))
+
+	close(release)
+	assert.Equal(t, http.StatusOK, (<-done).Code)
+}
+
+func TestIdempotency_Bypass(t *testing.T) {
+	var calls atomic.Int32
+	r := newIdempotentRouter(newMemoryStore(), func(c *gin.Context) {
+		calls.Add(1)
+		c.Status(http.StatusOK)
+	})
+
+	// No key, or a safe method: every request runs
+	send(r, http.MethodPost, "", `{}`)
+	send(r, http.MethodPost, "", `{}`)
+	send(r, http.MethodGet, "k1", "")
+	send(r, http.MethodGet, "k1", "")
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestIdempotency_Validation(t *testing.T) {
+	r := newIdempotentRouter(newMemoryStore(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	assert.Equal(t, http.StatusBadRequest, send(r, http.MethodPost, "has space", `{}`).Code)
+	assert.Equal(t, http.StatusBadRequest, send(r, http.MethodPost, strings.Repeat("k", 256), `{}`).Code)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send(r, http.MethodPost, "k1", strings.Repeat("x", 1025)).Code)
+}
+
+func TestRequestFingerprint(t *testing.T) {
+	base := requestFingerprint("POST", "/pay", []byte("{}"))
+	assert.Equal(t, base, requestFingerprint("POST", "/pay", []byte("{}")))
+	assert.False(t, bytes.Equal(base, requestFingerprint("PUT", "/pay", []byte("{}"))))
+	assert.False(t, bytes.Equal(base, requestFingerprint("POST", "/pay?x=1", []byte("{}"))))
+	// Length prefixes keep boundaries apart
+	assert.False(t, bytes.Equal(
+		requestFingerprint("POST", "/a", []byte("b")),
+		requestFingerprint("POST", "/ab", nil)))
+}
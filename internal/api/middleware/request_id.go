@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/Brownie44l1/debank/internal/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestID tags every request with an ID, reusing the caller's X-Request-ID when it is well formed
+// The ID is echoed in the response header and included in error responses.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		c.Header(requestid.Header, id)
+		c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), id))
+		c.Next()
+	}
+}
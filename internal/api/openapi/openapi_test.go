@@ -0,0 +1,250 @@
+package openapi
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Brownie44l1/debank/internal/api"
+	"github.com/Brownie44l1/debank/internal/api/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unexposedDTOs are dto structs no route reads or writes yet
+var unexposedDTOs = map[string]bool{
+	"ValidatePinRequest": true,
+	"LogoutRequest":      true,
+	"LogoutResponse":     true,
+	"SuccessResponse":    true,
+	"PaginationMeta":     true,
+
+	// Ledger operations are only reachable through cmd/debankctl
+	"CreateSystemAccountRequest": true,
+	"AdjustmentRequest":          true,
+	"ReverseTransactionRequest":  true,
+	"FreezeAccountRequest":       true,
+	"UnfreezeAccountRequest":     true,
+	"StatementRequest":           true,
+	"ReconciliationReport":       true,
+	"BalanceMismatchDTO":         true,
+	"UnbalancedTransactionDTO":   true,
+	"StatementResponse":          true,
+	"StatementEntryDTO":          true,
+}
+
+func buildDocument(t *testing.T) *Document {
+	t.Helper()
+	doc, err := Build()
+	require.NoError(t, err)
+	return doc
+}
+
+// TestSpecCoversRegisteredRoutes fails when a route is added to the router
+// without a line in routes.go, or removed from the router but not from routes.go
+func TestSpecCoversRegisteredRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := api.NewRouter(api.Handlers{}, nil, nil, nil, middleware.IdempotencyOptions{})
+	doc := buildDocument(t)
+
+	registered := make(map[string]bool)
+	for _, r := range router.Routes() {
+		registered[r.Method+" "+specPath(r.Path)] = true
+		item := doc.Paths[specPath(r.Path)]
+		assert.Contains(t, item, strings.ToLower(r.Method), "%s %s is registered but missing from routes.go", r.Method, r.Path)
+	}
+
+	for path, item := range doc.Paths {
+		for method := range item {
+			assert.True(t, registered[strings.ToUpper(method)+" "+path], "%s %s is in routes.go but not registered", strings.ToUpper(method), path)
+		}
+	}
+}
+
+// TestSpecCoversDTOFields reads the dto sources, so a struct that no route
+// references, or a field the spec does not show, fails here
+func TestSpecCoversDTOFields(t *testing.T) {
+	doc := buildDocument(t)
+	structs := parseDTOStructs(t)
+	require.NotEmpty(t, structs)
+
+	for name, fields := range structs {
+		if unexposedDTOs[name] {
+			assert.NotContains(t, doc.Components.Schemas, name, "dto.%s is in the spec; drop it from unexposedDTOs", name)
+			continue
+		}
+
+		if schema, ok := doc.Components.Schemas[name]; ok {
+			for _, f := range fields["json"] {
+				assert.Contains(t, schema.Properties, f, "dto.%s field %q is missing from its schema", name, f)
+			}
+			continue
+		}
+
+		ops := operationsBinding(doc, name)
+		if !assert.NotEmpty(t, ops, "dto.%s is not in the spec; reference it from routes.go or add it to unexposedDTOs", name) {
+			continue
+		}
+		for _, op := range ops {
+			shown := inputNames(op)
+			for _, f := range fields["form"] {
+				assert.True(t, shown[f], "dto.%s field %q is missing from %s", name, f, op.OperationID)
+			}
+		}
+	}
+}
+
+// parseDTOStructs maps each exported dto struct to its json and form field names
+func parseDTOStructs(t *testing.T) map[string]map[string][]string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join("..", "dto", "*.go"))
+	require.NoError(t, err)
+
+	structs := make(map[string]map[string][]string)
+	fset := token.NewFileSet()
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		require.NoError(t, err)
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok || !spec.Name.IsExported() {
+				return true
+			}
+			st, ok := spec.Type.(*ast.StructType)
+			if !ok {
+				return true
+			}
+
+			names := map[string][]string{}
+			for _, f := range st.Fields.List {
+				if f.Tag == nil {
+					continue
+				}
+				raw, err := strconv.Unquote(f.Tag.Value)
+				require.NoError(t, err)
+				for _, key := range []string{"json", "form"} {
+					name, _, _ := strings.Cut(reflect.StructTag(raw).Get(key), ",")
+					if name != "" && name != "-" {
+						names[key] = append(names[key], name)
+					}
+				}
+			}
+			structs[spec.Name.Name] = names
+			return true
+		})
+	}
+	return structs
+}
+
+// operationsBinding finds the operations whose query or multipart form comes from dto.name
+func operationsBinding(doc *Document, name string) []*Operation {
+	var ops []*Operation
+	for _, r := range routes {
+		for _, v := range []interface{}{r.query, r.form} {
+			if v != nil && reflect.TypeOf(v).Name() == name {
+				ops = append(ops, doc.Paths[specPath(r.path)][strings.ToLower(r.method)])
+			}
+		}
+	}
+	return ops
+}
+
+// inputNames lists an operation's parameter and multipart field names
+func inputNames(op *Operation) map[string]bool {
+	names := make(map[string]bool)
+	for _, p := range op.Parameters {
+		names[p.Name] = true
+	}
+	if op.RequestBody != nil {
+		if form, ok := op.RequestBody.Content["multipart/form-data"]; ok {
+			for name := range form.Schema.Properties {
+				names[name] = true
+			}
+		}
+	}
+	return names
+}
+
+func TestBuild_Operation(t *testing.T) {
+	doc := buildDocument(t)
+
+	op := doc.Paths["/api/v1/payment-requests/{id}/pay"]["post"]
+	require.NotNil(t, op)
+	assert.Equal(t, "postPaymentRequestsByIdPay", op.OperationID)
+	assert.Equal(t, []SecurityRequirement{{bearerScheme: {}}}, op.Security)
+	assert.Equal(t, "id", op.Parameters[0].Name)
+	assert.Equal(t, "integer", op.Parameters[0].Schema.Type)
+	assert.Equal(t, "#/components/parameters/"+idempotencyParameter, op.Parameters[1].Ref)
+	assert.Equal(t, componentPrefix+"PayPaymentRequestRequest", op.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Contains(t, op.Responses, "200")
+	assert.Equal(t, "#/components/responses/"+problemResponse, op.Responses["4XX"].Ref)
+
+	// Reads take no Idempotency-Key, and public routes no credentials
+	tiers := doc.Paths["/api/v1/kyc/tiers"]["get"]
+	assert.Empty(t, tiers.Security)
+	assert.Empty(t, tiers.Parameters)
+
+	deleted := doc.Paths["/api/v1/beneficiaries/{id}"]["delete"]
+	assert.Empty(t, deleted.Responses["204"].Content)
+}
+
+type RuleSample struct {
+	Email  string   `json:"email" binding:"required,email"`
+	Pin    string   `json:"pin" binding:"required,len=4,numeric"`
+	Role   string   `json:"role,omitempty" binding:"omitempty,oneof=user admin"`
+	Amount int64    `json:"amount" binding:"gt=0,max=500"`
+	Note   *string  `json:"note"`
+	Tags   []string `json:"tags" binding:"max=3"`
+	Skip   string   `json:"-"`
+	hidden string
+}
+
+func TestGenerator_BindingRules(t *testing.T) {
+	g := newGenerator()
+	ref := g.schemaOf(reflect.TypeOf(RuleSample{}))
+	require.NoError(t, g.err)
+	assert.Equal(t, componentPrefix+"OpenapiRuleSample", ref.Ref)
+
+	s := g.schemas["OpenapiRuleSample"]
+	assert.Equal(t, []string{"email", "pin"}, s.Required)
+	assert.Len(t, s.Properties, 6)
+	assert.Equal(t, "email", s.Properties["email"].Format)
+	assert.Equal(t, int64(4), *s.Properties["pin"].MinLength)
+	assert.Equal(t, int64(4), *s.Properties["pin"].MaxLength)
+	assert.Equal(t, "^[0-9]+$", s.Properties["pin"].Pattern)
+	assert.Equal(t, []interface{}{"user", "admin"}, s.Properties["role"].Enum)
+	assert.Equal(t, float64(0), *s.Properties["amount"].ExclusiveMinimum)
+	assert.Equal(t, float64(500), *s.Properties["amount"].Maximum)
+	assert.Equal(t, int64(3), *s.Properties["tags"].MaxItems)
+
+	raw, err := json.Marshal(s.Properties["note"])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":["string","null"]}`, string(raw))
+}
+
+func TestGenerator_UnknownRuleFails(t *testing.T) {
+	type sample struct {
+		URL string `json:"url" binding:"required,url"`
+	}
+	g := newGenerator()
+	g.schemaOf(reflect.TypeOf(sample{}))
+	assert.ErrorContains(t, g.err, `"url"`)
+}
+
+func TestOperationID(t *testing.T) {
+	assert.Equal(t, "getWellKnownJwksJson", operationID(http.MethodGet, "/.well-known/jwks.json"))
+	assert.Equal(t, "getAdminAccountsByAccountNumber", operationID(http.MethodGet, "/api/v1/admin/accounts/:account_number"))
+	assert.Equal(t, "/api/v1/admin/risk/rules/{code}", specPath("/api/v1/admin/risk/rules/:code"))
+}
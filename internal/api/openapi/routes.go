@@ -0,0 +1,227 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/Brownie44l1/debank/internal/health"
+)
+
+// ==============================================
+// ROUTES
+// ==============================================
+// Every route api.NewRouter registers is listed here with the DTOs its
+// handler binds and returns. openapi_test.go fails when a registered route is
+// missing from this table, when the table lists a route that no longer
+// exists, or when a dto struct is not reachable from any route.
+
+// access says who may call a route
+type access int
+
+const (
+	public   access = iota // No credentials
+	user                   // Access token
+	staff                  // Access token of a staff role holding the route's permission
+	operator               // Metrics token when one is configured; health checks answer without it but hide details
+)
+
+// route describes one endpoint
+type route struct {
+	method, path string // Gin path, e.g. /api/v1/users/:id
+	id           string // operationId; derived from the method and path when empty
+	tag, summary string
+	access       access
+	permission   auth.Permission // Required by staff routes beyond admin:access
+
+	query    interface{} // Struct bound with ShouldBindQuery
+	body     interface{} // Struct bound with ShouldBindJSON
+	form     interface{} // Struct bound from multipart fields
+	formFile string      // Name of the multipart file field; may be sent without form
+
+	status      int         // Success status; 200 when zero
+	response    interface{} // Success body, JSON unless contentType says otherwise
+	contentType string      // Media type of a non-JSON success body; alone when it has no schema
+}
+
+const (
+	tagOperations    = "Operations"
+	tagAuth          = "Auth"
+	tagMFA           = "MFA"
+	tagSecurity      = "Security"
+	tagWallet        = "Wallet"
+	tagEvents        = "Events"
+	tagPayments      = "Payment requests"
+	tagQR            = "QR"
+	tagBeneficiaries = "Beneficiaries"
+	tagKYC           = "KYC"
+	tagAdminUsers    = "Admin: users"
+	tagAdminKYC      = "Admin: KYC"
+	tagAdminRisk     = "Admin: risk"
+	tagAdminAML      = "Admin: AML"
+	tagAdminScreen   = "Admin: screening"
+	tagAdminAudit    = "Admin: audit"
+	tagAdminJournal  = "Admin: journal entries"
+)
+
+// tags lists the tags in the order the docs UI shows them
+var tags = []Tag{
+	{Name: tagAuth, Description: "Signup, login and account credentials"},
+	{Name: tagMFA, Description: "Second factors, recovery codes and step-up verification"},
+	{Name: tagSecurity, Description: "Devices, sessions and the user's security activity"},
+	{Name: tagWallet, Description: "Balance, deposits, withdrawals and transfers. Amounts are in kobo."},
+	{Name: tagEvents, Description: "Live transaction and balance events over SSE or WebSocket, resumable by event ID"},
+	{Name: tagPayments, Description: "Requesting money and paying requests"},
+	{Name: tagQR, Description: "Payment QR codes"},
+	{Name: tagBeneficiaries, Description: "Saved and recent recipients"},
+	{Name: tagKYC, Description: "Identity verification tiers and limits"},
+	{Name: tagAdminUsers, Description: "Support tooling; every call is audit logged"},
+	{Name: tagAdminKYC},
+	{Name: tagAdminRisk},
+	{Name: tagAdminAML},
+	{Name: tagAdminScreen},
+	{Name: tagAdminAudit, Description: "Audit log search and hash chain verification"},
+	{Name: tagAdminJournal, Description: "Manual postings between accounts; one staff member submits, another approves"},
+	{Name: tagOperations, Description: "Health checks, metrics, signing keys and these docs"},
+}
+
+var routes = []route{
+	// Operations
+	{method: http.MethodGet, path: "/livez", tag: tagOperations, access: operator, summary: "Liveness check", response: health.Report{}},
+	{method: http.MethodGet, path: "/readyz", tag: tagOperations, access: operator, summary: "Readiness check", response: health.Report{}},
+	{method: http.MethodGet, path: "/health", tag: tagOperations, access: operator, summary: "Liveness check (alias of /livez)", response: health.Report{}},
+	{method: http.MethodGet, path: "/ready", tag: tagOperations, access: operator, summary: "Readiness check (alias of /readyz)", response: health.Report{}},
+	{method: http.MethodGet, path: "/api/v1/health", id: "getApiHealth", tag: tagOperations, access: operator, summary: "Liveness check (alias of /livez)", response: health.Report{}},
+	{method: http.MethodGet, path: "/metrics", tag: tagOperations, access: operator, summary: "Prometheus metrics", contentType: "text/plain"},
+	{method: http.MethodGet, path: "/.well-known/jwks.json", tag: tagOperations, summary: "Public keys that verify access tokens", response: auth.JWKS{}},
+	{method: http.MethodGet, path: "/openapi.json", tag: tagOperations, summary: "This OpenAPI document", contentType: "application/json"},
+	{method: http.MethodGet, path: "/docs", tag: tagOperations, summary: "Interactive API docs", contentType: "text/html"},
+
+	// Auth
+	{method: http.MethodPost, path: "/api/v1/auth/signup", tag: tagAuth, summary: "Create an account", body: dto.SignupRequest{}, status: http.StatusCreated, response: dto.SignupResponse{}},
+	{method: http.MethodPost, path: "/api/v1/auth/verify-email", tag: tagAuth, summary: "Verify the signup email with its OTP", body: dto.VerifyEmailRequest{}, response: dto.VerifyEmailResponse{}},
+	{method: http.MethodPost, path: "/api/v1/auth/resend-otp", tag: tagAuth, summary: "Resend the email verification OTP", body: dto.ResendOTPRequest{}, response: dto.ResendOTPResponse{}},
+	{method: http.MethodPost, path: "/api/v1/auth/login", tag: tagAuth, summary: "Log in", body: dto.LoginRequest{}, response: dto.LoginResponse{}},
+	{method: http.MethodPost, path: "/api/v1/auth/login/mfa", tag: tagAuth, summary: "Finish a login with a second factor", body: dto.MFALoginRequest{}, response: dto.LoginResponse{}},
+	{method: http.MethodPost, path: "/api/v1/auth/login/mfa/email", tag: tagAuth, summary: "Email a login code instead of using the authenticator", body: dto.MFAEmailFallbackRequest{}, response: dto.MFACodeSentResponse{}},
+	{method: http.MethodPost, path: "/api/v1/auth/forgot-password", tag: tagAuth, summary: "Email a password reset OTP", body: dto.ForgotPasswordRequest{}, response: dto.ForgotPasswordResponse{}},
+	{method: http.MethodPost, path: "/api/v1/auth/reset-password", tag: tagAuth, summary: "Reset the password with an OTP", body: dto.ResetPasswordRequest{}, response: dto.ResetPasswordResponse{}},
+	{method: http.MethodPost, path: "/api/v1/auth/onboarding", tag: tagAuth, summary: "Complete the profile after signup", access: user, body: dto.CompleteOnboardingRequest{}, response: dto.CompleteOnboardingResponse{}},
+	{method: http.MethodPost, path: "/api/v1/auth/change-password", tag: tagAuth, summary: "Change the password", access: user, body: dto.ChangePasswordRequest{}, response: dto.ChangePasswordResponse{}},
+	{method: http.MethodPost, path: "/api/v1/auth/pin", tag: tagAuth, summary: "Set or change the transaction PIN", access: user, body: dto.SetPinRequest{}, response: dto.SetPinResponse{}},
+	{method: http.MethodPost, path: "/api/v1/auth/change-email", tag: tagAuth, summary: "Change the email address", access: user, body: dto.ChangeEmailRequest{}, response: dto.ChangeContactResponse{}},
+	{method: http.MethodPost, path: "/api/v1/auth/change-phone", tag: tagAuth, summary: "Change the phone number", access: user, body: dto.ChangePhoneRequest{}, response: dto.ChangeContactResponse{}},
+
+	// MFA and step-up
+	{method: http.MethodGet, path: "/api/v1/auth/mfa", tag: tagMFA, summary: "Second factor status", access: user, response: dto.MFAStatusResponse{}},
+	{method: http.MethodPost, path: "/api/v1/auth/mfa/totp", tag: tagMFA, summary: "Start authenticator app enrollment", access: user, response: dto.TOTPEnrollmentResponse{}},
+	{method: http.MethodPost, path: "/api/v1/auth/mfa/totp/confirm", tag: tagMFA, summary: "Confirm authenticator app enrollment", access: user, body: dto.ConfirmTOTPRequest{}, response: dto.RecoveryCodesResponse{}},
+	{method: http.MethodPost, path: "/api/v1/auth/mfa/email", tag: tagMFA, summary: "Use emailed codes as the second factor", access: user, body: dto.EnableEmailMFARequest{}, response: dto.RecoveryCodesResponse{}},
+	{method: http.MethodPost, path: "/api/v1/auth/mfa/code", tag: tagMFA, summary: "Email a second factor code", access: user, response: dto.MFACodeSentResponse{}},
+	{method: http.MethodPost, path: "/api/v1/auth/mfa/disable", tag: tagMFA, summary: "Turn off the second factor", access: user, body: dto.MFAReauthRequest{}, status: http.StatusNoContent},
+	{method: http.MethodPost, path: "/api/v1/auth/mfa/recovery-codes", tag: tagMFA, summary: "Replace the recovery codes", access: user, body: dto.MFAReauthRequest{}, response: dto.RecoveryCodesResponse{}},
+	{method: http.MethodPost, path: "/api/v1/auth/step-up/email", tag: tagMFA, summary: "Email a step-up code for a risky action", access: user, body: dto.StepUpEmailRequest{}, response: dto.MFACodeSentResponse{}},
+
+	// Security
+	{method: http.MethodGet, path: "/api/v1/devices", tag: tagSecurity, summary: "List signed-in devices", access: user, response: dto.DeviceListResponse{}},
+	{method: http.MethodPatch, path: "/api/v1/devices/:id", tag: tagSecurity, summary: "Rename a device", access: user, body: dto.RenameDeviceRequest{}, response: dto.DeviceDTO{}},
+	{method: http.MethodDelete, path: "/api/v1/devices/:id", tag: tagSecurity, summary: "Sign a device out", access: user, response: dto.RevokeDeviceResponse{}},
+	{method: http.MethodGet, path: "/api/v1/security/activity", tag: tagSecurity, summary: "Recent security events on the account", access: user, query: dto.ListSecurityActivityRequest{}, response: dto.SecurityActivityResponse{}},
+
+	// Wallet
+	{method: http.MethodPost, path: "/api/v1/deposit", tag: tagWallet, summary: "Deposit funds", access: user, body: dto.DepositRequest{}, response: dto.TransactionResponse{}},
+	{method: http.MethodPost, path: "/api/v1/withdraw", tag: tagWallet, summary: "Withdraw funds", access: user, body: dto.WithdrawRequest{}, response: dto.TransactionResponse{}},
+	{method: http.MethodPost, path: "/api/v1/transfer", tag: tagWallet, summary: "Send money to another account", access: user, body: dto.TransferRequest{}, response: dto.TransferResponse{}},
+	{method: http.MethodGet, path: "/api/v1/balance", tag: tagWallet, summary: "Account balance", access: user, response: dto.BalanceResponse{}},
+	{method: http.MethodGet, path: "/api/v1/transactions", tag: tagWallet, summary: "Transaction history", access: user, query: dto.PaginationRequest{}, response: dto.TransactionHistoryResponse{}},
+
+	// Events
+	{method: http.MethodGet, path: "/api/v1/events", tag: tagEvents, summary: "Stream your account events as Server-Sent Events", access: user, query: dto.EventStreamRequest{}, response: dto.AccountEvent{}, contentType: "text/event-stream"},
+	{method: http.MethodGet, path: "/api/v1/events/ws", tag: tagEvents, summary: "Stream your account events over a WebSocket", access: user, query: dto.EventStreamRequest{}, status: http.StatusSwitchingProtocols},
+
+	// Payment requests
+	{method: http.MethodGet, path: "/api/v1/pay/:token", tag: tagPayments, summary: "Look up a shared payment link", response: dto.PaymentRequestDTO{}},
+	{method: http.MethodPost, path: "/api/v1/pay/:token", tag: tagPayments, summary: "Pay a shared payment link", access: user, body: dto.PayPaymentRequestRequest{}, response: dto.PayPaymentRequestResponse{}},
+	{method: http.MethodPost, path: "/api/v1/payment-requests", tag: tagPayments, summary: "Request money", access: user, body: dto.CreatePaymentRequestRequest{}, status: http.StatusCreated, response: dto.PaymentRequestDTO{}},
+	{method: http.MethodGet, path: "/api/v1/payment-requests", tag: tagPayments, summary: "List sent or received requests", access: user, query: dto.ListPaymentRequestsRequest{}, response: dto.PaymentRequestListResponse{}},
+	{method: http.MethodGet, path: "/api/v1/payment-requests/:id", tag: tagPayments, summary: "Get a payment request", access: user, response: dto.PaymentRequestDTO{}},
+	{method: http.MethodPost, path: "/api/v1/payment-requests/:id/pay", tag: tagPayments, summary: "Pay a request sent to you", access: user, body: dto.PayPaymentRequestRequest{}, response: dto.PayPaymentRequestResponse{}},
+	{method: http.MethodPost, path: "/api/v1/payment-requests/:id/decline", tag: tagPayments, summary: "Decline a request sent to you", access: user, body: dto.DeclinePaymentRequestRequest{}, response: dto.PaymentRequestDTO{}},
+
+	// QR
+	{method: http.MethodPost, path: "/api/v1/qr", tag: tagQR, summary: "Generate a payment QR code", access: user, body: dto.GenerateQRRequest{}, response: dto.QRCodeResponse{}},
+	{method: http.MethodGet, path: "/api/v1/qr.png", tag: tagQR, summary: "Generate a payment QR code as a PNG", access: user, query: dto.GenerateQRRequest{}, contentType: "image/png"},
+	{method: http.MethodPost, path: "/api/v1/qr/decode", tag: tagQR, summary: "Check a scanned QR payload before paying it", access: user, body: dto.DecodeQRRequest{}, response: dto.DecodeQRResponse{}},
+
+	// Beneficiaries
+	{method: http.MethodGet, path: "/api/v1/name-enquiry", tag: tagBeneficiaries, summary: "Resolve an account holder's name", access: user, query: dto.NameEnquiryRequest{}, response: dto.NameEnquiryResponse{}},
+	{method: http.MethodGet, path: "/api/v1/beneficiaries", tag: tagBeneficiaries, summary: "List saved beneficiaries", access: user, response: dto.BeneficiaryListResponse{}},
+	{method: http.MethodPost, path: "/api/v1/beneficiaries", tag: tagBeneficiaries, summary: "Save a beneficiary", access: user, body: dto.CreateBeneficiaryRequest{}, status: http.StatusCreated, response: dto.BeneficiaryDTO{}},
+	{method: http.MethodDelete, path: "/api/v1/beneficiaries/:id", tag: tagBeneficiaries, summary: "Remove a beneficiary", access: user, status: http.StatusNoContent},
+	{method: http.MethodGet, path: "/api/v1/recipients", tag: tagBeneficiaries, summary: "Recent and frequent recipients", access: user, query: dto.ListRecipientsRequest{}, response: dto.RecipientListResponse{}},
+
+	// KYC
+	{method: http.MethodGet, path: "/api/v1/kyc/tiers", tag: tagKYC, summary: "KYC tiers and their limits", response: dto.KYCTiersResponse{}},
+	{method: http.MethodGet, path: "/api/v1/kyc/limits", tag: tagKYC, summary: "Your tier, limits and usage", access: user, response: dto.KYCLimitsResponse{}},
+	{method: http.MethodPost, path: "/api/v1/kyc/submissions", tag: tagKYC, summary: "Apply for a higher tier", access: user, body: dto.SubmitKYCRequest{}, status: http.StatusCreated, response: dto.KYCSubmissionDTO{}},
+	{method: http.MethodGet, path: "/api/v1/kyc/submissions", tag: tagKYC, summary: "List your submissions", access: user, response: dto.KYCSubmissionListResponse{}},
+	{method: http.MethodPost, path: "/api/v1/kyc/submissions/:id/documents", tag: tagKYC, summary: "Upload a document to a submission", access: user, form: dto.UploadKYCDocumentRequest{}, formFile: "file", status: http.StatusCreated, response: dto.KYCDocumentDTO{}},
+
+	// Admin: users
+	{method: http.MethodGet, path: "/api/v1/admin/users", tag: tagAdminUsers, summary: "Search users", access: staff, permission: auth.PermUsersRead, query: dto.SearchUsersRequest{}, response: dto.AdminUserListResponse{}},
+	{method: http.MethodGet, path: "/api/v1/admin/users/:id", tag: tagAdminUsers, summary: "Get a user", access: staff, permission: auth.PermUsersRead, response: dto.AdminUserDTO{}},
+	{method: http.MethodGet, path: "/api/v1/admin/accounts/:account_number", tag: tagAdminUsers, summary: "Get an account", access: staff, permission: auth.PermUsersRead, response: dto.AdminAccountDTO{}},
+	{method: http.MethodGet, path: "/api/v1/admin/transactions/:reference", tag: tagAdminUsers, summary: "Get a transaction", access: staff, permission: auth.PermTransactionsRead, response: dto.AdminTransactionDTO{}},
+	{method: http.MethodPost, path: "/api/v1/admin/users/:id/lock", tag: tagAdminUsers, summary: "Lock a user out", access: staff, permission: auth.PermUsersLock, body: dto.LockUserRequest{}, response: dto.AdminUserDTO{}},
+	{method: http.MethodPost, path: "/api/v1/admin/users/:id/unlock", tag: tagAdminUsers, summary: "Unlock a user", access: staff, permission: auth.PermUsersLock, body: dto.UnlockUserRequest{}, response: dto.AdminUserDTO{}},
+	{method: http.MethodPut, path: "/api/v1/admin/users/:id/role", tag: tagAdminUsers, summary: "Change a user's role", access: staff, permission: auth.PermRolesManage, body: dto.SetRoleRequest{}, response: dto.AdminUserDTO{}},
+
+	// Admin: KYC
+	{method: http.MethodGet, path: "/api/v1/admin/kyc/submissions", tag: tagAdminKYC, summary: "Review queue", access: staff, permission: auth.PermKYCReview, query: dto.ListKYCSubmissionsRequest{}, response: dto.KYCSubmissionListResponse{}},
+	{method: http.MethodGet, path: "/api/v1/admin/kyc/submissions/:id", tag: tagAdminKYC, summary: "Get a submission for review", access: staff, permission: auth.PermKYCReview, response: dto.KYCSubmissionDTO{}},
+	{method: http.MethodPost, path: "/api/v1/admin/kyc/submissions/:id/approve", tag: tagAdminKYC, summary: "Approve a submission", access: staff, permission: auth.PermKYCReview, body: dto.ReviewKYCSubmissionRequest{}, response: dto.KYCSubmissionDTO{}},
+	{method: http.MethodPost, path: "/api/v1/admin/kyc/submissions/:id/reject", tag: tagAdminKYC, summary: "Reject a submission", access: staff, permission: auth.PermKYCReview, body: dto.ReviewKYCSubmissionRequest{}, response: dto.KYCSubmissionDTO{}},
+	{method: http.MethodGet, path: "/api/v1/admin/kyc/documents/:id", tag: tagAdminKYC, summary: "Download a document", access: staff, permission: auth.PermKYCReview, contentType: "application/octet-stream"},
+
+	// Admin: risk
+	{method: http.MethodGet, path: "/api/v1/admin/risk/rules", tag: tagAdminRisk, summary: "List risk rules", access: staff, permission: auth.PermRiskRead, response: dto.RiskRulesResponse{}},
+	{method: http.MethodPatch, path: "/api/v1/admin/risk/rules/:code", tag: tagAdminRisk, summary: "Tune a risk rule", access: staff, permission: auth.PermRiskManage, body: dto.UpdateRiskRuleRequest{}, response: dto.RiskRuleDTO{}},
+	{method: http.MethodGet, path: "/api/v1/admin/risk/decisions", tag: tagAdminRisk, summary: "List risk decisions", access: staff, permission: auth.PermRiskRead, query: dto.ListRiskDecisionsRequest{}, response: dto.RiskDecisionListResponse{}},
+	{method: http.MethodGet, path: "/api/v1/admin/risk/decisions/:id", tag: tagAdminRisk, summary: "Get a risk decision", access: staff, permission: auth.PermRiskRead, response: dto.RiskDecisionDTO{}},
+
+	// Admin: AML
+	{method: http.MethodPost, path: "/api/v1/admin/aml/scans", tag: tagAdminAML, summary: "Run the AML scan now", access: staff, permission: auth.PermAMLManage, response: dto.AMLScanResponse{}},
+	{method: http.MethodGet, path: "/api/v1/admin/aml/cases", tag: tagAdminAML, summary: "List AML cases", access: staff, permission: auth.PermAMLManage, query: dto.ListAMLCasesRequest{}, response: dto.AMLCaseListResponse{}},
+	{method: http.MethodGet, path: "/api/v1/admin/aml/cases/:id", tag: tagAdminAML, summary: "Get an AML case", access: staff, permission: auth.PermAMLManage, response: dto.AMLCaseDTO{}},
+	{method: http.MethodPost, path: "/api/v1/admin/aml/cases/:id/assign", tag: tagAdminAML, summary: "Assign a case", access: staff, permission: auth.PermAMLManage, body: dto.AssignAMLCaseRequest{}, response: dto.AMLCaseDTO{}},
+	{method: http.MethodPost, path: "/api/v1/admin/aml/cases/:id/notes", tag: tagAdminAML, summary: "Add a note to a case", access: staff, permission: auth.PermAMLManage, body: dto.AddAMLCaseNoteRequest{}, response: dto.AMLCaseNoteDTO{}},
+	{method: http.MethodPost, path: "/api/v1/admin/aml/cases/:id/escalate", tag: tagAdminAML, summary: "Escalate a case", access: staff, permission: auth.PermAMLManage, body: dto.EscalateAMLCaseRequest{}, response: dto.AMLCaseDTO{}},
+	{method: http.MethodPost, path: "/api/v1/admin/aml/cases/:id/close", tag: tagAdminAML, summary: "Close a case", access: staff, permission: auth.PermAMLManage, body: dto.CloseAMLCaseRequest{}, response: dto.AMLCaseDTO{}},
+
+	// Admin: screening
+	{method: http.MethodGet, path: "/api/v1/admin/screening/hits", tag: tagAdminScreen, summary: "List watchlist hits", access: staff, permission: auth.PermScreeningManage, query: dto.ListScreeningHitsRequest{}, response: dto.ScreeningHitListResponse{}},
+	{method: http.MethodGet, path: "/api/v1/admin/screening/hits/:id", tag: tagAdminScreen, summary: "Get a watchlist hit", access: staff, permission: auth.PermScreeningManage, response: dto.ScreeningHitDTO{}},
+	{method: http.MethodPost, path: "/api/v1/admin/screening/hits/:id/clear", tag: tagAdminScreen, summary: "Clear a hit as a false positive", access: staff, permission: auth.PermScreeningManage, body: dto.ReviewScreeningHitRequest{}, response: dto.ScreeningHitDTO{}},
+	{method: http.MethodPost, path: "/api/v1/admin/screening/hits/:id/confirm", tag: tagAdminScreen, summary: "Confirm a hit as a true match", access: staff, permission: auth.PermScreeningManage, body: dto.ReviewScreeningHitRequest{}, response: dto.ScreeningHitDTO{}},
+	{method: http.MethodGet, path: "/api/v1/admin/screening/lists", tag: tagAdminScreen, summary: "Loaded watchlists", access: staff, permission: auth.PermScreeningManage, response: dto.WatchlistStatusResponse{}},
+	{method: http.MethodPost, path: "/api/v1/admin/screening/lists/reload", tag: tagAdminScreen, summary: "Reload the watchlists", access: staff, permission: auth.PermScreeningManage, response: dto.WatchlistStatusResponse{}},
+
+	// Admin: journal entries
+	{method: http.MethodPost, path: "/api/v1/admin/journal-entries", tag: tagAdminJournal, summary: "Submit a journal entry for approval", access: staff, permission: auth.PermLedgerManage, body: dto.SubmitJournalEntryRequest{}, status: http.StatusCreated, response: dto.JournalEntryDTO{}},
+	{method: http.MethodGet, path: "/api/v1/admin/journal-entries", tag: tagAdminJournal, summary: "Approval queue", access: staff, permission: auth.PermLedgerManage, query: dto.ListJournalEntriesRequest{}, response: dto.JournalEntryListResponse{}},
+	{method: http.MethodGet, path: "/api/v1/admin/journal-entries/:id", tag: tagAdminJournal, summary: "Get a journal entry", access: staff, permission: auth.PermLedgerManage, response: dto.JournalEntryDTO{}},
+	{method: http.MethodPost, path: "/api/v1/admin/journal-entries/:id/attachments", tag: tagAdminJournal, summary: "Attach a supporting document", access: staff, permission: auth.PermLedgerManage, formFile: "file", status: http.StatusCreated, response: dto.JournalAttachmentDTO{}},
+	{method: http.MethodPost, path: "/api/v1/admin/journal-entries/:id/approve", tag: tagAdminJournal, summary: "Approve and post an entry", access: staff, permission: auth.PermLedgerManage, body: dto.ReviewJournalEntryRequest{}, response: dto.JournalEntryDTO{}},
+	{method: http.MethodPost, path: "/api/v1/admin/journal-entries/:id/reject", tag: tagAdminJournal, summary: "Reject an entry", access: staff, permission: auth.PermLedgerManage, body: dto.ReviewJournalEntryRequest{}, response: dto.JournalEntryDTO{}},
+	{method: http.MethodGet, path: "/api/v1/admin/journal-attachments/:id", tag: tagAdminJournal, summary: "Download an attachment", access: staff, permission: auth.PermLedgerManage, contentType: "application/octet-stream"},
+
+	// Admin: audit
+	{method: http.MethodGet, path: "/api/v1/admin/audit-logs", tag: tagAdminAudit, summary: "Search the audit log", access: staff, permission: auth.PermAuditRead, query: dto.SearchAuditLogsRequest{}, response: dto.AuditLogListResponse{}},
+	{method: http.MethodGet, path: "/api/v1/admin/integrity/verify", tag: tagAdminAudit, summary: "Verify a hash chain", access: staff, permission: auth.PermAuditRead, query: dto.VerifyChainRequest{}, response: dto.ChainVerificationResponse{}},
+	{method: http.MethodGet, path: "/api/v1/admin/integrity/checkpoints", tag: tagAdminAudit, summary: "List chain checkpoints", access: staff, permission: auth.PermAuditRead, query: dto.ListCheckpointsRequest{}, response: dto.ChainCheckpointListResponse{}},
+}
+
+// problemExtensions are the extension members some problems add, keyed by member name
+var problemExtensions = map[string]interface{}{
+	"step_up": dto.StepUpChallengeResponse{}, // With STEP_UP_REQUIRED: repeat the request with the token and a code
+}
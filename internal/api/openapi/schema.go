@@ -0,0 +1,305 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ==============================================
+// SCHEMA GENERATION
+// ==============================================
+// Structs become components named after the Go type and are referenced with
+// $ref. Types outside the dto package are prefixed with their package name
+// ("health.Report" -> "HealthReport") unless the name already starts with it.
+// Gin binding rules become JSON Schema keywords; a rule the generator does
+// not know is an error, so the spec never silently under-describes a DTO.
+
+const (
+	componentPrefix = "#/components/schemas/"
+	dtoPackage      = "dto"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// ignoredRules are binding rules with no JSON Schema equivalent
+// Cross-field rules and ne are checked by the server only; dive hands
+// slice elements to their own struct's rules, which get their own schema.
+var ignoredRules = map[string]bool{
+	"omitempty":        true,
+	"eqfield":          true,
+	"required_without": true,
+	"ne":               true,
+	"dive":             true,
+}
+
+// generator builds schemas and collects the components they reference
+type generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+	err     error
+}
+
+func newGenerator() *generator {
+	return &generator{schemas: make(map[string]*Schema), names: make(map[reflect.Type]string)}
+}
+
+func (g *generator) fail(format string, args ...interface{}) {
+	if g.err == nil {
+		g.err = fmt.Errorf(format, args...)
+	}
+}
+
+// schemaOf returns the schema for values of t as encoding/json writes them
+func (g *generator) schemaOf(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &Schema{} // Any JSON value
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(g.schemaOf(t.Elem()))
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"} // encoding/json writes []byte as base64
+		}
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			g.fail("openapi: map key of %s is not a string", t)
+			return &Schema{}
+		}
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		return g.component(t)
+	}
+
+	g.fail("openapi: %s cannot be described", t)
+	return &Schema{}
+}
+
+// component registers struct t as a component and returns a reference to it
+func (g *generator) component(t reflect.Type) *Schema {
+	if name, ok := g.names[t]; ok {
+		return &Schema{Ref: componentPrefix + name}
+	}
+
+	name := componentName(t)
+	if _, taken := g.schemas[name]; taken {
+		g.fail("openapi: component name %s is used by two types", name)
+	}
+	g.names[t] = name
+	g.schemas[name] = &Schema{} // Placeholder so recursive types terminate
+	g.schemas[name] = g.objectOf(t, "json")
+	return &Schema{Ref: componentPrefix + name}
+}
+
+func componentName(t reflect.Type) string {
+	pkg := t.PkgPath()
+	pkg = pkg[strings.LastIndex(pkg, "/")+1:]
+	if pkg == dtoPackage || strings.HasPrefix(strings.ToLower(t.Name()), pkg) {
+		return t.Name()
+	}
+	return strings.ToUpper(pkg[:1]) + pkg[1:] + t.Name()
+}
+
+// objectOf describes struct t, naming fields by tag ("json" or "form") as Gin binds them
+func (g *generator) objectOf(t reflect.Type, tag string) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, f := range fieldsOf(t, tag) {
+		s.Properties[f.name] = g.fieldSchema(t, f)
+		if f.required {
+			s.Required = append(s.Required, f.name)
+		}
+	}
+	sort.Strings(s.Required)
+	return s
+}
+
+// fieldSchema is the schema of f's type narrowed by its binding rules
+func (g *generator) fieldSchema(owner reflect.Type, f field) *Schema {
+	t := f.Type
+	pointer := t.Kind() == reflect.Pointer
+	if pointer {
+		t = t.Elem()
+	}
+
+	s := g.schemaOf(t)
+	g.applyRules(s, t, f.rules, owner.Name()+"."+f.Name)
+
+	// encoding/json writes a nil pointer as null unless the field is omitted when empty
+	if pointer && !f.omitEmpty {
+		s = nullable(s)
+	}
+	return s
+}
+
+// nullable allows null in addition to s
+func nullable(s *Schema) *Schema {
+	if s.Ref != "" {
+		return &Schema{AnyOf: []*Schema{s, {Type: "null"}}}
+	}
+	if s.Type != "" {
+		s.Nullable = true
+	}
+	return s
+}
+
+// applyRules turns Gin binding rules into schema keywords
+func (g *generator) applyRules(s *Schema, t reflect.Type, rules []string, where string) {
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			// Reported as required by objectOf and parameters
+		case "email":
+			s.Format = "email"
+		case "numeric":
+			s.Pattern = "^[0-9]+$"
+		case "alphanum":
+			s.Pattern = "^[a-zA-Z0-9]+$"
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				s.Enum = append(s.Enum, g.enumValue(t, v, where))
+			}
+		case "len", "min", "max", "gt", "gte", "lt", "lte":
+			g.applyBound(s, t, name, param, where)
+		default:
+			if !ignoredRules[name] {
+				g.fail("openapi: binding rule %q on %s has no schema equivalent", rule, where)
+			}
+		}
+	}
+}
+
+func (g *generator) enumValue(t reflect.Type, v, where string) interface{} {
+	if t.Kind() == reflect.String {
+		return v
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		g.fail("openapi: oneof value %q on %s is not a number", v, where)
+	}
+	return n
+}
+
+// applyBound maps a size rule to length, item count or value bounds depending on the kind
+func (g *generator) applyBound(s *Schema, t reflect.Type, name, param, where string) {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		g.fail("openapi: %s=%s on %s is not a number", name, param, where)
+		return
+	}
+	size := int64(n)
+
+	switch t.Kind() {
+	case reflect.String:
+		switch name {
+		case "len":
+			s.MinLength, s.MaxLength = &size, &size
+		case "min", "gte":
+			s.MinLength = &size
+		case "max", "lte":
+			s.MaxLength = &size
+		default:
+			g.fail("openapi: %s on string %s has no schema equivalent", name, where)
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		switch name {
+		case "len":
+			s.MinItems, s.MaxItems = &size, &size
+		case "min", "gte":
+			s.MinItems = &size
+		case "max", "lte":
+			s.MaxItems = &size
+		default:
+			g.fail("openapi: %s on collection %s has no schema equivalent", name, where)
+		}
+	default:
+		switch name {
+		case "len":
+			s.Minimum, s.Maximum = &n, &n
+		case "min", "gte":
+			s.Minimum = &n
+		case "max", "lte":
+			s.Maximum = &n
+		case "gt":
+			s.ExclusiveMinimum = &n
+		case "lt":
+			s.ExclusiveMaximum = &n
+		}
+	}
+}
+
+// ==============================================
+// STRUCT FIELDS
+// ==============================================
+
+// field is a struct field as it appears on the wire
+type field struct {
+	reflect.StructField
+	name      string
+	omitEmpty bool
+	required  bool
+	rules     []string
+}
+
+// fieldsOf lists the fields of struct t that tag names, flattening embedded structs like encoding/json
+func fieldsOf(t reflect.Type, tag string) []field {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get(tag), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			fields = append(fields, fieldsOf(f.Type, tag)...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		var rules []string
+		if binding := f.Tag.Get("binding"); binding != "" {
+			rules = strings.Split(binding, ",")
+		}
+		fields = append(fields, field{
+			StructField: f,
+			name:        name,
+			omitEmpty:   strings.Contains(opts, "omitempty"),
+			required:    slices.Contains(rules, "required"),
+			rules:       rules,
+		})
+	}
+	return fields
+}
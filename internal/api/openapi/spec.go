@@ -0,0 +1,232 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Brownie44l1/debank/internal/api/problem"
+)
+
+// ==============================================
+// BUILD
+// ==============================================
+
+const (
+	problemResponse      = "Problem"
+	idempotencyParameter = "IdempotencyKey"
+	bearerScheme         = "bearerAuth"
+	metricsScheme        = "metricsToken"
+)
+
+var info = Info{
+	Title:   "Debank API",
+	Version: "v1",
+	Description: "Amounts are in kobo unless a field says otherwise. Every error is an " +
+		"application/problem+json body (RFC 7807) with a stable `code` and the " +
+		"`request_id` to quote to support.",
+}
+
+// Build generates the document from the route table and the dto structs
+func Build() (*Document, error) {
+	g := newGenerator()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Tags:    tags,
+		Paths:   make(map[string]PathItem),
+	}
+
+	ids := make(map[string]string)
+	for _, r := range routes {
+		op := g.operation(r)
+		if other, taken := ids[op.OperationID]; taken {
+			return nil, fmt.Errorf("openapi: %s %s and %s share operationId %s", r.method, r.path, other, op.OperationID)
+		}
+		ids[op.OperationID] = r.method + " " + r.path
+
+		path := specPath(r.path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(PathItem)
+		}
+		doc.Paths[path][strings.ToLower(r.method)] = op
+	}
+
+	problemRef := g.schemaOf(reflect.TypeOf(problem.Problem{}))
+	problemSchema := g.schemas[strings.TrimPrefix(problemRef.Ref, componentPrefix)]
+	for name, v := range problemExtensions {
+		problemSchema.Properties[name] = g.schemaOf(reflect.TypeOf(v))
+	}
+	if g.err != nil {
+		return nil, g.err
+	}
+
+	doc.Components = Components{
+		Schemas: g.schemas,
+		Responses: map[string]Response{
+			problemResponse: {
+				Description: "Problem details",
+				Content:     map[string]MediaType{problem.ContentType: {Schema: problemRef}},
+			},
+		},
+		Parameters: map[string]Parameter{
+			idempotencyParameter: {
+				Name: "Idempotency-Key",
+				In:   "header",
+				Description: "Retrying with the same key and body replays the first response instead of " +
+					"repeating the operation. Keys are scoped to the user and endpoint.",
+				Schema: &Schema{Type: "string", MinLength: int64Ptr(1), MaxLength: int64Ptr(255)},
+			},
+		},
+		SecuritySchemes: map[string]SecurityScheme{
+			bearerScheme: {
+				Type:         "http",
+				Scheme:       "bearer",
+				BearerFormat: "JWT",
+				Description:  "Access token from /api/v1/auth/login",
+			},
+			metricsScheme: {
+				Type:        "http",
+				Scheme:      "bearer",
+				Description: "The server's METRICS_TOKEN",
+			},
+		},
+	}
+	return doc, nil
+}
+
+// JSON is the document as served at /openapi.json
+func JSON() ([]byte, error) {
+	doc, err := Build()
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// operation describes r
+func (g *generator) operation(r route) *Operation {
+	op := &Operation{
+		OperationID: r.id,
+		Tags:        []string{r.tag},
+		Summary:     r.summary,
+		Responses:   make(map[string]Response),
+	}
+	if op.OperationID == "" {
+		op.OperationID = operationID(r.method, r.path)
+	}
+
+	for _, name := range pathParams(r.path) {
+		schema := &Schema{Type: "string"}
+		if name == "id" {
+			schema = &Schema{Type: "integer", Format: "int64", Minimum: float64Ptr(1)}
+		}
+		op.Parameters = append(op.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+	if r.query != nil {
+		t := reflect.TypeOf(r.query)
+		for _, f := range fieldsOf(t, "form") {
+			op.Parameters = append(op.Parameters, Parameter{Name: f.name, In: "query", Required: f.required, Schema: g.fieldSchema(t, f)})
+		}
+	}
+
+	switch r.access {
+	case user, staff:
+		op.Security = []SecurityRequirement{{bearerScheme: {}}}
+		if r.method != http.MethodGet {
+			op.Parameters = append(op.Parameters, Parameter{Ref: "#/components/parameters/" + idempotencyParameter})
+		}
+	case operator:
+		op.Security = []SecurityRequirement{{}, {metricsScheme: {}}}
+	}
+	if r.permission != "" {
+		op.Description = "Requires the `" + string(r.permission) + "` permission."
+	}
+
+	if r.body != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {Schema: g.schemaOf(reflect.TypeOf(r.body))}},
+		}
+	}
+	if r.formFile != "" {
+		form := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		if r.form != nil {
+			form = g.objectOf(reflect.TypeOf(r.form), "form")
+		}
+		form.Properties[r.formFile] = &Schema{Type: "string", Format: "binary"}
+		form.Required = append(form.Required, r.formFile)
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"multipart/form-data": {Schema: form}},
+		}
+	}
+
+	status := r.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := Response{Description: http.StatusText(status)}
+	switch {
+	case r.response != nil:
+		mediaType := r.contentType
+		if mediaType == "" {
+			mediaType = "application/json"
+		}
+		success.Content = map[string]MediaType{mediaType: {Schema: g.schemaOf(reflect.TypeOf(r.response))}}
+	case r.contentType != "":
+		success.Content = map[string]MediaType{r.contentType: {}}
+	}
+	op.Responses[strconv.Itoa(status)] = success
+
+	// Status codes per error are listed by the code in each problem, not per operation
+	ref := Response{Ref: "#/components/responses/" + problemResponse}
+	op.Responses["4XX"] = ref
+	op.Responses["5XX"] = ref
+	return op
+}
+
+// specPath turns a Gin path into an OpenAPI one: /users/:id -> /users/{id}
+func specPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") {
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func pathParams(path string) []string {
+	var names []string
+	for _, s := range strings.Split(path, "/") {
+		if strings.HasPrefix(s, ":") {
+			names = append(names, s[1:])
+		}
+	}
+	return names
+}
+
+// operationID derives an ID from the method and the path below /api/v1:
+// GET /api/v1/payment-requests/:id -> getPaymentRequestsById
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, s := range strings.Split(strings.TrimPrefix(path, "/api/v1"), "/") {
+		if strings.HasPrefix(s, ":") {
+			b.WriteString("By")
+			s = s[1:]
+		}
+		for _, word := range strings.FieldsFunc(s, func(r rune) bool { return r == '-' || r == '_' || r == '.' }) {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
+
+func int64Ptr(n int64) *int64 { return &n }
+
+func float64Ptr(n float64) *float64 { return &n }
@@ -0,0 +1,146 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// ==============================================
+// REQUEST BINDING ERRORS
+// ==============================================
+
+// UseRequestFieldNames makes validation errors name fields as clients send them
+// (the json, form or uri tag) instead of by their Go names. Call once at startup.
+func UseRequestFieldNames() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(requestFieldName)
+	}
+}
+
+func requestFieldName(f reflect.StructField) string {
+	for _, tag := range []string{"json", "form", "uri"} {
+		name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// FromBindError turns an error from c.ShouldBind* (or a *FieldError) into a 400 problem
+// Validation failures are listed per field; malformed bodies only get a detail.
+func FromBindError(err error) Problem {
+	p := New(http.StatusBadRequest, models.ErrCodeValidationFailed, "Invalid request")
+
+	var validationErrs validator.ValidationErrors
+	var fieldErr *FieldError
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+
+	switch {
+	case errors.As(err, &validationErrs):
+		for _, fe := range validationErrs {
+			p.Errors = append(p.Errors, FieldError{
+				Field:   fieldPath(fe.Namespace()),
+				Code:    fe.Tag(),
+				Message: ruleMessage(fe),
+			})
+		}
+		p.Detail = "One or more fields are invalid"
+	case errors.As(err, &fieldErr):
+		p.Errors = []FieldError{*fieldErr}
+		p.Detail = "One or more fields are invalid"
+	case errors.As(err, &typeErr):
+		p.Errors = []FieldError{{Field: typeErr.Field, Code: "type", Message: "must be " + jsonKind(typeErr.Type)}}
+		p.Detail = "One or more fields are invalid"
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		p.Detail = "Request body is not valid JSON"
+	case errors.Is(err, io.EOF):
+		p.Detail = "Request body is empty"
+	default:
+		p.Detail = "Request could not be parsed"
+	}
+	return p
+}
+
+// fieldPath drops the struct name from a validator namespace: "TransferRequest.step_up.code" -> "step_up.code"
+func fieldPath(namespace string) string {
+	if _, rest, found := strings.Cut(namespace, "."); found {
+		return rest
+	}
+	return namespace
+}
+
+// ruleMessage describes a failed validation rule for users
+func ruleMessage(fe validator.FieldError) string {
+	param := fe.Param()
+	switch fe.Tag() {
+	case "required", "required_without":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "numeric":
+		return "must contain only digits"
+	case "alphanum":
+		return "must contain only letters and digits"
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(param), ", ")
+	case "eqfield":
+		return "does not match"
+	case "len":
+		return sizeMessage(fe.Kind(), "exactly", param)
+	case "min":
+		return sizeMessage(fe.Kind(), "at least", param)
+	case "max":
+		return sizeMessage(fe.Kind(), "at most", param)
+	case "gt":
+		return "must be greater than " + param
+	case "gte":
+		return "must be at least " + param
+	case "lt":
+		return "must be less than " + param
+	case "lte":
+		return "must be at most " + param
+	}
+	return "is invalid"
+}
+
+func sizeMessage(kind reflect.Kind, bound, param string) string {
+	switch kind {
+	case reflect.String:
+		return fmt.Sprintf("must be %s %s characters long", bound, param)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return fmt.Sprintf("must contain %s %s items", bound, param)
+	}
+	return fmt.Sprintf("must be %s %s", bound, param)
+}
+
+// jsonKind names the JSON type a Go type is decoded from
+func jsonKind(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a whole number"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	}
+	return "an object"
+}
@@ -0,0 +1,121 @@
+// Package problem writes API errors as RFC 7807 problem details
+// (application/problem+json). Every error response carries a stable
+// machine-readable code, the HTTP status, a message that is safe to show
+// users, and the request ID to quote to support.
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// ContentType is the media type of every error response
+const ContentType = "application/problem+json"
+
+// typePrefix namespaces problem type URIs; the code follows it in lower case
+const typePrefix = "urn:debank:error:"
+
+// Problem is an RFC 7807 problem details object with debank's extension members
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`  // Safe summary; the same for every occurrence of the code
+	Status    int          `json:"status"` // HTTP status
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"` // Request path
+	Code      string       `json:"code"`               // Stable code from the models.ErrCode* constants
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"` // Per-field validation failures
+
+	// Extensions are extra members merged into the top-level object (e.g. "step_up")
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// FieldError is one invalid request field
+type FieldError struct {
+	Field   string `json:"field"`   // JSON, query or form name of the field
+	Code    string `json:"code"`    // Failed rule, e.g. "required", "max"
+	Message string `json:"message"` // e.g. "must be at most 100 characters"
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// New builds a problem; the type URI is derived from the code
+func New(status int, code, title string) Problem {
+	return Problem{Type: TypeURI(code), Title: title, Status: status, Code: code}
+}
+
+// WithDetail returns p with an occurrence-specific message
+func (p Problem) WithDetail(detail string) Problem {
+	p.Detail = detail
+	return p
+}
+
+// With returns p with an extension member set
+func (p Problem) With(key string, value interface{}) Problem {
+	ext := make(map[string]interface{}, len(p.Extensions)+1)
+	for k, v := range p.Extensions {
+		ext[k] = v
+	}
+	ext[key] = value
+	p.Extensions = ext
+	return p
+}
+
+// TypeURI names the problem type for code
+func TypeURI(code string) string {
+	return typePrefix + strings.ToLower(code)
+}
+
+// Internal is the problem for failures whose cause must not reach the client
+func Internal() Problem {
+	return New(http.StatusInternalServerError, models.ErrCodeInternalError, "Internal server error")
+}
+
+// MarshalJSON writes the standard members followed by the extensions
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type plain Problem
+	base, err := json.Marshal(plain(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return base, err
+	}
+
+	members := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(base, &members); err != nil {
+		return nil, err
+	}
+	for k, v := range p.Extensions {
+		if _, taken := members[k]; taken {
+			continue // Extensions never override standard members
+		}
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		members[k] = raw
+	}
+	return json.Marshal(members)
+}
+
+// Abort writes p as the response and stops the handler chain
+// The instance and request ID are filled in from the request.
+func Abort(c *gin.Context, p Problem) {
+	if p.Type == "" {
+		p.Type = TypeURI(p.Code)
+	}
+	if p.Instance == "" {
+		p.Instance = c.Request.URL.Path
+	}
+	if p.RequestID == "" {
+		p.RequestID = requestid.FromContext(c.Request.Context())
+	}
+
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(p.Status, p)
+}
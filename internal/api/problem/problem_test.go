@@ -0,0 +1,160 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	errTooSmall = errors.New("amount too small")
+	errDeclined = errors.New("declined")
+	errBroad    = errors.New("broad")
+)
+
+func testRegistry() *Registry {
+	r := NewRegistry()
+	r.AddWithDetail(http.StatusBadRequest, "AMOUNT_TOO_SMALL", "Amount too small", errTooSmall)
+	r.Add(http.StatusForbidden, "DECLINED", "Declined", errDeclined)
+	r.Add(http.StatusUnprocessableEntity, "BROAD", "Broad", errBroad, errDeclined)
+	return r
+}
+
+func TestRegistry_Lookup(t *testing.T) {
+	r := testRegistry()
+
+	p, ok := r.Lookup(fmt.Errorf("%w: minimum transfer is ₦1.00", errTooSmall))
+	require.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, p.Status)
+	assert.Equal(t, "AMOUNT_TOO_SMALL", p.Code)
+	assert.Equal(t, "urn:debank:error:amount_too_small", p.Type)
+	assert.Equal(t, "Amount too small", p.Title)
+	assert.Equal(t, "amount too small: minimum transfer is ₦1.00", p.Detail)
+
+	// The bare sentinel adds nothing over the title
+	p, _ = r.Lookup(errTooSmall)
+	assert.Empty(t, p.Detail)
+
+	// First registration wins, and wrapped text stays hidden without AddWithDetail
+	p, ok = r.Lookup(fmt.Errorf("%w: rule 12 on db host 10.0.0.3", errDeclined))
+	require.True(t, ok)
+	assert.Equal(t, "DECLINED", p.Code)
+	assert.Empty(t, p.Detail)
+}
+
+func TestRegistry_UnknownErrorIsInternal(t *testing.T) {
+	p, ok := testRegistry().Lookup(errors.New("pq: connection refused"))
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusInternalServerError, p.Status)
+	assert.Equal(t, models.ErrCodeInternalError, p.Code)
+	assert.NotContains(t, p.Title+p.Detail, "connection refused")
+}
+
+func TestRegistry_AppError(t *testing.T) {
+	r := testRegistry()
+
+	p, ok := r.Lookup(models.NewAppError("DECLINED", "Declined by your bank", errors.New("cause")))
+	require.True(t, ok)
+	assert.Equal(t, http.StatusForbidden, p.Status)
+	assert.Equal(t, "Declined by your bank", p.Title)
+
+	p, _ = r.Lookup(models.NewAppError("NEW_CODE", "Something specific", nil))
+	assert.Equal(t, http.StatusBadRequest, p.Status)
+	assert.Equal(t, "NEW_CODE", p.Code)
+}
+
+func TestProblem_MarshalJSON(t *testing.T) {
+	p := New(http.StatusForbidden, "STEP_UP_REQUIRED", "Additional verification required").
+		With("step_up", map[string]string{"token": "t"}).
+		With("code", "OVERRIDE")
+
+	raw, err := json.Marshal(p)
+	require.NoError(t, err)
+
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal(raw, &got))
+	assert.Equal(t, "STEP_UP_REQUIRED", got["code"], "extensions must not replace standard members")
+	assert.Equal(t, map[string]interface{}{"token": "t"}, got["step_up"])
+	assert.Equal(t, float64(http.StatusForbidden), got["status"])
+	assert.NotContains(t, got, "errors")
+}
+
+type signupRequest struct {
+	Email   string  `json:"email" binding:"required,email"`
+	Pin     string  `json:"pin" binding:"required,len=4,numeric"`
+	Role    string  `json:"role" binding:"omitempty,oneof=user admin"`
+	Amount  int64   `json:"amount" binding:"gt=0"`
+	Profile profile `json:"profile"`
+}
+
+type profile struct {
+	Name string `json:"name" binding:"max=5"`
+}
+
+func bindProblem(t *testing.T, body string) Problem {
+	t.Helper()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	var req signupRequest
+	err := c.ShouldBindJSON(&req)
+	require.Error(t, err)
+	return FromBindError(err)
+}
+
+func TestFromBindError_Validation(t *testing.T) {
+	UseRequestFieldNames()
+
+	p := bindProblem(t, `{"email":"nope","pin":"12a","role":"root","amount":0,"profile":{"name":"toolong"}}`)
+	assert.Equal(t, http.StatusBadRequest, p.Status)
+	assert.Equal(t, models.ErrCodeValidationFailed, p.Code)
+	assert.Equal(t, []FieldError{
+		{Field: "email", Code: "email", Message: "must be a valid email address"},
+		{Field: "pin", Code: "len", Message: "must be exactly 4 characters long"},
+		{Field: "role", Code: "oneof", Message: "must be one of: user, admin"},
+		{Field: "amount", Code: "gt", Message: "must be greater than 0"},
+		{Field: "profile.name", Code: "max", Message: "must be at most 5 characters long"},
+	}, p.Errors)
+}
+
+func TestFromBindError_MalformedBody(t *testing.T) {
+	p := bindProblem(t, `{"email":`)
+	assert.Equal(t, "Request body is not valid JSON", p.Detail)
+	assert.Empty(t, p.Errors)
+
+	p = bindProblem(t, `{"amount":"ten"}`)
+	assert.Equal(t, []FieldError{{Field: "amount", Code: "type", Message: "must be a whole number"}}, p.Errors)
+
+	p = FromBindError(&FieldError{Field: "id", Code: "numeric", Message: "must be a number"})
+	assert.Equal(t, []FieldError{{Field: "id", Code: "numeric", Message: "must be a number"}}, p.Errors)
+}
+
+func TestAbort(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/balance?x=1", nil)
+	c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), "req-1"))
+
+	Abort(c, New(http.StatusNotFound, models.ErrCodeAccountNotFound, "Account not found"))
+
+	assert.True(t, c.IsAborted())
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+
+	var got Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, "/api/v1/balance", got.Instance)
+	assert.Equal(t, "req-1", got.RequestID)
+	assert.Equal(t, "urn:debank:error:account_not_found", got.Type)
+}
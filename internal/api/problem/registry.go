@@ -0,0 +1,81 @@
+package problem
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Brownie44l1/debank/internal/models"
+)
+
+// ==============================================
+// ERROR REGISTRY
+// ==============================================
+// A Registry maps domain errors to problems. Entries are matched with
+// errors.Is in registration order, so register specific errors before the
+// sentinels they wrap. Errors that match nothing become a 500 whose cause
+// is only logged.
+
+// entry is one registered mapping
+type entry struct {
+	errs       []error
+	status     int
+	code       string
+	title      string
+	showDetail bool // err.Error() was written for users and may be shown as the detail
+}
+
+// Registry maps domain errors to problems
+type Registry struct {
+	entries []entry
+	codes   map[string]entry // First entry per code, for models.AppError
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{codes: make(map[string]entry)}
+}
+
+// Add maps errs (and anything wrapping them) to status, code and title
+func (r *Registry) Add(status int, code, title string, errs ...error) {
+	r.add(entry{errs: errs, status: status, code: code, title: title})
+}
+
+// AddWithDetail is Add for errors whose message is safe to show, e.g. "minimum transfer is ₦1.00"
+func (r *Registry) AddWithDetail(status int, code, title string, errs ...error) {
+	r.add(entry{errs: errs, status: status, code: code, title: title, showDetail: true})
+}
+
+func (r *Registry) add(e entry) {
+	r.entries = append(r.entries, e)
+	if _, ok := r.codes[e.code]; !ok {
+		r.codes[e.code] = e
+	}
+}
+
+// Lookup returns the problem for err, and false when err is not registered
+// A models.AppError supplies its own code and message; its status comes from
+// the first entry registered with that code, or 400 if there is none.
+func (r *Registry) Lookup(err error) (Problem, bool) {
+	var appErr *models.AppError
+	if errors.As(err, &appErr) {
+		status := http.StatusBadRequest
+		if e, ok := r.codes[appErr.Code]; ok {
+			status = e.status
+		}
+		return New(status, appErr.Code, appErr.Message), true
+	}
+
+	for _, e := range r.entries {
+		for _, target := range e.errs {
+			if !errors.Is(err, target) {
+				continue
+			}
+			p := New(e.status, e.code, e.title)
+			if e.showDetail && err.Error() != target.Error() {
+				p.Detail = err.Error()
+			}
+			return p, true
+		}
+	}
+	return Internal(), false
+}
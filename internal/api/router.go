@@ -1 +1,100 @@
 package api
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/Brownie44l1/debank/internal/api/handlers"
+	"github.com/Brownie44l1/debank/internal/api/middleware"
+	"github.com/Brownie44l1/debank/internal/api/problem"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// Handlers groups every HTTP handler mounted by the router
+type Handlers struct {
+	Health         *handlers.HealthHandler
+	Metrics        *handlers.MetricsHandler
+	JWKS           *handlers.JWKSHandler
+	Docs           *handlers.DocsHandler
+	Auth           *handlers.AuthHandler
+	Wallet         *handlers.WalletHandler
+	PaymentRequest *handlers.PaymentRequestHandler
+	QR             *handlers.QRHandler
+	Beneficiary    *handlers.BeneficiaryHandler
+	KYC            *handlers.KYCHandler
+	Risk           *handlers.RiskHandler
+	AML            *handlers.AMLHandler
+	Screening      *handlers.ScreeningHandler
+	StepUp         *handlers.StepUpHandler
+	Device         *handlers.DeviceHandler
+	Admin          *handlers.AdminHandler
+	Audit          *handlers.AuditHandler
+	Integrity      *handlers.IntegrityHandler
+	Journal        *handlers.JournalHandler
+	Events         *handlers.EventHandler
+}
+
+// NewRouter builds the Gin engine with public, authenticated and admin /api/v1 groups
+// The admin group is open to staff roles; each route then checks its own permission.
+// Authenticated mutating requests honour the Idempotency-Key header.
+// Every error, panics and unknown routes included, is an application/problem+json body.
+func NewRouter(h Handlers, tokens middleware.TokenVerifier, sessions middleware.SessionValidator, keys middleware.IdempotencyStore, idempotency middleware.IdempotencyOptions) *gin.Engine {
+	problem.UseRequestFieldNames()
+
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	router.Use(gin.Logger())
+	router.Use(gin.CustomRecovery(recoverWithProblem))
+	router.NoRoute(routeNotFound)
+	router.Use(middleware.Tracing())
+	router.Use(middleware.Metrics())
+	router.Use(middleware.ClientInfo())
+
+	h.Health.RegisterRoutes(router)
+	h.Metrics.RegisterRoutes(router)
+	h.JWKS.RegisterRoutes(router)
+	h.Docs.RegisterRoutes(router)
+
+	public := router.Group("/api/v1")
+	protected := router.Group("/api/v1")
+	protected.Use(middleware.AuthMiddleware(tokens, sessions))
+	protected.Use(middleware.Idempotency(keys, idempotency))
+	admin := protected.Group("/admin")
+	admin.Use(middleware.RequirePermission(auth.PermAdminAccess))
+
+	h.Auth.RegisterRoutes(public, protected)
+	h.StepUp.RegisterRoutes(public, protected)
+	h.Device.RegisterRoutes(public, protected)
+	h.Audit.RegisterRoutes(public, protected)
+	h.Wallet.RegisterRoutes(public, protected)
+	h.PaymentRequest.RegisterRoutes(public, protected)
+	h.QR.RegisterRoutes(public, protected)
+	h.Beneficiary.RegisterRoutes(public, protected)
+	h.Events.RegisterRoutes(public, protected)
+	h.KYC.RegisterRoutes(public, protected)
+	h.KYC.RegisterAdminRoutes(admin)
+	h.Risk.RegisterAdminRoutes(admin)
+	h.AML.RegisterAdminRoutes(admin)
+	h.Screening.RegisterAdminRoutes(admin)
+	h.Admin.RegisterAdminRoutes(admin)
+	h.Audit.RegisterAdminRoutes(admin)
+	h.Integrity.RegisterAdminRoutes(admin)
+	h.Journal.RegisterAdminRoutes(admin)
+
+	return router
+}
+
+// recoverWithProblem answers a handler panic with a 500 problem
+func recoverWithProblem(c *gin.Context, recovered interface{}) {
+	log.Printf("[API] Panic - RequestID: %s, Route: %s %s: %v",
+		requestid.FromContext(c.Request.Context()), c.Request.Method, c.FullPath(), recovered)
+	problem.Abort(c, problem.Internal())
+}
+
+func routeNotFound(c *gin.Context) {
+	problem.Abort(c, problem.New(http.StatusNotFound, models.ErrCodeNotFound, "Not found").
+		WithDetail("no route matches "+c.Request.Method+" "+c.Request.URL.Path))
+}
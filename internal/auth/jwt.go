@@ -2,6 +2,8 @@ package auth
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -11,26 +13,48 @@ import (
 const TokenExpirationTime = 24 * time.Hour
 
 // Claims represents JWT claims
+// RegisteredClaims.ID (jti) carries the login session ID, so the token dies with its session
 type Claims struct {
-	UserID int `json:"user_id"`
+	UserID int    `json:"user_id"`
+	Role   string `json:"role"`
 	jwt.RegisteredClaims
 }
 
-// GenerateJWT generates a JWT token for a user
-func GenerateJWT(userID int, secret string) (string, int, error) {
-	expirationTime := time.Now().Add(TokenExpirationTime)
-	
+// SessionID returns the login session the token was issued for
+func (c *Claims) SessionID() string {
+	return c.ID
+}
+
+// GenerateJWT signs an access token for a user's login session with the currently active key
+func (k *Keyring) GenerateJWT(userID int, role, sessionID string) (string, int, error) {
+	if sessionID == "" {
+		return "", 0, errors.New("token needs a session id")
+	}
+
+	now := k.now()
+	key, err := k.signingKey(now)
+	if err != nil {
+		return "", 0, err
+	}
+
 	claims := &Claims{
 		UserID: userID,
+		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ID:        sessionID,
+			Issuer:    k.opts.Issuer,
+			Subject:   strconv.Itoa(userID),
+			Audience:  jwt.ClaimStrings{k.opts.Audience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(TokenExpirationTime)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(secret))
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.ID
+
+	tokenString, err := token.SignedString(key.private)
 	if err != nil {
 		return "", 0, err
 	}
@@ -39,25 +63,50 @@ func GenerateJWT(userID int, secret string) (string, int, error) {
 	return tokenString, expiresIn, nil
 }
 
-// ValidateJWT validates a JWT token and returns the user ID
-func ValidateJWT(tokenString, secret string) (int, error) {
+// ParseJWT verifies an access token's type, signature, kid, issuer, audience, lifetime and jti, and returns its claims
+func (k *Keyring) ParseJWT(tokenString string) (*Claims, error) {
 	claims := &Claims{}
 
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		// Validate the signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		// Statements are signed with the same keys; only a JWT is an access token
+		switch typ, _ := token.Header["typ"].(string); typ {
+		case "JWT":
+		case StatementType:
+			return nil, errors.New("signed statement is not an access token")
+		default:
+			return nil, fmt.Errorf("token type %q is not JWT", typ)
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, errors.New("token has no kid")
 		}
-		return []byte(secret), nil
-	})
 
+		key, err := k.verificationKey(kid, k.now())
+		if err != nil {
+			return nil, err
+		}
+
+		// The key decides the algorithm, never the token
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("token algorithm %s does not match key %s", token.Method.Alg(), kid)
+		}
+		return key.Public(), nil
+	},
+		jwt.WithValidMethods([]string{AlgRS256, AlgEdDSA}),
+		jwt.WithIssuer(k.opts.Issuer),
+		jwt.WithAudience(k.opts.Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithTimeFunc(k.now),
+	)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	if !token.Valid {
-		return 0, errors.New("invalid token")
+	if claims.ID == "" {
+		return nil, errors.New("token has no jti")
 	}
 
-	return claims.UserID, nil
-}
\ No newline at end of file
+	return claims, nil
+}
@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKeyringOptions = KeyringOptions{Issuer: "debank", Audience: "debank-api", Overlap: TokenExpirationTime}
+
+func testEd25519Key(t *testing.T, id string, notBefore time.Time) *SigningKey {
+	t.Helper()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := NewSigningKey(id, private, notBefore)
+	require.NoError(t, err)
+	return key
+}
+
+func testKeyring(t *testing.T, now time.Time, keys ...*SigningKey) *Keyring {
+	t.Helper()
+	k, err := NewKeyring(keys, testKeyringOptions)
+	require.NoError(t, err)
+	k.now = func() time.Time { return now }
+	return k
+}
+
+func TestJWTRoundTrip(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, MinRSAKeyBits)
+	require.NoError(t, err)
+	rsaKey, err := NewSigningKey("rsa-1", rsaPrivate, now.Add(-time.Hour))
+	require.NoError(t, err)
+
+	for _, key := range []*SigningKey{testEd25519Key(t, "ed-1", now.Add(-time.Hour)), rsaKey} {
+		t.Run(key.Algorithm, func(t *testing.T) {
+			k := testKeyring(t, now, key)
+
+			token, expiresIn, err := k.GenerateJWT(42, RoleSupport, "session-abc")
+			require.NoError(t, err)
+			assert.Equal(t, int(TokenExpirationTime.Seconds()), expiresIn)
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+			require.NoError(t, err)
+			assert.Equal(t, key.ID, parsed.Header["kid"])
+			assert.Equal(t, key.Algorithm, parsed.Header["alg"])
+
+			claims, err := k.ParseJWT(token)
+			require.NoError(t, err)
+			assert.Equal(t, 42, claims.UserID)
+			assert.Equal(t, "session-abc", claims.SessionID())
+			assert.Equal(t, "debank", claims.Issuer)
+			assert.Equal(t, jwt.ClaimStrings{"debank-api"}, claims.Audience)
+		})
+	}
+}
+
+func TestJWTRejects(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	key := testEd25519Key(t, "ed-1", now.Add(-time.Hour))
+	k := testKeyring(t, now, key)
+
+	token, _, err := k.GenerateJWT(42, RoleSupport, "session-abc")
+	require.NoError(t, err)
+
+	t.Run("other audience", func(t *testing.T) {
+		other, err := NewKeyring([]*SigningKey{key}, KeyringOptions{Issuer: "debank", Audience: "ledger", Overlap: TokenExpirationTime})
+		require.NoError(t, err)
+		other.now = k.now
+		_, err = other.ParseJWT(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("other issuer", func(t *testing.T) {
+		other, err := NewKeyring([]*SigningKey{key}, KeyringOptions{Issuer: "someone-else", Audience: "debank-api", Overlap: TokenExpirationTime})
+		require.NoError(t, err)
+		other.now = k.now
+		_, err = other.ParseJWT(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		other := testKeyring(t, now, testEd25519Key(t, "ed-2", now.Add(-time.Hour)))
+		_, err := other.ParseJWT(token)
+		assert.ErrorIs(t, err, ErrUnknownKey)
+	})
+
+	t.Run("same kid, different key", func(t *testing.T) {
+		other := testKeyring(t, now, testEd25519Key(t, "ed-1", now.Add(-time.Hour)))
+		_, err := other.ParseJWT(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		later := testKeyring(t, now.Add(TokenExpirationTime+time.Minute), key)
+		_, err := later.ParseJWT(token)
+		assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+	})
+
+	t.Run("no jti", func(t *testing.T) {
+		_, _, err := k.GenerateJWT(42, RoleUser, "")
+		assert.Error(t, err)
+
+		unsigned := jwt.NewWithClaims(jwt.SigningMethodEdDSA, &Claims{
+			UserID: 42,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    "debank",
+				Audience:  jwt.ClaimStrings{"debank-api"},
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+				IssuedAt:  jwt.NewNumericDate(now),
+			},
+		})
+		unsigned.Header["kid"] = key.ID
+		forged, err := unsigned.SignedString(key.private)
+		require.NoError(t, err)
+
+		_, err = k.ParseJWT(forged)
+		assert.Error(t, err)
+	})
+
+	t.Run("HS256", func(t *testing.T) {
+		hmac := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{UserID: 42})
+		hmac.Header["kid"] = key.ID
+		forged, err := hmac.SignedString([]byte(key.Public().(ed25519.PublicKey)))
+		require.NoError(t, err)
+
+		_, err = k.ParseJWT(forged)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,513 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ==============================================
+// SIGNING KEYRING
+// ==============================================
+// Access tokens are signed with RS256 or EdDSA keys listed in a manifest
+// (KeyManifestFile) in the keys directory. Each key has a not_before
+// time: the newest key whose not_before has passed signs new tokens, so
+// a rotation is scheduled by staging the next key with a future
+// not_before on every instance ahead of time. A key that has been
+// superseded keeps verifying tokens for the overlap window, which must be
+// at least TokenExpirationTime so nothing it signed is cut short.
+// Staged and overlapping keys are all published in the JWKS.
+
+// KeyManifestFile is the manifest LoadKeyring reads from the keys directory
+const KeyManifestFile = "keys.json"
+
+// MinRSAKeyBits is the smallest RSA key accepted for signing
+const MinRSAKeyBits = 2048
+
+// Signing algorithms a key can use, as they appear in the JWT header
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+var (
+	ErrNoSigningKey = errors.New("no signing key is active yet")
+	ErrUnknownKey   = errors.New("token signed with an unknown or retired key")
+)
+
+// KeyringOptions are the claims and rotation settings shared by every key
+type KeyringOptions struct {
+	Issuer   string        // iss set on and required of every token
+	Audience string        // aud set on and required of every token
+	Overlap  time.Duration // How long a superseded key keeps verifying
+}
+
+// SigningKey is one private key in the keyring
+type SigningKey struct {
+	ID        string // kid
+	Algorithm string // AlgRS256 or AlgEdDSA
+	NotBefore time.Time
+
+	private crypto.Signer
+}
+
+// Public returns the key's public half
+func (k *SigningKey) Public() crypto.PublicKey {
+	return k.private.Public()
+}
+
+// NewSigningKey wraps an RSA or Ed25519 private key, picking the algorithm from its type
+func NewSigningKey(id string, private crypto.Signer, notBefore time.Time) (*SigningKey, error) {
+	if id == "" {
+		return nil, errors.New("signing key needs an id")
+	}
+
+	key := &SigningKey{ID: id, NotBefore: notBefore, private: private}
+	switch p := private.(type) {
+	case *rsa.PrivateKey:
+		if p.N.BitLen() < MinRSAKeyBits {
+			return nil, fmt.Errorf("key %s: RSA keys must be at least %d bits", id, MinRSAKeyBits)
+		}
+		key.Algorithm = AlgRS256
+	case ed25519.PrivateKey:
+		key.Algorithm = AlgEdDSA
+	default:
+		return nil, fmt.Errorf("key %s: unsupported key type %T (use RSA or Ed25519)", id, private)
+	}
+	return key, nil
+}
+
+// Keyring holds the signing keys and their rotation schedule
+type Keyring struct {
+	mu   sync.RWMutex
+	keys []*SigningKey // Sorted by NotBefore
+	dir  string        // Reloaded from here; empty for an in-memory keyring
+	opts KeyringOptions
+
+	now func() time.Time
+}
+
+// NewKeyring builds a keyring from keys already in memory
+func NewKeyring(keys []*SigningKey, opts KeyringOptions) (*Keyring, error) {
+	k := &Keyring{opts: opts, now: time.Now}
+	if err := k.setKeys(keys); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// NewEphemeralKeyring generates a single Ed25519 key that lives only as long as the process
+// For development: tokens stop working on restart and other instances can't verify them
+func NewEphemeralKeyring(opts KeyringOptions) (*Keyring, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate key id: %w", err)
+	}
+
+	key, err := NewSigningKey("ephemeral-"+hex.EncodeToString(id), private, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	return NewKeyring([]*SigningKey{key}, opts)
+}
+
+// LoadKeyring reads the manifest and PEM private keys in dir
+// Returns an error wrapping fs.ErrNotExist if there is no manifest
+func LoadKeyring(dir string, opts KeyringOptions) (*Keyring, error) {
+	keys, err := loadKeys(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &Keyring{dir: dir, opts: opts, now: time.Now}
+	if err := k.setKeys(keys); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Reload re-reads the keys directory, e.g. to pick up a newly staged key
+// On error the current keys stay in use
+func (k *Keyring) Reload() (int, error) {
+	if k.dir == "" {
+		return 0, errors.New("keyring was not loaded from a directory")
+	}
+
+	keys, err := loadKeys(k.dir)
+	if err != nil {
+		return 0, err
+	}
+	if err := k.setKeys(keys); err != nil {
+		return 0, err
+	}
+	return len(keys), nil
+}
+
+func (k *Keyring) setKeys(keys []*SigningKey) error {
+	if len(keys) == 0 {
+		return errors.New("keyring needs at least one key")
+	}
+	if k.opts.Issuer == "" || k.opts.Audience == "" {
+		return errors.New("keyring needs an issuer and an audience")
+	}
+	if k.opts.Overlap < TokenExpirationTime {
+		return fmt.Errorf("key overlap must be at least the token lifetime (%s)", TokenExpirationTime)
+	}
+
+	sorted, err := schedule(keys)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	k.keys = sorted
+	k.mu.Unlock()
+	return nil
+}
+
+// schedule sorts keys by not_before, rejecting duplicate ids and ties
+func schedule(keys []*SigningKey) ([]*SigningKey, error) {
+	sorted := make([]*SigningKey, len(keys))
+	copy(sorted, keys)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].NotBefore.Before(sorted[j].NotBefore) })
+
+	seen := make(map[string]bool, len(sorted))
+	for i, key := range sorted {
+		if seen[key.ID] {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		seen[key.ID] = true
+		if i > 0 && key.NotBefore.Equal(sorted[i-1].NotBefore) {
+			return nil, fmt.Errorf("keys %q and %q have the same not_before", sorted[i-1].ID, key.ID)
+		}
+	}
+	return sorted, nil
+}
+
+// ==============================================
+// SCHEDULE
+// ==============================================
+
+// signingKey returns the key that signs tokens at t: the newest one whose not_before has passed
+func (k *Keyring) signingKey(t time.Time) (*SigningKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	for i := len(k.keys) - 1; i >= 0; i-- {
+		if !k.keys[i].NotBefore.After(t) {
+			return k.keys[i], nil
+		}
+	}
+	return nil, ErrNoSigningKey
+}
+
+// verificationKey returns the key with this id if it may have signed a token that's still valid at t
+func (k *Keyring) verificationKey(id string, t time.Time) (*SigningKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	for i, key := range k.keys {
+		if key.ID != id {
+			continue
+		}
+		if key.NotBefore.After(t) || k.retired(i, t) {
+			return nil, ErrUnknownKey
+		}
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+// publishedKeys returns every key that is staged, signing, or still in its overlap window at t
+func (k *Keyring) publishedKeys(t time.Time) []*SigningKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	var keys []*SigningKey
+	for i, key := range k.keys {
+		if !k.retired(i, t) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// retired checks if the i'th key was superseded longer than the overlap window ago; callers hold mu
+func (k *Keyring) retired(i int, t time.Time) bool {
+	return i+1 < len(k.keys) && t.After(k.keys[i+1].NotBefore.Add(k.opts.Overlap))
+}
+
+// ==============================================
+// JWKS
+// ==============================================
+
+// JWK is the public half of a signing key, as published for other services
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// Ed25519
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set (RFC 7517)
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys other services need to verify tokens, including staged ones
+func (k *Keyring) JWKS() JWKS {
+	keys := k.publishedKeys(k.now())
+
+	set := JWKS{Keys: make([]JWK, 0, len(keys))}
+	for _, key := range keys {
+		jwk := JWK{KeyID: key.ID, Use: "sig", Algorithm: key.Algorithm}
+		switch pub := key.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// ==============================================
+// LOADING
+// ==============================================
+
+type keyManifest struct {
+	Keys []manifestKey `json:"keys"`
+}
+
+type manifestKey struct {
+	ID        string    `json:"kid"`
+	File      string    `json:"file"` // PEM private key, relative to the keys directory
+	NotBefore time.Time `json:"not_before"`
+}
+
+func readManifest(dir string) (*keyManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, KeyManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key manifest: %w", err)
+	}
+
+	var manifest keyManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse key manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+func loadKeys(dir string) ([]*SigningKey, error) {
+	manifest, err := readManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	return manifest.load(dir)
+}
+
+// load reads the private keys the manifest lists
+func (manifest *keyManifest) load(dir string) ([]*SigningKey, error) {
+	keys := make([]*SigningKey, 0, len(manifest.Keys))
+	for _, entry := range manifest.Keys {
+		if !filepath.IsLocal(entry.File) {
+			return nil, fmt.Errorf("key %s: file must be a path inside the keys directory", entry.ID)
+		}
+
+		raw, err := os.ReadFile(filepath.Join(dir, entry.File))
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", entry.ID, err)
+		}
+
+		private, err := ParsePrivateKeyPEM(raw)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", entry.ID, err)
+		}
+
+		key, err := NewSigningKey(entry.ID, private, entry.NotBefore)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// ParsePrivateKeyPEM decodes a PKCS#8 or PKCS#1 PEM private key
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse PKCS#8 key: %w", err)
+		}
+		signer, ok := parsed.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported key type %T", parsed)
+		}
+		return signer, nil
+	case "RSA PRIVATE KEY":
+		parsed, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse PKCS#1 key: %w", err)
+		}
+		return parsed, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+}
+
+// ==============================================
+// STAGING
+// ==============================================
+
+// StagedRSAKeyBits is the size of RSA keys StageKey generates
+const StagedRSAKeyBits = 3072
+
+// StageKey generates a key with the given algorithm, writes it to dir as
+// <id>.pem and adds it to the manifest (creating one if there is none).
+// It signs from notBefore once each instance has reloaded its keyring.
+// The manifest is replaced atomically, so a running server never reads
+// half of it; nothing is written if the updated keyring would not load.
+func StageKey(dir, id, algorithm string, notBefore time.Time) (*SigningKey, error) {
+	manifest, err := readManifest(dir)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		manifest = &keyManifest{}
+	case err != nil:
+		return nil, err
+	}
+	existing, err := manifest.load(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	file := id + ".pem"
+	if !filepath.IsLocal(file) || filepath.Base(file) != file {
+		return nil, fmt.Errorf("key id %q can't be used as a file name", id)
+	}
+
+	private, err := generateKey(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	key, err := NewSigningKey(id, private, notBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the whole schedule before touching the directory
+	if _, err := schedule(append(existing, key)); err != nil {
+		return nil, err
+	}
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode key: %w", err)
+	}
+	if err := writeNewFile(filepath.Join(dir, file), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})); err != nil {
+		return nil, err
+	}
+
+	manifest.Keys = append(manifest.Keys, manifestKey{ID: id, File: file, NotBefore: notBefore.UTC()})
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode key manifest: %w", err)
+	}
+	if err := replaceFile(filepath.Join(dir, KeyManifestFile), append(data, '\n')); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func generateKey(algorithm string) (crypto.Signer, error) {
+	switch algorithm {
+	case AlgEdDSA:
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate key: %w", err)
+		}
+		return private, nil
+	case AlgRS256:
+		private, err := rsa.GenerateKey(rand.Reader, StagedRSAKeyBits)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate key: %w", err)
+		}
+		return private, nil
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q (use %s or %s)", algorithm, AlgEdDSA, AlgRS256)
+	}
+}
+
+// writeNewFile writes a private file that must not already exist
+func writeNewFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Base(path), err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return f.Close()
+}
+
+// replaceFile swaps in new contents for path via a temporary file and rename
+func replaceFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyringRotation(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	rotation := start.Add(30 * 24 * time.Hour)
+
+	oldKey := testEd25519Key(t, "2026-10", start)
+	newKey := testEd25519Key(t, "2026-11", rotation)
+	k := testKeyring(t, rotation.Add(-time.Minute), oldKey, newKey)
+
+	// Before the rotation the old key signs; the staged key is already published
+	token, _, err := k.GenerateJWT(1, RoleUser, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2026-10", "2026-11"}, jwksIDs(k.JWKS()))
+
+	// After it the new key signs, and old tokens still verify during the overlap
+	k.now = func() time.Time { return rotation.Add(time.Hour) }
+	_, err = k.ParseJWT(token)
+	require.NoError(t, err)
+
+	key, err := k.signingKey(k.now())
+	require.NoError(t, err)
+	assert.Equal(t, "2026-11", key.ID)
+
+	// Past the overlap the old key is retired and unpublished
+	k.now = func() time.Time { return rotation.Add(testKeyringOptions.Overlap + time.Minute) }
+	_, err = k.verificationKey("2026-10", k.now())
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	assert.Equal(t, []string{"2026-11"}, jwksIDs(k.JWKS()))
+
+	// A staged key can't verify anything before its time
+	k.now = func() time.Time { return start.Add(time.Hour) }
+	_, err = k.verificationKey("2026-11", k.now())
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestKeyringValidation(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := NewKeyring(nil, testKeyringOptions)
+	assert.Error(t, err)
+
+	_, err = NewKeyring([]*SigningKey{testEd25519Key(t, "a", start)}, KeyringOptions{Issuer: "debank", Audience: "debank-api", Overlap: time.Hour})
+	assert.Error(t, err, "overlap shorter than the token lifetime")
+
+	_, err = NewKeyring([]*SigningKey{testEd25519Key(t, "a", start), testEd25519Key(t, "a", start.Add(time.Hour))}, testKeyringOptions)
+	assert.Error(t, err, "duplicate kid")
+
+	_, err = NewKeyring([]*SigningKey{testEd25519Key(t, "a", start), testEd25519Key(t, "b", start)}, testKeyringOptions)
+	assert.Error(t, err, "ambiguous schedule")
+
+	k := testKeyring(t, start.Add(-time.Hour), testEd25519Key(t, "a", start))
+	_, _, err = k.GenerateJWT(1, RoleUser, "s1")
+	assert.ErrorIs(t, err, ErrNoSigningKey)
+
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	_, err = NewSigningKey("small", small, start)
+	assert.Error(t, err)
+}
+
+func TestKeyringJWKS(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, MinRSAKeyBits)
+	require.NoError(t, err)
+	rsaKey, err := NewSigningKey("rsa", rsaPrivate, start)
+	require.NoError(t, err)
+	edKey := testEd25519Key(t, "ed", start.Add(time.Hour))
+
+	set := testKeyring(t, start.Add(2*time.Hour), rsaKey, edKey).JWKS()
+	require.Len(t, set.Keys, 2)
+
+	rsaJWK := set.Keys[0]
+	assert.Equal(t, "RSA", rsaJWK.KeyType)
+	assert.Equal(t, "RS256", rsaJWK.Algorithm)
+	assert.Equal(t, "sig", rsaJWK.Use)
+	assert.Equal(t, "AQAB", rsaJWK.E)
+	n, err := base64.RawURLEncoding.DecodeString(rsaJWK.N)
+	require.NoError(t, err)
+	assert.Equal(t, rsaPrivate.N.Bytes(), n)
+
+	edJWK := set.Keys[1]
+	assert.Equal(t, "OKP", edJWK.KeyType)
+	assert.Equal(t, "Ed25519", edJWK.Curve)
+	assert.Equal(t, "EdDSA", edJWK.Algorithm)
+	x, err := base64.RawURLEncoding.DecodeString(edJWK.X)
+	require.NoError(t, err)
+	assert.Equal(t, []byte(edKey.Public().(ed25519.PublicKey)), x)
+}
+
+func TestLoadKeyring(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := LoadKeyring(dir, testKeyringOptions)
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+
+	_, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(edPrivate)
+	require.NoError(t, err)
+	writeFile(t, dir, "ed.pem", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}))
+
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, MinRSAKeyBits)
+	require.NoError(t, err)
+	writeFile(t, dir, "rsa.pem", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaPrivate)}))
+
+	writeFile(t, dir, KeyManifestFile, []byte(`{"keys": [
+		{"kid": "ed", "file": "ed.pem", "not_before": "2026-10-01T00:00:00Z"}
+	]}`))
+
+	k, err := LoadKeyring(dir, testKeyringOptions)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ed"}, jwksIDs(k.JWKS()))
+
+	// Staging the next key and reloading publishes it
+	writeFile(t, dir, KeyManifestFile, []byte(`{"keys": [
+		{"kid": "ed", "file": "ed.pem", "not_before": "2026-10-01T00:00:00Z"},
+		{"kid": "rsa", "file": "rsa.pem", "not_before": "2099-01-01T00:00:00Z"}
+	]}`))
+	n, err := k.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"ed", "rsa"}, jwksIDs(k.JWKS()))
+
+	// A broken manifest leaves the loaded keys alone
+	writeFile(t, dir, KeyManifestFile, []byte(`{"keys": [{"kid": "x", "file": "../outside.pem"}]}`))
+	_, err = k.Reload()
+	assert.Error(t, err)
+	assert.Equal(t, []string{"ed", "rsa"}, jwksIDs(k.JWKS()))
+}
+
+func TestStageKey(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	// The first key creates the manifest
+	_, err := StageKey(dir, "2026-10", AlgEdDSA, start)
+	require.NoError(t, err)
+	k, err := LoadKeyring(dir, testKeyringOptions)
+	require.NoError(t, err)
+
+	next, err := StageKey(dir, "2026-11", AlgRS256, start.Add(30*24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, AlgRS256, next.Algorithm)
+
+	n, err := k.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"2026-10", "2026-11"}, jwksIDs(k.JWKS()))
+
+	info, err := os.Stat(filepath.Join(dir, "2026-11.pem"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// Clashes are refused without writing anything
+	_, err = StageKey(dir, "2026-11", AlgEdDSA, start.Add(60*24*time.Hour))
+	assert.Error(t, err)
+	_, err = StageKey(dir, "tie", AlgEdDSA, start)
+	assert.Error(t, err)
+	_, err = StageKey(dir, "../escape", AlgEdDSA, start.Add(time.Hour))
+	assert.Error(t, err)
+	_, err = StageKey(dir, "hs", "HS256", start.Add(time.Hour))
+	assert.Error(t, err)
+
+	_, err = os.Stat(filepath.Join(dir, "tie.pem"))
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+	n, err = k.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+}
+
+func jwksIDs(set JWKS) []string {
+	ids := make([]string, 0, len(set.Keys))
+	for _, key := range set.Keys {
+		ids = append(ids, key.KeyID)
+	}
+	return ids
+}
+
+func writeFile(t *testing.T, dir, name string, data []byte) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+}
@@ -0,0 +1,98 @@
+package auth
+
+import "slices"
+
+// ==============================================
+// ROLES AND PERMISSIONS
+// ==============================================
+// Every user has one role, stored on the user and carried in the access
+// token's role claim. Routes check permissions, never roles, so what a
+// role may do is decided here in one place.
+
+// Roles, in increasing order of privilege
+const (
+	RoleUser       = "user"
+	RoleSupport    = "support"
+	RoleCompliance = "compliance"
+	RoleAdmin      = "admin"
+	RoleSuperadmin = "superadmin"
+)
+
+// Permission is something a route can require of the caller's role
+type Permission string
+
+const (
+	PermAdminAccess      Permission = "admin:access" // Any /admin route
+	PermUsersRead        Permission = "users:read"
+	PermUsersLock        Permission = "users:lock"
+	PermTransactionsRead Permission = "transactions:read"
+	PermKYCReview        Permission = "kyc:review"
+	PermAMLManage        Permission = "aml:manage"
+	PermScreeningManage  Permission = "screening:manage"
+	PermRiskRead         Permission = "risk:read"
+	PermRiskManage       Permission = "risk:manage"
+	PermAuditRead        Permission = "audit:read"
+	PermRolesManage      Permission = "roles:manage"
+	PermLedgerManage     Permission = "ledger:manage" // Adjustments, reversals, freezes, system accounts and journal entries
+)
+
+var (
+	staffPermissions = []Permission{
+		PermAdminAccess, PermUsersRead, PermUsersLock, PermTransactionsRead,
+	}
+	compliancePermissions = slices.Concat(staffPermissions, []Permission{
+		PermKYCReview, PermAMLManage, PermScreeningManage, PermRiskRead, PermAuditRead,
+	})
+	adminPermissions = slices.Concat(compliancePermissions, []Permission{PermRiskManage, PermLedgerManage})
+
+	rolePermissions = map[string]map[Permission]bool{
+		RoleUser:       {},
+		RoleSupport:    permissionSet(staffPermissions),
+		RoleCompliance: permissionSet(compliancePermissions),
+		RoleAdmin:      permissionSet(adminPermissions),
+		RoleSuperadmin: permissionSet(slices.Concat(adminPermissions, []Permission{PermRolesManage})),
+	}
+
+	// Support and compliance are peers; neither outranks the other
+	roleRanks = map[string]int{
+		RoleUser:       0,
+		RoleSupport:    1,
+		RoleCompliance: 1,
+		RoleAdmin:      2,
+		RoleSuperadmin: 3,
+	}
+)
+
+func permissionSet(perms []Permission) map[Permission]bool {
+	set := make(map[Permission]bool, len(perms))
+	for _, p := range perms {
+		set[p] = true
+	}
+	return set
+}
+
+// IsValidRole checks if role is one of the known roles
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// HasPermission checks if a role grants a permission; unknown roles grant nothing
+func HasPermission(role string, perm Permission) bool {
+	return rolePermissions[role][perm]
+}
+
+// Outranks checks if actor's role is strictly more privileged than target's,
+// which staff need before acting on another staff member's account
+func Outranks(actor, target string) bool {
+	a, ok := roleRanks[actor]
+	if !ok {
+		return false
+	}
+	t, ok := roleRanks[target]
+	if !ok {
+		// Unknown roles are treated as the most privileged
+		return false
+	}
+	return a > t
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasPermission(t *testing.T) {
+	cases := []struct {
+		role    string
+		allowed []Permission
+		denied  []Permission
+	}{
+		{RoleUser, nil, []Permission{PermAdminAccess, PermUsersRead}},
+		{RoleSupport, []Permission{PermAdminAccess, PermUsersRead, PermUsersLock, PermTransactionsRead}, []Permission{PermKYCReview, PermAMLManage, PermRiskRead, PermAuditRead}},
+		{RoleCompliance, []Permission{PermAdminAccess, PermKYCReview, PermAMLManage, PermScreeningManage, PermRiskRead, PermAuditRead}, []Permission{PermRiskManage, PermLedgerManage, PermRolesManage}},
+		{RoleAdmin, []Permission{PermKYCReview, PermRiskManage, PermLedgerManage}, []Permission{PermRolesManage}},
+		{RoleSuperadmin, []Permission{PermAdminAccess, PermRiskManage, PermRolesManage}, nil},
+		{"", nil, []Permission{PermAdminAccess}},
+		{"root", nil, []Permission{PermAdminAccess}},
+	}
+
+	for _, tc := range cases {
+		for _, perm := range tc.allowed {
+			assert.True(t, HasPermission(tc.role, perm), "%q should have %s", tc.role, perm)
+		}
+		for _, perm := range tc.denied {
+			assert.False(t, HasPermission(tc.role, perm), "%q should not have %s", tc.role, perm)
+		}
+	}
+}
+
+func TestOutranks(t *testing.T) {
+	assert.True(t, Outranks(RoleSupport, RoleUser))
+	assert.True(t, Outranks(RoleAdmin, RoleCompliance))
+	assert.True(t, Outranks(RoleSuperadmin, RoleAdmin))
+
+	assert.False(t, Outranks(RoleSupport, RoleCompliance), "peers")
+	assert.False(t, Outranks(RoleAdmin, RoleAdmin), "same role")
+	assert.False(t, Outranks(RoleAdmin, RoleSuperadmin))
+	assert.False(t, Outranks("", RoleUser), "unknown actor")
+	assert.False(t, Outranks(RoleSuperadmin, "root"), "unknown target")
+}
+
+func TestIsValidRole(t *testing.T) {
+	for _, role := range []string{RoleUser, RoleSupport, RoleCompliance, RoleAdmin, RoleSuperadmin} {
+		assert.True(t, IsValidRole(role))
+	}
+	assert.False(t, IsValidRole(""))
+	assert.False(t, IsValidRole("Admin"))
+}
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ==============================================
+// SIGNED STATEMENTS
+// ==============================================
+// Besides access tokens, the keyring signs statements the bank makes about
+// itself, such as ledger checkpoints. A statement is a compact JWS whose
+// payload is plain JSON, so anyone holding the published JWKS can verify
+// it. Unlike a token it never expires: any key still in the keyring,
+// retired or not, verifies what it signed.
+
+// StatementType is the typ header on signed statements
+const StatementType = "debank-statement+jws"
+
+var ErrBadStatement = errors.New("statement signature is invalid")
+
+type statementHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ"`
+}
+
+// SignStatement signs payload, encoded as JSON, with the active key and returns the JWS and key id
+func (k *Keyring) SignStatement(payload any) (string, string, error) {
+	key, err := k.signingKey(k.now())
+	if err != nil {
+		return "", "", err
+	}
+
+	header, err := json.Marshal(statementHeader{Alg: key.Algorithm, Kid: key.ID, Typ: StatementType})
+	if err != nil {
+		return "", "", err
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode statement: %w", err)
+	}
+
+	signingString := jwtSegment(header) + "." + jwtSegment(body)
+	sig, err := jwt.GetSigningMethod(key.Algorithm).Sign(signingString, key.private)
+	if err != nil {
+		return "", "", err
+	}
+
+	return signingString + "." + jwtSegment(sig), key.ID, nil
+}
+
+// VerifyStatement checks a statement from SignStatement and decodes its payload into v
+func (k *Keyring) VerifyStatement(statement string, v any) error {
+	parts := strings.Split(statement, ".")
+	if len(parts) != 3 {
+		return ErrBadStatement
+	}
+
+	var header statementHeader
+	if err := decodeSegment(parts[0], &header); err != nil || header.Typ != StatementType {
+		return ErrBadStatement
+	}
+
+	key, err := k.statementKey(header.Kid)
+	if err != nil {
+		return err
+	}
+	// The key decides the algorithm, never the statement
+	if header.Alg != key.Algorithm {
+		return ErrBadStatement
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return ErrBadStatement
+	}
+	if err := jwt.GetSigningMethod(key.Algorithm).Verify(parts[0]+"."+parts[1], sig, key.Public()); err != nil {
+		return ErrBadStatement
+	}
+
+	if err := decodeSegment(parts[1], v); err != nil {
+		return ErrBadStatement
+	}
+	return nil
+}
+
+// statementKey returns the key with this id, whether or not it is still published
+func (k *Keyring) statementKey(id string) (*SigningKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	for _, key := range k.keys {
+		if key.ID == id {
+			return key, nil
+		}
+	}
+	return nil, ErrUnknownKey
+}
+
+func jwtSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeSegment(seg string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
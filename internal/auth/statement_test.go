@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testStatement struct {
+	Chain  string `json:"chain"`
+	LastID int64  `json:"last_id"`
+}
+
+func TestStatementRoundTrip(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	k := testKeyring(t, start.Add(time.Hour), testEd25519Key(t, "k1", start))
+
+	jws, kid, err := k.SignStatement(testStatement{Chain: "transactions", LastID: 42})
+	require.NoError(t, err)
+	assert.Equal(t, "k1", kid)
+
+	var got testStatement
+	require.NoError(t, k.VerifyStatement(jws, &got))
+	assert.Equal(t, testStatement{Chain: "transactions", LastID: 42}, got)
+}
+
+func TestStatementOutlivesKeyRetirement(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	rotation := start.Add(30 * 24 * time.Hour)
+	k := testKeyring(t, start.Add(time.Hour), testEd25519Key(t, "old", start), testEd25519Key(t, "new", rotation))
+
+	jws, _, err := k.SignStatement(testStatement{LastID: 1})
+	require.NoError(t, err)
+
+	// Tokens from a retired key are rejected, statements are not
+	k.now = func() time.Time { return rotation.Add(testKeyringOptions.Overlap + time.Hour) }
+	var got testStatement
+	assert.NoError(t, k.VerifyStatement(jws, &got))
+}
+
+func TestStatementRejectsTampering(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	k := testKeyring(t, start.Add(time.Hour), testEd25519Key(t, "k1", start))
+
+	jws, _, err := k.SignStatement(testStatement{LastID: 1})
+	require.NoError(t, err)
+	forged, _, err := k.SignStatement(testStatement{LastID: 2})
+	require.NoError(t, err)
+
+	parts := strings.Split(jws, ".")
+	swapped := parts[0] + "." + strings.Split(forged, ".")[1] + "." + parts[2]
+
+	var got testStatement
+	assert.ErrorIs(t, k.VerifyStatement(swapped, &got), ErrBadStatement)
+	assert.ErrorIs(t, k.VerifyStatement("not.a-jws", &got), ErrBadStatement)
+
+	// A token is not a statement
+	token, _, err := k.GenerateJWT(1, RoleUser, "s1")
+	require.NoError(t, err)
+	assert.ErrorIs(t, k.VerifyStatement(token, &got), ErrBadStatement)
+
+	// Keys the keyring doesn't hold can't vouch for anything
+	other := testKeyring(t, start.Add(time.Hour), testEd25519Key(t, "k2", start))
+	assert.ErrorIs(t, other.VerifyStatement(jws, &got), ErrUnknownKey)
+}
+
+func TestStatementIsNotAnAccessToken(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	now := start.Add(time.Hour)
+	k := testKeyring(t, now, testEd25519Key(t, "k1", start))
+
+	// Even a statement whose payload is a complete set of access token claims
+	jws, _, err := k.SignStatement(&Claims{
+		UserID: 1,
+		Role:   RoleSuperadmin,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "s1",
+			Issuer:    "debank",
+			Subject:   "1",
+			Audience:  jwt.ClaimStrings{"debank-api"},
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = k.ParseJWT(jws)
+	assert.Error(t, err)
+}
@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ==============================================
+// STEP-UP BINDING
+// ==============================================
+
+// StepUpBinding hashes an operation so a step-up proof only authorises
+// the exact action and parameters it was issued for
+// Parameter order doesn't matter; values are compared as given
+func StepUpBinding(action string, params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	// Length-prefixed so no choice of values can collide with another set of params
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d:%s", len(action), action)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "|%d:%s=%d:%s", len(k), k, len(params[k]), params[k])
+	}
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
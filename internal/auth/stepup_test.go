@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStepUpBinding(t *testing.T) {
+	base := StepUpBinding("transfer", map[string]string{"to": "@ada", "amount": "5000000"})
+
+	// Same operation, any map order
+	assert.Equal(t, base, StepUpBinding("transfer", map[string]string{"amount": "5000000", "to": "@ada"}))
+
+	// Any change to the action or a parameter breaks the binding
+	assert.NotEqual(t, base, StepUpBinding("withdraw", map[string]string{"to": "@ada", "amount": "5000000"}))
+	assert.NotEqual(t, base, StepUpBinding("transfer", map[string]string{"to": "@ada", "amount": "5000001"}))
+	assert.NotEqual(t, base, StepUpBinding("transfer", map[string]string{"to": "@bob", "amount": "5000000"}))
+	assert.NotEqual(t, base, StepUpBinding("transfer", map[string]string{"to": "@ada"}))
+
+	// Values can't smuggle in extra parameters
+	assert.NotEqual(t,
+		StepUpBinding("transfer", map[string]string{"a": "1|1:b=1:2"}),
+		StepUpBinding("transfer", map[string]string{"a": "1", "b": "2"}),
+	)
+}
@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+// ==============================================
+// TOTP (RFC 6238)
+// ==============================================
+
+const (
+	TOTPIssuer = "Debank"
+	TOTPPeriod = 30 // seconds
+	TOTPSkew   = 1  // Steps either side of now accepted, for clock drift
+)
+
+var totpOpts = totp.ValidateOpts{
+	Period:    TOTPPeriod,
+	Digits:    otp.DigitsSix,
+	Algorithm: otp.AlgorithmSHA1, // What authenticator apps support universally
+}
+
+// GenerateTOTPSecret creates a new TOTP secret and the otpauth:// URI to show as a QR code
+func GenerateTOTPSecret(accountName string) (secret, uri string, err error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      TOTPIssuer,
+		AccountName: accountName,
+		Period:      TOTPPeriod,
+		Digits:      totpOpts.Digits,
+		Algorithm:   totpOpts.Algorithm,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return key.Secret(), key.URL(), nil
+}
+
+// ValidateTOTP checks a 6-digit code against the secret around time t
+// Returns the time step the code belongs to so callers can refuse a replayed code
+func ValidateTOTP(code, secret string, t time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != 6 {
+		return 0, false
+	}
+
+	step := t.Unix() / TOTPPeriod
+	for offset := int64(-TOTPSkew); offset <= TOTPSkew; offset++ {
+		at := time.Unix((step+offset)*TOTPPeriod, 0)
+		expected, err := totp.GenerateCodeCustom(secret, at, totpOpts)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step + offset, true
+		}
+	}
+	return 0, false
+}
+
+// ==============================================
+// SECRET ENCRYPTION
+// ==============================================
+// TOTP secrets have to be recoverable, so unlike passwords they are
+// encrypted (AES-256-GCM) rather than hashed.
+
+// ErrSecretDecrypt is returned when a stored secret can't be decrypted with the configured key
+var ErrSecretDecrypt = errors.New("failed to decrypt secret")
+
+// SecretKey derives a 256-bit encryption key from configured key material
+func SecretKey(material string) []byte {
+	sum := sha256.Sum256([]byte("debank-mfa:" + material))
+	return sum[:]
+}
+
+// EncryptSecret seals plaintext with key, returning base64(nonce || ciphertext)
+func EncryptSecret(plaintext string, key []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptSecret opens a value produced by EncryptSecret
+func DecryptSecret(encoded string, key []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", ErrSecretDecrypt
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrSecretDecrypt
+	}
+	return string(plaintext), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// ==============================================
+// RECOVERY CODES AND OPAQUE TOKENS
+// ==============================================
+
+const RecoveryCodeCount = 10
+
+// GenerateRecoveryCodes returns n single-use codes formatted "xxxxx-xxxxx"
+func GenerateRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, n)
+	for i := range codes {
+		b := make([]byte, 7) // 56 bits
+		if _, err := io.ReadFull(rand.Reader, b); err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		s := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))[:10]
+		codes[i] = s[:5] + "-" + s[5:]
+	}
+	return codes, nil
+}
+
+// NormalizeRecoveryCode lowercases a code and drops separators so "ABCDE FGHIJ" matches "abcde-fghij"
+func NormalizeRecoveryCode(code string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(code) {
+		if (r >= 'a' && r <= 'z') || (r >= '2' && r <= '7') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// GenerateToken returns a random URL-safe token for short-lived challenges
+func GenerateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken hashes a high-entropy token or recovery code for storage
+// These are random, not user-chosen, so a fast hash is enough
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTOTP(t *testing.T) {
+	secret, uri, err := GenerateTOTPSecret("ada@example.com")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Debank:ada@example.com?"))
+
+	now := time.Unix(1_700_000_000, 0)
+	code, err := totp.GenerateCodeCustom(secret, now, totpOpts)
+	require.NoError(t, err)
+
+	step, ok := ValidateTOTP(code, secret, now)
+	assert.True(t, ok)
+	assert.Equal(t, now.Unix()/TOTPPeriod, step)
+
+	// One step of drift either way is accepted
+	_, ok = ValidateTOTP(code, secret, now.Add(TOTPPeriod*time.Second))
+	assert.True(t, ok)
+	_, ok = ValidateTOTP(code, secret, now.Add(-TOTPPeriod*time.Second))
+	assert.True(t, ok)
+
+	_, ok = ValidateTOTP(code, secret, now.Add(3*TOTPPeriod*time.Second))
+	assert.False(t, ok)
+	_, ok = ValidateTOTP("12345", secret, now)
+	assert.False(t, ok)
+}
+
+func TestEncryptSecret(t *testing.T) {
+	key := SecretKey("test-key")
+
+	sealed, err := EncryptSecret("JBSWY3DPEHPK3PXP", key)
+	require.NoError(t, err)
+	assert.NotContains(t, sealed, "JBSWY3DPEHPK3PXP")
+
+	opened, err := DecryptSecret(sealed, key)
+	require.NoError(t, err)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", opened)
+
+	_, err = DecryptSecret(sealed, SecretKey("other-key"))
+	assert.ErrorIs(t, err, ErrSecretDecrypt)
+	_, err = DecryptSecret("not base64!", key)
+	assert.ErrorIs(t, err, ErrSecretDecrypt)
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(RecoveryCodeCount)
+	require.NoError(t, err)
+	require.Len(t, codes, RecoveryCodeCount)
+
+	seen := map[string]bool{}
+	for _, c := range codes {
+		assert.Regexp(t, `^[a-z2-7]{5}-[a-z2-7]{5}$`, c)
+		assert.False(t, seen[c])
+		seen[c] = true
+	}
+
+	assert.Equal(t, "abcdefghij", NormalizeRecoveryCode(" ABCDE fghij "))
+	assert.Equal(t, NormalizeRecoveryCode(codes[0]), NormalizeRecoveryCode(strings.ToUpper(codes[0])))
+}
@@ -0,0 +1,32 @@
+// Package clientinfo carries details about the calling client (IP, user agent,
+// device) from the HTTP layer down to services through the request context.
+package clientinfo
+
+import "context"
+
+// DeviceIDHeader is the header mobile and web clients use to identify the device
+const DeviceIDHeader = "X-Device-ID"
+
+// DeviceNameHeader optionally names the device (e.g. "Ada's iPhone") when it's first registered
+const DeviceNameHeader = "X-Device-Name"
+
+// Info describes the client that made the current request
+type Info struct {
+	IPAddress  string
+	UserAgent  string
+	DeviceID   string // Empty if the client didn't send one
+	DeviceName string // Empty if the client didn't send one
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying info
+func NewContext(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, contextKey{}, info)
+}
+
+// FromContext returns the client info stored in ctx, or a zero Info
+func FromContext(ctx context.Context) Info {
+	info, _ := ctx.Value(contextKey{}).(Info)
+	return info
+}
@@ -1,18 +1,98 @@
 package config
 
 import (
+    "github.com/Brownie44l1/debank/internal/tracing"
     "github.com/spf13/viper"
     "log"
+    "time"
 )
 
 type Config struct {
-    DBUrl string `mapstructure:"DB_URL"`
+    DBUrl      string `mapstructure:"DB_URL"`
+    Port       string `mapstructure:"PORT"`
+    JWTSecret  string `mapstructure:"JWT_SECRET"` // Legacy: tokens are signed by the keyring; only the MFA_ENCRYPTION_KEY fallback now
+    AppBaseURL string `mapstructure:"APP_BASE_URL"` // Used to build shareable payment links
+
+    GRPCPort           string   `mapstructure:"GRPC_PORT"`            // Port of the internal gRPC ledger API; empty disables it
+    GRPCTLSCert        string   `mapstructure:"GRPC_TLS_CERT"`        // Server certificate (PEM)
+    GRPCTLSKey         string   `mapstructure:"GRPC_TLS_KEY"`         // Server private key (PEM)
+    GRPCClientCA       string   `mapstructure:"GRPC_CLIENT_CA"`       // CA that issues the calling services' client certificates (PEM)
+    GRPCAllowedClients []string `mapstructure:"GRPC_ALLOWED_CLIENTS"` // Comma-separated client certificate CNs/DNS SANs; empty allows any the CA issued
+    GRPCInsecure       bool     `mapstructure:"GRPC_INSECURE"`        // Plaintext without client certificates; development only
+
+    MetricsToken   string        `mapstructure:"METRICS_TOKEN"`    // Bearer token required on /metrics and to see /readyz details; empty leaves them open
+    HealthCacheTTL time.Duration `mapstructure:"HEALTH_CACHE_TTL"` // How long a health check result is reused before probing again
+
+    IdempotencyKeyTTL  time.Duration `mapstructure:"IDEMPOTENCY_KEY_TTL"`  // How long a response stored under an Idempotency-Key is replayed
+    IdempotencyWait    time.Duration `mapstructure:"IDEMPOTENCY_WAIT"`     // How long a retry waits for the original request before a 409
+    IdempotencyCleanup time.Duration `mapstructure:"IDEMPOTENCY_CLEANUP"` // How often the worker purges expired keys
+
+    AccountEventRetention time.Duration `mapstructure:"ACCOUNT_EVENT_RETENTION"` // How long stream events are kept for clients resuming by event ID
+    AccountEventCleanup   time.Duration `mapstructure:"ACCOUNT_EVENT_CLEANUP"`   // How often the worker purges older events
+
+    TracingExporter    string  `mapstructure:"TRACING_EXPORTER"`     // none, otlp (OTEL_EXPORTER_OTLP_ENDPOINT etc.) or stdout
+    TracingFile        string  `mapstructure:"TRACING_FILE"`         // stdout exporter only: write spans to this file instead
+    TracingSampleRatio float64 `mapstructure:"TRACING_SAMPLE_RATIO"` // Fraction of new traces recorded (0-1)
+
+    StorageDir string `mapstructure:"STORAGE_DIR"` // Local root for uploaded KYC documents
+
+    AMLScanInterval time.Duration `mapstructure:"AML_SCAN_INTERVAL"` // How often the worker runs AML monitoring
+
+    ChainCheckpointInterval time.Duration `mapstructure:"CHAIN_CHECKPOINT_INTERVAL"` // How often the worker signs ledger/audit hash chain checkpoints
+    ChainCheckpointDir      string        `mapstructure:"CHAIN_CHECKPOINT_DIR"`      // Where signed checkpoints are exported; keep a copy off this host
+
+    SanctionsListDir        string  `mapstructure:"SANCTIONS_LIST_DIR"`        // Directory of watchlist .csv/.xml files
+    SanctionsMatchThreshold float64 `mapstructure:"SANCTIONS_MATCH_THRESHOLD"` // Minimum name similarity (0-1) held for review
+
+    MFAEncryptionKey       string `mapstructure:"MFA_ENCRYPTION_KEY"`        // Encrypts stored TOTP secrets; defaults to JWT_SECRET
+    StepUpPaymentThreshold int64  `mapstructure:"STEP_UP_PAYMENT_THRESHOLD"` // Transfers/withdrawals from this many kobo need a fresh second factor
+    NewDeviceStepUp        bool   `mapstructure:"NEW_DEVICE_STEP_UP"`        // Sign-ins from unrecognised devices must enter an emailed code
+
+    JWTKeysDir    string        `mapstructure:"JWT_KEYS_DIR"`    // keys.json manifest + PEM private keys; a throwaway key is used if missing
+    JWTIssuer     string        `mapstructure:"JWT_ISSUER"`      // iss claim set and required on access tokens
+    JWTAudience   string        `mapstructure:"JWT_AUDIENCE"`    // aud claim set and required on access tokens
+    JWTKeyOverlap time.Duration `mapstructure:"JWT_KEY_OVERLAP"` // How long a rotated-out key keeps verifying; at least the token lifetime
 }
 
 func LoadConfig() Config {
     viper.SetConfigFile(".env")
     viper.AutomaticEnv()
 
+    viper.SetDefault("PORT", "8080")
+    viper.SetDefault("APP_BASE_URL", "http://localhost:8080")
+    viper.SetDefault("HEALTH_CACHE_TTL", "5s")
+    viper.SetDefault("IDEMPOTENCY_KEY_TTL", "24h")
+    viper.SetDefault("IDEMPOTENCY_WAIT", "10s")
+    viper.SetDefault("IDEMPOTENCY_CLEANUP", "1h")
+    viper.SetDefault("ACCOUNT_EVENT_RETENTION", "168h")
+    viper.SetDefault("ACCOUNT_EVENT_CLEANUP", "1h")
+    viper.SetDefault("TRACING_EXPORTER", "none")
+    viper.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
+    viper.SetDefault("STORAGE_DIR", "./data/uploads")
+    viper.SetDefault("AML_SCAN_INTERVAL", "1h")
+    viper.SetDefault("CHAIN_CHECKPOINT_INTERVAL", "1h")
+    viper.SetDefault("CHAIN_CHECKPOINT_DIR", "./data/checkpoints")
+    viper.SetDefault("SANCTIONS_LIST_DIR", "./data/watchlists")
+    viper.SetDefault("SANCTIONS_MATCH_THRESHOLD", 0.9)
+    viper.SetDefault("STEP_UP_PAYMENT_THRESHOLD", 5000000) // ₦50,000.00
+    viper.SetDefault("NEW_DEVICE_STEP_UP", false)
+    viper.SetDefault("JWT_KEYS_DIR", "./data/jwt-keys")
+    viper.SetDefault("JWT_ISSUER", "debank")
+    viper.SetDefault("JWT_AUDIENCE", "debank-api")
+    viper.SetDefault("JWT_KEY_OVERLAP", "24h")
+    viper.SetDefault("GRPC_INSECURE", false)
+    // AutomaticEnv only applies to keys viper already knows about
+    viper.BindEnv("DB_URL")
+    viper.BindEnv("JWT_SECRET")
+    viper.BindEnv("MFA_ENCRYPTION_KEY")
+    viper.BindEnv("METRICS_TOKEN")
+    viper.BindEnv("TRACING_FILE")
+    viper.BindEnv("GRPC_PORT")
+    viper.BindEnv("GRPC_TLS_CERT")
+    viper.BindEnv("GRPC_TLS_KEY")
+    viper.BindEnv("GRPC_CLIENT_CA")
+    viper.BindEnv("GRPC_ALLOWED_CLIENTS")
+
     if err := viper.ReadInConfig(); err != nil {
         log.Println("No .env file found, using env variables only")
     }
@@ -22,6 +102,55 @@ func LoadConfig() Config {
         log.Fatal("config unmarshal error:", err)
     }
 
+    if c.HealthCacheTTL < 0 {
+        log.Fatal("HEALTH_CACHE_TTL must not be negative")
+    }
+
+    if c.IdempotencyKeyTTL <= 0 || c.IdempotencyWait < 0 || c.IdempotencyCleanup <= 0 {
+        log.Fatal("IDEMPOTENCY_KEY_TTL and IDEMPOTENCY_CLEANUP must be positive durations, IDEMPOTENCY_WAIT not negative")
+    }
+
+    if c.AccountEventRetention <= 0 || c.AccountEventCleanup <= 0 {
+        log.Fatal("ACCOUNT_EVENT_RETENTION and ACCOUNT_EVENT_CLEANUP must be positive durations")
+    }
+
+    if !tracing.IsValidExporter(c.TracingExporter) {
+        log.Fatal("TRACING_EXPORTER must be none, otlp or stdout")
+    }
+
+    if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+        log.Fatal("TRACING_SAMPLE_RATIO must be between 0 and 1")
+    }
+
+    if c.GRPCPort != "" && !c.GRPCInsecure && (c.GRPCTLSCert == "" || c.GRPCTLSKey == "" || c.GRPCClientCA == "") {
+        log.Fatal("GRPC_TLS_CERT, GRPC_TLS_KEY and GRPC_CLIENT_CA must be set when GRPC_PORT is, unless GRPC_INSECURE=true")
+    }
+
+    if c.AMLScanInterval <= 0 {
+        log.Fatal("AML_SCAN_INTERVAL must be a positive duration")
+    }
+
+    if c.ChainCheckpointInterval <= 0 {
+        log.Fatal("CHAIN_CHECKPOINT_INTERVAL must be a positive duration")
+    }
+
+    if c.SanctionsMatchThreshold <= 0 || c.SanctionsMatchThreshold > 1 {
+        log.Fatal("SANCTIONS_MATCH_THRESHOLD must be between 0 and 1")
+    }
+
+    if c.StepUpPaymentThreshold <= 0 {
+        log.Fatal("STEP_UP_PAYMENT_THRESHOLD must be a positive amount in kobo")
+    }
+
+    if c.MFAEncryptionKey == "" {
+        if c.JWTSecret == "" {
+            log.Fatal("MFA_ENCRYPTION_KEY must be set")
+        }
+        // Changing either secret later makes enrolled authenticators unreadable
+        log.Println("MFA_ENCRYPTION_KEY not set, falling back to JWT_SECRET")
+        c.MFAEncryptionKey = c.JWTSecret
+    }
+
     log.Printf("DEBUG: Using DB_URL: %s", c.DBUrl)
     return c
-}
\ No newline at end of file
+}
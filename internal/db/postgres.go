@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/Brownie44l1/debank/internal/tracing"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -19,6 +20,9 @@ func NewPool(ctx context.Context, dbURL string) (*pgxpool.Pool, error) {
 	config.MaxConns = 25
 	config.MinConns = 5
 
+	// Spans for every statement and pool wait; a no-op unless tracing is set up
+	config.ConnConfig.Tracer = tracing.DBTracer{}
+
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create connection pool: %w", err)
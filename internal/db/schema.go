@@ -0,0 +1,32 @@
+package db
+
+import (
+	"embed"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// schemaFiles are the numbered migrations shipped with this build
+//
+//go:embed schema/*.sql
+var schemaFiles embed.FS
+
+// SchemaVersion is the number of the newest schema file this build expects to be applied
+// Readiness compares it with the highest version recorded in schema_migrations
+func SchemaVersion() int {
+	names, err := fs.Glob(schemaFiles, "schema/[0-9][0-9][0-9]_*.sql")
+	if err != nil {
+		return 0
+	}
+
+	latest := 0
+	for _, name := range names {
+		prefix, _, _ := strings.Cut(path.Base(name), "_")
+		if v, err := strconv.Atoi(prefix); err == nil && v > latest {
+			latest = v
+		}
+	}
+	return latest
+}
@@ -0,0 +1,54 @@
+-- ============================================
+-- SCHEMA: PAYMENT REQUESTS (Pull payments)
+-- ============================================
+-- A requester asks a payer for money. The request is either
+-- addressed to a specific user (@username / phone) or left open
+-- and shared as a link carrying its token.
+-- Settlement always goes through the normal P2P transfer path.
+-- ============================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS payment_requests CASCADE;
+
+CREATE TABLE payment_requests (
+    id BIGSERIAL PRIMARY KEY,
+    token TEXT UNIQUE NOT NULL,           -- Shareable link token
+
+    requester_user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    payer_user_id INT REFERENCES users(id) ON DELETE SET NULL, -- NULL = open link
+    payer_identifier TEXT,                -- @username / phone as entered
+
+    amount BIGINT NOT NULL,               -- In kobo
+    currency CHAR(3) NOT NULL DEFAULT 'NGN',
+    description TEXT,
+
+    status TEXT NOT NULL DEFAULT 'open',
+    transaction_id BIGINT REFERENCES transactions(id),
+    paid_by_user_id INT REFERENCES users(id) ON DELETE SET NULL,
+    decline_reason TEXT,
+
+    expires_at TIMESTAMPTZ NOT NULL,
+    paid_at TIMESTAMPTZ,
+    declined_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT positive_amount CHECK (amount > 0),
+    CONSTRAINT valid_payment_request_status CHECK (status IN ('open', 'paid', 'declined', 'expired')),
+    CONSTRAINT not_self_request CHECK (payer_user_id IS NULL OR payer_user_id <> requester_user_id)
+);
+
+CREATE INDEX idx_payment_requests_requester ON payment_requests(requester_user_id, created_at DESC);
+CREATE INDEX idx_payment_requests_payer ON payment_requests(payer_user_id, created_at DESC) WHERE payer_user_id IS NOT NULL;
+CREATE INDEX idx_payment_requests_paid_by ON payment_requests(paid_by_user_id) WHERE paid_by_user_id IS NOT NULL;
+CREATE INDEX idx_payment_requests_open_expiry ON payment_requests(expires_at) WHERE status = 'open';
+
+CREATE TRIGGER update_payment_requests_updated_at
+BEFORE UPDATE ON payment_requests
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+COMMIT;
+
+\echo '=== Payment requests schema created successfully ==='
@@ -0,0 +1,35 @@
+-- ============================================
+-- SCHEMA: BENEFICIARIES (Saved recipients)
+-- ============================================
+-- A user's address book of accounts they send money to.
+-- Recent / frequent recipients are derived from transactions
+-- and are not stored here.
+-- ============================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS beneficiaries CASCADE;
+
+CREATE TABLE beneficiaries (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    account_id BIGINT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    identifier TEXT NOT NULL,             -- @username / phone / account number as entered, used to pay
+    nickname TEXT,
+
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT unique_beneficiary UNIQUE (user_id, account_id)
+);
+
+CREATE INDEX idx_beneficiaries_user ON beneficiaries(user_id, created_at DESC);
+
+CREATE TRIGGER update_beneficiaries_updated_at
+BEFORE UPDATE ON beneficiaries
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+COMMIT;
+
+\echo '=== Beneficiaries schema created successfully ==='
@@ -0,0 +1,56 @@
+-- ============================================
+-- SCHEMA: KYC TIERS (Per-tier limits)
+-- ============================================
+-- Tier 1: phone + email verified
+-- Tier 2: + government ID number (BVN / NIN)
+-- Tier 3: + address and supporting documents
+-- All limits are in kobo. Daily / monthly debit caps are
+-- rolling windows (24 hours / 30 days) computed from postings.
+-- ============================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS kyc_tiers CASCADE;
+
+CREATE TABLE kyc_tiers (
+    tier INT PRIMARY KEY,
+    name TEXT NOT NULL,
+    requirements TEXT NOT NULL,
+
+    max_single_transaction BIGINT NOT NULL,
+    daily_debit_limit BIGINT NOT NULL,
+    monthly_debit_limit BIGINT NOT NULL,
+    max_balance BIGINT,                   -- NULL = unlimited
+
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT positive_limits CHECK (
+        max_single_transaction > 0 AND
+        daily_debit_limit > 0 AND
+        monthly_debit_limit > 0 AND
+        (max_balance IS NULL OR max_balance > 0)
+    )
+);
+
+INSERT INTO kyc_tiers (tier, name, requirements, max_single_transaction, daily_debit_limit, monthly_debit_limit, max_balance) VALUES
+    (1, 'Tier 1', 'Verified phone number and email',          5000000,   5000000,   30000000,  30000000),  -- ₦50k / ₦50k / ₦300k / ₦300k
+    (2, 'Tier 2', 'Tier 1 plus a verified BVN or NIN',         10000000,  20000000,  100000000, 50000000),  -- ₦100k / ₦200k / ₦1m / ₦500k
+    (3, 'Tier 3', 'Tier 2 plus verified address and documents', 100000000, 500000000, 2000000000, NULL);   -- ₦1m / ₦5m / ₦20m / unlimited
+
+CREATE TRIGGER update_kyc_tiers_updated_at
+BEFORE UPDATE ON kyc_tiers
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- Every user starts on tier 1
+ALTER TABLE users DROP COLUMN IF EXISTS kyc_tier;
+ALTER TABLE users DROP COLUMN IF EXISTS kyc_tier_updated_at;
+ALTER TABLE users ADD COLUMN kyc_tier INT NOT NULL DEFAULT 1 REFERENCES kyc_tiers(tier);
+ALTER TABLE users ADD COLUMN kyc_tier_updated_at TIMESTAMPTZ;
+
+CREATE INDEX idx_postings_debits ON postings(account_id, created_at) WHERE amount < 0;
+
+COMMIT;
+
+\echo '=== KYC tiers schema created successfully ==='
@@ -0,0 +1,77 @@
+-- ============================================
+-- SCHEMA: KYC SUBMISSIONS + DOCUMENTS
+-- ============================================
+-- A user submits ID details (and documents for tier 3) to move
+-- up a tier. An automated identity check records its result,
+-- but the tier only changes once a reviewer approves.
+-- Document bytes live in the storage backend; only keys are here.
+-- ============================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS kyc_documents CASCADE;
+DROP TABLE IF EXISTS kyc_submissions CASCADE;
+
+CREATE TABLE kyc_submissions (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    target_tier INT NOT NULL REFERENCES kyc_tiers(tier),
+
+    id_type TEXT NOT NULL,
+    id_number TEXT NOT NULL,
+    address TEXT,
+
+    status TEXT NOT NULL DEFAULT 'pending',
+
+    -- Automated identity check (BVN / NIN lookup)
+    verification_status TEXT NOT NULL,
+    verification_reference TEXT,
+    verified_name TEXT,
+
+    -- Manual review
+    reviewer_user_id INT REFERENCES users(id) ON DELETE SET NULL,
+    review_reason TEXT,
+    reviewed_at TIMESTAMPTZ,
+
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT valid_kyc_id_type CHECK (id_type IN ('bvn', 'nin')),
+    CONSTRAINT valid_kyc_submission_status CHECK (status IN ('pending', 'approved', 'rejected')),
+    CONSTRAINT valid_kyc_verification_status CHECK (verification_status IN ('matched', 'mismatch', 'not_found', 'error'))
+);
+
+-- At most one open submission per user
+CREATE UNIQUE INDEX idx_kyc_submissions_one_pending ON kyc_submissions(user_id) WHERE status = 'pending';
+CREATE INDEX idx_kyc_submissions_user ON kyc_submissions(user_id, created_at DESC);
+CREATE INDEX idx_kyc_submissions_queue ON kyc_submissions(status, created_at);
+
+CREATE TRIGGER update_kyc_submissions_updated_at
+BEFORE UPDATE ON kyc_submissions
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE kyc_documents (
+    id BIGSERIAL PRIMARY KEY,
+    submission_id BIGINT NOT NULL REFERENCES kyc_submissions(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+    document_type TEXT NOT NULL,
+    storage_key TEXT UNIQUE NOT NULL,
+    file_name TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    sha256 TEXT NOT NULL,
+
+    created_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT valid_kyc_document_type CHECK (document_type IN (
+        'id_card', 'passport', 'drivers_license', 'utility_bill', 'selfie'
+    ))
+);
+
+CREATE INDEX idx_kyc_documents_submission ON kyc_documents(submission_id);
+
+COMMIT;
+
+\echo '=== KYC submissions schema created successfully ==='
@@ -0,0 +1,80 @@
+-- ============================================
+-- SCHEMA: RISK RULES + DECISIONS
+-- ============================================
+-- Every withdrawal and transfer is screened before it posts.
+-- Rules are rows so analysts can tune thresholds or switch a
+-- rule off without a deploy. Each screening writes a decision
+-- with the rules that fired, including allowed ones.
+-- ============================================
+
+BEGIN;
+
+-- Set by the forgot-password flow (not by a normal password change)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_reset_at TIMESTAMPTZ;
+
+DROP TABLE IF EXISTS risk_decisions CASCADE;
+DROP TABLE IF EXISTS risk_rules CASCADE;
+
+CREATE TABLE risk_rules (
+    code TEXT PRIMARY KEY,
+    description TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    action TEXT NOT NULL,                  -- Outcome when the rule fires
+    params JSONB NOT NULL DEFAULT '{}',    -- Thresholds (amounts in kobo)
+    updated_by INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT valid_risk_rule_action CHECK (action IN ('challenge', 'block'))
+);
+
+CREATE TRIGGER update_risk_rules_updated_at
+BEFORE UPDATE ON risk_rules
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+INSERT INTO risk_rules (code, description, action, params) VALUES
+    ('velocity',
+     'Too many outgoing payments in a short window',
+     'challenge', '{"max_count": 5, "window_minutes": 10}'),
+    ('new_device_large_amount',
+     'Large payment from a device with no recent history',
+     'challenge', '{"min_amount": 5000000, "min_device_age_hours": 24}'),
+    ('new_beneficiary_large_amount',
+     'First transfer to a recipient above a threshold',
+     'challenge', '{"min_amount": 2000000}'),
+    ('recent_password_reset',
+     'Outgoing payment shortly after a password reset',
+     'block', '{"min_amount": 1000000, "window_hours": 24}');
+
+CREATE TABLE risk_decisions (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    account_id BIGINT NOT NULL REFERENCES accounts(id),
+    operation TEXT NOT NULL,
+    amount BIGINT NOT NULL,
+    counterparty_account_id BIGINT REFERENCES accounts(id),
+    idempotency_key TEXT,
+
+    -- Client context at the time of the request
+    device_id TEXT,
+    ip_address TEXT,
+    user_agent TEXT,
+
+    outcome TEXT NOT NULL,
+    fired_rules JSONB NOT NULL DEFAULT '[]',
+    transaction_id BIGINT REFERENCES transactions(id) ON DELETE SET NULL, -- Set when allowed and posted
+
+    created_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT valid_risk_operation CHECK (operation IN ('withdraw', 'transfer')),
+    CONSTRAINT valid_risk_outcome CHECK (outcome IN ('allow', 'challenge', 'block'))
+);
+
+CREATE INDEX idx_risk_decisions_user ON risk_decisions(user_id, created_at DESC);
+CREATE INDEX idx_risk_decisions_flagged ON risk_decisions(outcome, created_at DESC) WHERE outcome <> 'allow';
+CREATE INDEX idx_risk_decisions_device ON risk_decisions(user_id, device_id, created_at) WHERE device_id IS NOT NULL;
+
+COMMIT;
+
+\echo '=== Risk schema created successfully ==='
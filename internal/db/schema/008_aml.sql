@@ -0,0 +1,90 @@
+-- ============================================
+-- SCHEMA: AML MONITORING + CASES
+-- ============================================
+-- A batch job scans recent postings for suspicious patterns
+-- (structuring, rapid in-and-out, fan-in/fan-out, dormant
+-- accounts waking up) and opens a case per account and pattern.
+-- Compliance staff assign, annotate, escalate and close cases.
+-- ============================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS aml_case_notes CASCADE;
+DROP TABLE IF EXISTS aml_case_transactions CASCADE;
+DROP TABLE IF EXISTS aml_cases CASCADE;
+DROP TABLE IF EXISTS aml_scan_runs CASCADE;
+
+CREATE TABLE aml_scan_runs (
+    id BIGSERIAL PRIMARY KEY,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    finished_at TIMESTAMPTZ,
+    cases_opened INT NOT NULL DEFAULT 0,
+    cases_updated INT NOT NULL DEFAULT 0,
+    error TEXT
+);
+
+CREATE TABLE aml_cases (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts(id),
+    user_id INT REFERENCES users(id) ON DELETE SET NULL,
+
+    typology TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'open',
+    summary TEXT NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',     -- Metrics from the latest detection
+    scan_run_id BIGINT REFERENCES aml_scan_runs(id) ON DELETE SET NULL,
+    last_detected_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    assigned_to INT REFERENCES users(id) ON DELETE SET NULL,
+    assigned_at TIMESTAMPTZ,
+
+    escalated_by INT REFERENCES users(id) ON DELETE SET NULL,
+    escalated_at TIMESTAMPTZ,
+    account_frozen BOOLEAN NOT NULL DEFAULT FALSE,  -- Frozen because of this case
+
+    resolution TEXT,
+    closed_by INT REFERENCES users(id) ON DELETE SET NULL,
+    closed_at TIMESTAMPTZ,
+
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT valid_aml_typology CHECK (typology IN (
+        'structuring', 'rapid_in_out', 'fan_in', 'fan_out', 'dormant_reactivation'
+    )),
+    CONSTRAINT valid_aml_status CHECK (status IN ('open', 'escalated', 'closed')),
+    CONSTRAINT valid_aml_resolution CHECK (resolution IS NULL OR resolution IN (
+        'false_positive', 'no_action', 'reported'
+    ))
+);
+
+-- Re-detections add to the live case instead of opening another
+CREATE UNIQUE INDEX idx_aml_cases_one_live ON aml_cases(account_id, typology) WHERE status <> 'closed';
+CREATE INDEX idx_aml_cases_queue ON aml_cases(status, created_at DESC);
+CREATE INDEX idx_aml_cases_assignee ON aml_cases(assigned_to) WHERE status <> 'closed';
+
+CREATE TRIGGER update_aml_cases_updated_at
+BEFORE UPDATE ON aml_cases
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE aml_case_transactions (
+    case_id BIGINT NOT NULL REFERENCES aml_cases(id) ON DELETE CASCADE,
+    transaction_id BIGINT NOT NULL REFERENCES transactions(id),
+    added_at TIMESTAMPTZ DEFAULT now(),
+    PRIMARY KEY (case_id, transaction_id)
+);
+
+CREATE TABLE aml_case_notes (
+    id BIGSERIAL PRIMARY KEY,
+    case_id BIGINT NOT NULL REFERENCES aml_cases(id) ON DELETE CASCADE,
+    author_id INT REFERENCES users(id) ON DELETE SET NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX idx_aml_case_notes_case ON aml_case_notes(case_id, created_at);
+
+COMMIT;
+
+\echo '=== AML schema created successfully ==='
@@ -0,0 +1,60 @@
+-- ============================================
+-- SCHEMA: SANCTIONS / WATCHLIST SCREENING
+-- ============================================
+-- Names are screened against watchlists loaded from local files
+-- at signup, on KYC upgrade submissions and when a beneficiary is
+-- saved. Every potential match is stored as a hit and the subject
+-- is held (account frozen, submission not approvable, beneficiary
+-- not payable) until compliance clears or confirms each hit.
+-- ============================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS screening_hits CASCADE;
+
+CREATE TABLE screening_hits (
+    id BIGSERIAL PRIMARY KEY,
+    subject_type TEXT NOT NULL,
+    subject_id BIGINT NOT NULL,          -- users.id / kyc_submissions.id / beneficiaries.id
+    user_id INT REFERENCES users(id) ON DELETE CASCADE,
+    screened_name TEXT NOT NULL,
+
+    list_name TEXT NOT NULL,
+    entry_uid TEXT NOT NULL,
+    entry_name TEXT NOT NULL,
+    matched_name TEXT NOT NULL,          -- Primary name or alias that matched
+    entry_type TEXT,
+    program TEXT,
+    score NUMERIC(5,4) NOT NULL,
+
+    status TEXT NOT NULL DEFAULT 'pending',
+    account_frozen BOOLEAN NOT NULL DEFAULT FALSE,  -- Signup hit that froze the account
+    reviewed_by INT REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ,
+    review_note TEXT,
+
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT valid_screening_subject CHECK (subject_type IN ('signup', 'kyc_submission', 'beneficiary')),
+    CONSTRAINT valid_screening_status CHECK (status IN ('pending', 'cleared', 'confirmed')),
+    CONSTRAINT unique_screening_hit UNIQUE (subject_type, subject_id, list_name, entry_uid)
+);
+
+CREATE INDEX idx_screening_hits_queue ON screening_hits(status, created_at DESC);
+CREATE INDEX idx_screening_hits_subject ON screening_hits(subject_type, subject_id);
+CREATE INDEX idx_screening_hits_user ON screening_hits(user_id);
+
+CREATE TRIGGER update_screening_hits_updated_at
+BEFORE UPDATE ON screening_hits
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- Beneficiaries with a potential match can't be paid until reviewed
+ALTER TABLE beneficiaries ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active';
+ALTER TABLE beneficiaries DROP CONSTRAINT IF EXISTS valid_beneficiary_status;
+ALTER TABLE beneficiaries ADD CONSTRAINT valid_beneficiary_status CHECK (status IN ('active', 'held', 'blocked'));
+
+COMMIT;
+
+\echo '=== Screening schema created successfully ==='
@@ -0,0 +1,65 @@
+-- ============================================
+-- SCHEMA: TWO-FACTOR AUTHENTICATION
+-- ============================================
+-- Optional second factor at login: a TOTP authenticator app, or
+-- a code emailed to the user. Login becomes two steps; the first
+-- returns a short-lived challenge that the second completes with
+-- a code. Single-use recovery codes cover a lost device.
+-- ============================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS mfa_challenges CASCADE;
+DROP TABLE IF EXISTS mfa_recovery_codes CASCADE;
+DROP TABLE IF EXISTS user_mfa CASCADE;
+
+CREATE TABLE user_mfa (
+    user_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    method TEXT NOT NULL,
+    totp_secret TEXT,                    -- AES-GCM encrypted, only for method 'totp'
+    last_totp_step BIGINT,               -- Last accepted TOTP time step, so a code can't be replayed
+    enabled_at TIMESTAMPTZ,              -- NULL while a TOTP enrollment awaits its first code
+
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT valid_mfa_method CHECK (method IN ('totp', 'email')),
+    CONSTRAINT totp_has_secret CHECK (method <> 'totp' OR totp_secret IS NOT NULL)
+);
+
+CREATE TRIGGER update_user_mfa_updated_at
+BEFORE UPDATE ON user_mfa
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE mfa_recovery_codes (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash TEXT NOT NULL,             -- SHA-256 of the normalised code
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT unique_recovery_code UNIQUE (user_id, code_hash)
+);
+
+CREATE INDEX idx_mfa_recovery_codes_unused ON mfa_recovery_codes(user_id) WHERE used_at IS NULL;
+
+CREATE TABLE mfa_challenges (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash TEXT UNIQUE NOT NULL,     -- SHA-256 of the token handed to the client
+    method TEXT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    completed_at TIMESTAMPTZ,
+    ip_address TEXT,
+    user_agent TEXT,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX idx_mfa_challenges_user ON mfa_challenges(user_id, created_at DESC);
+CREATE INDEX idx_mfa_challenges_expires ON mfa_challenges(expires_at) WHERE completed_at IS NULL;
+
+COMMIT;
+
+\echo '=== MFA schema created successfully ==='
@@ -0,0 +1,41 @@
+-- ============================================
+-- SCHEMA: STEP-UP AUTHENTICATION
+-- ============================================
+-- Sensitive operations (large payments, contact and PIN changes,
+-- new beneficiaries) need a fresh second factor. The first attempt
+-- returns a challenge bound to a hash of the exact operation; the
+-- retry carries the challenge token and a TOTP or emailed code.
+-- ============================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS step_up_challenges CASCADE;
+
+CREATE TABLE step_up_challenges (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash TEXT UNIQUE NOT NULL,     -- SHA-256 of the token handed to the client
+    action TEXT NOT NULL,
+    binding_hash TEXT NOT NULL,          -- SHA-256 of the action and its parameters
+    method TEXT NOT NULL,                -- How the code is expected: 'totp' or 'email'
+    otp_id INT REFERENCES verification_codes(id) ON DELETE SET NULL, -- The emailed code, if any
+    risk_decision_id BIGINT REFERENCES risk_decisions(id),          -- Set when a risk rule asked for the challenge
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    consumed_at TIMESTAMPTZ,
+    ip_address TEXT,
+    user_agent TEXT,
+    created_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT valid_step_up_action CHECK (action IN (
+        'transfer', 'withdraw', 'email_change', 'phone_change', 'pin_change', 'beneficiary_add'
+    )),
+    CONSTRAINT valid_step_up_method CHECK (method IN ('totp', 'email'))
+);
+
+CREATE INDEX idx_step_up_challenges_user ON step_up_challenges(user_id, created_at DESC);
+CREATE INDEX idx_step_up_challenges_expires ON step_up_challenges(expires_at) WHERE consumed_at IS NULL;
+
+COMMIT;
+
+\echo '=== Step-up schema created successfully ==='
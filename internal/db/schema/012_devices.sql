@@ -0,0 +1,56 @@
+-- ============================================
+-- SCHEMA: DEVICE REGISTRY AND SESSIONS
+-- ============================================
+-- Clients identify themselves with a device fingerprint (X-Device-ID).
+-- Each user keeps a registry of the devices they have signed in from;
+-- a sign-in from a device that isn't in it (or was revoked) alerts the
+-- user by email and can be made to pass an emailed-code challenge.
+-- Every access token belongs to a login session, and revoking a device
+-- revokes all of its sessions.
+-- ============================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS user_devices CASCADE;
+
+CREATE TABLE user_devices (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    fingerprint TEXT NOT NULL,           -- As sent in X-Device-ID
+    name TEXT NOT NULL,                  -- Set by the client at sign-in, renameable by the user
+    user_agent TEXT,
+    last_ip TEXT,
+    first_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT unique_user_device UNIQUE (user_id, fingerprint),
+    CONSTRAINT device_name_length CHECK (char_length(name) BETWEEN 1 AND 64)
+);
+
+CREATE INDEX idx_user_devices_user ON user_devices(user_id, last_seen_at DESC);
+
+CREATE TRIGGER update_user_devices_updated_at
+    BEFORE UPDATE ON user_devices
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Sessions now record the device they were opened from; token holds the
+-- SHA-256 of the session ID carried in the access token's jti claim
+ALTER TABLE login_sessions
+    ADD COLUMN device_id BIGINT REFERENCES user_devices(id) ON DELETE SET NULL,
+    ADD COLUMN user_agent TEXT;
+
+CREATE INDEX idx_login_sessions_device ON login_sessions(device_id) WHERE revoked_at IS NULL;
+
+-- Login challenges remember why they were issued and which device asked
+ALTER TABLE mfa_challenges
+    ADD COLUMN reason TEXT NOT NULL DEFAULT 'mfa',
+    ADD COLUMN device_fingerprint TEXT,
+    ADD CONSTRAINT valid_mfa_challenge_reason CHECK (reason IN ('mfa', 'new_device'));
+
+COMMIT;
+
+\echo '=== Device registry schema created successfully ==='
@@ -0,0 +1,24 @@
+-- ============================================
+-- SCHEMA: ROLES
+-- ============================================
+-- Every user has exactly one role. Customers are 'user'; staff roles
+-- unlock the /api/v1/admin routes according to the permission table in
+-- internal/auth/rbac.go. The role is copied into each access token, so
+-- changing it ends the user's sessions.
+--
+-- Bootstrap the first superadmin by hand:
+--   UPDATE users SET role = 'superadmin' WHERE id = <id>;
+-- ============================================
+
+BEGIN;
+
+ALTER TABLE users
+    ADD COLUMN role TEXT NOT NULL DEFAULT 'user',
+    ADD CONSTRAINT valid_user_role CHECK (role IN ('user', 'support', 'compliance', 'admin', 'superadmin'));
+
+-- Staff are few; this keeps "who has access" queries cheap
+CREATE INDEX idx_users_staff_role ON users(role) WHERE role <> 'user';
+
+COMMIT;
+
+\echo '=== Roles schema created successfully ==='
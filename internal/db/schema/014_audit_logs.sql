@@ -0,0 +1,21 @@
+-- ============================================
+-- SCHEMA: AUDIT LOG QUERIES
+-- ============================================
+-- Sign-ins, credential changes, money movements and staff actions are all
+-- written to audit_logs. Users read their own security history (entries
+-- they made, or made on their account) and staff search by actor, entity
+-- and time range, so both are served newest-first from an index.
+-- ============================================
+
+BEGIN;
+
+-- Entries on a record (a user, a transaction, a device), newest first
+CREATE INDEX idx_audit_logs_entity ON audit_logs(entity_type, entity_id, created_at DESC);
+
+-- Entries by an actor, newest first; supersedes the plain user_id index
+CREATE INDEX idx_audit_logs_user_created ON audit_logs(user_id, created_at DESC);
+DROP INDEX IF EXISTS idx_audit_logs_user_id;
+
+COMMIT;
+
+\echo '=== Audit log indexes created successfully ==='
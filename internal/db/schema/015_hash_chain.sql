@@ -0,0 +1,63 @@
+-- ============================================
+-- SCHEMA: TAMPER-EVIDENT HASH CHAIN
+-- ============================================
+-- Every transactions and audit_logs row carries row_hash = SHA-256 over
+-- prev_hash and the row's canonical fields (pkg/hashchain), so editing,
+-- deleting or reordering a row breaks every link after it.
+--
+-- A row is linked in the transaction that inserts it, while holding its
+-- chain's row in hash_chain_heads FOR UPDATE; ids are allocated under that
+-- lock, so id order is chain order. Transactions take the transactions
+-- head before the audit_logs head, never the reverse.
+--
+-- Lifecycle columns (transactions.status, posted_at, failed_at,
+-- failure_reason) are left out of the hash so a pending payment can still
+-- settle. Rows written before this migration are not chained; verification
+-- starts at hash_chain_heads.start_id.
+--
+-- chain_checkpoints holds periodic signed snapshots of each head. They are
+-- signed with the JWT keyring and also exported as files, so history can
+-- be checked against a copy kept outside the database.
+-- ============================================
+
+BEGIN;
+
+ALTER TABLE transactions
+    ADD COLUMN prev_hash BYTEA,
+    ADD COLUMN row_hash BYTEA;
+
+ALTER TABLE audit_logs
+    ADD COLUMN prev_hash BYTEA,
+    ADD COLUMN row_hash BYTEA;
+
+CREATE TABLE hash_chain_heads (
+    chain TEXT PRIMARY KEY,
+    start_id BIGINT,                   -- First chained row
+    last_id BIGINT,
+    last_hash BYTEA NOT NULL,          -- Genesis (32 zero bytes) until the first row
+    rows BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT valid_chain CHECK (chain IN ('transactions', 'audit_logs'))
+);
+
+INSERT INTO hash_chain_heads (chain, last_hash) VALUES
+    ('transactions', decode(repeat('00', 32), 'hex')),
+    ('audit_logs', decode(repeat('00', 32), 'hex'));
+
+CREATE TABLE chain_checkpoints (
+    id BIGSERIAL PRIMARY KEY,
+    chain TEXT NOT NULL REFERENCES hash_chain_heads(chain),
+    last_id BIGINT NOT NULL,
+    last_hash BYTEA NOT NULL,
+    rows BIGINT NOT NULL,
+    key_id TEXT NOT NULL,
+    signature TEXT NOT NULL,           -- Compact JWS, verifiable against /.well-known/jwks.json
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_chain_checkpoints_chain ON chain_checkpoints(chain, id DESC);
+
+COMMIT;
+
+\echo '=== Hash chain schema created successfully ==='
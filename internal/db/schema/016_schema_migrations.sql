@@ -0,0 +1,40 @@
+-- ============================================
+-- SCHEMA: MIGRATION TRACKING
+-- ============================================
+-- schema_migrations records which numbered schema files have been applied.
+-- The readiness check compares its highest version with the newest file
+-- compiled into the binary, so a deploy that skipped a migration is not
+-- sent traffic.
+--
+-- Files run in order, so reaching this one means 001-015 are in place;
+-- they are backfilled here. Every later file must end by inserting its
+-- own version.
+-- ============================================
+
+BEGIN;
+
+CREATE TABLE schema_migrations (
+    version INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO schema_migrations (version, name) VALUES
+    (1, '001_schema.sql'),
+    (2, '002_functions.sql'),
+    (3, '003_payment_requests.sql'),
+    (4, '004_beneficiaries.sql'),
+    (5, '005_kyc_tiers.sql'),
+    (6, '006_kyc_submissions.sql'),
+    (7, '007_risk.sql'),
+    (8, '008_aml.sql'),
+    (9, '009_screening.sql'),
+    (10, '010_mfa.sql'),
+    (11, '011_step_up.sql'),
+    (12, '012_devices.sql'),
+    (13, '013_roles.sql'),
+    (14, '014_audit_logs.sql'),
+    (15, '015_hash_chain.sql'),
+    (16, '016_schema_migrations.sql');
+
+COMMIT;
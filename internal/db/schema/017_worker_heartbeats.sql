@@ -0,0 +1,22 @@
+-- ============================================
+-- SCHEMA: WORKER HEARTBEATS
+-- ============================================
+-- cmd/worker upserts a row per job after every run. The API's readiness
+-- check reports jobs that have stopped succeeding, so a dead worker
+-- (no AML scans, no hash chain checkpoints) is noticed.
+-- ============================================
+
+BEGIN;
+
+CREATE TABLE worker_heartbeats (
+    job TEXT PRIMARY KEY,
+    interval_seconds BIGINT NOT NULL CHECK (interval_seconds > 0),
+    last_run_at TIMESTAMPTZ NOT NULL,
+    last_success_at TIMESTAMPTZ,
+    last_error TEXT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO schema_migrations (version, name) VALUES (17, '017_worker_heartbeats.sql');
+
+COMMIT;
@@ -0,0 +1,41 @@
+-- ============================================
+-- SCHEMA: IDEMPOTENCY KEYS
+-- ============================================
+-- Backs the Idempotency-Key header on authenticated mutating endpoints.
+-- A key is scoped to the user and the route (method + path template) and
+-- remembers a SHA-256 fingerprint of the request, so reusing it for a
+-- different payload is refused instead of replayed.
+--
+-- A request claims its key as in_progress with a lease (locked_until);
+-- retries arriving meanwhile wait for it. If the server dies mid-request
+-- the lease runs out and an identical retry may take the key over.
+-- Successful responses are stored and replayed until expires_at; rejected
+-- requests delete their row so the key can be retried.
+-- ============================================
+
+BEGIN;
+
+CREATE TABLE idempotency_keys (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    endpoint TEXT NOT NULL,                   -- e.g. "POST /api/v1/transfer"
+    key TEXT NOT NULL,
+    fingerprint BYTEA NOT NULL,
+    status TEXT NOT NULL DEFAULT 'in_progress' CHECK (status IN ('in_progress', 'completed')),
+    locked_until TIMESTAMPTZ NOT NULL,
+    response_status INT,
+    response_content_type TEXT,
+    response_body BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    completed_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ NOT NULL,
+
+    UNIQUE (user_id, endpoint, key),
+    CHECK (status = 'in_progress' OR response_status IS NOT NULL)
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+
+INSERT INTO schema_migrations (version, name) VALUES (18, '018_idempotency_keys.sql');
+
+COMMIT;
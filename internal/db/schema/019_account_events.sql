@@ -0,0 +1,103 @@
+-- ============================================
+-- SCHEMA: ACCOUNT EVENTS
+-- ============================================
+-- Feeds the real-time streams at /api/v1/events. Triggers record an event
+-- for every posting to a user account (transaction_posted) and every change
+-- of a user account's balance (balance_changed), in the same transaction
+-- as the posting. Each event is then announced with pg_notify on the
+-- account_events channel; Postgres only delivers notifications once the
+-- transaction commits, so every API instance hears about committed money
+-- movements and nothing else.
+--
+-- Events are kept for ACCOUNT_EVENT_RETENTION so a reconnecting client can
+-- resume after the last event ID it saw; the worker purges older ones.
+-- ============================================
+
+BEGIN;
+
+CREATE TABLE account_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    account_id BIGINT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    type TEXT NOT NULL CHECK (type IN ('transaction_posted', 'balance_changed')),
+    data JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_account_events_user_id ON account_events (user_id, id);
+CREATE INDEX idx_account_events_created_at ON account_events (created_at);
+
+-- Fires before update_balance_trigger (triggers run in name order), so a
+-- transfer's transaction_posted event precedes its balance_changed event
+CREATE OR REPLACE FUNCTION record_transaction_posted_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    acct accounts%ROWTYPE;
+    txn transactions%ROWTYPE;
+BEGIN
+    SELECT * INTO acct FROM accounts WHERE id = NEW.account_id;
+    IF acct.user_id IS NULL THEN
+        RETURN NEW; -- System accounts have no one to notify
+    END IF;
+
+    SELECT * INTO txn FROM transactions WHERE id = NEW.transaction_id;
+
+    INSERT INTO account_events (user_id, account_id, type, data)
+    VALUES (acct.user_id, acct.id, 'transaction_posted', jsonb_build_object(
+        'transaction_id', txn.id,
+        'reference', txn.reference,
+        'type', txn.kind,
+        'status', txn.status,
+        'amount', abs(NEW.amount),
+        'direction', CASE WHEN NEW.amount > 0 THEN 'credit' ELSE 'debit' END,
+        'description', txn.description,
+        'currency', NEW.currency
+    ));
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER account_event_on_posting
+AFTER INSERT ON postings
+FOR EACH ROW
+EXECUTE FUNCTION record_transaction_posted_event();
+
+CREATE OR REPLACE FUNCTION record_balance_changed_event()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO account_events (user_id, account_id, type, data)
+    VALUES (NEW.user_id, NEW.id, 'balance_changed', jsonb_build_object(
+        'account_number', NEW.account_number,
+        'balance', NEW.balance,
+        'previous_balance', OLD.balance,
+        'currency', NEW.currency
+    ));
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER account_event_on_balance_change
+AFTER UPDATE OF balance ON accounts
+FOR EACH ROW
+WHEN (NEW.user_id IS NOT NULL AND NEW.balance IS DISTINCT FROM OLD.balance)
+EXECUTE FUNCTION record_balance_changed_event();
+
+-- The payload is the whole event; it stays far below the 8000 byte limit
+CREATE OR REPLACE FUNCTION notify_account_event()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('account_events', row_to_json(NEW)::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notify_account_event
+AFTER INSERT ON account_events
+FOR EACH ROW
+EXECUTE FUNCTION notify_account_event();
+
+INSERT INTO schema_migrations (version, name) VALUES (19, '019_account_events.sql');
+
+COMMIT;
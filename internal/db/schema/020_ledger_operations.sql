@@ -0,0 +1,25 @@
+-- ============================================
+-- SCHEMA: LEDGER OPERATIONS
+-- ============================================
+-- Transaction kinds for the corrections ops post with cmd/debankctl:
+-- adjustment (a manual credit or debit against a system account) and
+-- reversal (the mirror image of an earlier transaction). Both carry the
+-- operator's reason in metadata.
+--
+-- A reversal names the transaction it undoes in metadata.reversal_of and
+-- marks that transaction 'reversed'; the unique index stops a second
+-- reversal of the same transaction even if two are posted at once.
+-- ============================================
+
+BEGIN;
+
+ALTER TABLE transactions DROP CONSTRAINT valid_kind;
+ALTER TABLE transactions ADD CONSTRAINT valid_kind
+    CHECK (kind IN ('p2p', 'deposit', 'withdrawal', 'fee', 'interbank', 'refund', 'adjustment', 'reversal'));
+
+CREATE UNIQUE INDEX idx_transactions_reversal_of ON transactions ((metadata->>'reversal_of'))
+    WHERE kind = 'reversal';
+
+INSERT INTO schema_migrations (version, name) VALUES (20, '020_ledger_operations.sql');
+
+COMMIT;
@@ -0,0 +1,81 @@
+-- ============================================
+-- SCHEMA: JOURNAL ENTRIES
+-- ============================================
+-- Manual corrections between arbitrary accounts, under maker-checker
+-- control. A staff member submits an entry of N legs that sum to zero
+-- with a reason and supporting attachments; it waits in
+-- 'pending_approval' until a different staff member approves it, which
+-- posts one 'journal' transaction with a posting per leg, or rejects it.
+-- The checks below hold even for rows written around the API.
+-- Attachment bytes live in the storage backend; only keys are here.
+-- ============================================
+
+BEGIN;
+
+ALTER TABLE transactions DROP CONSTRAINT valid_kind;
+ALTER TABLE transactions ADD CONSTRAINT valid_kind
+    CHECK (kind IN ('p2p', 'deposit', 'withdrawal', 'fee', 'interbank', 'refund', 'adjustment', 'reversal', 'journal'));
+
+CREATE TABLE journal_entries (
+    id BIGSERIAL PRIMARY KEY,
+    reference TEXT UNIQUE NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending_approval',
+    currency CHAR(3) NOT NULL,
+    reason TEXT NOT NULL,
+
+    maker_user_id INT NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    checker_user_id INT REFERENCES users(id) ON DELETE RESTRICT,
+    review_note TEXT,
+    reviewed_at TIMESTAMPTZ,
+
+    transaction_id BIGINT UNIQUE REFERENCES transactions(id) ON DELETE RESTRICT,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+    CONSTRAINT valid_journal_status CHECK (status IN ('pending_approval', 'posted', 'rejected')),
+    CONSTRAINT journal_reason_required CHECK (btrim(reason) <> ''),
+    -- Four eyes: whoever decides an entry can't be the one who submitted it
+    CONSTRAINT journal_checker_not_maker CHECK (checker_user_id <> maker_user_id),
+    CONSTRAINT journal_reviewed CHECK ((status = 'pending_approval') = (checker_user_id IS NULL)),
+    CONSTRAINT journal_posted_transaction CHECK ((status = 'posted') = (transaction_id IS NOT NULL))
+);
+
+CREATE INDEX idx_journal_entries_queue ON journal_entries(status, created_at);
+
+CREATE TRIGGER update_journal_entries_updated_at
+BEFORE UPDATE ON journal_entries
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+-- Signed like postings: positive credits, negative debits
+CREATE TABLE journal_entry_legs (
+    id BIGSERIAL PRIMARY KEY,
+    entry_id BIGINT NOT NULL REFERENCES journal_entries(id) ON DELETE CASCADE,
+    account_id BIGINT NOT NULL REFERENCES accounts(id) ON DELETE RESTRICT,
+    amount BIGINT NOT NULL,
+    memo TEXT,
+
+    CONSTRAINT journal_leg_non_zero CHECK (amount <> 0),
+    CONSTRAINT journal_leg_one_per_account UNIQUE (entry_id, account_id)
+);
+
+CREATE TABLE journal_entry_attachments (
+    id BIGSERIAL PRIMARY KEY,
+    entry_id BIGINT NOT NULL REFERENCES journal_entries(id) ON DELETE CASCADE,
+    uploaded_by INT NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+
+    storage_key TEXT UNIQUE NOT NULL,
+    file_name TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    sha256 TEXT NOT NULL,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_journal_entry_attachments_entry ON journal_entry_attachments(entry_id);
+
+INSERT INTO schema_migrations (version, name) VALUES (21, '021_journal_entries.sql');
+
+COMMIT;
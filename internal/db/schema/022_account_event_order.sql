@@ -0,0 +1,75 @@
+-- ============================================
+-- SCHEMA: ACCOUNT EVENT ORDER
+-- ============================================
+-- A client resumes its event stream after the last ID it saw, which only
+-- works if a user's events commit in ID order. IDs come from a sequence
+-- when the row is inserted, not when it commits, so two transactions
+-- touching the same user could commit 60 before 59, and a client that
+-- saw 60 would never be sent 59.
+--
+-- Both event triggers now take a per-user transaction lock before the ID
+-- is drawn. The lock is held until commit, so a later transaction for the
+-- same user can only draw its ID once the earlier one is committed (or
+-- rolled back). Money movements already lock their accounts before
+-- posting, so this adds no new waits in practice.
+-- ============================================
+
+BEGIN;
+
+-- Serialises event IDs per user until the calling transaction ends
+CREATE OR REPLACE FUNCTION lock_account_events(p_user_id INT)
+RETURNS VOID AS $$
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtext('account_events'), p_user_id);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION record_transaction_posted_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    acct accounts%ROWTYPE;
+    txn transactions%ROWTYPE;
+BEGIN
+    SELECT * INTO acct FROM accounts WHERE id = NEW.account_id;
+    IF acct.user_id IS NULL THEN
+        RETURN NEW; -- System accounts have no one to notify
+    END IF;
+
+    SELECT * INTO txn FROM transactions WHERE id = NEW.transaction_id;
+
+    PERFORM lock_account_events(acct.user_id);
+    INSERT INTO account_events (user_id, account_id, type, data)
+    VALUES (acct.user_id, acct.id, 'transaction_posted', jsonb_build_object(
+        'transaction_id', txn.id,
+        'reference', txn.reference,
+        'type', txn.kind,
+        'status', txn.status,
+        'amount', abs(NEW.amount),
+        'direction', CASE WHEN NEW.amount > 0 THEN 'credit' ELSE 'debit' END,
+        'description', txn.description,
+        'currency', NEW.currency
+    ));
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION record_balance_changed_event()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM lock_account_events(NEW.user_id);
+    INSERT INTO account_events (user_id, account_id, type, data)
+    VALUES (NEW.user_id, NEW.id, 'balance_changed', jsonb_build_object(
+        'account_number', NEW.account_number,
+        'balance', NEW.balance,
+        'previous_balance', OLD.balance,
+        'currency', NEW.currency
+    ));
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+INSERT INTO schema_migrations (version, name) VALUES (22, '022_account_event_order.sql');
+
+COMMIT;
@@ -60,10 +60,16 @@ run_sql "$DB_DIR/schema/001_schema.sql" "Schema (tables and indexes)"
 # 2. Create functions and triggers
 run_sql "$DB_DIR/schema/002_functions.sql" "Functions and triggers"
 
-# 3. Seed system accounts (always run)
+# 3. Feature schemas (003 onwards, in file order)
+for file in "$DB_DIR"/schema/[0-9][0-9][0-9]_*.sql; do
+    case "$(basename "$file")" in 001_*|002_*) continue ;; esac
+    run_sql "$file" "Schema $(basename "$file")"
+done
+
+# 4. Seed system accounts (always run)
 run_sql "$DB_DIR/seeds/001_system_accounts.sql" "System accounts"
 
-# 4. Seed test data (skip in production)
+# 5. Seed test data (skip in production)
 if [ "$SKIP_TEST_DATA" = false ]; then
     run_sql "$DB_DIR/seeds/002_test_data.sql" "Test data"
 fi
@@ -4,5 +4,9 @@ BASE_DIR="$(dirname "$0")/.."
 
 psql -U postgres -d bank_ledger -f "$BASE_DIR/schema/001_schema.sql"
 psql -U postgres -d bank_ledger -f "$BASE_DIR/schema/002_functions.sql"
+for file in "$BASE_DIR"/schema/[0-9][0-9][0-9]_*.sql; do
+    case "$(basename "$file")" in 001_*|002_*) continue ;; esac
+    psql -U postgres -d bank_ledger -f "$file"
+done
 psql -U postgres -d bank_ledger -f "$BASE_DIR/seeds/001_system_accounts.sql"
 psql -U postgres -d bank_ledger -f "$BASE_DIR/seeds/002_test_data.sql"
\ No newline at end of file
@@ -0,0 +1,285 @@
+// Package events delivers account events to the clients streaming them.
+// Each API instance holds one Postgres LISTEN connection and fans the
+// events it hears out to the streams open on that instance; a client that
+// reconnects replays what it missed from the account_events table.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/models"
+)
+
+// ==============================================
+// STORE INTERFACE (for testing)
+// ==============================================
+
+// Store reads and listens for account events (implemented by AccountEventRepository)
+type Store interface {
+	ListAfter(ctx context.Context, userID int, afterID int64, limit int) ([]models.AccountEvent, error)
+	Listen(ctx context.Context, ready func(), handle func(models.AccountEvent)) error
+}
+
+// ErrUnavailable is returned while the broker is not listening, e.g. between reconnects
+var ErrUnavailable = errors.New("event stream unavailable")
+
+const (
+	replayPageSize = 100              // Events read per backlog query
+	liveBuffer     = 256              // Live events queued per stream before it is dropped as too slow
+	retryMin       = time.Second      // First wait before listening again
+	retryMax       = 30 * time.Second // Longest wait between listen attempts
+)
+
+// ==============================================
+// BROKER
+// ==============================================
+
+// Broker fans account events out to the subscriptions open on this instance
+type Broker struct {
+	store Store
+
+	mu        sync.Mutex
+	listening bool
+	closed    bool
+	subs      map[int]map[*Subscription]struct{} // By user ID
+}
+
+func NewBroker(store Store) *Broker {
+	return &Broker{store: store, subs: make(map[int]map[*Subscription]struct{})}
+}
+
+// Run listens for events until ctx ends, reconnecting with backoff
+// Every time the listener drops, open subscriptions are ended so their
+// clients reconnect and replay whatever was announced in between.
+func (b *Broker) Run(ctx context.Context) {
+	wait := retryMin
+	for {
+		listened := false
+		err := b.store.Listen(ctx, func() {
+			listened = true
+			b.ready()
+		}, b.publish)
+		b.reset()
+		if ctx.Err() != nil {
+			return
+		}
+		if listened {
+			wait = retryMin // The last connection was healthy; start the backoff over
+		}
+
+		log.Printf("[EVENTS] Listener stopped, retrying in %s: %v", wait, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		wait = min(wait*2, retryMax)
+	}
+}
+
+// Close ends every subscription and refuses new ones; for server shutdown
+func (b *Broker) Close() {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+	b.reset()
+}
+
+func (b *Broker) ready() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.listening = !b.closed
+}
+
+// reset ends all subscriptions; they may have missed events
+func (b *Broker) reset() {
+	b.mu.Lock()
+	subs := b.subs
+	b.subs = make(map[int]map[*Subscription]struct{})
+	b.listening = false
+	b.mu.Unlock()
+
+	for _, byUser := range subs {
+		for sub := range byUser {
+			sub.stop()
+		}
+	}
+}
+
+// publish hands an event to the user's subscriptions, dropping any that have fallen behind
+func (b *Broker) publish(e models.AccountEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subs[e.UserID] {
+		select {
+		case sub.live <- e:
+		default:
+			log.Printf("[EVENTS] Dropping slow stream - UserID: %d", e.UserID)
+			b.removeLocked(sub)
+			sub.stop()
+		}
+	}
+}
+
+func (b *Broker) remove(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.removeLocked(sub)
+}
+
+func (b *Broker) removeLocked(sub *Subscription) {
+	byUser := b.subs[sub.userID]
+	delete(byUser, sub)
+	if len(byUser) == 0 {
+		delete(b.subs, sub.userID)
+	}
+}
+
+// Subscribe opens a stream of the user's events
+// With lastEventID > 0 the events recorded after it are replayed first.
+// The subscription ends when ctx does, when Close is called, or when the
+// broker drops it; Events is then closed and the client should reconnect
+// with the last ID it received.
+func (b *Broker) Subscribe(ctx context.Context, userID int, lastEventID int64) (*Subscription, error) {
+	sub := &Subscription{
+		userID: userID,
+		live:   make(chan models.AccountEvent, liveBuffer),
+		out:    make(chan dto.AccountEvent),
+		done:   make(chan struct{}),
+		broker: b,
+	}
+
+	// Registered before the backlog is read, so nothing falls between the two
+	b.mu.Lock()
+	if !b.listening {
+		b.mu.Unlock()
+		return nil, ErrUnavailable
+	}
+	if b.subs[userID] == nil {
+		b.subs[userID] = make(map[*Subscription]struct{})
+	}
+	b.subs[userID][sub] = struct{}{}
+	b.mu.Unlock()
+
+	go sub.run(ctx, lastEventID)
+	return sub, nil
+}
+
+// ==============================================
+// SUBSCRIPTION
+// ==============================================
+
+// Subscription is one client's stream of events
+type Subscription struct {
+	userID int
+	live   chan models.AccountEvent
+	out    chan dto.AccountEvent
+	done   chan struct{}
+	once   sync.Once
+	broker *Broker
+}
+
+// Events yields the replayed events, then live ones, and is closed when the subscription ends
+func (s *Subscription) Events() <-chan dto.AccountEvent {
+	return s.out
+}
+
+// Close ends the subscription
+func (s *Subscription) Close() {
+	s.broker.remove(s)
+	s.stop()
+}
+
+func (s *Subscription) stop() {
+	s.once.Do(func() { close(s.done) })
+}
+
+func (s *Subscription) run(ctx context.Context, lastEventID int64) {
+	defer close(s.out)
+	defer s.Close()
+
+	// IDs replayed from the table; the same events may also arrive live
+	replayed := make(map[int64]bool)
+	if lastEventID > 0 {
+		for {
+			page, err := s.broker.store.ListAfter(ctx, s.userID, lastEventID, replayPageSize)
+			if err != nil {
+				log.Printf("[EVENTS] Replay failed - UserID: %d: %v", s.userID, err)
+				return
+			}
+			for _, e := range page {
+				if !s.send(ctx, e) {
+					return
+				}
+				replayed[e.ID] = true
+				lastEventID = e.ID
+			}
+			if len(page) < replayPageSize {
+				break
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.done:
+			return
+		case e := <-s.live:
+			if replayed[e.ID] {
+				continue
+			}
+			if !s.send(ctx, e) {
+				return
+			}
+		}
+	}
+}
+
+// send delivers e, reporting false once the subscription is over
+func (s *Subscription) send(ctx context.Context, e models.AccountEvent) bool {
+	msg, err := toDTO(e)
+	if err != nil {
+		log.Printf("[EVENTS] Skipping event %d: %v", e.ID, err)
+		return true
+	}
+
+	select {
+	case s.out <- msg:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-s.done:
+		return false
+	}
+}
+
+// toDTO decodes an event's data for the client
+func toDTO(e models.AccountEvent) (dto.AccountEvent, error) {
+	msg := dto.AccountEvent{ID: e.ID, Type: e.Type, CreatedAt: e.CreatedAt.Format(time.RFC3339)}
+
+	switch e.Type {
+	case models.AccountEventTransactionPosted:
+		msg.Transaction = &dto.TransactionPostedData{}
+		if err := json.Unmarshal(e.Data, msg.Transaction); err != nil {
+			return msg, fmt.Errorf("failed to decode %s data: %w", e.Type, err)
+		}
+	case models.AccountEventBalanceChanged:
+		msg.Balance = &dto.BalanceChangedData{}
+		if err := json.Unmarshal(e.Data, msg.Balance); err != nil {
+			return msg, fmt.Errorf("failed to decode %s data: %w", e.Type, err)
+		}
+	default:
+		return msg, fmt.Errorf("unknown event type %q", e.Type)
+	}
+	return msg, nil
+}
@@ -0,0 +1,182 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore keeps events in memory; Listen reports ready, then delivers
+// whatever is sent on notify until ctx ends or drop is closed
+type fakeStore struct {
+	events []models.AccountEvent
+	notify chan models.AccountEvent
+	drop   chan struct{}
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{notify: make(chan models.AccountEvent), drop: make(chan struct{})}
+}
+
+func (s *fakeStore) ListAfter(ctx context.Context, userID int, afterID int64, limit int) ([]models.AccountEvent, error) {
+	var page []models.AccountEvent
+	for _, e := range s.events {
+		if e.UserID == userID && e.ID > afterID && len(page) < limit {
+			page = append(page, e)
+		}
+	}
+	return page, nil
+}
+
+func (s *fakeStore) Listen(ctx context.Context, ready func(), handle func(models.AccountEvent)) error {
+	ready()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.drop:
+			return errors.New("connection lost")
+		case e := <-s.notify:
+			handle(e)
+		}
+	}
+}
+
+func event(id int64, userID int) models.AccountEvent {
+	data, _ := json.Marshal(dto.BalanceChangedData{AccountNumber: "0123456789", Balance: id * 100, Currency: "NGN"})
+	return models.AccountEvent{ID: id, UserID: userID, Type: models.AccountEventBalanceChanged, Data: data, CreatedAt: time.Now()}
+}
+
+// running starts a broker on store and waits until it is listening
+func running(t *testing.T, store Store) *Broker {
+	t.Helper()
+	b := NewBroker(store)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go b.Run(ctx)
+
+	require.Eventually(t, func() bool {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		return b.listening
+	}, time.Second, time.Millisecond)
+	return b
+}
+
+func receive(t *testing.T, sub *Subscription) (dto.AccountEvent, bool) {
+	t.Helper()
+	select {
+	case e, ok := <-sub.Events():
+		return e, ok
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+		return dto.AccountEvent{}, false
+	}
+}
+
+func TestBroker_UnavailableBeforeListening(t *testing.T) {
+	b := NewBroker(newFakeStore())
+
+	_, err := b.Subscribe(context.Background(), 7, 0)
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
+
+func TestBroker_DeliversLiveEventsToTheirUser(t *testing.T) {
+	store := newFakeStore()
+	b := running(t, store)
+
+	sub, err := b.Subscribe(context.Background(), 7, 0)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	store.notify <- event(1, 8) // Someone else's
+	store.notify <- event(2, 7)
+
+	e, ok := receive(t, sub)
+	require.True(t, ok)
+	assert.Equal(t, int64(2), e.ID)
+	require.NotNil(t, e.Balance)
+	assert.Equal(t, int64(200), e.Balance.Balance)
+}
+
+func TestBroker_ReplaysThenSkipsReplayedLiveEvents(t *testing.T) {
+	store := newFakeStore()
+	for id := int64(1); id <= replayPageSize+2; id++ {
+		store.events = append(store.events, event(id, 7))
+	}
+	b := running(t, store)
+
+	sub, err := b.Subscribe(context.Background(), 7, 1)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	// The last backlog event is also announced live once replay has read it
+	b.publish(event(replayPageSize+2, 7))
+	b.publish(event(replayPageSize+3, 7))
+
+	var ids []int64
+	for len(ids) < replayPageSize+2 {
+		e, ok := receive(t, sub)
+		require.True(t, ok)
+		ids = append(ids, e.ID)
+	}
+	assert.Equal(t, int64(2), ids[0])
+	assert.Equal(t, int64(replayPageSize+3), ids[len(ids)-1])
+	for i := 1; i < len(ids); i++ {
+		assert.Equal(t, ids[i-1]+1, ids[i])
+	}
+}
+
+func TestBroker_ListenerDropEndsSubscriptions(t *testing.T) {
+	store := newFakeStore()
+	b := running(t, store)
+
+	sub, err := b.Subscribe(context.Background(), 7, 0)
+	require.NoError(t, err)
+
+	close(store.drop)
+	_, ok := receive(t, sub)
+	assert.False(t, ok)
+}
+
+func TestBroker_DropsSlowSubscriber(t *testing.T) {
+	b := running(t, newFakeStore())
+
+	sub, err := b.Subscribe(context.Background(), 7, 0)
+	require.NoError(t, err)
+
+	// Nobody reads Events, so the queue fills and the stream is cut
+	for id := int64(1); id <= liveBuffer+2; id++ {
+		b.publish(event(id, 7))
+	}
+
+	for {
+		if _, ok := receive(t, sub); !ok {
+			break
+		}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	assert.Empty(t, b.subs)
+}
+
+func TestBroker_CloseRefusesNewSubscriptions(t *testing.T) {
+	b := running(t, newFakeStore())
+
+	sub, err := b.Subscribe(context.Background(), 7, 0)
+	require.NoError(t, err)
+
+	b.Close()
+	_, ok := receive(t, sub)
+	assert.False(t, ok)
+
+	_, err = b.Subscribe(context.Background(), 7, 0)
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
@@ -0,0 +1,138 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ==============================================
+// STANDARD CHECKS
+// ==============================================
+
+// Pinger is a dependency that can be asked whether it is reachable
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Ping checks that p answers
+func Ping(p Pinger) CheckFunc {
+	return func(ctx context.Context) (string, error) {
+		return "", p.Ping(ctx)
+	}
+}
+
+// PoolSaturation fails once at least limit (0-1) of the pool's connections are in use
+func PoolSaturation(pool *pgxpool.Pool, limit float64) CheckFunc {
+	return func(ctx context.Context) (string, error) {
+		s := pool.Stat()
+		detail := fmt.Sprintf("%d/%d connections in use, %d idle, %d acquires had to wait",
+			s.AcquiredConns(), s.MaxConns(), s.IdleConns(), s.EmptyAcquireCount())
+		if s.MaxConns() > 0 && float64(s.AcquiredConns())/float64(s.MaxConns()) >= limit {
+			return detail, errors.New("connection pool saturated")
+		}
+		return detail, nil
+	}
+}
+
+// SchemaVersion fails while the database is behind the newest migration this build ships
+func SchemaVersion(applied func(ctx context.Context) (int, error), want int) CheckFunc {
+	return func(ctx context.Context) (string, error) {
+		have, err := applied(ctx)
+		if err != nil {
+			return "", err
+		}
+		detail := fmt.Sprintf("database at version %d, build expects %d", have, want)
+		if have < want {
+			return detail, fmt.Errorf("%d pending migration(s)", want-have)
+		}
+		return detail, nil
+	}
+}
+
+// AccountLookup finds a system account by external ID
+type AccountLookup func(ctx context.Context, externalID string) (*models.Account, error)
+
+// SystemAccounts fails unless every listed system account exists and is active
+func SystemAccounts(lookup AccountLookup, externalIDs ...string) CheckFunc {
+	return func(ctx context.Context) (string, error) {
+		var problems []string
+		for _, id := range externalIDs {
+			account, err := lookup(ctx, id)
+			switch {
+			case err != nil:
+				problems = append(problems, fmt.Sprintf("%s: %v", id, err))
+			case !account.IsActive:
+				problems = append(problems, id+": inactive")
+			}
+		}
+		if len(problems) > 0 {
+			return "", errors.New(strings.Join(problems, "; "))
+		}
+		return strings.Join(externalIDs, ", ") + " present", nil
+	}
+}
+
+// HeartbeatLister returns the latest heartbeat of every worker job
+type HeartbeatLister func(ctx context.Context) ([]models.WorkerHeartbeat, error)
+
+// WorkerHeartbeats fails when a job hasn't succeeded within two of its intervals plus grace
+func WorkerHeartbeats(list HeartbeatLister, grace time.Duration) CheckFunc {
+	return func(ctx context.Context) (string, error) {
+		heartbeats, err := list(ctx)
+		if err != nil {
+			return "", err
+		}
+		return evaluateHeartbeats(heartbeats, time.Now(), grace)
+	}
+}
+
+func evaluateHeartbeats(heartbeats []models.WorkerHeartbeat, now time.Time, grace time.Duration) (string, error) {
+	if len(heartbeats) == 0 {
+		return "", errors.New("no worker job has reported yet")
+	}
+
+	var seen, problems []string
+	for _, h := range heartbeats {
+		if !h.LastSuccessAt.Valid {
+			problems = append(problems, fmt.Sprintf("%s: never succeeded", h.Job))
+		} else {
+			age := now.Sub(h.LastSuccessAt.Time)
+			seen = append(seen, fmt.Sprintf("%s %s ago", h.Job, age.Round(time.Second)))
+			if age > 2*h.Interval()+grace {
+				problems = append(problems, fmt.Sprintf("%s: last succeeded %s ago, runs every %s",
+					h.Job, age.Round(time.Second), h.Interval()))
+			}
+		}
+		if h.LastError.Valid {
+			problems = append(problems, fmt.Sprintf("%s: last run failed: %s", h.Job, h.LastError.String))
+		}
+	}
+
+	detail := "last success: " + strings.Join(seen, ", ")
+	if len(seen) == 0 {
+		detail = ""
+	}
+	if len(problems) > 0 {
+		return detail, errors.New(strings.Join(problems, "; "))
+	}
+	return detail, nil
+}
+
+// Goroutines fails once the process runs more than limit goroutines, a sign of a leak
+func Goroutines(limit int) CheckFunc {
+	return func(ctx context.Context) (string, error) {
+		n := runtime.NumGoroutine()
+		detail := fmt.Sprintf("%d goroutines", n)
+		if n > limit {
+			return detail, fmt.Errorf("more than %d goroutines", limit)
+		}
+		return detail, nil
+	}
+}
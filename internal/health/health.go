@@ -0,0 +1,175 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ==============================================
+// HEALTH CHECKS
+// ==============================================
+// Components register checks on a Registry; /livez and /readyz each run
+// their own registry. Checks run concurrently and each result is cached
+// for the registry's TTL, so frequent probes from several load balancers
+// cost one database round trip per check per TTL. A probe that arrives
+// while a check is running waits for that run instead of starting another.
+
+// Check outcomes
+const (
+	StatusOK   = "ok"
+	StatusWarn = "warn" // A non-critical check failed
+	StatusFail = "fail"
+)
+
+// Report outcomes
+const (
+	ReportOK       = "ok"
+	ReportDegraded = "degraded" // Only non-critical checks failed; still serving
+	ReportFail     = "fail"
+)
+
+// DefaultTimeout bounds a check that doesn't set its own
+const DefaultTimeout = 2 * time.Second
+
+// CheckFunc probes one dependency and describes what it saw
+type CheckFunc func(ctx context.Context) (detail string, err error)
+
+// Check is a named probe
+type Check struct {
+	Name     string
+	Run      CheckFunc
+	Critical bool          // A failure fails the whole report; otherwise it only degrades it
+	Timeout  time.Duration // Defaults to DefaultTimeout
+}
+
+// Result is one check's latest outcome
+type Result struct {
+	Name       string    `json:"name"`
+	Status     string    `json:"status"`
+	Critical   bool      `json:"critical"`
+	Detail     string    `json:"detail,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+	CheckedAt  time.Time `json:"checked_at"`
+	Cached     bool      `json:"cached"`
+}
+
+// Report is the outcome of every check in a registry
+type Report struct {
+	Status string   `json:"status"`
+	Checks []Result `json:"checks"`
+}
+
+// Healthy reports whether every critical check passed
+func (r Report) Healthy() bool {
+	return r.Status != ReportFail
+}
+
+// Registry runs a set of checks and caches their results
+type Registry struct {
+	ttl time.Duration
+
+	mu     sync.RWMutex
+	checks []*entry
+}
+
+type entry struct {
+	check Check
+
+	mu   sync.Mutex // Held while the check runs, so concurrent probes share one run
+	last *Result
+}
+
+// NewRegistry creates an empty registry; results are reused for ttl (0 disables caching)
+func NewRegistry(ttl time.Duration) *Registry {
+	return &Registry{ttl: ttl}
+}
+
+// Register adds a check; names should be unique within a registry
+func (r *Registry) Register(c Check) {
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, &entry{check: c})
+}
+
+// Run evaluates every check, reusing results younger than the TTL
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mu.RLock()
+	entries := make([]*entry, len(r.checks))
+	copy(entries, r.checks)
+	r.mu.RUnlock()
+
+	results := make([]Result, len(entries))
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = e.result(ctx, r.ttl)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: ReportOK, Checks: results}
+	for _, res := range results {
+		switch {
+		case res.Status == StatusFail:
+			report.Status = ReportFail
+		case res.Status == StatusWarn && report.Status == ReportOK:
+			report.Status = ReportDegraded
+		}
+	}
+	return report
+}
+
+func (e *entry) result(ctx context.Context, ttl time.Duration) Result {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.last != nil && time.Since(e.last.CheckedAt) < ttl {
+		cached := *e.last
+		cached.Cached = true
+		return cached
+	}
+
+	res := e.run(ctx)
+	e.last = &res
+	return res
+}
+
+// run executes the check once; the result is shared, so one caller hanging up doesn't cancel it
+func (e *entry) run(ctx context.Context) (res Result) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), e.check.Timeout)
+	defer cancel()
+
+	startTime := time.Now()
+	res = Result{Name: e.check.Name, Critical: e.check.Critical, CheckedAt: startTime}
+
+	defer func() {
+		if p := recover(); p != nil {
+			res.Status, res.Error = e.failStatus(), fmt.Sprintf("check panicked: %v", p)
+		}
+		res.DurationMS = time.Since(startTime).Milliseconds()
+	}()
+
+	detail, err := e.check.Run(ctx)
+	res.Detail = detail
+	if err != nil {
+		res.Status, res.Error = e.failStatus(), err.Error()
+		return res
+	}
+	res.Status = StatusOK
+	return res
+}
+
+func (e *entry) failStatus() string {
+	if e.check.Critical {
+		return StatusFail
+	}
+	return StatusWarn
+}
@@ -0,0 +1,230 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func constCheck(name string, critical bool, err error) Check {
+	return Check{
+		Name:     name,
+		Critical: critical,
+		Run: func(ctx context.Context) (string, error) {
+			return "detail of " + name, err
+		},
+	}
+}
+
+func TestRegistry_Statuses(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []Check
+		want   string
+	}{
+		{"empty", nil, ReportOK},
+		{"all ok", []Check{constCheck("db", true, nil), constCheck("email", false, nil)}, ReportOK},
+		{"non-critical failure", []Check{constCheck("db", true, nil), constCheck("email", false, errors.New("down"))}, ReportDegraded},
+		{"critical failure", []Check{constCheck("db", true, errors.New("down")), constCheck("email", false, errors.New("down"))}, ReportFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry(0)
+			for _, c := range tt.checks {
+				r.Register(c)
+			}
+			report := r.Run(context.Background())
+			assert.Equal(t, tt.want, report.Status)
+			assert.Equal(t, tt.want != ReportFail, report.Healthy())
+			require.Len(t, report.Checks, len(tt.checks))
+		})
+	}
+}
+
+func TestRegistry_ResultDetail(t *testing.T) {
+	r := NewRegistry(0)
+	r.Register(constCheck("db", true, nil))
+	r.Register(constCheck("email", false, errors.New("connection refused")))
+
+	report := r.Run(context.Background())
+
+	// Results keep registration order
+	db, email := report.Checks[0], report.Checks[1]
+	assert.Equal(t, "db", db.Name)
+	assert.Equal(t, StatusOK, db.Status)
+	assert.True(t, db.Critical)
+	assert.Equal(t, "detail of db", db.Detail)
+	assert.Empty(t, db.Error)
+
+	assert.Equal(t, "email", email.Name)
+	assert.Equal(t, StatusWarn, email.Status)
+	assert.Equal(t, "connection refused", email.Error)
+}
+
+func TestRegistry_CachesResults(t *testing.T) {
+	var calls atomic.Int32
+	r := NewRegistry(time.Minute)
+	r.Register(Check{Name: "db", Critical: true, Run: func(ctx context.Context) (string, error) {
+		calls.Add(1)
+		return "", nil
+	}})
+
+	first := r.Run(context.Background())
+	second := r.Run(context.Background())
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.False(t, first.Checks[0].Cached)
+	assert.True(t, second.Checks[0].Cached)
+	assert.Equal(t, first.Checks[0].CheckedAt, second.Checks[0].CheckedAt)
+}
+
+func TestRegistry_ZeroTTLRunsEveryTime(t *testing.T) {
+	var calls atomic.Int32
+	r := NewRegistry(0)
+	r.Register(Check{Name: "db", Run: func(ctx context.Context) (string, error) {
+		calls.Add(1)
+		return "", nil
+	}})
+
+	r.Run(context.Background())
+	r.Run(context.Background())
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRegistry_ConcurrentProbesShareOneRun(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	r := NewRegistry(time.Minute)
+	r.Register(Check{Name: "slow", Run: func(ctx context.Context) (string, error) {
+		calls.Add(1)
+		<-release
+		return "", nil
+	}})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.Run(context.Background())
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRegistry_Timeout(t *testing.T) {
+	r := NewRegistry(0)
+	r.Register(Check{Name: "hung", Critical: true, Timeout: 10 * time.Millisecond, Run: func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}})
+
+	report := r.Run(context.Background())
+	assert.Equal(t, ReportFail, report.Status)
+	assert.Contains(t, report.Checks[0].Error, "deadline exceeded")
+}
+
+func TestRegistry_CallerCancelDoesNotFailCheck(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	r := NewRegistry(0)
+	r.Register(Check{Name: "db", Critical: true, Run: func(ctx context.Context) (string, error) {
+		return "", ctx.Err()
+	}})
+
+	assert.Equal(t, ReportOK, r.Run(ctx).Status)
+}
+
+func TestRegistry_RecoversPanics(t *testing.T) {
+	r := NewRegistry(0)
+	r.Register(Check{Name: "buggy", Critical: true, Run: func(ctx context.Context) (string, error) {
+		panic("nil map")
+	}})
+
+	report := r.Run(context.Background())
+	assert.Equal(t, ReportFail, report.Status)
+	assert.Equal(t, StatusFail, report.Checks[0].Status)
+	assert.Contains(t, report.Checks[0].Error, "nil map")
+}
+
+func TestSchemaVersion(t *testing.T) {
+	at := func(v int) func(context.Context) (int, error) {
+		return func(context.Context) (int, error) { return v, nil }
+	}
+
+	_, err := SchemaVersion(at(17), 17)(context.Background())
+	assert.NoError(t, err)
+
+	// A newer database is fine while an older build is still rolling out
+	_, err = SchemaVersion(at(18), 17)(context.Background())
+	assert.NoError(t, err)
+
+	detail, err := SchemaVersion(at(15), 17)(context.Background())
+	assert.EqualError(t, err, "2 pending migration(s)")
+	assert.Equal(t, "database at version 15, build expects 17", detail)
+}
+
+func TestSystemAccounts(t *testing.T) {
+	accounts := map[string]*models.Account{
+		"sys_reserve": {IsActive: true},
+		"sys_fee":     {IsActive: false},
+	}
+	lookup := func(ctx context.Context, id string) (*models.Account, error) {
+		if a, ok := accounts[id]; ok {
+			return a, nil
+		}
+		return nil, errors.New("account not found")
+	}
+
+	_, err := SystemAccounts(lookup, "sys_reserve")(context.Background())
+	assert.NoError(t, err)
+
+	_, err = SystemAccounts(lookup, "sys_reserve", "sys_fee", "sys_missing")(context.Background())
+	assert.EqualError(t, err, "sys_fee: inactive; sys_missing: account not found")
+}
+
+func TestEvaluateHeartbeats(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	succeeded := func(ago time.Duration) pgtype.Timestamptz {
+		return pgtype.Timestamptz{Time: now.Add(-ago), Valid: true}
+	}
+	hourly := int64(time.Hour / time.Second)
+
+	_, err := evaluateHeartbeats(nil, now, time.Minute)
+	assert.EqualError(t, err, "no worker job has reported yet")
+
+	detail, err := evaluateHeartbeats([]models.WorkerHeartbeat{
+		{Job: "aml_scan", IntervalSeconds: hourly, LastSuccessAt: succeeded(90 * time.Minute)},
+	}, now, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, "last success: aml_scan 1h30m0s ago", detail)
+
+	_, err = evaluateHeartbeats([]models.WorkerHeartbeat{
+		{Job: "aml_scan", IntervalSeconds: hourly, LastSuccessAt: succeeded(3 * time.Hour)},
+		{Job: "hash_chain_checkpoint", IntervalSeconds: hourly, LastError: pgtype.Text{String: "no keys", Valid: true}},
+	}, now, time.Minute)
+	assert.EqualError(t, err, "aml_scan: last succeeded 3h0m0s ago, runs every 1h0m0s; "+
+		"hash_chain_checkpoint: never succeeded; hash_chain_checkpoint: last run failed: no keys")
+}
+
+func TestGoroutines(t *testing.T) {
+	_, err := Goroutines(1 << 20)(context.Background())
+	assert.NoError(t, err)
+
+	_, err = Goroutines(0)(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,216 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// ==============================================
+// PROMETHEUS METRICS
+// ==============================================
+// Everything is registered on Registry, which the server exposes at
+// /metrics. Label values are always drawn from small fixed sets (route
+// templates, transaction kinds, reasons), never from user input, so the
+// number of series stays bounded.
+
+const namespace = "debank"
+
+// Registry holds every debank metric plus the Go runtime and process collectors
+var Registry = prometheus.NewRegistry()
+
+// Amounts are in kobo; buckets run from ₦100 to ₦1,000,000
+var amountBuckets = []float64{
+	10000, 50000, 100000, 500000, 1000000, 5000000, 10000000, 50000000, 100000000,
+}
+
+var (
+	// HTTP (rate, errors, duration)
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route template, method and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route template and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	HTTPInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	})
+
+	// Ledger
+	TransactionAmount = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "transaction_amount_kobo",
+		Help:      "Requested transaction amounts in kobo by kind and status.",
+		Buckets:   amountBuckets,
+	}, []string{"kind", "status"})
+
+	TransactionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "transaction_duration_seconds",
+		Help:      "Time to post or reject a transaction by kind and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"kind", "status"})
+
+	IdempotentReplays = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "idempotent_replays_total",
+		Help:      "Requests answered from an earlier transaction with the same idempotency key.",
+	}, []string{"kind"})
+
+	InsufficientBalance = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "insufficient_balance_rejections_total",
+		Help:      "Debits rejected for insufficient balance.",
+	}, []string{"kind"})
+
+	// Auth
+	LoginFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "login_failures_total",
+		Help:      "Failed sign-ins by reason.",
+	}, []string{"reason"})
+
+	AccountLockouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "account_lockouts_total",
+		Help:      "Accounts locked, by what locked them.",
+	}, []string{"source"})
+
+	OTPSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "otp_sent_total",
+		Help:      "One-time codes emailed, by purpose.",
+	}, []string{"purpose"})
+)
+
+// Transaction statuses used as label values
+const (
+	StatusPosted = "posted"
+	StatusFailed = "failed"
+)
+
+// Lockout sources used as label values
+const (
+	LockoutFailedLogins = "failed_logins"
+	LockoutStaff        = "staff"
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPDuration,
+		HTTPInFlight,
+		TransactionAmount,
+		TransactionDuration,
+		IdempotentReplays,
+		InsufficientBalance,
+		LoginFailures,
+		AccountLockouts,
+		OTPSent,
+	)
+}
+
+// ObserveTransaction records one deposit, withdrawal or transfer attempt
+func ObserveTransaction(kind, status string, amount int64, started time.Time) {
+	TransactionAmount.WithLabelValues(kind, status).Observe(float64(amount))
+	TransactionDuration.WithLabelValues(kind, status).Observe(time.Since(started).Seconds())
+}
+
+// ==============================================
+// SCRAPE-TIME COLLECTORS
+// ==============================================
+
+// RegisterPool exports pgxpool statistics, read on every scrape
+func RegisterPool(pool *pgxpool.Pool) {
+	Registry.MustRegister(&poolCollector{pool: pool})
+}
+
+var (
+	poolAcquiredDesc = prometheus.NewDesc(namespace+"_db_pool_acquired_connections", "Connections currently in use.", nil, nil)
+	poolIdleDesc     = prometheus.NewDesc(namespace+"_db_pool_idle_connections", "Idle connections.", nil, nil)
+	poolTotalDesc    = prometheus.NewDesc(namespace+"_db_pool_total_connections", "Open connections.", nil, nil)
+	poolMaxDesc      = prometheus.NewDesc(namespace+"_db_pool_max_connections", "Configured maximum connections.", nil, nil)
+	poolAcquiresDesc = prometheus.NewDesc(namespace+"_db_pool_acquires_total", "Successful connection acquires.", nil, nil)
+	poolWaitsDesc    = prometheus.NewDesc(namespace+"_db_pool_empty_acquires_total", "Acquires that had to wait for a connection.", nil, nil)
+	poolWaitDesc     = prometheus.NewDesc(namespace+"_db_pool_acquire_wait_seconds_total", "Time spent waiting for a connection.", nil, nil)
+	poolCanceledDesc = prometheus.NewDesc(namespace+"_db_pool_canceled_acquires_total", "Acquires cancelled before a connection was free.", nil, nil)
+)
+
+type poolCollector struct {
+	pool *pgxpool.Pool
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		poolAcquiredDesc, poolIdleDesc, poolTotalDesc, poolMaxDesc,
+		poolAcquiresDesc, poolWaitsDesc, poolWaitDesc, poolCanceledDesc,
+	} {
+		ch <- d
+	}
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(poolAcquiredDesc, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(poolIdleDesc, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(poolTotalDesc, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(poolMaxDesc, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(poolAcquiresDesc, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolWaitsDesc, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolWaitDesc, prometheus.CounterValue, s.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(poolCanceledDesc, prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+}
+
+// BalanceReader reads a system account balance in kobo
+type BalanceReader func(ctx context.Context, externalID string) (int64, error)
+
+// balanceScrapeTimeout bounds the balance queries run on each scrape
+const balanceScrapeTimeout = 2 * time.Second
+
+var systemBalanceDesc = prometheus.NewDesc(
+	namespace+"_system_account_balance_kobo",
+	"Balance of a system account in kobo.",
+	[]string{"account"}, nil,
+)
+
+// RegisterSystemBalances exports the balances of the given system accounts, read on every scrape
+func RegisterSystemBalances(read BalanceReader, externalIDs ...string) {
+	Registry.MustRegister(&balanceCollector{read: read, accounts: externalIDs})
+}
+
+type balanceCollector struct {
+	read     BalanceReader
+	accounts []string
+}
+
+func (c *balanceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- systemBalanceDesc
+}
+
+func (c *balanceCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), balanceScrapeTimeout)
+	defer cancel()
+
+	for _, account := range c.accounts {
+		balance, err := c.read(ctx, account)
+		if err != nil {
+			ch <- prometheus.NewInvalidMetric(systemBalanceDesc, err)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(systemBalanceDesc, prometheus.GaugeValue, float64(balance), account)
+	}
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ==============================================
+// ACCOUNT EVENT MODELS (Database mapping)
+// ==============================================
+
+// AccountEvent is a money movement on a user account, recorded by the
+// 019_account_events.sql triggers and announced on the account_events channel
+// The json tags match the notification payload (row_to_json of the row).
+type AccountEvent struct {
+	ID        int64           `db:"id" json:"id"`
+	UserID    int             `db:"user_id" json:"user_id"`
+	AccountID int64           `db:"account_id" json:"account_id"`
+	Type      string          `db:"type" json:"type"`
+	Data      json.RawMessage `db:"data" json:"data"` // Shape depends on Type
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+}
+
+// ==============================================
+// ACCOUNT EVENT CONSTANTS
+// ==============================================
+
+const (
+	AccountEventTransactionPosted = "transaction_posted"
+	AccountEventBalanceChanged    = "balance_changed"
+)
+
+// AccountEventsChannel is the LISTEN/NOTIFY channel events are announced on
+const AccountEventsChannel = "account_events"
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// AML CASE MODEL (Database mapping)
+// ==============================================
+
+// AMLCase is a suspicious pattern on one account awaiting compliance review
+type AMLCase struct {
+	ID             int64              `db:"id"`
+	AccountID      int64              `db:"account_id"`
+	UserID         pgtype.Int4        `db:"user_id"`
+	Typology       string             `db:"typology"`
+	Status         string             `db:"status"` // 'open', 'escalated', 'closed'
+	Summary        string             `db:"summary"`
+	Details        map[string]any     `db:"details"`
+	ScanRunID      pgtype.Int8        `db:"scan_run_id"`
+	LastDetectedAt time.Time          `db:"last_detected_at"`
+	AssignedTo     pgtype.Int4        `db:"assigned_to"`
+	AssignedAt     pgtype.Timestamptz `db:"assigned_at"`
+	EscalatedBy    pgtype.Int4        `db:"escalated_by"`
+	EscalatedAt    pgtype.Timestamptz `db:"escalated_at"`
+	AccountFrozen  bool               `db:"account_frozen"`
+	Resolution     pgtype.Text        `db:"resolution"`
+	ClosedBy       pgtype.Int4        `db:"closed_by"`
+	ClosedAt       pgtype.Timestamptz `db:"closed_at"`
+	CreatedAt      time.Time          `db:"created_at"`
+	UpdatedAt      time.Time          `db:"updated_at"`
+}
+
+// IsClosed checks if the case has been resolved
+func (c *AMLCase) IsClosed() bool {
+	return c.Status == AMLCaseStatusClosed
+}
+
+// AMLCaseNote is an investigator's comment on a case
+type AMLCaseNote struct {
+	ID        int64       `db:"id"`
+	CaseID    int64       `db:"case_id"`
+	AuthorID  pgtype.Int4 `db:"author_id"`
+	Body      string      `db:"body"`
+	CreatedAt time.Time   `db:"created_at"`
+}
+
+// AMLScanRun records one pass of the monitoring job
+type AMLScanRun struct {
+	ID           int64              `db:"id"`
+	StartedAt    time.Time          `db:"started_at"`
+	FinishedAt   pgtype.Timestamptz `db:"finished_at"`
+	CasesOpened  int32              `db:"cases_opened"`
+	CasesUpdated int32              `db:"cases_updated"`
+	Error        pgtype.Text        `db:"error"`
+}
+
+// AMLDetection is one account matching a typology during a scan
+type AMLDetection struct {
+	AccountID      int64
+	UserID         pgtype.Int4
+	Typology       string
+	Summary        string
+	Details        map[string]any
+	TransactionIDs []int64
+}
+
+// ==============================================
+// AML CONSTANTS
+// ==============================================
+const (
+	AMLTypologyStructuring         = "structuring"
+	AMLTypologyRapidInOut          = "rapid_in_out"
+	AMLTypologyFanIn               = "fan_in"
+	AMLTypologyFanOut              = "fan_out"
+	AMLTypologyDormantReactivation = "dormant_reactivation"
+
+	AMLCaseStatusOpen      = "open"
+	AMLCaseStatusEscalated = "escalated"
+	AMLCaseStatusClosed    = "closed"
+
+	AMLResolutionFalsePositive = "false_positive"
+	AMLResolutionNoAction      = "no_action"
+	AMLResolutionReported      = "reported"
+)
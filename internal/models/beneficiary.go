@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// BENEFICIARY MODEL (Database mapping)
+// ==============================================
+
+// Beneficiary is a saved recipient in a user's address book
+type Beneficiary struct {
+	ID         int64       `db:"id"`
+	UserID     int32       `db:"user_id"`
+	AccountID  int64       `db:"account_id"`
+	Identifier string      `db:"identifier"` // As entered, used as ToIdentifier when paying
+	Nickname   pgtype.Text `db:"nickname"`
+	Status     string      `db:"status"` // 'active', 'held', 'blocked'
+	CreatedAt  time.Time   `db:"created_at"`
+	UpdatedAt  time.Time   `db:"updated_at"`
+
+	// Joined from accounts
+	AccountNumber string `db:"account_number"`
+	AccountName   string `db:"account_name"`
+}
+
+// ==============================================
+// RECIPIENT SUMMARY (Derived from transactions)
+// ==============================================
+
+// RecipientSummary aggregates a user's past P2P transfers to one account
+type RecipientSummary struct {
+	AccountID      int64     `db:"account_id"`
+	AccountNumber  string    `db:"account_number"`
+	AccountName    string    `db:"account_name"`
+	LastIdentifier string    `db:"last_identifier"` // ToIdentifier used on the most recent transfer
+	TransferCount  int       `db:"transfer_count"`
+	TotalAmount    int64     `db:"total_amount"` // In kobo
+	LastTransferAt time.Time `db:"last_transfer_at"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// DEVICE MODEL (Database mapping)
+// ==============================================
+
+// UserDevice is a device a user has signed in from
+type UserDevice struct {
+	ID          int64              `db:"id"`
+	UserID      int32              `db:"user_id"`
+	Fingerprint string             `db:"fingerprint"`
+	Name        string             `db:"name"`
+	UserAgent   pgtype.Text        `db:"user_agent"`
+	LastIP      pgtype.Text        `db:"last_ip"`
+	FirstSeenAt time.Time          `db:"first_seen_at"`
+	LastSeenAt  time.Time          `db:"last_seen_at"`
+	RevokedAt   pgtype.Timestamptz `db:"revoked_at"`
+	CreatedAt   time.Time          `db:"created_at"`
+	UpdatedAt   time.Time          `db:"updated_at"`
+
+	// Not a column: sessions on this device that are still open
+	ActiveSessions int `db:"-"`
+}
+
+// IsTrusted checks if sign-ins from this device are recognised
+func (d *UserDevice) IsTrusted() bool {
+	return !d.RevokedAt.Valid
+}
+
+// ==============================================
+// DEVICE CONSTANTS
+// ==============================================
+const (
+	DeviceNameMaxLength = 64
+	DefaultDeviceName   = "Unknown device"
+)
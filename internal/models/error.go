@@ -102,6 +102,8 @@ var (
 // ==============================================
 // ERROR CODES (for API responses)
 // ==============================================
+// Codes are part of the API contract: clients branch on them, so never
+// rename one. Several errors may share a code when clients handle them alike.
 const (
 	// Auth error codes
 	ErrCodeInvalidCredentials   = "INVALID_CREDENTIALS"
@@ -111,27 +113,115 @@ const (
 	ErrCodeUserExists           = "USER_EXISTS"
 	ErrCodeWeakPassword         = "WEAK_PASSWORD"
 	ErrCodeInvalidPin           = "INVALID_PIN"
-	
+	ErrCodeIncorrectPin         = "INCORRECT_PIN"
+	ErrCodePinNotSet            = "PIN_NOT_SET"
+	ErrCodePasswordIncorrect    = "PASSWORD_INCORRECT"
+	ErrCodeInvalidToken         = "INVALID_TOKEN"
+	ErrCodeTokenExpired         = "TOKEN_EXPIRED"
+	ErrCodeSessionExpired       = "SESSION_EXPIRED"
+	ErrCodeMFACodeInvalid       = "MFA_CODE_INVALID"
+	ErrCodeMFAChallengeExpired  = "MFA_CHALLENGE_EXPIRED"
+	ErrCodeMFAAlreadyEnabled    = "MFA_ALREADY_ENABLED"
+	ErrCodeMFANotEnabled        = "MFA_NOT_ENABLED"
+	ErrCodeMFAEnrollmentMissing = "MFA_ENROLLMENT_NOT_STARTED"
+	ErrCodeStepUpRequired       = "STEP_UP_REQUIRED"
+	ErrCodeStepUpExpired        = "STEP_UP_EXPIRED"
+	ErrCodeStepUpCodeInvalid    = "STEP_UP_CODE_INVALID"
+	ErrCodeStepUpMismatch       = "STEP_UP_MISMATCH"
+	ErrCodeInsufficientRole     = "INSUFFICIENT_ROLE"
+	ErrCodeSelfAction           = "SELF_ACTION"
+
+	// User profile error codes
+	ErrCodeInvalidPhone      = "INVALID_PHONE"
+	ErrCodeInvalidEmail      = "INVALID_EMAIL"
+	ErrCodePhoneExists       = "PHONE_EXISTS"
+	ErrCodeEmailExists       = "EMAIL_EXISTS"
+	ErrCodeUsernameTaken     = "USERNAME_TAKEN"
+	ErrCodeContactUnchanged  = "CONTACT_UNCHANGED"
+	ErrCodeInvalidDeviceName = "INVALID_DEVICE_NAME"
+	ErrCodeInvalidRole       = "INVALID_ROLE"
+	ErrCodeLockInPast        = "LOCK_IN_PAST"
+
 	// OTP error codes
-	ErrCodeOTPExpired          = "OTP_EXPIRED"
-	ErrCodeOTPInvalid          = "OTP_INVALID"
-	ErrCodeOTPMaxAttempts      = "OTP_MAX_ATTEMPTS"
-	
+	ErrCodeOTPExpired        = "OTP_EXPIRED"
+	ErrCodeOTPInvalid        = "OTP_INVALID"
+	ErrCodeOTPMaxAttempts    = "OTP_MAX_ATTEMPTS"
+	ErrCodeOTPResendCooldown = "OTP_RESEND_COOLDOWN"
+
 	// Wallet error codes
-	ErrCodeInsufficientBalance = "INSUFFICIENT_BALANCE"
-	ErrCodeAccountFrozen       = "ACCOUNT_FROZEN"
-	ErrCodeInvalidAmount       = "INVALID_AMOUNT"
-	
+	ErrCodeInsufficientBalance   = "INSUFFICIENT_BALANCE"
+	ErrCodeAccountFrozen         = "ACCOUNT_FROZEN"
+	ErrCodeInvalidAmount         = "INVALID_AMOUNT"
+	ErrCodeAmountTooSmall        = "AMOUNT_TOO_SMALL"
+	ErrCodeAmountTooLarge        = "AMOUNT_TOO_LARGE"
+	ErrCodeUnsupportedCurrency   = "UNSUPPORTED_CURRENCY"
+	ErrCodeSameAccount           = "SAME_ACCOUNT"
+	ErrCodeSystemAccountTransfer = "SYSTEM_ACCOUNT_TRANSFER"
+	ErrCodeAccountNotFound       = "ACCOUNT_NOT_FOUND"
+	ErrCodeUserNotFound          = "USER_NOT_FOUND"
+	ErrCodeRecipientNotFound     = "RECIPIENT_NOT_FOUND"
+	ErrCodePayerNotFound         = "PAYER_NOT_FOUND"
+	ErrCodeRecipientLimit        = "RECIPIENT_LIMIT_EXCEEDED"
+	ErrCodeRecipientOnHold       = "RECIPIENT_ON_HOLD"
+	ErrCodeBeneficiaryExists     = "BENEFICIARY_EXISTS"
+
 	// Transaction error codes
-	ErrCodeTransactionFailed   = "TRANSACTION_FAILED"
-	ErrCodeDuplicateTransaction = "DUPLICATE_TRANSACTION"
-	
+	ErrCodeTransactionFailed     = "TRANSACTION_FAILED"
+	ErrCodeDuplicateTransaction  = "DUPLICATE_TRANSACTION"
+	ErrCodeIdempotencyKeyMissing = "IDEMPOTENCY_KEY_REQUIRED"
+	ErrCodeIdempotencyConflict   = "IDEMPOTENCY_CONFLICT"
+	ErrCodeInvalidIdempotencyKey = "INVALID_IDEMPOTENCY_KEY"
+	ErrCodeRequestInProgress     = "REQUEST_IN_PROGRESS"
+	ErrCodeTransactionDeclined   = "TRANSACTION_DECLINED"
+	ErrCodeRiskChallenge         = "RISK_CHALLENGE_REQUIRED"
+	ErrCodeScreeningHold         = "SCREENING_HOLD"
+
+	// Payment request and QR error codes
+	ErrCodeSelfPaymentRequest    = "SELF_PAYMENT_REQUEST"
+	ErrCodeNotPayer              = "NOT_PAYER"
+	ErrCodePaymentRequestClosed  = "PAYMENT_REQUEST_NOT_OPEN"
+	ErrCodePaymentRequestExpired = "PAYMENT_REQUEST_EXPIRED"
+	ErrCodeInvalidQRCode         = "INVALID_QR_CODE"
+	ErrCodeUnsupportedQRCode     = "UNSUPPORTED_QR_CODE"
+
+	// KYC error codes
+	ErrCodeTierSingleLimit     = "TIER_SINGLE_LIMIT_EXCEEDED"
+	ErrCodeTierDailyLimit      = "TIER_DAILY_LIMIT_EXCEEDED"
+	ErrCodeTierMonthlyLimit    = "TIER_MONTHLY_LIMIT_EXCEEDED"
+	ErrCodeTierMaxBalance      = "TIER_MAX_BALANCE_EXCEEDED"
+	ErrCodeKYCTierNotHigher    = "KYC_TIER_NOT_HIGHER"
+	ErrCodeKYCAddressRequired  = "KYC_ADDRESS_REQUIRED"
+	ErrCodeKYCDocumentType     = "KYC_DOCUMENT_TYPE"
+	ErrCodeKYCDocumentsMissing = "KYC_DOCUMENTS_REQUIRED"
+	ErrCodeKYCPending          = "KYC_SUBMISSION_PENDING"
+	ErrCodeDocumentTooLarge    = "DOCUMENT_TOO_LARGE"
+
+	// Staff tooling error codes
+	ErrCodeReasonRequired    = "REASON_REQUIRED"
+	ErrCodeSelfReview        = "SELF_REVIEW"
+	ErrCodeAlreadyReviewed   = "ALREADY_REVIEWED"
+	ErrCodeAMLCaseClosed     = "AML_CASE_CLOSED"
+	ErrCodeAssigneeNotFound  = "ASSIGNEE_NOT_FOUND"
+	ErrCodeInvalidRuleParams = "INVALID_RULE_PARAMS"
+	ErrCodeWatchlistReload   = "WATCHLIST_RELOAD_FAILED"
+
+	// Journal entry error codes
+	ErrCodeJournalUnbalanced  = "JOURNAL_UNBALANCED"
+	ErrCodeCurrencyMismatch   = "CURRENCY_MISMATCH"
+	ErrCodeAttachmentType     = "ATTACHMENT_TYPE"
+	ErrCodeAttachmentsMissing = "ATTACHMENTS_REQUIRED"
+	ErrCodeNotMaker           = "NOT_MAKER"
+
+	// Event stream error codes
+	ErrCodeStreamUnavailable = "STREAM_UNAVAILABLE"
+
 	// Generic error codes
-	ErrCodeNotFound            = "NOT_FOUND"
-	ErrCodeValidationFailed    = "VALIDATION_FAILED"
-	ErrCodeInternalError       = "INTERNAL_ERROR"
-	ErrCodeUnauthorized        = "UNAUTHORIZED"
-	ErrCodeForbidden           = "FORBIDDEN"
+	ErrCodeNotFound         = "NOT_FOUND"
+	ErrCodeValidationFailed = "VALIDATION_FAILED"
+	ErrCodeInternalError    = "INTERNAL_ERROR"
+	ErrCodeUnauthorized     = "UNAUTHORIZED"
+	ErrCodeForbidden        = "FORBIDDEN"
+	ErrCodeRequestTooLarge  = "REQUEST_TOO_LARGE"
 )
 
 // ==============================================
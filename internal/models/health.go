@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// HEALTH MODELS (Database mapping)
+// ==============================================
+
+// WorkerHeartbeat is the outcome of a background job's most recent run
+type WorkerHeartbeat struct {
+	Job             string             `db:"job"`
+	IntervalSeconds int64              `db:"interval_seconds"`
+	LastRunAt       time.Time          `db:"last_run_at"`
+	LastSuccessAt   pgtype.Timestamptz `db:"last_success_at"`
+	LastError       pgtype.Text        `db:"last_error"` // Set while the latest run failed
+	UpdatedAt       time.Time          `db:"updated_at"`
+}
+
+// Interval is how often the job is scheduled to run
+func (h *WorkerHeartbeat) Interval() time.Duration {
+	return time.Duration(h.IntervalSeconds) * time.Second
+}
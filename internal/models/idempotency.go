@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// IDEMPOTENCY MODELS (Database mapping)
+// ==============================================
+
+// IdempotencyKey is a claimed Idempotency-Key and, once finished, the response to replay
+type IdempotencyKey struct {
+	ID                  int64              `db:"id"`
+	UserID              int                `db:"user_id"`
+	Endpoint            string             `db:"endpoint"` // Method and route template
+	Key                 string             `db:"key"`
+	Fingerprint         []byte             `db:"fingerprint"` // SHA-256 of the request
+	Status              string             `db:"status"`
+	LockedUntil         time.Time          `db:"locked_until"` // Lease of the in-progress attempt
+	ResponseStatus      pgtype.Int4        `db:"response_status"`
+	ResponseContentType pgtype.Text        `db:"response_content_type"`
+	ResponseBody        []byte             `db:"response_body"`
+	CreatedAt           time.Time          `db:"created_at"`
+	CompletedAt         pgtype.Timestamptz `db:"completed_at"`
+	ExpiresAt           time.Time          `db:"expires_at"`
+}
+
+// IsCompleted reports whether the response has been stored
+func (k *IdempotencyKey) IsCompleted() bool {
+	return k.Status == IdempotencyStatusCompleted
+}
+
+// ==============================================
+// IDEMPOTENCY CONSTANTS
+// ==============================================
+
+const (
+	IdempotencyStatusInProgress = "in_progress"
+	IdempotencyStatusCompleted  = "completed"
+)
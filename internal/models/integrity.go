@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// HASH CHAIN MODELS (Database mapping)
+// ==============================================
+
+// ChainHead is the last row linked into a hash chain
+type ChainHead struct {
+	Chain     string      `db:"chain"`
+	StartID   pgtype.Int8 `db:"start_id"` // First row in the chain; earlier rows predate it
+	LastID    pgtype.Int8 `db:"last_id"`
+	LastHash  []byte      `db:"last_hash"`
+	Rows      int64       `db:"rows"`
+	UpdatedAt time.Time   `db:"updated_at"`
+}
+
+// ChainCheckpoint is a signed snapshot of a chain head
+type ChainCheckpoint struct {
+	ID        int64     `db:"id"`
+	Chain     string    `db:"chain"`
+	LastID    int64     `db:"last_id"`
+	LastHash  []byte    `db:"last_hash"`
+	Rows      int64     `db:"rows"`
+	KeyID     string    `db:"key_id"`
+	Signature string    `db:"signature"` // Compact JWS over the checkpoint claims
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// ==============================================
+// HASH CHAIN CONSTANTS
+// ==============================================
+
+// Tables protected by a hash chain; the chain is named after its table
+const (
+	ChainTransactions = "transactions"
+	ChainAuditLogs    = "audit_logs"
+)
+
+// Chains lists every hash chain
+var Chains = []string{ChainTransactions, ChainAuditLogs}
+
+// IsValidChain checks if chain names a hash chain
+func IsValidChain(chain string) bool {
+	return chain == ChainTransactions || chain == ChainAuditLogs
+}
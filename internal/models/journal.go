@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// JOURNAL ENTRY MODEL (Database mapping)
+// ==============================================
+
+// JournalEntry is a manual posting between arbitrary accounts, held until
+// a second staff member approves it
+type JournalEntry struct {
+	ID            int64              `db:"id"`
+	Reference     string             `db:"reference"`
+	Status        string             `db:"status"` // 'pending_approval', 'posted', 'rejected'
+	Currency      string             `db:"currency"`
+	Reason        string             `db:"reason"`
+	MakerUserID   int32              `db:"maker_user_id"`
+	CheckerUserID pgtype.Int4        `db:"checker_user_id"`
+	ReviewNote    pgtype.Text        `db:"review_note"`
+	ReviewedAt    pgtype.Timestamptz `db:"reviewed_at"`
+	TransactionID pgtype.Int8        `db:"transaction_id"` // Set once posted
+	CreatedAt     time.Time          `db:"created_at"`
+	UpdatedAt     time.Time          `db:"updated_at"`
+}
+
+// IsPending checks if the entry is still waiting for a checker
+func (e *JournalEntry) IsPending() bool {
+	return e.Status == JournalStatusPendingApproval
+}
+
+// JournalLeg is one account's side of an entry
+type JournalLeg struct {
+	ID            int64       `db:"id"`
+	EntryID       int64       `db:"entry_id"`
+	AccountID     int64       `db:"account_id"`
+	AccountNumber string      `db:"account_number"` // Joined from accounts
+	Amount        int64       `db:"amount"`         // In kobo; positive credits, negative debits
+	Memo          pgtype.Text `db:"memo"`
+}
+
+// JournalAttachment is a supporting document uploaded to an entry
+type JournalAttachment struct {
+	ID          int64     `db:"id"`
+	EntryID     int64     `db:"entry_id"`
+	UploadedBy  int32     `db:"uploaded_by"`
+	StorageKey  string    `db:"storage_key"` // Key in the configured storage backend
+	FileName    string    `db:"file_name"`
+	ContentType string    `db:"content_type"`
+	SizeBytes   int64     `db:"size_bytes"`
+	SHA256      string    `db:"sha256"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// ==============================================
+// JOURNAL ENTRY CONSTANTS
+// ==============================================
+const (
+	JournalStatusPendingApproval = "pending_approval"
+	JournalStatusPosted          = "posted"
+	JournalStatusRejected        = "rejected"
+)
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// KYC TIER MODEL (Database mapping)
+// ==============================================
+
+// KYCTier holds the limits that apply to every user on a tier (all amounts in kobo)
+type KYCTier struct {
+	Tier                 int32       `db:"tier"`
+	Name                 string      `db:"name"`
+	Requirements         string      `db:"requirements"`
+	MaxSingleTransaction int64       `db:"max_single_transaction"`
+	DailyDebitLimit      int64       `db:"daily_debit_limit"`
+	MonthlyDebitLimit    int64       `db:"monthly_debit_limit"`
+	MaxBalance           pgtype.Int8 `db:"max_balance"` // NULL = unlimited
+	CreatedAt            time.Time   `db:"created_at"`
+	UpdatedAt            time.Time   `db:"updated_at"`
+}
+
+// AllowsBalance checks if an account on this tier may hold the given balance
+func (t *KYCTier) AllowsBalance(balance int64) bool {
+	return !t.MaxBalance.Valid || balance <= t.MaxBalance.Int64
+}
+
+// ==============================================
+// KYC TIER CONSTANTS
+// ==============================================
+const (
+	KYCTier1 = 1 // Phone + email
+	KYCTier2 = 2 // + BVN / NIN
+	KYCTier3 = 3 // + address and documents
+
+	KYCDailyWindow   = 24 * time.Hour
+	KYCMonthlyWindow = 30 * 24 * time.Hour
+)
+
+// ==============================================
+// KYC SUBMISSION MODEL (Database mapping)
+// ==============================================
+
+// KYCSubmission is a user's request to move up to a higher tier
+type KYCSubmission struct {
+	ID                    int64              `db:"id"`
+	UserID                int32              `db:"user_id"`
+	TargetTier            int32              `db:"target_tier"`
+	IDType                string             `db:"id_type"` // 'bvn', 'nin'
+	IDNumber              string             `db:"id_number"`
+	Address               pgtype.Text        `db:"address"` // Required for tier 3
+	Status                string             `db:"status"`  // 'pending', 'approved', 'rejected'
+	VerificationStatus    string             `db:"verification_status"`
+	VerificationReference pgtype.Text        `db:"verification_reference"`
+	VerifiedName          pgtype.Text        `db:"verified_name"`
+	ReviewerUserID        pgtype.Int4        `db:"reviewer_user_id"`
+	ReviewReason          pgtype.Text        `db:"review_reason"`
+	ReviewedAt            pgtype.Timestamptz `db:"reviewed_at"`
+	CreatedAt             time.Time          `db:"created_at"`
+	UpdatedAt             time.Time          `db:"updated_at"`
+}
+
+// IsPending checks if the submission is still waiting for review
+func (s *KYCSubmission) IsPending() bool {
+	return s.Status == KYCSubmissionStatusPending
+}
+
+// KYCDocument is an uploaded file attached to a submission
+type KYCDocument struct {
+	ID           int64     `db:"id"`
+	SubmissionID int64     `db:"submission_id"`
+	UserID       int32     `db:"user_id"`
+	DocumentType string    `db:"document_type"`
+	StorageKey   string    `db:"storage_key"` // Key in the configured storage backend
+	FileName     string    `db:"file_name"`
+	ContentType  string    `db:"content_type"`
+	SizeBytes    int64     `db:"size_bytes"`
+	SHA256       string    `db:"sha256"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+// ==============================================
+// KYC SUBMISSION CONSTANTS
+// ==============================================
+const (
+	KYCSubmissionStatusPending  = "pending"
+	KYCSubmissionStatusApproved = "approved"
+	KYCSubmissionStatusRejected = "rejected"
+
+	KYCIDTypeBVN = "bvn"
+	KYCIDTypeNIN = "nin"
+
+	// Result of the automated identity check, shown to reviewers
+	KYCVerificationMatched  = "matched"
+	KYCVerificationMismatch = "mismatch"
+	KYCVerificationNotFound = "not_found"
+	KYCVerificationError    = "error"
+
+	KYCDocumentIDCard         = "id_card"
+	KYCDocumentPassport       = "passport"
+	KYCDocumentDriversLicense = "drivers_license"
+	KYCDocumentUtilityBill    = "utility_bill"
+	KYCDocumentSelfie         = "selfie"
+)
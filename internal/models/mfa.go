@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// MFA MODELS (Database mapping)
+// ==============================================
+
+// UserMFA is a user's second factor; a TOTP enrollment is pending until EnabledAt is set
+type UserMFA struct {
+	UserID       int32              `db:"user_id"`
+	Method       string             `db:"method"`      // 'totp', 'email'
+	TOTPSecret   pgtype.Text        `db:"totp_secret"` // Encrypted
+	LastTOTPStep pgtype.Int8        `db:"last_totp_step"`
+	EnabledAt    pgtype.Timestamptz `db:"enabled_at"`
+	CreatedAt    time.Time          `db:"created_at"`
+	UpdatedAt    time.Time          `db:"updated_at"`
+}
+
+// IsEnabled checks if login requires the second factor
+func (m *UserMFA) IsEnabled() bool {
+	return m.EnabledAt.Valid
+}
+
+// MFAChallenge is the pending second step of a login
+type MFAChallenge struct {
+	ID          int64              `db:"id"`
+	UserID      int32              `db:"user_id"`
+	TokenHash   string             `db:"token_hash"`
+	Method      string             `db:"method"`
+	Reason      string             `db:"reason"` // 'mfa', 'new_device'
+	Fingerprint pgtype.Text        `db:"device_fingerprint"`
+	Attempts    int32              `db:"attempts"`
+	ExpiresAt   time.Time          `db:"expires_at"`
+	CompletedAt pgtype.Timestamptz `db:"completed_at"`
+	IPAddress   pgtype.Text        `db:"ip_address"`
+	UserAgent   pgtype.Text        `db:"user_agent"`
+	CreatedAt   time.Time          `db:"created_at"`
+}
+
+// IsUsable checks if the challenge can still be answered
+func (c *MFAChallenge) IsUsable() bool {
+	return !c.CompletedAt.Valid && time.Now().Before(c.ExpiresAt) && c.Attempts < MFAChallengeMaxAttempts
+}
+
+// ==============================================
+// MFA CONSTANTS
+// ==============================================
+const (
+	MFAMethodTOTP  = "totp"
+	MFAMethodEmail = "email"
+
+	MFAReasonEnabled   = "mfa"        // The user has 2FA on
+	MFAReasonNewDevice = "new_device" // Signing in from an unrecognised device
+
+	MFAChallengeTTL         = 5 * time.Minute
+	MFAChallengeMaxAttempts = 5
+)
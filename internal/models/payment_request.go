@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// PAYMENT REQUEST MODEL (Database mapping)
+// ==============================================
+
+// PaymentRequest is a pull payment: the requester asks a payer for money
+type PaymentRequest struct {
+	ID              int64              `db:"id"`
+	Token           string             `db:"token"` // Shareable link token
+	RequesterUserID int32              `db:"requester_user_id"`
+	PayerUserID     pgtype.Int4        `db:"payer_user_id"`    // NULL = open link, anyone can pay
+	PayerIdentifier pgtype.Text        `db:"payer_identifier"` // @username / phone as entered
+	Amount          int64              `db:"amount"`           // In kobo
+	Currency        string             `db:"currency"`
+	Description     pgtype.Text        `db:"description"`
+	Status          string             `db:"status"` // 'open', 'paid', 'declined', 'expired'
+	TransactionID   pgtype.Int8        `db:"transaction_id"`
+	PaidByUserID    pgtype.Int4        `db:"paid_by_user_id"`
+	DeclineReason   pgtype.Text        `db:"decline_reason"`
+	ExpiresAt       time.Time          `db:"expires_at"`
+	PaidAt          pgtype.Timestamptz `db:"paid_at"`
+	DeclinedAt      pgtype.Timestamptz `db:"declined_at"`
+	CreatedAt       time.Time          `db:"created_at"`
+	UpdatedAt       time.Time          `db:"updated_at"`
+}
+
+// IsOpen checks if the request can still be paid or declined
+func (p *PaymentRequest) IsOpen() bool {
+	return p.Status == PaymentRequestStatusOpen && !p.IsExpired()
+}
+
+// IsExpired checks if the request is past its expiry time
+func (p *PaymentRequest) IsExpired() bool {
+	return p.Status == PaymentRequestStatusExpired ||
+		(p.Status == PaymentRequestStatusOpen && time.Now().After(p.ExpiresAt))
+}
+
+// IsAddressed checks if the request targets a specific payer (vs. an open link)
+func (p *PaymentRequest) IsAddressed() bool {
+	return p.PayerUserID.Valid
+}
+
+// CanBePaidBy checks if userID is allowed to settle this request
+func (p *PaymentRequest) CanBePaidBy(userID int32) bool {
+	if userID == p.RequesterUserID {
+		return false
+	}
+	return !p.IsAddressed() || p.PayerUserID.Int32 == userID
+}
+
+// ==============================================
+// PAYMENT REQUEST CONSTANTS
+// ==============================================
+
+// Payment Request Statuses
+const (
+	PaymentRequestStatusOpen     = "open"
+	PaymentRequestStatusPaid     = "paid"
+	PaymentRequestStatusDeclined = "declined"
+	PaymentRequestStatusExpired  = "expired"
+)
+
+// Payment Request Limits
+const (
+	PaymentRequestDefaultExpiry = 7 * 24 * time.Hour  // 7 days
+	PaymentRequestMaxExpiry     = 30 * 24 * time.Hour // 30 days
+)
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// RECONCILIATION MODELS (Database mapping)
+// ==============================================
+
+// Reconciliation is one consistent check of the ledger against its postings
+type Reconciliation struct {
+	Accounts               int64
+	Transactions           int64
+	BalanceTotal           int64 // Sum of account balances
+	PostingTotal           int64 // Sum of all postings; zero in a balanced ledger
+	BalanceMismatches      []BalanceMismatch
+	UnbalancedTransactions []UnbalancedTransaction
+	CheckedAt              time.Time
+}
+
+// BalanceMismatch is an account whose stored balance differs from the sum of its postings
+type BalanceMismatch struct {
+	AccountID     int64       `db:"id"`
+	AccountNumber string      `db:"account_number"`
+	ExternalID    pgtype.Text `db:"external_id"`
+	Balance       int64       `db:"balance"`
+	PostedBalance int64       `db:"posted_balance"`
+}
+
+// UnbalancedTransaction is a transaction whose postings don't sum to zero,
+// or a posted one with no postings at all
+type UnbalancedTransaction struct {
+	TransactionID int64  `db:"id"`
+	Reference     string `db:"reference"`
+	Status        string `db:"status"`
+	PostingTotal  int64  `db:"posting_total"`
+	Postings      int    `db:"postings"`
+}
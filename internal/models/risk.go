@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// RISK RULE MODEL (Database mapping)
+// ==============================================
+
+// RiskRule is a configurable fraud check run before money leaves an account
+type RiskRule struct {
+	Code        string           `db:"code"`
+	Description string           `db:"description"`
+	Enabled     bool             `db:"enabled"`
+	Action      string           `db:"action"` // 'challenge', 'block'
+	Params      map[string]int64 `db:"params"` // Thresholds, amounts in kobo
+	UpdatedBy   pgtype.Int4      `db:"updated_by"`
+	CreatedAt   time.Time        `db:"created_at"`
+	UpdatedAt   time.Time        `db:"updated_at"`
+}
+
+// Param returns a threshold, or 0 if it isn't configured
+func (r *RiskRule) Param(name string) int64 {
+	return r.Params[name]
+}
+
+// ==============================================
+// RISK DECISION MODEL (Database mapping)
+// ==============================================
+
+// RiskDecision records the outcome of screening one withdrawal or transfer
+type RiskDecision struct {
+	ID                    int64         `db:"id"`
+	UserID                int32         `db:"user_id"`
+	AccountID             int64         `db:"account_id"`
+	Operation             string        `db:"operation"` // 'withdraw', 'transfer'
+	Amount                int64         `db:"amount"`
+	CounterpartyAccountID pgtype.Int8   `db:"counterparty_account_id"`
+	IdempotencyKey        pgtype.Text   `db:"idempotency_key"`
+	DeviceID              pgtype.Text   `db:"device_id"`
+	IPAddress             pgtype.Text   `db:"ip_address"`
+	UserAgent             pgtype.Text   `db:"user_agent"`
+	Outcome               string        `db:"outcome"` // 'allow', 'challenge', 'block'
+	FiredRules            []RiskRuleHit `db:"fired_rules"`
+	TransactionID         pgtype.Int8   `db:"transaction_id"`
+	CreatedAt             time.Time     `db:"created_at"`
+}
+
+// RiskRuleHit is one rule that fired during screening
+type RiskRuleHit struct {
+	Code   string `json:"code"`
+	Action string `json:"action"`
+	Detail string `json:"detail"`
+}
+
+// IsAllowed checks if the payment may be posted
+func (d *RiskDecision) IsAllowed() bool {
+	return d.Outcome == RiskOutcomeAllow
+}
+
+// ==============================================
+// RISK CONSTANTS
+// ==============================================
+const (
+	RiskOutcomeAllow     = "allow"
+	RiskOutcomeChallenge = "challenge"
+	RiskOutcomeBlock     = "block"
+
+	RiskOperationWithdraw = "withdraw"
+	RiskOperationTransfer = "transfer"
+
+	RiskRuleVelocity                  = "velocity"
+	RiskRuleNewDeviceLargeAmount      = "new_device_large_amount"
+	RiskRuleNewBeneficiaryLargeAmount = "new_beneficiary_large_amount"
+	RiskRuleRecentPasswordReset       = "recent_password_reset"
+)
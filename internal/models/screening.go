@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// SCREENING HIT MODEL (Database mapping)
+// ==============================================
+
+// ScreeningHit is a potential watchlist match held for compliance review
+type ScreeningHit struct {
+	ID            int64              `db:"id"`
+	SubjectType   string             `db:"subject_type"` // 'signup', 'kyc_submission', 'beneficiary'
+	SubjectID     int64              `db:"subject_id"`
+	UserID        pgtype.Int4        `db:"user_id"`
+	ScreenedName  string             `db:"screened_name"`
+	ListName      string             `db:"list_name"`
+	EntryUID      string             `db:"entry_uid"`
+	EntryName     string             `db:"entry_name"`
+	MatchedName   string             `db:"matched_name"`
+	EntryType     pgtype.Text        `db:"entry_type"`
+	Program       pgtype.Text        `db:"program"`
+	Score         float64            `db:"score"`
+	Status        string             `db:"status"` // 'pending', 'cleared', 'confirmed'
+	AccountFrozen bool               `db:"account_frozen"`
+	ReviewedBy    pgtype.Int4        `db:"reviewed_by"`
+	ReviewedAt    pgtype.Timestamptz `db:"reviewed_at"`
+	ReviewNote    pgtype.Text        `db:"review_note"`
+	CreatedAt     time.Time          `db:"created_at"`
+	UpdatedAt     time.Time          `db:"updated_at"`
+}
+
+// IsPending checks if the hit still needs a reviewer
+func (h *ScreeningHit) IsPending() bool {
+	return h.Status == ScreeningStatusPending
+}
+
+// ==============================================
+// SCREENING CONSTANTS
+// ==============================================
+const (
+	ScreeningSubjectSignup        = "signup"
+	ScreeningSubjectKYCSubmission = "kyc_submission"
+	ScreeningSubjectBeneficiary   = "beneficiary"
+
+	ScreeningStatusPending   = "pending"
+	ScreeningStatusCleared   = "cleared"
+	ScreeningStatusConfirmed = "confirmed"
+
+	// Placeholder hit recorded when no watchlist is loaded, so the subject
+	// is held for a manual check instead of passing unscreened
+	ScreeningListUnavailable  = "unavailable"
+	ScreeningEntryUnavailable = "no-watchlist-loaded"
+
+	BeneficiaryStatusActive  = "active"
+	BeneficiaryStatusHeld    = "held"
+	BeneficiaryStatusBlocked = "blocked"
+)
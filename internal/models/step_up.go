@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// STEP-UP CHALLENGE MODEL (Database mapping)
+// ==============================================
+
+// StepUpChallenge is a pending second-factor check for one sensitive operation
+type StepUpChallenge struct {
+	ID             int64              `db:"id"`
+	UserID         int32              `db:"user_id"`
+	TokenHash      string             `db:"token_hash"`
+	Action         string             `db:"action"`
+	BindingHash    string             `db:"binding_hash"`
+	Method         string             `db:"method"` // 'totp', 'email'
+	OTPID          pgtype.Int4        `db:"otp_id"`
+	RiskDecisionID pgtype.Int8        `db:"risk_decision_id"`
+	Attempts       int32              `db:"attempts"`
+	ExpiresAt      time.Time          `db:"expires_at"`
+	ConsumedAt     pgtype.Timestamptz `db:"consumed_at"`
+	IPAddress      pgtype.Text        `db:"ip_address"`
+	UserAgent      pgtype.Text        `db:"user_agent"`
+	CreatedAt      time.Time          `db:"created_at"`
+}
+
+// IsUsable checks if the challenge can still be answered
+func (c *StepUpChallenge) IsUsable() bool {
+	return !c.ConsumedAt.Valid && time.Now().Before(c.ExpiresAt) && c.Attempts < StepUpMaxAttempts
+}
+
+// ==============================================
+// STEP-UP CONSTANTS
+// ==============================================
+const (
+	StepUpActionTransfer       = "transfer"
+	StepUpActionWithdraw       = "withdraw"
+	StepUpActionEmailChange    = "email_change"
+	StepUpActionPhoneChange    = "phone_change"
+	StepUpActionPinChange      = "pin_change"
+	StepUpActionBeneficiaryAdd = "beneficiary_add"
+
+	StepUpChallengeTTL = 3 * time.Minute
+	StepUpMaxAttempts  = 3
+)
+
+// StepUpPurpose returns the OTP purpose emailed codes for an action are issued under
+func StepUpPurpose(action string) string {
+	switch action {
+	case StepUpActionTransfer, StepUpActionWithdraw:
+		return OTPPurposeTransactionAuth
+	default:
+		return OTPPurposeSettingsChange
+	}
+}
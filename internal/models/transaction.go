@@ -15,7 +15,7 @@ type Transaction struct {
 	ID              int64              `db:"id"`
 	IdempotencyKey  string             `db:"idempotency_key"`
 	Reference       string             `db:"reference"`
-	Kind            string             `db:"kind"`   // 'p2p', 'deposit', 'withdrawal', 'fee', 'interbank', 'refund'
+	Kind            string             `db:"kind"`   // 'p2p', 'deposit', 'withdrawal', 'fee', 'interbank', 'refund', 'adjustment', 'reversal', 'journal'
 	Status          string             `db:"status"` // 'pending', 'posted', 'failed', 'reversed'
 	Amount          int64              `db:"amount"` // In kobo
 	Currency        string             `db:"currency"`
@@ -29,6 +29,8 @@ type Transaction struct {
 	PostedAt        pgtype.Timestamptz `db:"posted_at"`
 	FailedAt        pgtype.Timestamptz `db:"failed_at"`
 	FailureReason   pgtype.Text        `db:"failure_reason"`
+	PrevHash        []byte             `db:"prev_hash"` // Hash chain link, see pkg/hashchain
+	RowHash         []byte             `db:"row_hash"`
 }
 
 // IsPending checks if transaction is still pending
@@ -78,6 +80,13 @@ const (
 	TransactionKindFee       = "fee"
 	TransactionKindInterbank = "interbank"
 	TransactionKindRefund    = "refund"
+
+	// Posted by ops through cmd/debankctl
+	TransactionKindAdjustment = "adjustment"
+	TransactionKindReversal   = "reversal"
+
+	// Posted when a checker approves a journal entry
+	TransactionKindJournal = "journal"
 )
 
 // Transaction Statuses
@@ -88,6 +97,12 @@ const (
 	TransactionStatusReversed = "reversed"
 )
 
+// Directions, as seen from one account
+const (
+	DirectionCredit = "credit"
+	DirectionDebit  = "debit"
+)
+
 // ==============================================
 // TRANSACTION HISTORY (for user-facing display)
 // ==============================================
@@ -103,4 +118,21 @@ type TransactionHistoryItem struct {
 	Direction    string     `json:"direction"`                  // 'credit' or 'debit' (computed)
 	Counterparty *string    `json:"counterparty,omitempty"`     // Who sent/received (computed)
 	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
-}
\ No newline at end of file
+}
+
+// ==============================================
+// STATEMENTS
+// ==============================================
+
+// StatementEntry is one posting on an account with the transaction it belongs to
+type StatementEntry struct {
+	PostingID     int64       `db:"posting_id"`
+	TransactionID int64       `db:"transaction_id"`
+	Reference     string      `db:"reference"`
+	Kind          string      `db:"kind"`
+	Status        string      `db:"status"`
+	Description   pgtype.Text `db:"description"`
+	Amount        int64       `db:"amount"` // Positive=credit, Negative=debit
+	Currency      string      `db:"currency"`
+	CreatedAt     time.Time   `db:"created_at"`
+}
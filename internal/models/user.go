@@ -23,6 +23,7 @@ type User struct {
 	OnboardingCompleted bool            `db:"onboarding_completed"`
 	FailedLoginAttempts int32           `db:"failed_login_attempts"`
 	LockedUntil         pgtype.Timestamp `db:"locked_until"`
+	Role                string          `db:"role"` // One of the auth.Role* constants
 	CreatedAt           time.Time       `db:"created_at"`
 	UpdatedAt           time.Time       `db:"updated_at"`
 	LastLoginAt         pgtype.Timestamp `db:"last_login_at"`
@@ -83,14 +84,16 @@ func (u *User) IsLocked() bool {
 // ==============================================
 
 type LoginSession struct {
-	ID         int32            `db:"id"`
-	UserID     int32            `db:"user_id"`
-	Token      string           `db:"token"`
-	DeviceInfo pgtype.Text      `db:"device_info"`
-	IPAddress  pgtype.Text      `db:"ip_address"`
-	ExpiresAt  time.Time        `db:"expires_at"`
-	RevokedAt  pgtype.Timestamp `db:"revoked_at"`
-	CreatedAt  time.Time        `db:"created_at"`
+	ID         int32              `db:"id"`
+	UserID     int32              `db:"user_id"`
+	Token      string             `db:"token"`       // SHA-256 of the session ID in the token's jti claim
+	DeviceID   pgtype.Int8        `db:"device_id"`   // Null if the client sent no device fingerprint
+	DeviceInfo pgtype.Text        `db:"device_info"` // JSON
+	IPAddress  pgtype.Text        `db:"ip_address"`
+	UserAgent  pgtype.Text        `db:"user_agent"`
+	ExpiresAt  time.Time          `db:"expires_at"`
+	RevokedAt  pgtype.Timestamptz `db:"revoked_at"`
+	CreatedAt  time.Time          `db:"created_at"`
 }
 
 func (s *LoginSession) IsValid() bool {
@@ -111,21 +114,62 @@ type AuditLog struct {
 	IPAddress  pgtype.Text      `db:"ip_address"`
 	UserAgent  pgtype.Text      `db:"user_agent"`
 	CreatedAt  time.Time        `db:"created_at"`
+	PrevHash   []byte           `db:"prev_hash"` // Hash chain link, see pkg/hashchain
+	RowHash    []byte           `db:"row_hash"`
 }
 
 // ==============================================
 // AUDIT ACTION CONSTANTS
 // ==============================================
 const (
-	AuditActionLogin           = "login"
-	AuditActionLoginFailed     = "login_failed"
-	AuditActionLogout          = "logout"
-	AuditActionPasswordChange  = "password_change"
-	AuditActionPinChange       = "pin_change"
-	AuditActionTransfer        = "transfer"
-	AuditActionOTPSent         = "otp_sent"
-	AuditActionOTPVerified     = "otp_verified"
-	AuditActionAccountLocked   = "account_locked"
-	AuditActionAccountUnlocked = "account_unlocked"
-	AuditActionSettingsChanged = "settings_changed"
+	AuditActionLogin              = "login"
+	AuditActionLoginFailed        = "login_failed"
+	AuditActionLogout             = "logout"
+	AuditActionPasswordChange     = "password_change"
+	AuditActionPinChange          = "pin_change"
+	AuditActionTransfer           = "transfer"
+	AuditActionOTPSent            = "otp_sent"
+	AuditActionOTPVerified        = "otp_verified"
+	AuditActionAccountLocked      = "account_locked"
+	AuditActionAccountUnlocked    = "account_unlocked"
+	AuditActionSettingsChanged    = "settings_changed"
+	AuditActionKYCApproved        = "kyc_approved"
+	AuditActionKYCRejected        = "kyc_rejected"
+	AuditActionRiskRuleUpdated    = "risk_rule_updated"
+	AuditActionAMLCaseAssigned    = "aml_case_assigned"
+	AuditActionAMLCaseEscalated   = "aml_case_escalated"
+	AuditActionAMLCaseClosed      = "aml_case_closed"
+	AuditActionScreeningCleared   = "screening_hit_cleared"
+	AuditActionScreeningConfirmed = "screening_hit_confirmed"
+	AuditActionMFAEnabled         = "mfa_enabled"
+	AuditActionMFADisabled        = "mfa_disabled"
+	AuditActionMFARecoveryCodes   = "mfa_recovery_codes_regenerated"
+	AuditActionMFARecoveryUsed    = "mfa_recovery_code_used"
+	AuditActionStepUpVerified     = "step_up_verified"
+	AuditActionNewDeviceLogin     = "new_device_login"
+	AuditActionDeviceRenamed      = "device_renamed"
+	AuditActionDeviceRevoked      = "device_revoked"
+	AuditActionRoleChanged        = "role_changed"
+	AuditActionAdminUserSearch    = "admin_user_search"
+	AuditActionAdminUserViewed    = "admin_user_viewed"
+	AuditActionAdminAccountViewed = "admin_account_viewed"
+	AuditActionAdminTxViewed      = "admin_transaction_viewed"
+	AuditActionAdminAuditSearch   = "admin_audit_search"
+	AuditActionDeposit            = "deposit"
+	AuditActionWithdrawal         = "withdrawal"
+	AuditActionChainVerified      = "hash_chain_verified"
+
+	// Ledger operations from cmd/debankctl
+	AuditActionSystemAccountCreated = "system_account_created"
+	AuditActionLedgerAdjustment     = "ledger_adjustment"
+	AuditActionTransactionReversed  = "transaction_reversed"
+	AuditActionAccountFrozen        = "account_frozen"
+	AuditActionAccountUnfrozen      = "account_unfrozen"
+	AuditActionStatementExported    = "statement_exported"
+
+	// Maker-checker journal entries
+	AuditActionJournalSubmitted  = "journal_entry_submitted"
+	AuditActionJournalAttachment = "journal_entry_attachment_added"
+	AuditActionJournalApproved   = "journal_entry_approved"
+	AuditActionJournalRejected   = "journal_entry_rejected"
 )
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ==============================================
+// ACCOUNT EVENT REPOSITORY
+// ==============================================
+// Reads the events recorded by the 019_account_events.sql triggers and
+// listens for new ones. The rows are written only by those triggers.
+
+type AccountEventRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAccountEventRepository(db *pgxpool.Pool) *AccountEventRepository {
+	return &AccountEventRepository{db: db}
+}
+
+// ListAfter retrieves a user's events with IDs above afterID, oldest first
+func (r *AccountEventRepository) ListAfter(ctx context.Context, userID int, afterID int64, limit int) ([]models.AccountEvent, error) {
+	query := `
+		SELECT id, user_id, account_id, type, data, created_at
+		FROM account_events
+		WHERE user_id = $1 AND id > $2
+		ORDER BY id
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query account events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.AccountEvent
+	for rows.Next() {
+		var e models.AccountEvent
+		if err := rows.Scan(&e.ID, &e.UserID, &e.AccountID, &e.Type, &e.Data, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan account event: %w", err)
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating account events: %w", err)
+	}
+
+	return events, nil
+}
+
+// DeleteBefore purges events recorded before the cutoff
+func (r *AccountEventRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM account_events WHERE created_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete account events: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// Listen holds a pool connection subscribed to the account_events channel and
+// passes every event announced on it to handle, calling ready once listening.
+// It only returns on error or when ctx ends; notifications sent while no
+// listener is connected are lost, so callers must treat a return as a gap.
+func (r *AccountEventRepository) Listen(ctx context.Context, ready func(), handle func(models.AccountEvent)) error {
+	pooled, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire listener connection: %w", err)
+	}
+	// The session keeps LISTEN active, so it is taken out of the pool and closed when done
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{models.AccountEventsChannel}.Sanitize()); err != nil {
+		return fmt.Errorf("failed to listen for account events: %w", err)
+	}
+	ready()
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("failed waiting for account events: %w", err)
+		}
+
+		var e models.AccountEvent
+		if err := json.Unmarshal([]byte(n.Payload), &e); err != nil {
+			log.Printf("[EVENTS] Ignoring malformed notification: %v", err)
+			continue
+		}
+		handle(e)
+	}
+}
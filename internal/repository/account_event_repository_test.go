@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// These tests need a migrated database with at least one user account:
+// set DATABASE_URL to run them (see internal/db/scripts/setup.sh)
+
+func accountEventTestDB(t *testing.T) (*pgxpool.Pool, int) {
+	t.Helper()
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("DATABASE_URL not set - skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, databaseURL)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	var userID int
+	err = pool.QueryRow(ctx, `SELECT user_id FROM accounts WHERE type = 'user' AND user_id IS NOT NULL ORDER BY id LIMIT 1`).Scan(&userID)
+	if err != nil {
+		t.Skipf("no user account to test with: %v", err)
+	}
+	return pool, userID
+}
+
+// A transaction that has recorded an event for a user holds back every other
+// transaction's events for that user until it commits, so a client resuming
+// after the last ID it saw can never skip an event that committed late
+func TestAccountEvents_CommitInIDOrder(t *testing.T) {
+	pool, userID := accountEventTestDB(t)
+	ctx := context.Background()
+	repo := NewAccountEventRepository(pool)
+
+	var before int64
+	require.NoError(t, pool.QueryRow(ctx, `SELECT COALESCE(max(id), 0) FROM account_events WHERE user_id = $1`, userID).Scan(&before))
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, `UPDATE accounts SET balance = balance - 1 WHERE user_id = $1 AND type = 'user'`, userID)
+		_, _ = pool.Exec(ctx, `DELETE FROM account_events WHERE user_id = $1 AND id > $2`, userID, before)
+	})
+
+	// First writer: a balance change, recorded by the trigger, left uncommitted
+	first, err := pool.Begin(ctx)
+	require.NoError(t, err)
+	defer func() { _ = first.Rollback(ctx) }()
+	_, err = first.Exec(ctx, `UPDATE accounts SET balance = balance + 1 WHERE user_id = $1 AND type = 'user'`, userID)
+	require.NoError(t, err)
+	var firstID int64
+	require.NoError(t, first.QueryRow(ctx, `SELECT max(id) FROM account_events WHERE user_id = $1`, userID).Scan(&firstID))
+
+	// Second writer: records its own event for the same user without touching the account row
+	secondID := make(chan int64, 1)
+	go func() {
+		second, err := pool.Begin(ctx)
+		if err != nil {
+			secondID <- 0
+			return
+		}
+		defer func() { _ = second.Rollback(ctx) }()
+
+		var id int64
+		err = second.QueryRow(ctx, `
+			WITH locked AS (SELECT lock_account_events($1))
+			INSERT INTO account_events (user_id, account_id, type, data)
+			SELECT $1, a.id, 'balance_changed', '{}'::jsonb
+			FROM accounts a, locked
+			WHERE a.user_id = $1 AND a.type = 'user'
+			RETURNING id
+		`, userID).Scan(&id)
+		if err != nil || second.Commit(ctx) != nil {
+			secondID <- 0
+			return
+		}
+		secondID <- id
+	}()
+
+	select {
+	case id := <-secondID:
+		t.Fatalf("second event %d was recorded while the first transaction was still open", id)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	require.NoError(t, first.Commit(ctx))
+
+	var id int64
+	select {
+	case id = <-secondID:
+	case <-time.After(5 * time.Second):
+		t.Fatal("second transaction never finished")
+	}
+	require.NotZero(t, id)
+	assert.Greater(t, id, firstID, "the later commit must carry the later ID")
+
+	// Resuming after the first event finds the second
+	events, err := repo.ListAfter(ctx, userID, firstID, 10)
+	require.NoError(t, err)
+	require.NotEmpty(t, events)
+	assert.Equal(t, id, events[0].ID)
+}
+
+// Both event triggers must take the lock before drawing an ID
+func TestAccountEvents_TriggersTakeOrderLock(t *testing.T) {
+	pool, _ := accountEventTestDB(t)
+
+	for _, fn := range []string{"record_transaction_posted_event", "record_balance_changed_event"} {
+		var src string
+		require.NoError(t, pool.QueryRow(context.Background(), `SELECT prosrc FROM pg_proc WHERE proname = $1`, fn).Scan(&src))
+		assert.Contains(t, src, "lock_account_events", fn)
+	}
+}
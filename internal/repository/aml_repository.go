@@ -0,0 +1,622 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ==============================================
+// ERRORS
+// ==============================================
+
+var (
+	ErrAMLCaseNotFound = errors.New("aml case not found")
+	ErrAMLCaseClosed   = errors.New("aml case is closed")
+)
+
+// ==============================================
+// AML REPOSITORY
+// ==============================================
+
+type AMLRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAMLRepository(db *pgxpool.Pool) *AMLRepository {
+	return &AMLRepository{db: db}
+}
+
+// ==============================================
+// SCAN RUNS
+// ==============================================
+
+// StartScanRun records the start of a monitoring pass
+func (r *AMLRepository) StartScanRun(ctx context.Context) (*models.AMLScanRun, error) {
+	query := `INSERT INTO aml_scan_runs DEFAULT VALUES RETURNING id, started_at`
+
+	run := &models.AMLScanRun{}
+	if err := r.db.QueryRow(ctx, query).Scan(&run.ID, &run.StartedAt); err != nil {
+		return nil, fmt.Errorf("failed to start aml scan run: %w", err)
+	}
+
+	return run, nil
+}
+
+// FinishScanRun stores the outcome of a monitoring pass
+func (r *AMLRepository) FinishScanRun(ctx context.Context, run *models.AMLScanRun) error {
+	query := `
+		UPDATE aml_scan_runs
+		SET finished_at = now(), cases_opened = $2, cases_updated = $3, error = $4
+		WHERE id = $1
+		RETURNING finished_at
+	`
+
+	err := r.db.QueryRow(ctx, query, run.ID, run.CasesOpened, run.CasesUpdated, run.Error).Scan(&run.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("failed to finish aml scan run: %w", err)
+	}
+
+	return nil
+}
+
+// ==============================================
+// DETECTION
+// ==============================================
+// Each query returns one row per user account matching the pattern,
+// with the metrics that triggered it and the transactions involved
+
+// DetectStructuring finds accounts with at least minCount deposits in [lower, threshold) since the given time
+func (r *AMLRepository) DetectStructuring(ctx context.Context, since time.Time, lower, threshold int64, minCount int) ([]models.AMLDetection, error) {
+	query := `
+		SELECT a.id, a.user_id, COUNT(*), SUM(p.amount),
+		       array_agg(p.transaction_id ORDER BY p.transaction_id)
+		FROM postings p
+		JOIN transactions t ON t.id = p.transaction_id
+		JOIN accounts a ON a.id = p.account_id
+		WHERE a.type = 'user'
+			AND t.kind = 'deposit'
+			AND t.status = 'posted'
+			AND p.amount >= $2 AND p.amount < $3
+			AND p.created_at >= $1
+		GROUP BY a.id, a.user_id
+		HAVING COUNT(*) >= $4
+	`
+
+	return r.detect(ctx, models.AMLTypologyStructuring, func(row pgx.Rows, d *models.AMLDetection) error {
+		var count, total int64
+		if err := row.Scan(&d.AccountID, &d.UserID, &count, &total, &d.TransactionIDs); err != nil {
+			return err
+		}
+		d.Details = map[string]any{"deposit_count": count, "total_amount": total, "threshold": threshold}
+		return nil
+	}, query, since, lower, threshold, minCount)
+}
+
+// DetectRapidInOut finds accounts that received at least minInflow and sent on at least outflowPercent of it since the given time
+func (r *AMLRepository) DetectRapidInOut(ctx context.Context, since time.Time, minInflow int64, outflowPercent int) ([]models.AMLDetection, error) {
+	query := `
+		WITH flows AS (
+			SELECT p.account_id,
+			       COALESCE(SUM(p.amount) FILTER (WHERE p.amount > 0), 0) AS inflow,
+			       COALESCE(SUM(-p.amount) FILTER (WHERE p.amount < 0), 0) AS outflow,
+			       array_agg(p.transaction_id ORDER BY p.transaction_id) AS txn_ids
+			FROM postings p
+			JOIN transactions t ON t.id = p.transaction_id
+			WHERE t.status = 'posted' AND p.created_at >= $1
+			GROUP BY p.account_id
+		)
+		SELECT a.id, a.user_id, f.inflow, f.outflow, f.txn_ids
+		FROM flows f
+		JOIN accounts a ON a.id = f.account_id
+		WHERE a.type = 'user'
+			AND f.inflow >= $2
+			AND f.outflow * 100 >= f.inflow * $3
+	`
+
+	return r.detect(ctx, models.AMLTypologyRapidInOut, func(row pgx.Rows, d *models.AMLDetection) error {
+		var inflow, outflow int64
+		if err := row.Scan(&d.AccountID, &d.UserID, &inflow, &outflow, &d.TransactionIDs); err != nil {
+			return err
+		}
+		d.Details = map[string]any{"inflow": inflow, "outflow": outflow}
+		return nil
+	}, query, since, minInflow, outflowPercent)
+}
+
+// DetectFanIn finds accounts paid by at least minCounterparties distinct senders since the given time
+func (r *AMLRepository) DetectFanIn(ctx context.Context, since time.Time, minCounterparties int) ([]models.AMLDetection, error) {
+	return r.detectFan(ctx, models.AMLTypologyFanIn, "to_account_id", "from_account_id", since, minCounterparties)
+}
+
+// DetectFanOut finds accounts paying at least minCounterparties distinct recipients since the given time
+func (r *AMLRepository) DetectFanOut(ctx context.Context, since time.Time, minCounterparties int) ([]models.AMLDetection, error) {
+	return r.detectFan(ctx, models.AMLTypologyFanOut, "from_account_id", "to_account_id", since, minCounterparties)
+}
+
+// detectFan groups p2p transfers by one side and counts distinct accounts on the other
+// Column names are fixed by the callers above, never user input
+func (r *AMLRepository) detectFan(ctx context.Context, typology, subjectCol, counterpartyCol string, since time.Time, minCounterparties int) ([]models.AMLDetection, error) {
+	query := `
+		SELECT a.id, a.user_id, COUNT(DISTINCT t.` + counterpartyCol + `), SUM(t.amount),
+		       array_agg(t.id ORDER BY t.id)
+		FROM transactions t
+		JOIN accounts a ON a.id = t.` + subjectCol + `
+		WHERE a.type = 'user'
+			AND t.kind = 'p2p'
+			AND t.status = 'posted'
+			AND t.created_at >= $1
+		GROUP BY a.id, a.user_id
+		HAVING COUNT(DISTINCT t.` + counterpartyCol + `) >= $2
+	`
+
+	return r.detect(ctx, typology, func(row pgx.Rows, d *models.AMLDetection) error {
+		var counterparties, total int64
+		if err := row.Scan(&d.AccountID, &d.UserID, &counterparties, &total, &d.TransactionIDs); err != nil {
+			return err
+		}
+		d.Details = map[string]any{"counterparties": counterparties, "total_amount": total}
+		return nil
+	}, query, since, minCounterparties)
+}
+
+// DetectDormantReactivation finds accounts with at least minVolume of activity since the given time
+// after no postings for dormantDays (accounts younger than that are never dormant)
+func (r *AMLRepository) DetectDormantReactivation(ctx context.Context, since time.Time, dormantDays int, minVolume int64) ([]models.AMLDetection, error) {
+	query := `
+		WITH recent AS (
+			SELECT p.account_id,
+			       SUM(ABS(p.amount)) AS volume,
+			       MIN(p.created_at) AS first_at,
+			       array_agg(p.transaction_id ORDER BY p.transaction_id) AS txn_ids
+			FROM postings p
+			JOIN transactions t ON t.id = p.transaction_id
+			WHERE t.status = 'posted' AND p.created_at >= $1
+			GROUP BY p.account_id
+		)
+		SELECT a.id, a.user_id, r.volume, prev.last_at, r.txn_ids
+		FROM recent r
+		JOIN accounts a ON a.id = r.account_id
+		LEFT JOIN LATERAL (
+			SELECT MAX(p2.created_at) AS last_at
+			FROM postings p2
+			WHERE p2.account_id = r.account_id AND p2.created_at < r.first_at
+		) prev ON TRUE
+		WHERE a.type = 'user'
+			AND r.volume >= $3
+			AND a.created_at < r.first_at - ($2 * INTERVAL '1 day')
+			AND (prev.last_at IS NULL OR prev.last_at < r.first_at - ($2 * INTERVAL '1 day'))
+	`
+
+	return r.detect(ctx, models.AMLTypologyDormantReactivation, func(row pgx.Rows, d *models.AMLDetection) error {
+		var volume int64
+		var lastActive pgtype.Timestamptz
+		if err := row.Scan(&d.AccountID, &d.UserID, &volume, &lastActive, &d.TransactionIDs); err != nil {
+			return err
+		}
+		d.Details = map[string]any{"volume": volume, "dormant_days": dormantDays}
+		if lastActive.Valid {
+			d.Details["last_active_at"] = lastActive.Time.Format(time.RFC3339)
+		}
+		return nil
+	}, query, since, dormantDays, minVolume)
+}
+
+func (r *AMLRepository) detect(
+	ctx context.Context,
+	typology string,
+	scan func(pgx.Rows, *models.AMLDetection) error,
+	query string,
+	args ...any,
+) ([]models.AMLDetection, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run %s detection: %w", typology, err)
+	}
+	defer rows.Close()
+
+	var detections []models.AMLDetection
+	for rows.Next() {
+		d := models.AMLDetection{Typology: typology}
+		if err := scan(rows, &d); err != nil {
+			return nil, fmt.Errorf("failed to scan %s detection: %w", typology, err)
+		}
+		detections = append(detections, d)
+	}
+
+	return detections, rows.Err()
+}
+
+// ==============================================
+// CASES
+// ==============================================
+
+const amlCaseColumns = `
+	id, account_id, user_id, typology, status, summary, details, scan_run_id,
+	last_detected_at, assigned_to, assigned_at, escalated_by, escalated_at,
+	account_frozen, resolution, closed_by, closed_at, created_at, updated_at
+`
+
+func scanAMLCase(row pgx.Row) (*models.AMLCase, error) {
+	var c models.AMLCase
+	err := row.Scan(
+		&c.ID,
+		&c.AccountID,
+		&c.UserID,
+		&c.Typology,
+		&c.Status,
+		&c.Summary,
+		&c.Details,
+		&c.ScanRunID,
+		&c.LastDetectedAt,
+		&c.AssignedTo,
+		&c.AssignedAt,
+		&c.EscalatedBy,
+		&c.EscalatedAt,
+		&c.AccountFrozen,
+		&c.Resolution,
+		&c.ClosedBy,
+		&c.ClosedAt,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// UpsertCase opens a case for a detection, or refreshes the live case for the same account and typology
+// Linked transactions are added to whatever the case already holds. A detection made up entirely of
+// transactions an investigator already closed out is skipped and returns a zero case ID
+func (r *AMLRepository) UpsertCase(ctx context.Context, runID int64, d *models.AMLDetection) (int64, bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	reviewedQuery := `
+		SELECT NOT EXISTS (
+			SELECT unnest($3::bigint[])
+			EXCEPT
+			SELECT ct.transaction_id
+			FROM aml_case_transactions ct
+			JOIN aml_cases c ON c.id = ct.case_id
+			WHERE c.account_id = $1 AND c.typology = $2 AND c.status = 'closed'
+		)
+	`
+	var alreadyReviewed bool
+	if err := tx.QueryRow(ctx, reviewedQuery, d.AccountID, d.Typology, d.TransactionIDs).Scan(&alreadyReviewed); err != nil {
+		return 0, false, fmt.Errorf("failed to check reviewed transactions: %w", err)
+	}
+	if alreadyReviewed {
+		return 0, false, nil
+	}
+
+	details, err := json.Marshal(d.Details)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to encode case details: %w", err)
+	}
+
+	query := `
+		INSERT INTO aml_cases (account_id, user_id, typology, summary, details, scan_run_id)
+		VALUES ($1, $2, $3, $4, $5::text::jsonb, $6)
+		ON CONFLICT (account_id, typology) WHERE status <> 'closed'
+		DO UPDATE SET summary = EXCLUDED.summary,
+		              details = EXCLUDED.details,
+		              scan_run_id = EXCLUDED.scan_run_id,
+		              last_detected_at = now()
+		RETURNING id, (xmax = 0)
+	`
+
+	var caseID int64
+	var created bool
+	err = tx.QueryRow(ctx, query, d.AccountID, d.UserID, d.Typology, d.Summary, string(details), runID).Scan(&caseID, &created)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to upsert aml case: %w", err)
+	}
+
+	linkQuery := `
+		INSERT INTO aml_case_transactions (case_id, transaction_id)
+		SELECT $1, unnest($2::bigint[])
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.Exec(ctx, linkQuery, caseID, d.TransactionIDs); err != nil {
+		return 0, false, fmt.Errorf("failed to link aml case transactions: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, false, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return caseID, created, nil
+}
+
+// GetCase returns a single case by ID
+func (r *AMLRepository) GetCase(ctx context.Context, id int64) (*models.AMLCase, error) {
+	query := `SELECT ` + amlCaseColumns + ` FROM aml_cases WHERE id = $1`
+
+	c, err := scanAMLCase(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAMLCaseNotFound
+		}
+		return nil, fmt.Errorf("failed to get aml case: %w", err)
+	}
+
+	return c, nil
+}
+
+// ListCases returns cases newest first; empty filters match everything and assignedTo 0 means any
+func (r *AMLRepository) ListCases(ctx context.Context, status, typology string, assignedTo int, limit, offset int) ([]models.AMLCase, error) {
+	query := `
+		SELECT ` + amlCaseColumns + `
+		FROM aml_cases
+		WHERE ($1 = '' OR status = $1)
+			AND ($2 = '' OR typology = $2)
+			AND ($3 = 0 OR assigned_to = $3)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4 OFFSET $5
+	`
+
+	rows, err := r.db.Query(ctx, query, status, typology, assignedTo, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aml cases: %w", err)
+	}
+	defer rows.Close()
+
+	var cases []models.AMLCase
+	for rows.Next() {
+		c, err := scanAMLCase(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan aml case: %w", err)
+		}
+		cases = append(cases, *c)
+	}
+
+	return cases, rows.Err()
+}
+
+// ListCaseTransactions returns the transactions linked to a case, oldest first
+func (r *AMLRepository) ListCaseTransactions(ctx context.Context, caseID int64) ([]models.Transaction, error) {
+	query := `
+		SELECT t.id, t.reference, t.kind, t.status, t.amount, t.currency,
+		       t.from_account_id, t.to_account_id, t.created_at
+		FROM aml_case_transactions ct
+		JOIN transactions t ON t.id = ct.transaction_id
+		WHERE ct.case_id = $1
+		ORDER BY t.created_at ASC, t.id ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, caseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aml case transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var txns []models.Transaction
+	for rows.Next() {
+		var t models.Transaction
+		if err := rows.Scan(
+			&t.ID,
+			&t.Reference,
+			&t.Kind,
+			&t.Status,
+			&t.Amount,
+			&t.Currency,
+			&t.FromAccountID,
+			&t.ToAccountID,
+			&t.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan aml case transaction: %w", err)
+		}
+		txns = append(txns, t)
+	}
+
+	return txns, rows.Err()
+}
+
+// ==============================================
+// NOTES
+// ==============================================
+
+// AddNote appends an investigator note to a case that is still live
+func (r *AMLRepository) AddNote(ctx context.Context, note *models.AMLCaseNote) error {
+	query := `
+		INSERT INTO aml_case_notes (case_id, author_id, body)
+		SELECT id, $2, $3 FROM aml_cases WHERE id = $1 AND status <> 'closed'
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(ctx, query, note.CaseID, note.AuthorID, note.Body).Scan(&note.ID, &note.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return r.missingOrClosed(ctx, note.CaseID)
+		}
+		return fmt.Errorf("failed to add aml case note: %w", err)
+	}
+
+	return nil
+}
+
+// ListNotes returns a case's notes, oldest first
+func (r *AMLRepository) ListNotes(ctx context.Context, caseID int64) ([]models.AMLCaseNote, error) {
+	query := `
+		SELECT id, case_id, author_id, body, created_at
+		FROM aml_case_notes
+		WHERE case_id = $1
+		ORDER BY created_at ASC, id ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, caseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aml case notes: %w", err)
+	}
+	defer rows.Close()
+
+	var notes []models.AMLCaseNote
+	for rows.Next() {
+		var n models.AMLCaseNote
+		if err := rows.Scan(&n.ID, &n.CaseID, &n.AuthorID, &n.Body, &n.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan aml case note: %w", err)
+		}
+		notes = append(notes, n)
+	}
+
+	return notes, rows.Err()
+}
+
+// ==============================================
+// CASE ACTIONS
+// ==============================================
+// Each action locks the case, refuses closed cases, and writes its
+// note and audit log entry in the same transaction
+
+// AssignCase hands a live case to an investigator
+func (r *AMLRepository) AssignCase(ctx context.Context, id int64, assigneeID int, audit *models.AuditLog) (*models.AMLCase, error) {
+	return r.act(ctx, id, nil, audit, func(tx pgx.Tx, c *models.AMLCase) (*models.AMLCase, error) {
+		query := `
+			UPDATE aml_cases
+			SET assigned_to = $2, assigned_at = now()
+			WHERE id = $1
+			RETURNING ` + amlCaseColumns
+
+		return scanAMLCase(tx.QueryRow(ctx, query, id, assigneeID))
+	})
+}
+
+// EscalateCase marks a case as escalated and optionally freezes the account
+func (r *AMLRepository) EscalateCase(ctx context.Context, id int64, actorID int, freeze bool, note *models.AMLCaseNote, audit *models.AuditLog) (*models.AMLCase, error) {
+	return r.act(ctx, id, note, audit, func(tx pgx.Tx, c *models.AMLCase) (*models.AMLCase, error) {
+		if freeze && !c.AccountFrozen {
+			freezeQuery := `
+				UPDATE accounts
+				SET frozen_at = COALESCE(frozen_at, now()),
+				    frozen_reason = COALESCE(frozen_reason, $2)
+				WHERE id = $1
+			`
+			reason := fmt.Sprintf("AML case %d", c.ID)
+			if _, err := tx.Exec(ctx, freezeQuery, c.AccountID, reason); err != nil {
+				return nil, fmt.Errorf("failed to freeze account: %w", err)
+			}
+		}
+
+		query := `
+			UPDATE aml_cases
+			SET status = 'escalated', escalated_by = $2, escalated_at = now(),
+			    account_frozen = account_frozen OR $3
+			WHERE id = $1
+			RETURNING ` + amlCaseColumns
+
+		return scanAMLCase(tx.QueryRow(ctx, query, id, actorID, freeze))
+	})
+}
+
+// CloseCase resolves a case. If unfreeze is set and this case froze the account,
+// the account is released unless another live case also froze it
+func (r *AMLRepository) CloseCase(ctx context.Context, id int64, actorID int, resolution string, unfreeze bool, note *models.AMLCaseNote, audit *models.AuditLog) (*models.AMLCase, error) {
+	return r.act(ctx, id, note, audit, func(tx pgx.Tx, c *models.AMLCase) (*models.AMLCase, error) {
+		if unfreeze && c.AccountFrozen {
+			unfreezeQuery := `
+				UPDATE accounts
+				SET frozen_at = NULL, frozen_reason = NULL
+				WHERE id = $1
+					AND NOT EXISTS (
+						SELECT 1 FROM aml_cases
+						WHERE account_id = $1 AND id <> $2
+							AND status <> 'closed' AND account_frozen
+					)
+					AND NOT EXISTS (
+						SELECT 1 FROM screening_hits
+						WHERE subject_type = 'signup' AND subject_id = accounts.user_id
+							AND account_frozen AND status <> 'cleared'
+					)
+			`
+			if _, err := tx.Exec(ctx, unfreezeQuery, c.AccountID, c.ID); err != nil {
+				return nil, fmt.Errorf("failed to unfreeze account: %w", err)
+			}
+		}
+
+		query := `
+			UPDATE aml_cases
+			SET status = 'closed', resolution = $2, closed_by = $3, closed_at = now(),
+			    account_frozen = account_frozen AND NOT $4
+			WHERE id = $1
+			RETURNING ` + amlCaseColumns
+
+		return scanAMLCase(tx.QueryRow(ctx, query, id, resolution, actorID, unfreeze))
+	})
+}
+
+func (r *AMLRepository) act(
+	ctx context.Context,
+	id int64,
+	note *models.AMLCaseNote,
+	audit *models.AuditLog,
+	apply func(pgx.Tx, *models.AMLCase) (*models.AMLCase, error),
+) (*models.AMLCase, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	query := `SELECT ` + amlCaseColumns + ` FROM aml_cases WHERE id = $1 FOR UPDATE`
+	current, err := scanAMLCase(tx.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAMLCaseNotFound
+		}
+		return nil, fmt.Errorf("failed to lock aml case: %w", err)
+	}
+	if current.IsClosed() {
+		return nil, ErrAMLCaseClosed
+	}
+
+	updated, err := apply(tx, current)
+	if err != nil {
+		return nil, err
+	}
+
+	if note != nil {
+		noteQuery := `
+			INSERT INTO aml_case_notes (case_id, author_id, body)
+			VALUES ($1, $2, $3)
+			RETURNING id, created_at
+		`
+		if err := tx.QueryRow(ctx, noteQuery, id, note.AuthorID, note.Body).Scan(&note.ID, &note.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to add aml case note: %w", err)
+		}
+		note.CaseID = id
+	}
+
+	audit.EntityID = pgtype.Int8{Int64: id, Valid: true}
+	if err := insertAuditLog(ctx, tx, audit); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return updated, nil
+}
+
+func (r *AMLRepository) missingOrClosed(ctx context.Context, id int64) error {
+	if _, err := r.GetCase(ctx, id); err != nil {
+		return err
+	}
+	return ErrAMLCaseClosed
+}
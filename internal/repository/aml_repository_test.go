@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// These tests need a migrated database with a user account and at least
+// three transactions: set DATABASE_URL to run them (see internal/db/scripts/setup.sh)
+
+type amlFixture struct {
+	pool      *pgxpool.Pool
+	repo      *AMLRepository
+	accountID int64
+	userID    pgtype.Int4
+	txnIDs    []int64
+	runID     int64
+}
+
+func amlTestDB(t *testing.T) *amlFixture {
+	t.Helper()
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("DATABASE_URL not set - skipping integration tests")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, databaseURL)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	f := &amlFixture{pool: pool, repo: NewAMLRepository(pool)}
+	err = pool.QueryRow(ctx, `SELECT id, user_id FROM accounts WHERE type = 'user' AND user_id IS NOT NULL ORDER BY id LIMIT 1`).Scan(&f.accountID, &f.userID)
+	if err != nil {
+		t.Skipf("no user account to test with: %v", err)
+	}
+
+	var live bool
+	require.NoError(t, pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM aml_cases WHERE account_id = $1 AND typology = $2)`,
+		f.accountID, models.AMLTypologyFanIn).Scan(&live))
+	if live {
+		t.Skip("test account already has fan-in cases")
+	}
+
+	require.NoError(t, pool.QueryRow(ctx, `SELECT COALESCE(array_agg(id ORDER BY id), '{}') FROM (SELECT id FROM transactions ORDER BY id LIMIT 3) t`).Scan(&f.txnIDs))
+	if len(f.txnIDs) < 3 {
+		t.Skip("need at least three transactions")
+	}
+
+	run, err := f.repo.StartScanRun(ctx)
+	require.NoError(t, err)
+	f.runID = run.ID
+
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, `DELETE FROM aml_cases WHERE account_id = $1 AND typology = $2`, f.accountID, models.AMLTypologyFanIn)
+		_, _ = pool.Exec(ctx, `DELETE FROM aml_scan_runs WHERE id = $1`, f.runID)
+	})
+	return f
+}
+
+func (f *amlFixture) detection(txnIDs ...int64) *models.AMLDetection {
+	return &models.AMLDetection{
+		AccountID:      f.accountID,
+		UserID:         f.userID,
+		Typology:       models.AMLTypologyFanIn,
+		Summary:        "Paid by 10 different accounts",
+		Details:        map[string]any{"counterparties": len(txnIDs)},
+		TransactionIDs: txnIDs,
+	}
+}
+
+func (f *amlFixture) linkedTransactions(t *testing.T, caseID int64) []int64 {
+	t.Helper()
+	var ids []int64
+	require.NoError(t, f.pool.QueryRow(context.Background(),
+		`SELECT COALESCE(array_agg(transaction_id ORDER BY transaction_id), '{}') FROM aml_case_transactions WHERE case_id = $1`, caseID).Scan(&ids))
+	return ids
+}
+
+// Re-detecting the same account and typology updates the open case and adds the new transactions to it
+func TestUpsertCase_UpdatesOpenCase(t *testing.T) {
+	f := amlTestDB(t)
+	ctx := context.Background()
+
+	caseID, created, err := f.repo.UpsertCase(ctx, f.runID, f.detection(f.txnIDs[0], f.txnIDs[1]))
+	require.NoError(t, err)
+	require.NotZero(t, caseID)
+	assert.True(t, created)
+
+	d := f.detection(f.txnIDs[1], f.txnIDs[2])
+	d.Summary = "Paid by 11 different accounts"
+	againID, created, err := f.repo.UpsertCase(ctx, f.runID, d)
+	require.NoError(t, err)
+	assert.Equal(t, caseID, againID)
+	assert.False(t, created)
+
+	var count int
+	require.NoError(t, f.pool.QueryRow(ctx, `SELECT COUNT(*) FROM aml_cases WHERE account_id = $1 AND typology = $2`,
+		f.accountID, models.AMLTypologyFanIn).Scan(&count))
+	assert.Equal(t, 1, count)
+
+	c, err := f.repo.GetCase(ctx, caseID)
+	require.NoError(t, err)
+	assert.Equal(t, "Paid by 11 different accounts", c.Summary)
+	assert.Equal(t, f.txnIDs, f.linkedTransactions(t, caseID))
+}
+
+// Once a case is closed, the same transactions never reopen it, but new activity opens a fresh case
+func TestUpsertCase_AfterClose(t *testing.T) {
+	f := amlTestDB(t)
+	ctx := context.Background()
+
+	caseID, _, err := f.repo.UpsertCase(ctx, f.runID, f.detection(f.txnIDs[0], f.txnIDs[1]))
+	require.NoError(t, err)
+	_, err = f.pool.Exec(ctx, `UPDATE aml_cases SET status = 'closed', resolution = 'false_positive', closed_at = now() WHERE id = $1`, caseID)
+	require.NoError(t, err)
+
+	skippedID, created, err := f.repo.UpsertCase(ctx, f.runID, f.detection(f.txnIDs[0], f.txnIDs[1]))
+	require.NoError(t, err)
+	assert.Zero(t, skippedID)
+	assert.False(t, created)
+
+	newID, created, err := f.repo.UpsertCase(ctx, f.runID, f.detection(f.txnIDs[1], f.txnIDs[2]))
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.NotEqual(t, caseID, newID)
+}
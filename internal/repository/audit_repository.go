@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ==============================================
+// AUDIT REPOSITORY
+// ==============================================
+// Changes write their audit entry in their own transaction, either inside
+// the repository (see withTx) or through InsertTx when the service owns
+// the transaction. Insert is for events that change nothing, such as a
+// failed sign-in or staff looking up a customer's records.
+
+type AuditRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAuditRepository(db *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// AuditLogFilter narrows an audit search; zero values match everything
+type AuditLogFilter struct {
+	ActorID    int // Who performed the action
+	SubjectID  int // The user acted on: the actor, or the entity when it's a user
+	Action     string
+	EntityType string
+	EntityID   int64
+	From, To   time.Time // created_at >= From and < To
+}
+
+// ==============================================
+// WRITES
+// ==============================================
+
+// Insert writes a standalone audit entry
+func (r *AuditRepository) Insert(ctx context.Context, audit *models.AuditLog) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := insertAuditLog(ctx, tx, audit); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return nil
+}
+
+// InsertTx writes an audit entry inside the caller's transaction
+func (r *AuditRepository) InsertTx(ctx context.Context, tx pgx.Tx, audit *models.AuditLog) error {
+	return insertAuditLog(ctx, tx, audit)
+}
+
+// ==============================================
+// QUERIES
+// ==============================================
+
+const auditLogColumns = `
+	id, user_id, action, entity_type, entity_id, metadata::text, ip_address, user_agent, created_at
+`
+
+func scanAuditLog(row pgx.Row) (*models.AuditLog, error) {
+	var a models.AuditLog
+	err := row.Scan(
+		&a.ID,
+		&a.UserID,
+		&a.Action,
+		&a.EntityType,
+		&a.EntityID,
+		&a.Metadata,
+		&a.IPAddress,
+		&a.UserAgent,
+		&a.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// ListUserActivity returns entries with the given actions that a user performed
+// or that were performed on their account, newest first
+func (r *AuditRepository) ListUserActivity(ctx context.Context, userID int, actions []string, limit, offset int) ([]models.AuditLog, error) {
+	query := `
+		SELECT ` + auditLogColumns + `
+		FROM audit_logs
+		WHERE (user_id = $1 OR (entity_type = 'user' AND entity_id = $1))
+			AND action = ANY($2)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	return r.list(ctx, query, userID, actions, limit, offset)
+}
+
+// Search returns entries matching the filter, newest first
+func (r *AuditRepository) Search(ctx context.Context, f AuditLogFilter, limit, offset int) ([]models.AuditLog, error) {
+	query := `
+		SELECT ` + auditLogColumns + `
+		FROM audit_logs
+		WHERE ($1 = 0 OR user_id = $1)
+			AND ($2 = 0 OR user_id = $2 OR (entity_type = 'user' AND entity_id = $2))
+			AND ($3 = '' OR action = $3)
+			AND ($4 = '' OR entity_type = $4)
+			AND ($5 = 0 OR entity_id = $5)
+			AND ($6::timestamptz IS NULL OR created_at >= $6)
+			AND ($7::timestamptz IS NULL OR created_at < $7)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $8 OFFSET $9
+	`
+
+	return r.list(ctx, query,
+		f.ActorID,
+		f.SubjectID,
+		f.Action,
+		f.EntityType,
+		f.EntityID,
+		nullTime(f.From),
+		nullTime(f.To),
+		limit,
+		offset,
+	)
+}
+
+func (r *AuditRepository) list(ctx context.Context, query string, args ...any) ([]models.AuditLog, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	var logs []models.AuditLog
+	for rows.Next() {
+		a, err := scanAuditLog(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit log: %w", err)
+		}
+		logs = append(logs, *a)
+	}
+
+	return logs, rows.Err()
+}
+
+// nullTime maps the zero time to NULL
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
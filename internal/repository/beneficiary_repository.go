@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ==============================================
+// ERRORS
+// ==============================================
+
+var (
+	ErrBeneficiaryNotFound = errors.New("beneficiary not found")
+	ErrBeneficiaryExists   = errors.New("beneficiary already saved")
+)
+
+// ==============================================
+// BENEFICIARY REPOSITORY
+// ==============================================
+
+type BeneficiaryRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBeneficiaryRepository(db *pgxpool.Pool) *BeneficiaryRepository {
+	return &BeneficiaryRepository{db: db}
+}
+
+const beneficiaryColumns = `
+	b.id, b.user_id, b.account_id, b.identifier, b.nickname, b.status, b.created_at, b.updated_at,
+	COALESCE(a.account_number, ''), a.name
+`
+
+func scanBeneficiary(row pgx.Row) (*models.Beneficiary, error) {
+	var b models.Beneficiary
+	err := row.Scan(
+		&b.ID,
+		&b.UserID,
+		&b.AccountID,
+		&b.Identifier,
+		&b.Nickname,
+		&b.Status,
+		&b.CreatedAt,
+		&b.UpdatedAt,
+		&b.AccountNumber,
+		&b.AccountName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// ==============================================
+// CREATE
+// ==============================================
+
+// CreateBeneficiary saves an account to the user's address book
+func (r *BeneficiaryRepository) CreateBeneficiary(ctx context.Context, b *models.Beneficiary) error {
+	query := `
+		INSERT INTO beneficiaries (user_id, account_id, identifier, nickname)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, status, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(ctx, query, b.UserID, b.AccountID, b.Identifier, b.Nickname).
+		Scan(&b.ID, &b.Status, &b.CreatedAt, &b.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrBeneficiaryExists
+		}
+		return fmt.Errorf("failed to create beneficiary: %w", err)
+	}
+
+	return nil
+}
+
+// ==============================================
+// GET / LIST
+// ==============================================
+
+// GetBeneficiary retrieves one of the user's beneficiaries
+func (r *BeneficiaryRepository) GetBeneficiary(ctx context.Context, userID int, id int64) (*models.Beneficiary, error) {
+	query := `
+		SELECT ` + beneficiaryColumns + `
+		FROM beneficiaries b
+		JOIN accounts a ON a.id = b.account_id
+		WHERE b.id = $1 AND b.user_id = $2
+	`
+
+	b, err := scanBeneficiary(r.db.QueryRow(ctx, query, id, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrBeneficiaryNotFound
+		}
+		return nil, fmt.Errorf("failed to get beneficiary: %w", err)
+	}
+
+	return b, nil
+}
+
+// ListBeneficiaries lists the user's saved beneficiaries, nickname first then name
+func (r *BeneficiaryRepository) ListBeneficiaries(ctx context.Context, userID int) ([]models.Beneficiary, error) {
+	query := `
+		SELECT ` + beneficiaryColumns + `
+		FROM beneficiaries b
+		JOIN accounts a ON a.id = b.account_id
+		WHERE b.user_id = $1
+		ORDER BY LOWER(COALESCE(b.nickname, a.name)), b.id
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query beneficiaries: %w", err)
+	}
+	defer rows.Close()
+
+	var beneficiaries []models.Beneficiary
+	for rows.Next() {
+		b, err := scanBeneficiary(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan beneficiary: %w", err)
+		}
+		beneficiaries = append(beneficiaries, *b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating beneficiaries: %w", err)
+	}
+
+	return beneficiaries, nil
+}
+
+// ==============================================
+// DELETE
+// ==============================================
+
+// DeleteBeneficiary removes one of the user's beneficiaries
+func (r *BeneficiaryRepository) DeleteBeneficiary(ctx context.Context, userID int, id int64) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM beneficiaries WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete beneficiary: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrBeneficiaryNotFound
+	}
+
+	return nil
+}
+
+// ==============================================
+// RECIPIENTS FROM HISTORY
+// ==============================================
+
+// Recipient ordering for ListRecipients
+const (
+	RecipientOrderRecent   = "recent"
+	RecipientOrderFrequent = "frequent"
+)
+
+// ListRecipients aggregates posted P2P transfers sent by the user per recipient account
+// order is RecipientOrderRecent (last transfer first) or RecipientOrderFrequent (most transfers first)
+func (r *BeneficiaryRepository) ListRecipients(ctx context.Context, userID int, order string, limit int) ([]models.RecipientSummary, error) {
+	orderBy := "last_transfer_at DESC"
+	if order == RecipientOrderFrequent {
+		orderBy = "transfer_count DESC, last_transfer_at DESC"
+	}
+
+	query := `
+		SELECT
+			t.to_account_id,
+			COALESCE(a.account_number, ''),
+			a.name,
+			(ARRAY_AGG(COALESCE(t.to_identifier, a.account_number, '') ORDER BY t.created_at DESC))[1] AS last_identifier,
+			COUNT(*) AS transfer_count,
+			SUM(t.amount) AS total_amount,
+			MAX(t.created_at) AS last_transfer_at
+		FROM transactions t
+		JOIN accounts sender ON sender.id = t.from_account_id
+		JOIN accounts a ON a.id = t.to_account_id
+		WHERE sender.user_id = $1
+			AND t.kind = 'p2p'
+			AND t.status = 'posted'
+			AND a.type = 'user'
+		GROUP BY t.to_account_id, a.account_number, a.name
+		ORDER BY ` + orderBy + `
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recipients: %w", err)
+	}
+	defer rows.Close()
+
+	var recipients []models.RecipientSummary
+	for rows.Next() {
+		var rs models.RecipientSummary
+		if err := rows.Scan(
+			&rs.AccountID,
+			&rs.AccountNumber,
+			&rs.AccountName,
+			&rs.LastIdentifier,
+			&rs.TransferCount,
+			&rs.TotalAmount,
+			&rs.LastTransferAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan recipient: %w", err)
+		}
+		recipients = append(recipients, rs)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating recipients: %w", err)
+	}
+
+	return recipients, nil
+}
@@ -0,0 +1,316 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ==============================================
+// ERRORS
+// ==============================================
+
+var (
+	ErrDeviceNotFound  = errors.New("device not found")
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+// ==============================================
+// DEVICE REPOSITORY
+// ==============================================
+
+type DeviceRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewDeviceRepository(db *pgxpool.Pool) *DeviceRepository {
+	return &DeviceRepository{db: db}
+}
+
+const deviceColumns = `
+	id, user_id, fingerprint, name, user_agent, last_ip,
+	first_seen_at, last_seen_at, revoked_at, created_at, updated_at
+`
+
+func scanDevice(row pgx.Row, d *models.UserDevice) error {
+	return row.Scan(
+		&d.ID,
+		&d.UserID,
+		&d.Fingerprint,
+		&d.Name,
+		&d.UserAgent,
+		&d.LastIP,
+		&d.FirstSeenAt,
+		&d.LastSeenAt,
+		&d.RevokedAt,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+	)
+}
+
+// GetDeviceByFingerprint retrieves a user's device by the fingerprint the client sends
+func (r *DeviceRepository) GetDeviceByFingerprint(ctx context.Context, userID int, fingerprint string) (*models.UserDevice, error) {
+	query := `SELECT ` + deviceColumns + ` FROM user_devices WHERE user_id = $1 AND fingerprint = $2`
+
+	var d models.UserDevice
+	if err := scanDevice(r.db.QueryRow(ctx, query, userID, fingerprint), &d); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDeviceNotFound
+		}
+		return nil, fmt.Errorf("failed to get device: %w", err)
+	}
+
+	return &d, nil
+}
+
+// ListDevices returns a user's trusted devices, most recently used first, with their open session counts
+func (r *DeviceRepository) ListDevices(ctx context.Context, userID int) ([]*models.UserDevice, error) {
+	query := `
+		SELECT ` + deviceColumns + `,
+		       (SELECT COUNT(*) FROM login_sessions s
+		        WHERE s.device_id = d.id AND s.revoked_at IS NULL AND s.expires_at > now())
+		FROM user_devices d
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY last_seen_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+	defer rows.Close()
+
+	var devices []*models.UserDevice
+	for rows.Next() {
+		var d models.UserDevice
+		err := rows.Scan(
+			&d.ID,
+			&d.UserID,
+			&d.Fingerprint,
+			&d.Name,
+			&d.UserAgent,
+			&d.LastIP,
+			&d.FirstSeenAt,
+			&d.LastSeenAt,
+			&d.RevokedAt,
+			&d.CreatedAt,
+			&d.UpdatedAt,
+			&d.ActiveSessions,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan device: %w", err)
+		}
+		devices = append(devices, &d)
+	}
+
+	return devices, rows.Err()
+}
+
+// RenameDevice changes the name of one of the user's trusted devices
+func (r *DeviceRepository) RenameDevice(ctx context.Context, userID int, deviceID int64, name string, audit *models.AuditLog) (*models.UserDevice, error) {
+	var d models.UserDevice
+	err := r.withTx(ctx, audit, func(tx pgx.Tx) error {
+		query := `
+			UPDATE user_devices
+			SET name = $3
+			WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+			RETURNING ` + deviceColumns
+
+		if err := scanDevice(tx.QueryRow(ctx, query, deviceID, userID, name), &d); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrDeviceNotFound
+			}
+			return fmt.Errorf("failed to rename device: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// RevokeDevice stops trusting a device and ends every session opened from it
+// Returns how many sessions were ended
+func (r *DeviceRepository) RevokeDevice(ctx context.Context, userID int, deviceID int64, audit *models.AuditLog) (int64, error) {
+	var ended int64
+	err := r.withTx(ctx, audit, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE user_devices
+			SET revoked_at = now()
+			WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+		`, deviceID, userID)
+		if err != nil {
+			return fmt.Errorf("failed to revoke device: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrDeviceNotFound
+		}
+
+		tag, err = tx.Exec(ctx, `
+			UPDATE login_sessions
+			SET revoked_at = now()
+			WHERE device_id = $1 AND revoked_at IS NULL
+		`, deviceID)
+		if err != nil {
+			return fmt.Errorf("failed to revoke device sessions: %w", err)
+		}
+		ended = tag.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return ended, nil
+}
+
+// ==============================================
+// SESSIONS
+// ==============================================
+
+// RecordLogin opens a session, registering or refreshing the device it came from
+// device may be nil when the client sent no fingerprint; a revoked device is trusted again
+// audits are written in the same transaction, against the device when there is one
+func (r *DeviceRepository) RecordLogin(ctx context.Context, device *models.UserDevice, session *models.LoginSession, audits ...*models.AuditLog) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if device != nil {
+		// The name is only taken from the client the first time (or after a revoke); users can rename it after that
+		query := `
+			INSERT INTO user_devices (user_id, fingerprint, name, user_agent, last_ip)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (user_id, fingerprint) DO UPDATE SET
+				name = CASE WHEN user_devices.revoked_at IS NULL THEN user_devices.name ELSE EXCLUDED.name END,
+				user_agent = EXCLUDED.user_agent,
+				last_ip = EXCLUDED.last_ip,
+				last_seen_at = now(),
+				revoked_at = NULL
+			RETURNING ` + deviceColumns
+
+		err := scanDevice(tx.QueryRow(ctx, query,
+			device.UserID,
+			device.Fingerprint,
+			device.Name,
+			device.UserAgent,
+			device.LastIP,
+		), device)
+		if err != nil {
+			return fmt.Errorf("failed to register device: %w", err)
+		}
+		session.DeviceID.Int64, session.DeviceID.Valid = device.ID, true
+
+		for _, audit := range audits {
+			if audit != nil {
+				audit.EntityType = pgtype.Text{String: "device", Valid: true}
+				audit.EntityID = pgtype.Int8{Int64: device.ID, Valid: true}
+			}
+		}
+	}
+
+	query := `
+		INSERT INTO login_sessions (user_id, token, device_id, device_info, ip_address, user_agent, expires_at)
+		VALUES ($1, $2, $3, $4::text::jsonb, $5, $6, $7)
+		RETURNING id, created_at
+	`
+
+	err = tx.QueryRow(ctx, query,
+		session.UserID,
+		session.Token,
+		session.DeviceID,
+		session.DeviceInfo,
+		session.IPAddress,
+		session.UserAgent,
+		session.ExpiresAt,
+	).Scan(&session.ID, &session.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	for _, audit := range audits {
+		if audit == nil {
+			continue
+		}
+		if err := insertAuditLog(ctx, tx, audit); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return nil
+}
+
+// GetSessionByToken retrieves a session by the hash of its session ID
+func (r *DeviceRepository) GetSessionByToken(ctx context.Context, tokenHash string) (*models.LoginSession, error) {
+	query := `
+		SELECT id, user_id, token, device_id, device_info::text, ip_address, user_agent,
+		       expires_at, revoked_at, created_at
+		FROM login_sessions
+		WHERE token = $1
+	`
+
+	var s models.LoginSession
+	err := r.db.QueryRow(ctx, query, tokenHash).Scan(
+		&s.ID,
+		&s.UserID,
+		&s.Token,
+		&s.DeviceID,
+		&s.DeviceInfo,
+		&s.IPAddress,
+		&s.UserAgent,
+		&s.ExpiresAt,
+		&s.RevokedAt,
+		&s.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return &s, nil
+}
+
+// ==============================================
+// HELPERS
+// ==============================================
+
+func (r *DeviceRepository) withTx(ctx context.Context, audit *models.AuditLog, fn func(pgx.Tx) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := insertAuditLog(ctx, tx, audit); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return nil
+}
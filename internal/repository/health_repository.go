@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ==============================================
+// HEALTH REPOSITORY
+// ==============================================
+// Backs the readiness checks: the applied schema version and the
+// heartbeats cmd/worker leaves after every job run.
+
+type HealthRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewHealthRepository(db *pgxpool.Pool) *HealthRepository {
+	return &HealthRepository{db: db}
+}
+
+// SchemaVersion returns the highest migration recorded in schema_migrations
+func (r *HealthRepository) SchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	err := r.db.QueryRow(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// RecordWorkerRun upserts a job's heartbeat; runErr is the run's outcome
+func (r *HealthRepository) RecordWorkerRun(ctx context.Context, job string, interval time.Duration, runErr error) error {
+	var lastError *string
+	if runErr != nil {
+		msg := runErr.Error()
+		lastError = &msg
+	}
+
+	query := `
+		INSERT INTO worker_heartbeats (job, interval_seconds, last_run_at, last_success_at, last_error)
+		VALUES ($1, $2, now(), CASE WHEN $3::text IS NULL THEN now() END, $3)
+		ON CONFLICT (job) DO UPDATE
+		SET interval_seconds = EXCLUDED.interval_seconds,
+		    last_run_at = EXCLUDED.last_run_at,
+		    last_success_at = COALESCE(EXCLUDED.last_success_at, worker_heartbeats.last_success_at),
+		    last_error = EXCLUDED.last_error,
+		    updated_at = now()
+	`
+
+	seconds := int64(interval / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	if _, err := r.db.Exec(ctx, query, job, seconds, lastError); err != nil {
+		return fmt.Errorf("failed to record worker heartbeat: %w", err)
+	}
+	return nil
+}
+
+// ListWorkerHeartbeats returns every job's latest heartbeat
+func (r *HealthRepository) ListWorkerHeartbeats(ctx context.Context) ([]models.WorkerHeartbeat, error) {
+	query := `
+		SELECT job, interval_seconds, last_run_at, last_success_at, last_error, updated_at
+		FROM worker_heartbeats
+		ORDER BY job
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worker heartbeats: %w", err)
+	}
+	defer rows.Close()
+
+	var heartbeats []models.WorkerHeartbeat
+	for rows.Next() {
+		var h models.WorkerHeartbeat
+		err := rows.Scan(
+			&h.Job,
+			&h.IntervalSeconds,
+			&h.LastRunAt,
+			&h.LastSuccessAt,
+			&h.LastError,
+			&h.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan worker heartbeat: %w", err)
+		}
+		heartbeats = append(heartbeats, h)
+	}
+
+	return heartbeats, rows.Err()
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ==============================================
+// IDEMPOTENCY REPOSITORY
+// ==============================================
+// Claims Idempotency-Key headers for the idempotency middleware and stores
+// the responses it replays. See 018_idempotency_keys.sql for the lifecycle.
+
+// claimAttempts bounds retries when a key is released between the claim and the lookup
+const claimAttempts = 3
+
+type IdempotencyRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewIdempotencyRepository(db *pgxpool.Pool) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+const idempotencyKeyColumns = `
+	id, user_id, endpoint, key, fingerprint, status, locked_until,
+	response_status, response_content_type, response_body,
+	created_at, completed_at, expires_at
+`
+
+func scanIdempotencyKey(row pgx.Row) (*models.IdempotencyKey, error) {
+	var k models.IdempotencyKey
+	err := row.Scan(
+		&k.ID,
+		&k.UserID,
+		&k.Endpoint,
+		&k.Key,
+		&k.Fingerprint,
+		&k.Status,
+		&k.LockedUntil,
+		&k.ResponseStatus,
+		&k.ResponseContentType,
+		&k.ResponseBody,
+		&k.CreatedAt,
+		&k.CompletedAt,
+		&k.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// Claim takes a key for a new attempt, holding it for lease and keeping the response for ttl
+// When the key is already held, it returns the existing record and false. An expired record,
+// or an identical request whose attempt outlived its lease, is taken over.
+func (r *IdempotencyRepository) Claim(ctx context.Context, userID int, endpoint, key string, fingerprint []byte, lease, ttl time.Duration) (*models.IdempotencyKey, bool, error) {
+	claim := `
+		INSERT INTO idempotency_keys (user_id, endpoint, key, fingerprint, locked_until, expires_at)
+		VALUES ($1, $2, $3, $4, now() + make_interval(secs => $5), now() + make_interval(secs => $6))
+		ON CONFLICT (user_id, endpoint, key) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint,
+		    status = 'in_progress',
+		    locked_until = EXCLUDED.locked_until,
+		    response_status = NULL,
+		    response_content_type = NULL,
+		    response_body = NULL,
+		    created_at = now(),
+		    completed_at = NULL,
+		    expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at < now()
+		   OR (idempotency_keys.status = 'in_progress'
+		       AND idempotency_keys.locked_until < now()
+		       AND idempotency_keys.fingerprint = EXCLUDED.fingerprint)
+		RETURNING ` + idempotencyKeyColumns
+
+	existing := `
+		SELECT ` + idempotencyKeyColumns + `
+		FROM idempotency_keys
+		WHERE user_id = $1 AND endpoint = $2 AND key = $3
+	`
+
+	for attempt := 0; attempt < claimAttempts; attempt++ {
+		k, err := scanIdempotencyKey(r.db.QueryRow(ctx, claim,
+			userID, endpoint, key, fingerprint, lease.Seconds(), ttl.Seconds()))
+		if err == nil {
+			return k, true, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, fmt.Errorf("failed to claim idempotency key: %w", err)
+		}
+
+		// Held by another attempt (or already completed)
+		k, err = scanIdempotencyKey(r.db.QueryRow(ctx, existing, userID, endpoint, key))
+		if err == nil {
+			return k, false, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, fmt.Errorf("failed to get idempotency key: %w", err)
+		}
+		// Released in between; try to claim it again
+	}
+
+	return nil, false, errors.New("failed to claim idempotency key: contended")
+}
+
+// Complete stores the response of a claimed attempt for replay
+// Claims are matched on their lease too, so an attempt whose key was taken over changes nothing
+func (r *IdempotencyRepository) Complete(ctx context.Context, k *models.IdempotencyKey, status int, contentType string, body []byte) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status = 'completed',
+		    response_status = $2,
+		    response_content_type = $3,
+		    response_body = $4,
+		    completed_at = now()
+		WHERE id = $1 AND locked_until = $5 AND status = 'in_progress'
+	`
+
+	if _, err := r.db.Exec(ctx, query, k.ID, status, contentType, body, k.LockedUntil); err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Release frees a claimed key whose attempt didn't succeed, so it can be retried
+func (r *IdempotencyRepository) Release(ctx context.Context, k *models.IdempotencyKey) error {
+	query := `DELETE FROM idempotency_keys WHERE id = $1 AND locked_until = $2 AND status = 'in_progress'`
+
+	if _, err := r.db.Exec(ctx, query, k.ID, k.LockedUntil); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired purges keys whose replay window has passed
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at < now()`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,348 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/pkg/hashchain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ==============================================
+// INTEGRITY REPOSITORY
+// ==============================================
+// transactions and audit_logs rows are linked into a hash chain by
+// CreateTransaction and insertAuditLog: they lock the chain head, insert the
+// row, then seal it with the head's hash. This repository reads the chains
+// back for verification and stores signed checkpoints of their heads.
+
+var ErrCheckpointNotFound = errors.New("checkpoint not found")
+
+// chainWalkBatch is how many rows WalkChain reads per query
+const chainWalkBatch = 1000
+
+type IntegrityRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewIntegrityRepository(db *pgxpool.Pool) *IntegrityRepository {
+	return &IntegrityRepository{db: db}
+}
+
+// ChainLink is one chained row as the verifier sees it
+type ChainLink struct {
+	ID       int64
+	PrevHash []byte
+	RowHash  []byte
+	Fields   []hashchain.Field
+}
+
+// ==============================================
+// LINKING
+// ==============================================
+
+// lockChainHead locks a chain's head until tx ends and returns the hash the next row links to
+// Take it before inserting the row, so ids are allocated in chain order
+func lockChainHead(ctx context.Context, tx pgx.Tx, chain string) ([]byte, error) {
+	var prev []byte
+	err := tx.QueryRow(ctx, `SELECT last_hash FROM hash_chain_heads WHERE chain = $1 FOR UPDATE`, chain).Scan(&prev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock %s hash chain: %w", chain, err)
+	}
+	return prev, nil
+}
+
+// sealChainRow stores a freshly inserted row's link and moves the head to it
+func sealChainRow(ctx context.Context, tx pgx.Tx, chain string, id int64, prev []byte, fields []hashchain.Field) ([]byte, error) {
+	if !models.IsValidChain(chain) {
+		return nil, fmt.Errorf("unknown hash chain %q", chain)
+	}
+	hash := hashchain.Link(prev, fields...)
+
+	// chain is one of the table names above, never user input
+	query := `UPDATE ` + chain + ` SET prev_hash = $2, row_hash = $3 WHERE id = $1`
+	if _, err := tx.Exec(ctx, query, id, prev, hash); err != nil {
+		return nil, fmt.Errorf("failed to seal %s row: %w", chain, err)
+	}
+
+	query = `
+		UPDATE hash_chain_heads
+		SET start_id = COALESCE(start_id, $2),
+		    last_id = $2,
+		    last_hash = $3,
+		    rows = rows + 1,
+		    updated_at = now()
+		WHERE chain = $1
+	`
+	if _, err := tx.Exec(ctx, query, chain, id, hash); err != nil {
+		return nil, fmt.Errorf("failed to advance %s hash chain: %w", chain, err)
+	}
+
+	return hash, nil
+}
+
+// transactionChainFields are the hashed columns of a transaction; lifecycle columns are left out
+// so a pending payment can settle
+func transactionChainFields(t *models.Transaction) []hashchain.Field {
+	return []hashchain.Field{
+		hashchain.Int(t.ID),
+		hashchain.String(t.IdempotencyKey),
+		hashchain.String(t.Reference),
+		hashchain.String(t.Kind),
+		hashchain.Int(t.Amount),
+		hashchain.String(t.Currency),
+		hashchain.OptInt(t.FromAccountID.Int64, t.FromAccountID.Valid),
+		hashchain.OptInt(t.ToAccountID.Int64, t.ToAccountID.Valid),
+		hashchain.OptString(t.FromIdentifier.String, t.FromIdentifier.Valid),
+		hashchain.OptString(t.ToIdentifier.String, t.ToIdentifier.Valid),
+		hashchain.OptString(t.Description.String, t.Description.Valid),
+		hashchain.OptString(t.Metadata.String, t.Metadata.Valid),
+		hashchain.Time(t.CreatedAt),
+	}
+}
+
+// auditLogChainFields are the hashed columns of an audit entry
+func auditLogChainFields(a *models.AuditLog) []hashchain.Field {
+	return []hashchain.Field{
+		hashchain.Int(a.ID),
+		hashchain.OptInt(int64(a.UserID.Int32), a.UserID.Valid),
+		hashchain.String(a.Action),
+		hashchain.OptString(a.EntityType.String, a.EntityType.Valid),
+		hashchain.OptInt(a.EntityID.Int64, a.EntityID.Valid),
+		hashchain.OptString(a.Metadata.String, a.Metadata.Valid),
+		hashchain.OptString(a.IPAddress.String, a.IPAddress.Valid),
+		hashchain.OptString(a.UserAgent.String, a.UserAgent.Valid),
+		hashchain.Time(a.CreatedAt),
+	}
+}
+
+// ==============================================
+// VERIFICATION QUERIES
+// ==============================================
+
+// GetHead retrieves a chain's head
+func (r *IntegrityRepository) GetHead(ctx context.Context, chain string) (*models.ChainHead, error) {
+	query := `
+		SELECT chain, start_id, last_id, last_hash, rows, updated_at
+		FROM hash_chain_heads
+		WHERE chain = $1
+	`
+
+	var h models.ChainHead
+	err := r.db.QueryRow(ctx, query, chain).Scan(
+		&h.Chain,
+		&h.StartID,
+		&h.LastID,
+		&h.LastHash,
+		&h.Rows,
+		&h.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s chain head: %w", chain, err)
+	}
+
+	return &h, nil
+}
+
+// WalkChain calls fn for each row with afterID < id <= throughID, in chain order
+func (r *IntegrityRepository) WalkChain(ctx context.Context, chain string, afterID, throughID int64, fn func(ChainLink) error) error {
+	for afterID < throughID {
+		links, err := r.chainBatch(ctx, chain, afterID, throughID)
+		if err != nil {
+			return err
+		}
+		if len(links) == 0 {
+			return nil
+		}
+
+		for _, link := range links {
+			if err := fn(link); err != nil {
+				return err
+			}
+		}
+		afterID = links[len(links)-1].ID
+	}
+	return nil
+}
+
+func (r *IntegrityRepository) chainBatch(ctx context.Context, chain string, afterID, throughID int64) ([]ChainLink, error) {
+	var query string
+	var scan func(pgx.Rows) (ChainLink, error)
+
+	switch chain {
+	case models.ChainTransactions:
+		query = `
+			SELECT id, idempotency_key, reference, kind, amount, currency,
+			       from_account_id, to_account_id, from_identifier, to_identifier,
+			       description, metadata::text, created_at, prev_hash, row_hash
+			FROM transactions
+			WHERE id > $1 AND id <= $2
+			ORDER BY id
+			LIMIT $3
+		`
+		scan = func(rows pgx.Rows) (ChainLink, error) {
+			var t models.Transaction
+			err := rows.Scan(
+				&t.ID,
+				&t.IdempotencyKey,
+				&t.Reference,
+				&t.Kind,
+				&t.Amount,
+				&t.Currency,
+				&t.FromAccountID,
+				&t.ToAccountID,
+				&t.FromIdentifier,
+				&t.ToIdentifier,
+				&t.Description,
+				&t.Metadata,
+				&t.CreatedAt,
+				&t.PrevHash,
+				&t.RowHash,
+			)
+			return ChainLink{ID: t.ID, PrevHash: t.PrevHash, RowHash: t.RowHash, Fields: transactionChainFields(&t)}, err
+		}
+	case models.ChainAuditLogs:
+		query = `
+			SELECT ` + auditLogColumns + `, prev_hash, row_hash
+			FROM audit_logs
+			WHERE id > $1 AND id <= $2
+			ORDER BY id
+			LIMIT $3
+		`
+		scan = func(rows pgx.Rows) (ChainLink, error) {
+			var a models.AuditLog
+			err := rows.Scan(
+				&a.ID,
+				&a.UserID,
+				&a.Action,
+				&a.EntityType,
+				&a.EntityID,
+				&a.Metadata,
+				&a.IPAddress,
+				&a.UserAgent,
+				&a.CreatedAt,
+				&a.PrevHash,
+				&a.RowHash,
+			)
+			return ChainLink{ID: a.ID, PrevHash: a.PrevHash, RowHash: a.RowHash, Fields: auditLogChainFields(&a)}, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown hash chain %q", chain)
+	}
+
+	rows, err := r.db.Query(ctx, query, afterID, throughID, chainWalkBatch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s chain: %w", chain, err)
+	}
+	defer rows.Close()
+
+	links := make([]ChainLink, 0, chainWalkBatch)
+	for rows.Next() {
+		link, err := scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s row: %w", chain, err)
+		}
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
+
+// ==============================================
+// CHECKPOINTS
+// ==============================================
+
+const checkpointColumns = `id, chain, last_id, last_hash, rows, key_id, signature, created_at`
+
+func scanCheckpoint(row pgx.Row) (*models.ChainCheckpoint, error) {
+	var c models.ChainCheckpoint
+	err := row.Scan(
+		&c.ID,
+		&c.Chain,
+		&c.LastID,
+		&c.LastHash,
+		&c.Rows,
+		&c.KeyID,
+		&c.Signature,
+		&c.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// CreateCheckpoint stores a signed checkpoint
+func (r *IntegrityRepository) CreateCheckpoint(ctx context.Context, c *models.ChainCheckpoint) error {
+	query := `
+		INSERT INTO chain_checkpoints (chain, last_id, last_hash, rows, key_id, signature)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		c.Chain,
+		c.LastID,
+		c.LastHash,
+		c.Rows,
+		c.KeyID,
+		c.Signature,
+	).Scan(&c.ID, &c.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatestCheckpoint retrieves a chain's newest checkpoint
+func (r *IntegrityRepository) GetLatestCheckpoint(ctx context.Context, chain string) (*models.ChainCheckpoint, error) {
+	query := `
+		SELECT ` + checkpointColumns + `
+		FROM chain_checkpoints
+		WHERE chain = $1
+		ORDER BY id DESC
+		LIMIT 1
+	`
+
+	c, err := scanCheckpoint(r.db.QueryRow(ctx, query, chain))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCheckpointNotFound
+		}
+		return nil, fmt.Errorf("failed to get checkpoint: %w", err)
+	}
+
+	return c, nil
+}
+
+// ListCheckpoints returns a chain's checkpoints, newest first
+func (r *IntegrityRepository) ListCheckpoints(ctx context.Context, chain string, limit, offset int) ([]models.ChainCheckpoint, error) {
+	query := `
+		SELECT ` + checkpointColumns + `
+		FROM chain_checkpoints
+		WHERE chain = $1
+		ORDER BY id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(ctx, query, chain, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	defer rows.Close()
+
+	var checkpoints []models.ChainCheckpoint
+	for rows.Next() {
+		c, err := scanCheckpoint(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan checkpoint: %w", err)
+		}
+		checkpoints = append(checkpoints, *c)
+	}
+
+	return checkpoints, rows.Err()
+}
@@ -0,0 +1,325 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ==============================================
+// JOURNAL REPOSITORY
+// ==============================================
+// Maker-checker journal entries. Writes that must land with ledger
+// postings or audit entries take the caller's transaction.
+
+var (
+	ErrJournalEntryNotFound      = errors.New("journal entry not found")
+	ErrJournalEntryNotPending    = errors.New("journal entry has already been reviewed")
+	ErrJournalAttachmentNotFound = errors.New("journal attachment not found")
+)
+
+type JournalRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewJournalRepository(db *pgxpool.Pool) *JournalRepository {
+	return &JournalRepository{db: db}
+}
+
+// ==============================================
+// ENTRIES
+// ==============================================
+
+const journalEntryColumns = `
+	id, reference, status, currency, reason, maker_user_id, checker_user_id,
+	review_note, reviewed_at, transaction_id, created_at, updated_at
+`
+
+func scanJournalEntry(row pgx.Row) (*models.JournalEntry, error) {
+	var e models.JournalEntry
+	err := row.Scan(
+		&e.ID,
+		&e.Reference,
+		&e.Status,
+		&e.Currency,
+		&e.Reason,
+		&e.MakerUserID,
+		&e.CheckerUserID,
+		&e.ReviewNote,
+		&e.ReviewedAt,
+		&e.TransactionID,
+		&e.CreatedAt,
+		&e.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// CreateEntry inserts a pending entry and its legs
+func (r *JournalRepository) CreateEntry(ctx context.Context, tx pgx.Tx, e *models.JournalEntry, legs []models.JournalLeg) error {
+	query := `
+		INSERT INTO journal_entries (reference, currency, reason, maker_user_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, status, created_at, updated_at
+	`
+
+	err := tx.QueryRow(ctx, query, e.Reference, e.Currency, e.Reason, e.MakerUserID).
+		Scan(&e.ID, &e.Status, &e.CreatedAt, &e.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create journal entry: %w", err)
+	}
+
+	for i := range legs {
+		legs[i].EntryID = e.ID
+		err := tx.QueryRow(ctx, `
+			INSERT INTO journal_entry_legs (entry_id, account_id, amount, memo)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`, e.ID, legs[i].AccountID, legs[i].Amount, legs[i].Memo).Scan(&legs[i].ID)
+		if err != nil {
+			return fmt.Errorf("failed to create journal leg: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetEntry retrieves an entry by ID
+func (r *JournalRepository) GetEntry(ctx context.Context, id int64) (*models.JournalEntry, error) {
+	e, err := scanJournalEntry(r.db.QueryRow(ctx, `SELECT `+journalEntryColumns+` FROM journal_entries WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrJournalEntryNotFound
+		}
+		return nil, fmt.Errorf("failed to get journal entry: %w", err)
+	}
+
+	return e, nil
+}
+
+// GetEntryForUpdate retrieves and locks an entry so only one checker decides it
+func (r *JournalRepository) GetEntryForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*models.JournalEntry, error) {
+	e, err := scanJournalEntry(tx.QueryRow(ctx, `SELECT `+journalEntryColumns+` FROM journal_entries WHERE id = $1 FOR UPDATE`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrJournalEntryNotFound
+		}
+		return nil, fmt.Errorf("failed to lock journal entry: %w", err)
+	}
+
+	return e, nil
+}
+
+// ListEntriesByStatus lists entries in a status, oldest first
+func (r *JournalRepository) ListEntriesByStatus(ctx context.Context, status string, limit, offset int) ([]models.JournalEntry, error) {
+	query := `SELECT ` + journalEntryColumns + `
+		FROM journal_entries
+		WHERE status = $1
+		ORDER BY created_at, id
+		LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.Query(ctx, query, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query journal entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.JournalEntry
+	for rows.Next() {
+		e, err := scanJournalEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan journal entry: %w", err)
+		}
+		entries = append(entries, *e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating journal entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// MarkPosted records the checker's approval and the transaction it posted
+func (r *JournalRepository) MarkPosted(ctx context.Context, tx pgx.Tx, id int64, checkerID int, note string, transactionID int64) (*models.JournalEntry, error) {
+	query := `
+		UPDATE journal_entries
+		SET status = 'posted',
+		    checker_user_id = $2,
+		    review_note = NULLIF($3, ''),
+		    reviewed_at = now(),
+		    transaction_id = $4
+		WHERE id = $1 AND status = 'pending_approval'
+		RETURNING ` + journalEntryColumns
+
+	return r.review(ctx, tx, query, id, checkerID, note, transactionID)
+}
+
+// MarkRejected records the checker's rejection; nothing is posted
+func (r *JournalRepository) MarkRejected(ctx context.Context, tx pgx.Tx, id int64, checkerID int, note string) (*models.JournalEntry, error) {
+	query := `
+		UPDATE journal_entries
+		SET status = 'rejected',
+		    checker_user_id = $2,
+		    review_note = NULLIF($3, ''),
+		    reviewed_at = now()
+		WHERE id = $1 AND status = 'pending_approval'
+		RETURNING ` + journalEntryColumns
+
+	return r.review(ctx, tx, query, id, checkerID, note)
+}
+
+func (r *JournalRepository) review(ctx context.Context, tx pgx.Tx, query string, args ...interface{}) (*models.JournalEntry, error) {
+	e, err := scanJournalEntry(tx.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrJournalEntryNotPending
+		}
+		return nil, fmt.Errorf("failed to review journal entry: %w", err)
+	}
+
+	return e, nil
+}
+
+// ==============================================
+// LEGS
+// ==============================================
+
+// ListLegs lists an entry's legs with their account numbers, in the order submitted
+func (r *JournalRepository) ListLegs(ctx context.Context, entryID int64) ([]models.JournalLeg, error) {
+	query := `
+		SELECT l.id, l.entry_id, l.account_id, a.account_number, l.amount, l.memo
+		FROM journal_entry_legs l
+		JOIN accounts a ON a.id = l.account_id
+		WHERE l.entry_id = $1
+		ORDER BY l.id
+	`
+
+	rows, err := r.db.Query(ctx, query, entryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query journal legs: %w", err)
+	}
+	defer rows.Close()
+
+	var legs []models.JournalLeg
+	for rows.Next() {
+		var l models.JournalLeg
+		if err := rows.Scan(&l.ID, &l.EntryID, &l.AccountID, &l.AccountNumber, &l.Amount, &l.Memo); err != nil {
+			return nil, fmt.Errorf("failed to scan journal leg: %w", err)
+		}
+		legs = append(legs, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating journal legs: %w", err)
+	}
+
+	return legs, nil
+}
+
+// ==============================================
+// ATTACHMENTS
+// ==============================================
+
+const journalAttachmentColumns = `
+	id, entry_id, uploaded_by, storage_key, file_name, content_type, size_bytes, sha256, created_at
+`
+
+func scanJournalAttachment(row pgx.Row) (*models.JournalAttachment, error) {
+	var a models.JournalAttachment
+	err := row.Scan(
+		&a.ID,
+		&a.EntryID,
+		&a.UploadedBy,
+		&a.StorageKey,
+		&a.FileName,
+		&a.ContentType,
+		&a.SizeBytes,
+		&a.SHA256,
+		&a.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// CreateAttachment records an uploaded attachment
+func (r *JournalRepository) CreateAttachment(ctx context.Context, tx pgx.Tx, a *models.JournalAttachment) error {
+	query := `
+		INSERT INTO journal_entry_attachments (
+			entry_id, uploaded_by, storage_key, file_name, content_type, size_bytes, sha256
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+
+	err := tx.QueryRow(ctx, query,
+		a.EntryID,
+		a.UploadedBy,
+		a.StorageKey,
+		a.FileName,
+		a.ContentType,
+		a.SizeBytes,
+		a.SHA256,
+	).Scan(&a.ID, &a.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create journal attachment: %w", err)
+	}
+
+	return nil
+}
+
+// GetAttachment retrieves an attachment by ID
+func (r *JournalRepository) GetAttachment(ctx context.Context, id int64) (*models.JournalAttachment, error) {
+	a, err := scanJournalAttachment(r.db.QueryRow(ctx, `SELECT `+journalAttachmentColumns+` FROM journal_entry_attachments WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrJournalAttachmentNotFound
+		}
+		return nil, fmt.Errorf("failed to get journal attachment: %w", err)
+	}
+
+	return a, nil
+}
+
+// ListAttachments lists the attachments on an entry
+func (r *JournalRepository) ListAttachments(ctx context.Context, entryID int64) ([]models.JournalAttachment, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+journalAttachmentColumns+` FROM journal_entry_attachments WHERE entry_id = $1 ORDER BY id`, entryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query journal attachments: %w", err)
+	}
+	defer rows.Close()
+
+	var attachments []models.JournalAttachment
+	for rows.Next() {
+		a, err := scanJournalAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan journal attachment: %w", err)
+		}
+		attachments = append(attachments, *a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating journal attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// CountAttachments counts an entry's attachments inside tx, e.g. while the entry is locked
+func (r *JournalRepository) CountAttachments(ctx context.Context, tx pgx.Tx, entryID int64) (int, error) {
+	var count int
+	err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM journal_entry_attachments WHERE entry_id = $1`, entryID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count journal attachments: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,480 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ==============================================
+// ERRORS
+// ==============================================
+
+var (
+	ErrKYCTierNotFound         = errors.New("kyc tier not found")
+	ErrKYCSubmissionNotFound   = errors.New("kyc submission not found")
+	ErrKYCSubmissionPending    = errors.New("a kyc submission is already pending")
+	ErrKYCSubmissionNotPending = errors.New("kyc submission has already been reviewed")
+	ErrKYCDocumentNotFound     = errors.New("kyc document not found")
+)
+
+// ==============================================
+// KYC REPOSITORY
+// ==============================================
+
+type KYCRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewKYCRepository(db *pgxpool.Pool) *KYCRepository {
+	return &KYCRepository{db: db}
+}
+
+const kycTierColumns = `
+	t.tier, t.name, t.requirements, t.max_single_transaction,
+	t.daily_debit_limit, t.monthly_debit_limit, t.max_balance,
+	t.created_at, t.updated_at
+`
+
+func scanKYCTier(row pgx.Row) (*models.KYCTier, error) {
+	var t models.KYCTier
+	err := row.Scan(
+		&t.Tier,
+		&t.Name,
+		&t.Requirements,
+		&t.MaxSingleTransaction,
+		&t.DailyDebitLimit,
+		&t.MonthlyDebitLimit,
+		&t.MaxBalance,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ==============================================
+// TIERS
+// ==============================================
+
+// ListTiers returns every tier, lowest first
+func (r *KYCRepository) ListTiers(ctx context.Context) ([]models.KYCTier, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+kycTierColumns+` FROM kyc_tiers t ORDER BY t.tier`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query kyc tiers: %w", err)
+	}
+	defer rows.Close()
+
+	var tiers []models.KYCTier
+	for rows.Next() {
+		t, err := scanKYCTier(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan kyc tier: %w", err)
+		}
+		tiers = append(tiers, *t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating kyc tiers: %w", err)
+	}
+
+	return tiers, nil
+}
+
+// GetTier retrieves a single tier
+func (r *KYCRepository) GetTier(ctx context.Context, tier int) (*models.KYCTier, error) {
+	t, err := scanKYCTier(r.db.QueryRow(ctx, `SELECT `+kycTierColumns+` FROM kyc_tiers t WHERE t.tier = $1`, tier))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrKYCTierNotFound
+		}
+		return nil, fmt.Errorf("failed to get kyc tier: %w", err)
+	}
+
+	return t, nil
+}
+
+// ==============================================
+// USER TIER
+// ==============================================
+
+// GetUserTier retrieves the tier (and its limits) a user is currently on
+func (r *KYCRepository) GetUserTier(ctx context.Context, userID int) (*models.KYCTier, error) {
+	query := `
+		SELECT ` + kycTierColumns + `
+		FROM users u
+		JOIN kyc_tiers t ON t.tier = u.kyc_tier
+		WHERE u.id = $1
+	`
+
+	t, err := scanKYCTier(r.db.QueryRow(ctx, query, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user kyc tier: %w", err)
+	}
+
+	return t, nil
+}
+
+// SetUserTier moves a user to a new tier
+func (r *KYCRepository) SetUserTier(ctx context.Context, userID int, tier int) error {
+	query := `
+		UPDATE users
+		SET kyc_tier = $2, kyc_tier_updated_at = now()
+		WHERE id = $1
+	`
+
+	tag, err := r.db.Exec(ctx, query, userID, tier)
+	if err != nil {
+		return fmt.Errorf("failed to set user kyc tier: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// ==============================================
+// SUBMISSIONS
+// ==============================================
+
+const kycSubmissionColumns = `
+	id, user_id, target_tier, id_type, id_number, address, status,
+	verification_status, verification_reference, verified_name,
+	reviewer_user_id, review_reason, reviewed_at, created_at, updated_at
+`
+
+func scanKYCSubmission(row pgx.Row) (*models.KYCSubmission, error) {
+	var s models.KYCSubmission
+	err := row.Scan(
+		&s.ID,
+		&s.UserID,
+		&s.TargetTier,
+		&s.IDType,
+		&s.IDNumber,
+		&s.Address,
+		&s.Status,
+		&s.VerificationStatus,
+		&s.VerificationReference,
+		&s.VerifiedName,
+		&s.ReviewerUserID,
+		&s.ReviewReason,
+		&s.ReviewedAt,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// CreateSubmission inserts a pending submission
+// Returns ErrKYCSubmissionPending if the user already has one open
+func (r *KYCRepository) CreateSubmission(ctx context.Context, s *models.KYCSubmission) error {
+	query := `
+		INSERT INTO kyc_submissions (
+			user_id, target_tier, id_type, id_number, address,
+			verification_status, verification_reference, verified_name
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, status, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		s.UserID,
+		s.TargetTier,
+		s.IDType,
+		s.IDNumber,
+		s.Address,
+		s.VerificationStatus,
+		s.VerificationReference,
+		s.VerifiedName,
+	).Scan(&s.ID, &s.Status, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrKYCSubmissionPending
+		}
+		return fmt.Errorf("failed to create kyc submission: %w", err)
+	}
+
+	return nil
+}
+
+// GetSubmission retrieves a submission by ID
+func (r *KYCRepository) GetSubmission(ctx context.Context, id int64) (*models.KYCSubmission, error) {
+	s, err := scanKYCSubmission(r.db.QueryRow(ctx, `SELECT `+kycSubmissionColumns+` FROM kyc_submissions WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrKYCSubmissionNotFound
+		}
+		return nil, fmt.Errorf("failed to get kyc submission: %w", err)
+	}
+
+	return s, nil
+}
+
+// ListUserSubmissions lists a user's submissions, newest first
+func (r *KYCRepository) ListUserSubmissions(ctx context.Context, userID int) ([]models.KYCSubmission, error) {
+	query := `
+		SELECT ` + kycSubmissionColumns + `
+		FROM kyc_submissions
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+
+	return r.listSubmissions(ctx, query, userID)
+}
+
+// ListSubmissionsByStatus is the review queue, oldest first so nothing starves
+func (r *KYCRepository) ListSubmissionsByStatus(ctx context.Context, status string, limit, offset int) ([]models.KYCSubmission, error) {
+	query := `
+		SELECT ` + kycSubmissionColumns + `
+		FROM kyc_submissions
+		WHERE status = $1
+		ORDER BY created_at ASC, id ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	return r.listSubmissions(ctx, query, status, limit, offset)
+}
+
+func (r *KYCRepository) listSubmissions(ctx context.Context, query string, args ...interface{}) ([]models.KYCSubmission, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query kyc submissions: %w", err)
+	}
+	defer rows.Close()
+
+	var submissions []models.KYCSubmission
+	for rows.Next() {
+		s, err := scanKYCSubmission(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan kyc submission: %w", err)
+		}
+		submissions = append(submissions, *s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating kyc submissions: %w", err)
+	}
+
+	return submissions, nil
+}
+
+// ==============================================
+// REVIEW
+// ==============================================
+// Review decisions, the tier change and the audit entry are written in one
+// database transaction so an approval can never be applied without a trail.
+
+// ApproveSubmission approves a pending submission and raises the user's tier
+// The tier never goes down: approving a lower target than the current tier is a no-op
+func (r *KYCRepository) ApproveSubmission(ctx context.Context, id int64, reviewerID int, reason string, audit *models.AuditLog) (*models.KYCSubmission, error) {
+	return r.review(ctx, id, models.KYCSubmissionStatusApproved, reviewerID, reason, audit, func(tx pgx.Tx, s *models.KYCSubmission) error {
+		_, err := tx.Exec(ctx, `
+			UPDATE users
+			SET kyc_tier = GREATEST(kyc_tier, $2), kyc_tier_updated_at = now()
+			WHERE id = $1
+		`, s.UserID, s.TargetTier)
+		if err != nil {
+			return fmt.Errorf("failed to raise user kyc tier: %w", err)
+		}
+		return nil
+	})
+}
+
+// RejectSubmission rejects a pending submission
+func (r *KYCRepository) RejectSubmission(ctx context.Context, id int64, reviewerID int, reason string, audit *models.AuditLog) (*models.KYCSubmission, error) {
+	return r.review(ctx, id, models.KYCSubmissionStatusRejected, reviewerID, reason, audit, nil)
+}
+
+func (r *KYCRepository) review(
+	ctx context.Context,
+	id int64,
+	status string,
+	reviewerID int,
+	reason string,
+	audit *models.AuditLog,
+	apply func(tx pgx.Tx, s *models.KYCSubmission) error,
+) (*models.KYCSubmission, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	query := `
+		UPDATE kyc_submissions
+		SET status = $2,
+		    reviewer_user_id = $3,
+		    review_reason = NULLIF($4, ''),
+		    reviewed_at = now()
+		WHERE id = $1 AND status = 'pending'
+		RETURNING ` + kycSubmissionColumns
+
+	s, err := scanKYCSubmission(tx.QueryRow(ctx, query, id, status, reviewerID, reason))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrKYCSubmissionNotPending
+		}
+		return nil, fmt.Errorf("failed to review kyc submission: %w", err)
+	}
+
+	if apply != nil {
+		if err := apply(tx, s); err != nil {
+			return nil, err
+		}
+	}
+
+	if audit != nil {
+		audit.EntityID.Int64, audit.EntityID.Valid = s.ID, true
+		if err := insertAuditLog(ctx, tx, audit); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return s, nil
+}
+
+// insertAuditLog writes an audit entry inside the caller's transaction and links it into the audit hash chain
+func insertAuditLog(ctx context.Context, tx pgx.Tx, a *models.AuditLog) error {
+	prev, err := lockChainHead(ctx, tx, models.ChainAuditLogs)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO audit_logs (user_id, action, entity_type, entity_id, metadata, ip_address, user_agent)
+		VALUES ($1, $2, $3, $4, $5::text::jsonb, $6, $7)
+		RETURNING id, metadata::text, created_at
+	`
+
+	err = tx.QueryRow(ctx, query,
+		a.UserID,
+		a.Action,
+		a.EntityType,
+		a.EntityID,
+		a.Metadata,
+		a.IPAddress,
+		a.UserAgent,
+	).Scan(&a.ID, &a.Metadata, &a.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+
+	a.PrevHash = prev
+	a.RowHash, err = sealChainRow(ctx, tx, models.ChainAuditLogs, a.ID, prev, auditLogChainFields(a))
+	return err
+}
+
+// ==============================================
+// DOCUMENTS
+// ==============================================
+
+const kycDocumentColumns = `
+	id, submission_id, user_id, document_type, storage_key,
+	file_name, content_type, size_bytes, sha256, created_at
+`
+
+func scanKYCDocument(row pgx.Row) (*models.KYCDocument, error) {
+	var d models.KYCDocument
+	err := row.Scan(
+		&d.ID,
+		&d.SubmissionID,
+		&d.UserID,
+		&d.DocumentType,
+		&d.StorageKey,
+		&d.FileName,
+		&d.ContentType,
+		&d.SizeBytes,
+		&d.SHA256,
+		&d.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// CreateDocument records an uploaded document
+func (r *KYCRepository) CreateDocument(ctx context.Context, d *models.KYCDocument) error {
+	query := `
+		INSERT INTO kyc_documents (
+			submission_id, user_id, document_type, storage_key,
+			file_name, content_type, size_bytes, sha256
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		d.SubmissionID,
+		d.UserID,
+		d.DocumentType,
+		d.StorageKey,
+		d.FileName,
+		d.ContentType,
+		d.SizeBytes,
+		d.SHA256,
+	).Scan(&d.ID, &d.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create kyc document: %w", err)
+	}
+
+	return nil
+}
+
+// GetDocument retrieves a document by ID
+func (r *KYCRepository) GetDocument(ctx context.Context, id int64) (*models.KYCDocument, error) {
+	d, err := scanKYCDocument(r.db.QueryRow(ctx, `SELECT `+kycDocumentColumns+` FROM kyc_documents WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrKYCDocumentNotFound
+		}
+		return nil, fmt.Errorf("failed to get kyc document: %w", err)
+	}
+
+	return d, nil
+}
+
+// ListDocuments lists the documents attached to a submission
+func (r *KYCRepository) ListDocuments(ctx context.Context, submissionID int64) ([]models.KYCDocument, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+kycDocumentColumns+` FROM kyc_documents WHERE submission_id = $1 ORDER BY id`, submissionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query kyc documents: %w", err)
+	}
+	defer rows.Close()
+
+	var documents []models.KYCDocument
+	for rows.Next() {
+		d, err := scanKYCDocument(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan kyc document: %w", err)
+		}
+		documents = append(documents, *d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating kyc documents: %w", err)
+	}
+
+	return documents, nil
+}
@@ -0,0 +1,343 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ==============================================
+// ERRORS
+// ==============================================
+
+var (
+	ErrMFANotFound          = errors.New("mfa not configured")
+	ErrMFAAlreadyEnabled    = errors.New("mfa already enabled")
+	ErrMFAChallengeNotFound = errors.New("mfa challenge not found")
+)
+
+// ==============================================
+// MFA REPOSITORY
+// ==============================================
+
+type MFARepository struct {
+	db *pgxpool.Pool
+}
+
+func NewMFARepository(db *pgxpool.Pool) *MFARepository {
+	return &MFARepository{db: db}
+}
+
+// ==============================================
+// ENROLLMENT
+// ==============================================
+
+// GetMFA retrieves a user's second factor, enabled or pending
+func (r *MFARepository) GetMFA(ctx context.Context, userID int) (*models.UserMFA, error) {
+	query := `
+		SELECT user_id, method, totp_secret, last_totp_step, enabled_at, created_at, updated_at
+		FROM user_mfa
+		WHERE user_id = $1
+	`
+
+	var m models.UserMFA
+	err := r.db.QueryRow(ctx, query, userID).Scan(
+		&m.UserID,
+		&m.Method,
+		&m.TOTPSecret,
+		&m.LastTOTPStep,
+		&m.EnabledAt,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrMFANotFound
+		}
+		return nil, fmt.Errorf("failed to get mfa: %w", err)
+	}
+
+	return &m, nil
+}
+
+// StartTOTPEnrollment stores a new, not yet enabled TOTP secret, replacing any earlier pending one
+func (r *MFARepository) StartTOTPEnrollment(ctx context.Context, userID int, encryptedSecret string) error {
+	query := `
+		INSERT INTO user_mfa (user_id, method, totp_secret)
+		VALUES ($1, 'totp', $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET method = 'totp', totp_secret = EXCLUDED.totp_secret, last_totp_step = NULL
+		WHERE user_mfa.enabled_at IS NULL
+	`
+
+	tag, err := r.db.Exec(ctx, query, userID, encryptedSecret)
+	if err != nil {
+		return fmt.Errorf("failed to start totp enrollment: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrMFAAlreadyEnabled
+	}
+
+	return nil
+}
+
+// EnableMFA turns on a second factor and issues a fresh set of recovery codes, audited in the same transaction
+// For TOTP the pending enrollment is enabled and step records the code that confirmed it
+func (r *MFARepository) EnableMFA(ctx context.Context, userID int, method string, step int64, recoveryHashes []string, audit *models.AuditLog) error {
+	return r.withTx(ctx, audit, func(tx pgx.Tx) error {
+		var query string
+		var args []interface{}
+		if method == models.MFAMethodTOTP {
+			query = `
+				UPDATE user_mfa
+				SET enabled_at = now(), last_totp_step = $2
+				WHERE user_id = $1 AND method = 'totp' AND enabled_at IS NULL
+			`
+			args = []interface{}{userID, step}
+		} else {
+			query = `
+				INSERT INTO user_mfa (user_id, method, enabled_at)
+				VALUES ($1, $2, now())
+				ON CONFLICT (user_id) DO UPDATE
+				SET method = EXCLUDED.method, totp_secret = NULL, last_totp_step = NULL, enabled_at = now()
+				WHERE user_mfa.enabled_at IS NULL
+			`
+			args = []interface{}{userID, method}
+		}
+
+		tag, err := tx.Exec(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to enable mfa: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrMFAAlreadyEnabled
+		}
+
+		return replaceRecoveryCodes(ctx, tx, userID, recoveryHashes)
+	})
+}
+
+// DisableMFA removes the second factor, its recovery codes and any open login challenges
+func (r *MFARepository) DisableMFA(ctx context.Context, userID int, audit *models.AuditLog) error {
+	return r.withTx(ctx, audit, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `DELETE FROM user_mfa WHERE user_id = $1 AND enabled_at IS NOT NULL`, userID)
+		if err != nil {
+			return fmt.Errorf("failed to disable mfa: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrMFANotFound
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+			return fmt.Errorf("failed to delete recovery codes: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM mfa_challenges WHERE user_id = $1 AND completed_at IS NULL`, userID); err != nil {
+			return fmt.Errorf("failed to delete mfa challenges: %w", err)
+		}
+		return nil
+	})
+}
+
+// RecordTOTPStep accepts a TOTP time step only if it is newer than the last one used
+// Returns false for a replayed code
+func (r *MFARepository) RecordTOTPStep(ctx context.Context, userID int, step int64) (bool, error) {
+	query := `
+		UPDATE user_mfa
+		SET last_totp_step = $2
+		WHERE user_id = $1 AND (last_totp_step IS NULL OR last_totp_step < $2)
+	`
+
+	tag, err := r.db.Exec(ctx, query, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to record totp step: %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// ==============================================
+// RECOVERY CODES
+// ==============================================
+
+// ReplaceRecoveryCodes invalidates the user's recovery codes and stores a new set
+func (r *MFARepository) ReplaceRecoveryCodes(ctx context.Context, userID int, hashes []string, audit *models.AuditLog) error {
+	return r.withTx(ctx, audit, func(tx pgx.Tx) error {
+		return replaceRecoveryCodes(ctx, tx, userID, hashes)
+	})
+}
+
+// UseRecoveryCode marks an unused code as spent; returns false if it doesn't match an unused code
+func (r *MFARepository) UseRecoveryCode(ctx context.Context, userID int, hash string, audit *models.AuditLog) (bool, error) {
+	used := false
+	err := r.withTx(ctx, audit, func(tx pgx.Tx) error {
+		query := `
+			UPDATE mfa_recovery_codes
+			SET used_at = now()
+			WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+			RETURNING id
+		`
+		var id int64
+		err := tx.QueryRow(ctx, query, userID, hash).Scan(&id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errNoRecoveryCode
+		}
+		if err != nil {
+			return fmt.Errorf("failed to use recovery code: %w", err)
+		}
+		used = true
+		return nil
+	})
+	if errors.Is(err, errNoRecoveryCode) {
+		return false, nil
+	}
+	return used, err
+}
+
+// errNoRecoveryCode rolls back UseRecoveryCode without writing an audit entry
+var errNoRecoveryCode = errors.New("recovery code not found")
+
+// CountRecoveryCodes returns how many unused recovery codes the user has left
+func (r *MFARepository) CountRecoveryCodes(ctx context.Context, userID int) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM mfa_recovery_codes WHERE user_id = $1 AND used_at IS NULL`, userID).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+
+	return n, nil
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx pgx.Tx, userID int, hashes []string) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	query := `
+		INSERT INTO mfa_recovery_codes (user_id, code_hash)
+		SELECT $1, unnest($2::text[])
+	`
+	if _, err := tx.Exec(ctx, query, userID, hashes); err != nil {
+		return fmt.Errorf("failed to store recovery codes: %w", err)
+	}
+
+	return nil
+}
+
+// ==============================================
+// LOGIN CHALLENGES
+// ==============================================
+
+// CreateChallenge stores the second step of a login
+func (r *MFARepository) CreateChallenge(ctx context.Context, c *models.MFAChallenge) error {
+	query := `
+		INSERT INTO mfa_challenges (user_id, token_hash, method, reason, device_fingerprint, expires_at, ip_address, user_agent)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		c.UserID,
+		c.TokenHash,
+		c.Method,
+		c.Reason,
+		c.Fingerprint,
+		c.ExpiresAt,
+		c.IPAddress,
+		c.UserAgent,
+	).Scan(&c.ID, &c.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create mfa challenge: %w", err)
+	}
+
+	return nil
+}
+
+// GetChallengeByToken retrieves a challenge by the hash of its token
+func (r *MFARepository) GetChallengeByToken(ctx context.Context, tokenHash string) (*models.MFAChallenge, error) {
+	query := `
+		SELECT id, user_id, token_hash, method, reason, device_fingerprint,
+		       attempts, expires_at, completed_at, ip_address, user_agent, created_at
+		FROM mfa_challenges
+		WHERE token_hash = $1
+	`
+
+	var c models.MFAChallenge
+	err := r.db.QueryRow(ctx, query, tokenHash).Scan(
+		&c.ID,
+		&c.UserID,
+		&c.TokenHash,
+		&c.Method,
+		&c.Reason,
+		&c.Fingerprint,
+		&c.Attempts,
+		&c.ExpiresAt,
+		&c.CompletedAt,
+		&c.IPAddress,
+		&c.UserAgent,
+		&c.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrMFAChallengeNotFound
+		}
+		return nil, fmt.Errorf("failed to get mfa challenge: %w", err)
+	}
+
+	return &c, nil
+}
+
+// IncrementChallengeAttempts counts a wrong code against the challenge
+func (r *MFARepository) IncrementChallengeAttempts(ctx context.Context, id int64) error {
+	if _, err := r.db.Exec(ctx, `UPDATE mfa_challenges SET attempts = attempts + 1 WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to increment mfa challenge attempts: %w", err)
+	}
+	return nil
+}
+
+// CompleteChallenge marks a challenge as used; returns false if it was already used or has expired
+func (r *MFARepository) CompleteChallenge(ctx context.Context, id int64) (bool, error) {
+	query := `
+		UPDATE mfa_challenges
+		SET completed_at = now()
+		WHERE id = $1 AND completed_at IS NULL AND expires_at > now()
+	`
+
+	tag, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to complete mfa challenge: %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// ==============================================
+// HELPERS
+// ==============================================
+
+func (r *MFARepository) withTx(ctx context.Context, audit *models.AuditLog, fn func(pgx.Tx) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := insertAuditLog(ctx, tx, audit); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return nil
+}
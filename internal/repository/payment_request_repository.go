@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ==============================================
+// ERRORS
+// ==============================================
+
+var (
+	ErrPaymentRequestNotFound = errors.New("payment request not found")
+	ErrPaymentRequestNotOpen  = errors.New("payment request is no longer open")
+)
+
+// ==============================================
+// PAYMENT REQUEST REPOSITORY
+// ==============================================
+
+type PaymentRequestRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPaymentRequestRepository(db *pgxpool.Pool) *PaymentRequestRepository {
+	return &PaymentRequestRepository{db: db}
+}
+
+const paymentRequestColumns = `
+	id, token, requester_user_id, payer_user_id, payer_identifier,
+	amount, currency, description, status, transaction_id, paid_by_user_id,
+	decline_reason, expires_at, paid_at, declined_at, created_at, updated_at
+`
+
+func scanPaymentRequest(row pgx.Row) (*models.PaymentRequest, error) {
+	var pr models.PaymentRequest
+	err := row.Scan(
+		&pr.ID,
+		&pr.Token,
+		&pr.RequesterUserID,
+		&pr.PayerUserID,
+		&pr.PayerIdentifier,
+		&pr.Amount,
+		&pr.Currency,
+		&pr.Description,
+		&pr.Status,
+		&pr.TransactionID,
+		&pr.PaidByUserID,
+		&pr.DeclineReason,
+		&pr.ExpiresAt,
+		&pr.PaidAt,
+		&pr.DeclinedAt,
+		&pr.CreatedAt,
+		&pr.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// ==============================================
+// CREATE
+// ==============================================
+
+// CreatePaymentRequest inserts a new open payment request
+func (r *PaymentRequestRepository) CreatePaymentRequest(ctx context.Context, pr *models.PaymentRequest) error {
+	query := `
+		INSERT INTO payment_requests (
+			token, requester_user_id, payer_user_id, payer_identifier,
+			amount, currency, description, expires_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, status, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		pr.Token,
+		pr.RequesterUserID,
+		pr.PayerUserID,
+		pr.PayerIdentifier,
+		pr.Amount,
+		pr.Currency,
+		pr.Description,
+		pr.ExpiresAt,
+	).Scan(&pr.ID, &pr.Status, &pr.CreatedAt, &pr.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create payment request: %w", err)
+	}
+
+	return nil
+}
+
+// ==============================================
+// GET
+// ==============================================
+
+// GetPaymentRequestByID retrieves a payment request by ID
+func (r *PaymentRequestRepository) GetPaymentRequestByID(ctx context.Context, id int64) (*models.PaymentRequest, error) {
+	query := `SELECT ` + paymentRequestColumns + ` FROM payment_requests WHERE id = $1`
+
+	pr, err := scanPaymentRequest(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPaymentRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment request: %w", err)
+	}
+
+	return pr, nil
+}
+
+// GetPaymentRequestByToken retrieves a payment request by its shareable token
+func (r *PaymentRequestRepository) GetPaymentRequestByToken(ctx context.Context, token string) (*models.PaymentRequest, error) {
+	query := `SELECT ` + paymentRequestColumns + ` FROM payment_requests WHERE token = $1`
+
+	pr, err := scanPaymentRequest(r.db.QueryRow(ctx, query, token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPaymentRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment request by token: %w", err)
+	}
+
+	return pr, nil
+}
+
+// GetPaymentRequestForUpdate retrieves and locks a payment request
+// Holds the row until tx ends, so decline and expiry wait for a settling payment
+func (r *PaymentRequestRepository) GetPaymentRequestForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*models.PaymentRequest, error) {
+	query := `SELECT ` + paymentRequestColumns + ` FROM payment_requests WHERE id = $1 FOR UPDATE`
+
+	pr, err := scanPaymentRequest(tx.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPaymentRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to lock payment request: %w", err)
+	}
+
+	return pr, nil
+}
+
+// ==============================================
+// STATUS TRANSITIONS
+// ==============================================
+// All transitions are conditional on status = 'open' so that two
+// concurrent actions (pay vs. decline, two payers on one link) can
+// never both succeed.
+
+// MarkPaid settles an open request against a posted transaction
+// Runs inside the transfer's tx so the request and the money move together
+func (r *PaymentRequestRepository) MarkPaid(ctx context.Context, tx pgx.Tx, id int64, paidByUserID int, transactionID int64) error {
+	query := `
+		UPDATE payment_requests
+		SET status = 'paid',
+		    paid_by_user_id = $2,
+		    transaction_id = $3,
+		    paid_at = now()
+		WHERE id = $1 AND status = 'open'
+	`
+
+	tag, err := tx.Exec(ctx, query, id, paidByUserID, transactionID)
+	if err != nil {
+		return fmt.Errorf("failed to mark payment request as paid: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrPaymentRequestNotOpen
+	}
+
+	return nil
+}
+
+// MarkDeclined declines an open request
+func (r *PaymentRequestRepository) MarkDeclined(ctx context.Context, id int64, reason string) error {
+	query := `
+		UPDATE payment_requests
+		SET status = 'declined',
+		    decline_reason = NULLIF($2, ''),
+		    declined_at = now()
+		WHERE id = $1 AND status = 'open' AND expires_at > now()
+	`
+
+	tag, err := r.db.Exec(ctx, query, id, reason)
+	if err != nil {
+		return fmt.Errorf("failed to decline payment request: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrPaymentRequestNotOpen
+	}
+
+	return nil
+}
+
+// ExpireOverdue flips every open request past its expiry to 'expired'
+// Returns the number of requests expired
+func (r *PaymentRequestRepository) ExpireOverdue(ctx context.Context) (int64, error) {
+	query := `
+		UPDATE payment_requests
+		SET status = 'expired'
+		WHERE status = 'open' AND expires_at <= now()
+	`
+
+	tag, err := r.db.Exec(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire payment requests: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// ==============================================
+// HISTORY
+// ==============================================
+
+// ListSentPaymentRequests lists requests created by a user, newest first
+// status is optional ("" = all statuses)
+func (r *PaymentRequestRepository) ListSentPaymentRequests(ctx context.Context, userID int, status string, limit, offset int) ([]models.PaymentRequest, error) {
+	query := `
+		SELECT ` + paymentRequestColumns + `
+		FROM payment_requests
+		WHERE requester_user_id = $1
+			AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	return r.listPaymentRequests(ctx, query, userID, status, limit, offset)
+}
+
+// ListReceivedPaymentRequests lists requests addressed to (or paid by) a user, newest first
+// status is optional ("" = all statuses)
+func (r *PaymentRequestRepository) ListReceivedPaymentRequests(ctx context.Context, userID int, status string, limit, offset int) ([]models.PaymentRequest, error) {
+	query := `
+		SELECT ` + paymentRequestColumns + `
+		FROM payment_requests
+		WHERE (payer_user_id = $1 OR paid_by_user_id = $1)
+			AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	return r.listPaymentRequests(ctx, query, userID, status, limit, offset)
+}
+
+func (r *PaymentRequestRepository) listPaymentRequests(ctx context.Context, query string, args ...interface{}) ([]models.PaymentRequest, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query payment requests: %w", err)
+	}
+	defer rows.Close()
+
+	var requests []models.PaymentRequest
+	for rows.Next() {
+		pr, err := scanPaymentRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan payment request: %w", err)
+		}
+		requests = append(requests, *pr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating payment requests: %w", err)
+	}
+
+	return requests, nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ==============================================
+// RECONCILIATION REPOSITORY
+// ==============================================
+// Checks the ledger's own invariants: every balance equals the sum of the
+// account's postings and every transaction's postings sum to zero. The
+// postings trigger enforces both on write, so a finding means rows were
+// edited around it (or seeded with a balance and no postings).
+
+type ReconciliationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReconciliationRepository(db *pgxpool.Pool) *ReconciliationRepository {
+	return &ReconciliationRepository{db: db}
+}
+
+// Reconcile runs every check against a single snapshot, so postings made
+// while it runs can't show up as mismatches
+func (r *ReconciliationRepository) Reconcile(ctx context.Context) (*models.Reconciliation, error) {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin reconciliation: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var rec models.Reconciliation
+	err = tx.QueryRow(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM accounts),
+			(SELECT COUNT(*) FROM transactions),
+			(SELECT COALESCE(SUM(balance), 0) FROM accounts),
+			(SELECT COALESCE(SUM(amount), 0) FROM postings),
+			now()
+	`).Scan(&rec.Accounts, &rec.Transactions, &rec.BalanceTotal, &rec.PostingTotal, &rec.CheckedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to total ledger: %w", err)
+	}
+
+	rec.BalanceMismatches, err = listBalanceMismatches(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	rec.UnbalancedTransactions, err = listUnbalancedTransactions(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+func listBalanceMismatches(ctx context.Context, tx pgx.Tx) ([]models.BalanceMismatch, error) {
+	query := `
+		SELECT a.id, a.account_number, a.external_id, a.balance, COALESCE(SUM(p.amount), 0) AS posted_balance
+		FROM accounts a
+		LEFT JOIN postings p ON p.account_id = a.id
+		GROUP BY a.id
+		HAVING a.balance <> COALESCE(SUM(p.amount), 0)
+		ORDER BY a.id
+	`
+
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query balance mismatches: %w", err)
+	}
+	defer rows.Close()
+
+	var mismatches []models.BalanceMismatch
+	for rows.Next() {
+		var m models.BalanceMismatch
+		if err := rows.Scan(&m.AccountID, &m.AccountNumber, &m.ExternalID, &m.Balance, &m.PostedBalance); err != nil {
+			return nil, fmt.Errorf("failed to scan balance mismatch: %w", err)
+		}
+		mismatches = append(mismatches, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating balance mismatches: %w", err)
+	}
+
+	return mismatches, nil
+}
+
+func listUnbalancedTransactions(ctx context.Context, tx pgx.Tx) ([]models.UnbalancedTransaction, error) {
+	query := `
+		SELECT t.id, t.reference, t.status, COALESCE(SUM(p.amount), 0) AS posting_total, COUNT(p.id) AS postings
+		FROM transactions t
+		LEFT JOIN postings p ON p.transaction_id = t.id
+		GROUP BY t.id
+		HAVING COALESCE(SUM(p.amount), 0) <> 0
+		    OR (t.status IN ('posted', 'reversed') AND COUNT(p.id) = 0)
+		ORDER BY t.id
+	`
+
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unbalanced transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var unbalanced []models.UnbalancedTransaction
+	for rows.Next() {
+		var u models.UnbalancedTransaction
+		if err := rows.Scan(&u.TransactionID, &u.Reference, &u.Status, &u.PostingTotal, &u.Postings); err != nil {
+			return nil, fmt.Errorf("failed to scan unbalanced transaction: %w", err)
+		}
+		unbalanced = append(unbalanced, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating unbalanced transactions: %w", err)
+	}
+
+	return unbalanced, nil
+}
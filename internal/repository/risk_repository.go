@@ -0,0 +1,354 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ==============================================
+// ERRORS
+// ==============================================
+
+var (
+	ErrRiskRuleNotFound     = errors.New("risk rule not found")
+	ErrRiskDecisionNotFound = errors.New("risk decision not found")
+)
+
+// ==============================================
+// RISK REPOSITORY
+// ==============================================
+
+type RiskRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRiskRepository(db *pgxpool.Pool) *RiskRepository {
+	return &RiskRepository{db: db}
+}
+
+// ==============================================
+// RULES
+// ==============================================
+
+const riskRuleColumns = `
+	code, description, enabled, action, params, updated_by, created_at, updated_at
+`
+
+func scanRiskRule(row pgx.Row) (*models.RiskRule, error) {
+	var r models.RiskRule
+	err := row.Scan(
+		&r.Code,
+		&r.Description,
+		&r.Enabled,
+		&r.Action,
+		&r.Params,
+		&r.UpdatedBy,
+		&r.CreatedAt,
+		&r.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// ListRules returns every rule, enabled or not
+func (r *RiskRepository) ListRules(ctx context.Context) ([]models.RiskRule, error) {
+	query := `SELECT ` + riskRuleColumns + ` FROM risk_rules ORDER BY code`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list risk rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []models.RiskRule
+	for rows.Next() {
+		rule, err := scanRiskRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan risk rule: %w", err)
+		}
+		rules = append(rules, *rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// GetRule returns a single rule by code
+func (r *RiskRepository) GetRule(ctx context.Context, code string) (*models.RiskRule, error) {
+	query := `SELECT ` + riskRuleColumns + ` FROM risk_rules WHERE code = $1`
+
+	rule, err := scanRiskRule(r.db.QueryRow(ctx, query, code))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRiskRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to get risk rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// UpdateRule saves a rule's enabled flag, action and params, and writes the audit log entry in the same transaction
+func (r *RiskRepository) UpdateRule(ctx context.Context, rule *models.RiskRule, audit *models.AuditLog) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	params, err := json.Marshal(rule.Params)
+	if err != nil {
+		return fmt.Errorf("failed to encode risk rule params: %w", err)
+	}
+
+	query := `
+		UPDATE risk_rules
+		SET enabled = $2, action = $3, params = $4::text::jsonb, updated_by = $5
+		WHERE code = $1
+		RETURNING ` + riskRuleColumns
+
+	updated, err := scanRiskRule(tx.QueryRow(ctx, query,
+		rule.Code,
+		rule.Enabled,
+		rule.Action,
+		string(params),
+		rule.UpdatedBy,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrRiskRuleNotFound
+		}
+		return fmt.Errorf("failed to update risk rule: %w", err)
+	}
+
+	if err := insertAuditLog(ctx, tx, audit); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+
+	*rule = *updated
+	return nil
+}
+
+// ==============================================
+// RULE FACTS
+// ==============================================
+// These run inside the ledger transaction after the account is locked,
+// so concurrent payments from the same account see each other
+
+// CountDebitsSince counts outgoing postings on an account since the given time
+func (r *RiskRepository) CountDebitsSince(ctx context.Context, tx pgx.Tx, accountID int64, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM postings p
+		JOIN transactions t ON t.id = p.transaction_id
+		WHERE p.account_id = $1
+			AND p.amount < 0
+			AND p.created_at >= $2
+			AND t.status IN ('posted', 'pending')
+	`
+
+	var count int
+	if err := tx.QueryRow(ctx, query, accountID, since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count debits: %w", err)
+	}
+
+	return count, nil
+}
+
+// HasPriorTransfer checks if the sender has ever completed a transfer to the recipient
+func (r *RiskRepository) HasPriorTransfer(ctx context.Context, tx pgx.Tx, fromAccountID, toAccountID int64) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM transactions
+			WHERE from_account_id = $1
+				AND to_account_id = $2
+				AND kind = 'p2p'
+				AND status = 'posted'
+		)
+	`
+
+	var exists bool
+	if err := tx.QueryRow(ctx, query, fromAccountID, toAccountID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check prior transfers: %w", err)
+	}
+
+	return exists, nil
+}
+
+// GetPasswordResetAt returns when the user last reset their password via the forgot-password flow
+func (r *RiskRepository) GetPasswordResetAt(ctx context.Context, tx pgx.Tx, userID int) (pgtype.Timestamptz, error) {
+	query := `SELECT password_reset_at FROM users WHERE id = $1`
+
+	var resetAt pgtype.Timestamptz
+	if err := tx.QueryRow(ctx, query, userID).Scan(&resetAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return resetAt, ErrUserNotFound
+		}
+		return resetAt, fmt.Errorf("failed to get password reset time: %w", err)
+	}
+
+	return resetAt, nil
+}
+
+// GetDeviceFirstSeen returns when the user first made a payment that went through from a device
+// That includes payments a risk challenge let through after step-up authentication
+func (r *RiskRepository) GetDeviceFirstSeen(ctx context.Context, userID int, deviceID string) (pgtype.Timestamptz, error) {
+	query := `
+		SELECT MIN(created_at)
+		FROM risk_decisions
+		WHERE user_id = $1 AND device_id = $2 AND transaction_id IS NOT NULL
+	`
+
+	var firstSeen pgtype.Timestamptz
+	if err := r.db.QueryRow(ctx, query, userID, deviceID).Scan(&firstSeen); err != nil {
+		return firstSeen, fmt.Errorf("failed to get device history: %w", err)
+	}
+
+	return firstSeen, nil
+}
+
+// ==============================================
+// DECISIONS
+// ==============================================
+
+const riskDecisionColumns = `
+	id, user_id, account_id, operation, amount, counterparty_account_id,
+	idempotency_key, device_id, ip_address, user_agent, outcome,
+	fired_rules, transaction_id, created_at
+`
+
+func scanRiskDecision(row pgx.Row) (*models.RiskDecision, error) {
+	var d models.RiskDecision
+	err := row.Scan(
+		&d.ID,
+		&d.UserID,
+		&d.AccountID,
+		&d.Operation,
+		&d.Amount,
+		&d.CounterpartyAccountID,
+		&d.IdempotencyKey,
+		&d.DeviceID,
+		&d.IPAddress,
+		&d.UserAgent,
+		&d.Outcome,
+		&d.FiredRules,
+		&d.TransactionID,
+		&d.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// CreateDecision stores a decision outside any ledger transaction
+// Used for challenged and blocked payments, whose ledger transaction is rolled back
+func (r *RiskRepository) CreateDecision(ctx context.Context, d *models.RiskDecision) error {
+	return createRiskDecision(ctx, r.db.QueryRow, d)
+}
+
+// CreateDecisionTx stores an allowed decision alongside the transaction it let through
+func (r *RiskRepository) CreateDecisionTx(ctx context.Context, tx pgx.Tx, d *models.RiskDecision) error {
+	return createRiskDecision(ctx, tx.QueryRow, d)
+}
+
+func createRiskDecision(ctx context.Context, queryRow func(context.Context, string, ...any) pgx.Row, d *models.RiskDecision) error {
+	firedRules := d.FiredRules
+	if firedRules == nil {
+		firedRules = []models.RiskRuleHit{}
+	}
+	encoded, err := json.Marshal(firedRules)
+	if err != nil {
+		return fmt.Errorf("failed to encode fired rules: %w", err)
+	}
+
+	query := `
+		INSERT INTO risk_decisions (
+			user_id, account_id, operation, amount, counterparty_account_id,
+			idempotency_key, device_id, ip_address, user_agent, outcome,
+			fired_rules, transaction_id
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11::text::jsonb, $12)
+		RETURNING id, created_at
+	`
+
+	err = queryRow(ctx, query,
+		d.UserID,
+		d.AccountID,
+		d.Operation,
+		d.Amount,
+		d.CounterpartyAccountID,
+		d.IdempotencyKey,
+		d.DeviceID,
+		d.IPAddress,
+		d.UserAgent,
+		d.Outcome,
+		string(encoded),
+		d.TransactionID,
+	).Scan(&d.ID, &d.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create risk decision: %w", err)
+	}
+
+	return nil
+}
+
+// GetDecision returns a single decision by ID
+func (r *RiskRepository) GetDecision(ctx context.Context, id int64) (*models.RiskDecision, error) {
+	query := `SELECT ` + riskDecisionColumns + ` FROM risk_decisions WHERE id = $1`
+
+	d, err := scanRiskDecision(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRiskDecisionNotFound
+		}
+		return nil, fmt.Errorf("failed to get risk decision: %w", err)
+	}
+
+	return d, nil
+}
+
+// ListDecisions returns decisions newest first, optionally filtered by user and outcome
+// userID 0 and outcome "" mean no filter
+func (r *RiskRepository) ListDecisions(ctx context.Context, userID int, outcome string, limit, offset int) ([]models.RiskDecision, error) {
+	query := `
+		SELECT ` + riskDecisionColumns + `
+		FROM risk_decisions
+		WHERE ($1 = 0 OR user_id = $1)
+			AND ($2 = '' OR outcome = $2)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, outcome, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list risk decisions: %w", err)
+	}
+	defer rows.Close()
+
+	var decisions []models.RiskDecision
+	for rows.Next() {
+		d, err := scanRiskDecision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan risk decision: %w", err)
+		}
+		decisions = append(decisions, *d)
+	}
+
+	return decisions, rows.Err()
+}
@@ -0,0 +1,348 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ==============================================
+// ERRORS
+// ==============================================
+
+var (
+	ErrScreeningHitNotFound = errors.New("screening hit not found")
+	ErrScreeningHitReviewed = errors.New("screening hit has already been reviewed")
+)
+
+// FrozenReasonSanctions is written to accounts.frozen_reason when signup screening freezes an account
+const FrozenReasonSanctions = "Sanctions screening"
+
+// ==============================================
+// SCREENING REPOSITORY
+// ==============================================
+
+type ScreeningRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewScreeningRepository(db *pgxpool.Pool) *ScreeningRepository {
+	return &ScreeningRepository{db: db}
+}
+
+const screeningHitColumns = `
+	id, subject_type, subject_id, user_id, screened_name, list_name, entry_uid,
+	entry_name, matched_name, entry_type, program, score::float8, status,
+	account_frozen, reviewed_by, reviewed_at, review_note, created_at, updated_at
+`
+
+func scanScreeningHit(row pgx.Row) (*models.ScreeningHit, error) {
+	var h models.ScreeningHit
+	err := row.Scan(
+		&h.ID,
+		&h.SubjectType,
+		&h.SubjectID,
+		&h.UserID,
+		&h.ScreenedName,
+		&h.ListName,
+		&h.EntryUID,
+		&h.EntryName,
+		&h.MatchedName,
+		&h.EntryType,
+		&h.Program,
+		&h.Score,
+		&h.Status,
+		&h.AccountFrozen,
+		&h.ReviewedBy,
+		&h.ReviewedAt,
+		&h.ReviewNote,
+		&h.CreatedAt,
+		&h.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// ==============================================
+// RECORD HITS
+// ==============================================
+
+// RecordHits stores potential matches for one subject and puts the subject on hold in the same transaction:
+// a signup hit freezes the user's account and a beneficiary hit marks the beneficiary held.
+// Hits already recorded for the same subject and list entry are skipped. Returns how many were new
+func (r *ScreeningRepository) RecordHits(ctx context.Context, subjectType string, subjectID int64, hits []models.ScreeningHit) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	query := `
+		INSERT INTO screening_hits (
+			subject_type, subject_id, user_id, screened_name, list_name, entry_uid,
+			entry_name, matched_name, entry_type, program, score, account_frozen
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (subject_type, subject_id, list_name, entry_uid) DO NOTHING
+		RETURNING id, status, created_at, updated_at
+	`
+
+	frozen := subjectType == models.ScreeningSubjectSignup
+	created := 0
+	for i := range hits {
+		h := &hits[i]
+		h.SubjectType = subjectType
+		h.SubjectID = subjectID
+		h.AccountFrozen = frozen
+
+		err := tx.QueryRow(ctx, query,
+			h.SubjectType,
+			h.SubjectID,
+			h.UserID,
+			h.ScreenedName,
+			h.ListName,
+			h.EntryUID,
+			h.EntryName,
+			h.MatchedName,
+			h.EntryType,
+			h.Program,
+			h.Score,
+			h.AccountFrozen,
+		).Scan(&h.ID, &h.Status, &h.CreatedAt, &h.UpdatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to record screening hit: %w", err)
+		}
+		created++
+	}
+
+	if created > 0 {
+		switch subjectType {
+		case models.ScreeningSubjectSignup:
+			freezeQuery := `
+				UPDATE accounts
+				SET frozen_at = COALESCE(frozen_at, now()),
+				    frozen_reason = COALESCE(frozen_reason, $2)
+				WHERE user_id = $1 AND type = 'user'
+			`
+			if _, err := tx.Exec(ctx, freezeQuery, subjectID, FrozenReasonSanctions); err != nil {
+				return 0, fmt.Errorf("failed to freeze account: %w", err)
+			}
+		case models.ScreeningSubjectBeneficiary:
+			holdQuery := `UPDATE beneficiaries SET status = 'held' WHERE id = $1 AND status = 'active'`
+			if _, err := tx.Exec(ctx, holdQuery, subjectID); err != nil {
+				return 0, fmt.Errorf("failed to hold beneficiary: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return created, nil
+}
+
+// ==============================================
+// HOLD CHECKS
+// ==============================================
+
+// HasOpenHits checks if a subject has hits that are pending or confirmed
+func (r *ScreeningRepository) HasOpenHits(ctx context.Context, subjectType string, subjectID int64) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM screening_hits
+			WHERE subject_type = $1 AND subject_id = $2 AND status <> 'cleared'
+		)
+	`
+
+	var open bool
+	if err := r.db.QueryRow(ctx, query, subjectType, subjectID).Scan(&open); err != nil {
+		return false, fmt.Errorf("failed to check screening hits: %w", err)
+	}
+
+	return open, nil
+}
+
+// IsPayeeHeld checks if the user has saved the account as a beneficiary that is held or blocked
+func (r *ScreeningRepository) IsPayeeHeld(ctx context.Context, userID int, accountID int64) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM beneficiaries
+			WHERE user_id = $1 AND account_id = $2 AND status <> 'active'
+		)
+	`
+
+	var held bool
+	if err := r.db.QueryRow(ctx, query, userID, accountID).Scan(&held); err != nil {
+		return false, fmt.Errorf("failed to check beneficiary status: %w", err)
+	}
+
+	return held, nil
+}
+
+// ==============================================
+// GET / LIST
+// ==============================================
+
+// GetHit retrieves a screening hit by ID
+func (r *ScreeningRepository) GetHit(ctx context.Context, id int64) (*models.ScreeningHit, error) {
+	query := `SELECT ` + screeningHitColumns + ` FROM screening_hits WHERE id = $1`
+
+	h, err := scanScreeningHit(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrScreeningHitNotFound
+		}
+		return nil, fmt.Errorf("failed to get screening hit: %w", err)
+	}
+
+	return h, nil
+}
+
+// ListHits returns hits newest first, optionally filtered by status and subject type
+func (r *ScreeningRepository) ListHits(ctx context.Context, status, subjectType string, limit, offset int) ([]models.ScreeningHit, error) {
+	query := `
+		SELECT ` + screeningHitColumns + `
+		FROM screening_hits
+		WHERE ($1 = '' OR status = $1)
+			AND ($2 = '' OR subject_type = $2)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.Query(ctx, query, status, subjectType, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list screening hits: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []models.ScreeningHit
+	for rows.Next() {
+		h, err := scanScreeningHit(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan screening hit: %w", err)
+		}
+		hits = append(hits, *h)
+	}
+
+	return hits, rows.Err()
+}
+
+// ==============================================
+// REVIEW
+// ==============================================
+
+// ReviewHit clears or confirms a pending hit, audited in the same transaction.
+// Once a subject has no pending or confirmed hits left its hold is lifted: the account is
+// unfrozen (unless an AML case also froze it) and a held beneficiary becomes active again.
+// Confirming a beneficiary hit blocks the beneficiary for good
+func (r *ScreeningRepository) ReviewHit(ctx context.Context, id int64, reviewerID int, status, note string, audit *models.AuditLog) (*models.ScreeningHit, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	lockQuery := `SELECT ` + screeningHitColumns + ` FROM screening_hits WHERE id = $1 FOR UPDATE`
+	current, err := scanScreeningHit(tx.QueryRow(ctx, lockQuery, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrScreeningHitNotFound
+		}
+		return nil, fmt.Errorf("failed to lock screening hit: %w", err)
+	}
+	if !current.IsPending() {
+		return nil, ErrScreeningHitReviewed
+	}
+
+	reviewNote := pgtype.Text{String: note, Valid: note != ""}
+	query := `
+		UPDATE screening_hits
+		SET status = $2, reviewed_by = $3, reviewed_at = now(), review_note = $4
+		WHERE id = $1
+		RETURNING ` + screeningHitColumns
+
+	updated, err := scanScreeningHit(tx.QueryRow(ctx, query, id, status, reviewerID, reviewNote))
+	if err != nil {
+		return nil, fmt.Errorf("failed to review screening hit: %w", err)
+	}
+
+	if err := r.releaseSubject(ctx, tx, updated); err != nil {
+		return nil, err
+	}
+
+	audit.EntityID = pgtype.Int8{Int64: id, Valid: true}
+	if err := insertAuditLog(ctx, tx, audit); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return updated, nil
+}
+
+// releaseSubject applies the effect of a review to the held subject
+func (r *ScreeningRepository) releaseSubject(ctx context.Context, tx pgx.Tx, h *models.ScreeningHit) error {
+	switch h.SubjectType {
+	case models.ScreeningSubjectSignup:
+		if !h.AccountFrozen {
+			return nil
+		}
+		query := `
+			UPDATE accounts
+			SET frozen_at = NULL, frozen_reason = NULL
+			WHERE user_id = $1 AND type = 'user'
+				AND NOT EXISTS (
+					SELECT 1 FROM screening_hits
+					WHERE subject_type = 'signup' AND subject_id = $1
+						AND account_frozen AND status <> 'cleared'
+				)
+				AND NOT EXISTS (
+					SELECT 1 FROM aml_cases
+					WHERE account_id = accounts.id AND status <> 'closed' AND account_frozen
+				)
+		`
+		if _, err := tx.Exec(ctx, query, h.SubjectID); err != nil {
+			return fmt.Errorf("failed to unfreeze account: %w", err)
+		}
+
+	case models.ScreeningSubjectBeneficiary:
+		query := `
+			UPDATE beneficiaries
+			SET status = CASE
+				WHEN EXISTS (
+					SELECT 1 FROM screening_hits
+					WHERE subject_type = 'beneficiary' AND subject_id = $1 AND status = 'confirmed'
+				) THEN 'blocked'
+				WHEN EXISTS (
+					SELECT 1 FROM screening_hits
+					WHERE subject_type = 'beneficiary' AND subject_id = $1 AND status = 'pending'
+				) THEN 'held'
+				ELSE 'active'
+			END
+			WHERE id = $1
+		`
+		if _, err := tx.Exec(ctx, query, h.SubjectID); err != nil {
+			return fmt.Errorf("failed to update beneficiary status: %w", err)
+		}
+	}
+
+	// KYC submissions have nothing to release; approval checks HasOpenHits
+	return nil
+}
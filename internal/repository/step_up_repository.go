@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ==============================================
+// ERRORS
+// ==============================================
+
+var (
+	ErrStepUpChallengeNotFound = errors.New("step-up challenge not found")
+)
+
+// ==============================================
+// STEP-UP REPOSITORY
+// ==============================================
+
+type StepUpRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewStepUpRepository(db *pgxpool.Pool) *StepUpRepository {
+	return &StepUpRepository{db: db}
+}
+
+// CreateChallenge stores a new challenge for one operation
+func (r *StepUpRepository) CreateChallenge(ctx context.Context, c *models.StepUpChallenge) error {
+	query := `
+		INSERT INTO step_up_challenges (
+			user_id, token_hash, action, binding_hash, method, otp_id, risk_decision_id,
+			expires_at, ip_address, user_agent
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		c.UserID,
+		c.TokenHash,
+		c.Action,
+		c.BindingHash,
+		c.Method,
+		c.OTPID,
+		c.RiskDecisionID,
+		c.ExpiresAt,
+		c.IPAddress,
+		c.UserAgent,
+	).Scan(&c.ID, &c.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create step-up challenge: %w", err)
+	}
+
+	return nil
+}
+
+// GetChallengeByToken retrieves a challenge by the hash of its token
+func (r *StepUpRepository) GetChallengeByToken(ctx context.Context, tokenHash string) (*models.StepUpChallenge, error) {
+	query := `
+		SELECT id, user_id, token_hash, action, binding_hash, method, otp_id, risk_decision_id,
+		       attempts, expires_at, consumed_at, ip_address, user_agent, created_at
+		FROM step_up_challenges
+		WHERE token_hash = $1
+	`
+
+	var c models.StepUpChallenge
+	err := r.db.QueryRow(ctx, query, tokenHash).Scan(
+		&c.ID,
+		&c.UserID,
+		&c.TokenHash,
+		&c.Action,
+		&c.BindingHash,
+		&c.Method,
+		&c.OTPID,
+		&c.RiskDecisionID,
+		&c.Attempts,
+		&c.ExpiresAt,
+		&c.ConsumedAt,
+		&c.IPAddress,
+		&c.UserAgent,
+		&c.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrStepUpChallengeNotFound
+		}
+		return nil, fmt.Errorf("failed to get step-up challenge: %w", err)
+	}
+
+	return &c, nil
+}
+
+// SetChallengeOTP points the challenge at a newly emailed code, replacing any earlier one
+func (r *StepUpRepository) SetChallengeOTP(ctx context.Context, id int64, otpID int32) error {
+	query := `UPDATE step_up_challenges SET otp_id = $2 WHERE id = $1 AND consumed_at IS NULL`
+
+	if _, err := r.db.Exec(ctx, query, id, otpID); err != nil {
+		return fmt.Errorf("failed to set step-up challenge code: %w", err)
+	}
+	return nil
+}
+
+// IncrementChallengeAttempts counts a wrong code against the challenge
+func (r *StepUpRepository) IncrementChallengeAttempts(ctx context.Context, id int64) error {
+	if _, err := r.db.Exec(ctx, `UPDATE step_up_challenges SET attempts = attempts + 1 WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to increment step-up challenge attempts: %w", err)
+	}
+	return nil
+}
+
+// ConsumeChallenge marks a challenge as used, along with the emailed code that answered it, audited in the same transaction
+// Returns false if it was already used, has expired or ran out of attempts
+func (r *StepUpRepository) ConsumeChallenge(ctx context.Context, c *models.StepUpChallenge, usedEmailCode bool, audit *models.AuditLog) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	query := `
+		UPDATE step_up_challenges
+		SET consumed_at = now()
+		WHERE id = $1 AND consumed_at IS NULL AND expires_at > now() AND attempts < $2
+	`
+	tag, err := tx.Exec(ctx, query, c.ID, models.StepUpMaxAttempts)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume step-up challenge: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if usedEmailCode && c.OTPID.Valid {
+		query := `UPDATE verification_codes SET used_at = now() WHERE id = $1 AND used_at IS NULL`
+		tag, err := tx.Exec(ctx, query, c.OTPID.Int32)
+		if err != nil {
+			return false, fmt.Errorf("failed to mark OTP as used: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return false, nil
+		}
+	}
+
+	if err := insertAuditLog(ctx, tx, audit); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return true, nil
+}
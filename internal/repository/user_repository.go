@@ -8,6 +8,7 @@ import (
 
 	"github.com/Brownie44l1/debank/internal/models"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -42,7 +43,7 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) erro
 	query := `
 		INSERT INTO users (name, phone, email, password_hash)
 		VALUES ($1, $2, $3, $4)
-		RETURNING id, created_at, updated_at
+		RETURNING id, role, created_at, updated_at
 	`
 
 	err := r.db.QueryRow(ctx, query,
@@ -50,7 +51,7 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) erro
 		user.Phone,
 		user.Email,
 		user.PasswordHash,
-	).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	).Scan(&user.ID, &user.Role, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
 		return fmt.Errorf("failed to create user: %w", err)
@@ -68,7 +69,7 @@ func (r *UserRepository) GetUserByID(ctx context.Context, userID int) (*models.U
 	query := `
 		SELECT id, name, phone, email, password_hash, username, pin_hash,
 		       is_email_verified, is_active, onboarding_completed,
-		       failed_login_attempts, locked_until,
+		       failed_login_attempts, locked_until, role,
 		       created_at, updated_at, last_login_at
 		FROM users
 		WHERE id = $1
@@ -88,6 +89,7 @@ func (r *UserRepository) GetUserByID(ctx context.Context, userID int) (*models.U
 		&user.OnboardingCompleted,
 		&user.FailedLoginAttempts,
 		&user.LockedUntil,
+		&user.Role,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&user.LastLoginAt,
@@ -108,7 +110,7 @@ func (r *UserRepository) GetUserByPhone(ctx context.Context, phone string) (*mod
 	query := `
 		SELECT id, name, phone, email, password_hash, username, pin_hash,
 		       is_email_verified, is_active, onboarding_completed,
-		       failed_login_attempts, locked_until,
+		       failed_login_attempts, locked_until, role,
 		       created_at, updated_at, last_login_at
 		FROM users
 		WHERE phone = $1
@@ -128,6 +130,7 @@ func (r *UserRepository) GetUserByPhone(ctx context.Context, phone string) (*mod
 		&user.OnboardingCompleted,
 		&user.FailedLoginAttempts,
 		&user.LockedUntil,
+		&user.Role,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&user.LastLoginAt,
@@ -148,7 +151,7 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*mod
 	query := `
 		SELECT id, name, phone, email, password_hash, username, pin_hash,
 		       is_email_verified, is_active, onboarding_completed,
-		       failed_login_attempts, locked_until,
+		       failed_login_attempts, locked_until, role,
 		       created_at, updated_at, last_login_at
 		FROM users
 		WHERE email = $1
@@ -168,6 +171,7 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*mod
 		&user.OnboardingCompleted,
 		&user.FailedLoginAttempts,
 		&user.LockedUntil,
+		&user.Role,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&user.LastLoginAt,
@@ -188,7 +192,7 @@ func (r *UserRepository) GetUserByUsername(ctx context.Context, username string)
 	query := `
 		SELECT id, name, phone, email, password_hash, username, pin_hash,
 		       is_email_verified, is_active, onboarding_completed,
-		       failed_login_attempts, locked_until,
+		       failed_login_attempts, locked_until, role,
 		       created_at, updated_at, last_login_at
 		FROM users
 		WHERE username = $1
@@ -208,6 +212,7 @@ func (r *UserRepository) GetUserByUsername(ctx context.Context, username string)
 		&user.OnboardingCompleted,
 		&user.FailedLoginAttempts,
 		&user.LockedUntil,
+		&user.Role,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&user.LastLoginAt,
@@ -244,14 +249,14 @@ func (r *UserRepository) SetUsername(ctx context.Context, userID int, username s
 }
 
 // SetPin sets the transaction PIN for a user
-func (r *UserRepository) SetPin(ctx context.Context, userID int, pinHash string) error {
+func (r *UserRepository) SetPin(ctx context.Context, userID int, pinHash string, audit *models.AuditLog) error {
 	query := `
 		UPDATE users
 		SET pin_hash = $1, updated_at = now()
 		WHERE id = $2
 	`
 
-	_, err := r.db.Exec(ctx, query, pinHash, userID)
+	_, err := r.execAudited(ctx, audit, query, pinHash, userID)
 	if err != nil {
 		return fmt.Errorf("failed to set PIN: %w", err)
 	}
@@ -276,14 +281,14 @@ func (r *UserRepository) CompleteOnboarding(ctx context.Context, userID int) err
 }
 
 // VerifyEmail marks user's email as verified
-func (r *UserRepository) VerifyEmail(ctx context.Context, userID int) error {
+func (r *UserRepository) VerifyEmail(ctx context.Context, userID int, audit *models.AuditLog) error {
 	query := `
 		UPDATE users
 		SET is_email_verified = true, updated_at = now()
 		WHERE id = $1
 	`
 
-	_, err := r.db.Exec(ctx, query, userID)
+	_, err := r.execAudited(ctx, audit, query, userID)
 	if err != nil {
 		return fmt.Errorf("failed to verify email: %w", err)
 	}
@@ -292,14 +297,14 @@ func (r *UserRepository) VerifyEmail(ctx context.Context, userID int) error {
 }
 
 // UpdatePassword updates user's password hash
-func (r *UserRepository) UpdatePassword(ctx context.Context, userID int, passwordHash string) error {
+func (r *UserRepository) UpdatePassword(ctx context.Context, userID int, passwordHash string, audit *models.AuditLog) error {
 	query := `
 		UPDATE users
 		SET password_hash = $1, updated_at = now()
-		WHERE id = $1
+		WHERE id = $2
 	`
 
-	_, err := r.db.Exec(ctx, query, passwordHash, userID)
+	_, err := r.execAudited(ctx, audit, query, passwordHash, userID)
 	if err != nil {
 		return fmt.Errorf("failed to update password: %w", err)
 	}
@@ -307,44 +312,103 @@ func (r *UserRepository) UpdatePassword(ctx context.Context, userID int, passwor
 	return nil
 }
 
-// UpdateLastLogin updates the last login timestamp
-func (r *UserRepository) UpdateLastLogin(ctx context.Context, userID int) error {
+// ResetPassword sets a new password hash from the forgot-password flow and records when it happened
+func (r *UserRepository) ResetPassword(ctx context.Context, userID int, passwordHash string, audit *models.AuditLog) error {
 	query := `
 		UPDATE users
-		SET last_login_at = now(),
-		    failed_login_attempts = 0,
-		    locked_until = NULL,
-		    updated_at = now()
-		WHERE id = $1
+		SET password_hash = $1, password_reset_at = now(), updated_at = now()
+		WHERE id = $2
 	`
 
-	_, err := r.db.Exec(ctx, query, userID)
+	_, err := r.execAudited(ctx, audit, query, passwordHash, userID)
 	if err != nil {
-		return fmt.Errorf("failed to update last login: %w", err)
+		return fmt.Errorf("failed to reset password: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateEmail changes the user's email and marks it unverified until the new address is confirmed
+func (r *UserRepository) UpdateEmail(ctx context.Context, userID int, email string, audit *models.AuditLog) error {
+	query := `
+		UPDATE users
+		SET email = $1, is_email_verified = false, updated_at = now()
+		WHERE id = $2
+	`
+
+	_, err := r.execAudited(ctx, audit, query, email, userID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrUserAlreadyExists
+		}
+		return fmt.Errorf("failed to update email: %w", err)
+	}
+
+	return nil
+}
+
+// UpdatePhone changes the user's phone number
+func (r *UserRepository) UpdatePhone(ctx context.Context, userID int, phone string, audit *models.AuditLog) error {
+	query := `
+		UPDATE users
+		SET phone = $1, updated_at = now()
+		WHERE id = $2
+	`
+
+	_, err := r.execAudited(ctx, audit, query, phone, userID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrUserAlreadyExists
+		}
+		return fmt.Errorf("failed to update phone: %w", err)
 	}
 
 	return nil
 }
 
-// IncrementFailedLogins increments failed login attempts
-func (r *UserRepository) IncrementFailedLogins(ctx context.Context, userID int) error {
+// UpdateLastLogin updates the last login timestamp
+func (r *UserRepository) UpdateLastLogin(ctx context.Context, userID int) error {
 	query := `
 		UPDATE users
-		SET failed_login_attempts = failed_login_attempts + 1,
+		SET last_login_at = now(),
+		    failed_login_attempts = 0,
+		    locked_until = NULL,
 		    updated_at = now()
 		WHERE id = $1
 	`
 
 	_, err := r.db.Exec(ctx, query, userID)
 	if err != nil {
-		return fmt.Errorf("failed to increment failed logins: %w", err)
+		return fmt.Errorf("failed to update last login: %w", err)
 	}
 
 	return nil
 }
 
-// LockAccount locks a user account until specified time
-func (r *UserRepository) LockAccount(ctx context.Context, userID int, until time.Time) error {
+// RecordFailedLogin counts a failed sign-in and, when lockUntil is set, locks the account,
+// writing the audit entries in the same transaction
+func (r *UserRepository) RecordFailedLogin(ctx context.Context, userID int, lockUntil time.Time, audits ...*models.AuditLog) error {
+	return r.withTx(ctx, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			UPDATE users
+			SET failed_login_attempts = failed_login_attempts + 1,
+			    locked_until = COALESCE($2, locked_until),
+			    updated_at = now()
+			WHERE id = $1
+		`, userID, pgtype.Timestamptz{Time: lockUntil, Valid: !lockUntil.IsZero()})
+		if err != nil {
+			return fmt.Errorf("failed to record failed login: %w", err)
+		}
+		return nil
+	}, audits...)
+}
+
+// LockAccount locks a user account until the given time and ends their open sessions
+// Lockouts from failed sign-ins go through RecordFailedLogin instead, which leaves sessions alone
+// audit is optional and written in the same transaction
+func (r *UserRepository) LockAccount(ctx context.Context, userID int, until time.Time, audit *models.AuditLog) error {
 	query := `
 		UPDATE users
 		SET locked_until = $1, updated_at = now()
@@ -352,16 +416,21 @@ func (r *UserRepository) LockAccount(ctx context.Context, userID int, until time
 	`
 
 	lockedUntil := pgtype.Timestamptz{Time: until, Valid: true}
-	_, err := r.db.Exec(ctx, query, lockedUntil, userID)
-	if err != nil {
-		return fmt.Errorf("failed to lock account: %w", err)
-	}
-
-	return nil
+	return r.withTx(ctx, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, query, lockedUntil, userID)
+		if err != nil {
+			return fmt.Errorf("failed to lock account: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrUserNotFound
+		}
+		return endSessions(ctx, tx, userID)
+	}, audit)
 }
 
-// UnlockAccount unlocks a user account
-func (r *UserRepository) UnlockAccount(ctx context.Context, userID int) error {
+// UnlockAccount unlocks a user account and clears failed login attempts
+// audit is optional and written in the same transaction
+func (r *UserRepository) UnlockAccount(ctx context.Context, userID int, audit *models.AuditLog) error {
 	query := `
 		UPDATE users
 		SET locked_until = NULL,
@@ -370,14 +439,102 @@ func (r *UserRepository) UnlockAccount(ctx context.Context, userID int) error {
 		WHERE id = $1
 	`
 
-	_, err := r.db.Exec(ctx, query, userID)
+	tag, err := r.execAudited(ctx, audit, query, userID)
 	if err != nil {
 		return fmt.Errorf("failed to unlock account: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
 
 	return nil
 }
 
+// ==============================================
+// ROLES
+// ==============================================
+
+// SetRole changes a user's role and ends their sessions, since every
+// open access token still carries the old role
+func (r *UserRepository) SetRole(ctx context.Context, userID int, role string, audit *models.AuditLog) error {
+	return r.withTx(ctx, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE users
+			SET role = $1, updated_at = now()
+			WHERE id = $2
+		`, role, userID)
+		if err != nil {
+			return fmt.Errorf("failed to set role: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrUserNotFound
+		}
+		return endSessions(ctx, tx, userID)
+	}, audit)
+}
+
+// ==============================================
+// ADMIN SEARCH
+// ==============================================
+
+// SearchUsers matches query against name, email, phone, username and account number, newest users first
+// It also returns the total number of matches for paging
+func (r *UserRepository) SearchUsers(ctx context.Context, query string, limit, offset int) ([]models.User, int, error) {
+	sql := `
+		SELECT u.id, u.name, u.phone, u.email, u.password_hash, u.username, u.pin_hash,
+		       u.is_email_verified, u.is_active, u.onboarding_completed,
+		       u.failed_login_attempts, u.locked_until, u.role,
+		       u.created_at, u.updated_at, u.last_login_at,
+		       COUNT(*) OVER () AS total
+		FROM users u
+		LEFT JOIN accounts a ON a.user_id = u.id AND a.type = 'user'
+		WHERE $1 = ''
+		   OR u.name ILIKE '%' || $1 || '%'
+		   OR u.email ILIKE '%' || $1 || '%'
+		   OR u.username ILIKE '%' || $1 || '%'
+		   OR u.phone = $1
+		   OR a.account_number = $1
+		ORDER BY u.created_at DESC, u.id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(ctx, sql, query, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []models.User
+	total := 0
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(
+			&user.ID,
+			&user.Name,
+			&user.Phone,
+			&user.Email,
+			&user.PasswordHash,
+			&user.Username,
+			&user.PinHash,
+			&user.IsEmailVerified,
+			&user.IsActive,
+			&user.OnboardingCompleted,
+			&user.FailedLoginAttempts,
+			&user.LockedUntil,
+			&user.Role,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&user.LastLoginAt,
+			&total,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	return users, total, rows.Err()
+}
+
 // ==============================================
 // USERNAME AVAILABILITY
 // ==============================================
@@ -419,4 +576,66 @@ func (r *UserRepository) SuggestUsernames(ctx context.Context, baseUsername stri
 	}
 
 	return suggestions, nil
-}
\ No newline at end of file
+}
+
+// ==============================================
+// HELPERS
+// ==============================================
+
+// endSessions revokes every open login session of a user
+func endSessions(ctx context.Context, tx pgx.Tx, userID int) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE login_sessions
+		SET revoked_at = now()
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to end sessions: %w", err)
+	}
+	return nil
+}
+
+// execAudited runs a single statement, in a transaction with its audit entry when there is one
+func (r *UserRepository) execAudited(ctx context.Context, audit *models.AuditLog, query string, args ...any) (pgconn.CommandTag, error) {
+	if audit == nil {
+		return r.db.Exec(ctx, query, args...)
+	}
+
+	var tag pgconn.CommandTag
+	err := r.withTx(ctx, func(tx pgx.Tx) error {
+		var err error
+		tag, err = tx.Exec(ctx, query, args...)
+		return err
+	}, audit)
+	return tag, err
+}
+
+// withTx runs fn and writes the non-nil audit entries in one transaction
+func (r *UserRepository) withTx(ctx context.Context, fn func(pgx.Tx) error, audits ...*models.AuditLog) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	for _, audit := range audits {
+		if audit == nil {
+			continue
+		}
+		if err := insertAuditLog(ctx, tx, audit); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return nil
+}
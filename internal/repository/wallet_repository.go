@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Brownie44l1/debank/internal/models"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -15,8 +18,11 @@ import (
 // ==============================================
 
 var (
-	ErrAccountNotFound = errors.New("account not found")
-	ErrNoRows          = errors.New("no rows found")
+	ErrAccountNotFound         = errors.New("account not found")
+	ErrNoRows                  = errors.New("no rows found")
+	ErrDuplicateIdempotencyKey = errors.New("idempotency key already used")
+	ErrDuplicateAccount        = errors.New("an account with this external id or account number already exists")
+	ErrAlreadyReversed         = errors.New("transaction has already been reversed")
 )
 
 // ==============================================
@@ -192,6 +198,82 @@ func (r *WalletRepository) GetAccountByAccountNumber(ctx context.Context, accoun
 	return &acc, nil
 }
 
+// GetAccountByIdentifier resolves a transfer identifier to a user account (no lock)
+// Accepts @username, a 10-digit account number, a phone number or a bare username
+func (r *WalletRepository) GetAccountByIdentifier(ctx context.Context, identifier string) (*models.Account, error) {
+	identifier = strings.TrimSpace(identifier)
+
+	if strings.HasPrefix(identifier, "@") {
+		return r.getAccountByUserField(ctx, "username", strings.TrimPrefix(identifier, "@"))
+	}
+
+	if isAccountNumber(identifier) {
+		acc, err := r.GetAccountByAccountNumber(ctx, identifier)
+		if err == nil || !errors.Is(err, ErrAccountNotFound) {
+			return acc, err
+		}
+		// Fall through: a 10-digit string may also be a phone number
+	}
+
+	if strings.HasPrefix(identifier, "+") || len(identifier) >= 10 {
+		return r.getAccountByUserField(ctx, "phone", identifier)
+	}
+
+	return r.getAccountByUserField(ctx, "username", identifier)
+}
+
+// getAccountByUserField looks up a user's account via a unique column on users
+// field is never user input - only "username" or "phone"
+func (r *WalletRepository) getAccountByUserField(ctx context.Context, field, value string) (*models.Account, error) {
+	query := `
+		SELECT a.id, a.account_number, a.external_id, a.name, a.type, a.balance, a.currency, a.user_id,
+		       a.bank_code, a.bank_name, a.is_active, a.frozen_at, a.frozen_reason, a.created_at, a.updated_at
+		FROM accounts a
+		JOIN users u ON u.id = a.user_id
+		WHERE u.` + field + ` = $1
+	`
+
+	var acc models.Account
+	err := r.db.QueryRow(ctx, query, value).Scan(
+		&acc.ID,
+		&acc.AccountNumber,
+		&acc.ExternalID,
+		&acc.Name,
+		&acc.Type,
+		&acc.Balance,
+		&acc.Currency,
+		&acc.UserID,
+		&acc.BankCode,
+		&acc.BankName,
+		&acc.IsActive,
+		&acc.FrozenAt,
+		&acc.FrozenReason,
+		&acc.CreatedAt,
+		&acc.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to get account by %s: %w", field, err)
+	}
+
+	return &acc, nil
+}
+
+// isAccountNumber reports whether s looks like a 10-digit NUBAN-style account number
+func isAccountNumber(s string) bool {
+	if len(s) != 10 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // ==============================================
 // ACCOUNT QUERIES (WITH LOCKING - for updates)
 // ==============================================
@@ -393,6 +475,87 @@ func (r *WalletRepository) GetTransactionByID(ctx context.Context, txnID int64)
 	return &txn, nil
 }
 
+// GetTransactionByReference retrieves a transaction by its public reference
+func (r *WalletRepository) GetTransactionByReference(ctx context.Context, reference string) (*models.Transaction, error) {
+	query := `
+		SELECT id, idempotency_key, reference, kind, status, amount, currency,
+		       from_account_id, to_account_id, from_identifier, to_identifier,
+		       description, metadata, created_at, posted_at, failed_at, failure_reason
+		FROM transactions
+		WHERE reference = $1
+	`
+
+	var txn models.Transaction
+	err := r.db.QueryRow(ctx, query, reference).Scan(
+		&txn.ID,
+		&txn.IdempotencyKey,
+		&txn.Reference,
+		&txn.Kind,
+		&txn.Status,
+		&txn.Amount,
+		&txn.Currency,
+		&txn.FromAccountID,
+		&txn.ToAccountID,
+		&txn.FromIdentifier,
+		&txn.ToIdentifier,
+		&txn.Description,
+		&txn.Metadata,
+		&txn.CreatedAt,
+		&txn.PostedAt,
+		&txn.FailedAt,
+		&txn.FailureReason,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get transaction by reference: %w", err)
+	}
+
+	return &txn, nil
+}
+
+// GetTransactionByReferenceForUpdate retrieves and locks a transaction by its public reference
+func (r *WalletRepository) GetTransactionByReferenceForUpdate(ctx context.Context, tx pgx.Tx, reference string) (*models.Transaction, error) {
+	query := `
+		SELECT id, idempotency_key, reference, kind, status, amount, currency,
+		       from_account_id, to_account_id, from_identifier, to_identifier,
+		       description, metadata, created_at, posted_at, failed_at, failure_reason
+		FROM transactions
+		WHERE reference = $1
+		FOR UPDATE
+	`
+
+	var txn models.Transaction
+	err := tx.QueryRow(ctx, query, reference).Scan(
+		&txn.ID,
+		&txn.IdempotencyKey,
+		&txn.Reference,
+		&txn.Kind,
+		&txn.Status,
+		&txn.Amount,
+		&txn.Currency,
+		&txn.FromAccountID,
+		&txn.ToAccountID,
+		&txn.FromIdentifier,
+		&txn.ToIdentifier,
+		&txn.Description,
+		&txn.Metadata,
+		&txn.CreatedAt,
+		&txn.PostedAt,
+		&txn.FailedAt,
+		&txn.FailureReason,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to lock transaction by reference: %w", err)
+	}
+
+	return &txn, nil
+}
+
 // GetTransactionByIdempotencyKey checks if idempotency key exists
 func (r *WalletRepository) GetTransactionByIdempotencyKey(ctx context.Context, key string) (*models.Transaction, error) {
 	query := `
@@ -433,8 +596,14 @@ func (r *WalletRepository) GetTransactionByIdempotencyKey(ctx context.Context, k
 	return &txn, nil
 }
 
-// CreateTransaction creates a new transaction record within a transaction
+// CreateTransaction creates a new transaction record within a transaction and links it into the ledger hash chain
+// This holds the chain head until the transaction ends, so call it after the account locks
 func (r *WalletRepository) CreateTransaction(ctx context.Context, tx pgx.Tx, txn *models.Transaction) error {
+	prev, err := lockChainHead(ctx, tx, models.ChainTransactions)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO transactions (
 			idempotency_key, reference, kind, status, amount, currency,
@@ -442,10 +611,10 @@ func (r *WalletRepository) CreateTransaction(ctx context.Context, tx pgx.Tx, txn
 			description, metadata
 		)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-		RETURNING id, created_at
+		RETURNING id, metadata::text, created_at
 	`
 
-	err := tx.QueryRow(ctx, query,
+	err = tx.QueryRow(ctx, query,
 		txn.IdempotencyKey,
 		txn.Reference,
 		txn.Kind,
@@ -458,12 +627,42 @@ func (r *WalletRepository) CreateTransaction(ctx context.Context, tx pgx.Tx, txn
 		txn.ToIdentifier,
 		txn.Description,
 		txn.Metadata,
-	).Scan(&txn.ID, &txn.CreatedAt)
+	).Scan(&txn.ID, &txn.Metadata, &txn.CreatedAt)
 
 	if err != nil {
+		// A concurrent request with the same key committed first
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			if pgErr.ConstraintName == "idx_transactions_reversal_of" {
+				return ErrAlreadyReversed
+			}
+			return ErrDuplicateIdempotencyKey
+		}
 		return fmt.Errorf("failed to create transaction: %w", err)
 	}
 
+	txn.PrevHash = prev
+	txn.RowHash, err = sealChainRow(ctx, tx, models.ChainTransactions, txn.ID, prev, transactionChainFields(txn))
+	return err
+}
+
+// MarkTransactionReversed sets a posted transaction's status to reversed
+// Status is a lifecycle column, so the transaction's hash chain link is unaffected
+func (r *WalletRepository) MarkTransactionReversed(ctx context.Context, tx pgx.Tx, txnID int64) error {
+	query := `
+		UPDATE transactions
+		SET status = 'reversed'
+		WHERE id = $1 AND status = 'posted'
+	`
+
+	tag, err := tx.Exec(ctx, query, txnID)
+	if err != nil {
+		return fmt.Errorf("failed to mark transaction reversed: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAlreadyReversed
+	}
+
 	return nil
 }
 
@@ -526,18 +725,76 @@ func (r *WalletRepository) GetPostingsByTransactionID(ctx context.Context, txnID
 }
 
 // ==============================================
-// TRANSACTION HISTORY
+// ACCOUNT MANAGEMENT (ops)
 // ==============================================
 
-// GetTransactionHistory retrieves transaction history for a user with pagination
-func (r *WalletRepository) GetTransactionHistory(ctx context.Context, userID int, limit, offset int) ([]models.TransactionHistoryItem, error) {
-	// First, get the user's account ID
-	account, err := r.GetAccountByUserID(ctx, userID)
+// CreateSystemAccount inserts an account with no user behind it
+// System accounts are found by external_id; it doubles as their account number.
+func (r *WalletRepository) CreateSystemAccount(ctx context.Context, tx pgx.Tx, acc *models.Account) error {
+	query := `
+		INSERT INTO accounts (account_number, external_id, name, type, currency)
+		VALUES ($1, $1, $2, $3, $4)
+		RETURNING id, account_number, balance, is_active, created_at, updated_at
+	`
+
+	err := tx.QueryRow(ctx, query, acc.ExternalID, acc.Name, acc.Type, acc.Currency).Scan(
+		&acc.ID,
+		&acc.AccountNumber,
+		&acc.Balance,
+		&acc.IsActive,
+		&acc.CreatedAt,
+		&acc.UpdatedAt,
+	)
 	if err != nil {
-		return nil, err
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrDuplicateAccount
+		}
+		return fmt.Errorf("failed to create system account: %w", err)
+	}
+
+	return nil
+}
+
+// FreezeAccount blocks debits from an account until it is unfrozen
+// An account that is already frozen keeps its original time and reason
+func (r *WalletRepository) FreezeAccount(ctx context.Context, tx pgx.Tx, accountID int64, reason string) error {
+	query := `
+		UPDATE accounts
+		SET frozen_at = COALESCE(frozen_at, now()),
+		    frozen_reason = COALESCE(frozen_reason, $2)
+		WHERE id = $1
+	`
+
+	if _, err := tx.Exec(ctx, query, accountID, reason); err != nil {
+		return fmt.Errorf("failed to freeze account: %w", err)
 	}
 
+	return nil
+}
+
+// UnfreezeAccount lifts a freeze, whatever placed it
+func (r *WalletRepository) UnfreezeAccount(ctx context.Context, tx pgx.Tx, accountID int64) error {
 	query := `
+		UPDATE accounts
+		SET frozen_at = NULL, frozen_reason = NULL
+		WHERE id = $1
+	`
+
+	if _, err := tx.Exec(ctx, query, accountID); err != nil {
+		return fmt.Errorf("failed to unfreeze account: %w", err)
+	}
+
+	return nil
+}
+
+// ==============================================
+// TRANSACTION HISTORY
+// ==============================================
+
+// historySelect reads transactions as one account saw them; callers add the
+// filters after "WHERE p.account_id = $1" and the ordering
+const historySelect = `
 		SELECT 
 			t.id,
 			t.reference,
@@ -558,12 +815,69 @@ func (r *WalletRepository) GetTransactionHistory(ctx context.Context, userID int
 			AND SIGN(other_p.amount) != SIGN(p.amount)
 		LEFT JOIN accounts other_acc ON other_acc.id = other_p.account_id
 		WHERE p.account_id = $1
+	`
+
+// GetTransactionHistory retrieves transaction history for a user with pagination
+func (r *WalletRepository) GetTransactionHistory(ctx context.Context, userID int, limit, offset int) ([]models.TransactionHistoryItem, error) {
+	// First, get the user's account ID
+	account, err := r.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := historySelect + `
 			AND t.status = 'posted'
 		ORDER BY t.created_at DESC, t.id DESC
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.Query(ctx, query, account.ID, limit, offset)
+	return r.queryTransactionHistory(ctx, query, account.ID, limit, offset)
+}
+
+// GetTransactionHistoryAfter retrieves a user's posted transactions with IDs
+// above afterID, oldest first, so a reader can resume from the last ID it saw
+func (r *WalletRepository) GetTransactionHistoryAfter(ctx context.Context, userID int, afterID int64, limit int) ([]models.TransactionHistoryItem, error) {
+	account, err := r.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := historySelect + `
+			AND t.status = 'posted'
+			AND t.id > $2
+		ORDER BY t.id ASC
+		LIMIT $3
+	`
+
+	return r.queryTransactionHistory(ctx, query, account.ID, afterID, limit)
+}
+
+// GetTransactionHistoryItem retrieves one of a user's transactions by reference
+// Returns ErrNoRows when the transaction does not touch the user's account
+func (r *WalletRepository) GetTransactionHistoryItem(ctx context.Context, userID int, reference string) (*models.TransactionHistoryItem, error) {
+	account, err := r.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := historySelect + `
+			AND t.reference = $2
+		LIMIT 1
+	`
+
+	history, err := r.queryTransactionHistory(ctx, query, account.ID, reference)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, ErrNoRows
+	}
+
+	return &history[0], nil
+}
+
+func (r *WalletRepository) queryTransactionHistory(ctx context.Context, query string, args ...interface{}) ([]models.TransactionHistoryItem, error) {
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query transaction history: %w", err)
 	}
@@ -618,4 +932,91 @@ func (r *WalletRepository) CountTransactionHistory(ctx context.Context, userID i
 	}
 
 	return count, nil
-}
\ No newline at end of file
+}
+
+// ==============================================
+// DEBIT USAGE (KYC limits)
+// ==============================================
+
+// GetDebitTotalSince sums an account's debits (negative postings) since a point in time
+func (r *WalletRepository) GetDebitTotalSince(ctx context.Context, accountID int64, since time.Time) (int64, error) {
+	return sumDebitsSince(ctx, r.db.QueryRow, accountID, since)
+}
+
+// GetDebitTotalSinceTx is GetDebitTotalSince inside a transaction
+// Call it after locking the account so concurrent debits are serialised
+func (r *WalletRepository) GetDebitTotalSinceTx(ctx context.Context, tx pgx.Tx, accountID int64, since time.Time) (int64, error) {
+	return sumDebitsSince(ctx, tx.QueryRow, accountID, since)
+}
+
+func sumDebitsSince(ctx context.Context, queryRow func(context.Context, string, ...any) pgx.Row, accountID int64, since time.Time) (int64, error) {
+	query := `
+		SELECT COALESCE(SUM(-p.amount), 0)
+		FROM postings p
+		JOIN transactions t ON t.id = p.transaction_id
+		WHERE p.account_id = $1
+			AND p.amount < 0
+			AND p.created_at >= $2
+			AND t.status IN ('posted', 'pending')
+	`
+
+	var total int64
+	if err := queryRow(ctx, query, accountID, since).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to sum debits: %w", err)
+	}
+
+	return total, nil
+}
+
+// ==============================================
+// STATEMENTS
+// ==============================================
+
+// GetBalanceBefore sums an account's postings made before a point in time
+func (r *WalletRepository) GetBalanceBefore(ctx context.Context, accountID int64, before time.Time) (int64, error) {
+	query := `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM postings
+		WHERE account_id = $1 AND created_at < $2
+	`
+
+	var balance int64
+	if err := r.db.QueryRow(ctx, query, accountID, before).Scan(&balance); err != nil {
+		return 0, fmt.Errorf("failed to sum postings: %w", err)
+	}
+
+	return balance, nil
+}
+
+// GetStatementEntries retrieves an account's postings in [from, to), oldest first
+func (r *WalletRepository) GetStatementEntries(ctx context.Context, accountID int64, from, to time.Time) ([]models.StatementEntry, error) {
+	query := `
+		SELECT p.id, t.id, t.reference, t.kind, t.status, t.description, p.amount, p.currency, p.created_at
+		FROM postings p
+		JOIN transactions t ON t.id = p.transaction_id
+		WHERE p.account_id = $1 AND p.created_at >= $2 AND p.created_at < $3
+		ORDER BY p.created_at, p.id
+	`
+
+	rows, err := r.db.Query(ctx, query, accountID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query statement: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.StatementEntry
+	for rows.Next() {
+		var e models.StatementEntry
+		err := rows.Scan(&e.PostingID, &e.TransactionID, &e.Reference, &e.Kind, &e.Status, &e.Description, &e.Amount, &e.Currency, &e.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan statement entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating statement: %w", err)
+	}
+
+	return entries, nil
+}
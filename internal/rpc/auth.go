@@ -0,0 +1,168 @@
+package rpc
+
+import (
+	"context"
+	"crypto/x509"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/Brownie44l1/debank/internal/api/problem"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/Brownie44l1/debank/internal/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// ==============================================
+// AUTHENTICATION
+// ==============================================
+// Two layers: the client certificate (mTLS) says which service is calling,
+// and the access token in the "authorization" metadata says which user it
+// calls for. Both are checked before any handler runs.
+
+// TokenVerifier checks an access token's signature and claims (implemented by auth.Keyring)
+type TokenVerifier interface {
+	ParseJWT(token string) (*auth.Claims, error)
+}
+
+// SessionValidator reports whether the login session behind a token is still open (implemented by DeviceService)
+type SessionValidator interface {
+	IsSessionActive(ctx context.Context, userID int, sessionID string) (bool, error)
+}
+
+type userIDKey struct{}
+
+// userIDFromContext returns the user the call was authenticated for
+func userIDFromContext(ctx context.Context) (int, bool) {
+	userID, ok := ctx.Value(userIDKey{}).(int)
+	return userID, ok && userID > 0
+}
+
+// authenticate verifies the Bearer token and its session and returns ctx carrying the user ID
+func authenticate(ctx context.Context, tokens TokenVerifier, sessions SessionValidator) (context.Context, error) {
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			header = values[0]
+		}
+	}
+	token, found := strings.CutPrefix(header, "Bearer ")
+	if !found || token == "" {
+		return nil, unauthenticated(ctx, "missing or malformed authorization metadata")
+	}
+
+	claims, err := tokens.ParseJWT(token)
+	if err != nil {
+		return nil, unauthenticated(ctx, "invalid or expired token")
+	}
+
+	// Signing out or revoking the device ends the session before the token expires
+	active, err := sessions.IsSessionActive(ctx, claims.UserID, claims.SessionID())
+	if err != nil {
+		log.Printf("[GRPC] Session check failed - UserID: %d: %v", claims.UserID, err)
+		return nil, problemStatus(ctx, problem.Internal().WithDetail("could not verify session"))
+	}
+	if !active {
+		return nil, unauthenticated(ctx, "session has ended, please sign in again")
+	}
+
+	return context.WithValue(ctx, userIDKey{}, claims.UserID), nil
+}
+
+// authUnary authenticates every unary call
+func authUnary(tokens TokenVerifier, sessions SessionValidator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, tokens, sessions)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authStream authenticates every streaming call
+func authStream(tokens TokenVerifier, sessions SessionValidator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), tokens, sessions)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// ==============================================
+// CLIENT CERTIFICATES
+// ==============================================
+
+// clientName identifies the calling service by its verified certificate's
+// common name, or "" when the connection has no client certificate
+func clientName(ctx context.Context) string {
+	cert := clientCertificate(ctx)
+	if cert == nil {
+		return ""
+	}
+	return cert.Subject.CommonName
+}
+
+func clientCertificate(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return tlsInfo.State.VerifiedChains[0][0]
+}
+
+// allowedClient reports whether the certificate names one of the allowed services,
+// by common name or DNS subject alternative name
+func allowedClient(cert *x509.Certificate, allowed map[string]bool) bool {
+	if cert == nil {
+		return false
+	}
+	if allowed[cert.Subject.CommonName] {
+		return true
+	}
+	for _, name := range cert.DNSNames {
+		if allowed[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// clientAllowlist refuses certificates the CA issued to services not in names
+func clientAllowlist(names []string) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	allowed := make(map[string]bool, len(names))
+	for _, name := range names {
+		allowed[name] = true
+	}
+
+	check := func(ctx context.Context) error {
+		if !allowedClient(clientCertificate(ctx), allowed) {
+			return problemStatus(ctx, problem.New(http.StatusForbidden, models.ErrCodeForbidden, "Forbidden").
+				WithDetail("client certificate is not allowed to call this service"))
+		}
+		return nil
+	}
+
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := check(ctx); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := check(ss.Context()); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+	return unary, stream
+}
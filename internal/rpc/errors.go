@@ -0,0 +1,128 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/api/handlers"
+	"github.com/Brownie44l1/debank/internal/api/problem"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/requestid"
+	"github.com/Brownie44l1/debank/internal/rpc/ledgerv1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// ==============================================
+// ERROR STATUSES
+// ==============================================
+// Errors are looked up in the same registry as the HTTP API and turned into
+// a gRPC status: the HTTP status picks the gRPC code, and an ErrorInfo detail
+// carries the stable error code as its reason, so clients branch on the same
+// codes whichever API they call.
+
+// errorDomain is the ErrorInfo domain of every error this server returns
+const errorDomain = "debank"
+
+// serviceStatus turns an error from the wallet service into a status error
+func serviceStatus(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "request canceled")
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "deadline exceeded")
+	}
+
+	p, known := handlers.ServiceProblem(err)
+	if !known || p.Status >= http.StatusInternalServerError {
+		method, _ := grpc.Method(ctx)
+		log.Printf("[GRPC] %s failed - RequestID: %s: %v", method, requestid.FromContext(ctx), err)
+	}
+	return problemStatus(ctx, p)
+}
+
+// bindStatus answers InvalidArgument for a request that failed validation
+func bindStatus(ctx context.Context, err error) error {
+	return problemStatus(ctx, problem.FromBindError(err))
+}
+
+// unauthenticated answers Unauthenticated with detail as the message
+func unauthenticated(ctx context.Context, detail string) error {
+	return problemStatus(ctx, problem.New(http.StatusUnauthorized, models.ErrCodeUnauthorized, "Unauthorized").WithDetail(detail))
+}
+
+// problemStatus builds the status for p with its details attached
+func problemStatus(ctx context.Context, p problem.Problem) error {
+	message := p.Title
+	if p.Detail != "" {
+		message += ": " + p.Detail
+	}
+	st := status.New(codeFor(p.Status), message)
+
+	info := &errdetails.ErrorInfo{Reason: p.Code, Domain: errorDomain}
+	if id := requestid.FromContext(ctx); id != "" {
+		info.Metadata = map[string]string{"request_id": id}
+	}
+	details := []protoadapt.MessageV1{info}
+
+	if len(p.Errors) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, fe := range p.Errors {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       fe.Field,
+				Description: fe.Message,
+			})
+		}
+		details = append(details, badRequest)
+	}
+
+	if challenge, ok := p.Extensions["step_up"].(*dto.StepUpChallengeResponse); ok && challenge != nil {
+		details = append(details, &ledgerv1.StepUpChallenge{
+			Token:     challenge.Token,
+			Action:    challenge.Action,
+			Method:    challenge.Method,
+			Email:     challenge.Email,
+			ExpiresIn: int32(challenge.ExpiresIn),
+		})
+	}
+
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		log.Printf("[GRPC] Attaching error details failed - Code: %s: %v", p.Code, err)
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// codeFor picks the gRPC code for an HTTP status
+// Several errors share a code; the ErrorInfo reason tells them apart.
+func codeFor(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusUnprocessableEntity:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	}
+	if httpStatus >= http.StatusInternalServerError {
+		return codes.Internal
+	}
+	return codes.FailedPrecondition
+}
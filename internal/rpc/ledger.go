@@ -0,0 +1,251 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/api/problem"
+	"github.com/Brownie44l1/debank/internal/rpc/ledgerv1"
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ==============================================
+// SERVICE INTERFACE (for testing)
+// ==============================================
+
+// Wallet is what the ledger API needs from the wallet (implemented by WalletService)
+type Wallet interface {
+	Deposit(ctx context.Context, userID int, req dto.DepositRequest) (*dto.TransactionResponse, error)
+	Withdraw(ctx context.Context, userID int, req dto.WithdrawRequest) (*dto.TransactionResponse, error)
+	Transfer(ctx context.Context, userID int, req dto.TransferRequest) (*dto.TransferResponse, error)
+	GetBalance(ctx context.Context, userID int) (*dto.BalanceResponse, error)
+	GetTransaction(ctx context.Context, userID int, reference string) (*dto.TransactionHistoryItem, error)
+	ListTransactionsAfter(ctx context.Context, userID int, afterID int64, limit int) ([]dto.TransactionHistoryItem, error)
+}
+
+// ==============================================
+// LEDGER SERVICE
+// ==============================================
+// Requests are converted to the HTTP API's DTOs and validated with the same
+// binding rules, so both APIs accept exactly the same input.
+
+// streamPageSize is how many transactions StreamTransactions reads per query
+const streamPageSize = 100
+
+// LedgerServer implements ledgerv1.LedgerServiceServer over the wallet service
+type LedgerServer struct {
+	ledgerv1.UnimplementedLedgerServiceServer
+	wallet Wallet
+}
+
+func NewLedgerServer(wallet Wallet) *LedgerServer {
+	return &LedgerServer{wallet: wallet}
+}
+
+// Deposit credits the caller's account
+func (s *LedgerServer) Deposit(ctx context.Context, in *ledgerv1.DepositRequest) (*ledgerv1.TransactionResult, error) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return nil, unauthenticated(ctx, "authentication required")
+	}
+
+	req := dto.DepositRequest{
+		Amount:         in.GetAmount(),
+		IdempotencyKey: in.GetIdempotencyKey(),
+		Reference:      in.GetReference(),
+	}
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return nil, bindStatus(ctx, err)
+	}
+
+	resp, err := s.wallet.Deposit(ctx, userID, req)
+	if err != nil {
+		return nil, serviceStatus(ctx, err)
+	}
+	return toTransactionResult(resp), nil
+}
+
+// Withdraw debits the caller's account
+func (s *LedgerServer) Withdraw(ctx context.Context, in *ledgerv1.WithdrawRequest) (*ledgerv1.TransactionResult, error) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return nil, unauthenticated(ctx, "authentication required")
+	}
+
+	req := dto.WithdrawRequest{
+		Amount:         in.GetAmount(),
+		Pin:            in.GetPin(),
+		IdempotencyKey: in.GetIdempotencyKey(),
+		Reference:      in.GetReference(),
+		StepUp:         toStepUpProof(in.GetStepUp()),
+	}
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return nil, bindStatus(ctx, err)
+	}
+
+	resp, err := s.wallet.Withdraw(ctx, userID, req)
+	if err != nil {
+		return nil, serviceStatus(ctx, err)
+	}
+	return toTransactionResult(resp), nil
+}
+
+// Transfer moves money from the caller's account to another customer's
+func (s *LedgerServer) Transfer(ctx context.Context, in *ledgerv1.TransferRequest) (*ledgerv1.TransferResult, error) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return nil, unauthenticated(ctx, "authentication required")
+	}
+
+	req := dto.TransferRequest{
+		ToIdentifier:   in.GetToIdentifier(),
+		Amount:         in.GetAmount(),
+		Pin:            in.GetPin(),
+		IdempotencyKey: in.GetIdempotencyKey(),
+		Description:    in.GetDescription(),
+		StepUp:         toStepUpProof(in.GetStepUp()),
+	}
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return nil, bindStatus(ctx, err)
+	}
+
+	resp, err := s.wallet.Transfer(ctx, userID, req)
+	if err != nil {
+		return nil, serviceStatus(ctx, err)
+	}
+	return &ledgerv1.TransferResult{
+		TransactionId:    resp.TransactionID,
+		Reference:        resp.Reference,
+		Status:           resp.Status,
+		SenderBalance:    resp.SenderBalance,
+		RecipientBalance: resp.RecipientBalance,
+		Message:          resp.Message,
+	}, nil
+}
+
+// GetBalance returns the caller's balance
+func (s *LedgerServer) GetBalance(ctx context.Context, _ *ledgerv1.GetBalanceRequest) (*ledgerv1.Balance, error) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return nil, unauthenticated(ctx, "authentication required")
+	}
+
+	resp, err := s.wallet.GetBalance(ctx, userID)
+	if err != nil {
+		return nil, serviceStatus(ctx, err)
+	}
+	return &ledgerv1.Balance{
+		UserId:        int64(resp.UserID),
+		AccountNumber: resp.AccountNumber,
+		Balance:       resp.Balance,
+		Currency:      resp.Currency,
+	}, nil
+}
+
+// GetTransaction returns one of the caller's transactions by reference
+func (s *LedgerServer) GetTransaction(ctx context.Context, in *ledgerv1.GetTransactionRequest) (*ledgerv1.Transaction, error) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return nil, unauthenticated(ctx, "authentication required")
+	}
+	if in.GetReference() == "" {
+		return nil, bindStatus(ctx, &problem.FieldError{Field: "reference", Code: "required", Message: "is required"})
+	}
+
+	item, err := s.wallet.GetTransaction(ctx, userID, in.GetReference())
+	if err != nil {
+		return nil, serviceStatus(ctx, err)
+	}
+	return toTransaction(ctx, item)
+}
+
+// StreamTransactions sends the caller's posted transactions after in.AfterId
+// a page at a time, and returns once a page comes back short
+func (s *LedgerServer) StreamTransactions(in *ledgerv1.StreamTransactionsRequest, stream grpc.ServerStreamingServer[ledgerv1.Transaction]) error {
+	ctx := stream.Context()
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return unauthenticated(ctx, "authentication required")
+	}
+	if in.GetAfterId() < 0 {
+		return bindStatus(ctx, &problem.FieldError{Field: "after_id", Code: "gte", Message: "must be at least 0"})
+	}
+
+	afterID := in.GetAfterId()
+	for {
+		page, err := s.wallet.ListTransactionsAfter(ctx, userID, afterID, streamPageSize)
+		if err != nil {
+			return serviceStatus(ctx, err)
+		}
+
+		for i := range page {
+			txn, err := toTransaction(ctx, &page[i])
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(txn); err != nil {
+				return err
+			}
+			afterID = page[i].ID
+		}
+
+		if len(page) < streamPageSize {
+			return nil
+		}
+	}
+}
+
+// ==============================================
+// CONVERSIONS
+// ==============================================
+
+func toStepUpProof(in *ledgerv1.StepUpProof) dto.StepUpProof {
+	return dto.StepUpProof{Token: in.GetToken(), Code: in.GetCode()}
+}
+
+func toTransactionResult(resp *dto.TransactionResponse) *ledgerv1.TransactionResult {
+	return &ledgerv1.TransactionResult{
+		TransactionId: resp.TransactionID,
+		Reference:     resp.Reference,
+		Status:        resp.Status,
+		Balance:       resp.Balance,
+		Message:       resp.Message,
+	}
+}
+
+func toTransaction(ctx context.Context, item *dto.TransactionHistoryItem) (*ledgerv1.Transaction, error) {
+	createdAt, err := time.Parse(time.RFC3339, item.CreatedAt)
+	if err != nil {
+		return nil, serviceStatus(ctx, err)
+	}
+
+	txn := &ledgerv1.Transaction{
+		Id:        item.ID,
+		Reference: item.Reference,
+		Type:      item.Type,
+		Status:    item.Status,
+		Amount:    item.Amount,
+		Direction: toDirection(item.Direction),
+		CreatedAt: timestamppb.New(createdAt),
+	}
+	if item.Description != nil {
+		txn.Description = *item.Description
+	}
+	if item.Counterparty != nil {
+		txn.Counterparty = *item.Counterparty
+	}
+	return txn, nil
+}
+
+func toDirection(direction string) ledgerv1.Direction {
+	switch direction {
+	case "credit":
+		return ledgerv1.Direction_DIRECTION_CREDIT
+	case "debit":
+		return ledgerv1.Direction_DIRECTION_DEBIT
+	}
+	return ledgerv1.Direction_DIRECTION_UNSPECIFIED
+}
@@ -0,0 +1,268 @@
+package rpc
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/rpc/ledgerv1"
+	"github.com/Brownie44l1/debank/internal/service"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const validToken = "valid-token"
+
+// fakeTokens accepts validToken for user 7 in session "s1"
+type fakeTokens struct{}
+
+func (fakeTokens) ParseJWT(token string) (*auth.Claims, error) {
+	if token != validToken {
+		return nil, errors.New("bad token")
+	}
+	return &auth.Claims{UserID: 7, RegisteredClaims: jwt.RegisteredClaims{ID: "s1"}}, nil
+}
+
+type fakeSessions struct{ active bool }
+
+func (s fakeSessions) IsSessionActive(ctx context.Context, userID int, sessionID string) (bool, error) {
+	return s.active && userID == 7 && sessionID == "s1", nil
+}
+
+// fakeWallet records the user it was called for and returns err when set
+type fakeWallet struct {
+	userID       int
+	err          error
+	transactions []dto.TransactionHistoryItem
+	pages        int
+}
+
+func (w *fakeWallet) Deposit(ctx context.Context, userID int, req dto.DepositRequest) (*dto.TransactionResponse, error) {
+	w.userID = userID
+	if w.err != nil {
+		return nil, w.err
+	}
+	return &dto.TransactionResponse{TransactionID: 1, Reference: "TXN-1", Status: "posted", Balance: req.Amount}, nil
+}
+
+func (w *fakeWallet) Withdraw(ctx context.Context, userID int, req dto.WithdrawRequest) (*dto.TransactionResponse, error) {
+	w.userID = userID
+	return nil, w.err
+}
+
+func (w *fakeWallet) Transfer(ctx context.Context, userID int, req dto.TransferRequest) (*dto.TransferResponse, error) {
+	w.userID = userID
+	return nil, w.err
+}
+
+func (w *fakeWallet) GetBalance(ctx context.Context, userID int) (*dto.BalanceResponse, error) {
+	w.userID = userID
+	return &dto.BalanceResponse{UserID: userID, AccountNumber: "0123456789", Balance: 500, Currency: "NGN"}, w.err
+}
+
+func (w *fakeWallet) GetTransaction(ctx context.Context, userID int, reference string) (*dto.TransactionHistoryItem, error) {
+	for i := range w.transactions {
+		if w.transactions[i].Reference == reference {
+			return &w.transactions[i], nil
+		}
+	}
+	return nil, service.ErrTransactionNotFound
+}
+
+func (w *fakeWallet) ListTransactionsAfter(ctx context.Context, userID int, afterID int64, limit int) ([]dto.TransactionHistoryItem, error) {
+	w.pages++
+	var page []dto.TransactionHistoryItem
+	for _, txn := range w.transactions {
+		if txn.ID > afterID && len(page) < limit {
+			page = append(page, txn)
+		}
+	}
+	return page, nil
+}
+
+// dial serves the ledger in memory and returns a client for it
+func dial(t *testing.T, wallet Wallet) ledgerv1.LedgerServiceClient {
+	t.Helper()
+	srv, err := NewServer(Config{Insecure: true}, NewLedgerServer(wallet), fakeTokens{}, fakeSessions{active: true})
+	require.NoError(t, err)
+
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return ledgerv1.NewLedgerServiceClient(conn)
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+// errorInfo returns the ErrorInfo detail of a status error
+func errorInfo(t *testing.T, err error) *errdetails.ErrorInfo {
+	t.Helper()
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	t.Fatalf("no ErrorInfo in %v", err)
+	return nil
+}
+
+func TestLedger_RequiresToken(t *testing.T) {
+	client := dial(t, &fakeWallet{})
+
+	_, err := client.GetBalance(context.Background(), &ledgerv1.GetBalanceRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, models.ErrCodeUnauthorized, errorInfo(t, err).Reason)
+
+	_, err = client.GetBalance(withToken("forged"), &ledgerv1.GetBalanceRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestLedger_DepositActsForTokenUser(t *testing.T) {
+	wallet := &fakeWallet{}
+	client := dial(t, wallet)
+
+	var header metadata.MD
+	resp, err := client.Deposit(withToken(validToken), &ledgerv1.DepositRequest{Amount: 1000, IdempotencyKey: "k1"}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, 7, wallet.userID)
+	assert.Equal(t, int64(1000), resp.Balance)
+	assert.NotEmpty(t, header.Get("x-request-id"))
+}
+
+func TestLedger_ValidationUsesBindingRules(t *testing.T) {
+	client := dial(t, &fakeWallet{})
+
+	_, err := client.Withdraw(withToken(validToken), &ledgerv1.WithdrawRequest{Amount: 100, Pin: "12", IdempotencyKey: "k1"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, models.ErrCodeValidationFailed, errorInfo(t, err).Reason)
+
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, d := range status.Convert(err).Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			violations = br.FieldViolations
+		}
+	}
+	require.Len(t, violations, 1)
+	assert.Equal(t, "pin", violations[0].Field)
+}
+
+func TestLedger_ServiceErrors(t *testing.T) {
+	tests := []struct {
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{service.ErrInsufficientBalance, codes.FailedPrecondition, models.ErrCodeInsufficientBalance},
+		{fmt.Errorf("wrapped: %w", service.ErrRecipientNotFound), codes.NotFound, models.ErrCodeRecipientNotFound},
+		{service.ErrIdempotencyConflict, codes.Aborted, models.ErrCodeIdempotencyConflict},
+		{errors.New("connection reset"), codes.Internal, models.ErrCodeInternalError},
+	}
+	for _, tt := range tests {
+		client := dial(t, &fakeWallet{err: tt.err})
+		_, err := client.Transfer(withToken(validToken), &ledgerv1.TransferRequest{
+			ToIdentifier: "@ada", Amount: 100, Pin: "1234", IdempotencyKey: "k1",
+		})
+		assert.Equal(t, tt.code, status.Code(err), tt.err)
+		info := errorInfo(t, err)
+		assert.Equal(t, tt.reason, info.Reason)
+		assert.Equal(t, errorDomain, info.Domain)
+		assert.NotEmpty(t, info.Metadata["request_id"])
+	}
+}
+
+func TestLedger_StepUpChallengeDetail(t *testing.T) {
+	challenge := &dto.StepUpChallengeResponse{Token: "su-1", Action: "transfer", Method: "totp", ExpiresIn: 300}
+	client := dial(t, &fakeWallet{err: &service.StepUpRequiredError{Challenge: challenge}})
+
+	_, err := client.Withdraw(withToken(validToken), &ledgerv1.WithdrawRequest{Amount: 100, Pin: "1234", IdempotencyKey: "k1"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, models.ErrCodeStepUpRequired, errorInfo(t, err).Reason)
+
+	var got *ledgerv1.StepUpChallenge
+	for _, d := range status.Convert(err).Details() {
+		if c, ok := d.(*ledgerv1.StepUpChallenge); ok {
+			got = c
+		}
+	}
+	require.NotNil(t, got)
+	assert.Equal(t, "su-1", got.Token)
+	assert.Equal(t, int32(300), got.ExpiresIn)
+}
+
+func TestLedger_StreamTransactionsPagesUntilCaughtUp(t *testing.T) {
+	wallet := &fakeWallet{}
+	for i := 1; i <= streamPageSize+5; i++ {
+		wallet.transactions = append(wallet.transactions, dto.TransactionHistoryItem{
+			ID: int64(i), Reference: fmt.Sprintf("TXN-%d", i), Direction: "credit", CreatedAt: "2026-01-02T15:04:05Z",
+		})
+	}
+	client := dial(t, wallet)
+
+	stream, err := client.StreamTransactions(withToken(validToken), &ledgerv1.StreamTransactionsRequest{AfterId: 3})
+	require.NoError(t, err)
+
+	var ids []int64
+	for {
+		txn, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Equal(t, ledgerv1.Direction_DIRECTION_CREDIT, txn.Direction)
+		ids = append(ids, txn.Id)
+	}
+	require.Len(t, ids, streamPageSize+2)
+	assert.Equal(t, int64(4), ids[0])
+	assert.Equal(t, int64(streamPageSize+5), ids[len(ids)-1])
+	assert.Equal(t, 2, wallet.pages)
+}
+
+func TestLedger_GetTransactionNotFound(t *testing.T) {
+	client := dial(t, &fakeWallet{})
+
+	_, err := client.GetTransaction(withToken(validToken), &ledgerv1.GetTransactionRequest{Reference: "TXN-404"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCodeFor(t *testing.T) {
+	assert.Equal(t, codes.InvalidArgument, codeFor(http.StatusBadRequest))
+	assert.Equal(t, codes.PermissionDenied, codeFor(http.StatusForbidden))
+	assert.Equal(t, codes.ResourceExhausted, codeFor(http.StatusTooManyRequests))
+	assert.Equal(t, codes.Unavailable, codeFor(http.StatusServiceUnavailable))
+	assert.Equal(t, codes.Internal, codeFor(http.StatusBadGateway))
+}
+
+func TestAllowedClient(t *testing.T) {
+	allowed := map[string]bool{"payouts": true}
+
+	assert.True(t, allowedClient(&x509.Certificate{Subject: pkix.Name{CommonName: "payouts"}}, allowed))
+	assert.True(t, allowedClient(&x509.Certificate{DNSNames: []string{"payouts"}}, allowed))
+	assert.False(t, allowedClient(&x509.Certificate{Subject: pkix.Name{CommonName: "reports"}}, allowed))
+	assert.False(t, allowedClient(nil, allowed))
+}
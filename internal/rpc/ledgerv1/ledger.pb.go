@@ -0,0 +1,1042 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: debank/ledger/v1/ledger.proto
+
+package ledgerv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Direction int32
+
+const (
+	Direction_DIRECTION_UNSPECIFIED Direction = 0
+	Direction_DIRECTION_CREDIT      Direction = 1
+	Direction_DIRECTION_DEBIT       Direction = 2
+)
+
+// Enum value maps for Direction.
+var (
+	Direction_name = map[int32]string{
+		0: "DIRECTION_UNSPECIFIED",
+		1: "DIRECTION_CREDIT",
+		2: "DIRECTION_DEBIT",
+	}
+	Direction_value = map[string]int32{
+		"DIRECTION_UNSPECIFIED": 0,
+		"DIRECTION_CREDIT":      1,
+		"DIRECTION_DEBIT":       2,
+	}
+)
+
+func (x Direction) Enum() *Direction {
+	p := new(Direction)
+	*p = x
+	return p
+}
+
+func (x Direction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Direction) Descriptor() protoreflect.EnumDescriptor {
+	return file_debank_ledger_v1_ledger_proto_enumTypes[0].Descriptor()
+}
+
+func (Direction) Type() protoreflect.EnumType {
+	return &file_debank_ledger_v1_ledger_proto_enumTypes[0]
+}
+
+func (x Direction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Direction.Descriptor instead.
+func (Direction) EnumDescriptor() ([]byte, []int) {
+	return file_debank_ledger_v1_ledger_proto_rawDescGZIP(), []int{0}
+}
+
+// StepUpProof answers a StepUpChallenge when the first attempt was refused
+type StepUpProof struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"` // 6 digits from the authenticator app or the email
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StepUpProof) Reset() {
+	*x = StepUpProof{}
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StepUpProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StepUpProof) ProtoMessage() {}
+
+func (x *StepUpProof) ProtoReflect() protoreflect.Message {
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StepUpProof.ProtoReflect.Descriptor instead.
+func (*StepUpProof) Descriptor() ([]byte, []int) {
+	return file_debank_ledger_v1_ledger_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StepUpProof) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *StepUpProof) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type DepositRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Amount         int64                  `protobuf:"varint,1,opt,name=amount,proto3" json:"amount,omitempty"` // In kobo
+	IdempotencyKey string                 `protobuf:"bytes,2,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	Reference      string                 `protobuf:"bytes,3,opt,name=reference,proto3" json:"reference,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DepositRequest) Reset() {
+	*x = DepositRequest{}
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DepositRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepositRequest) ProtoMessage() {}
+
+func (x *DepositRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepositRequest.ProtoReflect.Descriptor instead.
+func (*DepositRequest) Descriptor() ([]byte, []int) {
+	return file_debank_ledger_v1_ledger_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DepositRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *DepositRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *DepositRequest) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+type WithdrawRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Amount         int64                  `protobuf:"varint,1,opt,name=amount,proto3" json:"amount,omitempty"` // In kobo
+	Pin            string                 `protobuf:"bytes,2,opt,name=pin,proto3" json:"pin,omitempty"`
+	IdempotencyKey string                 `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	Reference      string                 `protobuf:"bytes,4,opt,name=reference,proto3" json:"reference,omitempty"`
+	StepUp         *StepUpProof           `protobuf:"bytes,5,opt,name=step_up,json=stepUp,proto3" json:"step_up,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WithdrawRequest) Reset() {
+	*x = WithdrawRequest{}
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WithdrawRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WithdrawRequest) ProtoMessage() {}
+
+func (x *WithdrawRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WithdrawRequest.ProtoReflect.Descriptor instead.
+func (*WithdrawRequest) Descriptor() ([]byte, []int) {
+	return file_debank_ledger_v1_ledger_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *WithdrawRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *WithdrawRequest) GetPin() string {
+	if x != nil {
+		return x.Pin
+	}
+	return ""
+}
+
+func (x *WithdrawRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *WithdrawRequest) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *WithdrawRequest) GetStepUp() *StepUpProof {
+	if x != nil {
+		return x.StepUp
+	}
+	return nil
+}
+
+type TransferRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ToIdentifier   string                 `protobuf:"bytes,1,opt,name=to_identifier,json=toIdentifier,proto3" json:"to_identifier,omitempty"` // @username, phone or account number
+	Amount         int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`                                // In kobo
+	Pin            string                 `protobuf:"bytes,3,opt,name=pin,proto3" json:"pin,omitempty"`
+	IdempotencyKey string                 `protobuf:"bytes,4,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	Description    string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	StepUp         *StepUpProof           `protobuf:"bytes,6,opt,name=step_up,json=stepUp,proto3" json:"step_up,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TransferRequest) Reset() {
+	*x = TransferRequest{}
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferRequest) ProtoMessage() {}
+
+func (x *TransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferRequest.ProtoReflect.Descriptor instead.
+func (*TransferRequest) Descriptor() ([]byte, []int) {
+	return file_debank_ledger_v1_ledger_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TransferRequest) GetToIdentifier() string {
+	if x != nil {
+		return x.ToIdentifier
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *TransferRequest) GetPin() string {
+	if x != nil {
+		return x.Pin
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetStepUp() *StepUpProof {
+	if x != nil {
+		return x.StepUp
+	}
+	return nil
+}
+
+type TransactionResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId int64                  `protobuf:"varint,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Reference     string                 `protobuf:"bytes,2,opt,name=reference,proto3" json:"reference,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Balance       int64                  `protobuf:"varint,4,opt,name=balance,proto3" json:"balance,omitempty"` // New balance in kobo
+	Message       string                 `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransactionResult) Reset() {
+	*x = TransactionResult{}
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransactionResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionResult) ProtoMessage() {}
+
+func (x *TransactionResult) ProtoReflect() protoreflect.Message {
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionResult.ProtoReflect.Descriptor instead.
+func (*TransactionResult) Descriptor() ([]byte, []int) {
+	return file_debank_ledger_v1_ledger_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TransactionResult) GetTransactionId() int64 {
+	if x != nil {
+		return x.TransactionId
+	}
+	return 0
+}
+
+func (x *TransactionResult) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *TransactionResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TransactionResult) GetBalance() int64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *TransactionResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type TransferResult struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId    int64                  `protobuf:"varint,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Reference        string                 `protobuf:"bytes,2,opt,name=reference,proto3" json:"reference,omitempty"`
+	Status           string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	SenderBalance    int64                  `protobuf:"varint,4,opt,name=sender_balance,json=senderBalance,proto3" json:"sender_balance,omitempty"`          // In kobo
+	RecipientBalance int64                  `protobuf:"varint,5,opt,name=recipient_balance,json=recipientBalance,proto3" json:"recipient_balance,omitempty"` // In kobo
+	Message          string                 `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TransferResult) Reset() {
+	*x = TransferResult{}
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferResult) ProtoMessage() {}
+
+func (x *TransferResult) ProtoReflect() protoreflect.Message {
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferResult.ProtoReflect.Descriptor instead.
+func (*TransferResult) Descriptor() ([]byte, []int) {
+	return file_debank_ledger_v1_ledger_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TransferResult) GetTransactionId() int64 {
+	if x != nil {
+		return x.TransactionId
+	}
+	return 0
+}
+
+func (x *TransferResult) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *TransferResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TransferResult) GetSenderBalance() int64 {
+	if x != nil {
+		return x.SenderBalance
+	}
+	return 0
+}
+
+func (x *TransferResult) GetRecipientBalance() int64 {
+	if x != nil {
+		return x.RecipientBalance
+	}
+	return 0
+}
+
+func (x *TransferResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type GetBalanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalanceRequest) Reset() {
+	*x = GetBalanceRequest{}
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalanceRequest) ProtoMessage() {}
+
+func (x *GetBalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalanceRequest.ProtoReflect.Descriptor instead.
+func (*GetBalanceRequest) Descriptor() ([]byte, []int) {
+	return file_debank_ledger_v1_ledger_proto_rawDescGZIP(), []int{6}
+}
+
+type Balance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AccountNumber string                 `protobuf:"bytes,2,opt,name=account_number,json=accountNumber,proto3" json:"account_number,omitempty"`
+	Balance       int64                  `protobuf:"varint,3,opt,name=balance,proto3" json:"balance,omitempty"` // In kobo
+	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Balance) Reset() {
+	*x = Balance{}
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Balance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Balance) ProtoMessage() {}
+
+func (x *Balance) ProtoReflect() protoreflect.Message {
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Balance.ProtoReflect.Descriptor instead.
+func (*Balance) Descriptor() ([]byte, []int) {
+	return file_debank_ledger_v1_ledger_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Balance) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *Balance) GetAccountNumber() string {
+	if x != nil {
+		return x.AccountNumber
+	}
+	return ""
+}
+
+func (x *Balance) GetBalance() int64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *Balance) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type GetTransactionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reference     string                 `protobuf:"bytes,1,opt,name=reference,proto3" json:"reference,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTransactionRequest) Reset() {
+	*x = GetTransactionRequest{}
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTransactionRequest) ProtoMessage() {}
+
+func (x *GetTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTransactionRequest.ProtoReflect.Descriptor instead.
+func (*GetTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_debank_ledger_v1_ledger_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetTransactionRequest) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+type StreamTransactionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AfterId       int64                  `protobuf:"varint,1,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"` // 0 starts with the oldest transaction
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamTransactionsRequest) Reset() {
+	*x = StreamTransactionsRequest{}
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamTransactionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamTransactionsRequest) ProtoMessage() {}
+
+func (x *StreamTransactionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamTransactionsRequest.ProtoReflect.Descriptor instead.
+func (*StreamTransactionsRequest) Descriptor() ([]byte, []int) {
+	return file_debank_ledger_v1_ledger_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StreamTransactionsRequest) GetAfterId() int64 {
+	if x != nil {
+		return x.AfterId
+	}
+	return 0
+}
+
+// Transaction is a posted transaction as the caller's account saw it
+type Transaction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reference     string                 `protobuf:"bytes,2,opt,name=reference,proto3" json:"reference,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"` // e.g. "deposit", "withdrawal", "p2p"
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Amount        int64                  `protobuf:"varint,5,opt,name=amount,proto3" json:"amount,omitempty"` // In kobo
+	Description   string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	Direction     Direction              `protobuf:"varint,7,opt,name=direction,proto3,enum=debank.ledger.v1.Direction" json:"direction,omitempty"`
+	Counterparty  string                 `protobuf:"bytes,8,opt,name=counterparty,proto3" json:"counterparty,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Transaction) Reset() {
+	*x = Transaction{}
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transaction) ProtoMessage() {}
+
+func (x *Transaction) ProtoReflect() protoreflect.Message {
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transaction.ProtoReflect.Descriptor instead.
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return file_debank_ledger_v1_ledger_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Transaction) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Transaction) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *Transaction) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Transaction) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Transaction) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Transaction) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Transaction) GetDirection() Direction {
+	if x != nil {
+		return x.Direction
+	}
+	return Direction_DIRECTION_UNSPECIFIED
+}
+
+func (x *Transaction) GetCounterparty() string {
+	if x != nil {
+		return x.Counterparty
+	}
+	return ""
+}
+
+func (x *Transaction) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// StepUpChallenge is attached to a STEP_UP_REQUIRED error; repeat the call
+// with step_up.token and a code within expires_in seconds
+type StepUpChallenge struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Action        string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	Method        string                 `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"` // "totp" or "email"
+	Email         string                 `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`   // Masked; set when a code was emailed
+	ExpiresIn     int32                  `protobuf:"varint,5,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StepUpChallenge) Reset() {
+	*x = StepUpChallenge{}
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StepUpChallenge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StepUpChallenge) ProtoMessage() {}
+
+func (x *StepUpChallenge) ProtoReflect() protoreflect.Message {
+	mi := &file_debank_ledger_v1_ledger_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StepUpChallenge.ProtoReflect.Descriptor instead.
+func (*StepUpChallenge) Descriptor() ([]byte, []int) {
+	return file_debank_ledger_v1_ledger_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *StepUpChallenge) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *StepUpChallenge) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *StepUpChallenge) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *StepUpChallenge) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *StepUpChallenge) GetExpiresIn() int32 {
+	if x != nil {
+		return x.ExpiresIn
+	}
+	return 0
+}
+
+var File_debank_ledger_v1_ledger_proto protoreflect.FileDescriptor
+
+const file_debank_ledger_v1_ledger_proto_rawDesc = "" +
+	"\n" +
+	"\x1ddebank/ledger/v1/ledger.proto\x12\x10debank.ledger.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"7\n" +
+	"\vStepUpProof\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\"o\n" +
+	"\x0eDepositRequest\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x03R\x06amount\x12'\n" +
+	"\x0fidempotency_key\x18\x02 \x01(\tR\x0eidempotencyKey\x12\x1c\n" +
+	"\treference\x18\x03 \x01(\tR\treference\"\xba\x01\n" +
+	"\x0fWithdrawRequest\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x03R\x06amount\x12\x10\n" +
+	"\x03pin\x18\x02 \x01(\tR\x03pin\x12'\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\x12\x1c\n" +
+	"\treference\x18\x04 \x01(\tR\treference\x126\n" +
+	"\astep_up\x18\x05 \x01(\v2\x1d.debank.ledger.v1.StepUpProofR\x06stepUp\"\xe3\x01\n" +
+	"\x0fTransferRequest\x12#\n" +
+	"\rto_identifier\x18\x01 \x01(\tR\ftoIdentifier\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\x12\x10\n" +
+	"\x03pin\x18\x03 \x01(\tR\x03pin\x12'\n" +
+	"\x0fidempotency_key\x18\x04 \x01(\tR\x0eidempotencyKey\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x126\n" +
+	"\astep_up\x18\x06 \x01(\v2\x1d.debank.ledger.v1.StepUpProofR\x06stepUp\"\xa4\x01\n" +
+	"\x11TransactionResult\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\x03R\rtransactionId\x12\x1c\n" +
+	"\treference\x18\x02 \x01(\tR\treference\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x18\n" +
+	"\abalance\x18\x04 \x01(\x03R\abalance\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\"\xdb\x01\n" +
+	"\x0eTransferResult\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\x03R\rtransactionId\x12\x1c\n" +
+	"\treference\x18\x02 \x01(\tR\treference\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12%\n" +
+	"\x0esender_balance\x18\x04 \x01(\x03R\rsenderBalance\x12+\n" +
+	"\x11recipient_balance\x18\x05 \x01(\x03R\x10recipientBalance\x12\x18\n" +
+	"\amessage\x18\x06 \x01(\tR\amessage\"\x13\n" +
+	"\x11GetBalanceRequest\"\x7f\n" +
+	"\aBalance\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12%\n" +
+	"\x0eaccount_number\x18\x02 \x01(\tR\raccountNumber\x12\x18\n" +
+	"\abalance\x18\x03 \x01(\x03R\abalance\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\"5\n" +
+	"\x15GetTransactionRequest\x12\x1c\n" +
+	"\treference\x18\x01 \x01(\tR\treference\"6\n" +
+	"\x19StreamTransactionsRequest\x12\x19\n" +
+	"\bafter_id\x18\x01 \x01(\x03R\aafterId\"\xbb\x02\n" +
+	"\vTransaction\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1c\n" +
+	"\treference\x18\x02 \x01(\tR\treference\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x16\n" +
+	"\x06amount\x18\x05 \x01(\x03R\x06amount\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\x129\n" +
+	"\tdirection\x18\a \x01(\x0e2\x1b.debank.ledger.v1.DirectionR\tdirection\x12\"\n" +
+	"\fcounterparty\x18\b \x01(\tR\fcounterparty\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x8c\x01\n" +
+	"\x0fStepUpChallenge\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x16\n" +
+	"\x06method\x18\x03 \x01(\tR\x06method\x12\x14\n" +
+	"\x05email\x18\x04 \x01(\tR\x05email\x12\x1d\n" +
+	"\n" +
+	"expires_in\x18\x05 \x01(\x05R\texpiresIn*Q\n" +
+	"\tDirection\x12\x19\n" +
+	"\x15DIRECTION_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10DIRECTION_CREDIT\x10\x01\x12\x13\n" +
+	"\x0fDIRECTION_DEBIT\x10\x022\x92\x04\n" +
+	"\rLedgerService\x12P\n" +
+	"\aDeposit\x12 .debank.ledger.v1.DepositRequest\x1a#.debank.ledger.v1.TransactionResult\x12R\n" +
+	"\bWithdraw\x12!.debank.ledger.v1.WithdrawRequest\x1a#.debank.ledger.v1.TransactionResult\x12O\n" +
+	"\bTransfer\x12!.debank.ledger.v1.TransferRequest\x1a .debank.ledger.v1.TransferResult\x12L\n" +
+	"\n" +
+	"GetBalance\x12#.debank.ledger.v1.GetBalanceRequest\x1a\x19.debank.ledger.v1.Balance\x12X\n" +
+	"\x0eGetTransaction\x12'.debank.ledger.v1.GetTransactionRequest\x1a\x1d.debank.ledger.v1.Transaction\x12b\n" +
+	"\x12StreamTransactions\x12+.debank.ledger.v1.StreamTransactionsRequest\x1a\x1d.debank.ledger.v1.Transaction0\x01B>Z<github.com/Brownie44l1/debank/internal/rpc/ledgerv1;ledgerv1b\x06proto3"
+
+var (
+	file_debank_ledger_v1_ledger_proto_rawDescOnce sync.Once
+	file_debank_ledger_v1_ledger_proto_rawDescData []byte
+)
+
+func file_debank_ledger_v1_ledger_proto_rawDescGZIP() []byte {
+	file_debank_ledger_v1_ledger_proto_rawDescOnce.Do(func() {
+		file_debank_ledger_v1_ledger_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_debank_ledger_v1_ledger_proto_rawDesc), len(file_debank_ledger_v1_ledger_proto_rawDesc)))
+	})
+	return file_debank_ledger_v1_ledger_proto_rawDescData
+}
+
+var file_debank_ledger_v1_ledger_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_debank_ledger_v1_ledger_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_debank_ledger_v1_ledger_proto_goTypes = []any{
+	(Direction)(0),                    // 0: debank.ledger.v1.Direction
+	(*StepUpProof)(nil),               // 1: debank.ledger.v1.StepUpProof
+	(*DepositRequest)(nil),            // 2: debank.ledger.v1.DepositRequest
+	(*WithdrawRequest)(nil),           // 3: debank.ledger.v1.WithdrawRequest
+	(*TransferRequest)(nil),           // 4: debank.ledger.v1.TransferRequest
+	(*TransactionResult)(nil),         // 5: debank.ledger.v1.TransactionResult
+	(*TransferResult)(nil),            // 6: debank.ledger.v1.TransferResult
+	(*GetBalanceRequest)(nil),         // 7: debank.ledger.v1.GetBalanceRequest
+	(*Balance)(nil),                   // 8: debank.ledger.v1.Balance
+	(*GetTransactionRequest)(nil),     // 9: debank.ledger.v1.GetTransactionRequest
+	(*StreamTransactionsRequest)(nil), // 10: debank.ledger.v1.StreamTransactionsRequest
+	(*Transaction)(nil),               // 11: debank.ledger.v1.Transaction
+	(*StepUpChallenge)(nil),           // 12: debank.ledger.v1.StepUpChallenge
+	(*timestamppb.Timestamp)(nil),     // 13: google.protobuf.Timestamp
+}
+var file_debank_ledger_v1_ledger_proto_depIdxs = []int32{
+	1,  // 0: debank.ledger.v1.WithdrawRequest.step_up:type_name -> debank.ledger.v1.StepUpProof
+	1,  // 1: debank.ledger.v1.TransferRequest.step_up:type_name -> debank.ledger.v1.StepUpProof
+	0,  // 2: debank.ledger.v1.Transaction.direction:type_name -> debank.ledger.v1.Direction
+	13, // 3: debank.ledger.v1.Transaction.created_at:type_name -> google.protobuf.Timestamp
+	2,  // 4: debank.ledger.v1.LedgerService.Deposit:input_type -> debank.ledger.v1.DepositRequest
+	3,  // 5: debank.ledger.v1.LedgerService.Withdraw:input_type -> debank.ledger.v1.WithdrawRequest
+	4,  // 6: debank.ledger.v1.LedgerService.Transfer:input_type -> debank.ledger.v1.TransferRequest
+	7,  // 7: debank.ledger.v1.LedgerService.GetBalance:input_type -> debank.ledger.v1.GetBalanceRequest
+	9,  // 8: debank.ledger.v1.LedgerService.GetTransaction:input_type -> debank.ledger.v1.GetTransactionRequest
+	10, // 9: debank.ledger.v1.LedgerService.StreamTransactions:input_type -> debank.ledger.v1.StreamTransactionsRequest
+	5,  // 10: debank.ledger.v1.LedgerService.Deposit:output_type -> debank.ledger.v1.TransactionResult
+	5,  // 11: debank.ledger.v1.LedgerService.Withdraw:output_type -> debank.ledger.v1.TransactionResult
+	6,  // 12: debank.ledger.v1.LedgerService.Transfer:output_type -> debank.ledger.v1.TransferResult
+	8,  // 13: debank.ledger.v1.LedgerService.GetBalance:output_type -> debank.ledger.v1.Balance
+	11, // 14: debank.ledger.v1.LedgerService.GetTransaction:output_type -> debank.ledger.v1.Transaction
+	11, // 15: debank.ledger.v1.LedgerService.StreamTransactions:output_type -> debank.ledger.v1.Transaction
+	10, // [10:16] is the sub-list for method output_type
+	4,  // [4:10] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_debank_ledger_v1_ledger_proto_init() }
+func file_debank_ledger_v1_ledger_proto_init() {
+	if File_debank_ledger_v1_ledger_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_debank_ledger_v1_ledger_proto_rawDesc), len(file_debank_ledger_v1_ledger_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_debank_ledger_v1_ledger_proto_goTypes,
+		DependencyIndexes: file_debank_ledger_v1_ledger_proto_depIdxs,
+		EnumInfos:         file_debank_ledger_v1_ledger_proto_enumTypes,
+		MessageInfos:      file_debank_ledger_v1_ledger_proto_msgTypes,
+	}.Build()
+	File_debank_ledger_v1_ledger_proto = out.File
+	file_debank_ledger_v1_ledger_proto_goTypes = nil
+	file_debank_ledger_v1_ledger_proto_depIdxs = nil
+}
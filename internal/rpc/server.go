@@ -0,0 +1,249 @@
+// Package rpc serves the ledger over gRPC for other backend services.
+// It runs next to the HTTP API on its own port and calls the same wallet
+// service, so limits, risk rules and error codes are identical.
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/problem"
+	"github.com/Brownie44l1/debank/internal/requestid"
+	"github.com/Brownie44l1/debank/internal/rpc/ledgerv1"
+	"github.com/Brownie44l1/debank/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ==============================================
+// SERVER
+// ==============================================
+
+// Config is how the gRPC server authenticates its clients
+type Config struct {
+	CertFile       string   // Server certificate (PEM)
+	KeyFile        string   // Server private key (PEM)
+	ClientCAFile   string   // CA that issues client certificates (PEM); every client must present one
+	AllowedClients []string // Client certificate CNs or DNS SANs allowed to call; empty allows any the CA issued
+	Insecure       bool     // Plaintext without client certificates; development only
+}
+
+// NewServer builds the gRPC server with the ledger service registered
+// Interceptors run in order: recovery, request ID, tracing, logging, client allowlist, then authentication.
+func NewServer(cfg Config, ledger ledgerv1.LedgerServiceServer, tokens TokenVerifier, sessions SessionValidator) (*grpc.Server, error) {
+	creds := insecure.NewCredentials()
+	if !cfg.Insecure {
+		tlsConfig, err := serverTLS(cfg)
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	// Validation errors name fields as the proto does (snake_case), like the HTTP API's JSON names
+	problem.UseRequestFieldNames()
+
+	unary := []grpc.UnaryServerInterceptor{recoveryUnary, requestIDUnary, tracingUnary, loggingUnary}
+	stream := []grpc.StreamServerInterceptor{recoveryStream, requestIDStream, tracingStream, loggingStream}
+	if len(cfg.AllowedClients) > 0 && !cfg.Insecure {
+		allowUnary, allowStream := clientAllowlist(cfg.AllowedClients)
+		unary = append(unary, allowUnary)
+		stream = append(stream, allowStream)
+	}
+	unary = append(unary, authUnary(tokens, sessions))
+	stream = append(stream, authStream(tokens, sessions))
+
+	srv := grpc.NewServer(
+		grpc.Creds(creds),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	)
+	ledgerv1.RegisterLedgerServiceServer(srv, ledger)
+	return srv, nil
+}
+
+// serverTLS requires and verifies a client certificate from the configured CA
+func serverTLS(cfg Config) (*tls.Config, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" || cfg.ClientCAFile == "" {
+		return nil, errors.New("gRPC needs a certificate, key and client CA unless insecure mode is on")
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load gRPC server certificate: %w", err)
+	}
+
+	caPEM, err := os.ReadFile(cfg.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gRPC client CA: %w", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates found in %s", cfg.ClientCAFile)
+	}
+
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}, nil
+}
+
+// serverStream replaces a stream's context with one the interceptors added to
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// ==============================================
+// INTERCEPTORS
+// ==============================================
+
+// recoveryUnary turns a panic into Internal instead of crashing the process
+func recoveryUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[GRPC] Panic in %s: %v\n%s", info.FullMethod, r, debug.Stack())
+			err = status.Error(codes.Internal, "internal server error")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func recoveryStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[GRPC] Panic in %s: %v\n%s", info.FullMethod, r, debug.Stack())
+			err = status.Error(codes.Internal, "internal server error")
+		}
+	}()
+	return handler(srv, ss)
+}
+
+// withRequestID reuses the caller's x-request-id metadata when it is well formed,
+// and sends the ID back in the response headers
+func withRequestID(ctx context.Context) context.Context {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestid.Header); len(values) > 0 {
+			id = values[0]
+		}
+	}
+	if !requestid.Valid(id) {
+		id = requestid.New()
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestid.Header, id))
+	return requestid.NewContext(ctx, id)
+}
+
+func requestIDUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(withRequestID(ctx), req)
+}
+
+func requestIDStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &serverStream{ServerStream: ss, ctx: withRequestID(ss.Context())})
+}
+
+// startSpan opens a server span per call, continuing the caller's trace from its metadata
+func startSpan(ctx context.Context, fullMethod string) (context.Context, func(error)) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+
+	ctx, span := tracing.StartServer(ctx, fullMethod,
+		semconv.RPCSystemGRPC,
+		attribute.String("rpc.method", fullMethod),
+	)
+	return ctx, func(err error) {
+		code := status.Code(err)
+		span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+		if isServerFault(code) {
+			tracing.Fail(span, err)
+		}
+		span.End()
+	}
+}
+
+func tracingUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, end := startSpan(ctx, info.FullMethod)
+	resp, err := handler(ctx, req)
+	end(err)
+	return resp, err
+}
+
+func tracingStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, end := startSpan(ss.Context(), info.FullMethod)
+	err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	end(err)
+	return err
+}
+
+// logCall logs one line per finished call
+func logCall(ctx context.Context, fullMethod string, start time.Time, err error) {
+	log.Printf("[GRPC] %s %s %v - Client: %s, RequestID: %s",
+		fullMethod, status.Code(err), time.Since(start), clientName(ctx), requestid.FromContext(ctx))
+}
+
+func loggingUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	logCall(ctx, info.FullMethod, start, err)
+	return resp, err
+}
+
+func loggingStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	logCall(ss.Context(), info.FullMethod, start, err)
+	return err
+}
+
+// isServerFault reports whether a code means the server failed rather than the request
+func isServerFault(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.Unimplemented:
+		return true
+	}
+	return false
+}
+
+// metadataCarrier lets the OpenTelemetry propagator read traceparent from gRPC metadata
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,148 @@
+package sanctions
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ==============================================
+// CSV
+// ==============================================
+// Two layouts are accepted:
+//   - A header row naming the columns (id/uid/ent_num, name/sdn_name, aliases,
+//     type/sdn_type, program). Aliases are separated by ';'.
+//   - OFAC's headerless sdn.csv: ent_num, SDN_Name, SDN_Type, Program, ...
+
+var csvColumns = map[string]string{
+	"id": "id", "uid": "id", "ent_num": "id",
+	"name": "name", "sdn_name": "name",
+	"aliases": "aliases", "aka": "aliases",
+	"type": "type", "sdn_type": "type",
+	"program": "program", "programs": "program",
+}
+
+func parseCSV(r io.Reader, list string) ([]*Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	// Header row if any cell names a known column
+	cols := map[string]int{}
+	for i, cell := range rows[0] {
+		if key, ok := csvColumns[strings.ToLower(strings.TrimSpace(cell))]; ok {
+			cols[key] = i
+		}
+	}
+
+	if _, ok := cols["name"]; ok {
+		rows = rows[1:]
+	} else {
+		// OFAC positional layout
+		cols = map[string]int{"id": 0, "name": 1, "type": 2, "program": 3}
+	}
+
+	var entries []*Entry
+	for n, row := range rows {
+		e := &Entry{
+			List:    list,
+			ID:      csvField(row, cols, "id"),
+			Name:    csvField(row, cols, "name"),
+			Type:    strings.ToLower(csvField(row, cols, "type")),
+			Program: csvField(row, cols, "program"),
+		}
+		if e.Name == "" {
+			continue
+		}
+		if e.ID == "" {
+			e.ID = fmt.Sprintf("row-%d", n+1)
+		}
+		for _, alias := range strings.Split(csvField(row, cols, "aliases"), ";") {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				e.Aliases = append(e.Aliases, alias)
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// csvField returns a trimmed cell, treating OFAC's "-0-" placeholder as empty
+func csvField(row []string, cols map[string]int, key string) string {
+	i, ok := cols[key]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	v := strings.TrimSpace(row[i])
+	if v == "-0-" {
+		return ""
+	}
+	return v
+}
+
+// ==============================================
+// XML (OFAC SDN format)
+// ==============================================
+
+type sdnList struct {
+	Entries []sdnEntry `xml:"sdnEntry"`
+}
+
+type sdnEntry struct {
+	UID       string   `xml:"uid"`
+	FirstName string   `xml:"firstName"`
+	LastName  string   `xml:"lastName"`
+	SDNType   string   `xml:"sdnType"`
+	Programs  []string `xml:"programList>program"`
+	AKAs      []sdnAKA `xml:"akaList>aka"`
+}
+
+type sdnAKA struct {
+	FirstName string `xml:"firstName"`
+	LastName  string `xml:"lastName"`
+}
+
+func parseXML(r io.Reader, list string) ([]*Entry, error) {
+	var doc sdnList
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	entries := make([]*Entry, 0, len(doc.Entries))
+	for _, s := range doc.Entries {
+		name := joinName(s.FirstName, s.LastName)
+		if name == "" {
+			continue
+		}
+
+		e := &Entry{
+			List:    list,
+			ID:      strings.TrimSpace(s.UID),
+			Name:    name,
+			Type:    strings.ToLower(strings.TrimSpace(s.SDNType)),
+			Program: strings.Join(s.Programs, "; "),
+		}
+		for _, aka := range s.AKAs {
+			if alias := joinName(aka.FirstName, aka.LastName); alias != "" {
+				e.Aliases = append(e.Aliases, alias)
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func joinName(first, last string) string {
+	return strings.TrimSpace(strings.TrimSpace(first) + " " + strings.TrimSpace(last))
+}
@@ -0,0 +1,214 @@
+// Package sanctions loads sanctions and watchlists from local files and
+// screens names against them with fuzzy matching.
+package sanctions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Brownie44l1/debank/pkg/namematch"
+)
+
+// ==============================================
+// ERRORS
+// ==============================================
+
+var (
+	ErrNoLists           = errors.New("no watchlist files found")
+	ErrUnsupportedFormat = errors.New("unsupported watchlist format")
+)
+
+// ==============================================
+// ENTRIES
+// ==============================================
+
+// Entry is one listed person or organisation
+type Entry struct {
+	List    string   // File the entry came from, e.g. "sdn"
+	ID      string   // Identifier within the list (OFAC uid / ent_num)
+	Name    string   // Primary name as published
+	Aliases []string // Also-known-as names
+	Type    string   // "individual", "entity", ... when the list says
+	Program string   // Sanctions programme(s)
+
+	tokens [][]string // Name and aliases, normalised once at load
+}
+
+// Match is a screened name that resembles a listed entry
+type Match struct {
+	Entry       *Entry
+	MatchedName string  // The name or alias that matched
+	Score       float64 // 0..1
+}
+
+// Watchlist is an immutable snapshot of every loaded list
+type Watchlist struct {
+	Entries  []*Entry
+	Files    []string
+	LoadedAt time.Time
+}
+
+// ==============================================
+// LOADING
+// ==============================================
+
+// LoadDir reads every .csv and .xml file in dir into a new watchlist
+func LoadDir(dir string) (*Watchlist, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watchlists: %w", err)
+	}
+	sort.Strings(paths)
+
+	wl := &Watchlist{LoadedAt: time.Now()}
+	for _, path := range paths {
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext != ".csv" && ext != ".xml" {
+			continue
+		}
+
+		entries, err := LoadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		wl.Entries = append(wl.Entries, entries...)
+		wl.Files = append(wl.Files, filepath.Base(path))
+	}
+
+	if len(wl.Files) == 0 {
+		return nil, fmt.Errorf("%w in %s", ErrNoLists, dir)
+	}
+	return wl, nil
+}
+
+// LoadFile parses a single CSV or XML watchlist; the list name is the file name without extension
+func LoadFile(path string) ([]*Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open watchlist: %w", err)
+	}
+	defer f.Close()
+
+	list := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	var entries []*Entry
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		entries, err = parseCSV(f, list)
+	case ".xml":
+		entries, err = parseXML(f, list)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+
+	for _, e := range entries {
+		e.prepare()
+	}
+	return entries, nil
+}
+
+func (e *Entry) prepare() {
+	names := append([]string{e.Name}, e.Aliases...)
+	e.tokens = make([][]string, len(names))
+	for i, n := range names {
+		e.tokens[i] = namematch.Tokens(n)
+	}
+}
+
+// ==============================================
+// SCREENING
+// ==============================================
+
+// Screen returns entries whose name or any alias scores at least threshold, best first
+func (w *Watchlist) Screen(name string, threshold float64) []Match {
+	query := namematch.Tokens(name)
+	if len(query) == 0 {
+		return nil
+	}
+
+	var matches []Match
+	for _, e := range w.Entries {
+		best, bestIdx := 0.0, -1
+		for i, tokens := range e.tokens {
+			if score := namematch.SimilarityTokens(query, tokens); score > best {
+				best, bestIdx = score, i
+			}
+		}
+		if bestIdx < 0 || best < threshold {
+			continue
+		}
+
+		matched := e.Name
+		if bestIdx > 0 {
+			matched = e.Aliases[bestIdx-1]
+		}
+		matches = append(matches, Match{Entry: e, MatchedName: matched, Score: best})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	return matches
+}
+
+// ==============================================
+// SCREENER
+// ==============================================
+
+// Screener holds the current watchlist and swaps it atomically on Reload,
+// so screening never sees a half-loaded list
+type Screener struct {
+	dir       string
+	threshold float64
+
+	mu      sync.RWMutex
+	current *Watchlist
+}
+
+// NewScreener creates a screener over the files in dir; call Reload to load them
+func NewScreener(dir string, threshold float64) *Screener {
+	return &Screener{dir: dir, threshold: threshold}
+}
+
+// Reload re-reads the watchlist directory; on failure the previous lists stay in use
+func (s *Screener) Reload(ctx context.Context) (*Watchlist, error) {
+	wl, err := LoadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.current = wl
+	s.mu.Unlock()
+	return wl, nil
+}
+
+// Current returns the watchlist in use, or nil if nothing has loaded yet
+func (s *Screener) Current() *Watchlist {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// Screen checks a name against the current watchlist
+// With no list loaded nothing can match, so callers should treat a nil Current as a configuration problem
+func (s *Screener) Screen(name string) []Match {
+	wl := s.Current()
+	if wl == nil {
+		return nil
+	}
+	return wl.Screen(name, s.threshold)
+}
+
+// Threshold returns the minimum score reported as a potential match
+func (s *Screener) Threshold() float64 {
+	return s.threshold
+}
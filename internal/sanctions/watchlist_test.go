@@ -0,0 +1,124 @@
+package sanctions
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const headerCSV = `id,name,aliases,type,program
+1,Ivan Petrov,Иван Петров;Vanya Petrov,individual,UKRAINE-EO13660
+2,Acme Shell Holdings,,entity,SDGT
+`
+
+const ofacCSV = `36,"AEROCARIBBEAN AIRLINES",-0- ,"CUBA",-0-
+173,"ZAYDAN, Muhammad","individual","SDGT",-0-
+`
+
+const ofacXML = `<?xml version="1.0"?>
+<sdnList>
+  <sdnEntry>
+    <uid>7421</uid>
+    <firstName>José</firstName>
+    <lastName>GARCÍA LÓPEZ</lastName>
+    <sdnType>Individual</sdnType>
+    <programList><program>SDNTK</program></programList>
+    <akaList>
+      <aka><firstName>Pepe</firstName><lastName>GARCIA</lastName></aka>
+    </akaList>
+  </sdnEntry>
+</sdnList>
+`
+
+func writeList(t *testing.T, dir, name, body string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644))
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	writeList(t, dir, "internal.csv", headerCSV)
+	writeList(t, dir, "sdn.csv", ofacCSV)
+	writeList(t, dir, "sdn_xml.xml", ofacXML)
+	writeList(t, dir, "README.txt", "ignored")
+
+	wl, err := LoadDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"internal.csv", "sdn.csv", "sdn_xml.xml"}, wl.Files)
+	require.Len(t, wl.Entries, 5)
+
+	ivan := wl.Entries[0]
+	assert.Equal(t, "internal", ivan.List)
+	assert.Equal(t, "1", ivan.ID)
+	assert.Equal(t, []string{"Иван Петров", "Vanya Petrov"}, ivan.Aliases)
+
+	zaydan := wl.Entries[3]
+	assert.Equal(t, "173", zaydan.ID)
+	assert.Equal(t, "ZAYDAN, Muhammad", zaydan.Name)
+	assert.Equal(t, "individual", zaydan.Type)
+
+	jose := wl.Entries[4]
+	assert.Equal(t, "José GARCÍA LÓPEZ", jose.Name)
+	assert.Equal(t, []string{"Pepe GARCIA"}, jose.Aliases)
+	assert.Equal(t, "SDNTK", jose.Program)
+}
+
+func TestLoadDirEmpty(t *testing.T) {
+	_, err := LoadDir(t.TempDir())
+	assert.ErrorIs(t, err, ErrNoLists)
+}
+
+func TestWatchlistScreen(t *testing.T) {
+	dir := t.TempDir()
+	writeList(t, dir, "internal.csv", headerCSV)
+	writeList(t, dir, "sdn.csv", ofacCSV)
+	writeList(t, dir, "sdn_xml.xml", ofacXML)
+
+	wl, err := LoadDir(dir)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		query   string
+		wantID  string
+		matched string
+	}{
+		{"exact", "Ivan Petrov", "1", "Ivan Petrov"},
+		{"reordered with comma", "Muhammad Zaydan", "173", "ZAYDAN, Muhammad"},
+		{"accents dropped", "Jose Garcia Lopez", "7421", "José GARCÍA LÓPEZ"},
+		{"alias", "Pepe Garcia", "7421", "Pepe GARCIA"},
+		{"typo", "Muhamad Zaydan", "173", "ZAYDAN, Muhammad"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := wl.Screen(tt.query, 0.9)
+			require.NotEmpty(t, matches)
+			assert.Equal(t, tt.wantID, matches[0].Entry.ID)
+			assert.Equal(t, tt.matched, matches[0].MatchedName)
+		})
+	}
+
+	assert.Empty(t, wl.Screen("Adaeze Okonkwo", 0.9))
+	assert.Empty(t, wl.Screen("", 0.9))
+}
+
+func TestScreenerReloadKeepsPreviousOnError(t *testing.T) {
+	dir := t.TempDir()
+	s := NewScreener(dir, 0.9)
+	assert.Nil(t, s.Current())
+	assert.Empty(t, s.Screen("Ivan Petrov"))
+
+	writeList(t, dir, "internal.csv", headerCSV)
+	_, err := s.Reload(context.Background())
+	require.NoError(t, err)
+	assert.NotEmpty(t, s.Screen("Ivan Petrov"))
+
+	writeList(t, dir, "broken.xml", "<sdnList><sdnEntry>")
+	_, err = s.Reload(context.Background())
+	assert.Error(t, err)
+	assert.NotEmpty(t, s.Screen("Ivan Petrov"))
+}
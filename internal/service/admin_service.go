@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/Brownie44l1/debank/internal/metrics"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// ADMIN SERVICE
+// ==============================================
+// Staff tooling behind /api/v1/admin. Route permissions are enforced by
+// middleware; this layer adds the rules that depend on the target: staff
+// can't act on themselves or on anyone of equal or higher rank. Every
+// call is audited, lookups included, with the staff member as the actor.
+
+var (
+	ErrTransactionNotFound = errors.New("transaction not found")
+	ErrAdminSelfAction     = errors.New("staff cannot perform this action on their own account")
+	ErrInsufficientRole    = errors.New("your role does not outrank this user's")
+	ErrInvalidRole         = errors.New("unknown role")
+	ErrLockInPast          = errors.New("lock must end in the future")
+)
+
+// A lock without an end date; far enough out that it only ends by unlocking
+var indefiniteLock = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+type AdminService struct {
+	userRepo   *repository.UserRepository
+	walletRepo *repository.WalletRepository
+	audit      *AuditLogger
+}
+
+func NewAdminService(userRepo *repository.UserRepository, walletRepo *repository.WalletRepository, audit *AuditLogger) *AdminService {
+	return &AdminService{
+		userRepo:   userRepo,
+		walletRepo: walletRepo,
+		audit:      audit,
+	}
+}
+
+// ==============================================
+// LOOKUPS
+// ==============================================
+
+// SearchUsers returns users matching the query, newest first
+func (s *AdminService) SearchUsers(ctx context.Context, actorID int, req dto.SearchUsersRequest) (*dto.AdminUserListResponse, error) {
+	page, perPage := pageOrDefault(req.Page, req.PerPage)
+	query := strings.TrimSpace(req.Query)
+
+	users, total, err := s.userRepo.SearchUsers(ctx, query, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.auditLookup(ctx, actorID, models.AuditActionAdminUserSearch, "user", 0, map[string]interface{}{
+		"query":   query,
+		"page":    page,
+		"results": len(users),
+	}); err != nil {
+		return nil, err
+	}
+
+	resp := &dto.AdminUserListResponse{
+		Users:   make([]dto.AdminUserDTO, len(users)),
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+	}
+	for i := range users {
+		resp.Users[i] = *adminUserToDTO(&users[i])
+	}
+	return resp, nil
+}
+
+// GetUser returns a user with their wallet account
+func (s *AdminService) GetUser(ctx context.Context, actorID, userID int) (*dto.AdminUserDTO, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := adminUserToDTO(user)
+	account, err := s.walletRepo.GetAccountByUserID(ctx, userID)
+	switch {
+	case err == nil:
+		out.Account = adminAccountToDTO(account)
+	case !errors.Is(err, repository.ErrAccountNotFound):
+		return nil, err
+	}
+
+	if err := s.auditLookup(ctx, actorID, models.AuditActionAdminUserViewed, "user", int64(userID), nil); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetAccount returns an account by its account number
+func (s *AdminService) GetAccount(ctx context.Context, actorID int, accountNumber string) (*dto.AdminAccountDTO, error) {
+	account, err := s.walletRepo.GetAccountByAccountNumber(ctx, accountNumber)
+	if err != nil {
+		if errors.Is(err, repository.ErrAccountNotFound) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+
+	if err := s.auditLookup(ctx, actorID, models.AuditActionAdminAccountViewed, "account", account.ID, map[string]interface{}{
+		"account_number": accountNumber,
+	}); err != nil {
+		return nil, err
+	}
+	return adminAccountToDTO(account), nil
+}
+
+// GetTransaction returns a transaction and its postings by reference
+func (s *AdminService) GetTransaction(ctx context.Context, actorID int, reference string) (*dto.AdminTransactionDTO, error) {
+	txn, err := s.walletRepo.GetTransactionByReference(ctx, reference)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoRows) {
+			return nil, ErrTransactionNotFound
+		}
+		return nil, err
+	}
+
+	postings, err := s.walletRepo.GetPostingsByTransactionID(ctx, txn.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.auditLookup(ctx, actorID, models.AuditActionAdminTxViewed, "transaction", txn.ID, map[string]interface{}{
+		"reference": reference,
+	}); err != nil {
+		return nil, err
+	}
+	return adminTransactionToDTO(txn, postings), nil
+}
+
+// ==============================================
+// ACCOUNT CONTROL
+// ==============================================
+
+// LockUser locks a user out and ends their sessions
+func (s *AdminService) LockUser(ctx context.Context, actorID int, actorRole string, userID int, req dto.LockUserRequest) (*dto.AdminUserDTO, error) {
+	until := req.Until
+	if until.IsZero() {
+		until = indefiniteLock
+	} else if !until.After(time.Now()) {
+		return nil, ErrLockInPast
+	}
+
+	target, err := s.actionTarget(ctx, actorID, actorRole, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]interface{}{
+		"reason":       req.Reason,
+		"actor_role":   actorRole,
+		"indefinite":   req.Until.IsZero(),
+		"locked_until": until.Format(time.RFC3339),
+	}
+	audit, err := auditEntry(ctx, actorID, models.AuditActionAccountLocked, "user", int64(userID), metadata)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.LockAccount(ctx, userID, until, audit); err != nil {
+		return nil, mapAdminError(err)
+	}
+
+	metrics.AccountLockouts.WithLabelValues(metrics.LockoutStaff).Inc()
+	log.Printf("[ADMIN] User locked - UserID: %d, By: %d, Until: %s", userID, actorID, until.Format(time.RFC3339))
+	target.LockedUntil = pgtype.Timestamp{Time: until, Valid: true}
+	return adminUserToDTO(target), nil
+}
+
+// UnlockUser lifts a lock and clears failed login attempts
+func (s *AdminService) UnlockUser(ctx context.Context, actorID int, actorRole string, userID int, req dto.UnlockUserRequest) (*dto.AdminUserDTO, error) {
+	target, err := s.actionTarget(ctx, actorID, actorRole, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	audit, err := auditEntry(ctx, actorID, models.AuditActionAccountUnlocked, "user", int64(userID), map[string]interface{}{
+		"reason":     req.Reason,
+		"actor_role": actorRole,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.UnlockAccount(ctx, userID, audit); err != nil {
+		return nil, mapAdminError(err)
+	}
+
+	log.Printf("[ADMIN] User unlocked - UserID: %d, By: %d", userID, actorID)
+	target.LockedUntil = pgtype.Timestamp{}
+	target.FailedLoginAttempts = 0
+	return adminUserToDTO(target), nil
+}
+
+// SetRole changes a user's role; the user has to sign in again to pick it up
+func (s *AdminService) SetRole(ctx context.Context, actorID int, actorRole string, userID int, req dto.SetRoleRequest) (*dto.AdminUserDTO, error) {
+	if !auth.IsValidRole(req.Role) {
+		return nil, ErrInvalidRole
+	}
+
+	target, err := s.actionTarget(ctx, actorID, actorRole, userID)
+	if err != nil {
+		return nil, err
+	}
+	// Nobody can hand out a role at or above their own
+	if !auth.Outranks(actorRole, req.Role) {
+		return nil, ErrInsufficientRole
+	}
+	if target.Role == req.Role {
+		return adminUserToDTO(target), nil
+	}
+
+	audit, err := auditEntry(ctx, actorID, models.AuditActionRoleChanged, "user", int64(userID), map[string]interface{}{
+		"from":       target.Role,
+		"to":         req.Role,
+		"reason":     req.Reason,
+		"actor_role": actorRole,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.SetRole(ctx, userID, req.Role, audit); err != nil {
+		return nil, mapAdminError(err)
+	}
+
+	log.Printf("[ADMIN] Role changed - UserID: %d, From: %s, To: %s, By: %d", userID, target.Role, req.Role, actorID)
+	target.Role = req.Role
+	return adminUserToDTO(target), nil
+}
+
+// ==============================================
+// HELPERS
+// ==============================================
+
+func (s *AdminService) getUser(ctx context.Context, userID int) (*models.User, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, mapAdminError(err)
+	}
+	return user, nil
+}
+
+// actionTarget loads the user a staff action applies to and checks the actor may act on them
+func (s *AdminService) actionTarget(ctx context.Context, actorID int, actorRole string, userID int) (*models.User, error) {
+	if actorID == userID {
+		return nil, ErrAdminSelfAction
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !auth.Outranks(actorRole, user.Role) {
+		return nil, ErrInsufficientRole
+	}
+	return user, nil
+}
+
+// auditLookup writes a standalone entry for a staff action that changes nothing
+func (s *AdminService) auditLookup(ctx context.Context, actorID int, action, entityType string, entityID int64, metadata map[string]interface{}) error {
+	entry, err := auditEntry(ctx, actorID, action, entityType, entityID, metadata)
+	if err != nil {
+		return err
+	}
+	return s.audit.Write(ctx, entry)
+}
+
+func mapAdminError(err error) error {
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return models.ErrUserNotFound
+	}
+	return err
+}
+
+func adminUserToDTO(u *models.User) *dto.AdminUserDTO {
+	out := &dto.AdminUserDTO{
+		ID:                  int(u.ID),
+		Name:                u.Name,
+		Phone:               u.Phone,
+		Email:               u.Email,
+		Role:                u.Role,
+		IsActive:            u.IsActive,
+		IsEmailVerified:     u.IsEmailVerified,
+		FailedLoginAttempts: int(u.FailedLoginAttempts),
+		Locked:              u.IsLocked(),
+		CreatedAt:           u.CreatedAt.Format(time.RFC3339),
+	}
+	if u.Username.Valid {
+		username := u.Username.String
+		out.Username = &username
+	}
+	if u.LockedUntil.Valid {
+		out.LockedUntil = u.LockedUntil.Time.Format(time.RFC3339)
+	}
+	if u.LastLoginAt.Valid {
+		out.LastLoginAt = u.LastLoginAt.Time.Format(time.RFC3339)
+	}
+	return out
+}
+
+func adminAccountToDTO(a *models.Account) *dto.AdminAccountDTO {
+	out := &dto.AdminAccountDTO{
+		ID:            a.ID,
+		AccountNumber: a.AccountNumber.String,
+		Name:          a.Name,
+		Type:          a.Type,
+		Balance:       a.Balance,
+		Currency:      a.Currency,
+		IsActive:      a.IsActive,
+		Frozen:        a.FrozenAt.Valid,
+		FrozenReason:  a.FrozenReason.String,
+		CreatedAt:     a.CreatedAt.Format(time.RFC3339),
+	}
+	if a.UserID.Valid {
+		userID := int(a.UserID.Int32)
+		out.UserID = &userID
+	}
+	if a.FrozenAt.Valid {
+		out.FrozenAt = a.FrozenAt.Time.Format(time.RFC3339)
+	}
+	return out
+}
+
+func adminTransactionToDTO(t *models.Transaction, postings []models.Posting) *dto.AdminTransactionDTO {
+	out := &dto.AdminTransactionDTO{
+		ID:             t.ID,
+		Reference:      t.Reference,
+		Kind:           t.Kind,
+		Status:         t.Status,
+		Amount:         t.Amount,
+		Currency:       t.Currency,
+		FromIdentifier: t.FromIdentifier.String,
+		ToIdentifier:   t.ToIdentifier.String,
+		Description:    t.Description.String,
+		FailureReason:  t.FailureReason.String,
+		CreatedAt:      t.CreatedAt.Format(time.RFC3339),
+		Postings:       make([]dto.AdminPostingDTO, len(postings)),
+	}
+	if t.FromAccountID.Valid {
+		id := t.FromAccountID.Int64
+		out.FromAccountID = &id
+	}
+	if t.ToAccountID.Valid {
+		id := t.ToAccountID.Int64
+		out.ToAccountID = &id
+	}
+	if t.PostedAt.Valid {
+		out.PostedAt = t.PostedAt.Time.Format(time.RFC3339)
+	}
+	if t.FailedAt.Valid {
+		out.FailedAt = t.FailedAt.Time.Format(time.RFC3339)
+	}
+	for i, p := range postings {
+		out.Postings[i] = dto.AdminPostingDTO{
+			AccountID: p.AccountID,
+			Amount:    p.Amount,
+			Currency:  p.Currency,
+		}
+	}
+	return out
+}
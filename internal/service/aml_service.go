@@ -0,0 +1,468 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// MONITORING RULES
+// ==============================================
+
+// AMLRules holds the thresholds used by the monitoring job (amounts in kobo)
+type AMLRules struct {
+	// Structuring: many deposits just under a reporting threshold
+	StructuringWindow    time.Duration
+	StructuringThreshold int64
+	StructuringMargin    int64 // Deposits in [threshold-margin, threshold) count
+	StructuringMinCount  int
+
+	// Rapid in-and-out: money received and sent straight back out
+	RapidWindow         time.Duration
+	RapidMinInflow      int64
+	RapidOutflowPercent int
+
+	// Fan-in / fan-out: many distinct counterparties
+	FanWindow            time.Duration
+	FanMinCounterparties int
+
+	// Dormant reactivation: activity after a long silence
+	DormantWindow    time.Duration
+	DormantDays      int
+	DormantMinVolume int64
+}
+
+// DefaultAMLRules returns the thresholds used unless overridden
+func DefaultAMLRules() AMLRules {
+	return AMLRules{
+		StructuringWindow:    72 * time.Hour,
+		StructuringThreshold: 50000000, // ₦500,000.00
+		StructuringMargin:    5000000,  // ₦50,000.00
+		StructuringMinCount:  3,
+
+		RapidWindow:         24 * time.Hour,
+		RapidMinInflow:      20000000, // ₦200,000.00
+		RapidOutflowPercent: 90,
+
+		FanWindow:            24 * time.Hour,
+		FanMinCounterparties: 10,
+
+		DormantWindow:    24 * time.Hour,
+		DormantDays:      180,
+		DormantMinVolume: 10000000, // ₦100,000.00
+	}
+}
+
+// ==============================================
+// SERVICE ERRORS
+// ==============================================
+
+var (
+	ErrAMLCaseNotFound     = errors.New("aml case not found")
+	ErrAMLCaseClosed       = errors.New("aml case is closed")
+	ErrAMLAssigneeNotFound = errors.New("assignee not found")
+)
+
+// ==============================================
+// SERVICE
+// ==============================================
+
+// AMLStore runs the detectors and keeps the cases they raise (implemented by repository.AMLRepository)
+type AMLStore interface {
+	StartScanRun(ctx context.Context) (*models.AMLScanRun, error)
+	FinishScanRun(ctx context.Context, run *models.AMLScanRun) error
+	DetectStructuring(ctx context.Context, since time.Time, lower, threshold int64, minCount int) ([]models.AMLDetection, error)
+	DetectRapidInOut(ctx context.Context, since time.Time, minInflow int64, outflowPercent int) ([]models.AMLDetection, error)
+	DetectFanIn(ctx context.Context, since time.Time, minCounterparties int) ([]models.AMLDetection, error)
+	DetectFanOut(ctx context.Context, since time.Time, minCounterparties int) ([]models.AMLDetection, error)
+	DetectDormantReactivation(ctx context.Context, since time.Time, dormantDays int, minVolume int64) ([]models.AMLDetection, error)
+	UpsertCase(ctx context.Context, runID int64, d *models.AMLDetection) (int64, bool, error)
+	GetCase(ctx context.Context, id int64) (*models.AMLCase, error)
+	ListCases(ctx context.Context, status, typology string, assignedTo int, limit, offset int) ([]models.AMLCase, error)
+	ListCaseTransactions(ctx context.Context, caseID int64) ([]models.Transaction, error)
+	AddNote(ctx context.Context, note *models.AMLCaseNote) error
+	ListNotes(ctx context.Context, caseID int64) ([]models.AMLCaseNote, error)
+	AssignCase(ctx context.Context, id int64, assigneeID int, audit *models.AuditLog) (*models.AMLCase, error)
+	EscalateCase(ctx context.Context, id int64, actorID int, freeze bool, note *models.AMLCaseNote, audit *models.AuditLog) (*models.AMLCase, error)
+	CloseCase(ctx context.Context, id int64, actorID int, resolution string, unfreeze bool, note *models.AMLCaseNote, audit *models.AuditLog) (*models.AMLCase, error)
+}
+
+type AMLService struct {
+	repo     AMLStore
+	userRepo *repository.UserRepository
+	rules    AMLRules
+}
+
+func NewAMLService(repo AMLStore, userRepo *repository.UserRepository, rules AMLRules) *AMLService {
+	return &AMLService{repo: repo, userRepo: userRepo, rules: rules}
+}
+
+// ==============================================
+// MONITORING JOB
+// ==============================================
+
+// RunScan runs every detector over recent postings and opens or refreshes cases
+// Safe to run repeatedly: a live case per account and typology is reused
+func (s *AMLService) RunScan(ctx context.Context) (*dto.AMLScanResponse, error) {
+	startTime := time.Now()
+	log.Printf("[AML_SCAN] Started")
+
+	run, err := s.repo.StartScanRun(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scanErr := s.scan(ctx, run)
+	if scanErr != nil {
+		run.Error = pgtype.Text{String: scanErr.Error(), Valid: true}
+	}
+	if err := s.repo.FinishScanRun(ctx, run); err != nil {
+		log.Printf("[AML_SCAN] Failed to record run %d: %v", run.ID, err)
+	}
+	if scanErr != nil {
+		log.Printf("[AML_SCAN] Failed - RunID: %d, Error: %v", run.ID, scanErr)
+		return nil, scanErr
+	}
+
+	log.Printf("[AML_SCAN] Success - RunID: %d, Opened: %d, Updated: %d, Duration: %v",
+		run.ID, run.CasesOpened, run.CasesUpdated, time.Since(startTime))
+
+	return &dto.AMLScanResponse{
+		RunID:        run.ID,
+		CasesOpened:  int(run.CasesOpened),
+		CasesUpdated: int(run.CasesUpdated),
+		StartedAt:    run.StartedAt.Format(time.RFC3339),
+		FinishedAt:   run.FinishedAt.Time.Format(time.RFC3339),
+	}, nil
+}
+
+func (s *AMLService) scan(ctx context.Context, run *models.AMLScanRun) error {
+	now := time.Now()
+	r := s.rules
+
+	detectors := []func() ([]models.AMLDetection, error){
+		func() ([]models.AMLDetection, error) {
+			return s.repo.DetectStructuring(ctx, now.Add(-r.StructuringWindow),
+				r.StructuringThreshold-r.StructuringMargin, r.StructuringThreshold, r.StructuringMinCount)
+		},
+		func() ([]models.AMLDetection, error) {
+			return s.repo.DetectRapidInOut(ctx, now.Add(-r.RapidWindow), r.RapidMinInflow, r.RapidOutflowPercent)
+		},
+		func() ([]models.AMLDetection, error) {
+			return s.repo.DetectFanIn(ctx, now.Add(-r.FanWindow), r.FanMinCounterparties)
+		},
+		func() ([]models.AMLDetection, error) {
+			return s.repo.DetectFanOut(ctx, now.Add(-r.FanWindow), r.FanMinCounterparties)
+		},
+		func() ([]models.AMLDetection, error) {
+			return s.repo.DetectDormantReactivation(ctx, now.Add(-r.DormantWindow), r.DormantDays, r.DormantMinVolume)
+		},
+	}
+
+	for _, detect := range detectors {
+		detections, err := detect()
+		if err != nil {
+			return err
+		}
+
+		for i := range detections {
+			d := &detections[i]
+			d.Summary = amlSummary(d)
+
+			caseID, created, err := s.repo.UpsertCase(ctx, run.ID, d)
+			if err != nil {
+				return err
+			}
+			switch {
+			case caseID == 0:
+				// Same activity an investigator already closed
+			case created:
+				run.CasesOpened++
+				log.Printf("[AML_SCAN] Case opened - CaseID: %d, Typology: %s, AccountID: %d", caseID, d.Typology, d.AccountID)
+			default:
+				run.CasesUpdated++
+			}
+		}
+	}
+
+	return nil
+}
+
+// amlSummary turns detection metrics into a one-line description for investigators
+func amlSummary(d *models.AMLDetection) string {
+	n := len(d.TransactionIDs)
+	switch d.Typology {
+	case models.AMLTypologyStructuring:
+		return fmt.Sprintf("%v deposits just under ₦%s", d.Details["deposit_count"], formatNaira(d.Details["threshold"]))
+	case models.AMLTypologyRapidInOut:
+		return fmt.Sprintf("Received ₦%s and sent out ₦%s across %d transactions",
+			formatNaira(d.Details["inflow"]), formatNaira(d.Details["outflow"]), n)
+	case models.AMLTypologyFanIn:
+		return fmt.Sprintf("Paid by %v different accounts", d.Details["counterparties"])
+	case models.AMLTypologyFanOut:
+		return fmt.Sprintf("Paid %v different accounts", d.Details["counterparties"])
+	case models.AMLTypologyDormantReactivation:
+		return fmt.Sprintf("₦%s moved after %v+ days without activity", formatNaira(d.Details["volume"]), d.Details["dormant_days"])
+	default:
+		return fmt.Sprintf("%s across %d transactions", d.Typology, n)
+	}
+}
+
+func formatNaira(kobo any) string {
+	v, _ := kobo.(int64)
+	return fmt.Sprintf("%.2f", float64(v)/100)
+}
+
+// ==============================================
+// CASE QUERIES
+// ==============================================
+
+// ListCases returns the case queue, newest first
+func (s *AMLService) ListCases(ctx context.Context, req dto.ListAMLCasesRequest) (*dto.AMLCaseListResponse, error) {
+	page, perPage := req.Page, req.PerPage
+	if page == 0 {
+		page = 1
+	}
+	if perPage == 0 {
+		perPage = 20
+	}
+
+	cases, err := s.repo.ListCases(ctx, req.Status, req.Typology, req.AssignedTo, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.AMLCaseListResponse{
+		Cases:   make([]dto.AMLCaseDTO, len(cases)),
+		Page:    page,
+		PerPage: perPage,
+	}
+	for i := range cases {
+		resp.Cases[i] = *amlCaseToDTO(&cases[i])
+	}
+	return resp, nil
+}
+
+// GetCase returns a case with its linked transactions and notes
+func (s *AMLService) GetCase(ctx context.Context, id int64) (*dto.AMLCaseDTO, error) {
+	c, err := s.repo.GetCase(ctx, id)
+	if err != nil {
+		return nil, mapAMLError(err)
+	}
+
+	txns, err := s.repo.ListCaseTransactions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.repo.ListNotes(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	out := amlCaseToDTO(c)
+	out.Transactions = make([]dto.AMLCaseTransactionDTO, len(txns))
+	for i, t := range txns {
+		out.Transactions[i] = dto.AMLCaseTransactionDTO{
+			ID:        t.ID,
+			Reference: t.Reference,
+			Kind:      t.Kind,
+			Status:    t.Status,
+			Amount:    t.Amount,
+			AmountNGN: float64(t.Amount) / 100,
+			CreatedAt: t.CreatedAt.Format(time.RFC3339),
+		}
+		if t.FromAccountID.Valid {
+			id := t.FromAccountID.Int64
+			out.Transactions[i].FromAccountID = &id
+		}
+		if t.ToAccountID.Valid {
+			id := t.ToAccountID.Int64
+			out.Transactions[i].ToAccountID = &id
+		}
+	}
+	out.Notes = make([]dto.AMLCaseNoteDTO, len(notes))
+	for i := range notes {
+		out.Notes[i] = amlNoteToDTO(&notes[i])
+	}
+
+	return out, nil
+}
+
+// ==============================================
+// CASE ACTIONS
+// ==============================================
+
+// Assign hands a case to an investigator (the caller if no assignee is given)
+func (s *AMLService) Assign(ctx context.Context, actorID int, id int64, req dto.AssignAMLCaseRequest) (*dto.AMLCaseDTO, error) {
+	assigneeID := req.AssigneeID
+	if assigneeID == 0 {
+		assigneeID = actorID
+	}
+	if _, err := s.userRepo.GetUserByID(ctx, assigneeID); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrAMLAssigneeNotFound
+		}
+		return nil, err
+	}
+
+	audit, err := amlCaseAudit(actorID, models.AuditActionAMLCaseAssigned, map[string]interface{}{
+		"assignee_id": assigneeID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.repo.AssignCase(ctx, id, assigneeID, audit)
+	if err != nil {
+		return nil, mapAMLError(err)
+	}
+
+	log.Printf("[AML] Case assigned - CaseID: %d, Assignee: %d, By: %d", id, assigneeID, actorID)
+	return amlCaseToDTO(c), nil
+}
+
+// AddNote records an investigator comment on a live case
+func (s *AMLService) AddNote(ctx context.Context, actorID int, id int64, req dto.AddAMLCaseNoteRequest) (*dto.AMLCaseNoteDTO, error) {
+	note := &models.AMLCaseNote{
+		CaseID:   id,
+		AuthorID: pgtype.Int4{Int32: int32(actorID), Valid: true},
+		Body:     strings.TrimSpace(req.Body),
+	}
+
+	if err := s.repo.AddNote(ctx, note); err != nil {
+		return nil, mapAMLError(err)
+	}
+
+	out := amlNoteToDTO(note)
+	return &out, nil
+}
+
+// Escalate flags a case for senior review and can freeze the account while it is investigated
+func (s *AMLService) Escalate(ctx context.Context, actorID int, id int64, req dto.EscalateAMLCaseRequest) (*dto.AMLCaseDTO, error) {
+	audit, err := amlCaseAudit(actorID, models.AuditActionAMLCaseEscalated, map[string]interface{}{
+		"freeze_account": req.FreezeAccount,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.repo.EscalateCase(ctx, id, actorID, req.FreezeAccount, amlActionNote(actorID, req.Note), audit)
+	if err != nil {
+		return nil, mapAMLError(err)
+	}
+
+	log.Printf("[AML] Case escalated - CaseID: %d, Freeze: %t, By: %d", id, req.FreezeAccount, actorID)
+	return amlCaseToDTO(c), nil
+}
+
+// Close resolves a case and can lift a freeze this case put on the account
+func (s *AMLService) Close(ctx context.Context, actorID int, id int64, req dto.CloseAMLCaseRequest) (*dto.AMLCaseDTO, error) {
+	audit, err := amlCaseAudit(actorID, models.AuditActionAMLCaseClosed, map[string]interface{}{
+		"resolution":       req.Resolution,
+		"unfreeze_account": req.UnfreezeAccount,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.repo.CloseCase(ctx, id, actorID, req.Resolution, req.UnfreezeAccount, amlActionNote(actorID, req.Note), audit)
+	if err != nil {
+		return nil, mapAMLError(err)
+	}
+
+	log.Printf("[AML] Case closed - CaseID: %d, Resolution: %s, By: %d", id, req.Resolution, actorID)
+	return amlCaseToDTO(c), nil
+}
+
+// ==============================================
+// HELPERS
+// ==============================================
+
+func mapAMLError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrAMLCaseNotFound):
+		return ErrAMLCaseNotFound
+	case errors.Is(err, repository.ErrAMLCaseClosed):
+		return ErrAMLCaseClosed
+	default:
+		return err
+	}
+}
+
+func amlActionNote(actorID int, body string) *models.AMLCaseNote {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil
+	}
+	return &models.AMLCaseNote{
+		AuthorID: pgtype.Int4{Int32: int32(actorID), Valid: true},
+		Body:     body,
+	}
+}
+
+func amlCaseAudit(actorID int, action string, metadata map[string]interface{}) (*models.AuditLog, error) {
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit metadata: %w", err)
+	}
+
+	return &models.AuditLog{
+		UserID:     pgtype.Int4{Int32: int32(actorID), Valid: true},
+		Action:     action,
+		EntityType: pgtype.Text{String: "aml_case", Valid: true},
+		Metadata:   pgtype.Text{String: string(encoded), Valid: true},
+	}, nil
+}
+
+func amlCaseToDTO(c *models.AMLCase) *dto.AMLCaseDTO {
+	out := &dto.AMLCaseDTO{
+		ID:             c.ID,
+		AccountID:      c.AccountID,
+		Typology:       c.Typology,
+		Status:         c.Status,
+		Summary:        c.Summary,
+		Details:        c.Details,
+		AccountFrozen:  c.AccountFrozen,
+		Resolution:     c.Resolution.String,
+		LastDetectedAt: c.LastDetectedAt.Format(time.RFC3339),
+		CreatedAt:      c.CreatedAt.Format(time.RFC3339),
+	}
+	if c.UserID.Valid {
+		id := int(c.UserID.Int32)
+		out.UserID = &id
+	}
+	if c.AssignedTo.Valid {
+		id := int(c.AssignedTo.Int32)
+		out.AssignedTo = &id
+	}
+	if c.EscalatedAt.Valid {
+		out.EscalatedAt = c.EscalatedAt.Time.Format(time.RFC3339)
+	}
+	if c.ClosedAt.Valid {
+		out.ClosedAt = c.ClosedAt.Time.Format(time.RFC3339)
+	}
+	return out
+}
+
+func amlNoteToDTO(n *models.AMLCaseNote) dto.AMLCaseNoteDTO {
+	out := dto.AMLCaseNoteDTO{
+		ID:        n.ID,
+		Body:      n.Body,
+		CreatedAt: n.CreatedAt.Format(time.RFC3339),
+	}
+	if n.AuthorID.Valid {
+		id := int(n.AuthorID.Int32)
+		out.AuthorID = &id
+	}
+	return out
+}
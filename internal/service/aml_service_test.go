@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type structuringQuery struct {
+	since            time.Time
+	lower, threshold int64
+	minCount         int
+}
+
+type rapidQuery struct {
+	since          time.Time
+	minInflow      int64
+	outflowPercent int
+}
+
+type amlCaseKey struct {
+	accountID int64
+	typology  string
+}
+
+// fakeAMLStore records the thresholds each detector was asked for and keeps
+// one live case per account and typology, like the partial unique index
+type fakeAMLStore struct {
+	AMLStore
+	structuring []models.AMLDetection
+	rapid       []models.AMLDetection
+
+	structuringQueries []structuringQuery
+	rapidQueries       []rapidQuery
+	cases              map[amlCaseKey]int64
+	summaries          map[int64]string
+}
+
+func (f *fakeAMLStore) StartScanRun(ctx context.Context) (*models.AMLScanRun, error) {
+	return &models.AMLScanRun{ID: 1, StartedAt: time.Now()}, nil
+}
+
+func (f *fakeAMLStore) FinishScanRun(ctx context.Context, run *models.AMLScanRun) error {
+	return nil
+}
+
+func (f *fakeAMLStore) DetectStructuring(ctx context.Context, since time.Time, lower, threshold int64, minCount int) ([]models.AMLDetection, error) {
+	f.structuringQueries = append(f.structuringQueries, structuringQuery{since, lower, threshold, minCount})
+	return f.structuring, nil
+}
+
+func (f *fakeAMLStore) DetectRapidInOut(ctx context.Context, since time.Time, minInflow int64, outflowPercent int) ([]models.AMLDetection, error) {
+	f.rapidQueries = append(f.rapidQueries, rapidQuery{since, minInflow, outflowPercent})
+	return f.rapid, nil
+}
+
+func (f *fakeAMLStore) DetectFanIn(ctx context.Context, since time.Time, minCounterparties int) ([]models.AMLDetection, error) {
+	return nil, nil
+}
+
+func (f *fakeAMLStore) DetectFanOut(ctx context.Context, since time.Time, minCounterparties int) ([]models.AMLDetection, error) {
+	return nil, nil
+}
+
+func (f *fakeAMLStore) DetectDormantReactivation(ctx context.Context, since time.Time, dormantDays int, minVolume int64) ([]models.AMLDetection, error) {
+	return nil, nil
+}
+
+func (f *fakeAMLStore) UpsertCase(ctx context.Context, runID int64, d *models.AMLDetection) (int64, bool, error) {
+	if f.cases == nil {
+		f.cases = map[amlCaseKey]int64{}
+		f.summaries = map[int64]string{}
+	}
+	key := amlCaseKey{d.AccountID, d.Typology}
+	id, live := f.cases[key]
+	if !live {
+		id = int64(len(f.cases) + 1)
+		f.cases[key] = id
+	}
+	f.summaries[id] = d.Summary
+	return id, !live, nil
+}
+
+func TestRunScan_PassesThresholds(t *testing.T) {
+	store := &fakeAMLStore{}
+	rules := DefaultAMLRules()
+	s := NewAMLService(store, nil, rules)
+
+	before := time.Now()
+	_, err := s.RunScan(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, store.structuringQueries, 1)
+	q := store.structuringQueries[0]
+	assert.Equal(t, int64(45000000), q.lower, "deposits from threshold minus margin count")
+	assert.Equal(t, int64(50000000), q.threshold, "the threshold itself is excluded")
+	assert.Equal(t, 3, q.minCount)
+	assert.WithinDuration(t, before.Add(-72*time.Hour), q.since, time.Minute)
+
+	require.Len(t, store.rapidQueries, 1)
+	r := store.rapidQueries[0]
+	assert.Equal(t, int64(20000000), r.minInflow)
+	assert.Equal(t, 90, r.outflowPercent)
+	assert.WithinDuration(t, before.Add(-24*time.Hour), r.since, time.Minute)
+}
+
+func TestRunScan_CustomThresholds(t *testing.T) {
+	store := &fakeAMLStore{}
+	rules := DefaultAMLRules()
+	rules.StructuringThreshold = 1000000
+	rules.StructuringMargin = 100000
+	rules.StructuringMinCount = 5
+	rules.RapidMinInflow = 300
+	rules.RapidOutflowPercent = 75
+	s := NewAMLService(store, nil, rules)
+
+	_, err := s.RunScan(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(900000), store.structuringQueries[0].lower)
+	assert.Equal(t, int64(1000000), store.structuringQueries[0].threshold)
+	assert.Equal(t, 5, store.structuringQueries[0].minCount)
+	assert.Equal(t, int64(300), store.rapidQueries[0].minInflow)
+	assert.Equal(t, 75, store.rapidQueries[0].outflowPercent)
+}
+
+// A second scan finding the same activity refreshes the live cases instead of opening new ones
+func TestRunScan_ReusesLiveCases(t *testing.T) {
+	store := &fakeAMLStore{
+		structuring: []models.AMLDetection{{
+			AccountID:      10,
+			Typology:       models.AMLTypologyStructuring,
+			Details:        map[string]any{"deposit_count": int64(3), "threshold": int64(50000000)},
+			TransactionIDs: []int64{1, 2, 3},
+		}},
+		rapid: []models.AMLDetection{{
+			AccountID:      10,
+			Typology:       models.AMLTypologyRapidInOut,
+			Details:        map[string]any{"inflow": int64(20000000), "outflow": int64(19000000)},
+			TransactionIDs: []int64{4, 5},
+		}},
+	}
+	s := NewAMLService(store, nil, DefaultAMLRules())
+
+	first, err := s.RunScan(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, first.CasesOpened)
+	assert.Equal(t, 0, first.CasesUpdated)
+
+	second, err := s.RunScan(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, second.CasesOpened)
+	assert.Equal(t, 2, second.CasesUpdated)
+
+	assert.Len(t, store.cases, 2)
+	assert.Equal(t, "3 deposits just under ₦500000.00", store.summaries[store.cases[amlCaseKey{10, models.AMLTypologyStructuring}]])
+	assert.Equal(t, "Received ₦200000.00 and sent out ₦190000.00 across 2 transactions", store.summaries[store.cases[amlCaseKey{10, models.AMLTypologyRapidInOut}]])
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/clientinfo"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// AUDIT LOG
+// ==============================================
+// Every entry records who acted (user_id), what they did (action), what
+// it was done to (entity_type/entity_id), the request's IP and user agent,
+// and JSON metadata. A change writes its entry in the same transaction:
+// repositories take the entry as an argument, and services that own the
+// transaction use AuditLogger.WriteTx. Events that change nothing (a
+// failed sign-in, a code sent, staff viewing a record) use Write or Record.
+
+// securityActions are the entries a user sees in their own security history
+var securityActions = []string{
+	models.AuditActionLogin,
+	models.AuditActionLoginFailed,
+	models.AuditActionPasswordChange,
+	models.AuditActionPinChange,
+	models.AuditActionOTPSent,
+	models.AuditActionOTPVerified,
+	models.AuditActionAccountLocked,
+	models.AuditActionAccountUnlocked,
+	models.AuditActionSettingsChanged,
+	models.AuditActionMFAEnabled,
+	models.AuditActionMFADisabled,
+	models.AuditActionMFARecoveryCodes,
+	models.AuditActionMFARecoveryUsed,
+	models.AuditActionStepUpVerified,
+	models.AuditActionNewDeviceLogin,
+	models.AuditActionDeviceRenamed,
+	models.AuditActionDeviceRevoked,
+	models.AuditActionRoleChanged,
+}
+
+// AuditLogger writes audit entries
+type AuditLogger struct {
+	repo *repository.AuditRepository
+}
+
+func NewAuditLogger(repo *repository.AuditRepository) *AuditLogger {
+	return &AuditLogger{repo: repo}
+}
+
+// Write stores an entry on its own
+func (l *AuditLogger) Write(ctx context.Context, entry *models.AuditLog) error {
+	return l.repo.Insert(ctx, entry)
+}
+
+// WriteTx stores an entry in the caller's transaction, so it commits or rolls back with the change
+func (l *AuditLogger) WriteTx(ctx context.Context, tx pgx.Tx, entry *models.AuditLog) error {
+	return l.repo.InsertTx(ctx, tx, entry)
+}
+
+// Record stores an entry for something that has already happened; a failure is logged, not returned
+func (l *AuditLogger) Record(ctx context.Context, actorID int, action, entityType string, entityID int64, metadata map[string]interface{}) {
+	entry, err := auditEntry(ctx, actorID, action, entityType, entityID, metadata)
+	if err == nil {
+		err = l.repo.Insert(ctx, entry)
+	}
+	if err != nil {
+		log.Printf("[AUDIT] Failed to record %s - UserID: %d: %v", action, actorID, err)
+	}
+}
+
+// auditEntry builds an audit entry stamped with the request's IP and user agent
+// entityID 0 leaves the entity unset, e.g. for searches
+func auditEntry(ctx context.Context, actorID int, action, entityType string, entityID int64, metadata map[string]interface{}) (*models.AuditLog, error) {
+	info := clientinfo.FromContext(ctx)
+	audit := &models.AuditLog{
+		UserID:     pgtype.Int4{Int32: int32(actorID), Valid: true},
+		Action:     action,
+		EntityType: pgtype.Text{String: entityType, Valid: true},
+		EntityID:   pgtype.Int8{Int64: entityID, Valid: entityID != 0},
+		IPAddress:  pgtype.Text{String: info.IPAddress, Valid: info.IPAddress != ""},
+		UserAgent:  pgtype.Text{String: info.UserAgent, Valid: info.UserAgent != ""},
+	}
+
+	if metadata != nil {
+		encoded, err := json.Marshal(metadata)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode audit metadata: %w", err)
+		}
+		audit.Metadata = pgtype.Text{String: string(encoded), Valid: true}
+	}
+	return audit, nil
+}
+
+// ==============================================
+// AUDIT SERVICE
+// ==============================================
+
+// AuditService reads the audit log for users and staff
+type AuditService struct {
+	repo   *repository.AuditRepository
+	logger *AuditLogger
+}
+
+func NewAuditService(repo *repository.AuditRepository, logger *AuditLogger) *AuditService {
+	return &AuditService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// ListSecurityActivity returns the sign-ins, credential changes and staff actions on the caller's account
+func (s *AuditService) ListSecurityActivity(ctx context.Context, userID int, req dto.ListSecurityActivityRequest) (*dto.SecurityActivityResponse, error) {
+	page, perPage := pageOrDefault(req.Page, req.PerPage)
+
+	logs, err := s.repo.ListUserActivity(ctx, userID, securityActions, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.SecurityActivityResponse{
+		Events:  make([]dto.SecurityEventDTO, len(logs)),
+		Page:    page,
+		PerPage: perPage,
+	}
+	for i, a := range logs {
+		event := dto.SecurityEventDTO{
+			ID:        a.ID,
+			Action:    a.Action,
+			ByStaff:   !a.UserID.Valid || int(a.UserID.Int32) != userID,
+			IPAddress: a.IPAddress.String,
+			UserAgent: a.UserAgent.String,
+			CreatedAt: a.CreatedAt.Format(time.RFC3339),
+		}
+		// Staff notes and their network details stay internal
+		if event.ByStaff {
+			event.IPAddress, event.UserAgent = "", ""
+		} else if a.Metadata.Valid {
+			_ = json.Unmarshal([]byte(a.Metadata.String), &event.Details)
+		}
+		resp.Events[i] = event
+	}
+	return resp, nil
+}
+
+// SearchLogs returns audit entries matching the filters; the search itself is audited
+func (s *AuditService) SearchLogs(ctx context.Context, actorID int, req dto.SearchAuditLogsRequest) (*dto.AuditLogListResponse, error) {
+	page, perPage := pageOrDefault(req.Page, req.PerPage)
+
+	filter := repository.AuditLogFilter{
+		ActorID:    req.ActorID,
+		SubjectID:  req.UserID,
+		Action:     req.Action,
+		EntityType: req.EntityType,
+		EntityID:   req.EntityID,
+		From:       req.From,
+		To:         req.To,
+	}
+	logs, err := s.repo.Search(ctx, filter, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := auditEntry(ctx, actorID, models.AuditActionAdminAuditSearch, "audit_log", 0, map[string]interface{}{
+		"filter":  auditFilterMetadata(req),
+		"page":    page,
+		"results": len(logs),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.logger.Write(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	resp := &dto.AuditLogListResponse{
+		Logs:    make([]dto.AuditLogDTO, len(logs)),
+		Page:    page,
+		PerPage: perPage,
+	}
+	for i, a := range logs {
+		out := dto.AuditLogDTO{
+			ID:         a.ID,
+			Action:     a.Action,
+			EntityType: a.EntityType.String,
+			IPAddress:  a.IPAddress.String,
+			UserAgent:  a.UserAgent.String,
+			CreatedAt:  a.CreatedAt.Format(time.RFC3339),
+		}
+		if a.UserID.Valid {
+			id := int(a.UserID.Int32)
+			out.ActorID = &id
+		}
+		if a.EntityID.Valid {
+			id := a.EntityID.Int64
+			out.EntityID = &id
+		}
+		if a.Metadata.Valid {
+			out.Metadata = json.RawMessage(a.Metadata.String)
+		}
+		resp.Logs[i] = out
+	}
+	return resp, nil
+}
+
+// auditFilterMetadata keeps only the filters that were set
+func auditFilterMetadata(req dto.SearchAuditLogsRequest) map[string]interface{} {
+	filter := map[string]interface{}{}
+	if req.ActorID != 0 {
+		filter["actor_id"] = req.ActorID
+	}
+	if req.UserID != 0 {
+		filter["user_id"] = req.UserID
+	}
+	if req.Action != "" {
+		filter["action"] = req.Action
+	}
+	if req.EntityType != "" {
+		filter["entity_type"] = req.EntityType
+	}
+	if req.EntityID != 0 {
+		filter["entity_id"] = req.EntityID
+	}
+	if !req.From.IsZero() {
+		filter["from"] = req.From.Format(time.RFC3339)
+	}
+	if !req.To.IsZero() {
+		filter["to"] = req.To.Format(time.RFC3339)
+	}
+	return filter
+}
+
+func pageOrDefault(page, perPage int) (int, int) {
+	if page == 0 {
+		page = 1
+	}
+	if perPage == 0 {
+		perPage = 20
+	}
+	return page, perPage
+}
@@ -4,13 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/Brownie44l1/debank/internal/api/dto"
 	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/Brownie44l1/debank/internal/clientinfo"
+	"github.com/Brownie44l1/debank/internal/metrics"
 	"github.com/Brownie44l1/debank/internal/models"
 	"github.com/Brownie44l1/debank/internal/repository"
+	"github.com/Brownie44l1/debank/internal/tracing"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -18,27 +23,90 @@ import (
 // AUTH SERVICE
 // ==============================================
 
+var (
+	ErrContactUnchanged = errors.New("new value is the same as the current one")
+)
+
+// Same format the users.phone_format constraint enforces
+var phonePattern = regexp.MustCompile(`^\+?[0-9]{10,15}$`)
+
+// SignupScreener checks new users against sanctions lists (implemented by ScreeningService)
+type SignupScreener interface {
+	ScreenSignup(ctx context.Context, userID int, name string) error
+}
+
+// TokenIssuer signs access tokens for login sessions (implemented by auth.Keyring)
+type TokenIssuer interface {
+	GenerateJWT(userID int, role, sessionID string) (string, int, error)
+}
+
+// DeviceRegistry tracks the devices users sign in from and their sessions (implemented by DeviceService)
+type DeviceRegistry interface {
+	IsRecognised(ctx context.Context, userID int, fingerprint string) (bool, error)
+	StartSession(ctx context.Context, user *models.User, fingerprint string) (string, error)
+}
+
+// UserStore reads and updates user accounts (implemented by repository.UserRepository)
+type UserStore interface {
+	UserLookup
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	IsUsernameAvailable(ctx context.Context, username string) (bool, error)
+	CreateUser(ctx context.Context, user *models.User) error
+	CompleteOnboarding(ctx context.Context, userID int) error
+	SetUsername(ctx context.Context, userID int, username string) error
+	SetPin(ctx context.Context, userID int, pinHash string, audit *models.AuditLog) error
+	VerifyEmail(ctx context.Context, userID int, audit *models.AuditLog) error
+	UpdateEmail(ctx context.Context, userID int, email string, audit *models.AuditLog) error
+	UpdatePhone(ctx context.Context, userID int, phone string, audit *models.AuditLog) error
+	UpdatePassword(ctx context.Context, userID int, passwordHash string, audit *models.AuditLog) error
+	ResetPassword(ctx context.Context, userID int, passwordHash string, audit *models.AuditLog) error
+	UpdateLastLogin(ctx context.Context, userID int) error
+	RecordFailedLogin(ctx context.Context, userID int, lockUntil time.Time, audits ...*models.AuditLog) error
+	UnlockAccount(ctx context.Context, userID int, audit *models.AuditLog) error
+}
+
 type AuthService struct {
-	userRepo         *repository.UserRepository
+	userRepo         UserStore
 	verificationRepo *repository.VerificationRepository
 	walletRepo       *repository.WalletRepository
 	emailService     *EmailService
-	jwtSecret        string
+	mfaRepo          *repository.MFARepository
+	screener         SignupScreener
+	stepUp           StepUpGuard
+	devices          DeviceRegistry
+	tokens           TokenIssuer
+	audit            *AuditLogger
+	mfaKey           []byte
+	newDeviceStepUp  bool // Unrecognised devices must enter an emailed code at login
 }
 
 func NewAuthService(
-	userRepo *repository.UserRepository,
+	userRepo UserStore,
 	verificationRepo *repository.VerificationRepository,
 	walletRepo *repository.WalletRepository,
 	emailService *EmailService,
-	jwtSecret string,
+	mfaRepo *repository.MFARepository,
+	screener SignupScreener,
+	stepUp StepUpGuard,
+	devices DeviceRegistry,
+	tokens TokenIssuer,
+	audit *AuditLogger,
+	mfaKey []byte,
+	newDeviceStepUp bool,
 ) *AuthService {
 	return &AuthService{
 		userRepo:         userRepo,
 		verificationRepo: verificationRepo,
 		walletRepo:       walletRepo,
 		emailService:     emailService,
-		jwtSecret:        jwtSecret,
+		mfaRepo:          mfaRepo,
+		screener:         screener,
+		stepUp:           stepUp,
+		devices:          devices,
+		tokens:           tokens,
+		audit:            audit,
+		mfaKey:           mfaKey,
+		newDeviceStepUp:  newDeviceStepUp,
 	}
 }
 
@@ -84,10 +152,16 @@ func (s *AuthService) Signup(ctx context.Context, req dto.SignupRequest) (*dto.S
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	// 5. Send email verification OTP (async)
+	// 5. Sanctions screening - a potential match freezes the new account until compliance reviews it
+	// The user isn't told, so signup succeeds either way
+	if err := s.screener.ScreenSignup(ctx, int(user.ID), user.Name); err != nil {
+		log.Printf("[SCREENING] Signup screening failed - UserID: %d, Error: %v", user.ID, err)
+	}
+
+	// 6. Send email verification OTP (async)
 	go s.sendEmailVerificationOTP(context.Background(), user.Email, int(user.ID))
 
-	// 6. Build response
+	// 7. Build response
 	userDTO := s.userToDTO(user)
 
 	return &dto.SignupResponse{
@@ -119,7 +193,13 @@ func (s *AuthService) VerifyEmail(ctx context.Context, req dto.VerifyEmailReques
 	}
 
 	// 3. Mark email as verified
-	if err := s.userRepo.VerifyEmail(ctx, user.ID); err != nil {
+	audit, err := userAudit(ctx, int(user.ID), models.AuditActionOTPVerified, map[string]interface{}{
+		"purpose": models.OTPPurposeEmailVerify,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.VerifyEmail(ctx, int(user.ID), audit); err != nil {
 		return nil, fmt.Errorf("failed to mark email as verified: %w", err)
 	}
 
@@ -155,14 +235,14 @@ func (s *AuthService) ResendOTP(ctx context.Context, req dto.ResendOTPRequest) (
 		return nil, errors.New("too many OTP requests, please try again later")
 	}
 
-	// 3. Get user (if purpose requires it)
+	// 3. Get user (password reset requires one)
 	var userID *int
-	if req.Purpose == models.OTPPurposePasswordReset {
-		user, err := s.userRepo.GetUserByEmail(ctx, req.Email)
-		if err != nil {
-			return nil, models.ErrUserNotFound
-		}
-		userID = &user.ID
+	user, err := s.userRepo.GetUserByEmail(ctx, req.Email)
+	if err == nil {
+		id := int(user.ID)
+		userID = &id
+	} else if req.Purpose == models.OTPPurposePasswordReset {
+		return nil, models.ErrUserNotFound
 	}
 
 	// 4. Generate and send new OTP
@@ -188,6 +268,9 @@ func (s *AuthService) ResendOTP(ctx context.Context, req dto.ResendOTPRequest) (
 	if err := s.emailService.SendOTP(req.Email, code, req.Purpose); err != nil {
 		return nil, fmt.Errorf("failed to send OTP email: %w", err)
 	}
+	if userID != nil {
+		s.recordOTPSent(ctx, *userID, req.Purpose)
+	}
 
 	return &dto.ResendOTPResponse{
 		Success:   true,
@@ -234,7 +317,11 @@ func (s *AuthService) CompleteOnboarding(ctx context.Context, userID int, req dt
 	}
 
 	// 6. Set PIN
-	if err := s.userRepo.SetPin(ctx, userID, pinHash); err != nil {
+	audit, err := userAudit(ctx, userID, models.AuditActionPinChange, map[string]interface{}{"first_pin": true})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.SetPin(ctx, userID, pinHash, audit); err != nil {
 		return nil, fmt.Errorf("failed to set PIN: %w", err)
 	}
 
@@ -270,6 +357,9 @@ func (s *AuthService) CompleteOnboarding(ctx context.Context, userID int, req dt
 // ==============================================
 
 func (s *AuthService) Login(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Login")
+	defer span.End()
+
 	// 1. Determine identifier type and get user
 	var user *models.User
 	var err error
@@ -290,6 +380,7 @@ func (s *AuthService) Login(ctx context.Context, req dto.LoginRequest) (*dto.Log
 
 	if err != nil {
 		if errors.Is(err, repository.ErrUserNotFound) {
+			metrics.LoginFailures.WithLabelValues("unknown_user").Inc()
 			return nil, models.ErrInvalidCredentials
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
@@ -297,41 +388,108 @@ func (s *AuthService) Login(ctx context.Context, req dto.LoginRequest) (*dto.Log
 
 	// 2. Check if account is locked
 	if user.IsLocked() {
+		s.audit.Record(ctx, int(user.ID), models.AuditActionLoginFailed, "user", int64(user.ID), map[string]interface{}{"reason": "account_locked"})
+		metrics.LoginFailures.WithLabelValues("account_locked").Inc()
 		return nil, models.ErrAccountLocked
 	}
 
 	// 3. Check if account is active
 	if !user.IsActive {
+		s.audit.Record(ctx, int(user.ID), models.AuditActionLoginFailed, "user", int64(user.ID), map[string]interface{}{"reason": "account_inactive"})
+		metrics.LoginFailures.WithLabelValues("account_inactive").Inc()
 		return nil, models.ErrAccountInactive
 	}
 
 	// 4. Verify password
 	if !auth.CheckPassword(req.Password, user.PasswordHash) {
-		// Increment failed login attempts
-		_ = s.userRepo.IncrementFailedLogins(ctx, user.ID)
-
-		// Lock account after 5 failed attempts
-		if user.FailedLoginAttempts >= 4 { // Will be 5 after increment
-			lockUntil := time.Now().Add(30 * time.Minute)
-			_ = s.userRepo.LockAccount(ctx, user.ID, lockUntil)
-			return nil, errors.New("account locked due to too many failed login attempts")
+		return nil, s.recordFailedLogin(ctx, user, models.ErrInvalidCredentials)
+	}
+
+	// 5. With 2FA on, hand back a challenge instead of a token
+	mfa, err := s.mfaRepo.GetMFA(ctx, int(user.ID))
+	if err != nil && !errors.Is(err, repository.ErrMFANotFound) {
+		return nil, err
+	}
+	if mfa != nil && mfa.IsEnabled() {
+		return s.startMFAChallenge(ctx, user, mfa.Method, models.MFAReasonEnabled)
+	}
+
+	// 6. Optionally, an unrecognised device has to confirm an emailed code first
+	fingerprint := clientinfo.FromContext(ctx).DeviceID
+	if s.newDeviceStepUp {
+		recognised, err := s.devices.IsRecognised(ctx, int(user.ID), fingerprint)
+		if err != nil {
+			return nil, err
 		}
+		if !recognised {
+			return s.startMFAChallenge(ctx, user, models.MFAMethodEmail, models.MFAReasonNewDevice)
+		}
+	}
 
-		return nil, models.ErrInvalidCredentials
+	return s.completeLogin(ctx, user, fingerprint)
+}
+
+// recordFailedLogin counts a wrong password or second-factor code, locking the account after 5
+func (s *AuthService) recordFailedLogin(ctx context.Context, user *models.User, failure error) error {
+	userID := int(user.ID)
+	reason := "invalid_password"
+	if errors.Is(failure, ErrMFACodeInvalid) {
+		reason = "invalid_mfa_code"
+	}
+
+	failed, err := userAudit(ctx, userID, models.AuditActionLoginFailed, map[string]interface{}{
+		"reason":  reason,
+		"attempt": user.FailedLoginAttempts + 1,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Lock account after 5 failed attempts
+	var lockUntil time.Time
+	var locked *models.AuditLog
+	if user.FailedLoginAttempts >= 4 { // Will be 5 after increment
+		lockUntil = time.Now().Add(30 * time.Minute)
+		locked, err = userAudit(ctx, userID, models.AuditActionAccountLocked, map[string]interface{}{
+			"reason":       "too_many_failed_logins",
+			"locked_until": lockUntil.Format(time.RFC3339),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.userRepo.RecordFailedLogin(ctx, userID, lockUntil, failed, locked); err != nil {
+		log.Printf("[AUTH] Failed to record failed login - UserID: %d: %v", userID, err)
+	}
+
+	metrics.LoginFailures.WithLabelValues(reason).Inc()
+	if locked != nil {
+		metrics.AccountLockouts.WithLabelValues(metrics.LockoutFailedLogins).Inc()
+		return errors.New("account locked due to too many failed login attempts")
 	}
+	return failure
+}
 
-	// 5. Update last login and reset failed attempts
-	if err := s.userRepo.UpdateLastLogin(ctx, user.ID); err != nil {
+// completeLogin opens a session on the device and issues its access token once every factor has been checked
+func (s *AuthService) completeLogin(ctx context.Context, user *models.User, fingerprint string) (*dto.LoginResponse, error) {
+	// Update last login and reset failed attempts
+	if err := s.userRepo.UpdateLastLogin(ctx, int(user.ID)); err != nil {
 		return nil, fmt.Errorf("failed to update last login: %w", err)
 	}
 
-	// 6. Generate JWT token
-	token, expiresIn, err := auth.GenerateJWT(user.ID, s.jwtSecret)
+	sessionID, err := s.devices.StartSession(ctx, user, fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+
+	// Generate JWT token
+	token, expiresIn, err := s.tokens.GenerateJWT(int(user.ID), user.Role, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 
-	// 7. Build response
+	// Build response
 	userDTO := s.userToDTO(user)
 
 	return &dto.LoginResponse{
@@ -380,6 +538,7 @@ func (s *AuthService) ForgotPassword(ctx context.Context, req dto.ForgotPassword
 	if err := s.emailService.SendOTP(user.Email, code, models.OTPPurposePasswordReset); err != nil {
 		return nil, fmt.Errorf("failed to send email: %w", err)
 	}
+	s.recordOTPSent(ctx, int(user.ID), models.OTPPurposePasswordReset)
 
 	// 4. Mask email
 	maskedEmail := maskEmail(user.Email)
@@ -413,14 +572,24 @@ func (s *AuthService) ResetPassword(ctx context.Context, req dto.ResetPasswordRe
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	// 4. Update password
-	if err := s.userRepo.UpdatePassword(ctx, user.ID, passwordHash); err != nil {
+	// 4. Update password (also starts the post-reset risk window)
+	audit, err := userAudit(ctx, int(user.ID), models.AuditActionPasswordChange, map[string]interface{}{"method": "reset"})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.ResetPassword(ctx, int(user.ID), passwordHash, audit); err != nil {
 		return nil, fmt.Errorf("failed to update password: %w", err)
 	}
 
 	// 5. Unlock account if locked
 	if user.IsLocked() {
-		_ = s.userRepo.UnlockAccount(ctx, user.ID)
+		unlocked, err := userAudit(ctx, int(user.ID), models.AuditActionAccountUnlocked, map[string]interface{}{"reason": "password_reset"})
+		if err == nil {
+			err = s.userRepo.UnlockAccount(ctx, int(user.ID), unlocked)
+		}
+		if err != nil {
+			log.Printf("[AUTH] Failed to unlock after password reset - UserID: %d: %v", user.ID, err)
+		}
 	}
 
 	return &dto.ResetPasswordResponse{
@@ -448,7 +617,11 @@ func (s *AuthService) ChangePassword(ctx context.Context, userID int, req dto.Ch
 	}
 
 	// 4. Update password
-	if err := s.userRepo.UpdatePassword(ctx, userID, passwordHash); err != nil {
+	audit, err := userAudit(ctx, userID, models.AuditActionPasswordChange, map[string]interface{}{"method": "change"})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.UpdatePassword(ctx, userID, passwordHash, audit); err != nil {
 		return nil, fmt.Errorf("failed to update password: %w", err)
 	}
 
@@ -458,11 +631,121 @@ func (s *AuthService) ChangePassword(ctx context.Context, userID int, req dto.Ch
 	}, nil
 }
 
+// ==============================================
+// CONTACT DETAILS
+// ==============================================
+
+// ChangeEmail moves the account to a new email address after step-up authentication
+// The new address starts unverified and is sent a verification code
+func (s *AuthService) ChangeEmail(ctx context.Context, userID int, req dto.ChangeEmailRequest) (*dto.ChangeContactResponse, error) {
+	email := strings.TrimSpace(req.NewEmail)
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if strings.EqualFold(email, user.Email) {
+		return nil, ErrContactUnchanged
+	}
+
+	existingUser, err := s.userRepo.GetUserByEmail(ctx, email)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, fmt.Errorf("failed to check email: %w", err)
+	}
+	if existingUser != nil {
+		return nil, models.ErrEmailAlreadyExists
+	}
+
+	op := StepUpOperation{Action: models.StepUpActionEmailChange, Params: map[string]string{"email": email}}
+	if _, err := s.stepUp.Require(ctx, userID, op, req.StepUp); err != nil {
+		return nil, err
+	}
+
+	audit, err := userAudit(ctx, userID, models.AuditActionSettingsChanged, map[string]interface{}{"field": "email"})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.UpdateEmail(ctx, userID, email, audit); err != nil {
+		if errors.Is(err, repository.ErrUserAlreadyExists) {
+			return nil, models.ErrEmailAlreadyExists
+		}
+		return nil, err
+	}
+
+	log.Printf("[AUTH] Email changed - UserID: %d", userID)
+	go s.sendEmailVerificationOTP(context.Background(), email, userID)
+
+	return &dto.ChangeContactResponse{
+		Success:  true,
+		Message:  "Email updated. Please check your new inbox for a verification code.",
+		NextStep: "verify_email",
+	}, nil
+}
+
+// ChangePhone changes the phone number used to log in after step-up authentication
+func (s *AuthService) ChangePhone(ctx context.Context, userID int, req dto.ChangePhoneRequest) (*dto.ChangeContactResponse, error) {
+	phone := strings.TrimSpace(req.NewPhone)
+	if !phonePattern.MatchString(phone) {
+		return nil, models.ErrInvalidPhone
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if phone == user.Phone {
+		return nil, ErrContactUnchanged
+	}
+
+	existingUser, err := s.userRepo.GetUserByPhone(ctx, phone)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, fmt.Errorf("failed to check phone: %w", err)
+	}
+	if existingUser != nil {
+		return nil, models.ErrPhoneAlreadyExists
+	}
+
+	op := StepUpOperation{Action: models.StepUpActionPhoneChange, Params: map[string]string{"phone": phone}}
+	if _, err := s.stepUp.Require(ctx, userID, op, req.StepUp); err != nil {
+		return nil, err
+	}
+
+	audit, err := userAudit(ctx, userID, models.AuditActionSettingsChanged, map[string]interface{}{"field": "phone"})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.UpdatePhone(ctx, userID, phone, audit); err != nil {
+		if errors.Is(err, repository.ErrUserAlreadyExists) {
+			return nil, models.ErrPhoneAlreadyExists
+		}
+		return nil, err
+	}
+
+	log.Printf("[AUTH] Phone changed - UserID: %d", userID)
+	return &dto.ChangeContactResponse{
+		Success: true,
+		Message: "Phone number updated. Use it to log in from now on.",
+	}, nil
+}
+
 // ==============================================
 // PIN MANAGEMENT
 // ==============================================
 
 func (s *AuthService) SetPin(ctx context.Context, userID int, req dto.SetPinRequest) (*dto.SetPinResponse, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// Setting the first PIN is part of onboarding; changing one needs a fresh second factor
+	if user.HasPin() {
+		op := StepUpOperation{Action: models.StepUpActionPinChange}
+		if _, err := s.stepUp.Require(ctx, userID, op, req.StepUp); err != nil {
+			return nil, err
+		}
+	}
+
 	// Hash PIN
 	pinHash, err := auth.HashPin(req.Pin)
 	if err != nil {
@@ -470,7 +753,11 @@ func (s *AuthService) SetPin(ctx context.Context, userID int, req dto.SetPinRequ
 	}
 
 	// Set PIN
-	if err := s.userRepo.SetPin(ctx, userID, pinHash); err != nil {
+	audit, err := userAudit(ctx, userID, models.AuditActionPinChange, map[string]interface{}{"first_pin": !user.HasPin()})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.SetPin(ctx, userID, pinHash, audit); err != nil {
 		return nil, fmt.Errorf("failed to set PIN: %w", err)
 	}
 
@@ -524,17 +811,28 @@ func (s *AuthService) sendEmailVerificationOTP(ctx context.Context, email string
 
 	if err := s.emailService.SendOTP(email, code, models.OTPPurposeEmailVerify); err != nil {
 		fmt.Printf("Failed to send OTP email: %v\n", err)
+		return
 	}
+	s.recordOTPSent(ctx, userID, models.OTPPurposeEmailVerify)
+}
+
+// recordOTPSent audits a verification code going out; the code itself is never logged
+func (s *AuthService) recordOTPSent(ctx context.Context, userID int, purpose string) {
+	s.audit.Record(ctx, userID, models.AuditActionOTPSent, "user", int64(userID), map[string]interface{}{
+		"purpose": purpose,
+		"channel": "email",
+	})
 }
 
 func (s *AuthService) userToDTO(user *models.User) *dto.UserDTO {
 	userDTO := &dto.UserDTO{
-		ID:                  user.ID,
+		ID:                  int(user.ID),
 		Name:                user.Name,
 		Phone:               user.Phone,
 		Email:               user.Email,
 		IsEmailVerified:     user.IsEmailVerified,
 		OnboardingCompleted: user.OnboardingCompleted,
+		Role:                user.Role,
 		CreatedAt:           user.CreatedAt.Format(time.RFC3339),
 	}
 
@@ -576,4 +874,4 @@ func maskEmail(email string) string {
 	}
 
 	return username[0:1] + "***" + username[len(username)-1:] + "@" + domain
-}
\ No newline at end of file
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// BUSINESS RULES (Constants)
+// ==============================================
+
+const (
+	DefaultRecipientLimit = 10
+)
+
+// ==============================================
+// SERVICE ERRORS
+// ==============================================
+
+var (
+	ErrBeneficiaryNotFound = errors.New("beneficiary not found")
+	ErrBeneficiaryExists   = errors.New("beneficiary already saved")
+)
+
+// ==============================================
+// SERVICE
+// ==============================================
+
+// BeneficiaryScreener screens saved recipients against sanctions lists (implemented by ScreeningService)
+type BeneficiaryScreener interface {
+	ScreenBeneficiary(ctx context.Context, userID int, beneficiaryID int64, name string) (bool, error)
+}
+
+// BeneficiaryStore keeps saved recipients and summarises past ones (implemented by repository.BeneficiaryRepository)
+type BeneficiaryStore interface {
+	CreateBeneficiary(ctx context.Context, b *models.Beneficiary) error
+	ListBeneficiaries(ctx context.Context, userID int) ([]models.Beneficiary, error)
+	DeleteBeneficiary(ctx context.Context, userID int, id int64) error
+	ListRecipients(ctx context.Context, userID int, order string, limit int) ([]models.RecipientSummary, error)
+}
+
+type BeneficiaryService struct {
+	repo       BeneficiaryStore
+	walletRepo WalletRepositoryInterface
+	screener   BeneficiaryScreener
+	stepUp     StepUpGuard
+}
+
+func NewBeneficiaryService(repo BeneficiaryStore, walletRepo WalletRepositoryInterface, screener BeneficiaryScreener, stepUp StepUpGuard) *BeneficiaryService {
+	return &BeneficiaryService{repo: repo, walletRepo: walletRepo, screener: screener, stepUp: stepUp}
+}
+
+// ==============================================
+// NAME ENQUIRY
+// ==============================================
+
+// NameEnquiry resolves an identifier to the masked account it would pay, without moving money
+func (s *BeneficiaryService) NameEnquiry(ctx context.Context, userID int, req dto.NameEnquiryRequest) (*dto.NameEnquiryResponse, error) {
+	identifier := strings.TrimSpace(req.Identifier)
+
+	account, err := s.resolveRecipient(ctx, userID, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.NameEnquiryResponse{
+		Identifier:    identifier,
+		AccountName:   maskName(account.Name),
+		AccountNumber: maskAccountNumber(account.AccountNumber.String),
+	}, nil
+}
+
+// ==============================================
+// BENEFICIARIES
+// ==============================================
+
+// Create saves a recipient to the user's address book
+func (s *BeneficiaryService) Create(ctx context.Context, userID int, req dto.CreateBeneficiaryRequest) (*dto.BeneficiaryDTO, error) {
+	identifier := strings.TrimSpace(req.Identifier)
+
+	account, err := s.resolveRecipient(ctx, userID, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	// A saved beneficiary is a trusted payee, so adding one needs a fresh second factor
+	op := StepUpOperation{
+		Action: models.StepUpActionBeneficiaryAdd,
+		Params: map[string]string{"account_id": strconv.FormatInt(account.ID, 10)},
+	}
+	if _, err := s.stepUp.Require(ctx, userID, op, req.StepUp); err != nil {
+		return nil, err
+	}
+
+	b := &models.Beneficiary{
+		UserID:        int32(userID),
+		AccountID:     account.ID,
+		Identifier:    identifier,
+		AccountNumber: account.AccountNumber.String,
+		AccountName:   account.Name,
+	}
+	if nickname := strings.TrimSpace(req.Nickname); nickname != "" {
+		b.Nickname = pgtype.Text{String: nickname, Valid: true}
+	}
+
+	if err := s.repo.CreateBeneficiary(ctx, b); err != nil {
+		if errors.Is(err, repository.ErrBeneficiaryExists) {
+			return nil, ErrBeneficiaryExists
+		}
+		return nil, err
+	}
+
+	// A potential sanctions match holds the beneficiary until compliance reviews it
+	// If screening can't run, don't keep an unscreened payee: drop it and fail the request
+	held, err := s.screener.ScreenBeneficiary(ctx, userID, b.ID, account.Name)
+	if err != nil {
+		log.Printf("[SCREENING] Beneficiary screening failed - BeneficiaryID: %d, Error: %v", b.ID, err)
+		if delErr := s.repo.DeleteBeneficiary(ctx, userID, b.ID); delErr != nil {
+			log.Printf("[BENEFICIARY] Failed to remove unscreened beneficiary - BeneficiaryID: %d, Error: %v", b.ID, delErr)
+		}
+		return nil, fmt.Errorf("failed to screen beneficiary: %w", err)
+	}
+	if held {
+		b.Status = models.BeneficiaryStatusHeld
+	}
+
+	log.Printf("[BENEFICIARY] Saved - UserID: %d, BeneficiaryID: %d, Status: %s", userID, b.ID, b.Status)
+	return beneficiaryToDTO(b), nil
+}
+
+// List returns the user's saved beneficiaries
+func (s *BeneficiaryService) List(ctx context.Context, userID int) (*dto.BeneficiaryListResponse, error) {
+	beneficiaries, err := s.repo.ListBeneficiaries(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.BeneficiaryListResponse{Beneficiaries: make([]dto.BeneficiaryDTO, 0, len(beneficiaries))}
+	for i := range beneficiaries {
+		resp.Beneficiaries = append(resp.Beneficiaries, *beneficiaryToDTO(&beneficiaries[i]))
+	}
+
+	return resp, nil
+}
+
+// Delete removes a beneficiary from the user's address book
+func (s *BeneficiaryService) Delete(ctx context.Context, userID int, id int64) error {
+	if err := s.repo.DeleteBeneficiary(ctx, userID, id); err != nil {
+		if errors.Is(err, repository.ErrBeneficiaryNotFound) {
+			return ErrBeneficiaryNotFound
+		}
+		return err
+	}
+
+	log.Printf("[BENEFICIARY] Deleted - UserID: %d, BeneficiaryID: %d", userID, id)
+	return nil
+}
+
+// ==============================================
+// RECENT / FREQUENT RECIPIENTS
+// ==============================================
+
+// Recipients lists accounts the user has paid before, built from transaction history
+func (s *BeneficiaryService) Recipients(ctx context.Context, userID int, req dto.ListRecipientsRequest) (*dto.RecipientListResponse, error) {
+	order := req.Order
+	if order == "" {
+		order = repository.RecipientOrderRecent
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultRecipientLimit
+	}
+
+	recipients, err := s.repo.ListRecipients(ctx, userID, order, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	beneficiaries, err := s.repo.ListBeneficiaries(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	saved := make(map[int64]bool, len(beneficiaries))
+	for _, b := range beneficiaries {
+		saved[b.AccountID] = true
+	}
+
+	resp := &dto.RecipientListResponse{
+		Order:      order,
+		Recipients: make([]dto.RecipientDTO, 0, len(recipients)),
+	}
+	for _, r := range recipients {
+		resp.Recipients = append(resp.Recipients, dto.RecipientDTO{
+			Identifier:     r.LastIdentifier,
+			AccountName:    maskName(r.AccountName),
+			AccountNumber:  maskAccountNumber(r.AccountNumber),
+			TransferCount:  r.TransferCount,
+			TotalAmount:    r.TotalAmount,
+			TotalAmountNGN: float64(r.TotalAmount) / 100,
+			LastTransferAt: r.LastTransferAt.Format(time.RFC3339),
+			IsBeneficiary:  saved[r.AccountID],
+		})
+	}
+
+	return resp, nil
+}
+
+// ==============================================
+// HELPERS
+// ==============================================
+
+// resolveRecipient applies the same recipient rules as Transfer
+func (s *BeneficiaryService) resolveRecipient(ctx context.Context, userID int, identifier string) (*models.Account, error) {
+	account, err := s.walletRepo.GetAccountByIdentifier(ctx, identifier)
+	if err != nil {
+		if isAccountNotFoundError(err) {
+			return nil, ErrRecipientNotFound
+		}
+		return nil, err
+	}
+	if account.IsSystemAccount() {
+		return nil, ErrRecipientNotFound
+	}
+	if account.UserID.Valid && int(account.UserID.Int32) == userID {
+		return nil, ErrSameAccount
+	}
+
+	return account, nil
+}
+
+func beneficiaryToDTO(b *models.Beneficiary) *dto.BeneficiaryDTO {
+	resp := &dto.BeneficiaryDTO{
+		ID:            b.ID,
+		Identifier:    b.Identifier,
+		AccountName:   maskName(b.AccountName),
+		AccountNumber: maskAccountNumber(b.AccountNumber),
+		Status:        b.Status,
+		CreatedAt:     b.CreatedAt.Format(time.RFC3339),
+	}
+	if b.Nickname.Valid {
+		nickname := b.Nickname.String
+		resp.Nickname = &nickname
+	}
+	return resp
+}
+
+// maskName keeps the first name and the initial of every other name: "Ada O*** N***"
+func maskName(name string) string {
+	parts := strings.Fields(name)
+	for i := 1; i < len(parts); i++ {
+		r := []rune(parts[i])
+		parts[i] = string(r[0]) + "***"
+	}
+	return strings.Join(parts, " ")
+}
+
+// maskAccountNumber keeps the last 4 digits: "******5678"
+// A short number keeps at most half, so it is never shown in full: "**34"
+func maskAccountNumber(accountNumber string) string {
+	r := []rune(accountNumber)
+	keep := min(4, len(r)/2)
+	return strings.Repeat("*", len(r)-keep) + string(r[len(r)-keep:])
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskName(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "full name", in: "Ada Obi Nwosu", want: "Ada O*** N***"},
+		{name: "single word", in: "Ada", want: "Ada"},
+		{name: "single letter surname", in: "Ada O", want: "Ada O***"},
+		{name: "extra whitespace", in: "  Ada   Obi ", want: "Ada O***"},
+		{name: "empty", in: "", want: ""},
+		{name: "multi-byte initials", in: "Zoë Ångström Øyen", want: "Zoë Å*** Ø***"},
+		{name: "non-latin", in: "Чиома Ёлкина", want: "Чиома Ё***"},
+		{name: "emoji surname", in: "Ada 🦊Fox", want: "Ada 🦊***"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, maskName(tt.in))
+		})
+	}
+}
+
+func TestMaskAccountNumber(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "nuban", in: "0123456789", want: "******6789"},
+		{name: "five digits", in: "12345", want: "***45"},
+		{name: "four digits", in: "1234", want: "**34"},
+		{name: "three digits", in: "123", want: "**3"},
+		{name: "one digit", in: "7", want: "*"},
+		{name: "empty", in: "", want: ""},
+		{name: "multi-byte", in: "ÅB12345678", want: "******5678"},
+		{name: "multi-byte tail", in: "12345678ØÅ", want: "******78ØÅ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, maskAccountNumber(tt.in))
+		})
+	}
+}
+
+// fakeBeneficiaryStore returns fixed recipients and saved beneficiaries
+type fakeBeneficiaryStore struct {
+	BeneficiaryStore
+	recipients    []models.RecipientSummary
+	beneficiaries []models.Beneficiary
+	order         string
+	limit         int
+}
+
+func (f *fakeBeneficiaryStore) ListRecipients(ctx context.Context, userID int, order string, limit int) ([]models.RecipientSummary, error) {
+	f.order, f.limit = order, limit
+	return f.recipients, nil
+}
+
+func (f *fakeBeneficiaryStore) ListBeneficiaries(ctx context.Context, userID int) ([]models.Beneficiary, error) {
+	return f.beneficiaries, nil
+}
+
+func TestRecipients_MasksEveryRecipient(t *testing.T) {
+	store := &fakeBeneficiaryStore{
+		recipients: []models.RecipientSummary{
+			{AccountID: 10, AccountNumber: "0123456789", AccountName: "Ada Obi", LastIdentifier: "@ada", TransferCount: 3, TotalAmount: 150000, LastTransferAt: time.Now()},
+			{AccountID: 11, AccountNumber: "4321", AccountName: "Chidi", LastIdentifier: "4321", TransferCount: 1, TotalAmount: 10000, LastTransferAt: time.Now()},
+		},
+		beneficiaries: []models.Beneficiary{{AccountID: 11}},
+	}
+	s := NewBeneficiaryService(store, nil, nil, nil)
+
+	resp, err := s.Recipients(context.Background(), 7, dto.ListRecipientsRequest{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "recent", resp.Order)
+	assert.Equal(t, DefaultRecipientLimit, store.limit)
+	require.Len(t, resp.Recipients, 2)
+
+	assert.Equal(t, "Ada O***", resp.Recipients[0].AccountName)
+	assert.Equal(t, "******6789", resp.Recipients[0].AccountNumber)
+	assert.False(t, resp.Recipients[0].IsBeneficiary)
+
+	assert.Equal(t, "Chidi", resp.Recipients[1].AccountName)
+	assert.Equal(t, "**21", resp.Recipients[1].AccountNumber)
+	assert.True(t, resp.Recipients[1].IsBeneficiary)
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/Brownie44l1/debank/internal/clientinfo"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// DEVICE REGISTRY
+// ==============================================
+// Every sign-in opens a login session, and the session's ID is the jti
+// of the access token, so ending a session kills its token. Sessions are
+// tied to the device (X-Device-ID) they were opened from. A sign-in from
+// a device the user hasn't used before, or has revoked, emails an alert;
+// with NEW_DEVICE_STEP_UP on, Login also makes it enter an emailed code.
+
+// ==============================================
+// SERVICE ERRORS
+// ==============================================
+
+var (
+	ErrDeviceNotFound    = errors.New("device not found")
+	ErrInvalidDeviceName = errors.New("device name must not be blank")
+)
+
+type DeviceService struct {
+	repo         *repository.DeviceRepository
+	emailService *EmailService
+}
+
+func NewDeviceService(repo *repository.DeviceRepository, emailService *EmailService) *DeviceService {
+	return &DeviceService{
+		repo:         repo,
+		emailService: emailService,
+	}
+}
+
+// ==============================================
+// SIGN-IN
+// ==============================================
+
+// IsRecognised checks if the user has signed in from this device before and hasn't revoked it
+// A client that sends no fingerprint can't be recognised
+func (s *DeviceService) IsRecognised(ctx context.Context, userID int, fingerprint string) (bool, error) {
+	if fingerprint == "" {
+		return false, nil
+	}
+
+	device, err := s.repo.GetDeviceByFingerprint(ctx, userID, fingerprint)
+	if err != nil {
+		if errors.Is(err, repository.ErrDeviceNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return device.IsTrusted(), nil
+}
+
+// StartSession opens a login session for the device and returns the session ID to put in the access token
+// Unrecognised devices are registered and the user is alerted by email
+func (s *DeviceService) StartSession(ctx context.Context, user *models.User, fingerprint string) (string, error) {
+	userID := int(user.ID)
+
+	recognised, err := s.IsRecognised(ctx, userID, fingerprint)
+	if err != nil {
+		return "", err
+	}
+
+	sessionID, err := auth.GenerateToken()
+	if err != nil {
+		return "", err
+	}
+
+	info := clientinfo.FromContext(ctx)
+	name := deviceName(info.DeviceName)
+	session := &models.LoginSession{
+		UserID:    user.ID,
+		Token:     auth.HashToken(sessionID),
+		IPAddress: pgtype.Text{String: info.IPAddress, Valid: info.IPAddress != ""},
+		UserAgent: pgtype.Text{String: info.UserAgent, Valid: info.UserAgent != ""},
+		ExpiresAt: time.Now().Add(auth.TokenExpirationTime),
+	}
+
+	var device *models.UserDevice
+	if fingerprint != "" {
+		device = &models.UserDevice{
+			UserID:      user.ID,
+			Fingerprint: fingerprint,
+			Name:        name,
+			UserAgent:   session.UserAgent,
+			LastIP:      session.IPAddress,
+		}
+
+		encoded, err := json.Marshal(map[string]string{"fingerprint": fingerprint, "name": name})
+		if err != nil {
+			return "", fmt.Errorf("failed to encode device info: %w", err)
+		}
+		session.DeviceInfo = pgtype.Text{String: string(encoded), Valid: true}
+	}
+
+	login, err := userAudit(ctx, userID, models.AuditActionLogin, map[string]interface{}{
+		"device_name": name,
+		"new_device":  !recognised,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var newDevice *models.AuditLog
+	if !recognised {
+		newDevice, err = userAudit(ctx, userID, models.AuditActionNewDeviceLogin, map[string]interface{}{
+			"device_name": name,
+			"registered":  device != nil,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if err := s.repo.RecordLogin(ctx, device, session, login, newDevice); err != nil {
+		return "", err
+	}
+
+	if !recognised {
+		log.Printf("[DEVICE] New device sign-in - UserID: %d, SessionID: %d", userID, session.ID)
+		if err := s.emailService.SendNewDeviceAlert(user.Email, user.Name, name, info.IPAddress, session.CreatedAt); err != nil {
+			// The session is open either way; a lost alert shouldn't fail the sign-in
+			log.Printf("[DEVICE] Failed to send new device alert - UserID: %d: %v", userID, err)
+		}
+	}
+
+	return sessionID, nil
+}
+
+// IsSessionActive checks that the session behind an access token hasn't been revoked or expired
+func (s *DeviceService) IsSessionActive(ctx context.Context, userID int, sessionID string) (bool, error) {
+	if sessionID == "" {
+		return false, nil
+	}
+
+	session, err := s.repo.GetSessionByToken(ctx, auth.HashToken(sessionID))
+	if err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return int(session.UserID) == userID && session.IsValid(), nil
+}
+
+// ==============================================
+// DEVICE MANAGEMENT
+// ==============================================
+
+// ListDevices returns the user's trusted devices, flagging the one making the request
+func (s *DeviceService) ListDevices(ctx context.Context, userID int) (*dto.DeviceListResponse, error) {
+	devices, err := s.repo.ListDevices(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := clientinfo.FromContext(ctx).DeviceID
+	resp := &dto.DeviceListResponse{Devices: make([]dto.DeviceDTO, 0, len(devices))}
+	for _, d := range devices {
+		item := deviceToDTO(d)
+		item.Current = current != "" && d.Fingerprint == current
+		resp.Devices = append(resp.Devices, *item)
+	}
+
+	return resp, nil
+}
+
+// RenameDevice changes the name a trusted device is listed under
+func (s *DeviceService) RenameDevice(ctx context.Context, userID int, deviceID int64, req dto.RenameDeviceRequest) (*dto.DeviceDTO, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrInvalidDeviceName
+	}
+
+	audit, err := userAudit(ctx, userID, models.AuditActionDeviceRenamed, map[string]interface{}{
+		"device_id": deviceID,
+		"name":      name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	device, err := s.repo.RenameDevice(ctx, userID, deviceID, name, audit)
+	if err != nil {
+		if errors.Is(err, repository.ErrDeviceNotFound) {
+			return nil, ErrDeviceNotFound
+		}
+		return nil, err
+	}
+
+	item := deviceToDTO(device)
+	item.Current = device.Fingerprint == clientinfo.FromContext(ctx).DeviceID
+	return item, nil
+}
+
+// RevokeDevice stops trusting a device and signs it out everywhere
+// Its next sign-in counts as a new device again
+func (s *DeviceService) RevokeDevice(ctx context.Context, userID int, deviceID int64) (*dto.RevokeDeviceResponse, error) {
+	audit, err := userAudit(ctx, userID, models.AuditActionDeviceRevoked, map[string]interface{}{
+		"device_id": deviceID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ended, err := s.repo.RevokeDevice(ctx, userID, deviceID, audit)
+	if err != nil {
+		if errors.Is(err, repository.ErrDeviceNotFound) {
+			return nil, ErrDeviceNotFound
+		}
+		return nil, err
+	}
+
+	log.Printf("[DEVICE] Revoked - UserID: %d, DeviceID: %d, SessionsEnded: %d", userID, deviceID, ended)
+
+	return &dto.RevokeDeviceResponse{
+		Message:       "Device removed and signed out",
+		SessionsEnded: ended,
+	}, nil
+}
+
+// ==============================================
+// HELPERS
+// ==============================================
+
+// deviceName cleans up the client-supplied name, falling back to a placeholder
+func deviceName(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return models.DefaultDeviceName
+	}
+	if runes := []rune(name); len(runes) > models.DeviceNameMaxLength {
+		name = strings.TrimSpace(string(runes[:models.DeviceNameMaxLength]))
+	}
+	return name
+}
+
+func deviceToDTO(d *models.UserDevice) *dto.DeviceDTO {
+	return &dto.DeviceDTO{
+		ID:             d.ID,
+		Name:           d.Name,
+		UserAgent:      d.UserAgent.String,
+		LastIP:         d.LastIP.String,
+		ActiveSessions: d.ActiveSessions,
+		FirstSeenAt:    d.FirstSeenAt.Format(time.RFC3339),
+		LastSeenAt:     d.LastSeenAt.Format(time.RFC3339),
+	}
+}
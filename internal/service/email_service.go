@@ -1,8 +1,12 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/Brownie44l1/debank/internal/metrics"
 	"github.com/Brownie44l1/debank/internal/models"
 )
 
@@ -25,6 +29,15 @@ func NewEmailService() *EmailService {
 	}
 }
 
+// ErrEmailNotConfigured means no provider is set up: codes are printed to the log, not delivered
+var ErrEmailNotConfigured = errors.New("no email provider configured, codes are only logged")
+
+// Ping checks the email provider can be reached
+func (s *EmailService) Ping(ctx context.Context) error {
+	// TODO: Dial the provider once one is configured
+	return ErrEmailNotConfigured
+}
+
 // ==============================================
 // SEND OTP
 // ==============================================
@@ -46,6 +59,7 @@ func (s *EmailService) SendOTP(email, code, purpose string) error {
 	// Example using SendGrid:
 	// return s.sendViaSendGrid(email, subject, body)
 	
+	metrics.OTPSent.WithLabelValues(purpose).Inc()
 	return nil
 }
 
@@ -98,10 +112,44 @@ Please use this code to authorize your transaction:
 
 Authorization code: %s
 
-This code will expire in 10 minutes.
+This code will expire in 3 minutes.
 
 If you didn't initiate this transaction, please contact support immediately.
 
+Best regards,
+DeBank Team
+		`, code)
+
+	case models.OTPPurposeSettingsChange:
+		subject = "Confirm Account Change - DeBank"
+		body = fmt.Sprintf(`
+Hello,
+
+Please use this code to confirm a change to your account
+(contact details, PIN or saved beneficiaries):
+
+Confirmation code: %s
+
+This code will expire in 3 minutes.
+
+If you didn't request this change, please contact support immediately.
+
+Best regards,
+DeBank Team
+		`, code)
+
+	case models.OTPPurposeLoginMFA:
+		subject = "Your Sign-in Code - DeBank"
+		body = fmt.Sprintf(`
+Hello,
+
+Your two-factor sign-in code is: %s
+
+This code will expire in 10 minutes.
+
+If you aren't signing in right now, someone may know your password.
+Please change it and contact support.
+
 Best regards,
 DeBank Team
 		`, code)
@@ -164,6 +212,7 @@ DeBank Team
 
 	fmt.Printf("📧 Sending welcome email to %s\n", email)
 	fmt.Printf("Subject: %s\n", subject)
+	fmt.Printf("Body: %s\n", body)
 	
 	// TODO: Implement actual email sending
 	return nil
@@ -189,7 +238,38 @@ DeBank Team
 	`, transactionType, amountNGN, transactionType, amountNGN)
 
 	fmt.Printf("📧 Sending transaction notification to %s\n", email)
+	fmt.Printf("Subject: %s\n", subject)
+	fmt.Printf("Body: %s\n", body)
 	
 	// TODO: Implement actual email sending
 	return nil
-}
\ No newline at end of file
+}
+
+// SendNewDeviceAlert tells the user their account was signed in to from a device it hasn't seen before
+func (s *EmailService) SendNewDeviceAlert(email, name, deviceName, ipAddress string, at time.Time) error {
+	subject := "New sign-in to your DeBank account"
+	body := fmt.Sprintf(`
+Hello %s,
+
+Your DeBank account was just signed in to from a new device.
+
+Device: %s
+IP address: %s
+Time: %s
+
+If this was you, there's nothing to do.
+
+If you don't recognise this sign-in, remove the device under Settings > Devices,
+change your password and contact support immediately.
+
+Best regards,
+DeBank Team
+	`, name, deviceName, ipAddress, at.UTC().Format("2 Jan 2006 15:04 MST"))
+
+	fmt.Printf("📧 Sending new device alert to %s\n", email)
+	fmt.Printf("Subject: %s\n", subject)
+	fmt.Printf("Body: %s\n", body)
+
+	// TODO: Implement actual email sending
+	return nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/models"
+)
+
+// ==============================================
+// IDENTITY VERIFIER INTERFACE
+// ==============================================
+
+// IdentityCheck is what we know about the user when looking up their BVN / NIN
+type IdentityCheck struct {
+	IDType   string // models.KYCIDTypeBVN, models.KYCIDTypeNIN
+	IDNumber string
+	FullName string
+	Phone    string
+}
+
+// IdentityResult is the provider's answer
+type IdentityResult struct {
+	Status    string // models.KYCVerification*
+	Reference string // Provider reference for the lookup
+	FullName  string // Name on record, if found
+}
+
+// IdentityVerifier looks up a BVN / NIN with an identity provider
+// Production implementations call a provider; FakeIdentityVerifier is used locally
+type IdentityVerifier interface {
+	VerifyIdentity(ctx context.Context, check IdentityCheck) (*IdentityResult, error)
+}
+
+// ==============================================
+// FAKE IDENTITY VERIFIER (local / tests)
+// ==============================================
+
+// FakeIdentityVerifier simulates a provider without network calls:
+//   - numbers ending in "0000" are not found
+//   - numbers ending in "9999" return a different name (mismatch)
+//   - anything else matches the user's name
+type FakeIdentityVerifier struct{}
+
+func NewFakeIdentityVerifier() *FakeIdentityVerifier {
+	return &FakeIdentityVerifier{}
+}
+
+func (f *FakeIdentityVerifier) VerifyIdentity(ctx context.Context, check IdentityCheck) (*IdentityResult, error) {
+	reference := fmt.Sprintf("FAKE-%s-%d", strings.ToUpper(check.IDType), time.Now().UnixNano())
+
+	switch {
+	case strings.HasSuffix(check.IDNumber, "0000"):
+		return &IdentityResult{Status: models.KYCVerificationNotFound, Reference: reference}, nil
+	case strings.HasSuffix(check.IDNumber, "9999"):
+		return &IdentityResult{Status: models.KYCVerificationMismatch, Reference: reference, FullName: "John Doe"}, nil
+	default:
+		return &IdentityResult{Status: models.KYCVerificationMatched, Reference: reference, FullName: check.FullName}, nil
+	}
+}
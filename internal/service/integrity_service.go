@@ -0,0 +1,345 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/repository"
+	"github.com/Brownie44l1/debank/pkg/hashchain"
+)
+
+// ==============================================
+// LEDGER INTEGRITY
+// ==============================================
+// transactions and audit_logs are hash chains (see pkg/hashchain and
+// 015_hash_chain.sql). Verify walks a whole chain and reports every row
+// where it breaks. Checkpoint runs from the worker: it checks the rows
+// added since the last checkpoint, then signs the head with the JWT
+// keyring and exports it, so a later rewrite of the whole chain would not
+// match the checkpoints kept outside the database.
+
+var (
+	ErrChainBroken             = errors.New("hash chain is broken")
+	ErrUnknownKeyForCheckpoint = errors.New("checkpoint signed by a key no longer in the keyring")
+)
+
+// checkpointStatementType marks a signed payload as a checkpoint
+const checkpointStatementType = "hash_chain_checkpoint"
+
+// maxReportedBreaks caps how many breaks one verification lists
+const maxReportedBreaks = 100
+
+// Break reasons beyond the per-row ones from pkg/hashchain
+const (
+	breakCheckpointMismatch  = "row hash does not match the signed checkpoint"
+	breakCheckpointSignature = "checkpoint signature or contents are invalid"
+	breakHeadMismatch        = "chain head does not match the last row; rows were removed or added outside the chain"
+)
+
+// StatementSigner signs and verifies checkpoints (implemented by auth.Keyring)
+type StatementSigner interface {
+	SignStatement(payload any) (string, string, error)
+	VerifyStatement(statement string, v any) error
+}
+
+// CheckpointStore keeps exported checkpoints outside the database (implemented by storage.LocalStorage)
+type CheckpointStore interface {
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+}
+
+// checkpointStatement is the signed payload of a checkpoint
+type checkpointStatement struct {
+	Type     string    `json:"type"`
+	Chain    string    `json:"chain"`
+	LastID   int64     `json:"last_id"`
+	LastHash string    `json:"last_hash"` // Hex
+	Rows     int64     `json:"rows"`
+	IssuedAt time.Time `json:"issued_at"`
+}
+
+type IntegrityService struct {
+	repo   *repository.IntegrityRepository
+	signer StatementSigner
+	store  CheckpointStore // May be nil where checkpoints are only verified, not taken
+	audit  *AuditLogger
+}
+
+func NewIntegrityService(repo *repository.IntegrityRepository, signer StatementSigner, store CheckpointStore, audit *AuditLogger) *IntegrityService {
+	return &IntegrityService{
+		repo:   repo,
+		signer: signer,
+		store:  store,
+		audit:  audit,
+	}
+}
+
+// ==============================================
+// VERIFICATION
+// ==============================================
+
+// Verify walks a chain from its first row to its head, checking every link and checkpoint
+func (s *IntegrityService) Verify(ctx context.Context, actorID int, chain string) (*dto.ChainVerificationResponse, error) {
+	head, err := s.repo.GetHead(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints, err := s.checkpointsByLastID(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.ChainVerificationResponse{
+		Chain:      chain,
+		HeadHash:   hex.EncodeToString(head.LastHash),
+		Breaks:     []dto.ChainBreakDTO{},
+		VerifiedAt: time.Now().Format(time.RFC3339),
+	}
+	addBreak := func(rowID int64, reason string) {
+		if len(resp.Breaks) == maxReportedBreaks {
+			resp.BreaksTruncated = true
+			return
+		}
+		resp.Breaks = append(resp.Breaks, dto.ChainBreakDTO{RowID: rowID, Reason: reason})
+	}
+
+	v := hashchain.NewVerifier(hashchain.Genesis)
+	if head.StartID.Valid && head.LastID.Valid {
+		resp.FirstID = head.StartID.Int64
+
+		err = s.repo.WalkChain(ctx, chain, head.StartID.Int64-1, head.LastID.Int64, func(link repository.ChainLink) error {
+			if err := v.Check(link.ID, link.PrevHash, link.RowHash, link.Fields...); err != nil {
+				addBreak(link.ID, errors.Unwrap(err).Error())
+			}
+
+			cp, ok := checkpoints[link.ID]
+			if !ok {
+				return nil
+			}
+			resp.CheckpointsChecked++
+			if !bytes.Equal(cp.LastHash, link.RowHash) {
+				addBreak(link.ID, breakCheckpointMismatch)
+			}
+			switch err := s.verifyCheckpoint(cp); {
+			case errors.Is(err, ErrUnknownKeyForCheckpoint):
+				resp.CheckpointsUnverifiable++
+			case err != nil:
+				addBreak(link.ID, breakCheckpointSignature)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp.RowsChecked = v.Rows()
+	resp.LastID = v.LastID()
+	if v.LastID() != head.LastID.Int64 || v.Rows() != head.Rows || !bytes.Equal(v.Head(), head.LastHash) {
+		addBreak(head.LastID.Int64, breakHeadMismatch)
+	}
+	resp.Intact = len(resp.Breaks) == 0
+
+	s.audit.Record(ctx, actorID, models.AuditActionChainVerified, "hash_chain", 0, map[string]interface{}{
+		"chain":  chain,
+		"intact": resp.Intact,
+		"rows":   resp.RowsChecked,
+		"breaks": len(resp.Breaks),
+	})
+	if !resp.Intact {
+		log.Printf("[INTEGRITY] CRITICAL - %s chain broken: %d break(s), first at row %d", chain, len(resp.Breaks), resp.Breaks[0].RowID)
+	}
+
+	return resp, nil
+}
+
+// verifyCheckpoint checks a stored checkpoint's signature and that it signs what the row says
+func (s *IntegrityService) verifyCheckpoint(cp *models.ChainCheckpoint) error {
+	var stmt checkpointStatement
+	if err := s.signer.VerifyStatement(cp.Signature, &stmt); err != nil {
+		if errors.Is(err, auth.ErrUnknownKey) {
+			return ErrUnknownKeyForCheckpoint
+		}
+		return err
+	}
+
+	if stmt.Type != checkpointStatementType ||
+		stmt.Chain != cp.Chain ||
+		stmt.LastID != cp.LastID ||
+		stmt.LastHash != hex.EncodeToString(cp.LastHash) ||
+		stmt.Rows != cp.Rows {
+		return errors.New("checkpoint does not match its signed statement")
+	}
+	return nil
+}
+
+func (s *IntegrityService) checkpointsByLastID(ctx context.Context, chain string) (map[int64]*models.ChainCheckpoint, error) {
+	const pageSize = 500
+
+	byLastID := map[int64]*models.ChainCheckpoint{}
+	for offset := 0; ; offset += pageSize {
+		page, err := s.repo.ListCheckpoints(ctx, chain, pageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		for i := range page {
+			byLastID[page[i].LastID] = &page[i]
+		}
+		if len(page) < pageSize {
+			return byLastID, nil
+		}
+	}
+}
+
+// ==============================================
+// CHECKPOINTS
+// ==============================================
+
+// Checkpoint signs and exports the head of every chain that has grown since its last checkpoint
+// A chain is only signed once the rows since its last checkpoint verify
+func (s *IntegrityService) Checkpoint(ctx context.Context) error {
+	var errs []error
+	for _, chain := range models.Chains {
+		cp, err := s.checkpointChain(ctx, chain)
+		if err != nil {
+			log.Printf("[INTEGRITY] Checkpoint failed - Chain: %s: %v", chain, err)
+			errs = append(errs, fmt.Errorf("%s: %w", chain, err))
+			continue
+		}
+		if cp != nil {
+			log.Printf("[INTEGRITY] Checkpoint %d - Chain: %s, LastID: %d, Rows: %d", cp.ID, chain, cp.LastID, cp.Rows)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *IntegrityService) checkpointChain(ctx context.Context, chain string) (*models.ChainCheckpoint, error) {
+	head, err := s.repo.GetHead(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+	if !head.LastID.Valid {
+		return nil, nil // Nothing chained yet
+	}
+
+	// Resume from the last checkpoint if we can still vouch for it
+	v := hashchain.NewVerifier(hashchain.Genesis)
+	afterID := head.StartID.Int64 - 1
+	var baseRows int64
+
+	latest, err := s.repo.GetLatestCheckpoint(ctx, chain)
+	switch {
+	case errors.Is(err, repository.ErrCheckpointNotFound):
+	case err != nil:
+		return nil, err
+	case latest.LastID == head.LastID.Int64:
+		// Unchanged since the last checkpoint; export it again in case that failed
+		return nil, s.export(ctx, latest)
+	default:
+		if err := s.verifyCheckpoint(latest); err == nil {
+			v = hashchain.NewVerifier(latest.LastHash)
+			afterID = latest.LastID
+			baseRows = latest.Rows
+		} else if !errors.Is(err, ErrUnknownKeyForCheckpoint) {
+			return nil, fmt.Errorf("%w: checkpoint %d: %v", ErrChainBroken, latest.ID, err)
+		}
+	}
+
+	err = s.repo.WalkChain(ctx, chain, afterID, head.LastID.Int64, func(link repository.ChainLink) error {
+		if err := v.Check(link.ID, link.PrevHash, link.RowHash, link.Fields...); err != nil {
+			return fmt.Errorf("%w: %v", ErrChainBroken, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if v.LastID() != head.LastID.Int64 || baseRows+v.Rows() != head.Rows || !bytes.Equal(v.Head(), head.LastHash) {
+		return nil, fmt.Errorf("%w: %s", ErrChainBroken, breakHeadMismatch)
+	}
+
+	cp := &models.ChainCheckpoint{
+		Chain:    chain,
+		LastID:   head.LastID.Int64,
+		LastHash: head.LastHash,
+		Rows:     head.Rows,
+	}
+	cp.Signature, cp.KeyID, err = s.signer.SignStatement(checkpointStatement{
+		Type:     checkpointStatementType,
+		Chain:    cp.Chain,
+		LastID:   cp.LastID,
+		LastHash: hex.EncodeToString(cp.LastHash),
+		Rows:     cp.Rows,
+		IssuedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign checkpoint: %w", err)
+	}
+
+	if err := s.repo.CreateCheckpoint(ctx, cp); err != nil {
+		return nil, err
+	}
+
+	if err := s.export(ctx, cp); err != nil {
+		// The signed row is in the database; the next run exports it again
+		return cp, fmt.Errorf("failed to export checkpoint %d: %w", cp.ID, err)
+	}
+	return cp, nil
+}
+
+// export writes a checkpoint to the store as <chain>/<id>.json
+func (s *IntegrityService) export(ctx context.Context, cp *models.ChainCheckpoint) error {
+	if s.store == nil {
+		return nil
+	}
+
+	body, err := json.MarshalIndent(checkpointToDTO(cp), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = s.store.Put(ctx, fmt.Sprintf("%s/%010d.json", cp.Chain, cp.ID), bytes.NewReader(body))
+	return err
+}
+
+// ListCheckpoints returns a chain's checkpoints, newest first
+func (s *IntegrityService) ListCheckpoints(ctx context.Context, req dto.ListCheckpointsRequest) (*dto.ChainCheckpointListResponse, error) {
+	page, perPage := pageOrDefault(req.Page, req.PerPage)
+
+	checkpoints, err := s.repo.ListCheckpoints(ctx, req.Chain, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.ChainCheckpointListResponse{
+		Checkpoints: make([]dto.ChainCheckpointDTO, len(checkpoints)),
+		Page:        page,
+		PerPage:     perPage,
+	}
+	for i := range checkpoints {
+		resp.Checkpoints[i] = checkpointToDTO(&checkpoints[i])
+	}
+	return resp, nil
+}
+
+func checkpointToDTO(cp *models.ChainCheckpoint) dto.ChainCheckpointDTO {
+	return dto.ChainCheckpointDTO{
+		ID:        cp.ID,
+		Chain:     cp.Chain,
+		LastID:    cp.LastID,
+		LastHash:  hex.EncodeToString(cp.LastHash),
+		Rows:      cp.Rows,
+		KeyID:     cp.KeyID,
+		Signature: cp.Signature,
+		CreatedAt: cp.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,633 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/repository"
+	"github.com/Brownie44l1/debank/internal/storage"
+	"github.com/Brownie44l1/debank/pkg/generator"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// BUSINESS RULES (Constants)
+// ==============================================
+
+const (
+	MinJournalLegs           = 2
+	MaxJournalAttachmentSize = 5 << 20 // 5 MiB, within the idempotency middleware's body cap
+)
+
+// Attachment content types we accept, detected from the bytes rather than trusted from the client
+var allowedJournalContentTypes = map[string]bool{
+	"image/jpeg":                true,
+	"image/png":                 true,
+	"application/pdf":           true,
+	"text/plain; charset=utf-8": true, // CSV exports from bank statements
+}
+
+// ==============================================
+// SERVICE ERRORS
+// ==============================================
+
+var (
+	ErrJournalEntryNotFound       = errors.New("journal entry not found")
+	ErrJournalEntryNotPending     = errors.New("journal entry has already been reviewed")
+	ErrJournalAttachmentNotFound  = errors.New("journal attachment not found")
+	ErrJournalTooFewLegs          = errors.New("a journal entry needs at least two legs")
+	ErrJournalZeroLeg             = errors.New("journal legs must have a non-zero amount")
+	ErrJournalUnbalanced          = errors.New("journal legs must sum to zero")
+	ErrJournalDuplicateAccount    = errors.New("each account may appear in only one leg")
+	ErrJournalReasonRequired      = errors.New("a reason is required")
+	ErrJournalAttachmentsRequired = errors.New("a journal entry needs at least one attachment before it can be approved")
+	ErrJournalAttachmentType      = errors.New("attachment must be a JPEG, PNG, PDF or plain text file")
+	ErrJournalAttachmentTooLarge  = errors.New("attachment exceeds the maximum upload size")
+	ErrJournalNotMaker            = errors.New("only the submitter can attach files to a journal entry")
+	ErrJournalSelfApproval        = errors.New("a journal entry must be reviewed by someone other than its submitter")
+)
+
+// ==============================================
+// SERVICE
+// ==============================================
+
+// JournalService runs manual journal entries under maker-checker control.
+// A maker submits balanced legs with a reason and attachments; nothing
+// touches the ledger until a different staff member approves, which posts
+// every leg in one transaction.
+type JournalService struct {
+	repo       *repository.JournalRepository
+	walletRepo *repository.WalletRepository
+	store      storage.Storage
+	audit      *AuditLogger
+}
+
+func NewJournalService(repo *repository.JournalRepository, walletRepo *repository.WalletRepository, store storage.Storage, audit *AuditLogger) *JournalService {
+	return &JournalService{
+		repo:       repo,
+		walletRepo: walletRepo,
+		store:      store,
+		audit:      audit,
+	}
+}
+
+// ==============================================
+// MAKER: SUBMIT AND ATTACH
+// ==============================================
+
+// Submit records a pending entry; the legs are checked now and again when approved
+func (s *JournalService) Submit(ctx context.Context, makerID int, req dto.SubmitJournalEntryRequest) (*dto.JournalEntryDTO, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, ErrJournalReasonRequired
+	}
+	if err := validateLegs(req.Legs); err != nil {
+		return nil, err
+	}
+
+	legs := make([]models.JournalLeg, len(req.Legs))
+	currency := ""
+	for i, l := range req.Legs {
+		account, err := s.walletRepo.GetAccountByAccountNumber(ctx, l.AccountNumber)
+		if err != nil {
+			if errors.Is(err, repository.ErrAccountNotFound) {
+				return nil, ErrAccountNotFound
+			}
+			return nil, err
+		}
+		if currency == "" {
+			currency = account.Currency
+		} else if account.Currency != currency {
+			return nil, ErrCurrencyMismatch
+		}
+
+		// Account numbers can be written differently; IDs can't
+		for _, prev := range legs[:i] {
+			if prev.AccountID == account.ID {
+				return nil, ErrJournalDuplicateAccount
+			}
+		}
+
+		legs[i] = models.JournalLeg{
+			AccountID:     account.ID,
+			AccountNumber: account.AccountNumber.String,
+			Amount:        l.Amount,
+		}
+		if memo := strings.TrimSpace(l.Memo); memo != "" {
+			legs[i].Memo = pgtype.Text{String: memo, Valid: true}
+		}
+	}
+
+	entry := &models.JournalEntry{
+		Reference:   generator.GenerateReference("JNL"),
+		Currency:    currency,
+		Reason:      reason,
+		MakerUserID: int32(makerID),
+	}
+
+	tx, err := s.walletRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := s.repo.CreateEntry(ctx, tx, entry, legs); err != nil {
+		return nil, err
+	}
+	if err := s.auditChange(ctx, tx, makerID, models.AuditActionJournalSubmitted, entry.ID, map[string]interface{}{
+		"reference": entry.Reference,
+		"currency":  entry.Currency,
+		"total":     journalTotal(legs),
+		"legs":      len(legs),
+		"reason":    entry.Reason,
+	}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	log.Printf("[JOURNAL] Submitted - EntryID: %d, Reference: %s, Legs: %d, Total: %d, Maker: %d", entry.ID, entry.Reference, len(legs), journalTotal(legs), makerID)
+	return journalEntryToDTO(entry, legs, nil), nil
+}
+
+// UploadAttachment stores a supporting document against one of the maker's pending entries
+func (s *JournalService) UploadAttachment(ctx context.Context, userID int, entryID int64, fileName string, r io.Reader) (*dto.JournalAttachmentDTO, error) {
+	entry, err := s.getEntry(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+	if int(entry.MakerUserID) != userID {
+		return nil, ErrJournalNotMaker
+	}
+	if !entry.IsPending() {
+		return nil, ErrJournalEntryNotPending
+	}
+
+	// Sniff the real content type from the first bytes
+	br := bufio.NewReaderSize(r, 512)
+	head, err := br.Peek(512)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	contentType := http.DetectContentType(head)
+	if !allowedJournalContentTypes[contentType] {
+		return nil, ErrJournalAttachmentType
+	}
+
+	token, err := generator.GenerateToken(12)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate storage key: %w", err)
+	}
+	key := fmt.Sprintf("journal/%d/%s", entryID, token)
+
+	hash := sha256.New()
+	limited := io.LimitReader(br, MaxJournalAttachmentSize+1)
+	size, err := s.store.Put(ctx, key, io.TeeReader(limited, hash))
+	if err != nil {
+		return nil, err
+	}
+	if size > MaxJournalAttachmentSize {
+		_ = s.store.Delete(ctx, key)
+		return nil, ErrJournalAttachmentTooLarge
+	}
+
+	attachment := &models.JournalAttachment{
+		EntryID:     entryID,
+		UploadedBy:  int32(userID),
+		StorageKey:  key,
+		FileName:    sanitizeFileName(fileName),
+		ContentType: contentType,
+		SizeBytes:   size,
+		SHA256:      hex.EncodeToString(hash.Sum(nil)),
+	}
+	if err := s.recordAttachment(ctx, userID, attachment); err != nil {
+		_ = s.store.Delete(ctx, key)
+		return nil, err
+	}
+
+	log.Printf("[JOURNAL] Attachment uploaded - EntryID: %d, AttachmentID: %d, Size: %d", entryID, attachment.ID, size)
+	resp := journalAttachmentToDTO(attachment)
+	return &resp, nil
+}
+
+// recordAttachment inserts the attachment while the entry is locked, so it
+// can't land on an entry a checker is deciding at the same moment
+func (s *JournalService) recordAttachment(ctx context.Context, userID int, attachment *models.JournalAttachment) error {
+	tx, err := s.walletRepo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	entry, err := s.lockEntry(ctx, tx, attachment.EntryID)
+	if err != nil {
+		return err
+	}
+	if !entry.IsPending() {
+		return ErrJournalEntryNotPending
+	}
+
+	if err := s.repo.CreateAttachment(ctx, tx, attachment); err != nil {
+		return err
+	}
+	if err := s.auditChange(ctx, tx, userID, models.AuditActionJournalAttachment, entry.ID, map[string]interface{}{
+		"reference":     entry.Reference,
+		"attachment_id": attachment.ID,
+		"file_name":     attachment.FileName,
+		"sha256":        attachment.SHA256,
+	}); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+// ==============================================
+// CHECKER: QUEUE AND DECISIONS
+// ==============================================
+
+// List returns entries in a given status, oldest first
+func (s *JournalService) List(ctx context.Context, req dto.ListJournalEntriesRequest) (*dto.JournalEntryListResponse, error) {
+	status := req.Status
+	if status == "" {
+		status = models.JournalStatusPendingApproval
+	}
+	page, perPage := req.Page, req.PerPage
+	if page == 0 {
+		page = 1
+	}
+	if perPage == 0 {
+		perPage = 20
+	}
+
+	entries, err := s.repo.ListEntriesByStatus(ctx, status, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.JournalEntryListResponse{
+		Entries: make([]dto.JournalEntryDTO, 0, len(entries)),
+		Page:    page,
+		PerPage: perPage,
+	}
+	for i := range entries {
+		entry, err := s.withDetails(ctx, &entries[i])
+		if err != nil {
+			return nil, err
+		}
+		resp.Entries = append(resp.Entries, *entry)
+	}
+	return resp, nil
+}
+
+// Get returns an entry with its legs and attachments
+func (s *JournalService) Get(ctx context.Context, id int64) (*dto.JournalEntryDTO, error) {
+	entry, err := s.getEntry(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.withDetails(ctx, entry)
+}
+
+// Approve posts the entry's legs as one journal transaction. The entry is
+// locked for the whole decision, and the legs are re-checked against the
+// balances as they stand now rather than when the entry was submitted.
+func (s *JournalService) Approve(ctx context.Context, checkerID int, id int64, req dto.ReviewJournalEntryRequest) (*dto.JournalEntryDTO, error) {
+	legs, err := s.repo.ListLegs(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.walletRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	entry, err := s.lockReviewable(ctx, tx, checkerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.repo.CountAttachments(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if attachments == 0 {
+		return nil, ErrJournalAttachmentsRequired
+	}
+
+	ids := make([]int64, len(legs))
+	for i, l := range legs {
+		ids[i] = l.AccountID
+	}
+	locked, err := lockAccounts(ctx, s.walletRepo, tx, ids...)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range legs {
+		account := locked[l.AccountID]
+		if account.Currency != entry.Currency {
+			return nil, ErrCurrencyMismatch
+		}
+		if l.Amount < 0 && account.IsUserAccount() && account.Balance < -l.Amount {
+			return nil, ErrInsufficientBalance
+		}
+	}
+
+	metadata, err := json.Marshal(map[string]interface{}{
+		"journal_entry_id": entry.ID,
+		"reason":           entry.Reason,
+		"maker_id":         entry.MakerUserID,
+		"checker_id":       checkerID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode metadata: %w", err)
+	}
+
+	txn := &models.Transaction{
+		IdempotencyKey: fmt.Sprintf("journal:%d", entry.ID),
+		Reference:      entry.Reference,
+		Kind:           models.TransactionKindJournal,
+		Status:         models.TransactionStatusPosted,
+		Amount:         journalTotal(legs),
+		Currency:       entry.Currency,
+		Description:    pgtype.Text{String: "Journal entry", Valid: true},
+		Metadata:       pgtype.Text{String: string(metadata), Valid: true},
+	}
+	// A simple two-leg entry reads like any other transfer in account history
+	if len(legs) == 2 {
+		from, to := legs[0], legs[1]
+		if from.Amount > 0 {
+			from, to = to, from
+		}
+		txn.FromAccountID = pgtype.Int8{Int64: from.AccountID, Valid: true}
+		txn.ToAccountID = pgtype.Int8{Int64: to.AccountID, Valid: true}
+	}
+	if err := s.walletRepo.CreateTransaction(ctx, tx, txn); err != nil {
+		return nil, createTransactionError(err)
+	}
+
+	postings := make([]models.Posting, len(legs))
+	for i, l := range legs {
+		postings[i] = models.Posting{TransactionID: txn.ID, AccountID: l.AccountID, Amount: l.Amount, Currency: txn.Currency}
+	}
+	if err := createPostings(ctx, s.walletRepo, tx, postings); err != nil {
+		return nil, err
+	}
+
+	posted, err := s.repo.MarkPosted(ctx, tx, id, checkerID, strings.TrimSpace(req.Note), txn.ID)
+	if err != nil {
+		return nil, mapJournalError(err)
+	}
+	if err := s.auditChange(ctx, tx, checkerID, models.AuditActionJournalApproved, id, map[string]interface{}{
+		"reference":      entry.Reference,
+		"transaction_id": txn.ID,
+		"maker_id":       entry.MakerUserID,
+		"total":          txn.Amount,
+		"note":           req.Note,
+	}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	log.Printf("[JOURNAL] Approved - EntryID: %d, Reference: %s, TransactionID: %d, Total: %d, Checker: %d", id, entry.Reference, txn.ID, txn.Amount, checkerID)
+	return s.withDetails(ctx, posted)
+}
+
+// Reject closes the entry without posting; a note explaining why is required
+func (s *JournalService) Reject(ctx context.Context, checkerID int, id int64, req dto.ReviewJournalEntryRequest) (*dto.JournalEntryDTO, error) {
+	note := strings.TrimSpace(req.Note)
+	if note == "" {
+		return nil, ErrJournalReasonRequired
+	}
+
+	tx, err := s.walletRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	entry, err := s.lockReviewable(ctx, tx, checkerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rejected, err := s.repo.MarkRejected(ctx, tx, id, checkerID, note)
+	if err != nil {
+		return nil, mapJournalError(err)
+	}
+	if err := s.auditChange(ctx, tx, checkerID, models.AuditActionJournalRejected, id, map[string]interface{}{
+		"reference": entry.Reference,
+		"maker_id":  entry.MakerUserID,
+		"note":      note,
+	}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	log.Printf("[JOURNAL] Rejected - EntryID: %d, Reference: %s, Checker: %d", id, entry.Reference, checkerID)
+	return s.withDetails(ctx, rejected)
+}
+
+// OpenAttachment streams an attachment; the caller must close the reader
+func (s *JournalService) OpenAttachment(ctx context.Context, id int64) (io.ReadCloser, *dto.JournalAttachmentDTO, error) {
+	attachment, err := s.repo.GetAttachment(ctx, id)
+	if err != nil {
+		return nil, nil, mapJournalError(err)
+	}
+
+	rc, err := s.store.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := journalAttachmentToDTO(attachment)
+	return rc, &resp, nil
+}
+
+// ==============================================
+// HELPERS
+// ==============================================
+
+// validateLegs checks the shape of an entry before any account is looked up
+func validateLegs(legs []dto.JournalLegRequest) error {
+	if len(legs) < MinJournalLegs {
+		return ErrJournalTooFewLegs
+	}
+
+	seen := make(map[string]bool, len(legs))
+	var sum int64
+	for _, l := range legs {
+		if l.Amount == 0 {
+			return ErrJournalZeroLeg
+		}
+		if seen[l.AccountNumber] {
+			return ErrJournalDuplicateAccount
+		}
+		seen[l.AccountNumber] = true
+		sum += l.Amount
+	}
+	if sum != 0 {
+		return ErrJournalUnbalanced
+	}
+	return nil
+}
+
+// journalTotal is the amount an entry moves: the sum of its credit legs
+func journalTotal(legs []models.JournalLeg) int64 {
+	var total int64
+	for _, l := range legs {
+		if l.Amount > 0 {
+			total += l.Amount
+		}
+	}
+	return total
+}
+
+func mapJournalError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrJournalEntryNotFound):
+		return ErrJournalEntryNotFound
+	case errors.Is(err, repository.ErrJournalEntryNotPending):
+		return ErrJournalEntryNotPending
+	case errors.Is(err, repository.ErrJournalAttachmentNotFound):
+		return ErrJournalAttachmentNotFound
+	}
+	return err
+}
+
+func (s *JournalService) getEntry(ctx context.Context, id int64) (*models.JournalEntry, error) {
+	entry, err := s.repo.GetEntry(ctx, id)
+	if err != nil {
+		return nil, mapJournalError(err)
+	}
+	return entry, nil
+}
+
+func (s *JournalService) lockEntry(ctx context.Context, tx pgx.Tx, id int64) (*models.JournalEntry, error) {
+	entry, err := s.repo.GetEntryForUpdate(ctx, tx, id)
+	if err != nil {
+		return nil, mapJournalError(err)
+	}
+	return entry, nil
+}
+
+// lockReviewable locks a pending entry that checkerID is allowed to decide
+func (s *JournalService) lockReviewable(ctx context.Context, tx pgx.Tx, checkerID int, id int64) (*models.JournalEntry, error) {
+	entry, err := s.lockEntry(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if int(entry.MakerUserID) == checkerID {
+		return nil, ErrJournalSelfApproval
+	}
+	if !entry.IsPending() {
+		return nil, ErrJournalEntryNotPending
+	}
+	return entry, nil
+}
+
+// auditChange records a journal decision in the transaction that makes it
+func (s *JournalService) auditChange(ctx context.Context, tx pgx.Tx, actorID int, action string, entryID int64, metadata map[string]interface{}) error {
+	entry, err := auditEntry(ctx, actorID, action, "journal_entry", entryID, metadata)
+	if err != nil {
+		return err
+	}
+	return s.audit.WriteTx(ctx, tx, entry)
+}
+
+func (s *JournalService) withDetails(ctx context.Context, entry *models.JournalEntry) (*dto.JournalEntryDTO, error) {
+	legs, err := s.repo.ListLegs(ctx, entry.ID)
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := s.repo.ListAttachments(ctx, entry.ID)
+	if err != nil {
+		return nil, err
+	}
+	return journalEntryToDTO(entry, legs, attachments), nil
+}
+
+func journalEntryToDTO(e *models.JournalEntry, legs []models.JournalLeg, attachments []models.JournalAttachment) *dto.JournalEntryDTO {
+	resp := &dto.JournalEntryDTO{
+		ID:          e.ID,
+		Reference:   e.Reference,
+		Status:      e.Status,
+		Currency:    e.Currency,
+		Total:       journalTotal(legs),
+		Reason:      e.Reason,
+		MakerUserID: int(e.MakerUserID),
+		CreatedAt:   e.CreatedAt.Format(time.RFC3339),
+		Legs:        make([]dto.JournalLegDTO, 0, len(legs)),
+		Attachments: make([]dto.JournalAttachmentDTO, 0, len(attachments)),
+	}
+	if e.CheckerUserID.Valid {
+		checker := int(e.CheckerUserID.Int32)
+		resp.CheckerUserID = &checker
+	}
+	if e.ReviewNote.Valid {
+		note := e.ReviewNote.String
+		resp.ReviewNote = &note
+	}
+	if e.ReviewedAt.Valid {
+		reviewedAt := e.ReviewedAt.Time.Format(time.RFC3339)
+		resp.ReviewedAt = &reviewedAt
+	}
+	if e.TransactionID.Valid {
+		transactionID := e.TransactionID.Int64
+		resp.TransactionID = &transactionID
+	}
+	for _, l := range legs {
+		resp.Legs = append(resp.Legs, dto.JournalLegDTO{
+			AccountID:     l.AccountID,
+			AccountNumber: l.AccountNumber,
+			Amount:        l.Amount,
+			Memo:          l.Memo.String,
+		})
+	}
+	for i := range attachments {
+		resp.Attachments = append(resp.Attachments, journalAttachmentToDTO(&attachments[i]))
+	}
+	return resp
+}
+
+func journalAttachmentToDTO(a *models.JournalAttachment) dto.JournalAttachmentDTO {
+	return dto.JournalAttachmentDTO{
+		ID:          a.ID,
+		UploadedBy:  int(a.UploadedBy),
+		FileName:    a.FileName,
+		ContentType: a.ContentType,
+		SizeBytes:   a.SizeBytes,
+		SHA256:      a.SHA256,
+		CreatedAt:   a.CreatedAt.Format(time.RFC3339),
+	}
+}
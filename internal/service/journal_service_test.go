@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateLegs(t *testing.T) {
+	tests := []struct {
+		name string
+		legs []dto.JournalLegRequest
+		want error
+	}{
+		{
+			name: "balanced multi-leg",
+			legs: []dto.JournalLegRequest{
+				{AccountNumber: "0000000001", Amount: -15000},
+				{AccountNumber: "0000000002", Amount: 10000},
+				{AccountNumber: "0000000003", Amount: 5000},
+			},
+		},
+		{
+			name: "single leg",
+			legs: []dto.JournalLegRequest{{AccountNumber: "0000000001", Amount: 100}},
+			want: ErrJournalTooFewLegs,
+		},
+		{
+			name: "zero amount",
+			legs: []dto.JournalLegRequest{
+				{AccountNumber: "0000000001", Amount: 0},
+				{AccountNumber: "0000000002", Amount: 0},
+			},
+			want: ErrJournalZeroLeg,
+		},
+		{
+			name: "unbalanced",
+			legs: []dto.JournalLegRequest{
+				{AccountNumber: "0000000001", Amount: -10000},
+				{AccountNumber: "0000000002", Amount: 9999},
+			},
+			want: ErrJournalUnbalanced,
+		},
+		{
+			name: "same account twice",
+			legs: []dto.JournalLegRequest{
+				{AccountNumber: "0000000001", Amount: -500},
+				{AccountNumber: "0000000001", Amount: 500},
+			},
+			want: ErrJournalDuplicateAccount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, validateLegs(tt.legs), tt.want)
+		})
+	}
+}
+
+func TestJournalTotal_SumsCredits(t *testing.T) {
+	legs := []models.JournalLeg{{Amount: -15000}, {Amount: 10000}, {Amount: 5000}}
+
+	assert.Equal(t, int64(15000), journalTotal(legs))
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/repository"
+	"github.com/Brownie44l1/debank/internal/storage"
+	"github.com/jackc/pgx/v5"
+)
+
+// ==============================================
+// SERVICE ERRORS
+// ==============================================
+
+var (
+	ErrTierSingleLimitExceeded  = errors.New("amount exceeds the single transaction limit for your KYC tier")
+	ErrTierDailyLimitExceeded   = errors.New("amount exceeds the daily limit for your KYC tier")
+	ErrTierMonthlyLimitExceeded = errors.New("amount exceeds the monthly limit for your KYC tier")
+	ErrTierMaxBalanceExceeded   = errors.New("balance would exceed the maximum for your KYC tier")
+	ErrRecipientLimitExceeded   = errors.New("recipient cannot receive this amount")
+)
+
+// ==============================================
+// SERVICE
+// ==============================================
+
+// KYCScreener screens upgrade submissions against sanctions lists (implemented by ScreeningService)
+type KYCScreener interface {
+	ScreenKYCSubmission(ctx context.Context, userID int, submissionID int64, names ...string) (bool, error)
+	CheckKYCSubmission(ctx context.Context, submissionID int64) error
+}
+
+// UserTierLookup resolves the tier a user is on (implemented by KYCRepository)
+type UserTierLookup interface {
+	GetUserTier(ctx context.Context, userID int) (*models.KYCTier, error)
+}
+
+// KYCService owns KYC tiers, enforces their limits on the ledger paths and
+// runs the tier upgrade workflow. It implements TransactionLimiter for WalletService
+type KYCService struct {
+	repo       *repository.KYCRepository
+	tiers      UserTierLookup
+	walletRepo WalletRepositoryInterface
+	userRepo   *repository.UserRepository
+	store      storage.Storage
+	verifier   IdentityVerifier
+	screener   KYCScreener
+	now        func() time.Time
+}
+
+func NewKYCService(
+	repo *repository.KYCRepository,
+	walletRepo WalletRepositoryInterface,
+	userRepo *repository.UserRepository,
+	store storage.Storage,
+	verifier IdentityVerifier,
+	screener KYCScreener,
+) *KYCService {
+	return &KYCService{
+		repo:       repo,
+		tiers:      repo,
+		walletRepo: walletRepo,
+		userRepo:   userRepo,
+		store:      store,
+		verifier:   verifier,
+		screener:   screener,
+		now:        time.Now,
+	}
+}
+
+// ==============================================
+// LIMIT ENFORCEMENT
+// ==============================================
+// Both checks run inside the ledger transaction after the account row
+// has been locked, so concurrent debits on one account are serialised
+// and cannot jointly overshoot a rolling cap.
+
+// CheckDebit verifies that debiting amount from a locked account stays within its tier
+func (s *KYCService) CheckDebit(ctx context.Context, tx pgx.Tx, account *models.Account, amount int64) error {
+	tier, err := s.tierForAccount(ctx, account)
+	if err != nil || tier == nil {
+		return err
+	}
+
+	if amount > tier.MaxSingleTransaction {
+		return ErrTierSingleLimitExceeded
+	}
+
+	now := s.now()
+
+	daily, err := s.walletRepo.GetDebitTotalSinceTx(ctx, tx, account.ID, now.Add(-models.KYCDailyWindow))
+	if err != nil {
+		return err
+	}
+	if daily+amount > tier.DailyDebitLimit {
+		log.Printf("[KYC] Daily limit hit - AccountID: %d, Tier: %d, Used: %d, Amount: %d", account.ID, tier.Tier, daily, amount)
+		return ErrTierDailyLimitExceeded
+	}
+
+	monthly, err := s.walletRepo.GetDebitTotalSinceTx(ctx, tx, account.ID, now.Add(-models.KYCMonthlyWindow))
+	if err != nil {
+		return err
+	}
+	if monthly+amount > tier.MonthlyDebitLimit {
+		log.Printf("[KYC] Monthly limit hit - AccountID: %d, Tier: %d, Used: %d, Amount: %d", account.ID, tier.Tier, monthly, amount)
+		return ErrTierMonthlyLimitExceeded
+	}
+
+	return nil
+}
+
+// CheckCredit verifies that crediting amount to a locked account stays within its tier's maximum balance
+func (s *KYCService) CheckCredit(ctx context.Context, tx pgx.Tx, account *models.Account, amount int64) error {
+	tier, err := s.tierForAccount(ctx, account)
+	if err != nil || tier == nil {
+		return err
+	}
+
+	if amount > tier.MaxSingleTransaction {
+		return ErrTierSingleLimitExceeded
+	}
+	if !tier.AllowsBalance(account.Balance + amount) {
+		return ErrTierMaxBalanceExceeded
+	}
+
+	return nil
+}
+
+// tierForAccount returns nil for accounts not owned by a user (system accounts have no tier)
+func (s *KYCService) tierForAccount(ctx context.Context, account *models.Account) (*models.KYCTier, error) {
+	if !account.UserID.Valid {
+		return nil, nil
+	}
+
+	tier, err := s.tiers.GetUserTier(ctx, int(account.UserID.Int32))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kyc tier: %w", err)
+	}
+	return tier, nil
+}
+
+// ==============================================
+// QUERIES
+// ==============================================
+
+// ListTiers returns every tier and its limits
+func (s *KYCService) ListTiers(ctx context.Context) (*dto.KYCTiersResponse, error) {
+	tiers, err := s.repo.ListTiers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.KYCTiersResponse{Tiers: make([]dto.KYCTierDTO, 0, len(tiers))}
+	for i := range tiers {
+		resp.Tiers = append(resp.Tiers, kycTierToDTO(&tiers[i]))
+	}
+	return resp, nil
+}
+
+// GetLimits returns the user's tier together with their rolling usage
+func (s *KYCService) GetLimits(ctx context.Context, userID int) (*dto.KYCLimitsResponse, error) {
+	tier, err := s.repo.GetUserTier(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.walletRepo.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		if isAccountNotFoundError(err) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+
+	now := s.now()
+	daily, err := s.walletRepo.GetDebitTotalSince(ctx, account.ID, now.Add(-models.KYCDailyWindow))
+	if err != nil {
+		return nil, err
+	}
+	monthly, err := s.walletRepo.GetDebitTotalSince(ctx, account.ID, now.Add(-models.KYCMonthlyWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.KYCLimitsResponse{
+		CurrentTier:           kycTierToDTO(tier),
+		Balance:               account.Balance,
+		DailyDebitUsed:        daily,
+		DailyDebitRemaining:   max(tier.DailyDebitLimit-daily, 0),
+		MonthlyDebitUsed:      monthly,
+		MonthlyDebitRemaining: max(tier.MonthlyDebitLimit-monthly, 0),
+	}
+
+	next, err := s.repo.GetTier(ctx, int(tier.Tier)+1)
+	if err != nil && !errors.Is(err, repository.ErrKYCTierNotFound) {
+		return nil, err
+	}
+	if next != nil {
+		nextDTO := kycTierToDTO(next)
+		resp.NextTier = &nextDTO
+	}
+
+	return resp, nil
+}
+
+func kycTierToDTO(t *models.KYCTier) dto.KYCTierDTO {
+	resp := dto.KYCTierDTO{
+		Tier:                 int(t.Tier),
+		Name:                 t.Name,
+		Requirements:         t.Requirements,
+		MaxSingleTransaction: t.MaxSingleTransaction,
+		DailyDebitLimit:      t.DailyDebitLimit,
+		MonthlyDebitLimit:    t.MonthlyDebitLimit,
+	}
+	if t.MaxBalance.Valid {
+		maxBalance := t.MaxBalance.Int64
+		resp.MaxBalance = &maxBalance
+	}
+	return resp
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTierLookup puts every user on the same tier
+type fakeTierLookup struct {
+	tier *models.KYCTier
+}
+
+func (f *fakeTierLookup) GetUserTier(ctx context.Context, userID int) (*models.KYCTier, error) {
+	return f.tier, nil
+}
+
+type pastDebit struct {
+	at     time.Time
+	amount int64
+}
+
+// fakeDebitHistory sums the debits made at or after since
+type fakeDebitHistory struct {
+	WalletRepositoryInterface
+	debits []pastDebit
+}
+
+func (f *fakeDebitHistory) GetDebitTotalSinceTx(ctx context.Context, tx pgx.Tx, accountID int64, since time.Time) (int64, error) {
+	var total int64
+	for _, d := range f.debits {
+		if !d.at.Before(since) {
+			total += d.amount
+		}
+	}
+	return total, nil
+}
+
+var kycTestNow = time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+
+func testTier() *models.KYCTier {
+	return &models.KYCTier{
+		Tier:                 1,
+		MaxSingleTransaction: 50_000,
+		DailyDebitLimit:      100_000,
+		MonthlyDebitLimit:    300_000,
+		MaxBalance:           pgtype.Int8{Int64: 500_000, Valid: true},
+	}
+}
+
+func newLimitTestService(tier *models.KYCTier, debits ...pastDebit) *KYCService {
+	return &KYCService{
+		tiers:      &fakeTierLookup{tier: tier},
+		walletRepo: &fakeDebitHistory{debits: debits},
+		now:        func() time.Time { return kycTestNow },
+	}
+}
+
+func userAccount(balance int64) *models.Account {
+	return &models.Account{ID: 1, UserID: pgtype.Int4{Int32: 7, Valid: true}, Balance: balance}
+}
+
+func TestCheckDebit_Limits(t *testing.T) {
+	hoursAgo := func(h int) time.Time { return kycTestNow.Add(-time.Duration(h) * time.Hour) }
+
+	tests := []struct {
+		name   string
+		debits []pastDebit
+		amount int64
+		want   error
+	}{
+		{name: "at single limit", amount: 50_000},
+		{name: "over single limit", amount: 50_001, want: ErrTierSingleLimitExceeded},
+
+		{name: "reaches daily limit", debits: []pastDebit{{hoursAgo(1), 50_000}}, amount: 50_000},
+		{name: "over daily limit", debits: []pastDebit{{hoursAgo(1), 50_001}}, amount: 50_000, want: ErrTierDailyLimitExceeded},
+		{name: "debit exactly 24h ago is in the daily window", debits: []pastDebit{{hoursAgo(24), 60_000}}, amount: 40_001, want: ErrTierDailyLimitExceeded},
+		{name: "debit older than 24h leaves the daily window", debits: []pastDebit{{hoursAgo(25), 60_000}}, amount: 50_000},
+
+		{name: "reaches monthly limit", debits: []pastDebit{{hoursAgo(48), 250_000}}, amount: 50_000},
+		{name: "over monthly limit", debits: []pastDebit{{hoursAgo(48), 250_001}}, amount: 50_000, want: ErrTierMonthlyLimitExceeded},
+		{name: "debit exactly 30 days ago is in the monthly window", debits: []pastDebit{{hoursAgo(30 * 24), 250_001}}, amount: 50_000, want: ErrTierMonthlyLimitExceeded},
+		{name: "debit older than 30 days leaves the monthly window", debits: []pastDebit{{hoursAgo(30*24 + 1), 250_001}}, amount: 50_000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newLimitTestService(testTier(), tt.debits...)
+			err := s.CheckDebit(context.Background(), nil, userAccount(0), tt.amount)
+			if tt.want == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckCredit_Limits(t *testing.T) {
+	tests := []struct {
+		name    string
+		balance int64
+		amount  int64
+		want    error
+	}{
+		{name: "within max balance", balance: 400_000, amount: 50_000},
+		{name: "reaches max balance", balance: 450_000, amount: 50_000},
+		{name: "pushes recipient over max balance", balance: 450_001, amount: 50_000, want: ErrTierMaxBalanceExceeded},
+		{name: "recipient already at max balance", balance: 500_000, amount: 1, want: ErrTierMaxBalanceExceeded},
+		{name: "over single limit", balance: 0, amount: 50_001, want: ErrTierSingleLimitExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newLimitTestService(testTier())
+			err := s.CheckCredit(context.Background(), nil, userAccount(tt.balance), tt.amount)
+			if tt.want == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckCredit_UnlimitedBalance(t *testing.T) {
+	tier := testTier()
+	tier.MaxBalance = pgtype.Int8{}
+	s := newLimitTestService(tier)
+
+	require.NoError(t, s.CheckCredit(context.Background(), nil, userAccount(1_000_000_000), 50_000))
+}
+
+// System accounts have no tier, so neither check applies
+func TestLimits_SkipSystemAccounts(t *testing.T) {
+	s := newLimitTestService(testTier(), pastDebit{kycTestNow, 1_000_000})
+	system := &models.Account{ID: 2, Balance: 1_000_000_000}
+
+	assert.NoError(t, s.CheckDebit(context.Background(), nil, system, 10_000_000))
+	assert.NoError(t, s.CheckCredit(context.Background(), nil, system, 10_000_000))
+}
@@ -0,0 +1,473 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/repository"
+	"github.com/Brownie44l1/debank/pkg/generator"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// BUSINESS RULES (Constants)
+// ==============================================
+
+const (
+	MaxKYCDocumentSize = 5 << 20 // 5 MiB
+)
+
+// Document content types we accept, detected from the bytes rather than trusted from the client
+var allowedKYCContentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"application/pdf": true,
+}
+
+// ==============================================
+// SERVICE ERRORS
+// ==============================================
+
+var (
+	ErrKYCSubmissionNotFound   = errors.New("kyc submission not found")
+	ErrKYCSubmissionPending    = errors.New("a kyc submission is already pending")
+	ErrKYCSubmissionNotPending = errors.New("kyc submission has already been reviewed")
+	ErrKYCDocumentNotFound     = errors.New("kyc document not found")
+	ErrKYCTierNotHigher        = errors.New("target tier must be higher than your current tier")
+	ErrKYCAddressRequired      = errors.New("address is required for tier 3")
+	ErrKYCDocumentsRequired    = errors.New("tier 3 requires at least one uploaded document")
+	ErrKYCDocumentTooLarge     = errors.New("document exceeds the maximum upload size")
+	ErrKYCDocumentType         = errors.New("document must be a JPEG, PNG or PDF")
+	ErrKYCReviewReasonRequired = errors.New("a reason is required to reject a submission")
+	ErrKYCSelfReview           = errors.New("reviewers cannot review their own submission")
+)
+
+// ==============================================
+// USER: SUBMIT
+// ==============================================
+
+// Submit records a tier upgrade request and runs the automated identity check
+// The tier itself only changes when a reviewer approves
+func (s *KYCService) Submit(ctx context.Context, userID int, req dto.SubmitKYCRequest) (*dto.KYCSubmissionDTO, error) {
+	log.Printf("[KYC] Submit - UserID: %d, TargetTier: %d, IDType: %s", userID, req.TargetTier, req.IDType)
+
+	current, err := s.repo.GetUserTier(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.TargetTier <= int(current.Tier) {
+		return nil, ErrKYCTierNotHigher
+	}
+
+	address := strings.TrimSpace(req.Address)
+	if req.TargetTier >= models.KYCTier3 && address == "" {
+		return nil, ErrKYCAddressRequired
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	submission := &models.KYCSubmission{
+		UserID:     int32(userID),
+		TargetTier: int32(req.TargetTier),
+		IDType:     req.IDType,
+		IDNumber:   req.IDNumber,
+	}
+	if address != "" {
+		submission.Address = pgtype.Text{String: address, Valid: true}
+	}
+
+	// A provider outage shouldn't block the submission - the reviewer sees the error
+	result, err := s.verifier.VerifyIdentity(ctx, IdentityCheck{
+		IDType:   req.IDType,
+		IDNumber: req.IDNumber,
+		FullName: user.Name,
+		Phone:    user.Phone,
+	})
+	if err != nil {
+		log.Printf("[KYC] Identity check failed - UserID: %d, Error: %v", userID, err)
+		submission.VerificationStatus = models.KYCVerificationError
+	} else {
+		submission.VerificationStatus = result.Status
+		if result.Reference != "" {
+			submission.VerificationReference = pgtype.Text{String: result.Reference, Valid: true}
+		}
+		if result.FullName != "" {
+			submission.VerifiedName = pgtype.Text{String: result.FullName, Valid: true}
+		}
+	}
+
+	if err := s.repo.CreateSubmission(ctx, submission); err != nil {
+		if errors.Is(err, repository.ErrKYCSubmissionPending) {
+			return nil, ErrKYCSubmissionPending
+		}
+		return nil, err
+	}
+
+	// Screen the profile name and the name the identity provider returned
+	// A potential match stops the submission being approved until compliance reviews it.
+	// If screening fails here, Approve screens again and refuses until it succeeds
+	if err := s.screenSubmission(ctx, submission, user.Name); err != nil {
+		log.Printf("[SCREENING] KYC screening failed - SubmissionID: %d, Error: %v", submission.ID, err)
+	}
+
+	log.Printf("[KYC] Submitted - SubmissionID: %d, Verification: %s", submission.ID, submission.VerificationStatus)
+	return kycSubmissionToDTO(submission, nil, false), nil
+}
+
+// UploadDocument stores a document against one of the user's pending submissions
+func (s *KYCService) UploadDocument(ctx context.Context, userID int, submissionID int64, documentType, fileName string, r io.Reader) (*dto.KYCDocumentDTO, error) {
+	submission, err := s.getSubmission(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	if int(submission.UserID) != userID {
+		return nil, ErrKYCSubmissionNotFound
+	}
+	if !submission.IsPending() {
+		return nil, ErrKYCSubmissionNotPending
+	}
+
+	// Sniff the real content type from the first bytes
+	br := bufio.NewReaderSize(r, 512)
+	head, err := br.Peek(512)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	contentType := http.DetectContentType(head)
+	if !allowedKYCContentTypes[contentType] {
+		return nil, ErrKYCDocumentType
+	}
+
+	token, err := generator.GenerateToken(12)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate storage key: %w", err)
+	}
+	key := fmt.Sprintf("kyc/%d/%d/%s-%s", userID, submissionID, documentType, token)
+
+	hash := sha256.New()
+	limited := io.LimitReader(br, MaxKYCDocumentSize+1)
+	size, err := s.store.Put(ctx, key, io.TeeReader(limited, hash))
+	if err != nil {
+		return nil, err
+	}
+	if size > MaxKYCDocumentSize {
+		_ = s.store.Delete(ctx, key)
+		return nil, ErrKYCDocumentTooLarge
+	}
+
+	document := &models.KYCDocument{
+		SubmissionID: submissionID,
+		UserID:       int32(userID),
+		DocumentType: documentType,
+		StorageKey:   key,
+		FileName:     sanitizeFileName(fileName),
+		ContentType:  contentType,
+		SizeBytes:    size,
+		SHA256:       hex.EncodeToString(hash.Sum(nil)),
+	}
+	if err := s.repo.CreateDocument(ctx, document); err != nil {
+		_ = s.store.Delete(ctx, key)
+		return nil, err
+	}
+
+	log.Printf("[KYC] Document uploaded - SubmissionID: %d, DocumentID: %d, Type: %s, Size: %d", submissionID, document.ID, documentType, size)
+	resp := kycDocumentToDTO(document)
+	return &resp, nil
+}
+
+// ListMySubmissions returns the user's submissions, newest first
+func (s *KYCService) ListMySubmissions(ctx context.Context, userID int) (*dto.KYCSubmissionListResponse, error) {
+	submissions, err := s.repo.ListUserSubmissions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.submissionList(ctx, submissions, false, 0, 0)
+}
+
+// ==============================================
+// REVIEWER: QUEUE AND DECISIONS
+// ==============================================
+
+// ListQueue returns submissions in a given status, oldest first
+func (s *KYCService) ListQueue(ctx context.Context, req dto.ListKYCSubmissionsRequest) (*dto.KYCSubmissionListResponse, error) {
+	status := req.Status
+	if status == "" {
+		status = models.KYCSubmissionStatusPending
+	}
+	page, perPage := req.Page, req.PerPage
+	if page == 0 {
+		page = 1
+	}
+	if perPage == 0 {
+		perPage = 20
+	}
+
+	submissions, err := s.repo.ListSubmissionsByStatus(ctx, status, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+	return s.submissionList(ctx, submissions, true, page, perPage)
+}
+
+// GetForReview returns a submission with its documents and the full ID number
+func (s *KYCService) GetForReview(ctx context.Context, id int64) (*dto.KYCSubmissionDTO, error) {
+	submission, err := s.getSubmission(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	documents, err := s.repo.ListDocuments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return kycSubmissionToDTO(submission, documents, true), nil
+}
+
+// Approve accepts a submission and raises the user's tier, audited in the same transaction
+func (s *KYCService) Approve(ctx context.Context, reviewerID int, id int64, req dto.ReviewKYCSubmissionRequest) (*dto.KYCSubmissionDTO, error) {
+	submission, err := s.getReviewable(ctx, reviewerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	documents, err := s.repo.ListDocuments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if submission.TargetTier >= models.KYCTier3 && len(documents) == 0 {
+		return nil, ErrKYCDocumentsRequired
+	}
+
+	// Screen again so a submission never goes through unscreened, and a
+	// list updated since it was submitted is applied
+	user, err := s.userRepo.GetUserByID(ctx, int(submission.UserID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if err := s.screenSubmission(ctx, submission, user.Name); err != nil {
+		return nil, err
+	}
+	if err := s.screener.CheckKYCSubmission(ctx, id); err != nil {
+		return nil, err
+	}
+
+	audit, err := kycReviewAudit(reviewerID, models.AuditActionKYCApproved, submission, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	approved, err := s.repo.ApproveSubmission(ctx, id, reviewerID, req.Reason, audit)
+	if err != nil {
+		if errors.Is(err, repository.ErrKYCSubmissionNotPending) {
+			return nil, ErrKYCSubmissionNotPending
+		}
+		return nil, err
+	}
+
+	log.Printf("[KYC] Approved - SubmissionID: %d, UserID: %d, Tier: %d, Reviewer: %d", id, approved.UserID, approved.TargetTier, reviewerID)
+	return kycSubmissionToDTO(approved, documents, true), nil
+}
+
+// Reject declines a submission with a reason; the user's tier is unchanged
+func (s *KYCService) Reject(ctx context.Context, reviewerID int, id int64, req dto.ReviewKYCSubmissionRequest) (*dto.KYCSubmissionDTO, error) {
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, ErrKYCReviewReasonRequired
+	}
+
+	submission, err := s.getReviewable(ctx, reviewerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	audit, err := kycReviewAudit(reviewerID, models.AuditActionKYCRejected, submission, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	rejected, err := s.repo.RejectSubmission(ctx, id, reviewerID, req.Reason, audit)
+	if err != nil {
+		if errors.Is(err, repository.ErrKYCSubmissionNotPending) {
+			return nil, ErrKYCSubmissionNotPending
+		}
+		return nil, err
+	}
+
+	documents, err := s.repo.ListDocuments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[KYC] Rejected - SubmissionID: %d, UserID: %d, Reviewer: %d", id, rejected.UserID, reviewerID)
+	return kycSubmissionToDTO(rejected, documents, true), nil
+}
+
+// OpenDocument streams a document to a reviewer; the caller must close the reader
+func (s *KYCService) OpenDocument(ctx context.Context, id int64) (io.ReadCloser, *dto.KYCDocumentDTO, error) {
+	document, err := s.repo.GetDocument(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrKYCDocumentNotFound) {
+			return nil, nil, ErrKYCDocumentNotFound
+		}
+		return nil, nil, err
+	}
+
+	rc, err := s.store.Open(ctx, document.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := kycDocumentToDTO(document)
+	return rc, &resp, nil
+}
+
+// ==============================================
+// HELPERS
+// ==============================================
+
+// screenSubmission screens the user's name and the verified name on a submission
+func (s *KYCService) screenSubmission(ctx context.Context, submission *models.KYCSubmission, userName string) error {
+	if _, err := s.screener.ScreenKYCSubmission(ctx, int(submission.UserID), submission.ID, userName, submission.VerifiedName.String); err != nil {
+		return fmt.Errorf("failed to screen KYC submission: %w", err)
+	}
+	return nil
+}
+
+func (s *KYCService) getSubmission(ctx context.Context, id int64) (*models.KYCSubmission, error) {
+	submission, err := s.repo.GetSubmission(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrKYCSubmissionNotFound) {
+			return nil, ErrKYCSubmissionNotFound
+		}
+		return nil, err
+	}
+	return submission, nil
+}
+
+func (s *KYCService) getReviewable(ctx context.Context, reviewerID int, id int64) (*models.KYCSubmission, error) {
+	submission, err := s.getSubmission(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if int(submission.UserID) == reviewerID {
+		return nil, ErrKYCSelfReview
+	}
+	if !submission.IsPending() {
+		return nil, ErrKYCSubmissionNotPending
+	}
+	return submission, nil
+}
+
+func (s *KYCService) submissionList(ctx context.Context, submissions []models.KYCSubmission, reviewer bool, page, perPage int) (*dto.KYCSubmissionListResponse, error) {
+	resp := &dto.KYCSubmissionListResponse{
+		Submissions: make([]dto.KYCSubmissionDTO, 0, len(submissions)),
+		Page:        page,
+		PerPage:     perPage,
+	}
+	for i := range submissions {
+		documents, err := s.repo.ListDocuments(ctx, submissions[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		resp.Submissions = append(resp.Submissions, *kycSubmissionToDTO(&submissions[i], documents, reviewer))
+	}
+	return resp, nil
+}
+
+func kycReviewAudit(reviewerID int, action string, submission *models.KYCSubmission, reason string) (*models.AuditLog, error) {
+	metadata, err := json.Marshal(map[string]interface{}{
+		"subject_user_id":     submission.UserID,
+		"target_tier":         submission.TargetTier,
+		"verification_status": submission.VerificationStatus,
+		"reason":              reason,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit metadata: %w", err)
+	}
+
+	return &models.AuditLog{
+		UserID:     pgtype.Int4{Int32: int32(reviewerID), Valid: true},
+		Action:     action,
+		EntityType: pgtype.Text{String: "kyc_submission", Valid: true},
+		Metadata:   pgtype.Text{String: string(metadata), Valid: true},
+	}, nil
+}
+
+func kycSubmissionToDTO(s *models.KYCSubmission, documents []models.KYCDocument, reviewer bool) *dto.KYCSubmissionDTO {
+	idNumber := maskAccountNumber(s.IDNumber)
+	if reviewer {
+		idNumber = s.IDNumber
+	}
+
+	resp := &dto.KYCSubmissionDTO{
+		ID:                 s.ID,
+		UserID:             int(s.UserID),
+		TargetTier:         int(s.TargetTier),
+		IDType:             s.IDType,
+		IDNumber:           idNumber,
+		Status:             s.Status,
+		VerificationStatus: s.VerificationStatus,
+		CreatedAt:          s.CreatedAt.Format(time.RFC3339),
+		Documents:          make([]dto.KYCDocumentDTO, 0, len(documents)),
+	}
+	if s.Address.Valid {
+		address := s.Address.String
+		resp.Address = &address
+	}
+	if s.VerifiedName.Valid && reviewer {
+		name := s.VerifiedName.String
+		resp.VerifiedName = &name
+	}
+	if s.ReviewReason.Valid {
+		reason := s.ReviewReason.String
+		resp.ReviewReason = &reason
+	}
+	if s.ReviewedAt.Valid {
+		reviewedAt := s.ReviewedAt.Time.Format(time.RFC3339)
+		resp.ReviewedAt = &reviewedAt
+	}
+	for i := range documents {
+		resp.Documents = append(resp.Documents, kycDocumentToDTO(&documents[i]))
+	}
+	return resp
+}
+
+func kycDocumentToDTO(d *models.KYCDocument) dto.KYCDocumentDTO {
+	return dto.KYCDocumentDTO{
+		ID:           d.ID,
+		DocumentType: d.DocumentType,
+		FileName:     d.FileName,
+		ContentType:  d.ContentType,
+		SizeBytes:    d.SizeBytes,
+		SHA256:       d.SHA256,
+		CreatedAt:    d.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// sanitizeFileName keeps only the base name of a client-supplied file name
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" || name == "" {
+		return "upload"
+	}
+	if len(name) > 255 {
+		name = name[:255]
+	}
+	return name
+}
@@ -0,0 +1,576 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/repository"
+	"github.com/Brownie44l1/debank/pkg/generator"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// LEDGER OPERATIONS SERVICE
+// ==============================================
+// Corrections and checks ops run with cmd/debankctl instead of raw SQL.
+// Like the admin API, permissions are checked by the caller (the CLI
+// resolves its operator once and checks each command); this layer keeps
+// the ledger rules and audits every change with the operator as actor.
+//
+// Changes take a dryRun flag: the work is done inside the database
+// transaction as usual, triggers and constraints included, and then
+// rolled back, so a dry run reports exactly what would have happened.
+
+var (
+	ErrNotOperator          = errors.New("user is not an active staff member")
+	ErrInvalidExternalID    = errors.New("external id must be lowercase letters, digits and underscores, starting with a letter")
+	ErrNotSystemAccount     = errors.New("counter account must be a system account")
+	ErrCurrencyMismatch     = errors.New("accounts hold different currencies")
+	ErrTransactionNotPosted = errors.New("only posted transactions can be reversed")
+	ErrReversalOfReversal   = errors.New("a reversal can't itself be reversed; post an adjustment instead")
+	ErrAlreadyReversed      = errors.New("transaction has already been reversed")
+	ErrDuplicateAccount     = errors.New("an account with this external id already exists")
+)
+
+var externalIDPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{2,49}$`)
+
+type LedgerOpsService struct {
+	walletRepo *repository.WalletRepository
+	reconRepo  *repository.ReconciliationRepository
+	userRepo   *repository.UserRepository
+	audit      *AuditLogger
+}
+
+func NewLedgerOpsService(walletRepo *repository.WalletRepository, reconRepo *repository.ReconciliationRepository, userRepo *repository.UserRepository, audit *AuditLogger) *LedgerOpsService {
+	return &LedgerOpsService{
+		walletRepo: walletRepo,
+		reconRepo:  reconRepo,
+		userRepo:   userRepo,
+		audit:      audit,
+	}
+}
+
+// Operator loads the staff member running commands
+// Their role decides which commands they may run, as it does for /admin routes.
+func (s *LedgerOpsService) Operator(ctx context.Context, userID int) (*models.User, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, mapAdminError(err)
+	}
+	if !user.IsActive || user.IsLocked() || !auth.HasPermission(user.Role, auth.PermAdminAccess) {
+		return nil, ErrNotOperator
+	}
+	return user, nil
+}
+
+// ==============================================
+// ACCOUNTS
+// ==============================================
+
+// CreateSystemAccount opens an account with no user, e.g. a settlement or fee account
+func (s *LedgerOpsService) CreateSystemAccount(ctx context.Context, actorID int, req dto.CreateSystemAccountRequest, dryRun bool) (*dto.AdminAccountDTO, error) {
+	if !externalIDPattern.MatchString(req.ExternalID) {
+		return nil, ErrInvalidExternalID
+	}
+
+	tx, err := s.walletRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	account := &models.Account{
+		ExternalID: pgtype.Text{String: req.ExternalID, Valid: true},
+		Name:       req.Name,
+		Type:       req.Type,
+		Currency:   req.Currency,
+	}
+	if err := s.walletRepo.CreateSystemAccount(ctx, tx, account); err != nil {
+		if errors.Is(err, repository.ErrDuplicateAccount) {
+			return nil, ErrDuplicateAccount
+		}
+		return nil, err
+	}
+
+	if err := s.auditChange(ctx, tx, actorID, models.AuditActionSystemAccountCreated, "account", account.ID, map[string]interface{}{
+		"external_id": req.ExternalID,
+		"type":        req.Type,
+		"currency":    req.Currency,
+		"reason":      req.Reason,
+	}); err != nil {
+		return nil, err
+	}
+	if err := finish(ctx, tx, dryRun); err != nil {
+		return nil, err
+	}
+
+	logChange(dryRun, "System account created - ExternalID: %s, By: %d", req.ExternalID, actorID)
+	return adminAccountToDTO(account), nil
+}
+
+// FreezeAccount stops debits from an account; freezing a frozen account changes nothing
+func (s *LedgerOpsService) FreezeAccount(ctx context.Context, actorID int, req dto.FreezeAccountRequest, dryRun bool) (*dto.AdminAccountDTO, error) {
+	return s.setFrozen(ctx, actorID, req.AccountNumber, req.Reason, true, dryRun)
+}
+
+// UnfreezeAccount lifts a freeze whoever placed it, AML and sanctions holds included
+func (s *LedgerOpsService) UnfreezeAccount(ctx context.Context, actorID int, req dto.UnfreezeAccountRequest, dryRun bool) (*dto.AdminAccountDTO, error) {
+	return s.setFrozen(ctx, actorID, req.AccountNumber, req.Reason, false, dryRun)
+}
+
+func (s *LedgerOpsService) setFrozen(ctx context.Context, actorID int, accountNumber, reason string, freeze, dryRun bool) (*dto.AdminAccountDTO, error) {
+	tx, err := s.walletRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	account, err := s.walletRepo.GetAccountByAccountNumberForUpdate(ctx, tx, accountNumber)
+	if err != nil {
+		if errors.Is(err, repository.ErrAccountNotFound) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+	if account.IsFrozen() == freeze {
+		return adminAccountToDTO(account), nil
+	}
+
+	action := models.AuditActionAccountUnfrozen
+	metadata := map[string]interface{}{
+		"account_number": accountNumber,
+		"reason":         reason,
+	}
+	if freeze {
+		action = models.AuditActionAccountFrozen
+		err = s.walletRepo.FreezeAccount(ctx, tx, account.ID, reason)
+		account.FrozenAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+		account.FrozenReason = pgtype.Text{String: reason, Valid: true}
+	} else {
+		metadata["frozen_reason"] = account.FrozenReason.String
+		err = s.walletRepo.UnfreezeAccount(ctx, tx, account.ID)
+		account.FrozenAt = pgtype.Timestamptz{}
+		account.FrozenReason = pgtype.Text{}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.auditChange(ctx, tx, actorID, action, "account", account.ID, metadata); err != nil {
+		return nil, err
+	}
+	if err := finish(ctx, tx, dryRun); err != nil {
+		return nil, err
+	}
+
+	logChange(dryRun, "Account %s - AccountNumber: %s, By: %d", action, accountNumber, actorID)
+	return adminAccountToDTO(account), nil
+}
+
+// ==============================================
+// POSTINGS
+// ==============================================
+
+// PostAdjustment credits or debits an account against a system account
+func (s *LedgerOpsService) PostAdjustment(ctx context.Context, actorID int, req dto.AdjustmentRequest, dryRun bool) (*dto.AdminTransactionDTO, error) {
+	target, err := s.walletRepo.GetAccountByAccountNumber(ctx, req.AccountNumber)
+	if err != nil {
+		if errors.Is(err, repository.ErrAccountNotFound) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+	counter, err := s.walletRepo.GetSystemAccount(ctx, req.CounterAccount)
+	if err != nil {
+		if errors.Is(err, repository.ErrAccountNotFound) {
+			return nil, ErrNotSystemAccount
+		}
+		return nil, err
+	}
+	if target.ID == counter.ID {
+		return nil, ErrSameAccount
+	}
+	if target.Currency != counter.Currency {
+		return nil, ErrCurrencyMismatch
+	}
+
+	tx, err := s.walletRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	locked, err := lockAccounts(ctx, s.walletRepo, tx, target.ID, counter.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Money moves from -> to; a credit to the target comes out of the counter account
+	from, to := locked[counter.ID], locked[target.ID]
+	if req.Direction == models.DirectionDebit {
+		from, to = to, from
+	}
+	if from.IsUserAccount() && from.Balance < req.Amount {
+		return nil, ErrInsufficientBalance
+	}
+
+	metadata, err := json.Marshal(map[string]interface{}{
+		"reason":      req.Reason,
+		"direction":   req.Direction,
+		"operator_id": actorID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode metadata: %w", err)
+	}
+
+	txn := &models.Transaction{
+		IdempotencyKey: req.IdempotencyKey,
+		Reference:      generator.GenerateReference("ADJ"),
+		Kind:           models.TransactionKindAdjustment,
+		Status:         models.TransactionStatusPosted,
+		Amount:         req.Amount,
+		Currency:       target.Currency,
+		FromAccountID:  pgtype.Int8{Int64: from.ID, Valid: true},
+		ToAccountID:    pgtype.Int8{Int64: to.ID, Valid: true},
+		Description:    pgtype.Text{String: "Manual adjustment", Valid: true},
+		Metadata:       pgtype.Text{String: string(metadata), Valid: true},
+	}
+	if err := s.walletRepo.CreateTransaction(ctx, tx, txn); err != nil {
+		return nil, createTransactionError(err)
+	}
+
+	postings := []models.Posting{
+		{TransactionID: txn.ID, AccountID: from.ID, Amount: -req.Amount, Currency: txn.Currency},
+		{TransactionID: txn.ID, AccountID: to.ID, Amount: req.Amount, Currency: txn.Currency},
+	}
+	if err := createPostings(ctx, s.walletRepo, tx, postings); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditChange(ctx, tx, actorID, models.AuditActionLedgerAdjustment, "transaction", txn.ID, map[string]interface{}{
+		"reference":       txn.Reference,
+		"account_number":  req.AccountNumber,
+		"counter_account": req.CounterAccount,
+		"direction":       req.Direction,
+		"amount":          req.Amount,
+		"reason":          req.Reason,
+	}); err != nil {
+		return nil, err
+	}
+	if err := finish(ctx, tx, dryRun); err != nil {
+		return nil, err
+	}
+
+	logChange(dryRun, "Adjustment posted - Reference: %s, Account: %s, %s %d, By: %d", txn.Reference, req.AccountNumber, req.Direction, req.Amount, actorID)
+	return adminTransactionToDTO(txn, postings), nil
+}
+
+// ReverseTransaction posts the mirror image of a posted transaction and marks it reversed
+func (s *LedgerOpsService) ReverseTransaction(ctx context.Context, actorID int, req dto.ReverseTransactionRequest, dryRun bool) (*dto.AdminTransactionDTO, error) {
+	tx, err := s.walletRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// Locking the original first serialises concurrent reversals of it
+	original, err := s.walletRepo.GetTransactionByReferenceForUpdate(ctx, tx, req.Reference)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoRows) {
+			return nil, ErrTransactionNotFound
+		}
+		return nil, err
+	}
+	switch {
+	case original.Kind == models.TransactionKindReversal:
+		return nil, ErrReversalOfReversal
+	case original.Status == models.TransactionStatusReversed:
+		return nil, ErrAlreadyReversed
+	case !original.IsPosted():
+		return nil, ErrTransactionNotPosted
+	}
+
+	legs, err := s.walletRepo.GetPostingsByTransactionID(ctx, original.ID)
+	if err != nil {
+		return nil, err
+	}
+	accountIDs := make([]int64, len(legs))
+	for i, leg := range legs {
+		accountIDs[i] = leg.AccountID
+	}
+	locked, err := lockAccounts(ctx, s.walletRepo, tx, accountIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each leg is undone; a user who spent what they were credited can't go negative
+	postings := make([]models.Posting, len(legs))
+	for i, leg := range legs {
+		account := locked[leg.AccountID]
+		if account.IsUserAccount() && account.Balance < leg.Amount {
+			return nil, ErrInsufficientBalance
+		}
+		account.Balance -= leg.Amount
+		postings[i] = models.Posting{AccountID: leg.AccountID, Amount: -leg.Amount, Currency: leg.Currency}
+	}
+
+	metadata, err := json.Marshal(map[string]interface{}{
+		"reversal_of": original.Reference,
+		"reason":      req.Reason,
+		"operator_id": actorID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode metadata: %w", err)
+	}
+
+	reversal := &models.Transaction{
+		IdempotencyKey: req.IdempotencyKey,
+		Reference:      generator.GenerateReference("REV"),
+		Kind:           models.TransactionKindReversal,
+		Status:         models.TransactionStatusPosted,
+		Amount:         original.Amount,
+		Currency:       original.Currency,
+		FromAccountID:  original.ToAccountID,
+		ToAccountID:    original.FromAccountID,
+		FromIdentifier: original.ToIdentifier,
+		ToIdentifier:   original.FromIdentifier,
+		Description:    pgtype.Text{String: "Reversal of " + original.Reference, Valid: true},
+		Metadata:       pgtype.Text{String: string(metadata), Valid: true},
+	}
+	if err := s.walletRepo.CreateTransaction(ctx, tx, reversal); err != nil {
+		if errors.Is(err, repository.ErrAlreadyReversed) {
+			return nil, ErrAlreadyReversed
+		}
+		return nil, createTransactionError(err)
+	}
+	for i := range postings {
+		postings[i].TransactionID = reversal.ID
+	}
+	if err := createPostings(ctx, s.walletRepo, tx, postings); err != nil {
+		return nil, err
+	}
+
+	if err := s.walletRepo.MarkTransactionReversed(ctx, tx, original.ID); err != nil {
+		if errors.Is(err, repository.ErrAlreadyReversed) {
+			return nil, ErrAlreadyReversed
+		}
+		return nil, err
+	}
+
+	if err := s.auditChange(ctx, tx, actorID, models.AuditActionTransactionReversed, "transaction", original.ID, map[string]interface{}{
+		"reference":          original.Reference,
+		"reversal_reference": reversal.Reference,
+		"amount":             original.Amount,
+		"reason":             req.Reason,
+	}); err != nil {
+		return nil, err
+	}
+	if err := finish(ctx, tx, dryRun); err != nil {
+		return nil, err
+	}
+
+	logChange(dryRun, "Transaction reversed - Reference: %s, Reversal: %s, By: %d", original.Reference, reversal.Reference, actorID)
+	return adminTransactionToDTO(reversal, postings), nil
+}
+
+// ==============================================
+// CHECKS AND EXPORTS
+// ==============================================
+
+// Reconcile checks every balance against its postings and every transaction nets to zero
+func (s *LedgerOpsService) Reconcile(ctx context.Context) (*dto.ReconciliationReport, error) {
+	rec, err := s.reconRepo.Reconcile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.ReconciliationReport{
+		Accounts:               rec.Accounts,
+		Transactions:           rec.Transactions,
+		BalanceTotal:           rec.BalanceTotal,
+		PostingTotal:           rec.PostingTotal,
+		BalanceMismatches:      make([]dto.BalanceMismatchDTO, len(rec.BalanceMismatches)),
+		UnbalancedTransactions: make([]dto.UnbalancedTransactionDTO, len(rec.UnbalancedTransactions)),
+		CheckedAt:              rec.CheckedAt.Format(time.RFC3339),
+	}
+	for i, m := range rec.BalanceMismatches {
+		report.BalanceMismatches[i] = dto.BalanceMismatchDTO{
+			AccountID:     m.AccountID,
+			AccountNumber: m.AccountNumber,
+			ExternalID:    m.ExternalID.String,
+			Balance:       m.Balance,
+			PostedBalance: m.PostedBalance,
+			Difference:    m.Balance - m.PostedBalance,
+		}
+	}
+	for i, u := range rec.UnbalancedTransactions {
+		report.UnbalancedTransactions[i] = dto.UnbalancedTransactionDTO{
+			TransactionID: u.TransactionID,
+			Reference:     u.Reference,
+			Status:        u.Status,
+			PostingTotal:  u.PostingTotal,
+			Postings:      u.Postings,
+		}
+	}
+	report.OK = rec.PostingTotal == 0 && len(rec.BalanceMismatches) == 0 && len(rec.UnbalancedTransactions) == 0
+
+	if !report.OK {
+		log.Printf("[LEDGER] Reconciliation found %d balance mismatches and %d unbalanced transactions", len(rec.BalanceMismatches), len(rec.UnbalancedTransactions))
+	}
+	return report, nil
+}
+
+// Statement lists an account's postings over a period with running balances
+func (s *LedgerOpsService) Statement(ctx context.Context, actorID int, req dto.StatementRequest) (*dto.StatementResponse, error) {
+	account, err := s.walletRepo.GetAccountByAccountNumber(ctx, req.AccountNumber)
+	if err != nil {
+		if errors.Is(err, repository.ErrAccountNotFound) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+
+	opening, err := s.walletRepo.GetBalanceBefore(ctx, account.ID, req.From)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.walletRepo.GetStatementEntries(ctx, account.ID, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := buildStatement(opening, entries)
+	statement.Account = *adminAccountToDTO(account)
+	statement.From = req.From.Format(time.RFC3339)
+	statement.To = req.To.Format(time.RFC3339)
+
+	entry, err := auditEntry(ctx, actorID, models.AuditActionStatementExported, "account", account.ID, map[string]interface{}{
+		"account_number": req.AccountNumber,
+		"from":           statement.From,
+		"to":             statement.To,
+		"entries":        len(entries),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.audit.Write(ctx, entry); err != nil {
+		return nil, err
+	}
+	return statement, nil
+}
+
+// buildStatement runs the balance forward from opening through each entry
+func buildStatement(opening int64, entries []models.StatementEntry) *dto.StatementResponse {
+	statement := &dto.StatementResponse{
+		OpeningBalance: opening,
+		Entries:        make([]dto.StatementEntryDTO, len(entries)),
+	}
+
+	balance := opening
+	for i, e := range entries {
+		balance += e.Amount
+		line := dto.StatementEntryDTO{
+			Date:        e.CreatedAt.Format(time.RFC3339),
+			Reference:   e.Reference,
+			Type:        e.Kind,
+			Status:      e.Status,
+			Description: e.Description.String,
+			Direction:   models.DirectionCredit,
+			Amount:      e.Amount,
+			Balance:     balance,
+		}
+		if e.Amount < 0 {
+			line.Direction = models.DirectionDebit
+			line.Amount = -e.Amount
+			statement.TotalDebits += line.Amount
+		} else {
+			statement.TotalCredits += line.Amount
+		}
+		statement.Entries[i] = line
+	}
+	statement.ClosingBalance = balance
+	return statement
+}
+
+// ==============================================
+// HELPERS
+// ==============================================
+
+// lockAccounts locks each account once, in ascending ID order so concurrent operations can't deadlock
+func lockAccounts(ctx context.Context, walletRepo *repository.WalletRepository, tx pgx.Tx, ids ...int64) (map[int64]*models.Account, error) {
+	sorted := slices.Clone(ids)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+
+	locked := make(map[int64]*models.Account, len(sorted))
+	for _, id := range sorted {
+		account, err := walletRepo.GetAccountByIDForUpdate(ctx, tx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrAccountNotFound) {
+				return nil, ErrAccountNotFound
+			}
+			return nil, err
+		}
+		locked[id] = account
+	}
+	return locked, nil
+}
+
+func createPostings(ctx context.Context, walletRepo *repository.WalletRepository, tx pgx.Tx, postings []models.Posting) error {
+	for i := range postings {
+		if err := walletRepo.CreatePosting(ctx, tx, &postings[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// auditChange records an operator's change in the transaction that makes it
+func (s *LedgerOpsService) auditChange(ctx context.Context, tx pgx.Tx, actorID int, action, entityType string, entityID int64, metadata map[string]interface{}) error {
+	entry, err := auditEntry(ctx, actorID, action, entityType, entityID, metadata)
+	if err != nil {
+		return err
+	}
+	return s.audit.WriteTx(ctx, tx, entry)
+}
+
+// finish commits tx, or rolls it back for a dry run once every check has passed
+func finish(ctx context.Context, tx pgx.Tx, dryRun bool) error {
+	if dryRun {
+		// Deferred constraints (postings must balance) only fire at commit;
+		// setting them immediate runs them now without committing
+		if _, err := tx.Exec(ctx, "SET CONSTRAINTS ALL IMMEDIATE"); err != nil {
+			return fmt.Errorf("dry run failed: %w", err)
+		}
+		return tx.Rollback(ctx)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+func logChange(dryRun bool, format string, args ...interface{}) {
+	if dryRun {
+		format = "(dry run) " + format
+	}
+	log.Printf("[LEDGER] "+format, args...)
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildStatement_RunsBalanceForward(t *testing.T) {
+	day := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	entries := []models.StatementEntry{
+		{Reference: "DEP-1", Kind: models.TransactionKindDeposit, Amount: 50000, CreatedAt: day},
+		{Reference: "TRF-1", Kind: models.TransactionKindP2P, Amount: -12000, CreatedAt: day.Add(time.Hour)},
+		{Reference: "ADJ-1", Kind: models.TransactionKindAdjustment, Amount: 500, CreatedAt: day.Add(2 * time.Hour)},
+	}
+
+	stmt := buildStatement(10000, entries)
+
+	require.Len(t, stmt.Entries, 3)
+	assert.Equal(t, int64(10000), stmt.OpeningBalance)
+	assert.Equal(t, int64(48500), stmt.ClosingBalance)
+	assert.Equal(t, int64(50500), stmt.TotalCredits)
+	assert.Equal(t, int64(12000), stmt.TotalDebits)
+
+	debit := stmt.Entries[1]
+	assert.Equal(t, models.DirectionDebit, debit.Direction)
+	assert.Equal(t, int64(12000), debit.Amount)
+	assert.Equal(t, int64(48000), debit.Balance)
+	assert.Equal(t, "2026-03-01T10:00:00Z", debit.Date)
+}
+
+func TestBuildStatement_Empty(t *testing.T) {
+	stmt := buildStatement(2500, nil)
+
+	assert.Empty(t, stmt.Entries)
+	assert.Equal(t, int64(2500), stmt.ClosingBalance)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/Brownie44l1/debank/internal/metrics"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scrapeMetrics fetches /metrics the way Prometheus does and returns every sample by name and labels
+func scrapeMetrics(t *testing.T) map[string]float64 {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	samples := map[string]float64{}
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.LastIndexByte(line, ' ')
+		value, err := strconv.ParseFloat(line[i+1:], 64)
+		require.NoError(t, err, line)
+		samples[line[:i]] = value
+	}
+	require.NoError(t, scanner.Err())
+	return samples
+}
+
+// fakeUserStore holds one active user
+type fakeUserStore struct {
+	UserStore
+	user         *models.User
+	failedLogins int
+}
+
+func (f *fakeUserStore) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	copied := *f.user
+	return &copied, nil
+}
+
+func (f *fakeUserStore) RecordFailedLogin(ctx context.Context, userID int, lockUntil time.Time, audits ...*models.AuditLog) error {
+	f.failedLogins++
+	return nil
+}
+
+func TestMetrics_ScrapeAfterTransferAndFailedLogin(t *testing.T) {
+	const (
+		postedTransfers = `debank_transaction_amount_kobo_count{kind="p2p",status="posted"}`
+		transferredKobo = `debank_transaction_amount_kobo_sum{kind="p2p",status="posted"}`
+		wrongPasswords  = `debank_login_failures_total{reason="invalid_password"}`
+	)
+	ctx := context.Background()
+	before := scrapeMetrics(t)
+
+	// A posted transfer
+	ledger := newFakeLedger(
+		&models.Account{ID: 1, UserID: pgtype.Int4{Int32: 1, Valid: true}, AccountNumber: pgtype.Text{String: "0000000001", Valid: true}, Type: models.AccountTypeUser, Balance: 100000},
+		&models.Account{ID: 2, UserID: pgtype.Int4{Int32: 2, Valid: true}, AccountNumber: pgtype.Text{String: "0000000002", Valid: true}, Type: models.AccountTypeUser},
+	)
+	_, err := newPassingWalletService(ledger).Transfer(ctx, 1, dto.TransferRequest{
+		ToIdentifier:   "0000000002",
+		Amount:         25000,
+		Pin:            "1234",
+		IdempotencyKey: "metrics-test-transfer",
+	})
+	require.NoError(t, err)
+
+	// A sign-in with the wrong password
+	hash, err := auth.HashPassword("correct horse battery staple")
+	require.NoError(t, err)
+	users := &fakeUserStore{user: &models.User{ID: 1, PasswordHash: hash, IsActive: true}}
+	auths := NewAuthService(users, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, false)
+	_, err = auths.Login(ctx, dto.LoginRequest{Identifier: "@ada", Password: "wrong"})
+	require.ErrorIs(t, err, models.ErrInvalidCredentials)
+	require.Equal(t, 1, users.failedLogins)
+
+	after := scrapeMetrics(t)
+	assert.Equal(t, 1.0, after[postedTransfers]-before[postedTransfers])
+	assert.Equal(t, 25000.0, after[transferredKobo]-before[transferredKobo])
+	assert.Equal(t, 1.0, after[wrongPasswords]-before[wrongPasswords])
+}
@@ -0,0 +1,517 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/Brownie44l1/debank/internal/clientinfo"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// TWO-FACTOR AUTHENTICATION
+// ==============================================
+// With 2FA on, Login checks the password and returns a short-lived
+// challenge token instead of a JWT; CompleteMFALogin exchanges the
+// token plus a TOTP code, emailed code or recovery code for the JWT.
+// Wrong codes count towards the same lockout as wrong passwords.
+// The same challenge, answered with an emailed code, guards sign-ins
+// from unrecognised devices when NEW_DEVICE_STEP_UP is on.
+
+// ==============================================
+// SERVICE ERRORS
+// ==============================================
+
+var (
+	ErrMFAAlreadyEnabled       = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnabled           = errors.New("two-factor authentication is not enabled")
+	ErrMFAEnrollmentNotStarted = errors.New("no pending authenticator enrollment")
+	ErrMFACodeInvalid          = errors.New("invalid two-factor code")
+	ErrMFAChallengeInvalid     = errors.New("login challenge is invalid or has expired")
+	ErrPasswordIncorrect       = errors.New("password is incorrect")
+)
+
+// ==============================================
+// LOGIN: SECOND STEP
+// ==============================================
+
+// startMFAChallenge issues the challenge token returned by Login when a second factor is needed
+// The challenge remembers the device that asked, so the session is opened for that device
+func (s *AuthService) startMFAChallenge(ctx context.Context, user *models.User, method, reason string) (*dto.LoginResponse, error) {
+	token, err := auth.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	info := clientinfo.FromContext(ctx)
+	challenge := &models.MFAChallenge{
+		UserID:      user.ID,
+		TokenHash:   auth.HashToken(token),
+		Method:      method,
+		Reason:      reason,
+		Fingerprint: pgtype.Text{String: info.DeviceID, Valid: info.DeviceID != ""},
+		ExpiresAt:   time.Now().Add(models.MFAChallengeTTL),
+		IPAddress:   pgtype.Text{String: info.IPAddress, Valid: info.IPAddress != ""},
+		UserAgent:   pgtype.Text{String: info.UserAgent, Valid: info.UserAgent != ""},
+	}
+	if err := s.mfaRepo.CreateChallenge(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	if method == models.MFAMethodEmail {
+		if err := s.sendLoginCode(ctx, user); err != nil && !errors.Is(err, models.ErrOTPResendCooldown) {
+			return nil, err
+		}
+	}
+
+	log.Printf("[MFA] Challenge issued - UserID: %d, Method: %s, Reason: %s", user.ID, method, reason)
+
+	// No user details until the second factor is in
+	return &dto.LoginResponse{
+		MFARequired:  true,
+		MFAToken:     token,
+		MFAMethod:    method,
+		MFAReason:    reason,
+		MFAExpiresIn: int(models.MFAChallengeTTL.Seconds()),
+	}, nil
+}
+
+// CompleteMFALogin exchanges a login challenge and a second-factor code for an access token
+func (s *AuthService) CompleteMFALogin(ctx context.Context, req dto.MFALoginRequest) (*dto.LoginResponse, error) {
+	challenge, user, mfa, err := s.loadChallenge(ctx, req.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.verifySecondFactor(ctx, user, mfa, req.Code, req.RecoveryCode); err != nil {
+		if !errors.Is(err, ErrMFACodeInvalid) {
+			return nil, err
+		}
+		_ = s.mfaRepo.IncrementChallengeAttempts(ctx, challenge.ID)
+		log.Printf("[MFA] Wrong code - UserID: %d, ChallengeID: %d", user.ID, challenge.ID)
+		return nil, s.recordFailedLogin(ctx, user, ErrMFACodeInvalid)
+	}
+
+	completed, err := s.mfaRepo.CompleteChallenge(ctx, challenge.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !completed {
+		return nil, ErrMFAChallengeInvalid
+	}
+
+	return s.completeLogin(ctx, user, challenge.Fingerprint.String)
+}
+
+// SendLoginCodeByEmail emails a code for a pending login challenge, as a fallback for a missing authenticator
+func (s *AuthService) SendLoginCodeByEmail(ctx context.Context, req dto.MFAEmailFallbackRequest) (*dto.MFACodeSentResponse, error) {
+	_, user, _, err := s.loadChallenge(ctx, req.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sendLoginCode(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &dto.MFACodeSentResponse{
+		Message:   "Sign-in code sent to your email",
+		Email:     maskEmail(user.Email),
+		ExpiresIn: models.OTPExpiryMinutes * 60,
+	}, nil
+}
+
+// loadChallenge resolves a challenge token to a usable challenge, its user and their enabled second factor
+// The second factor is nil for new-device challenges
+func (s *AuthService) loadChallenge(ctx context.Context, token string) (*models.MFAChallenge, *models.User, *models.UserMFA, error) {
+	challenge, err := s.mfaRepo.GetChallengeByToken(ctx, auth.HashToken(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrMFAChallengeNotFound) {
+			return nil, nil, nil, ErrMFAChallengeInvalid
+		}
+		return nil, nil, nil, err
+	}
+	if !challenge.IsUsable() {
+		return nil, nil, nil, ErrMFAChallengeInvalid
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, int(challenge.UserID))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.IsLocked() {
+		return nil, nil, nil, models.ErrAccountLocked
+	}
+	if !user.IsActive {
+		return nil, nil, nil, models.ErrAccountInactive
+	}
+
+	// New-device challenges are always answered with an emailed code
+	if challenge.Reason == models.MFAReasonNewDevice {
+		return challenge, user, nil, nil
+	}
+
+	// 2FA may have been turned off since the challenge was issued
+	mfa, err := s.getEnabledMFA(ctx, int(user.ID))
+	if err != nil {
+		if errors.Is(err, ErrMFANotEnabled) {
+			return nil, nil, nil, ErrMFAChallengeInvalid
+		}
+		return nil, nil, nil, err
+	}
+
+	return challenge, user, mfa, nil
+}
+
+// ==============================================
+// ENROLLMENT
+// ==============================================
+
+// GetMFAStatus returns the caller's 2FA settings
+func (s *AuthService) GetMFAStatus(ctx context.Context, userID int) (*dto.MFAStatusResponse, error) {
+	resp := &dto.MFAStatusResponse{}
+
+	mfa, err := s.mfaRepo.GetMFA(ctx, userID)
+	if err != nil && !errors.Is(err, repository.ErrMFANotFound) {
+		return nil, err
+	}
+	if mfa == nil {
+		return resp, nil
+	}
+
+	resp.Method = mfa.Method
+	resp.Enabled = mfa.IsEnabled()
+	resp.PendingEnrollment = !mfa.IsEnabled()
+	if resp.Enabled {
+		if resp.RecoveryCodesRemaining, err = s.mfaRepo.CountRecoveryCodes(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// StartTOTPEnrollment issues a new authenticator secret; 2FA only turns on once ConfirmTOTP sees a valid code
+func (s *AuthService) StartTOTPEnrollment(ctx context.Context, userID int) (*dto.TOTPEnrollmentResponse, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	secret, uri, err := auth.GenerateTOTPSecret(user.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate totp secret: %w", err)
+	}
+	encrypted, err := auth.EncryptSecret(secret, s.mfaKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mfaRepo.StartTOTPEnrollment(ctx, userID, encrypted); err != nil {
+		if errors.Is(err, repository.ErrMFAAlreadyEnabled) {
+			return nil, ErrMFAAlreadyEnabled
+		}
+		return nil, err
+	}
+
+	return &dto.TOTPEnrollmentResponse{
+		Secret:     secret,
+		OTPAuthURI: uri,
+		Message:    "Add this key to your authenticator app, then confirm with the 6-digit code it shows",
+	}, nil
+}
+
+// ConfirmTOTP enables authenticator 2FA with the first code from the app and returns recovery codes
+func (s *AuthService) ConfirmTOTP(ctx context.Context, userID int, req dto.ConfirmTOTPRequest) (*dto.RecoveryCodesResponse, error) {
+	mfa, err := s.mfaRepo.GetMFA(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMFANotFound) {
+			return nil, ErrMFAEnrollmentNotStarted
+		}
+		return nil, err
+	}
+	if mfa.IsEnabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if mfa.Method != models.MFAMethodTOTP {
+		return nil, ErrMFAEnrollmentNotStarted
+	}
+
+	secret, err := auth.DecryptSecret(mfa.TOTPSecret.String, s.mfaKey)
+	if err != nil {
+		return nil, err
+	}
+	step, ok := auth.ValidateTOTP(req.Code, secret, time.Now())
+	if !ok {
+		return nil, ErrMFACodeInvalid
+	}
+
+	return s.enableMFA(ctx, userID, models.MFAMethodTOTP, step)
+}
+
+// EnableEmailMFA turns on emailed sign-in codes after re-checking the password
+func (s *AuthService) EnableEmailMFA(ctx context.Context, userID int, req dto.EnableEmailMFARequest) (*dto.RecoveryCodesResponse, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if !auth.CheckPassword(req.Password, user.PasswordHash) {
+		return nil, ErrPasswordIncorrect
+	}
+	if !user.IsEmailVerified {
+		return nil, models.ErrEmailNotVerified
+	}
+
+	return s.enableMFA(ctx, userID, models.MFAMethodEmail, 0)
+}
+
+func (s *AuthService) enableMFA(ctx context.Context, userID int, method string, step int64) (*dto.RecoveryCodesResponse, error) {
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	audit, err := userAudit(ctx, userID, models.AuditActionMFAEnabled, map[string]interface{}{"method": method})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mfaRepo.EnableMFA(ctx, userID, method, step, hashes, audit); err != nil {
+		if errors.Is(err, repository.ErrMFAAlreadyEnabled) {
+			return nil, ErrMFAAlreadyEnabled
+		}
+		return nil, err
+	}
+
+	log.Printf("[MFA] Enabled - UserID: %d, Method: %s", userID, method)
+	return &dto.RecoveryCodesResponse{
+		Method:        method,
+		RecoveryCodes: codes,
+		Message:       "Two-factor authentication is on. Store these recovery codes somewhere safe; each works once and they won't be shown again.",
+	}, nil
+}
+
+// ==============================================
+// MANAGEMENT (re-authenticated)
+// ==============================================
+
+// SendMFACode emails a code the signed-in user can use to re-authenticate
+func (s *AuthService) SendMFACode(ctx context.Context, userID int) (*dto.MFACodeSentResponse, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if _, err := s.getEnabledMFA(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.sendLoginCode(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &dto.MFACodeSentResponse{
+		Message:   "Verification code sent to your email",
+		Email:     maskEmail(user.Email),
+		ExpiresIn: models.OTPExpiryMinutes * 60,
+	}, nil
+}
+
+// DisableMFA turns 2FA off; needs the password and a current second factor
+func (s *AuthService) DisableMFA(ctx context.Context, userID int, req dto.MFAReauthRequest) error {
+	if err := s.reauthenticate(ctx, userID, req); err != nil {
+		return err
+	}
+
+	audit, err := userAudit(ctx, userID, models.AuditActionMFADisabled, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.mfaRepo.DisableMFA(ctx, userID, audit); err != nil {
+		if errors.Is(err, repository.ErrMFANotFound) {
+			return ErrMFANotEnabled
+		}
+		return err
+	}
+
+	log.Printf("[MFA] Disabled - UserID: %d", userID)
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces every recovery code; needs the password and a current second factor
+func (s *AuthService) RegenerateRecoveryCodes(ctx context.Context, userID int, req dto.MFAReauthRequest) (*dto.RecoveryCodesResponse, error) {
+	if err := s.reauthenticate(ctx, userID, req); err != nil {
+		return nil, err
+	}
+
+	mfa, err := s.getEnabledMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	audit, err := userAudit(ctx, userID, models.AuditActionMFARecoveryCodes, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mfaRepo.ReplaceRecoveryCodes(ctx, userID, hashes, audit); err != nil {
+		return nil, err
+	}
+
+	return &dto.RecoveryCodesResponse{
+		Method:        mfa.Method,
+		RecoveryCodes: codes,
+		Message:       "Your old recovery codes no longer work. Store these somewhere safe.",
+	}, nil
+}
+
+func (s *AuthService) reauthenticate(ctx context.Context, userID int, req dto.MFAReauthRequest) error {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	mfa, err := s.getEnabledMFA(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !auth.CheckPassword(req.Password, user.PasswordHash) {
+		return ErrPasswordIncorrect
+	}
+
+	return s.verifySecondFactor(ctx, user, mfa, req.Code, req.RecoveryCode)
+}
+
+// ==============================================
+// HELPERS
+// ==============================================
+
+// verifySecondFactor accepts a recovery code, a fresh TOTP code, or an emailed code
+// Emailed codes are accepted for TOTP users too, as the fallback when the authenticator isn't to hand
+// With no second factor enrolled (mfa is nil) only an emailed code will do
+func (s *AuthService) verifySecondFactor(ctx context.Context, user *models.User, mfa *models.UserMFA, code, recoveryCode string) error {
+	userID := int(user.ID)
+
+	if recoveryCode != "" {
+		audit, err := userAudit(ctx, userID, models.AuditActionMFARecoveryUsed, nil)
+		if err != nil {
+			return err
+		}
+		used, err := s.mfaRepo.UseRecoveryCode(ctx, userID, auth.HashToken(auth.NormalizeRecoveryCode(recoveryCode)), audit)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrMFACodeInvalid
+		}
+		log.Printf("[MFA] Recovery code used - UserID: %d", userID)
+		return nil
+	}
+
+	if mfa != nil && mfa.Method == models.MFAMethodTOTP {
+		valid, err := checkTOTP(ctx, s.mfaRepo, s.mfaKey, mfa, code)
+		if err != nil {
+			return err
+		}
+		if valid {
+			return nil
+		}
+	}
+
+	valid, err := s.verificationRepo.VerifyOTP(ctx, user.Email, code, models.OTPPurposeLoginMFA)
+	if err != nil {
+		return fmt.Errorf("failed to verify OTP: %w", err)
+	}
+	if !valid {
+		return ErrMFACodeInvalid
+	}
+	return nil
+}
+
+// checkTOTP validates a code from the user's authenticator and burns its time step, so it can't be replayed
+func checkTOTP(ctx context.Context, mfaRepo *repository.MFARepository, key []byte, mfa *models.UserMFA, code string) (bool, error) {
+	secret, err := auth.DecryptSecret(mfa.TOTPSecret.String, key)
+	if err != nil {
+		return false, err
+	}
+
+	step, ok := auth.ValidateTOTP(code, secret, time.Now())
+	if !ok {
+		return false, nil
+	}
+
+	return mfaRepo.RecordTOTPStep(ctx, int(mfa.UserID), step)
+}
+
+func (s *AuthService) getEnabledMFA(ctx context.Context, userID int) (*models.UserMFA, error) {
+	mfa, err := s.mfaRepo.GetMFA(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMFANotFound) {
+			return nil, ErrMFANotEnabled
+		}
+		return nil, err
+	}
+	if !mfa.IsEnabled() {
+		return nil, ErrMFANotEnabled
+	}
+	return mfa, nil
+}
+
+// sendLoginCode emails a login_mfa code, respecting the resend cooldown
+func (s *AuthService) sendLoginCode(ctx context.Context, user *models.User) error {
+	canSend, err := s.verificationRepo.CanResendOTP(ctx, user.Email, models.OTPPurposeLoginMFA, models.OTPResendCooldown)
+	if err != nil {
+		return fmt.Errorf("failed to check resend eligibility: %w", err)
+	}
+	if !canSend {
+		return models.ErrOTPResendCooldown
+	}
+
+	code := auth.GenerateOTP()
+	otp := &models.VerificationCode{
+		UserID:    pgtype.Int4{Int32: user.ID, Valid: true},
+		Email:     user.Email,
+		Code:      code,
+		Purpose:   models.OTPPurposeLoginMFA,
+		ExpiresAt: time.Now().Add(time.Duration(models.OTPExpiryMinutes) * time.Minute),
+	}
+	if err := s.verificationRepo.CreateOTP(ctx, otp); err != nil {
+		return fmt.Errorf("failed to create OTP: %w", err)
+	}
+
+	if err := s.emailService.SendOTP(user.Email, code, models.OTPPurposeLoginMFA); err != nil {
+		return fmt.Errorf("failed to send OTP email: %w", err)
+	}
+	return nil
+}
+
+// newRecoveryCodes returns the codes to show the user and the hashes to store
+func newRecoveryCodes() ([]string, []string, error) {
+	codes, err := auth.GenerateRecoveryCodes(auth.RecoveryCodeCount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hashes := make([]string, len(codes))
+	for i, c := range codes {
+		hashes[i] = auth.HashToken(auth.NormalizeRecoveryCode(c))
+	}
+	return codes, hashes, nil
+}
+
+// userAudit builds an audit entry for a security event on the user's own account
+func userAudit(ctx context.Context, userID int, action string, metadata map[string]interface{}) (*models.AuditLog, error) {
+	return auditEntry(ctx, userID, action, "user", int64(userID), metadata)
+}
@@ -0,0 +1,519 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/repository"
+	"github.com/Brownie44l1/debank/pkg/generator"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// SERVICE ERRORS
+// ==============================================
+
+var (
+	ErrPaymentRequestNotFound = errors.New("payment request not found")
+	ErrPaymentRequestNotOpen  = errors.New("payment request is no longer open")
+	ErrPaymentRequestExpired  = errors.New("payment request has expired")
+	ErrNotPaymentRequestPayer = errors.New("you are not the payer of this payment request")
+	ErrPayerNotFound          = errors.New("payer not found")
+	ErrSelfPaymentRequest     = errors.New("cannot request money from yourself")
+)
+
+// ==============================================
+// SERVICE
+// ==============================================
+
+// PaymentRequestStore persists payment requests (implemented by repository.PaymentRequestRepository)
+type PaymentRequestStore interface {
+	CreatePaymentRequest(ctx context.Context, pr *models.PaymentRequest) error
+	GetPaymentRequestByID(ctx context.Context, id int64) (*models.PaymentRequest, error)
+	GetPaymentRequestByToken(ctx context.Context, token string) (*models.PaymentRequest, error)
+	GetPaymentRequestForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*models.PaymentRequest, error)
+	MarkPaid(ctx context.Context, tx pgx.Tx, id int64, paidByUserID int, transactionID int64) error
+	MarkDeclined(ctx context.Context, id int64, reason string) error
+	ExpireOverdue(ctx context.Context) (int64, error)
+	ListSentPaymentRequests(ctx context.Context, userID int, status string, limit, offset int) ([]models.PaymentRequest, error)
+	ListReceivedPaymentRequests(ctx context.Context, userID int, status string, limit, offset int) ([]models.PaymentRequest, error)
+}
+
+// UserLookup finds users by ID, username or phone (implemented by repository.UserRepository)
+type UserLookup interface {
+	GetUserByID(ctx context.Context, userID int) (*models.User, error)
+	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
+	GetUserByPhone(ctx context.Context, phone string) (*models.User, error)
+}
+
+type PaymentRequestService struct {
+	repo          PaymentRequestStore
+	userRepo      UserLookup
+	walletService *WalletService
+	linkBaseURL   string
+}
+
+func NewPaymentRequestService(
+	repo PaymentRequestStore,
+	userRepo UserLookup,
+	walletService *WalletService,
+	linkBaseURL string,
+) *PaymentRequestService {
+	return &PaymentRequestService{
+		repo:          repo,
+		userRepo:      userRepo,
+		walletService: walletService,
+		linkBaseURL:   strings.TrimRight(linkBaseURL, "/"),
+	}
+}
+
+// ==============================================
+// CREATE
+// ==============================================
+
+func (s *PaymentRequestService) Create(ctx context.Context, userID int, req dto.CreatePaymentRequestRequest) (*dto.PaymentRequestDTO, error) {
+	log.Printf("[PAYMENT_REQUEST] Create - UserID: %d, Payer: %q, Amount: %d kobo", userID, req.PayerIdentifier, req.Amount)
+
+	// 1. Validate amount against the same rules as a transfer
+	if err := s.walletService.validateTransferAmount(req.Amount); err != nil {
+		return nil, err
+	}
+
+	requester, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get requester: %w", err)
+	}
+
+	// 2. Resolve payer (optional - empty means open link)
+	pr := &models.PaymentRequest{
+		RequesterUserID: requester.ID,
+		Amount:          req.Amount,
+		Currency:        "NGN",
+	}
+
+	if identifier := strings.TrimSpace(req.PayerIdentifier); identifier != "" {
+		payer, err := s.resolveUser(ctx, identifier)
+		if err != nil {
+			return nil, err
+		}
+		if payer.ID == requester.ID {
+			return nil, ErrSelfPaymentRequest
+		}
+		pr.PayerUserID = pgtype.Int4{Int32: payer.ID, Valid: true}
+		pr.PayerIdentifier = pgtype.Text{String: identifier, Valid: true}
+	}
+
+	if req.Description != "" {
+		pr.Description = pgtype.Text{String: req.Description, Valid: true}
+	}
+
+	// 3. Expiry
+	expiry := models.PaymentRequestDefaultExpiry
+	if req.ExpiresInHours > 0 {
+		expiry = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+	if expiry > models.PaymentRequestMaxExpiry {
+		expiry = models.PaymentRequestMaxExpiry
+	}
+	pr.ExpiresAt = time.Now().Add(expiry)
+
+	// 4. Token for the shareable link
+	token, err := generator.GenerateToken(18)
+	if err != nil {
+		return nil, err
+	}
+	pr.Token = token
+
+	if err := s.repo.CreatePaymentRequest(ctx, pr); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[PAYMENT_REQUEST] Created - ID: %d, Requester: %d", pr.ID, userID)
+	return s.toDTO(pr, requester), nil
+}
+
+// ==============================================
+// VIEW
+// ==============================================
+
+// Get returns a request to one of its parties
+func (s *PaymentRequestService) Get(ctx context.Context, userID int, id int64) (*dto.PaymentRequestDTO, error) {
+	pr, err := s.getByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.isParty(pr, userID) {
+		// Don't reveal that the request exists
+		return nil, ErrPaymentRequestNotFound
+	}
+
+	return s.toDTOWithRequester(ctx, pr)
+}
+
+// GetByToken returns a request from its shareable link
+// Anyone holding the token may view it; the token is the secret
+func (s *PaymentRequestService) GetByToken(ctx context.Context, token string) (*dto.PaymentRequestDTO, error) {
+	pr, err := s.getByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toDTOWithRequester(ctx, pr)
+}
+
+// ==============================================
+// PAY
+// ==============================================
+
+// Pay settles a request by ID
+func (s *PaymentRequestService) Pay(ctx context.Context, userID int, id int64, req dto.PayPaymentRequestRequest) (*dto.PayPaymentRequestResponse, error) {
+	pr, err := s.getByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.pay(ctx, userID, pr, req)
+}
+
+// PayByToken settles a request opened from a shareable link
+func (s *PaymentRequestService) PayByToken(ctx context.Context, userID int, token string, req dto.PayPaymentRequestRequest) (*dto.PayPaymentRequestResponse, error) {
+	pr, err := s.getByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return s.pay(ctx, userID, pr, req)
+}
+
+func (s *PaymentRequestService) pay(ctx context.Context, userID int, pr *models.PaymentRequest, req dto.PayPaymentRequestRequest) (*dto.PayPaymentRequestResponse, error) {
+	log.Printf("[PAYMENT_REQUEST] Pay - ID: %d, PayerUserID: %d", pr.ID, userID)
+
+	// 1. Check state and payer. A payer retrying a request they already
+	// settled goes on to the transfer, which replays the original
+	retry := pr.Status == models.PaymentRequestStatusPaid &&
+		pr.PaidByUserID.Valid && pr.PaidByUserID.Int32 == int32(userID)
+	if !retry {
+		if err := s.checkActionable(pr, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	requester, err := s.userRepo.GetUserByID(ctx, int(pr.RequesterUserID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get requester: %w", err)
+	}
+
+	requesterAccount, err := s.walletService.repo.GetAccountByUserID(ctx, int(pr.RequesterUserID))
+	if err != nil {
+		if isAccountNotFoundError(err) {
+			return nil, ErrRecipientNotFound
+		}
+		return nil, err
+	}
+
+	// 2. Settle through the normal P2P transfer path (which also checks the payer's PIN)
+	description := fmt.Sprintf("Payment request #%d", pr.ID)
+	if pr.Description.Valid {
+		description = pr.Description.String
+	}
+
+	// The request is locked and closed inside the transfer's own transaction,
+	// so a decline, cancel or expiry can't land between the money moving and
+	// the request being marked paid
+	settle := func(ctx context.Context, tx pgx.Tx, txn *models.Transaction) error {
+		locked, err := s.repo.GetPaymentRequestForUpdate(ctx, tx, pr.ID)
+		if err != nil {
+			if errors.Is(err, repository.ErrPaymentRequestNotFound) {
+				return ErrPaymentRequestNotFound
+			}
+			return err
+		}
+		if locked.Status == models.PaymentRequestStatusOpen && locked.IsExpired() {
+			return ErrPaymentRequestExpired
+		}
+		if err := s.checkActionable(locked, userID); err != nil {
+			return err
+		}
+		if err := s.repo.MarkPaid(ctx, tx, pr.ID, userID, txn.ID); err != nil {
+			if errors.Is(err, repository.ErrPaymentRequestNotOpen) {
+				return ErrPaymentRequestNotOpen
+			}
+			return err
+		}
+		return nil
+	}
+
+	// The idempotency key is derived from the request itself in the reserved
+	// internal namespace, so at most one transfer can ever exist for it and
+	// no client can claim it first
+	transfer, err := s.walletService.transfer(ctx, userID, dto.TransferRequest{
+		ToIdentifier:   requesterAccount.AccountNumber.String,
+		Amount:         pr.Amount,
+		Pin:            req.Pin,
+		IdempotencyKey: paymentRequestIdempotencyKey(pr.ID),
+		Description:    description,
+		StepUp:         req.StepUp,
+	}, settle)
+	if err != nil {
+		if errors.Is(err, ErrIdempotencyConflict) {
+			// Another payer already settled this open link
+			return nil, ErrPaymentRequestNotOpen
+		}
+		return nil, err
+	}
+
+	updated, err := s.repo.GetPaymentRequestByID(ctx, pr.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[PAYMENT_REQUEST] Paid - ID: %d, TxnID: %d", pr.ID, transfer.TransactionID)
+	return &dto.PayPaymentRequestResponse{
+		PaymentRequest: s.toDTO(updated, requester),
+		Transfer:       transfer,
+	}, nil
+}
+
+// ==============================================
+// DECLINE
+// ==============================================
+
+func (s *PaymentRequestService) Decline(ctx context.Context, userID int, id int64, req dto.DeclinePaymentRequestRequest) (*dto.PaymentRequestDTO, error) {
+	log.Printf("[PAYMENT_REQUEST] Decline - ID: %d, UserID: %d", id, userID)
+
+	pr, err := s.getByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only an addressed payer can decline; an open link is simply ignored
+	if !pr.IsAddressed() || pr.PayerUserID.Int32 != int32(userID) {
+		return nil, ErrNotPaymentRequestPayer
+	}
+	if err := s.checkActionable(pr, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.MarkDeclined(ctx, pr.ID, req.Reason); err != nil {
+		if errors.Is(err, repository.ErrPaymentRequestNotOpen) {
+			return nil, ErrPaymentRequestNotOpen
+		}
+		return nil, err
+	}
+
+	updated, err := s.repo.GetPaymentRequestByID(ctx, pr.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toDTOWithRequester(ctx, updated)
+}
+
+// ==============================================
+// HISTORY
+// ==============================================
+
+func (s *PaymentRequestService) List(ctx context.Context, userID int, req dto.ListPaymentRequestsRequest) (*dto.PaymentRequestListResponse, error) {
+	page, perPage := req.Page, req.PerPage
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+	offset := (page - 1) * perPage
+
+	direction := req.Direction
+	if direction == "" {
+		direction = "received"
+	}
+
+	// Bring stale 'open' rows up to date before listing
+	if _, err := s.repo.ExpireOverdue(ctx); err != nil {
+		log.Printf("[PAYMENT_REQUEST] Failed to expire overdue requests: %v", err)
+	}
+
+	var (
+		requests []models.PaymentRequest
+		err      error
+	)
+	if direction == "sent" {
+		requests, err = s.repo.ListSentPaymentRequests(ctx, userID, req.Status, perPage, offset)
+	} else {
+		requests, err = s.repo.ListReceivedPaymentRequests(ctx, userID, req.Status, perPage, offset)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	requesters := make(map[int32]*models.User)
+	items := make([]dto.PaymentRequestDTO, 0, len(requests))
+	for i := range requests {
+		pr := &requests[i]
+		requester, ok := requesters[pr.RequesterUserID]
+		if !ok {
+			requester, err = s.userRepo.GetUserByID(ctx, int(pr.RequesterUserID))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get requester: %w", err)
+			}
+			requesters[pr.RequesterUserID] = requester
+		}
+		items = append(items, *s.toDTO(pr, requester))
+	}
+
+	return &dto.PaymentRequestListResponse{
+		Direction:       direction,
+		PaymentRequests: items,
+		Page:            page,
+		PerPage:         perPage,
+	}, nil
+}
+
+// ExpireOverdue closes every open request past its expiry (for the worker)
+func (s *PaymentRequestService) ExpireOverdue(ctx context.Context) (int64, error) {
+	return s.repo.ExpireOverdue(ctx)
+}
+
+// ==============================================
+// HELPERS
+// ==============================================
+
+func paymentRequestIdempotencyKey(id int64) string {
+	return fmt.Sprintf("%spayreq:%d", InternalIdempotencyPrefix, id)
+}
+
+func (s *PaymentRequestService) getByID(ctx context.Context, id int64) (*models.PaymentRequest, error) {
+	pr, err := s.repo.GetPaymentRequestByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrPaymentRequestNotFound) {
+			return nil, ErrPaymentRequestNotFound
+		}
+		return nil, err
+	}
+	return s.expireIfDue(ctx, pr), nil
+}
+
+func (s *PaymentRequestService) getByToken(ctx context.Context, token string) (*models.PaymentRequest, error) {
+	pr, err := s.repo.GetPaymentRequestByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, repository.ErrPaymentRequestNotFound) {
+			return nil, ErrPaymentRequestNotFound
+		}
+		return nil, err
+	}
+	return s.expireIfDue(ctx, pr), nil
+}
+
+// expireIfDue reports overdue open requests as expired without waiting for the sweep
+func (s *PaymentRequestService) expireIfDue(ctx context.Context, pr *models.PaymentRequest) *models.PaymentRequest {
+	if pr.Status == models.PaymentRequestStatusOpen && pr.IsExpired() {
+		if _, err := s.repo.ExpireOverdue(ctx); err != nil {
+			log.Printf("[PAYMENT_REQUEST] Failed to expire overdue requests: %v", err)
+		}
+		pr.Status = models.PaymentRequestStatusExpired
+	}
+	return pr
+}
+
+func (s *PaymentRequestService) checkActionable(pr *models.PaymentRequest, userID int) error {
+	if pr.Status == models.PaymentRequestStatusExpired {
+		return ErrPaymentRequestExpired
+	}
+	if pr.Status != models.PaymentRequestStatusOpen {
+		return ErrPaymentRequestNotOpen
+	}
+	if !pr.CanBePaidBy(int32(userID)) {
+		if pr.RequesterUserID == int32(userID) {
+			return ErrSelfPaymentRequest
+		}
+		return ErrNotPaymentRequestPayer
+	}
+	return nil
+}
+
+func (s *PaymentRequestService) isParty(pr *models.PaymentRequest, userID int) bool {
+	uid := int32(userID)
+	return pr.RequesterUserID == uid ||
+		(pr.PayerUserID.Valid && pr.PayerUserID.Int32 == uid) ||
+		(pr.PaidByUserID.Valid && pr.PaidByUserID.Int32 == uid)
+}
+
+// resolveUser finds a user by @username or phone
+func (s *PaymentRequestService) resolveUser(ctx context.Context, identifier string) (*models.User, error) {
+	var (
+		user *models.User
+		err  error
+	)
+
+	if strings.HasPrefix(identifier, "@") {
+		user, err = s.userRepo.GetUserByUsername(ctx, strings.TrimPrefix(identifier, "@"))
+	} else if strings.HasPrefix(identifier, "+") || len(identifier) >= 10 {
+		user, err = s.userRepo.GetUserByPhone(ctx, identifier)
+	} else {
+		user, err = s.userRepo.GetUserByUsername(ctx, identifier)
+	}
+
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrPayerNotFound
+		}
+		return nil, fmt.Errorf("failed to resolve payer: %w", err)
+	}
+
+	return user, nil
+}
+
+func (s *PaymentRequestService) toDTOWithRequester(ctx context.Context, pr *models.PaymentRequest) (*dto.PaymentRequestDTO, error) {
+	requester, err := s.userRepo.GetUserByID(ctx, int(pr.RequesterUserID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get requester: %w", err)
+	}
+	return s.toDTO(pr, requester), nil
+}
+
+func (s *PaymentRequestService) toDTO(pr *models.PaymentRequest, requester *models.User) *dto.PaymentRequestDTO {
+	out := &dto.PaymentRequestDTO{
+		ID:        pr.ID,
+		Token:     pr.Token,
+		Link:      s.linkBaseURL + "/pay/" + pr.Token,
+		Requester: requester.Name,
+		Amount:    pr.Amount,
+		AmountNGN: float64(pr.Amount) / 100,
+		Currency:  pr.Currency,
+		Status:    pr.Status,
+		ExpiresAt: pr.ExpiresAt.Format(time.RFC3339),
+		CreatedAt: pr.CreatedAt.Format(time.RFC3339),
+	}
+
+	if requester.Username.Valid {
+		out.Requester = "@" + requester.Username.String
+	}
+	if pr.PayerIdentifier.Valid {
+		out.PayerIdentifier = &pr.PayerIdentifier.String
+	}
+	if pr.Description.Valid {
+		out.Description = &pr.Description.String
+	}
+	if pr.TransactionID.Valid {
+		out.TransactionID = &pr.TransactionID.Int64
+	}
+	if pr.DeclineReason.Valid {
+		out.DeclineReason = &pr.DeclineReason.String
+	}
+	if pr.PaidAt.Valid {
+		paidAt := pr.PaidAt.Time.Format(time.RFC3339)
+		out.PaidAt = &paidAt
+	}
+	if pr.DeclinedAt.Valid {
+		declinedAt := pr.DeclinedAt.Time.Format(time.RFC3339)
+		out.DeclinedAt = &declinedAt
+	}
+
+	return out
+}
@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ==============================================
+// FAKES
+// ==============================================
+
+// fakeTx applies its writes only on Commit
+type fakeTx struct {
+	pgx.Tx
+	onCommit []func()
+}
+
+func (tx *fakeTx) Commit(ctx context.Context) error {
+	for _, apply := range tx.onCommit {
+		apply()
+	}
+	tx.onCommit = nil
+	return nil
+}
+
+func (tx *fakeTx) Rollback(ctx context.Context) error {
+	tx.onCommit = nil
+	return nil
+}
+
+// fakeLedger keeps accounts and transactions in memory
+type fakeLedger struct {
+	WalletRepositoryInterface
+	accounts     map[int64]*models.Account
+	transactions map[string]*models.Transaction
+	nextTxnID    int64
+}
+
+func newFakeLedger(accounts ...*models.Account) *fakeLedger {
+	l := &fakeLedger{accounts: map[int64]*models.Account{}, transactions: map[string]*models.Transaction{}}
+	for _, a := range accounts {
+		l.accounts[a.ID] = a
+	}
+	return l
+}
+
+func (l *fakeLedger) BeginTx(ctx context.Context) (pgx.Tx, error) {
+	return &fakeTx{}, nil
+}
+
+func (l *fakeLedger) find(match func(*models.Account) bool) (*models.Account, error) {
+	for _, a := range l.accounts {
+		if match(a) {
+			copied := *a
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrAccountNotFound
+}
+
+func (l *fakeLedger) GetAccountByUserID(ctx context.Context, userID int) (*models.Account, error) {
+	return l.find(func(a *models.Account) bool { return a.UserID.Valid && a.UserID.Int32 == int32(userID) })
+}
+
+func (l *fakeLedger) GetAccountByIdentifier(ctx context.Context, identifier string) (*models.Account, error) {
+	return l.find(func(a *models.Account) bool { return a.AccountNumber.String == identifier })
+}
+
+func (l *fakeLedger) GetAccountByIDForUpdate(ctx context.Context, tx pgx.Tx, accountID int64) (*models.Account, error) {
+	return l.find(func(a *models.Account) bool { return a.ID == accountID })
+}
+
+func (l *fakeLedger) GetTransactionByIdempotencyKey(ctx context.Context, key string) (*models.Transaction, error) {
+	if txn, ok := l.transactions[key]; ok {
+		return txn, nil
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (l *fakeLedger) CreateTransaction(ctx context.Context, tx pgx.Tx, txn *models.Transaction) error {
+	l.nextTxnID++
+	txn.ID = l.nextTxnID
+	tx.(*fakeTx).onCommit = append(tx.(*fakeTx).onCommit, func() { l.transactions[txn.IdempotencyKey] = txn })
+	return nil
+}
+
+func (l *fakeLedger) CreatePosting(ctx context.Context, tx pgx.Tx, posting *models.Posting) error {
+	tx.(*fakeTx).onCommit = append(tx.(*fakeTx).onCommit, func() { l.accounts[posting.AccountID].Balance += posting.Amount })
+	return nil
+}
+
+// passAll lets every payment through the wallet's PIN, limit, risk, payee, step-up and audit checks
+type passAll struct{}
+
+func (passAll) ValidatePin(ctx context.Context, userID int, pin string) error {
+	return nil
+}
+
+func (passAll) CheckDebit(ctx context.Context, tx pgx.Tx, account *models.Account, amount int64) error {
+	return nil
+}
+
+func (passAll) CheckCredit(ctx context.Context, tx pgx.Tx, account *models.Account, amount int64) error {
+	return nil
+}
+
+func (passAll) Screen(ctx context.Context, tx pgx.Tx, check RiskCheck) (*models.RiskDecision, error) {
+	return &models.RiskDecision{Outcome: models.RiskOutcomeAllow}, nil
+}
+
+func (passAll) Record(ctx context.Context, tx pgx.Tx, decision *models.RiskDecision, transactionID int64) error {
+	return nil
+}
+
+func (passAll) Reject(ctx context.Context, decision *models.RiskDecision) error {
+	return ErrRiskBlocked
+}
+
+func (passAll) CheckPayee(ctx context.Context, userID int, accountID int64) error {
+	return nil
+}
+
+func (passAll) Require(ctx context.Context, userID int, op StepUpOperation, proof dto.StepUpProof) (bool, error) {
+	return false, nil
+}
+
+func (passAll) Challenge(ctx context.Context, userID int, op StepUpOperation) error {
+	return nil
+}
+
+func (passAll) WriteTx(ctx context.Context, tx pgx.Tx, entry *models.AuditLog) error {
+	return nil
+}
+
+func newPassingWalletService(ledger *fakeLedger) *WalletService {
+	return NewWalletService(ledger, passAll{}, passAll{}, passAll{}, passAll{}, passAll{}, passAll{})
+}
+
+// fakePaymentRequests keeps requests in memory; MarkPaid lands when the transfer commits
+type fakePaymentRequests struct {
+	PaymentRequestStore
+	requests map[int64]*models.PaymentRequest
+}
+
+func (f *fakePaymentRequests) get(id int64) (*models.PaymentRequest, error) {
+	pr, ok := f.requests[id]
+	if !ok {
+		return nil, repository.ErrPaymentRequestNotFound
+	}
+	copied := *pr
+	return &copied, nil
+}
+
+func (f *fakePaymentRequests) GetPaymentRequestByID(ctx context.Context, id int64) (*models.PaymentRequest, error) {
+	return f.get(id)
+}
+
+func (f *fakePaymentRequests) GetPaymentRequestForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*models.PaymentRequest, error) {
+	return f.get(id)
+}
+
+func (f *fakePaymentRequests) MarkPaid(ctx context.Context, tx pgx.Tx, id int64, paidByUserID int, transactionID int64) error {
+	if f.requests[id].Status != models.PaymentRequestStatusOpen {
+		return repository.ErrPaymentRequestNotOpen
+	}
+	tx.(*fakeTx).onCommit = append(tx.(*fakeTx).onCommit, func() {
+		pr := f.requests[id]
+		pr.Status = models.PaymentRequestStatusPaid
+		pr.PaidByUserID = pgtype.Int4{Int32: int32(paidByUserID), Valid: true}
+		pr.TransactionID = pgtype.Int8{Int64: transactionID, Valid: true}
+	})
+	return nil
+}
+
+func (f *fakePaymentRequests) ExpireOverdue(ctx context.Context) (int64, error) {
+	var n int64
+	for _, pr := range f.requests {
+		if pr.Status == models.PaymentRequestStatusOpen && time.Now().After(pr.ExpiresAt) {
+			pr.Status = models.PaymentRequestStatusExpired
+			n++
+		}
+	}
+	return n, nil
+}
+
+type fakeUsers struct {
+	UserLookup
+}
+
+func (fakeUsers) GetUserByID(ctx context.Context, userID int) (*models.User, error) {
+	return &models.User{ID: int32(userID), Name: "Test User"}, nil
+}
+
+// ==============================================
+// TESTS
+// ==============================================
+
+const (
+	requesterID = 1
+	payerID     = 2
+	otherID     = 3
+)
+
+type paymentRequestFixture struct {
+	ledger   *fakeLedger
+	requests *fakePaymentRequests
+	service  *PaymentRequestService
+	wallet   *WalletService
+}
+
+func newPaymentRequestFixture(pr *models.PaymentRequest) *paymentRequestFixture {
+	account := func(id int64, userID int32, number string, balance int64) *models.Account {
+		return &models.Account{
+			ID:            id,
+			UserID:        pgtype.Int4{Int32: userID, Valid: true},
+			AccountNumber: pgtype.Text{String: number, Valid: true},
+			Type:          models.AccountTypeUser,
+			Balance:       balance,
+		}
+	}
+	f := &paymentRequestFixture{
+		ledger: newFakeLedger(
+			account(10, requesterID, "0000000010", 0),
+			account(20, payerID, "0000000020", 1000000),
+			account(30, otherID, "0000000030", 1000000),
+		),
+		requests: &fakePaymentRequests{requests: map[int64]*models.PaymentRequest{pr.ID: pr}},
+	}
+	f.wallet = newPassingWalletService(f.ledger)
+	f.service = NewPaymentRequestService(f.requests, fakeUsers{}, f.wallet, "https://pay.example")
+	return f
+}
+
+func openRequest() *models.PaymentRequest {
+	return &models.PaymentRequest{
+		ID:              5,
+		RequesterUserID: requesterID,
+		PayerUserID:     pgtype.Int4{Int32: payerID, Valid: true},
+		Amount:          50000,
+		Currency:        "NGN",
+		Status:          models.PaymentRequestStatusOpen,
+		Token:           "tok",
+		ExpiresAt:       time.Now().Add(time.Hour),
+	}
+}
+
+func TestPay_SettlesOnce(t *testing.T) {
+	f := newPaymentRequestFixture(openRequest())
+	ctx := context.Background()
+
+	first, err := f.service.Pay(ctx, payerID, 5, dto.PayPaymentRequestRequest{Pin: "1234"})
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentRequestStatusPaid, first.PaymentRequest.Status)
+	assert.Equal(t, int64(50000), f.ledger.accounts[10].Balance)
+	assert.Equal(t, int64(950000), f.ledger.accounts[20].Balance)
+
+	// The payer retrying gets the original transfer back, and no money moves again
+	second, err := f.service.Pay(ctx, payerID, 5, dto.PayPaymentRequestRequest{Pin: "1234"})
+	require.NoError(t, err)
+	assert.Equal(t, first.Transfer.TransactionID, second.Transfer.TransactionID)
+	assert.Equal(t, int64(50000), f.ledger.accounts[10].Balance)
+	assert.Equal(t, int64(950000), f.ledger.accounts[20].Balance)
+	assert.Len(t, f.ledger.transactions, 1)
+}
+
+func TestPay_OpenLinkPaidOnlyOnce(t *testing.T) {
+	pr := openRequest()
+	pr.PayerUserID = pgtype.Int4{}
+	f := newPaymentRequestFixture(pr)
+	ctx := context.Background()
+
+	_, err := f.service.Pay(ctx, payerID, 5, dto.PayPaymentRequestRequest{Pin: "1234"})
+	require.NoError(t, err)
+
+	_, err = f.service.Pay(ctx, otherID, 5, dto.PayPaymentRequestRequest{Pin: "1234"})
+	assert.ErrorIs(t, err, ErrPaymentRequestNotOpen)
+	assert.Equal(t, int64(1000000), f.ledger.accounts[30].Balance)
+	assert.Equal(t, int64(50000), f.ledger.accounts[10].Balance)
+}
+
+func TestPay_RequestNotActionable(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*models.PaymentRequest)
+		want   error
+	}{
+		{name: "expired", change: func(pr *models.PaymentRequest) { pr.Status = models.PaymentRequestStatusExpired }, want: ErrPaymentRequestExpired},
+		{name: "past expiry, not yet swept", change: func(pr *models.PaymentRequest) { pr.ExpiresAt = time.Now().Add(-time.Minute) }, want: ErrPaymentRequestExpired},
+		{name: "declined", change: func(pr *models.PaymentRequest) { pr.Status = models.PaymentRequestStatusDeclined }, want: ErrPaymentRequestNotOpen},
+		{name: "paid by someone else", change: func(pr *models.PaymentRequest) {
+			pr.Status = models.PaymentRequestStatusPaid
+			pr.PaidByUserID = pgtype.Int4{Int32: otherID, Valid: true}
+		}, want: ErrPaymentRequestNotOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := openRequest()
+			tt.change(pr)
+			f := newPaymentRequestFixture(pr)
+
+			_, err := f.service.Pay(context.Background(), payerID, 5, dto.PayPaymentRequestRequest{Pin: "1234"})
+			assert.ErrorIs(t, err, tt.want)
+			assert.Empty(t, f.ledger.transactions)
+			assert.Equal(t, int64(1000000), f.ledger.accounts[20].Balance)
+		})
+	}
+}
+
+// A request declined or expired after it was loaded is caught under the lock, and the transfer rolls back
+func TestPay_StateChangesBeforeSettle(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*models.PaymentRequest)
+		want   error
+	}{
+		{name: "declined", change: func(pr *models.PaymentRequest) { pr.Status = models.PaymentRequestStatusDeclined }, want: ErrPaymentRequestNotOpen},
+		{name: "expired", change: func(pr *models.PaymentRequest) { pr.ExpiresAt = time.Now().Add(-time.Second) }, want: ErrPaymentRequestExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newPaymentRequestFixture(openRequest())
+			pr, err := f.service.getByID(context.Background(), 5)
+			require.NoError(t, err)
+
+			tt.change(f.requests.requests[5])
+
+			_, err = f.service.pay(context.Background(), payerID, pr, dto.PayPaymentRequestRequest{Pin: "1234"})
+			assert.ErrorIs(t, err, tt.want)
+			assert.Empty(t, f.ledger.transactions)
+			assert.Equal(t, int64(1000000), f.ledger.accounts[20].Balance)
+			assert.Equal(t, int64(0), f.ledger.accounts[10].Balance)
+		})
+	}
+}
+
+// Clients can't use the internal key namespace, so they can't pre-empt or replay a payment request's transfer
+func TestTransfer_RejectsInternalIdempotencyKey(t *testing.T) {
+	f := newPaymentRequestFixture(openRequest())
+
+	_, err := f.wallet.Transfer(context.Background(), payerID, dto.TransferRequest{
+		ToIdentifier:   "0000000010",
+		Amount:         50000,
+		Pin:            "1234",
+		IdempotencyKey: paymentRequestIdempotencyKey(5),
+	})
+	assert.ErrorIs(t, err, ErrReservedIdempotencyKey)
+	assert.Empty(t, f.ledger.transactions)
+
+	// The request can still be paid
+	_, err = f.service.Pay(context.Background(), payerID, 5, dto.PayPaymentRequestRequest{Pin: "1234"})
+	require.NoError(t, err)
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/pkg/emvco"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// ==============================================
+// QR CONFIG
+// ==============================================
+
+const (
+	// QRSchemeGUID identifies debank accounts inside the EMVCo merchant account template
+	QRSchemeGUID   = "NG.DEBANK"
+	QRMerchantCity = "LAGOS"
+	QRImageSize    = 256 // PNG width/height in pixels
+
+	QRTypeStatic  = "static"
+	QRTypeDynamic = "dynamic"
+)
+
+// ==============================================
+// SERVICE ERRORS
+// ==============================================
+
+var (
+	ErrInvalidQRPayload    = errors.New("invalid qr payload")
+	ErrUnsupportedQRScheme = errors.New("qr code is not a debank payment code")
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+)
+
+// ==============================================
+// SERVICE
+// ==============================================
+
+type QRService struct {
+	walletRepo WalletRepositoryInterface
+}
+
+func NewQRService(walletRepo WalletRepositoryInterface) *QRService {
+	return &QRService{walletRepo: walletRepo}
+}
+
+// ==============================================
+// GENERATE
+// ==============================================
+
+// Generate builds a QR payload and PNG for receiving money into the user's account
+func (s *QRService) Generate(ctx context.Context, userID int, req dto.GenerateQRRequest) (*dto.QRCodeResponse, []byte, error) {
+	if req.Amount < 0 {
+		return nil, nil, ErrInvalidAmount
+	}
+	if req.Amount > MaxTransactionAmount {
+		return nil, nil, ErrAmountTooLarge
+	}
+
+	account, err := s.walletRepo.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		if isAccountNotFoundError(err) {
+			return nil, nil, ErrAccountNotFound
+		}
+		return nil, nil, err
+	}
+	if !account.AccountNumber.Valid {
+		return nil, nil, ErrAccountNotFound
+	}
+
+	payload, err := emvco.Encode(emvco.Payload{
+		Dynamic:       req.Amount > 0,
+		GUID:          QRSchemeGUID,
+		AccountNumber: account.AccountNumber.String,
+		MerchantName:  account.Name,
+		MerchantCity:  QRMerchantCity,
+		Currency:      emvco.CurrencyNGN,
+		CountryCode:   emvco.CountryNigeria,
+		Amount:        req.Amount,
+		Reference:     req.Reference,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidQRPayload, err)
+	}
+
+	png, err := qrcode.Encode(payload, qrcode.Medium, QRImageSize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to render qr code: %w", err)
+	}
+
+	log.Printf("[QR] Generated - UserID: %d, Type: %s, Amount: %d kobo", userID, qrType(req.Amount > 0), req.Amount)
+
+	return &dto.QRCodeResponse{
+		Payload:       payload,
+		PNGBase64:     base64.StdEncoding.EncodeToString(png),
+		Type:          qrType(req.Amount > 0),
+		AccountNumber: account.AccountNumber.String,
+		AccountName:   account.Name,
+		Amount:        req.Amount,
+		AmountNGN:     float64(req.Amount) / 100,
+		Reference:     req.Reference,
+	}, png, nil
+}
+
+// ==============================================
+// DECODE
+// ==============================================
+
+// Decode validates a scanned payload and resolves the account it pays into
+func (s *QRService) Decode(ctx context.Context, req dto.DecodeQRRequest) (*dto.DecodeQRResponse, error) {
+	payload, err := emvco.Decode(req.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidQRPayload, err)
+	}
+
+	if payload.GUID != QRSchemeGUID {
+		return nil, ErrUnsupportedQRScheme
+	}
+	if payload.Currency != "" && payload.Currency != emvco.CurrencyNGN {
+		return nil, ErrUnsupportedCurrency
+	}
+	if payload.Amount > MaxTransactionAmount {
+		return nil, ErrAmountTooLarge
+	}
+
+	// Never trust the name embedded in the code - show the account holder's real (masked) name
+	account, err := s.walletRepo.GetAccountByIdentifier(ctx, payload.AccountNumber)
+	if err != nil {
+		if isAccountNotFoundError(err) {
+			return nil, ErrRecipientNotFound
+		}
+		return nil, err
+	}
+	if account.IsSystemAccount() || !account.AccountNumber.Valid || account.AccountNumber.String != payload.AccountNumber {
+		return nil, ErrRecipientNotFound
+	}
+
+	// Masked like name enquiry - anyone can build a payload for any account number
+	return &dto.DecodeQRResponse{
+		Type:          qrType(payload.Dynamic),
+		AccountNumber: maskAccountNumber(payload.AccountNumber),
+		AccountName:   maskName(account.Name),
+		Amount:        payload.Amount,
+		AmountNGN:     float64(payload.Amount) / 100,
+		Reference:     payload.Reference,
+		Transfer: dto.TransferRequest{
+			ToIdentifier: payload.AccountNumber,
+			Amount:       payload.Amount,
+			Description:  payload.Reference,
+		},
+	}, nil
+}
+
+func qrType(dynamic bool) string {
+	if dynamic {
+		return QRTypeDynamic
+	}
+	return QRTypeStatic
+}
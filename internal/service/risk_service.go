@@ -0,0 +1,457 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/clientinfo"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// RULE PARAMETERS
+// ==============================================
+
+// Parameters each rule understands; updates may only set these keys
+var riskRuleParams = map[string][]string{
+	models.RiskRuleVelocity:                  {"max_count", "window_minutes"},
+	models.RiskRuleNewDeviceLargeAmount:      {"min_amount", "min_device_age_hours"},
+	models.RiskRuleNewBeneficiaryLargeAmount: {"min_amount"},
+	models.RiskRuleRecentPasswordReset:       {"min_amount", "window_hours"},
+}
+
+// Higher wins when several rules fire
+var riskOutcomeSeverity = map[string]int{
+	models.RiskOutcomeAllow:     0,
+	models.RiskOutcomeChallenge: 1,
+	models.RiskOutcomeBlock:     2,
+}
+
+// ==============================================
+// SERVICE ERRORS
+// ==============================================
+
+var (
+	ErrRiskBlocked           = errors.New("transaction declined by risk checks")
+	ErrRiskChallengeRequired = errors.New("additional verification required for this transaction")
+	ErrRiskRuleNotFound      = errors.New("risk rule not found")
+	ErrRiskDecisionNotFound  = errors.New("risk decision not found")
+	ErrRiskRuleParams        = errors.New("invalid risk rule parameters")
+)
+
+// ==============================================
+// SERVICE
+// ==============================================
+
+// RiskCheck describes an outgoing payment about to be posted
+type RiskCheck struct {
+	UserID         int
+	Account        *models.Account // Locked FOR UPDATE by the caller
+	Operation      string
+	Amount         int64
+	Counterparty   *models.Account // nil for withdrawals
+	IdempotencyKey string
+}
+
+// RiskStore holds rules, the history they are evaluated against and their decisions (implemented by repository.RiskRepository)
+type RiskStore interface {
+	ListRules(ctx context.Context) ([]models.RiskRule, error)
+	GetRule(ctx context.Context, code string) (*models.RiskRule, error)
+	UpdateRule(ctx context.Context, rule *models.RiskRule, audit *models.AuditLog) error
+	CountDebitsSince(ctx context.Context, tx pgx.Tx, accountID int64, since time.Time) (int, error)
+	HasPriorTransfer(ctx context.Context, tx pgx.Tx, fromAccountID, toAccountID int64) (bool, error)
+	GetPasswordResetAt(ctx context.Context, tx pgx.Tx, userID int) (pgtype.Timestamptz, error)
+	GetDeviceFirstSeen(ctx context.Context, userID int, deviceID string) (pgtype.Timestamptz, error)
+	CreateDecision(ctx context.Context, d *models.RiskDecision) error
+	CreateDecisionTx(ctx context.Context, tx pgx.Tx, d *models.RiskDecision) error
+	GetDecision(ctx context.Context, id int64) (*models.RiskDecision, error)
+	ListDecisions(ctx context.Context, userID int, outcome string, limit, offset int) ([]models.RiskDecision, error)
+}
+
+type RiskService struct {
+	repo RiskStore
+}
+
+func NewRiskService(repo RiskStore) *RiskService {
+	return &RiskService{repo: repo}
+}
+
+// ==============================================
+// SCREENING
+// ==============================================
+
+// Screen runs every enabled rule against a payment inside its ledger transaction
+// It only evaluates; the caller records the decision with Record or Reject
+func (s *RiskService) Screen(ctx context.Context, tx pgx.Tx, check RiskCheck) (*models.RiskDecision, error) {
+	rules, err := s.repo.ListRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	client := clientinfo.FromContext(ctx)
+	decision := &models.RiskDecision{
+		UserID:         int32(check.UserID),
+		AccountID:      check.Account.ID,
+		Operation:      check.Operation,
+		Amount:         check.Amount,
+		IdempotencyKey: optionalText(check.IdempotencyKey),
+		DeviceID:       optionalText(client.DeviceID),
+		IPAddress:      optionalText(client.IPAddress),
+		UserAgent:      optionalText(client.UserAgent),
+		Outcome:        models.RiskOutcomeAllow,
+	}
+	if check.Counterparty != nil {
+		decision.CounterpartyAccountID = pgtype.Int8{Int64: check.Counterparty.ID, Valid: true}
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.Enabled {
+			continue
+		}
+
+		fired, detail, err := s.evaluate(ctx, tx, rule, check, client)
+		if err != nil {
+			return nil, fmt.Errorf("risk rule %s: %w", rule.Code, err)
+		}
+		if !fired {
+			continue
+		}
+
+		decision.FiredRules = append(decision.FiredRules, models.RiskRuleHit{
+			Code:   rule.Code,
+			Action: rule.Action,
+			Detail: detail,
+		})
+		if riskOutcomeSeverity[rule.Action] > riskOutcomeSeverity[decision.Outcome] {
+			decision.Outcome = rule.Action
+		}
+	}
+
+	return decision, nil
+}
+
+// Record stores an allowed decision in the same transaction as the payment it let through
+func (s *RiskService) Record(ctx context.Context, tx pgx.Tx, decision *models.RiskDecision, transactionID int64) error {
+	decision.TransactionID = pgtype.Int8{Int64: transactionID, Valid: true}
+	return s.repo.CreateDecisionTx(ctx, tx, decision)
+}
+
+// Reject stores a challenge or block decision and returns the error to surface to the user
+// Call it after rolling back the ledger transaction so the account locks are released
+func (s *RiskService) Reject(ctx context.Context, decision *models.RiskDecision) error {
+	outcomeErr := ErrRiskBlocked
+	if decision.Outcome == models.RiskOutcomeChallenge {
+		outcomeErr = ErrRiskChallengeRequired
+	}
+
+	if err := s.repo.CreateDecision(ctx, decision); err != nil {
+		// Still refuse the payment - losing the record is better than letting it through
+		log.Printf("[RISK] Failed to record decision - UserID: %d, Outcome: %s, Error: %v",
+			decision.UserID, decision.Outcome, err)
+		return outcomeErr
+	}
+
+	log.Printf("[RISK] %s - DecisionID: %d, UserID: %d, Operation: %s, Amount: %d kobo, Rules: %d",
+		decision.Outcome, decision.ID, decision.UserID, decision.Operation, decision.Amount, len(decision.FiredRules))
+
+	// Only the reference is exposed; which rules fired stays internal
+	return fmt.Errorf("%w (reference %d)", outcomeErr, decision.ID)
+}
+
+func (s *RiskService) evaluate(ctx context.Context, tx pgx.Tx, rule *models.RiskRule, check RiskCheck, client clientinfo.Info) (bool, string, error) {
+	switch rule.Code {
+	case models.RiskRuleVelocity:
+		return s.checkVelocity(ctx, tx, rule, check)
+	case models.RiskRuleNewDeviceLargeAmount:
+		return s.checkNewDevice(ctx, rule, check, client)
+	case models.RiskRuleNewBeneficiaryLargeAmount:
+		return s.checkNewBeneficiary(ctx, tx, rule, check)
+	case models.RiskRuleRecentPasswordReset:
+		return s.checkRecentPasswordReset(ctx, tx, rule, check)
+	default:
+		log.Printf("[RISK] Skipping unknown rule %q", rule.Code)
+		return false, "", nil
+	}
+}
+
+// checkVelocity fires when this payment would exceed max_count debits in window_minutes
+func (s *RiskService) checkVelocity(ctx context.Context, tx pgx.Tx, rule *models.RiskRule, check RiskCheck) (bool, string, error) {
+	maxCount := rule.Param("max_count")
+	window := time.Duration(rule.Param("window_minutes")) * time.Minute
+	if maxCount <= 0 || window <= 0 {
+		return false, "", nil
+	}
+
+	count, err := s.repo.CountDebitsSince(ctx, tx, check.Account.ID, time.Now().Add(-window))
+	if err != nil {
+		return false, "", err
+	}
+	if int64(count) < maxCount {
+		return false, "", nil
+	}
+
+	return true, fmt.Sprintf("%d debits in the last %s (max %d)", count, window, maxCount), nil
+}
+
+// checkNewDevice fires for large payments from a device with no allowed payment older than min_device_age_hours
+func (s *RiskService) checkNewDevice(ctx context.Context, rule *models.RiskRule, check RiskCheck, client clientinfo.Info) (bool, string, error) {
+	if check.Amount < rule.Param("min_amount") {
+		return false, "", nil
+	}
+	if client.DeviceID == "" {
+		return true, "no device ID sent", nil
+	}
+
+	firstSeen, err := s.repo.GetDeviceFirstSeen(ctx, check.UserID, client.DeviceID)
+	if err != nil {
+		return false, "", err
+	}
+	if !firstSeen.Valid {
+		return true, "first payment from this device", nil
+	}
+
+	minAge := time.Duration(rule.Param("min_device_age_hours")) * time.Hour
+	if age := time.Since(firstSeen.Time); age < minAge {
+		return true, fmt.Sprintf("device first used %s ago", age.Round(time.Minute)), nil
+	}
+
+	return false, "", nil
+}
+
+// checkNewBeneficiary fires for a transfer above min_amount to someone the sender has never paid
+func (s *RiskService) checkNewBeneficiary(ctx context.Context, tx pgx.Tx, rule *models.RiskRule, check RiskCheck) (bool, string, error) {
+	if check.Counterparty == nil || check.Amount < rule.Param("min_amount") {
+		return false, "", nil
+	}
+
+	paidBefore, err := s.repo.HasPriorTransfer(ctx, tx, check.Account.ID, check.Counterparty.ID)
+	if err != nil {
+		return false, "", err
+	}
+	if paidBefore {
+		return false, "", nil
+	}
+
+	return true, "first transfer to this recipient", nil
+}
+
+// checkRecentPasswordReset fires for payments above min_amount within window_hours of a password reset
+func (s *RiskService) checkRecentPasswordReset(ctx context.Context, tx pgx.Tx, rule *models.RiskRule, check RiskCheck) (bool, string, error) {
+	if check.Amount < rule.Param("min_amount") {
+		return false, "", nil
+	}
+
+	resetAt, err := s.repo.GetPasswordResetAt(ctx, tx, check.UserID)
+	if err != nil {
+		return false, "", err
+	}
+	if !resetAt.Valid {
+		return false, "", nil
+	}
+
+	window := time.Duration(rule.Param("window_hours")) * time.Hour
+	if since := time.Since(resetAt.Time); since < window {
+		return true, fmt.Sprintf("password reset %s ago", since.Round(time.Minute)), nil
+	}
+
+	return false, "", nil
+}
+
+// ==============================================
+// ADMIN: RULES
+// ==============================================
+
+// ListRules returns every rule with its current configuration
+func (s *RiskService) ListRules(ctx context.Context) (*dto.RiskRulesResponse, error) {
+	rules, err := s.repo.ListRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.RiskRulesResponse{Rules: make([]dto.RiskRuleDTO, len(rules))}
+	for i := range rules {
+		resp.Rules[i] = riskRuleToDTO(&rules[i])
+	}
+	return resp, nil
+}
+
+// UpdateRule changes a rule's enabled flag, action or thresholds
+// Params are merged into the existing ones, so a single threshold can be changed on its own
+func (s *RiskService) UpdateRule(ctx context.Context, adminID int, code string, req dto.UpdateRiskRuleRequest) (*dto.RiskRuleDTO, error) {
+	rule, err := s.repo.GetRule(ctx, code)
+	if err != nil {
+		if errors.Is(err, repository.ErrRiskRuleNotFound) {
+			return nil, ErrRiskRuleNotFound
+		}
+		return nil, err
+	}
+
+	allowed := riskRuleParams[rule.Code]
+	for name, value := range req.Params {
+		if !containsString(allowed, name) {
+			return nil, fmt.Errorf("%w: %s does not take %q", ErrRiskRuleParams, rule.Code, name)
+		}
+		if value < 0 {
+			return nil, fmt.Errorf("%w: %s must not be negative", ErrRiskRuleParams, name)
+		}
+	}
+
+	before := riskRuleToDTO(rule)
+
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	if req.Action != "" {
+		rule.Action = req.Action
+	}
+	if rule.Params == nil {
+		rule.Params = map[string]int64{}
+	}
+	for name, value := range req.Params {
+		rule.Params[name] = value
+	}
+	rule.UpdatedBy = pgtype.Int4{Int32: int32(adminID), Valid: true}
+
+	metadata, err := json.Marshal(map[string]interface{}{
+		"code":   rule.Code,
+		"before": before,
+		"after":  riskRuleToDTO(rule),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit metadata: %w", err)
+	}
+	audit := &models.AuditLog{
+		UserID:     pgtype.Int4{Int32: int32(adminID), Valid: true},
+		Action:     models.AuditActionRiskRuleUpdated,
+		EntityType: pgtype.Text{String: "risk_rule", Valid: true},
+		Metadata:   pgtype.Text{String: string(metadata), Valid: true},
+	}
+
+	if err := s.repo.UpdateRule(ctx, rule, audit); err != nil {
+		if errors.Is(err, repository.ErrRiskRuleNotFound) {
+			return nil, ErrRiskRuleNotFound
+		}
+		return nil, err
+	}
+
+	log.Printf("[RISK] Rule updated - Code: %s, Enabled: %t, Action: %s, By: %d", rule.Code, rule.Enabled, rule.Action, adminID)
+
+	out := riskRuleToDTO(rule)
+	return &out, nil
+}
+
+// ==============================================
+// ADMIN: DECISIONS
+// ==============================================
+
+// ListDecisions returns screening decisions for analysts, newest first
+func (s *RiskService) ListDecisions(ctx context.Context, req dto.ListRiskDecisionsRequest) (*dto.RiskDecisionListResponse, error) {
+	page, perPage := req.Page, req.PerPage
+	if page == 0 {
+		page = 1
+	}
+	if perPage == 0 {
+		perPage = 20
+	}
+
+	decisions, err := s.repo.ListDecisions(ctx, req.UserID, req.Outcome, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]dto.RiskDecisionDTO, len(decisions))
+	for i := range decisions {
+		out[i] = riskDecisionToDTO(&decisions[i])
+	}
+
+	return &dto.RiskDecisionListResponse{
+		Decisions: out,
+		Page:      page,
+		PerPage:   perPage,
+	}, nil
+}
+
+// GetDecision returns a single decision with the rules that fired
+func (s *RiskService) GetDecision(ctx context.Context, id int64) (*dto.RiskDecisionDTO, error) {
+	decision, err := s.repo.GetDecision(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrRiskDecisionNotFound) {
+			return nil, ErrRiskDecisionNotFound
+		}
+		return nil, err
+	}
+
+	out := riskDecisionToDTO(decision)
+	return &out, nil
+}
+
+// ==============================================
+// HELPERS
+// ==============================================
+
+func riskRuleToDTO(r *models.RiskRule) dto.RiskRuleDTO {
+	params := make(map[string]int64, len(r.Params))
+	for name, value := range r.Params {
+		params[name] = value
+	}
+
+	return dto.RiskRuleDTO{
+		Code:        r.Code,
+		Description: r.Description,
+		Enabled:     r.Enabled,
+		Action:      r.Action,
+		Params:      params,
+		UpdatedAt:   r.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func riskDecisionToDTO(d *models.RiskDecision) dto.RiskDecisionDTO {
+	out := dto.RiskDecisionDTO{
+		ID:         d.ID,
+		UserID:     int(d.UserID),
+		AccountID:  d.AccountID,
+		Operation:  d.Operation,
+		Amount:     d.Amount,
+		AmountNGN:  float64(d.Amount) / 100,
+		DeviceID:   d.DeviceID.String,
+		IPAddress:  d.IPAddress.String,
+		UserAgent:  d.UserAgent.String,
+		Outcome:    d.Outcome,
+		FiredRules: make([]dto.RiskRuleHitDTO, len(d.FiredRules)),
+		CreatedAt:  d.CreatedAt.Format(time.RFC3339),
+	}
+	for i, hit := range d.FiredRules {
+		out.FiredRules[i] = dto.RiskRuleHitDTO{Code: hit.Code, Action: hit.Action, Detail: hit.Detail}
+	}
+	if d.CounterpartyAccountID.Valid {
+		id := d.CounterpartyAccountID.Int64
+		out.CounterpartyAccountID = &id
+	}
+	if d.TransactionID.Valid {
+		id := d.TransactionID.Int64
+		out.TransactionID = &id
+	}
+	return out
+}
+
+func optionalText(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
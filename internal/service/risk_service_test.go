@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/clientinfo"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRiskStore answers the rule queries from fixed history
+type fakeRiskStore struct {
+	RiskStore
+	rules      []models.RiskRule
+	debits     int
+	deviceSeen map[string]time.Time
+	paidBefore bool
+	resetAt    time.Time
+}
+
+func (f *fakeRiskStore) ListRules(ctx context.Context) ([]models.RiskRule, error) {
+	return f.rules, nil
+}
+
+func (f *fakeRiskStore) CountDebitsSince(ctx context.Context, tx pgx.Tx, accountID int64, since time.Time) (int, error) {
+	return f.debits, nil
+}
+
+func (f *fakeRiskStore) HasPriorTransfer(ctx context.Context, tx pgx.Tx, fromAccountID, toAccountID int64) (bool, error) {
+	return f.paidBefore, nil
+}
+
+func (f *fakeRiskStore) GetPasswordResetAt(ctx context.Context, tx pgx.Tx, userID int) (pgtype.Timestamptz, error) {
+	return pgtype.Timestamptz{Time: f.resetAt, Valid: !f.resetAt.IsZero()}, nil
+}
+
+func (f *fakeRiskStore) GetDeviceFirstSeen(ctx context.Context, userID int, deviceID string) (pgtype.Timestamptz, error) {
+	seen, ok := f.deviceSeen[deviceID]
+	return pgtype.Timestamptz{Time: seen, Valid: ok}, nil
+}
+
+func riskRule(code, action string, params map[string]int64) models.RiskRule {
+	return models.RiskRule{Code: code, Enabled: true, Action: action, Params: params}
+}
+
+var (
+	velocityRule       = riskRule(models.RiskRuleVelocity, models.RiskOutcomeChallenge, map[string]int64{"max_count": 3, "window_minutes": 10})
+	newDeviceRule      = riskRule(models.RiskRuleNewDeviceLargeAmount, models.RiskOutcomeChallenge, map[string]int64{"min_amount": 100_000, "min_device_age_hours": 24})
+	newBeneficiaryRule = riskRule(models.RiskRuleNewBeneficiaryLargeAmount, models.RiskOutcomeChallenge, map[string]int64{"min_amount": 100_000})
+	passwordResetRule  = riskRule(models.RiskRuleRecentPasswordReset, models.RiskOutcomeBlock, map[string]int64{"min_amount": 100_000, "window_hours": 24})
+)
+
+func riskCheck(amount int64, toAccount bool) RiskCheck {
+	check := RiskCheck{
+		UserID:    7,
+		Account:   &models.Account{ID: 1},
+		Operation: models.RiskOperationWithdraw,
+		Amount:    amount,
+	}
+	if toAccount {
+		check.Operation = models.RiskOperationTransfer
+		check.Counterparty = &models.Account{ID: 2}
+	}
+	return check
+}
+
+func withDevice(deviceID string) context.Context {
+	return clientinfo.NewContext(context.Background(), clientinfo.Info{DeviceID: deviceID})
+}
+
+func TestScreen_Rules(t *testing.T) {
+	ago := func(d time.Duration) time.Time { return time.Now().Add(-d) }
+
+	tests := []struct {
+		name  string
+		store fakeRiskStore
+		ctx   context.Context
+		check RiskCheck
+		fired bool
+	}{
+		{name: "velocity under max", store: fakeRiskStore{rules: []models.RiskRule{velocityRule}, debits: 2}, check: riskCheck(1, false)},
+		{name: "velocity at max", store: fakeRiskStore{rules: []models.RiskRule{velocityRule}, debits: 3}, check: riskCheck(1, false), fired: true},
+		{name: "velocity unconfigured", store: fakeRiskStore{rules: []models.RiskRule{riskRule(models.RiskRuleVelocity, models.RiskOutcomeBlock, nil)}, debits: 100}, check: riskCheck(1, false)},
+
+		{name: "new device below min amount", store: fakeRiskStore{rules: []models.RiskRule{newDeviceRule}}, ctx: withDevice("d1"), check: riskCheck(99_999, false)},
+		{name: "new device never used", store: fakeRiskStore{rules: []models.RiskRule{newDeviceRule}}, ctx: withDevice("d1"), check: riskCheck(100_000, false), fired: true},
+		{name: "new device without device ID", store: fakeRiskStore{rules: []models.RiskRule{newDeviceRule}}, check: riskCheck(100_000, false), fired: true},
+		{name: "new device used recently", store: fakeRiskStore{rules: []models.RiskRule{newDeviceRule}, deviceSeen: map[string]time.Time{"d1": ago(23 * time.Hour)}}, ctx: withDevice("d1"), check: riskCheck(100_000, false), fired: true},
+		{name: "known device", store: fakeRiskStore{rules: []models.RiskRule{newDeviceRule}, deviceSeen: map[string]time.Time{"d1": ago(25 * time.Hour)}}, ctx: withDevice("d1"), check: riskCheck(100_000, false)},
+
+		{name: "new beneficiary below min amount", store: fakeRiskStore{rules: []models.RiskRule{newBeneficiaryRule}}, check: riskCheck(99_999, true)},
+		{name: "new beneficiary", store: fakeRiskStore{rules: []models.RiskRule{newBeneficiaryRule}}, check: riskCheck(100_000, true), fired: true},
+		{name: "beneficiary paid before", store: fakeRiskStore{rules: []models.RiskRule{newBeneficiaryRule}, paidBefore: true}, check: riskCheck(100_000, true)},
+		{name: "new beneficiary skips withdrawals", store: fakeRiskStore{rules: []models.RiskRule{newBeneficiaryRule}}, check: riskCheck(100_000, false)},
+
+		{name: "password never reset", store: fakeRiskStore{rules: []models.RiskRule{passwordResetRule}}, check: riskCheck(100_000, false)},
+		{name: "recent password reset", store: fakeRiskStore{rules: []models.RiskRule{passwordResetRule}, resetAt: ago(time.Hour)}, check: riskCheck(100_000, false), fired: true},
+		{name: "recent password reset below min amount", store: fakeRiskStore{rules: []models.RiskRule{passwordResetRule}, resetAt: ago(time.Hour)}, check: riskCheck(99_999, false)},
+		{name: "password reset outside window", store: fakeRiskStore{rules: []models.RiskRule{passwordResetRule}, resetAt: ago(25 * time.Hour)}, check: riskCheck(100_000, false)},
+
+		{name: "disabled rule", store: fakeRiskStore{rules: []models.RiskRule{{Code: models.RiskRuleVelocity, Action: models.RiskOutcomeBlock, Params: velocityRule.Params}}, debits: 100}, check: riskCheck(1, false)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			store := tt.store
+			s := NewRiskService(&store)
+
+			decision, err := s.Screen(ctx, nil, tt.check)
+			require.NoError(t, err)
+
+			if !tt.fired {
+				assert.Empty(t, decision.FiredRules)
+				assert.Equal(t, models.RiskOutcomeAllow, decision.Outcome)
+				return
+			}
+			require.Len(t, decision.FiredRules, 1)
+			assert.Equal(t, store.rules[0].Code, decision.FiredRules[0].Code)
+			assert.Equal(t, store.rules[0].Action, decision.Outcome)
+		})
+	}
+}
+
+func TestScreen_WorstOutcomeWins(t *testing.T) {
+	challenge := func(code string) models.RiskRule {
+		return riskRule(code, models.RiskOutcomeChallenge, map[string]int64{"min_amount": 1})
+	}
+	block := func(code string) models.RiskRule {
+		return riskRule(code, models.RiskOutcomeBlock, map[string]int64{"min_amount": 1})
+	}
+	resetRule := func(action string) models.RiskRule {
+		return riskRule(models.RiskRuleRecentPasswordReset, action, map[string]int64{"min_amount": 1, "window_hours": 24})
+	}
+
+	tests := []struct {
+		name  string
+		rules []models.RiskRule
+		fired int
+		want  string
+	}{
+		{name: "nothing fires", rules: nil, want: models.RiskOutcomeAllow},
+		{name: "one challenge", rules: []models.RiskRule{challenge(models.RiskRuleNewBeneficiaryLargeAmount)}, fired: 1, want: models.RiskOutcomeChallenge},
+		{name: "two challenges", rules: []models.RiskRule{challenge(models.RiskRuleNewBeneficiaryLargeAmount), challenge(models.RiskRuleNewDeviceLargeAmount)}, fired: 2, want: models.RiskOutcomeChallenge},
+		{name: "challenge then block", rules: []models.RiskRule{challenge(models.RiskRuleNewBeneficiaryLargeAmount), resetRule(models.RiskOutcomeBlock)}, fired: 2, want: models.RiskOutcomeBlock},
+		{name: "block then challenge", rules: []models.RiskRule{resetRule(models.RiskOutcomeBlock), challenge(models.RiskRuleNewDeviceLargeAmount)}, fired: 2, want: models.RiskOutcomeBlock},
+		{name: "block beside a rule that does not fire", rules: []models.RiskRule{block(models.RiskRuleNewBeneficiaryLargeAmount), riskRule(models.RiskRuleVelocity, models.RiskOutcomeChallenge, map[string]int64{"max_count": 50, "window_minutes": 10})}, fired: 1, want: models.RiskOutcomeBlock},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewRiskService(&fakeRiskStore{rules: tt.rules, resetAt: time.Now().Add(-time.Hour)})
+
+			decision, err := s.Screen(context.Background(), nil, riskCheck(100_000, true))
+			require.NoError(t, err)
+			assert.Len(t, decision.FiredRules, tt.fired)
+			assert.Equal(t, tt.want, decision.Outcome)
+			assert.Equal(t, tt.want == models.RiskOutcomeAllow, decision.IsAllowed())
+		})
+	}
+}
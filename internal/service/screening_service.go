@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/repository"
+	"github.com/Brownie44l1/debank/internal/sanctions"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// BUSINESS RULES (Constants)
+// ==============================================
+
+const (
+	MaxScreeningHitsPerName = 10 // Strongest matches kept per screened name
+)
+
+// ==============================================
+// SERVICE ERRORS
+// ==============================================
+
+var (
+	ErrScreeningHitNotFound = errors.New("screening hit not found")
+	ErrScreeningHitReviewed = errors.New("screening hit has already been reviewed")
+	ErrScreeningHold        = errors.New("on hold pending sanctions screening review")
+	ErrBeneficiaryHeld      = errors.New("this recipient is on hold pending review")
+	ErrWatchlistReload      = errors.New("failed to reload watchlists")
+)
+
+// ==============================================
+// SERVICE
+// ==============================================
+
+// ScreeningStore persists hits and the holds they place (implemented by repository.ScreeningRepository)
+type ScreeningStore interface {
+	RecordHits(ctx context.Context, subjectType string, subjectID int64, hits []models.ScreeningHit) (int, error)
+	HasOpenHits(ctx context.Context, subjectType string, subjectID int64) (bool, error)
+	IsPayeeHeld(ctx context.Context, userID int, accountID int64) (bool, error)
+	GetHit(ctx context.Context, id int64) (*models.ScreeningHit, error)
+	ListHits(ctx context.Context, status, subjectType string, limit, offset int) ([]models.ScreeningHit, error)
+	ReviewHit(ctx context.Context, id int64, reviewerID int, status, note string, audit *models.AuditLog) (*models.ScreeningHit, error)
+}
+
+// ScreeningService checks names against locally loaded sanctions lists and holds potential matches for review
+type ScreeningService struct {
+	repo     ScreeningStore
+	screener *sanctions.Screener
+}
+
+func NewScreeningService(repo ScreeningStore, screener *sanctions.Screener) *ScreeningService {
+	return &ScreeningService{repo: repo, screener: screener}
+}
+
+// ==============================================
+// SCREENING
+// ==============================================
+
+// ScreenSignup screens a new user's name; a potential match freezes their account until reviewed
+func (s *ScreeningService) ScreenSignup(ctx context.Context, userID int, name string) error {
+	_, err := s.screen(ctx, models.ScreeningSubjectSignup, int64(userID), userID, name)
+	return err
+}
+
+// ScreenKYCSubmission screens the names on a tier upgrade; a submission with open hits can't be approved
+func (s *ScreeningService) ScreenKYCSubmission(ctx context.Context, userID int, submissionID int64, names ...string) (bool, error) {
+	return s.screen(ctx, models.ScreeningSubjectKYCSubmission, submissionID, userID, names...)
+}
+
+// ScreenBeneficiary screens a saved recipient's account name; a potential match holds the beneficiary
+func (s *ScreeningService) ScreenBeneficiary(ctx context.Context, userID int, beneficiaryID int64, name string) (bool, error) {
+	return s.screen(ctx, models.ScreeningSubjectBeneficiary, beneficiaryID, userID, name)
+}
+
+// CheckKYCSubmission returns ErrScreeningHold while a submission has pending or confirmed hits
+func (s *ScreeningService) CheckKYCSubmission(ctx context.Context, submissionID int64) error {
+	open, err := s.repo.HasOpenHits(ctx, models.ScreeningSubjectKYCSubmission, submissionID)
+	if err != nil {
+		return err
+	}
+	if open {
+		return ErrScreeningHold
+	}
+	return nil
+}
+
+// CheckPayee returns ErrBeneficiaryHeld if the user saved this account as a beneficiary that is held or blocked
+func (s *ScreeningService) CheckPayee(ctx context.Context, userID int, accountID int64) error {
+	held, err := s.repo.IsPayeeHeld(ctx, userID, accountID)
+	if err != nil {
+		return err
+	}
+	if held {
+		return ErrBeneficiaryHeld
+	}
+	return nil
+}
+
+// screen matches each name against the current lists and records the hits, returning whether any were found
+func (s *ScreeningService) screen(ctx context.Context, subjectType string, subjectID int64, userID int, names ...string) (bool, error) {
+	// Fail closed: without a list nothing can be cleared, so hold the subject for a manual check
+	if s.screener.Current() == nil {
+		log.Printf("[SCREENING] No watchlist loaded - holding %s %d for manual review", subjectType, subjectID)
+		if _, err := s.repo.RecordHits(ctx, subjectType, subjectID, []models.ScreeningHit{unscreenedHit(userID, names)}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	var hits []models.ScreeningHit
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		matches := s.screener.Screen(name)
+		if len(matches) > MaxScreeningHitsPerName {
+			matches = matches[:MaxScreeningHitsPerName]
+		}
+		for _, m := range matches {
+			key := m.Entry.List + "/" + m.Entry.ID
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			hits = append(hits, screeningHit(userID, name, m))
+		}
+	}
+	if len(hits) == 0 {
+		return false, nil
+	}
+
+	created, err := s.repo.RecordHits(ctx, subjectType, subjectID, hits)
+	if err != nil {
+		return false, err
+	}
+
+	log.Printf("[SCREENING] Potential match - Subject: %s %d, UserID: %d, Hits: %d, New: %d",
+		subjectType, subjectID, userID, len(hits), created)
+	return true, nil
+}
+
+// unscreenedHit is the placeholder that holds a subject screened while no watchlist was loaded
+func unscreenedHit(userID int, names []string) models.ScreeningHit {
+	var screened []string
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			screened = append(screened, name)
+		}
+	}
+	name := strings.Join(screened, " / ")
+
+	return models.ScreeningHit{
+		UserID:       pgtype.Int4{Int32: int32(userID), Valid: userID != 0},
+		ScreenedName: name,
+		ListName:     models.ScreeningListUnavailable,
+		EntryUID:     models.ScreeningEntryUnavailable,
+		EntryName:    "No watchlist loaded - screen manually",
+		MatchedName:  name,
+	}
+}
+
+func screeningHit(userID int, name string, m sanctions.Match) models.ScreeningHit {
+	return models.ScreeningHit{
+		UserID:       pgtype.Int4{Int32: int32(userID), Valid: userID != 0},
+		ScreenedName: name,
+		ListName:     m.Entry.List,
+		EntryUID:     m.Entry.ID,
+		EntryName:    m.Entry.Name,
+		MatchedName:  m.MatchedName,
+		EntryType:    pgtype.Text{String: m.Entry.Type, Valid: m.Entry.Type != ""},
+		Program:      pgtype.Text{String: m.Entry.Program, Valid: m.Entry.Program != ""},
+		Score:        m.Score,
+	}
+}
+
+// ==============================================
+// WATCHLISTS
+// ==============================================
+
+// Reload re-reads the watchlist files; the previous lists stay in use if loading fails
+func (s *ScreeningService) Reload(ctx context.Context) (*dto.WatchlistStatusResponse, error) {
+	wl, err := s.screener.Reload(ctx)
+	if err != nil {
+		log.Printf("[SCREENING] Reload failed: %v", err)
+		return nil, fmt.Errorf("%w: %v", ErrWatchlistReload, err)
+	}
+
+	log.Printf("[SCREENING] Watchlists loaded - Files: %s, Entries: %d", strings.Join(wl.Files, ", "), len(wl.Entries))
+	return s.Status(), nil
+}
+
+// Status describes the lists currently in use
+func (s *ScreeningService) Status() *dto.WatchlistStatusResponse {
+	resp := &dto.WatchlistStatusResponse{
+		Files:     []string{},
+		Threshold: s.screener.Threshold(),
+	}
+
+	wl := s.screener.Current()
+	if wl == nil {
+		return resp
+	}
+
+	resp.Loaded = true
+	resp.Files = wl.Files
+	resp.Entries = len(wl.Entries)
+	resp.LoadedAt = wl.LoadedAt.Format(time.RFC3339)
+	return resp
+}
+
+// ==============================================
+// REVIEW
+// ==============================================
+
+// ListHits returns the review queue, newest first
+func (s *ScreeningService) ListHits(ctx context.Context, req dto.ListScreeningHitsRequest) (*dto.ScreeningHitListResponse, error) {
+	page, perPage := req.Page, req.PerPage
+	if page == 0 {
+		page = 1
+	}
+	if perPage == 0 {
+		perPage = 20
+	}
+
+	hits, err := s.repo.ListHits(ctx, req.Status, req.SubjectType, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.ScreeningHitListResponse{
+		Hits:    make([]dto.ScreeningHitDTO, len(hits)),
+		Page:    page,
+		PerPage: perPage,
+	}
+	for i := range hits {
+		resp.Hits[i] = *screeningHitToDTO(&hits[i])
+	}
+	return resp, nil
+}
+
+// GetHit returns a single hit
+func (s *ScreeningService) GetHit(ctx context.Context, id int64) (*dto.ScreeningHitDTO, error) {
+	h, err := s.repo.GetHit(ctx, id)
+	if err != nil {
+		return nil, mapScreeningError(err)
+	}
+	return screeningHitToDTO(h), nil
+}
+
+// Clear marks a hit as a false positive, lifting the hold once the subject has no open hits
+func (s *ScreeningService) Clear(ctx context.Context, reviewerID int, id int64, req dto.ReviewScreeningHitRequest) (*dto.ScreeningHitDTO, error) {
+	return s.review(ctx, reviewerID, id, models.ScreeningStatusCleared, models.AuditActionScreeningCleared, req)
+}
+
+// Confirm marks a hit as a true match; the hold stays and a beneficiary is blocked
+func (s *ScreeningService) Confirm(ctx context.Context, reviewerID int, id int64, req dto.ReviewScreeningHitRequest) (*dto.ScreeningHitDTO, error) {
+	return s.review(ctx, reviewerID, id, models.ScreeningStatusConfirmed, models.AuditActionScreeningConfirmed, req)
+}
+
+func (s *ScreeningService) review(ctx context.Context, reviewerID int, id int64, status, action string, req dto.ReviewScreeningHitRequest) (*dto.ScreeningHitDTO, error) {
+	note := strings.TrimSpace(req.Note)
+
+	metadata, err := json.Marshal(map[string]interface{}{
+		"status": status,
+		"note":   note,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit metadata: %w", err)
+	}
+	audit := &models.AuditLog{
+		UserID:     pgtype.Int4{Int32: int32(reviewerID), Valid: true},
+		Action:     action,
+		EntityType: pgtype.Text{String: "screening_hit", Valid: true},
+		Metadata:   pgtype.Text{String: string(metadata), Valid: true},
+	}
+
+	h, err := s.repo.ReviewHit(ctx, id, reviewerID, status, note, audit)
+	if err != nil {
+		return nil, mapScreeningError(err)
+	}
+
+	log.Printf("[SCREENING] Hit %s - HitID: %d, Subject: %s %d, Reviewer: %d", status, id, h.SubjectType, h.SubjectID, reviewerID)
+	return screeningHitToDTO(h), nil
+}
+
+// ==============================================
+// HELPERS
+// ==============================================
+
+func mapScreeningError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrScreeningHitNotFound):
+		return ErrScreeningHitNotFound
+	case errors.Is(err, repository.ErrScreeningHitReviewed):
+		return ErrScreeningHitReviewed
+	default:
+		return err
+	}
+}
+
+func screeningHitToDTO(h *models.ScreeningHit) *dto.ScreeningHitDTO {
+	out := &dto.ScreeningHitDTO{
+		ID:           h.ID,
+		SubjectType:  h.SubjectType,
+		SubjectID:    h.SubjectID,
+		ScreenedName: h.ScreenedName,
+		ListName:     h.ListName,
+		EntryUID:     h.EntryUID,
+		EntryName:    h.EntryName,
+		MatchedName:  h.MatchedName,
+		EntryType:    h.EntryType.String,
+		Program:      h.Program.String,
+		Score:        h.Score,
+		Status:       h.Status,
+		ReviewNote:   h.ReviewNote.String,
+		CreatedAt:    h.CreatedAt.Format(time.RFC3339),
+	}
+	if h.UserID.Valid {
+		id := int(h.UserID.Int32)
+		out.UserID = &id
+	}
+	if h.ReviewedBy.Valid {
+		id := int(h.ReviewedBy.Int32)
+		out.ReviewedBy = &id
+	}
+	if h.ReviewedAt.Valid {
+		out.ReviewedAt = h.ReviewedAt.Time.Format(time.RFC3339)
+	}
+	return out
+}
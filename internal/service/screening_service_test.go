@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/sanctions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeScreeningStore records hits in memory
+type fakeScreeningStore struct {
+	ScreeningStore
+	recorded map[string][]models.ScreeningHit
+}
+
+func (f *fakeScreeningStore) RecordHits(ctx context.Context, subjectType string, subjectID int64, hits []models.ScreeningHit) (int, error) {
+	if f.recorded == nil {
+		f.recorded = map[string][]models.ScreeningHit{}
+	}
+	f.recorded[subjectType] = append(f.recorded[subjectType], hits...)
+	return len(hits), nil
+}
+
+func loadedScreener(t *testing.T) *sanctions.Screener {
+	t.Helper()
+	dir := t.TempDir()
+	list := "id,name,aliases,type,program\n1,Ivan Petrov,,individual,UKRAINE-EO13660\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "internal.csv"), []byte(list), 0o644))
+
+	screener := sanctions.NewScreener(dir, 0.9)
+	_, err := screener.Reload(context.Background())
+	require.NoError(t, err)
+	return screener
+}
+
+func TestScreen_HoldsEverySubjectWithoutWatchlist(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeScreeningStore{}
+	s := NewScreeningService(store, sanctions.NewScreener(t.TempDir(), 0.9))
+
+	require.NoError(t, s.ScreenSignup(ctx, 7, "Ada Obi"))
+
+	held, err := s.ScreenBeneficiary(ctx, 7, 11, "Bola Ade")
+	require.NoError(t, err)
+	assert.True(t, held)
+
+	held, err = s.ScreenKYCSubmission(ctx, 7, 12, "Ada Obi", "ADA OBI")
+	require.NoError(t, err)
+	assert.True(t, held)
+
+	for _, subject := range []string{models.ScreeningSubjectSignup, models.ScreeningSubjectBeneficiary, models.ScreeningSubjectKYCSubmission} {
+		require.Len(t, store.recorded[subject], 1, subject)
+		hit := store.recorded[subject][0]
+		assert.Equal(t, models.ScreeningListUnavailable, hit.ListName)
+		assert.Equal(t, models.ScreeningEntryUnavailable, hit.EntryUID)
+	}
+	assert.Equal(t, "Ada Obi / ADA OBI", store.recorded[models.ScreeningSubjectKYCSubmission][0].ScreenedName)
+}
+
+func TestScreen_WithWatchlist(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeScreeningStore{}
+	s := NewScreeningService(store, loadedScreener(t))
+
+	held, err := s.ScreenBeneficiary(ctx, 7, 11, "Bola Ade")
+	require.NoError(t, err)
+	assert.False(t, held)
+	assert.Empty(t, store.recorded)
+
+	held, err = s.ScreenBeneficiary(ctx, 7, 12, "Ivan Petrov")
+	require.NoError(t, err)
+	assert.True(t, held)
+	require.Len(t, store.recorded[models.ScreeningSubjectBeneficiary], 1)
+	assert.Equal(t, "internal", store.recorded[models.ScreeningSubjectBeneficiary][0].ListName)
+}
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/auth"
+	"github.com/Brownie44l1/debank/internal/clientinfo"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ==============================================
+// STEP-UP AUTHENTICATION
+// ==============================================
+// Sensitive operations need a fresh second factor on top of the session.
+// The first attempt fails with a StepUpRequiredError carrying a challenge
+// bound to the exact operation; the client repeats the same request with
+// the challenge token and a TOTP code (authenticator users) or the code
+// emailed to them. A proof for one operation can't authorise another.
+
+// ==============================================
+// SERVICE ERRORS
+// ==============================================
+
+var (
+	ErrStepUpRequired    = errors.New("additional verification required")
+	ErrStepUpInvalid     = errors.New("verification challenge is invalid or has expired")
+	ErrStepUpMismatch    = errors.New("verification challenge was issued for a different operation")
+	ErrStepUpCodeInvalid = errors.New("invalid verification code")
+)
+
+// StepUpRequiredError carries the challenge the client has to answer
+type StepUpRequiredError struct {
+	Challenge *dto.StepUpChallengeResponse
+	Reference int64 // Risk decision that asked for the challenge, if any
+}
+
+func (e *StepUpRequiredError) Error() string {
+	if e.Reference > 0 {
+		return fmt.Sprintf("%s (reference %d)", ErrStepUpRequired.Error(), e.Reference)
+	}
+	return ErrStepUpRequired.Error()
+}
+
+func (e *StepUpRequiredError) Unwrap() error {
+	return ErrStepUpRequired
+}
+
+// StepUpOperation is a sensitive action and the parameters a proof is bound to
+type StepUpOperation struct {
+	Action         string            // models.StepUpAction*
+	Amount         int64             // Kobo, for payments; checked against the threshold and bound into the proof
+	Params         map[string]string // Anything else the proof must match, e.g. the recipient
+	RiskDecisionID int64             // Set when a risk rule asked for the challenge
+}
+
+func (op StepUpOperation) binding() string {
+	params := make(map[string]string, len(op.Params)+1)
+	for k, v := range op.Params {
+		params[k] = v
+	}
+	if op.Amount > 0 {
+		params["amount"] = strconv.FormatInt(op.Amount, 10)
+	}
+	return auth.StepUpBinding(op.Action, params)
+}
+
+// ==============================================
+// SERVICE
+// ==============================================
+
+type StepUpService struct {
+	repo             *repository.StepUpRepository
+	mfaRepo          *repository.MFARepository
+	userRepo         *repository.UserRepository
+	verificationRepo *repository.VerificationRepository
+	emailService     *EmailService
+	mfaKey           []byte
+	paymentThreshold int64 // Payments at or above this many kobo need step-up
+}
+
+func NewStepUpService(
+	repo *repository.StepUpRepository,
+	mfaRepo *repository.MFARepository,
+	userRepo *repository.UserRepository,
+	verificationRepo *repository.VerificationRepository,
+	emailService *EmailService,
+	mfaKey []byte,
+	paymentThreshold int64,
+) *StepUpService {
+	return &StepUpService{
+		repo:             repo,
+		mfaRepo:          mfaRepo,
+		userRepo:         userRepo,
+		verificationRepo: verificationRepo,
+		emailService:     emailService,
+		mfaKey:           mfaKey,
+		paymentThreshold: paymentThreshold,
+	}
+}
+
+// Require checks the proof sent with an operation, or challenges the user if the operation needs one
+// Returns true when a valid proof was consumed, so callers can also let risk challenges through
+func (s *StepUpService) Require(ctx context.Context, userID int, op StepUpOperation, proof dto.StepUpProof) (bool, error) {
+	if !proof.IsEmpty() {
+		if err := s.verify(ctx, userID, op, proof); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	if !s.isRequired(op) {
+		return false, nil
+	}
+	return false, s.Challenge(ctx, userID, op)
+}
+
+// Challenge starts a challenge for the operation and returns it as a StepUpRequiredError
+// Users with an authenticator answer with a TOTP code; everyone else is emailed a code
+func (s *StepUpService) Challenge(ctx context.Context, userID int, op StepUpOperation) error {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	method := models.MFAMethodEmail
+	mfa, err := s.mfaRepo.GetMFA(ctx, userID)
+	if err != nil && !errors.Is(err, repository.ErrMFANotFound) {
+		return err
+	}
+	if mfa != nil && mfa.IsEnabled() && mfa.Method == models.MFAMethodTOTP {
+		method = models.MFAMethodTOTP
+	}
+
+	token, err := auth.GenerateToken()
+	if err != nil {
+		return err
+	}
+
+	info := clientinfo.FromContext(ctx)
+	challenge := &models.StepUpChallenge{
+		UserID:      int32(userID),
+		TokenHash:   auth.HashToken(token),
+		Action:      op.Action,
+		BindingHash: op.binding(),
+		Method:      method,
+		ExpiresAt:   time.Now().Add(models.StepUpChallengeTTL),
+		IPAddress:   pgtype.Text{String: info.IPAddress, Valid: info.IPAddress != ""},
+		UserAgent:   pgtype.Text{String: info.UserAgent, Valid: info.UserAgent != ""},
+	}
+	if op.RiskDecisionID > 0 {
+		challenge.RiskDecisionID = pgtype.Int8{Int64: op.RiskDecisionID, Valid: true}
+	}
+
+	var otp *models.VerificationCode
+	if method == models.MFAMethodEmail {
+		if otp, err = s.createCode(ctx, user, challenge); err != nil {
+			return err
+		}
+		challenge.OTPID = pgtype.Int4{Int32: otp.ID, Valid: true}
+	}
+
+	if err := s.repo.CreateChallenge(ctx, challenge); err != nil {
+		return err
+	}
+
+	resp := &dto.StepUpChallengeResponse{
+		Token:     token,
+		Action:    op.Action,
+		Method:    method,
+		ExpiresIn: int(models.StepUpChallengeTTL.Seconds()),
+	}
+	if otp != nil {
+		if err := s.emailService.SendOTP(user.Email, otp.Code, otp.Purpose); err != nil {
+			return fmt.Errorf("failed to send OTP email: %w", err)
+		}
+		resp.Email = maskEmail(user.Email)
+	}
+
+	log.Printf("[STEP_UP] Challenge issued - UserID: %d, Action: %s, Method: %s, ChallengeID: %d",
+		userID, op.Action, method, challenge.ID)
+
+	return &StepUpRequiredError{Challenge: resp, Reference: op.RiskDecisionID}
+}
+
+// SendEmailCode emails a code for a pending challenge, as a fallback for a missing authenticator
+func (s *StepUpService) SendEmailCode(ctx context.Context, userID int, req dto.StepUpEmailRequest) (*dto.MFACodeSentResponse, error) {
+	challenge, err := s.loadChallenge(ctx, userID, req.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	otp, err := s.createCode(ctx, user, challenge)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetChallengeOTP(ctx, challenge.ID, otp.ID); err != nil {
+		return nil, err
+	}
+	if err := s.emailService.SendOTP(user.Email, otp.Code, otp.Purpose); err != nil {
+		return nil, fmt.Errorf("failed to send OTP email: %w", err)
+	}
+
+	return &dto.MFACodeSentResponse{
+		Message:   "Verification code sent to your email",
+		Email:     maskEmail(user.Email),
+		ExpiresIn: int(time.Until(challenge.ExpiresAt).Seconds()),
+	}, nil
+}
+
+// ==============================================
+// HELPERS
+// ==============================================
+
+func (s *StepUpService) isRequired(op StepUpOperation) bool {
+	switch op.Action {
+	case models.StepUpActionTransfer, models.StepUpActionWithdraw:
+		return op.Amount >= s.paymentThreshold
+	default:
+		return true
+	}
+}
+
+// verify consumes a proof if it answers a live challenge for exactly this operation
+func (s *StepUpService) verify(ctx context.Context, userID int, op StepUpOperation, proof dto.StepUpProof) error {
+	if proof.Token == "" || proof.Code == "" {
+		return ErrStepUpInvalid
+	}
+
+	challenge, err := s.loadChallenge(ctx, userID, proof.Token)
+	if err != nil {
+		return err
+	}
+	if challenge.BindingHash != op.binding() {
+		log.Printf("[STEP_UP] Operation mismatch - UserID: %d, ChallengeID: %d, Action: %s",
+			userID, challenge.ID, op.Action)
+		return ErrStepUpMismatch
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	usedEmailCode, valid, err := s.checkCode(ctx, user, challenge, proof.Code)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		_ = s.repo.IncrementChallengeAttempts(ctx, challenge.ID)
+		log.Printf("[STEP_UP] Wrong code - UserID: %d, ChallengeID: %d", userID, challenge.ID)
+		return ErrStepUpCodeInvalid
+	}
+
+	audit, err := userAudit(ctx, userID, models.AuditActionStepUpVerified, map[string]interface{}{
+		"action":       challenge.Action,
+		"challenge_id": challenge.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	consumed, err := s.repo.ConsumeChallenge(ctx, challenge, usedEmailCode, audit)
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return ErrStepUpInvalid
+	}
+
+	return nil
+}
+
+// checkCode accepts a fresh TOTP code for authenticator challenges, or the code emailed for this challenge
+func (s *StepUpService) checkCode(ctx context.Context, user *models.User, challenge *models.StepUpChallenge, code string) (usedEmailCode, valid bool, err error) {
+	if challenge.Method == models.MFAMethodTOTP {
+		mfa, err := s.mfaRepo.GetMFA(ctx, int(user.ID))
+		if err != nil && !errors.Is(err, repository.ErrMFANotFound) {
+			return false, false, err
+		}
+		if mfa != nil && mfa.IsEnabled() && mfa.Method == models.MFAMethodTOTP {
+			ok, err := checkTOTP(ctx, s.mfaRepo, s.mfaKey, mfa, code)
+			if err != nil || ok {
+				return false, ok, err
+			}
+		}
+	}
+
+	if !challenge.OTPID.Valid {
+		return false, false, nil
+	}
+
+	// Only the code sent for this challenge counts, not any other code of the same purpose
+	otp, err := s.verificationRepo.GetOTPByCode(ctx, user.Email, code, models.StepUpPurpose(challenge.Action))
+	if err != nil {
+		if errors.Is(err, repository.ErrOTPNotFound) {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+	if otp.ID != challenge.OTPID.Int32 || !otp.IsValid() {
+		return false, false, nil
+	}
+
+	return true, true, nil
+}
+
+func (s *StepUpService) loadChallenge(ctx context.Context, userID int, token string) (*models.StepUpChallenge, error) {
+	challenge, err := s.repo.GetChallengeByToken(ctx, auth.HashToken(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrStepUpChallengeNotFound) {
+			return nil, ErrStepUpInvalid
+		}
+		return nil, err
+	}
+	if challenge.UserID != int32(userID) || !challenge.IsUsable() {
+		return nil, ErrStepUpInvalid
+	}
+	return challenge, nil
+}
+
+// createCode stores an emailed code that lives exactly as long as the challenge, respecting the resend cooldown
+func (s *StepUpService) createCode(ctx context.Context, user *models.User, challenge *models.StepUpChallenge) (*models.VerificationCode, error) {
+	purpose := models.StepUpPurpose(challenge.Action)
+
+	canSend, err := s.verificationRepo.CanResendOTP(ctx, user.Email, purpose, models.OTPResendCooldown)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check resend eligibility: %w", err)
+	}
+	if !canSend {
+		return nil, models.ErrOTPResendCooldown
+	}
+
+	otp := &models.VerificationCode{
+		UserID:    pgtype.Int4{Int32: user.ID, Valid: true},
+		Email:     user.Email,
+		Code:      auth.GenerateOTP(),
+		Purpose:   purpose,
+		ExpiresAt: challenge.ExpiresAt,
+	}
+	if err := s.verificationRepo.CreateOTP(ctx, otp); err != nil {
+		return nil, fmt.Errorf("failed to create OTP: %w", err)
+	}
+
+	return otp, nil
+}
@@ -1 +1,309 @@
-package service
\ No newline at end of file
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/metrics"
+	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/tracing"
+	"github.com/Brownie44l1/debank/pkg/generator"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ==============================================
+// P2P TRANSFER
+// ==============================================
+
+func (s *WalletService) Transfer(ctx context.Context, userID int, req dto.TransferRequest) (*dto.TransferResponse, error) {
+	if err := validateClientIdempotencyKey(req.IdempotencyKey); err != nil {
+		return nil, err
+	}
+	return s.transfer(ctx, userID, req, nil)
+}
+
+// settleFunc runs inside a transfer's database transaction, after the
+// postings and before commit, so a caller's own state change lands or
+// rolls back together with the money
+type settleFunc func(ctx context.Context, tx pgx.Tx, txn *models.Transaction) error
+
+func (s *WalletService) transfer(ctx context.Context, userID int, req dto.TransferRequest, settle settleFunc) (*dto.TransferResponse, error) {
+	startTime := time.Now()
+	ctx, span := tracing.Start(ctx, "WalletService.Transfer",
+		attribute.Int("user.id", userID),
+		attribute.Int64("transaction.amount_kobo", req.Amount),
+	)
+	defer span.End()
+	log.Printf("[TRANSFER] Started - UserID: %d, To: %s, Amount: %d kobo, IdempotencyKey: %s",
+		userID, req.ToIdentifier, req.Amount, req.IdempotencyKey)
+
+	// 1. Validate inputs
+	if req.IdempotencyKey == "" {
+		return nil, ErrInvalidIdempotencyKey
+	}
+	if err := s.validateTransferAmount(req.Amount); err != nil {
+		log.Printf("[TRANSFER] Validation failed: %v", err)
+		return nil, err
+	}
+	if err := s.pinValidator.ValidatePin(ctx, userID, req.Pin); err != nil {
+		log.Printf("[TRANSFER] PIN check failed - UserID: %d, Error: %v", userID, err)
+		return nil, err
+	}
+
+	// 2. Check idempotency (before starting transaction)
+	existingTxn, err := s.repo.GetTransactionByIdempotencyKey(ctx, req.IdempotencyKey)
+	if err != nil && !isNoRowsError(err) {
+		return nil, fmt.Errorf("idempotency check failed: %w", err)
+	}
+	if existingTxn != nil {
+		log.Printf("[TRANSFER] Idempotent request - Returning existing transaction: %d", existingTxn.ID)
+		metrics.IdempotentReplays.WithLabelValues(models.TransactionKindP2P).Inc()
+		span.SetAttributes(attribute.Bool("transaction.idempotent_replay", true))
+		return s.buildIdempotentTransferResponse(ctx, existingTxn, userID, req)
+	}
+
+	// 3. Large transfers need a fresh second factor, bound to this recipient and amount
+	op := StepUpOperation{
+		Action: models.StepUpActionTransfer,
+		Amount: req.Amount,
+		Params: map[string]string{"to": strings.TrimSpace(req.ToIdentifier)},
+	}
+	steppedUp, err := s.stepUp.Require(ctx, userID, op, req.StepUp)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Execute transfer with locking
+	txn, senderBalance, err := s.executeTransfer(ctx, userID, req, op, steppedUp, settle)
+	if err != nil {
+		log.Printf("[TRANSFER] Failed - UserID: %d, Error: %v", userID, err)
+		observeTransactionFailure(models.TransactionKindP2P, req.Amount, startTime, err)
+		tracing.Fail(span, err)
+		return nil, err
+	}
+
+	// 5. Validate result
+	if senderBalance < 0 {
+		log.Printf("[TRANSFER] CRITICAL - Negative balance! UserID: %d, Balance: %d", userID, senderBalance)
+		return nil, ErrNegativeBalance
+	}
+
+	duration := time.Since(startTime)
+	log.Printf("[TRANSFER] Success - TxnID: %d, SenderBalance: %d kobo, Duration: %v", txn.ID, senderBalance, duration)
+	metrics.ObserveTransaction(models.TransactionKindP2P, metrics.StatusPosted, req.Amount, startTime)
+	span.SetAttributes(attribute.Int64("transaction.id", txn.ID))
+
+	return &dto.TransferResponse{
+		TransactionID: txn.ID,
+		Reference:     txn.Reference,
+		Status:        txn.Status,
+		SenderBalance: senderBalance,
+		Message:       fmt.Sprintf("Successfully sent ₦%.2f to %s", float64(req.Amount)/100, req.ToIdentifier),
+	}, nil
+}
+
+func (s *WalletService) executeTransfer(ctx context.Context, userID int, req dto.TransferRequest, op StepUpOperation, steppedUp bool, settle settleFunc) (*models.Transaction, int64, error) {
+	// Resolve both sides before taking any locks
+	sender, err := s.repo.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		if isAccountNotFoundError(err) {
+			return nil, 0, ErrAccountNotFound
+		}
+		return nil, 0, err
+	}
+
+	recipient, err := s.repo.GetAccountByIdentifier(ctx, req.ToIdentifier)
+	if err != nil {
+		if isAccountNotFoundError(err) {
+			return nil, 0, ErrRecipientNotFound
+		}
+		return nil, 0, err
+	}
+
+	if recipient.IsSystemAccount() {
+		return nil, 0, models.ErrSystemAccountTransfer
+	}
+	if recipient.ID == sender.ID {
+		return nil, 0, ErrSameAccount
+	}
+	if err := s.payees.CheckPayee(ctx, userID, recipient.ID); err != nil {
+		return nil, 0, err
+	}
+
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// Lock both accounts in ascending ID order so that two opposing
+	// transfers between the same pair can never deadlock
+	firstID, secondID := sender.ID, recipient.ID
+	if firstID > secondID {
+		firstID, secondID = secondID, firstID
+	}
+
+	first, err := s.repo.GetAccountByIDForUpdate(ctx, tx, firstID)
+	if err != nil {
+		return nil, 0, err
+	}
+	second, err := s.repo.GetAccountByIDForUpdate(ctx, tx, secondID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	lockedSender, lockedRecipient := first, second
+	if first.ID != sender.ID {
+		lockedSender, lockedRecipient = second, first
+	}
+
+	if lockedSender.IsFrozen() || lockedRecipient.IsFrozen() {
+		return nil, 0, ErrAccountFrozen
+	}
+	if lockedSender.Balance < req.Amount {
+		return nil, 0, ErrInsufficientBalance
+	}
+
+	// Enforce KYC tiers on both sides
+	if err := s.limiter.CheckDebit(ctx, tx, lockedSender, req.Amount); err != nil {
+		return nil, 0, err
+	}
+	if err := s.limiter.CheckCredit(ctx, tx, lockedRecipient, req.Amount); err != nil {
+		if errors.Is(err, ErrTierMaxBalanceExceeded) || errors.Is(err, ErrTierSingleLimitExceeded) {
+			// Don't reveal the recipient's tier or balance
+			return nil, 0, ErrRecipientLimitExceeded
+		}
+		return nil, 0, err
+	}
+
+	// Fraud screening - last check before any money moves
+	decision, err := s.risk.Screen(ctx, tx, RiskCheck{
+		UserID:         userID,
+		Account:        lockedSender,
+		Operation:      models.RiskOperationTransfer,
+		Amount:         req.Amount,
+		Counterparty:   lockedRecipient,
+		IdempotencyKey: req.IdempotencyKey,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if !s.riskPassed(decision, steppedUp) {
+		_ = tx.Rollback(ctx)
+		return nil, 0, s.rejectRisk(ctx, userID, decision, op)
+	}
+
+	txn := &models.Transaction{
+		IdempotencyKey: req.IdempotencyKey,
+		Reference:      generator.GenerateReference("TRF"),
+		Kind:           models.TransactionKindP2P,
+		Status:         models.TransactionStatusPosted,
+		Amount:         req.Amount,
+		Currency:       "NGN",
+		FromAccountID:  pgtype.Int8{Int64: lockedSender.ID, Valid: true},
+		ToAccountID:    pgtype.Int8{Int64: lockedRecipient.ID, Valid: true},
+		ToIdentifier:   pgtype.Text{String: req.ToIdentifier, Valid: true},
+	}
+	if lockedSender.AccountNumber.Valid {
+		txn.FromIdentifier = lockedSender.AccountNumber
+	}
+	if req.Description != "" {
+		txn.Description = pgtype.Text{String: req.Description, Valid: true}
+	}
+
+	if err := s.repo.CreateTransaction(ctx, tx, txn); err != nil {
+		return nil, 0, createTransactionError(err)
+	}
+
+	// Debit sender
+	if err := s.repo.CreatePosting(ctx, tx, &models.Posting{
+		TransactionID: txn.ID,
+		AccountID:     lockedSender.ID,
+		Amount:        -req.Amount,
+		Currency:      "NGN",
+	}); err != nil {
+		return nil, 0, err
+	}
+
+	// Credit recipient
+	if err := s.repo.CreatePosting(ctx, tx, &models.Posting{
+		TransactionID: txn.ID,
+		AccountID:     lockedRecipient.ID,
+		Amount:        req.Amount,
+		Currency:      "NGN",
+	}); err != nil {
+		return nil, 0, err
+	}
+
+	if err := s.risk.Record(ctx, tx, decision, txn.ID); err != nil {
+		return nil, 0, err
+	}
+
+	if err := s.auditTransaction(ctx, tx, userID, models.AuditActionTransfer, txn, map[string]interface{}{
+		"to_account_id": lockedRecipient.ID,
+		"risk_outcome":  decision.Outcome,
+		"stepped_up":    steppedUp,
+	}); err != nil {
+		return nil, 0, err
+	}
+
+	if settle != nil {
+		if err := settle(ctx, tx, txn); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, 0, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	newBalance := lockedSender.Balance - req.Amount
+	return txn, newBalance, nil
+}
+
+func (s *WalletService) buildIdempotentTransferResponse(ctx context.Context, txn *models.Transaction, userID int, req dto.TransferRequest) (*dto.TransferResponse, error) {
+	account, err := s.repo.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		if isAccountNotFoundError(err) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+
+	// The key belongs to someone else's transfer - never report it as ours
+	if !txn.FromAccountID.Valid || txn.FromAccountID.Int64 != account.ID {
+		return nil, ErrIdempotencyConflict
+	}
+
+	// Nor replay a different transfer of ours under a reused key
+	if txn.Kind != models.TransactionKindP2P || txn.Amount != req.Amount || txn.Currency != "NGN" {
+		return nil, ErrIdempotencyConflict
+	}
+	recipient, err := s.repo.GetAccountByIdentifier(ctx, req.ToIdentifier)
+	if err != nil {
+		if isAccountNotFoundError(err) {
+			return nil, ErrIdempotencyConflict
+		}
+		return nil, err
+	}
+	if !txn.ToAccountID.Valid || txn.ToAccountID.Int64 != recipient.ID {
+		return nil, ErrIdempotencyConflict
+	}
+
+	return &dto.TransferResponse{
+		TransactionID: txn.ID,
+		Reference:     txn.Reference,
+		Status:        txn.Status,
+		SenderBalance: account.Balance,
+		Message:       "Transaction already processed",
+	}, nil
+}
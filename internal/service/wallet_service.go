@@ -5,11 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/Brownie44l1/debank/internal/api/dto"
+	"github.com/Brownie44l1/debank/internal/metrics"
 	"github.com/Brownie44l1/debank/internal/models"
+	"github.com/Brownie44l1/debank/internal/repository"
+	"github.com/Brownie44l1/debank/internal/tracing"
 	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // ==============================================
@@ -20,6 +25,8 @@ type WalletRepositoryInterface interface {
 	BeginTx(ctx context.Context) (pgx.Tx, error)
 	GetAccountByUserID(ctx context.Context, userID int) (*models.Account, error)
 	GetAccountByUserIDForUpdate(ctx context.Context, tx pgx.Tx, userID int) (*models.Account, error)
+	GetAccountByIdentifier(ctx context.Context, identifier string) (*models.Account, error)
+	GetAccountByIDForUpdate(ctx context.Context, tx pgx.Tx, accountID int64) (*models.Account, error)
 	GetSystemAccount(ctx context.Context, externalID string) (*models.Account, error)
 	GetSystemAccountForUpdate(ctx context.Context, tx pgx.Tx, externalID string) (*models.Account, error)
 	GetTransactionByIdempotencyKey(ctx context.Context, key string) (*models.Transaction, error)
@@ -27,6 +34,45 @@ type WalletRepositoryInterface interface {
 	CreatePosting(ctx context.Context, tx pgx.Tx, posting *models.Posting) error
 	GetTransactionHistory(ctx context.Context, userID int, limit, offset int) ([]models.TransactionHistoryItem, error)
 	CountTransactionHistory(ctx context.Context, userID int) (int, error)
+	GetTransactionHistoryAfter(ctx context.Context, userID int, afterID int64, limit int) ([]models.TransactionHistoryItem, error)
+	GetTransactionHistoryItem(ctx context.Context, userID int, reference string) (*models.TransactionHistoryItem, error)
+	GetDebitTotalSince(ctx context.Context, accountID int64, since time.Time) (int64, error)
+	GetDebitTotalSinceTx(ctx context.Context, tx pgx.Tx, accountID int64, since time.Time) (int64, error)
+}
+
+// PinValidator checks a user's transaction PIN (implemented by AuthService)
+type PinValidator interface {
+	ValidatePin(ctx context.Context, userID int, pin string) error
+}
+
+// TransactionLimiter enforces per-account limits inside a ledger transaction (implemented by KYCService)
+// Both methods expect the account row to be locked FOR UPDATE already
+type TransactionLimiter interface {
+	CheckDebit(ctx context.Context, tx pgx.Tx, account *models.Account, amount int64) error
+	CheckCredit(ctx context.Context, tx pgx.Tx, account *models.Account, amount int64) error
+}
+
+// RiskScreener runs fraud rules on outgoing payments inside the ledger transaction (implemented by RiskService)
+type RiskScreener interface {
+	Screen(ctx context.Context, tx pgx.Tx, check RiskCheck) (*models.RiskDecision, error)
+	Record(ctx context.Context, tx pgx.Tx, decision *models.RiskDecision, transactionID int64) error
+	Reject(ctx context.Context, decision *models.RiskDecision) error
+}
+
+// PayeeChecker refuses payments to beneficiaries held by sanctions screening (implemented by ScreeningService)
+type PayeeChecker interface {
+	CheckPayee(ctx context.Context, userID int, accountID int64) error
+}
+
+// StepUpGuard demands a fresh second factor for sensitive operations (implemented by StepUpService)
+type StepUpGuard interface {
+	Require(ctx context.Context, userID int, op StepUpOperation, proof dto.StepUpProof) (bool, error)
+	Challenge(ctx context.Context, userID int, op StepUpOperation) error
+}
+
+// AuditWriter records money movements in the posting transaction (implemented by AuditLogger)
+type AuditWriter interface {
+	WriteTx(ctx context.Context, tx pgx.Tx, entry *models.AuditLog) error
 }
 
 // ==============================================
@@ -37,8 +83,12 @@ const (
 	MinDepositAmount     = 10000     // ₦100.00 minimum deposit
 	MinWithdrawAmount    = 10000     // ₦100.00 minimum withdrawal
 	MinTransferAmount    = 10000     // ₦100.00 minimum transfer
-	MaxTransactionAmount = 100000000 // ₦1,000,000.00 maximum per transaction
+	MaxTransactionAmount = 100000000 // ₦1,000,000.00 hard ceiling; KYC tiers apply tighter limits
 	DefaultTransferFee   = 0         // ₦0.00 (free transfers for now)
+
+	// Idempotency keys the service derives for transfers it makes on a
+	// user's behalf (e.g. settling a payment request); clients can't use it
+	InternalIdempotencyPrefix = "internal:"
 )
 
 // ==============================================
@@ -46,14 +96,18 @@ const (
 // ==============================================
 
 var (
-	ErrInvalidAmount         = errors.New("invalid transaction amount")
-	ErrAmountTooSmall        = errors.New("amount is below minimum")
-	ErrAmountTooLarge        = errors.New("amount exceeds maximum")
-	ErrInvalidIdempotencyKey = errors.New("idempotency key is required")
-	ErrNegativeBalance       = errors.New("balance integrity error: negative balance detected")
-	ErrInsufficientBalance   = errors.New("insufficient balance")
-	ErrAccountNotFound       = errors.New("account not found")
-	ErrSameAccount           = errors.New("cannot transfer to same account")
+	ErrInvalidAmount          = errors.New("invalid transaction amount")
+	ErrAmountTooSmall         = errors.New("amount is below minimum")
+	ErrAmountTooLarge         = errors.New("amount exceeds maximum")
+	ErrInvalidIdempotencyKey  = errors.New("idempotency key is required")
+	ErrReservedIdempotencyKey = errors.New("idempotency key uses a reserved prefix")
+	ErrIdempotencyConflict    = errors.New("idempotency key already used for a different transaction")
+	ErrNegativeBalance        = errors.New("balance integrity error: negative balance detected")
+	ErrInsufficientBalance    = errors.New("insufficient balance")
+	ErrAccountNotFound        = errors.New("account not found")
+	ErrSameAccount            = errors.New("cannot transfer to same account")
+	ErrRecipientNotFound      = errors.New("recipient not found")
+	ErrAccountFrozen          = errors.New("account is frozen")
 )
 
 // ==============================================
@@ -61,11 +115,17 @@ var (
 // ==============================================
 
 type WalletService struct {
-	repo WalletRepositoryInterface
+	repo         WalletRepositoryInterface
+	pinValidator PinValidator
+	limiter      TransactionLimiter
+	risk         RiskScreener
+	payees       PayeeChecker
+	stepUp       StepUpGuard
+	audit        AuditWriter
 }
 
-func NewWalletService(repo WalletRepositoryInterface) *WalletService {
-	return &WalletService{repo: repo}
+func NewWalletService(repo WalletRepositoryInterface, pinValidator PinValidator, limiter TransactionLimiter, risk RiskScreener, payees PayeeChecker, stepUp StepUpGuard, audit AuditWriter) *WalletService {
+	return &WalletService{repo: repo, pinValidator: pinValidator, limiter: limiter, risk: risk, payees: payees, stepUp: stepUp, audit: audit}
 }
 
 // ==============================================
@@ -74,12 +134,17 @@ func NewWalletService(repo WalletRepositoryInterface) *WalletService {
 
 func (s *WalletService) Deposit(ctx context.Context, userID int, req dto.DepositRequest) (*dto.TransactionResponse, error) {
 	startTime := time.Now()
+	ctx, span := tracing.Start(ctx, "WalletService.Deposit",
+		attribute.Int("user.id", userID),
+		attribute.Int64("transaction.amount_kobo", req.Amount),
+	)
+	defer span.End()
 	log.Printf("[DEPOSIT] Started - UserID: %d, Amount: %d kobo, IdempotencyKey: %s",
 		userID, req.Amount, req.IdempotencyKey)
 
 	// 1. Validate inputs
-	if req.IdempotencyKey == "" {
-		return nil, ErrInvalidIdempotencyKey
+	if err := validateClientIdempotencyKey(req.IdempotencyKey); err != nil {
+		return nil, err
 	}
 	if err := s.validateDepositAmount(req.Amount); err != nil {
 		log.Printf("[DEPOSIT] Validation failed: %v", err)
@@ -93,13 +158,17 @@ func (s *WalletService) Deposit(ctx context.Context, userID int, req dto.Deposit
 	}
 	if existingTxn != nil {
 		log.Printf("[DEPOSIT] Idempotent request - Returning existing transaction: %d", existingTxn.ID)
-		return s.buildIdempotentResponse(ctx, existingTxn.ID, userID, req.Reference)
+		metrics.IdempotentReplays.WithLabelValues(models.TransactionKindDeposit).Inc()
+		span.SetAttributes(attribute.Bool("transaction.idempotent_replay", true))
+		return s.buildIdempotentResponse(ctx, existingTxn, userID, req.Reference)
 	}
 
 	// 3. Execute deposit transaction with locking
 	txnID, newBalance, err := s.executeDeposit(ctx, userID, req)
 	if err != nil {
 		log.Printf("[DEPOSIT] Failed - UserID: %d, Error: %v", userID, err)
+		observeTransactionFailure(models.TransactionKindDeposit, req.Amount, startTime, err)
+		tracing.Fail(span, err)
 		return nil, err
 	}
 
@@ -111,6 +180,8 @@ func (s *WalletService) Deposit(ctx context.Context, userID int, req dto.Deposit
 
 	duration := time.Since(startTime)
 	log.Printf("[DEPOSIT] Success - TxnID: %d, NewBalance: %d kobo, Duration: %v", txnID, newBalance, duration)
+	metrics.ObserveTransaction(models.TransactionKindDeposit, metrics.StatusPosted, req.Amount, startTime)
+	span.SetAttributes(attribute.Int64("transaction.id", txnID))
 
 	return &dto.TransactionResponse{
 		TransactionID: txnID,
@@ -139,6 +210,11 @@ func (s *WalletService) executeDeposit(ctx context.Context, userID int, req dto.
 		return 0, 0, err
 	}
 
+	// Enforce KYC tier (max single amount, max balance)
+	if err := s.limiter.CheckCredit(ctx, tx, userAccount, req.Amount); err != nil {
+		return 0, 0, err
+	}
+
 	// Lock reserve account
 	reserveAccount, err := s.repo.GetSystemAccountForUpdate(ctx, tx, "sys_reserve")
 	if err != nil {
@@ -154,7 +230,7 @@ func (s *WalletService) executeDeposit(ctx context.Context, userID int, req dto.
 		Amount:         req.Amount,
 		Currency:       "NGN",
 	}
-	
+
 	// Set account IDs
 	txn.FromAccountID.Int64 = reserveAccount.ID
 	txn.FromAccountID.Valid = true
@@ -162,7 +238,7 @@ func (s *WalletService) executeDeposit(ctx context.Context, userID int, req dto.
 	txn.ToAccountID.Valid = true
 
 	if err := s.repo.CreateTransaction(ctx, tx, txn); err != nil {
-		return 0, 0, err
+		return 0, 0, createTransactionError(err)
 	}
 
 	// Debit reserve
@@ -185,6 +261,10 @@ func (s *WalletService) executeDeposit(ctx context.Context, userID int, req dto.
 		return 0, 0, err
 	}
 
+	if err := s.auditTransaction(ctx, tx, userID, models.AuditActionDeposit, txn, nil); err != nil {
+		return 0, 0, err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return 0, 0, fmt.Errorf("failed to commit: %w", err)
 	}
@@ -199,16 +279,25 @@ func (s *WalletService) executeDeposit(ctx context.Context, userID int, req dto.
 
 func (s *WalletService) Withdraw(ctx context.Context, userID int, req dto.WithdrawRequest) (*dto.TransactionResponse, error) {
 	startTime := time.Now()
+	ctx, span := tracing.Start(ctx, "WalletService.Withdraw",
+		attribute.Int("user.id", userID),
+		attribute.Int64("transaction.amount_kobo", req.Amount),
+	)
+	defer span.End()
 	log.Printf("[WITHDRAW] Started - UserID: %d, Amount: %d kobo, IdempotencyKey: %s",
 		userID, req.Amount, req.IdempotencyKey)
 
-	if req.IdempotencyKey == "" {
-		return nil, ErrInvalidIdempotencyKey
+	if err := validateClientIdempotencyKey(req.IdempotencyKey); err != nil {
+		return nil, err
 	}
 	if err := s.validateWithdrawAmount(req.Amount); err != nil {
 		log.Printf("[WITHDRAW] Validation failed: %v", err)
 		return nil, err
 	}
+	if err := s.pinValidator.ValidatePin(ctx, userID, req.Pin); err != nil {
+		log.Printf("[WITHDRAW] PIN check failed - UserID: %d, Error: %v", userID, err)
+		return nil, err
+	}
 
 	existingTxn, err := s.repo.GetTransactionByIdempotencyKey(ctx, req.IdempotencyKey)
 	if err != nil && !isNoRowsError(err) {
@@ -216,12 +305,23 @@ func (s *WalletService) Withdraw(ctx context.Context, userID int, req dto.Withdr
 	}
 	if existingTxn != nil {
 		log.Printf("[WITHDRAW] Idempotent request - Returning existing transaction: %d", existingTxn.ID)
-		return s.buildIdempotentResponse(ctx, existingTxn.ID, userID, req.Reference)
+		metrics.IdempotentReplays.WithLabelValues(models.TransactionKindWithdraw).Inc()
+		span.SetAttributes(attribute.Bool("transaction.idempotent_replay", true))
+		return s.buildIdempotentResponse(ctx, existingTxn, userID, req.Reference)
+	}
+
+	// Large withdrawals need a fresh second factor
+	op := StepUpOperation{Action: models.StepUpActionWithdraw, Amount: req.Amount}
+	steppedUp, err := s.stepUp.Require(ctx, userID, op, req.StepUp)
+	if err != nil {
+		return nil, err
 	}
 
-	txnID, newBalance, err := s.executeWithdraw(ctx, userID, req)
+	txnID, newBalance, err := s.executeWithdraw(ctx, userID, req, op, steppedUp)
 	if err != nil {
 		log.Printf("[WITHDRAW] Failed - UserID: %d, Error: %v", userID, err)
+		observeTransactionFailure(models.TransactionKindWithdraw, req.Amount, startTime, err)
+		tracing.Fail(span, err)
 		return nil, err
 	}
 
@@ -232,6 +332,8 @@ func (s *WalletService) Withdraw(ctx context.Context, userID int, req dto.Withdr
 
 	duration := time.Since(startTime)
 	log.Printf("[WITHDRAW] Success - TxnID: %d, NewBalance: %d kobo, Duration: %v", txnID, newBalance, duration)
+	metrics.ObserveTransaction(models.TransactionKindWithdraw, metrics.StatusPosted, req.Amount, startTime)
+	span.SetAttributes(attribute.Int64("transaction.id", txnID))
 
 	return &dto.TransactionResponse{
 		TransactionID: txnID,
@@ -242,7 +344,7 @@ func (s *WalletService) Withdraw(ctx context.Context, userID int, req dto.Withdr
 	}, nil
 }
 
-func (s *WalletService) executeWithdraw(ctx context.Context, userID int, req dto.WithdrawRequest) (int64, int64, error) {
+func (s *WalletService) executeWithdraw(ctx context.Context, userID int, req dto.WithdrawRequest, op StepUpOperation, steppedUp bool) (int64, int64, error) {
 	tx, err := s.repo.BeginTx(ctx)
 	if err != nil {
 		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
@@ -259,10 +361,34 @@ func (s *WalletService) executeWithdraw(ctx context.Context, userID int, req dto
 		return 0, 0, err
 	}
 
+	if userAccount.IsFrozen() {
+		return 0, 0, ErrAccountFrozen
+	}
 	if userAccount.Balance < req.Amount {
 		return 0, 0, ErrInsufficientBalance
 	}
 
+	// Enforce KYC tier (max single amount, rolling daily/monthly caps)
+	if err := s.limiter.CheckDebit(ctx, tx, userAccount, req.Amount); err != nil {
+		return 0, 0, err
+	}
+
+	// Fraud screening - last check before any money moves
+	decision, err := s.risk.Screen(ctx, tx, RiskCheck{
+		UserID:         userID,
+		Account:        userAccount,
+		Operation:      models.RiskOperationWithdraw,
+		Amount:         req.Amount,
+		IdempotencyKey: req.IdempotencyKey,
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	if !s.riskPassed(decision, steppedUp) {
+		_ = tx.Rollback(ctx)
+		return 0, 0, s.rejectRisk(ctx, userID, decision, op)
+	}
+
 	reserveAccount, err := s.repo.GetSystemAccountForUpdate(ctx, tx, "sys_reserve")
 	if err != nil {
 		return 0, 0, fmt.Errorf("reserve account not found: %w", err)
@@ -276,14 +402,14 @@ func (s *WalletService) executeWithdraw(ctx context.Context, userID int, req dto
 		Amount:         req.Amount,
 		Currency:       "NGN",
 	}
-	
+
 	txn.FromAccountID.Int64 = userAccount.ID
 	txn.FromAccountID.Valid = true
 	txn.ToAccountID.Int64 = reserveAccount.ID
 	txn.ToAccountID.Valid = true
 
 	if err := s.repo.CreateTransaction(ctx, tx, txn); err != nil {
-		return 0, 0, err
+		return 0, 0, createTransactionError(err)
 	}
 
 	if err := s.repo.CreatePosting(ctx, tx, &models.Posting{
@@ -304,6 +430,17 @@ func (s *WalletService) executeWithdraw(ctx context.Context, userID int, req dto
 		return 0, 0, err
 	}
 
+	if err := s.risk.Record(ctx, tx, decision, txn.ID); err != nil {
+		return 0, 0, err
+	}
+
+	if err := s.auditTransaction(ctx, tx, userID, models.AuditActionWithdrawal, txn, map[string]interface{}{
+		"risk_outcome": decision.Outcome,
+		"stepped_up":   steppedUp,
+	}); err != nil {
+		return 0, 0, err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return 0, 0, fmt.Errorf("failed to commit: %w", err)
 	}
@@ -312,6 +449,42 @@ func (s *WalletService) executeWithdraw(ctx context.Context, userID int, req dto
 	return txn.ID, newBalance, nil
 }
 
+// auditTransaction records a posted transaction in the ledger transaction that posts it
+func (s *WalletService) auditTransaction(ctx context.Context, tx pgx.Tx, userID int, action string, txn *models.Transaction, extra map[string]interface{}) error {
+	metadata := map[string]interface{}{
+		"amount":    txn.Amount,
+		"currency":  txn.Currency,
+		"reference": txn.Reference,
+	}
+	for k, v := range extra {
+		metadata[k] = v
+	}
+
+	entry, err := auditEntry(ctx, userID, action, "transaction", txn.ID, metadata)
+	if err != nil {
+		return err
+	}
+	return s.audit.WriteTx(ctx, tx, entry)
+}
+
+// riskPassed checks if screening lets a payment through
+// A challenge outcome is satisfied by a step-up proof for this exact payment
+func (s *WalletService) riskPassed(decision *models.RiskDecision, steppedUp bool) bool {
+	return decision.IsAllowed() || (steppedUp && decision.Outcome == models.RiskOutcomeChallenge)
+}
+
+// rejectRisk records a refused payment; a challenge outcome is turned into a step-up challenge
+// Call it after rolling back the ledger transaction so the account locks are released
+func (s *WalletService) rejectRisk(ctx context.Context, userID int, decision *models.RiskDecision, op StepUpOperation) error {
+	err := s.risk.Reject(ctx, decision)
+	if decision.Outcome != models.RiskOutcomeChallenge {
+		return err
+	}
+
+	op.RiskDecisionID = decision.ID
+	return s.stepUp.Challenge(ctx, userID, op)
+}
+
 // ==============================================
 // GET BALANCE
 // ==============================================
@@ -373,18 +546,7 @@ func (s *WalletService) GetTransactionHistory(ctx context.Context, userID, page,
 	// Convert to DTOs
 	dtoTransactions := make([]dto.TransactionHistoryItem, len(transactions))
 	for i, txn := range transactions {
-		dtoTransactions[i] = dto.TransactionHistoryItem{
-			ID:           txn.ID,
-			Reference:    txn.Reference,
-			Type:         txn.Type,
-			Status:       txn.Status,
-			Amount:       txn.Amount,
-			AmountNGN:    float64(txn.Amount) / 100,
-			Description:  txn.Description,
-			Direction:    txn.Direction,
-			Counterparty: txn.Counterparty,
-			CreatedAt:    txn.CreatedAt.Format(time.RFC3339),
-		}
+		dtoTransactions[i] = toHistoryItemDTO(txn)
 	}
 
 	log.Printf("[GET_HISTORY] Success - UserID: %d, Found: %d/%d transactions", userID, len(transactions), total)
@@ -398,10 +560,76 @@ func (s *WalletService) GetTransactionHistory(ctx context.Context, userID, page,
 	}, nil
 }
 
+// GetTransaction returns one of the user's transactions by reference
+func (s *WalletService) GetTransaction(ctx context.Context, userID int, reference string) (*dto.TransactionHistoryItem, error) {
+	txn, err := s.repo.GetTransactionHistoryItem(ctx, userID, reference)
+	if err != nil {
+		if isAccountNotFoundError(err) {
+			return nil, ErrAccountNotFound
+		}
+		if errors.Is(err, repository.ErrNoRows) {
+			return nil, ErrTransactionNotFound
+		}
+		return nil, err
+	}
+
+	item := toHistoryItemDTO(*txn)
+	return &item, nil
+}
+
+// ListTransactionsAfter returns up to limit of the user's posted transactions
+// with IDs above afterID, oldest first
+func (s *WalletService) ListTransactionsAfter(ctx context.Context, userID int, afterID int64, limit int) ([]dto.TransactionHistoryItem, error) {
+	if limit < 1 || limit > 100 {
+		limit = 100
+	}
+
+	transactions, err := s.repo.GetTransactionHistoryAfter(ctx, userID, afterID, limit)
+	if err != nil {
+		if isAccountNotFoundError(err) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+
+	items := make([]dto.TransactionHistoryItem, len(transactions))
+	for i, txn := range transactions {
+		items[i] = toHistoryItemDTO(txn)
+	}
+	return items, nil
+}
+
+func toHistoryItemDTO(txn models.TransactionHistoryItem) dto.TransactionHistoryItem {
+	return dto.TransactionHistoryItem{
+		ID:           txn.ID,
+		Reference:    txn.Reference,
+		Type:         txn.Type,
+		Status:       txn.Status,
+		Amount:       txn.Amount,
+		AmountNGN:    float64(txn.Amount) / 100,
+		Description:  txn.Description,
+		Direction:    txn.Direction,
+		Counterparty: txn.Counterparty,
+		CreatedAt:    txn.CreatedAt.Format(time.RFC3339),
+	}
+}
+
 // ==============================================
 // VALIDATION & HELPERS
 // ==============================================
 
+// validateClientIdempotencyKey keeps client keys out of the internal namespace,
+// so a client can never claim the key of a transfer the service will make later
+func validateClientIdempotencyKey(key string) error {
+	if key == "" {
+		return ErrInvalidIdempotencyKey
+	}
+	if strings.HasPrefix(key, InternalIdempotencyPrefix) {
+		return ErrReservedIdempotencyKey
+	}
+	return nil
+}
+
 func (s *WalletService) validateDepositAmount(amount int64) error {
 	if amount <= 0 {
 		return ErrInvalidAmount
@@ -439,4 +667,62 @@ func (s *WalletService) validateTransferAmount(amount int64) error {
 		return fmt.Errorf("%w: maximum per transaction is ₦%.2f", ErrAmountTooLarge, float64(MaxTransactionAmount)/100)
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+func (s *WalletService) buildIdempotentResponse(ctx context.Context, txn *models.Transaction, userID int, reference string) (*dto.TransactionResponse, error) {
+	account, err := s.repo.GetAccountByUserID(ctx, userID)
+	if err != nil {
+		if isAccountNotFoundError(err) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+
+	// The key belongs to another user's or another kind of transaction - never report it as ours
+	if !ownsTransaction(txn, account.ID) {
+		return nil, ErrIdempotencyConflict
+	}
+
+	return &dto.TransactionResponse{
+		TransactionID: txn.ID,
+		Status:        "posted",
+		Balance:       account.Balance,
+		Reference:     reference,
+		Message:       "Transaction already processed",
+	}, nil
+}
+
+// ownsTransaction reports whether txn is a deposit into, or a withdrawal from, accountID
+func ownsTransaction(txn *models.Transaction, accountID int64) bool {
+	switch txn.Kind {
+	case models.TransactionKindDeposit:
+		return txn.ToAccountID.Valid && txn.ToAccountID.Int64 == accountID
+	case models.TransactionKindWithdraw:
+		return txn.FromAccountID.Valid && txn.FromAccountID.Int64 == accountID
+	}
+	return false
+}
+
+// createTransactionError maps a key that a concurrent request claimed first to a conflict
+func createTransactionError(err error) error {
+	if errors.Is(err, repository.ErrDuplicateIdempotencyKey) {
+		return ErrIdempotencyConflict
+	}
+	return err
+}
+
+// observeTransactionFailure records a transaction that was rejected after passing validation
+func observeTransactionFailure(kind string, amount int64, started time.Time, err error) {
+	metrics.ObserveTransaction(kind, metrics.StatusFailed, amount, started)
+	if errors.Is(err, ErrInsufficientBalance) {
+		metrics.InsufficientBalance.WithLabelValues(kind).Inc()
+	}
+}
+
+func isNoRowsError(err error) bool {
+	return errors.Is(err, repository.ErrNoRows) || errors.Is(err, pgx.ErrNoRows)
+}
+
+func isAccountNotFoundError(err error) bool {
+	return errors.Is(err, repository.ErrAccountNotFound)
+}
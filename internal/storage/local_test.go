@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage_PutOpenDelete(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	n, err := s.Put(ctx, "kyc/1/2/id_card-abc", strings.NewReader("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+
+	rc, err := s.Open(ctx, "kyc/1/2/id_card-abc")
+	require.NoError(t, err)
+	body, _ := io.ReadAll(rc)
+	rc.Close()
+	assert.Equal(t, "hello", string(body))
+
+	require.NoError(t, s.Delete(ctx, "kyc/1/2/id_card-abc"))
+	_, err = s.Open(ctx, "kyc/1/2/id_card-abc")
+	assert.ErrorIs(t, err, ErrObjectNotFound)
+}
+
+func TestLocalStorage_RejectsEscapingKeys(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "/etc/passwd", "../outside", "kyc/../../outside", "kyc//x", `kyc\x`} {
+		_, err := s.Put(context.Background(), key, strings.NewReader("x"))
+		assert.ErrorIs(t, err, ErrInvalidKey, key)
+	}
+}
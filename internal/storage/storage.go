@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ==============================================
+// ERRORS
+// ==============================================
+
+var (
+	ErrObjectNotFound = errors.New("object not found")
+	ErrInvalidKey     = errors.New("invalid object key")
+)
+
+// ==============================================
+// STORAGE INTERFACE
+// ==============================================
+
+// Storage is a minimal object store for uploaded files (KYC documents)
+// Keys are slash-separated relative paths, e.g. "kyc/42/7/id_card-abc123"
+type Storage interface {
+	// Put writes the reader's contents under key and returns the number of bytes written
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Open returns the object's contents; the caller must close it
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,128 @@
+package tracing
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ==============================================
+// PGX TRACING
+// ==============================================
+// DBTracer opens a span for every Query, QueryRow and Exec, including the
+// BEGIN and COMMIT a pgx.Tx sends. Two spans matter most when a transaction is
+// slow:
+//   - row-locking statements (SELECT ... FOR UPDATE and friends) are named
+//     "<OP> FOR UPDATE" and carry db.lock_wait=true; their duration is mostly
+//     time spent waiting for the row lock
+//   - COMMIT, which is where the deferred double-entry balance trigger runs
+// Waiting for a pool connection gets its own "pool.acquire" span.
+
+// maxQueryText caps the statement text attached to a span
+const maxQueryText = 2048
+
+// AttrLockWait flags spans whose time is dominated by waiting for row or table locks
+var AttrLockWait = attribute.Key("db.lock_wait")
+
+var lockClause = regexp.MustCompile(`(?i)\bFOR\s+(NO\s+KEY\s+UPDATE|UPDATE|KEY\s+SHARE|SHARE)\b`)
+
+// DBTracer implements pgx.QueryTracer and pgxpool.AcquireTracer
+type DBTracer struct{}
+
+var (
+	_ pgx.QueryTracer       = DBTracer{}
+	_ pgxpool.AcquireTracer = DBTracer{}
+)
+
+// TraceQueryStart opens the statement's span
+func (DBTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	name, locking := describeQuery(data.SQL)
+
+	attrs := []attribute.KeyValue{
+		semconv.DBSystemNamePostgreSQL,
+		semconv.DBOperationName(strings.Fields(name)[0]),
+		semconv.DBQueryText(queryText(data.SQL)),
+	}
+	if locking {
+		attrs = append(attrs, AttrLockWait.Bool(true))
+	}
+
+	ctx, _ = Start(ctx, name, attrs...)
+	return ctx
+}
+
+// TraceQueryEnd closes the statement's span
+func (DBTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	if data.Err == nil {
+		span.SetAttributes(attribute.Int64("db.response.rows_affected", data.CommandTag.RowsAffected()))
+	}
+	Fail(span, data.Err)
+	span.End()
+}
+
+// TraceAcquireStart opens a span covering the wait for a pool connection
+func (DBTracer) TraceAcquireStart(ctx context.Context, _ *pgxpool.Pool, _ pgxpool.TraceAcquireStartData) context.Context {
+	ctx, _ = Start(ctx, "pool.acquire", semconv.DBSystemNamePostgreSQL)
+	return ctx
+}
+
+// TraceAcquireEnd closes the pool acquire span
+func (DBTracer) TraceAcquireEnd(ctx context.Context, _ *pgxpool.Pool, data pgxpool.TraceAcquireEndData) {
+	span := trace.SpanFromContext(ctx)
+	Fail(span, data.Err)
+	span.End()
+}
+
+// describeQuery names a statement's span after its leading keyword and reports whether it takes row locks
+// Statements come from the repositories, never from user input, so the names stay few
+func describeQuery(sql string) (string, bool) {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "QUERY", false
+	}
+	op := strings.ToUpper(fields[0])
+	if op == "WITH" {
+		// A CTE: name it after the statement it wraps
+		op = "WITH " + mainStatement(fields[1:])
+	}
+
+	if m := lockClause.FindString(sql); m != "" {
+		return op + " " + strings.ToUpper(strings.Join(strings.Fields(m), " ")), true
+	}
+	if op == "LOCK" {
+		return op, true
+	}
+	return op, false
+}
+
+// mainStatement finds the first top-level SELECT/INSERT/UPDATE/DELETE after a WITH list
+func mainStatement(fields []string) string {
+	depth := 0
+	for _, f := range fields {
+		depth += strings.Count(f, "(") - strings.Count(f, ")")
+		if depth != 0 {
+			continue
+		}
+		switch kw := strings.ToUpper(strings.TrimRight(f, ";")); kw {
+		case "SELECT", "INSERT", "UPDATE", "DELETE":
+			return kw
+		}
+	}
+	return "QUERY"
+}
+
+// queryText collapses a statement's whitespace and caps its length
+func queryText(sql string) string {
+	text := strings.Join(strings.Fields(sql), " ")
+	if len(text) > maxQueryText {
+		text = text[:maxQueryText] + "..."
+	}
+	return text
+}
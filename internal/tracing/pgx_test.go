@@ -0,0 +1,101 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestDescribeQuery(t *testing.T) {
+	tests := []struct {
+		sql     string
+		name    string
+		locking bool
+	}{
+		{"SELECT id, balance FROM accounts WHERE user_id = $1", "SELECT", false},
+		{"\n\t\tSELECT id, balance\n\t\tFROM accounts\n\t\tWHERE user_id = $1\n\t\tFOR UPDATE\n\t", "SELECT FOR UPDATE", true},
+		{"select * from accounts where id = $1 for no key update", "SELECT FOR NO KEY UPDATE", true},
+		{"SELECT last_hash FROM hash_chain_heads WHERE chain = $1 FOR  UPDATE", "SELECT FOR UPDATE", true},
+		{"SELECT * FROM users WHERE id = $1 FOR SHARE", "SELECT FOR SHARE", true},
+		{"UPDATE accounts SET balance = balance + $1 WHERE id = $2", "UPDATE", false},
+		{"INSERT INTO t (a) VALUES ($1) ON CONFLICT (a) DO UPDATE SET a = EXCLUDED.a", "INSERT", false},
+		{"LOCK TABLE accounts IN SHARE MODE", "LOCK", true},
+		{"begin", "BEGIN", false},
+		{"commit", "COMMIT", false},
+		{"WITH moved AS (UPDATE accounts SET balance = 0 RETURNING id) SELECT count(*) FROM moved", "WITH SELECT", false},
+		{"WITH x AS (SELECT 1) INSERT INTO t SELECT * FROM x", "WITH INSERT", false},
+		{"   ", "QUERY", false},
+	}
+
+	for _, tt := range tests {
+		name, locking := describeQuery(tt.sql)
+		assert.Equal(t, tt.name, name, tt.sql)
+		assert.Equal(t, tt.locking, locking, tt.sql)
+	}
+}
+
+func TestQueryText(t *testing.T) {
+	assert.Equal(t, "SELECT id FROM accounts WHERE id = $1", queryText("\n\tSELECT id\n\tFROM accounts\n\tWHERE id = $1\n"))
+
+	long := queryText("SELECT " + strings.Repeat("x", 3*maxQueryText))
+	assert.Len(t, long, maxQueryText+len("..."))
+	assert.True(t, strings.HasSuffix(long, "..."))
+}
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestDBTracer_Spans(t *testing.T) {
+	recorder := recordSpans(t)
+	tracer := DBTracer{}
+
+	ctx, parent := Start(context.Background(), "WalletService.Deposit")
+
+	qctx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{
+		SQL: "SELECT id FROM accounts WHERE user_id = $1 FOR UPDATE",
+	})
+	tracer.TraceQueryEnd(qctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1")})
+
+	cctx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "commit"})
+	tracer.TraceQueryEnd(cctx, nil, pgx.TraceQueryEndData{Err: errors.New("balance check failed")})
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	lock, commit := spans[0], spans[1]
+	assert.Equal(t, "SELECT FOR UPDATE", lock.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), lock.Parent().SpanID())
+	attrs := map[string]interface{}{}
+	for _, kv := range lock.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	assert.Equal(t, true, attrs["db.lock_wait"])
+	assert.Equal(t, "postgresql", attrs["db.system.name"])
+	assert.Equal(t, "SELECT", attrs["db.operation.name"])
+	assert.Equal(t, int64(1), attrs["db.response.rows_affected"])
+
+	assert.Equal(t, "COMMIT", commit.Name())
+	assert.Equal(t, codes.Error, commit.Status().Code)
+	for _, kv := range commit.Attributes() {
+		assert.NotEqual(t, AttrLockWait, kv.Key)
+	}
+}
@@ -1 +1,69 @@
-package worker
\ No newline at end of file
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Brownie44l1/debank/internal/tracing"
+)
+
+// Job is a piece of background work run on a fixed interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Run executes the job straight away and then every Interval until ctx is cancelled
+// Failures are logged and retried on the next tick; runs never overlap
+func Run(ctx context.Context, job Job) {
+	log.Printf("[WORKER] %s scheduled every %v", job.Name, job.Interval)
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		RunOnce(ctx, job)
+
+		select {
+		case <-ctx.Done():
+			log.Printf("[WORKER] %s stopped", job.Name)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce executes the job a single time and logs the outcome
+func RunOnce(ctx context.Context, job Job) error {
+	ctx, span := tracing.Start(ctx, "job "+job.Name)
+	defer span.End()
+
+	startTime := time.Now()
+	if err := job.Run(ctx); err != nil {
+		tracing.Fail(span, err)
+		log.Printf("[WORKER] %s failed after %v: %v", job.Name, time.Since(startTime), err)
+		return err
+	}
+	log.Printf("[WORKER] %s finished in %v", job.Name, time.Since(startTime))
+	return nil
+}
+
+// HeartbeatRecorder stores the outcome of each job run for the API's readiness check
+type HeartbeatRecorder interface {
+	RecordWorkerRun(ctx context.Context, job string, interval time.Duration, runErr error) error
+}
+
+// WithHeartbeat records every run of job; a failure to record is logged and doesn't fail the run
+func WithHeartbeat(job Job, rec HeartbeatRecorder) Job {
+	run := job.Run
+	job.Run = func(ctx context.Context) error {
+		err := run(ctx)
+		if recErr := rec.RecordWorkerRun(ctx, job.Name, job.Interval, err); recErr != nil {
+			log.Printf("[WORKER] %s heartbeat not recorded: %v", job.Name, recErr)
+		}
+		return err
+	}
+	return job
+}
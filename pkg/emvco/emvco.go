@@ -0,0 +1,336 @@
+package emvco
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ==============================================
+// EMVCo MERCHANT-PRESENTED QR PAYLOADS
+// ==============================================
+// Payloads are a flat list of ID-LENGTH-VALUE objects (2-digit ID, 2-digit
+// length, value) terminated by a CRC-16/CCITT-FALSE checksum in ID 63.
+// See EMV QR Code Specification for Payment Systems - Merchant-Presented Mode.
+
+// Top-level data object IDs
+const (
+	IDPayloadFormat        = "00"
+	IDPointOfInitiation    = "01"
+	IDMerchantAccount      = "26" // First of the 26-51 template range
+	IDMerchantCategoryCode = "52"
+	IDTransactionCurrency  = "53"
+	IDTransactionAmount    = "54"
+	IDCountryCode          = "58"
+	IDMerchantName         = "59"
+	IDMerchantCity         = "60"
+	IDAdditionalData       = "62"
+	IDCRC                  = "63"
+)
+
+// Sub-object IDs
+const (
+	subIDGloballyUniqueID = "00"
+	subIDAccountNumber    = "01"
+	subIDBankCode         = "02"
+	subIDReferenceLabel   = "05"
+)
+
+const (
+	PayloadFormatIndicator = "01"
+	InitiationStatic       = "11" // Reusable, amount entered by the payer
+	InitiationDynamic      = "12" // Single payment, amount fixed by the payee
+
+	CurrencyNGN    = "566" // ISO 4217 numeric
+	CountryNigeria = "NG"
+	DefaultMCC     = "0000"
+
+	maxValueLength     = 99 // Longest value a 2-digit length can describe
+	maxNameLength      = 25 // Name, city and reference limits are in bytes
+	maxCityLength      = 15
+	maxReferenceLength = 25
+)
+
+var (
+	ErrPayloadTooShort     = errors.New("qr payload too short")
+	ErrMalformedPayload    = errors.New("malformed qr payload")
+	ErrInvalidCRC          = errors.New("qr payload checksum mismatch")
+	ErrUnsupportedPayload  = errors.New("unsupported qr payload format")
+	ErrMissingAccount      = errors.New("qr payload has no merchant account information")
+	ErrInvalidPayloadValue = errors.New("invalid qr payload value")
+)
+
+// Payload is the decoded form of a merchant-presented QR code
+type Payload struct {
+	Dynamic       bool
+	GUID          string // Identifies the scheme that owns the merchant account template
+	AccountNumber string
+	BankCode      string
+	MerchantName  string
+	MerchantCity  string
+	Currency      string
+	CountryCode   string
+	MCC           string
+	Amount        int64 // In kobo, 0 when the payer enters the amount
+	Reference     string
+}
+
+// ==============================================
+// ENCODE
+// ==============================================
+
+// Encode serialises a payload and appends its CRC
+func Encode(p Payload) (string, error) {
+	if p.AccountNumber == "" {
+		return "", ErrMissingAccount
+	}
+	if p.Amount < 0 {
+		return "", fmt.Errorf("%w: negative amount", ErrInvalidPayloadValue)
+	}
+	if len(p.Reference) > maxReferenceLength {
+		return "", fmt.Errorf("%w: reference longer than %d characters", ErrInvalidPayloadValue, maxReferenceLength)
+	}
+
+	initiation := InitiationStatic
+	if p.Dynamic {
+		initiation = InitiationDynamic
+	}
+
+	var account tlvWriter
+	account.write(subIDGloballyUniqueID, p.GUID)
+	account.write(subIDAccountNumber, p.AccountNumber)
+	if p.BankCode != "" {
+		account.write(subIDBankCode, p.BankCode)
+	}
+	if account.err != nil {
+		return "", account.err
+	}
+
+	var w tlvWriter
+	w.write(IDPayloadFormat, PayloadFormatIndicator)
+	w.write(IDPointOfInitiation, initiation)
+	w.write(IDMerchantAccount, account.String())
+	w.write(IDMerchantCategoryCode, defaultString(p.MCC, DefaultMCC))
+	w.write(IDTransactionCurrency, defaultString(p.Currency, CurrencyNGN))
+	if p.Amount > 0 {
+		w.write(IDTransactionAmount, formatAmount(p.Amount))
+	}
+	w.write(IDCountryCode, defaultString(p.CountryCode, CountryNigeria))
+	w.write(IDMerchantName, truncate(p.MerchantName, maxNameLength))
+	w.write(IDMerchantCity, truncate(p.MerchantCity, maxCityLength))
+	if p.Reference != "" {
+		w.write(IDAdditionalData, tlv(subIDReferenceLabel, p.Reference))
+	}
+	if w.err != nil {
+		return "", w.err
+	}
+
+	// The CRC covers everything up to and including its own ID and length
+	body := w.String() + IDCRC + "04"
+	return body + CRC16(body), nil
+}
+
+// ==============================================
+// DECODE
+// ==============================================
+
+// Decode validates the CRC and structure of a payload and parses it
+func Decode(raw string) (*Payload, error) {
+	raw = strings.TrimSpace(raw)
+	if len(raw) < 8 {
+		return nil, ErrPayloadTooShort
+	}
+
+	body, crc := raw[:len(raw)-4], raw[len(raw)-4:]
+	if !strings.HasSuffix(body, IDCRC+"04") {
+		return nil, ErrMalformedPayload
+	}
+	if !strings.EqualFold(CRC16(body), crc) {
+		return nil, ErrInvalidCRC
+	}
+
+	objects, err := parseTLV(raw[:len(raw)-8])
+	if err != nil {
+		return nil, err
+	}
+
+	if objects[IDPayloadFormat] != PayloadFormatIndicator {
+		return nil, ErrUnsupportedPayload
+	}
+
+	p := &Payload{
+		MerchantName: objects[IDMerchantName],
+		MerchantCity: objects[IDMerchantCity],
+		Currency:     objects[IDTransactionCurrency],
+		CountryCode:  objects[IDCountryCode],
+		MCC:          objects[IDMerchantCategoryCode],
+	}
+
+	switch objects[IDPointOfInitiation] {
+	case InitiationDynamic:
+		p.Dynamic = true
+	case InitiationStatic, "":
+	default:
+		return nil, fmt.Errorf("%w: point of initiation %q", ErrInvalidPayloadValue, objects[IDPointOfInitiation])
+	}
+
+	// Merchant account templates may appear anywhere in 26-51; take the first
+	// one that carries an account number
+	for _, id := range sortedKeys(objects) {
+		n, _ := strconv.Atoi(id)
+		if n < 26 || n > 51 {
+			continue
+		}
+		account, err := parseTLV(objects[id])
+		if err != nil {
+			return nil, err
+		}
+		if account[subIDAccountNumber] != "" {
+			p.GUID = account[subIDGloballyUniqueID]
+			p.AccountNumber = account[subIDAccountNumber]
+			p.BankCode = account[subIDBankCode]
+			break
+		}
+	}
+	if p.AccountNumber == "" {
+		return nil, ErrMissingAccount
+	}
+
+	if amount, ok := objects[IDTransactionAmount]; ok {
+		p.Amount, err = parseAmount(amount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if additional, ok := objects[IDAdditionalData]; ok {
+		extra, err := parseTLV(additional)
+		if err != nil {
+			return nil, err
+		}
+		p.Reference = extra[subIDReferenceLabel]
+	}
+
+	return p, nil
+}
+
+// ==============================================
+// HELPERS
+// ==============================================
+
+// CRC16 computes CRC-16/CCITT-FALSE (poly 0x1021, init 0xFFFF) as 4 uppercase hex digits
+func CRC16(data string) string {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return fmt.Sprintf("%04X", crc)
+}
+
+func tlv(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+// tlvWriter builds a list of objects, keeping the first error; a value
+// longer than a 2-digit length can describe would corrupt every object after it
+type tlvWriter struct {
+	b   strings.Builder
+	err error
+}
+
+func (w *tlvWriter) write(id, value string) {
+	if w.err != nil {
+		return
+	}
+	if len(value) > maxValueLength {
+		w.err = fmt.Errorf("%w: object %s is %d bytes, longer than %d", ErrInvalidPayloadValue, id, len(value), maxValueLength)
+		return
+	}
+	w.b.WriteString(tlv(id, value))
+}
+
+func (w *tlvWriter) String() string {
+	return w.b.String()
+}
+
+func parseTLV(data string) (map[string]string, error) {
+	objects := make(map[string]string)
+	for i := 0; i < len(data); {
+		if i+4 > len(data) {
+			return nil, ErrMalformedPayload
+		}
+		id := data[i : i+2]
+		length, err := strconv.Atoi(data[i+2 : i+4])
+		if err != nil || length < 0 || i+4+length > len(data) {
+			return nil, ErrMalformedPayload
+		}
+		objects[id] = data[i+4 : i+4+length]
+		i += 4 + length
+	}
+	return objects, nil
+}
+
+// formatAmount renders kobo as a decimal naira string ("2500.00")
+func formatAmount(kobo int64) string {
+	return fmt.Sprintf("%d.%02d", kobo/100, kobo%100)
+}
+
+// parseAmount converts a decimal naira string back to kobo without going through float64
+func parseAmount(s string) (int64, error) {
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if whole == "" || len(frac) > 2 || (hasFrac && frac == "") {
+		return 0, fmt.Errorf("%w: amount %q", ErrInvalidPayloadValue, s)
+	}
+	for len(frac) < 2 {
+		frac += "0"
+	}
+
+	naira, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || naira < 0 {
+		return 0, fmt.Errorf("%w: amount %q", ErrInvalidPayloadValue, s)
+	}
+	kobo, err := strconv.ParseInt(frac, 10, 64)
+	if err != nil || kobo < 0 {
+		return 0, fmt.Errorf("%w: amount %q", ErrInvalidPayloadValue, s)
+	}
+
+	return naira*100 + kobo, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// truncate cuts s to at most max bytes without splitting a character
+// Object lengths count bytes, so a name in a multi-byte script is cut sooner
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
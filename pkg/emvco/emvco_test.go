@@ -0,0 +1,114 @@
+package emvco
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCRC16_CheckValue(t *testing.T) {
+	// Standard CRC-16/CCITT-FALSE check value
+	assert.Equal(t, "29B1", CRC16("123456789"))
+}
+
+func TestEncodeDecode_RoundTrip(t *testing.T) {
+	in := Payload{
+		Dynamic:       true,
+		GUID:          "NG.DEBANK",
+		AccountNumber: "8012345678",
+		MerchantName:  "Ada Obi",
+		MerchantCity:  "LAGOS",
+		Amount:        250050,
+		Reference:     "INV-1",
+	}
+
+	raw, err := Encode(in)
+	require.NoError(t, err)
+	assert.Contains(t, raw, "54072500.50")
+
+	out, err := Decode(raw)
+	require.NoError(t, err)
+	assert.True(t, out.Dynamic)
+	assert.Equal(t, in.AccountNumber, out.AccountNumber)
+	assert.Equal(t, in.Amount, out.Amount)
+	assert.Equal(t, in.Reference, out.Reference)
+	assert.Equal(t, CurrencyNGN, out.Currency)
+}
+
+func TestEncode_StaticHasNoAmount(t *testing.T) {
+	raw, err := Encode(Payload{GUID: "NG.DEBANK", AccountNumber: "8012345678"})
+	require.NoError(t, err)
+
+	out, err := Decode(raw)
+	require.NoError(t, err)
+	assert.False(t, out.Dynamic)
+	assert.Zero(t, out.Amount)
+}
+
+func TestDecode_RejectsTamperedPayload(t *testing.T) {
+	raw, err := Encode(Payload{GUID: "NG.DEBANK", AccountNumber: "8012345678", Amount: 10000})
+	require.NoError(t, err)
+
+	tampered := []byte(raw)
+	tampered[len(tampered)-10] = '9'
+
+	_, err = Decode(string(tampered))
+	assert.ErrorIs(t, err, ErrInvalidCRC)
+}
+
+func TestDecode_RejectsMalformed(t *testing.T) {
+	_, err := Decode("0002")
+	assert.ErrorIs(t, err, ErrPayloadTooShort)
+
+	_, err = Decode("abc" + "6304" + CRC16("abc6304"))
+	assert.ErrorIs(t, err, ErrMalformedPayload)
+}
+
+func TestEncode_RejectsValuesLongerThan99Bytes(t *testing.T) {
+	_, err := Encode(Payload{GUID: "NG.DEBANK", AccountNumber: strings.Repeat("1", 100)})
+	assert.ErrorIs(t, err, ErrInvalidPayloadValue)
+
+	// Each sub-object fits, but the account template holding them doesn't
+	_, err = Encode(Payload{GUID: strings.Repeat("G", 50), AccountNumber: strings.Repeat("1", 50)})
+	assert.ErrorIs(t, err, ErrInvalidPayloadValue)
+
+	_, err = Encode(Payload{GUID: "NG.DEBANK", AccountNumber: "8012345678", MCC: strings.Repeat("0", 100)})
+	assert.ErrorIs(t, err, ErrInvalidPayloadValue)
+
+	// 99 bytes is the most a length field can hold
+	raw, err := Encode(Payload{GUID: strings.Repeat("G", 81), AccountNumber: "8012345678"})
+	require.NoError(t, err)
+	out, err := Decode(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "8012345678", out.AccountNumber)
+}
+
+func TestEncode_TruncatesNameToMaxBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "short", in: "Ada Obi", want: "Ada Obi"},
+		{name: "ascii", in: "Adaeze Chukwuemeka-Obiora Nwosu", want: "Adaeze Chukwuemeka-Obiora"},
+		{name: "two-byte", in: strings.Repeat("É", 30), want: strings.Repeat("É", 12)},
+		{name: "three-byte", in: strings.Repeat("語", 30), want: strings.Repeat("語", 8)},
+		{name: "four-byte", in: strings.Repeat("🦊", 30), want: strings.Repeat("🦊", 6)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := Encode(Payload{GUID: "NG.DEBANK", AccountNumber: "8012345678", MerchantName: tt.in})
+			require.NoError(t, err)
+
+			out, err := Decode(raw)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.MerchantName)
+			assert.LessOrEqual(t, len(out.MerchantName), maxNameLength)
+			assert.True(t, utf8.ValidString(out.MerchantName))
+		})
+	}
+}
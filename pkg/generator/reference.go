@@ -1 +1,21 @@
-package generator
\ No newline at end of file
+package generator
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GenerateReference creates a unique, human-readable transaction reference
+// Format: PREFIX-YYYYMMDDHHMMSS-XXXXXXXX (e.g. TRF-20250101120000-9F2C1A7B)
+func GenerateReference(prefix string) string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		// Fallback to nanoseconds (less random but still unique enough)
+		return fmt.Sprintf("%s-%s-%08X", prefix, time.Now().UTC().Format("20060102150405"), time.Now().UnixNano()&0xFFFFFFFF)
+	}
+
+	return fmt.Sprintf("%s-%s-%s", prefix, time.Now().UTC().Format("20060102150405"), strings.ToUpper(hex.EncodeToString(b)))
+}
@@ -0,0 +1,123 @@
+package hashchain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type row struct {
+	id     int64
+	prev   []byte
+	hash   []byte
+	fields []Field
+}
+
+func buildChain(n int) []row {
+	rows := make([]row, n)
+	prev := Genesis
+	for i := range rows {
+		id := int64(i + 1)
+		fields := []Field{Int(id), String("deposit"), Int(id * 100), Time(time.Unix(1700000000+id, 0))}
+		hash := Link(prev, fields...)
+		rows[i] = row{id: id, prev: prev, hash: hash, fields: fields}
+		prev = hash
+	}
+	return rows
+}
+
+func verify(rows []row) (*Verifier, []*BreakError) {
+	v := NewVerifier(Genesis)
+	var breaks []*BreakError
+	for _, r := range rows {
+		if err := v.Check(r.id, r.prev, r.hash, r.fields...); err != nil {
+			var b *BreakError
+			if errors.As(err, &b) {
+				breaks = append(breaks, b)
+			}
+		}
+	}
+	return v, breaks
+}
+
+func TestLinkIsDeterministic(t *testing.T) {
+	at := time.Date(2026, 10, 1, 12, 0, 0, 123000, time.FixedZone("WAT", 3600))
+	a := Link(Genesis, Int(1), String("x"), Time(at))
+	b := Link(Genesis, Int(1), String("x"), Time(at.UTC()))
+
+	assert.Len(t, a, Size)
+	assert.Equal(t, a, b, "time zone must not change the hash")
+	assert.NotEqual(t, a, Link(a, Int(1), String("x"), Time(at)), "previous hash is part of the link")
+}
+
+func TestLinkEncodingIsUnambiguous(t *testing.T) {
+	assert.NotEqual(t, Link(Genesis, String("ab"), String("c")), Link(Genesis, String("a"), String("bc")))
+	assert.NotEqual(t, Link(Genesis, String("")), Link(Genesis, Null()))
+	assert.NotEqual(t, Link(Genesis, OptString("", false)), Link(Genesis, OptString("", true)))
+	assert.Equal(t, Link(Genesis, OptInt(7, true)), Link(Genesis, Int(7)))
+}
+
+func TestVerifierAcceptsIntactChain(t *testing.T) {
+	rows := buildChain(5)
+	v, breaks := verify(rows)
+
+	assert.Empty(t, breaks)
+	assert.Equal(t, rows[4].hash, v.Head())
+	assert.Equal(t, int64(5), v.LastID())
+	assert.Equal(t, int64(5), v.Rows())
+}
+
+func TestVerifierDetectsEditedRow(t *testing.T) {
+	rows := buildChain(5)
+	rows[2].fields[2] = Int(999999)
+
+	_, breaks := verify(rows)
+	require.Len(t, breaks, 1, "later rows still link to the stored hash")
+	assert.Equal(t, int64(3), breaks[0].ID)
+	assert.ErrorIs(t, breaks[0], ErrHashMismatch)
+}
+
+func TestVerifierDetectsDeletedRow(t *testing.T) {
+	rows := buildChain(5)
+	rows = append(rows[:2], rows[3:]...)
+
+	_, breaks := verify(rows)
+	require.Len(t, breaks, 1)
+	assert.Equal(t, int64(4), breaks[0].ID)
+	assert.ErrorIs(t, breaks[0], ErrPrevMismatch)
+}
+
+func TestVerifierDetectsRehashedRow(t *testing.T) {
+	// Editing a row and recomputing its own hash still breaks the next link
+	rows := buildChain(5)
+	rows[1].fields[2] = Int(1)
+	rows[1].hash = Link(rows[1].prev, rows[1].fields...)
+
+	_, breaks := verify(rows)
+	require.Len(t, breaks, 1)
+	assert.Equal(t, int64(3), breaks[0].ID)
+	assert.ErrorIs(t, breaks[0], ErrPrevMismatch)
+}
+
+func TestVerifierReportsUnsealedRowOnce(t *testing.T) {
+	rows := buildChain(4)
+	rows[1].hash = nil
+
+	_, breaks := verify(rows)
+	require.Len(t, breaks, 1)
+	assert.Equal(t, int64(2), breaks[0].ID)
+	assert.ErrorIs(t, breaks[0], ErrUnsealed)
+}
+
+func TestVerifierResumesFromCheckpoint(t *testing.T) {
+	rows := buildChain(6)
+	v := NewVerifier(rows[2].hash)
+	for _, r := range rows[3:] {
+		require.NoError(t, v.Check(r.id, r.prev, r.hash, r.fields...))
+	}
+	assert.Equal(t, rows[5].hash, v.Head())
+	assert.Equal(t, int64(3), v.Rows())
+}
@@ -0,0 +1,201 @@
+package namematch
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ==============================================
+// FUZZY NAME MATCHING
+// ==============================================
+// Names are normalised (case, accents, punctuation, honorifics, common
+// non-Latin scripts) and compared with Jaro-Winkler, both as whole strings
+// and token by token so that word order and middle names matter less.
+
+// Titles and honorifics dropped before comparing
+var honorifics = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "miss": true, "dr": true, "prof": true,
+	"sir": true, "chief": true, "alhaji": true, "alhaja": true, "hon": true,
+	"engr": true, "rev": true, "sheikh": true, "jr": true, "sr": true,
+}
+
+// Letters that don't decompose to ASCII under NFD
+var latinFold = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'ł': "l", 'đ': "d", 'ð': "d", 'þ': "th", 'ı': "i",
+}
+
+// Cyrillic to Latin, roughly following common passport transliteration
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "iu",
+	'я': "ia", 'і': "i", 'ї': "i", 'є': "ie", 'ґ': "g",
+}
+
+// Greek to Latin
+var greek = map[rune]string{
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th",
+	'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p",
+	'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+}
+
+// Normalize lowercases a name, transliterates it to ASCII, strips punctuation
+// and honorifics, and collapses whitespace
+func Normalize(name string) string {
+	return strings.Join(Tokens(name), " ")
+}
+
+// Tokens returns the normalised words of a name in their original order
+func Tokens(name string) []string {
+	// Decompose and drop combining marks: "José" -> "Jose"
+	stripped, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), name)
+	if err != nil {
+		stripped = name
+	}
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(stripped) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+		case latinFold[r] != "":
+			b.WriteString(latinFold[r])
+		case unicode.Is(unicode.Cyrillic, r):
+			b.WriteString(cyrillic[r])
+		case unicode.Is(unicode.Greek, r):
+			b.WriteString(greek[r])
+		case r == '\'' || r == '’':
+			// O'Brien -> obrien
+		default:
+			b.WriteRune(' ')
+		}
+	}
+
+	var tokens []string
+	for _, t := range strings.Fields(b.String()) {
+		if !honorifics[t] {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens
+}
+
+// Similarity scores two names between 0 (unrelated) and 1 (same after normalisation)
+func Similarity(a, b string) float64 {
+	return SimilarityTokens(Tokens(a), Tokens(b))
+}
+
+// SimilarityTokens is Similarity for names already split with Tokens
+// Lets callers normalise a large list once up front
+func SimilarityTokens(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	whole := JaroWinkler(strings.Join(a, " "), strings.Join(b, " "))
+	sorted := JaroWinkler(sortedJoin(a), sortedJoin(b))
+	return max(whole, sorted, tokenSimilarity(a, b))
+}
+
+// tokenSimilarity matches each word of the shorter name to its best partner in the
+// longer one, so "John Smith" still scores well against "John Michael Smith"
+// Words of a single letter (initials) are skipped unless that's all there is
+func tokenSimilarity(a, b []string) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+
+	var total float64
+	var counted int
+	for _, ta := range a {
+		if len(ta) < 2 && len(a) > 1 {
+			continue
+		}
+		best := 0.0
+		for _, tb := range b {
+			best = max(best, JaroWinkler(ta, tb))
+		}
+		total += best
+		counted++
+	}
+	if counted == 0 {
+		return 0
+	}
+
+	// A single shared word is weak evidence on its own
+	score := total / float64(counted)
+	if counted == 1 && len(b) > 1 {
+		score *= 0.85
+	}
+	return score
+}
+
+func sortedJoin(tokens []string) string {
+	sorted := append([]string(nil), tokens...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, " ")
+}
+
+// JaroWinkler returns the Jaro-Winkler similarity of two strings (0..1)
+func JaroWinkler(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	window := max(len(ra), len(rb))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	matchedA := make([]bool, len(ra))
+	matchedB := make([]bool, len(rb))
+	matches := 0
+	for i := range ra {
+		lo, hi := max(0, i-window), min(len(rb), i+window+1)
+		for j := lo; j < hi; j++ {
+			if !matchedB[j] && ra[i] == rb[j] {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	j := 0
+	for i := range ra {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(ra), len(rb)) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
@@ -0,0 +1,45 @@
+package namematch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]string{
+		"  José  MARÍA   Pérez ":   "jose maria perez",
+		"Alhaji Dr. Musa Yar'Adua": "musa yaradua",
+		"Владимир Путин":           "vladimir putin",
+		"O’Brien-Smith, Seán":      "obrien smith sean",
+		"Straße":                   "strasse",
+		"!!!":                      "",
+	}
+	for in, want := range cases {
+		assert.Equal(t, want, Normalize(in), in)
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	assert.Equal(t, 1.0, JaroWinkler("martha", "martha"))
+	assert.InDelta(t, 0.961, JaroWinkler("martha", "marhta"), 0.001)
+	assert.InDelta(t, 0.840, JaroWinkler("dwayne", "duane"), 0.001)
+	assert.Equal(t, 0.0, JaroWinkler("abc", ""))
+	assert.Equal(t, 0.0, JaroWinkler("abc", "xyz"))
+}
+
+func TestSimilarity(t *testing.T) {
+	// Same person, different formatting
+	assert.Equal(t, 1.0, Similarity("SMITH, John", "john smith"))
+	assert.Equal(t, 1.0, Similarity("Mr. Ahmed Al-Hassan", "ahmed al hassan"))
+
+	// Spelling variants and extra names still score high
+	assert.Greater(t, Similarity("Mohammed Abdullahi", "Muhammad Abdullahi"), 0.9)
+	assert.Greater(t, Similarity("John Smith", "John Michael Smith"), 0.9)
+	assert.Greater(t, Similarity("Vladimir Putin", "Владимир Путин"), 0.99)
+
+	// Different people
+	assert.Less(t, Similarity("John Smith", "Adaeze Okafor"), 0.7)
+	assert.Less(t, Similarity("Smith", "John Smith"), 0.9)
+	assert.Equal(t, 0.0, Similarity("", "John Smith"))
+}